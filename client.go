@@ -3,20 +3,37 @@
 package oql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/engine/parser"
 	"github.com/omniql-engine/omniql/engine/translator"
+	"github.com/omniql-engine/omniql/mapping"
+	esbuilders "github.com/omniql-engine/omniql/engine/builders/elasticsearch"
+	mongobuilders "github.com/omniql-engine/omniql/engine/builders/mongodb"
+	mysqlbuilders "github.com/omniql-engine/omniql/engine/builders/mysql"
+	pgbuilders "github.com/omniql-engine/omniql/engine/builders/postgres"
 	redisbuilders "github.com/omniql-engine/omniql/engine/builders/redis"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // ============================================
@@ -25,28 +42,69 @@ import (
 
 // Client wraps a database connection with OmniQL
 type Client struct {
-	sqlDB    *sql.DB
-	mongoDB  *mongo.Database
-	redisDB  *redis.Client
-	dbType   string
-	tenantID string
-	ctx      context.Context
+	sqlDB     *sql.DB
+	mongoDB   *mongo.Database
+	redisDB   redis.UniversalClient
+	esClient  *http.Client
+	esURL     string
+	dbType    string
+	tenantID  string
+	ctx       context.Context
+	pageCount bool
+	schema    SchemaProvider
+	copyFrom  CopyFromFunc
+	listen    ListenFunc
+	userHost  string
+	retries   int
+	warehouse string
+	options   translator.Options
+
+	sessionParams map[string]string
+	inTransaction bool
+	textIndexes   map[string][]string
+	ttlIndexes    map[string]bool
 }
 
+// CopyFromFunc bulk-loads rows with the PostgreSQL COPY protocol. The table
+// and columns are unquoted, and each row holds one value per column in column
+// order; it returns the number of rows copied.
+// With pgx: conn.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+type CopyFromFunc func(ctx context.Context, table string, columns []string, rows [][]any) (int64, error)
+
+// Notification is one NOTIFY message received by Subscribe
+type Notification struct {
+	Channel string
+	Payload string
+}
+
+// ListenFunc starts LISTEN on a dedicated connection and streams notifications
+// until ctx is done. database/sql cannot receive notifications, so the driver
+// does the listening. With pgx: conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+// then loop on conn.WaitForNotification(ctx). With lib/pq: pq.NewListener(...).Listen(channel).
+type ListenFunc func(ctx context.Context, channel string) (<-chan Notification, error)
+
 // ============================================
 // CONSTRUCTORS
 // ============================================
 
-// WrapSQL wraps a SQL database connection (PostgreSQL or MySQL)
+// WrapSQL wraps a SQL database connection (PostgreSQL, MySQL, SQLite, Oracle,
+// SQLServer, CockroachDB, ClickHouse, BigQuery, Snowflake, Cassandra or a dialect registered with RegisterDialect).
+// The Cassandra drivers most code uses, such as gocql, are not database/sql
+// drivers: open db with one that is, such as github.com/MichaelS11/go-cql-driver
+// (sql.Open("cql", "host:9042?keyspace=name")), which runs CQL over database/sql.
 func WrapSQL(db *sql.DB, dbType string) *Client {
-	if dbType != "PostgreSQL" && dbType != "MySQL" {
+	if dbType != "PostgreSQL" && dbType != "MySQL" && dbType != "SQLite" && dbType != "Oracle" && dbType != "SQLServer" && dbType != "CockroachDB" && dbType != "ClickHouse" && dbType != "BigQuery" && dbType != "Snowflake" && dbType != "Cassandra" && !translator.IsDialect(dbType) {
 		dbType = "PostgreSQL"
 	}
-	return &Client{
+	client := &Client{
 		sqlDB:  db,
 		dbType: dbType,
 		ctx:    context.Background(),
 	}
+	if dbType == "CockroachDB" {
+		client.retries = DefaultRetries
+	}
+	return client
 }
 
 // WrapMongo wraps a MongoDB database connection
@@ -58,8 +116,24 @@ func WrapMongo(db *mongo.Database) *Client {
 	}
 }
 
-// WrapRedis wraps a Redis client connection
-func WrapRedis(rdb *redis.Client, tenantID string) *Client {
+// WrapElasticsearch wraps an Elasticsearch cluster reached over HTTP at url
+// (http://localhost:9200). httpClient carries authentication and TLS in its
+// Transport; nil uses http.DefaultClient.
+func WrapElasticsearch(httpClient *http.Client, url string) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		esClient: httpClient,
+		esURL:    strings.TrimRight(url, "/"),
+		dbType:   "Elasticsearch",
+		ctx:      context.Background(),
+	}
+}
+
+// WrapRedis wraps a Redis connection: a *redis.Client, or a *redis.ClusterClient
+// with HashTags set in the Redis options (SetOptions) so an entity's keys share a slot
+func WrapRedis(rdb redis.UniversalClient, tenantID string) *Client {
 	return &Client{
 		redisDB:  rdb,
 		dbType:   "Redis",
@@ -82,30 +156,510 @@ func (c *Client) SetContext(ctx context.Context) {
 	c.ctx = ctx
 }
 
+// SetSchema enables parse-time validation of entities and columns (nil disables)
+func (c *Client) SetSchema(schema SchemaProvider) {
+	c.schema = schema
+}
+
+// SetPageCount makes QueryPage also run a COUNT to report total rows and pages
+func (c *Client) SetPageCount(enabled bool) {
+	c.pageCount = enabled
+}
+
+// SetCopyFrom sets how large PostgreSQL and CockroachDB BULK INSERTs are loaded with COPY
+// (see pgbuilders.Options). Without it the client uses COPY FROM STDIN
+// through database/sql, which drivers such as lib/pq support.
+func (c *Client) SetCopyFrom(fn CopyFromFunc) {
+	c.copyFrom = fn
+}
+
+// SetListener sets how Subscribe listens for PostgreSQL notifications
+func (c *Client) SetListener(fn ListenFunc) {
+	c.listen = fn
+}
+
+// SetUserHost sets the MySQL account host for DCL statements that name none
+// ('%' for any host). Without it accounts are bound to 'localhost'.
+func (c *Client) SetUserHost(host string) {
+	c.userHost = host
+}
+
+// SetOptions sets the per-database settings the client translates and runs
+// queries with, replacing earlier ones, e.g.
+// translator.Options{Redis: redisbuilders.Options{HashTags: true}}. The zero
+// value is the default of every database.
+func (c *Client) SetOptions(opts translator.Options) {
+	c.options = opts
+}
+
+// SetNativeSetOperations makes MySQL run INTERSECT and EXCEPT natively, which
+// needs MySQL 8.0.31+. Without it they are rewritten for older servers.
+func (c *Client) SetNativeSetOperations(enabled bool) {
+	c.options.MySQL.NativeSetOperations = enabled
+}
+
+// SetTextIndexes names the fields covered by each entity's MongoDB text index,
+// keyed by entity as written in OmniQL. LIKE '%words%' on those fields then runs
+// as a $text search, which uses the index, instead of an unanchored regex.
+func (c *Client) SetTextIndexes(indexes map[string][]string) {
+	c.textIndexes = indexes
+}
+
+// SetRetries sets how many more times a SQL statement is run when it fails
+// with a serialization error (SQLSTATE 40001), waiting longer before each
+// try. CockroachDB clients retry DefaultRetries times; others, and n = 0,
+// return the first error. Statements inside a BEGIN ... COMMIT are never
+// retried.
+func (c *Client) SetRetries(n int) {
+	c.retries = n
+}
+
+// SetWarehouse sets the Snowflake virtual warehouse that runs the client's
+// statements (USE WAREHOUSE). Without it they run on the user's default warehouse.
+func (c *Client) SetWarehouse(name string) {
+	c.warehouse = name
+}
+
+// SetSessionParameters sets Snowflake session parameters for the client's
+// statements, e.g. {"QUERY_TAG": "nightly-etl", "STATEMENT_TIMEOUT_IN_SECONDS": "600"}
+// (ALTER SESSION SET). nil clears them.
+func (c *Client) SetSessionParameters(params map[string]string) {
+	c.sessionParams = params
+}
+
 // ============================================
 // QUERY METHOD
 // ============================================
 
 // Query executes an OmniQL or native query and returns results
 func (c *Client) Query(input string) ([]map[string]any, error) {
+	rows, err := c.query(input)
+	return c.resultFields(rows), err
+}
+
+func (c *Client) query(input string) ([]map[string]any, error) {
 	switch c.dbType {
-	case "PostgreSQL", "MySQL":
+	case "PostgreSQL", "MySQL", "SQLite", "Oracle", "SQLServer", "CockroachDB", "ClickHouse", "BigQuery", "Snowflake", "Cassandra":
 		return c.querySQL(input)
 	case "MongoDB":
 		return c.queryMongo(input)
+	case "Elasticsearch":
+		return c.queryElasticsearch(input)
 	case "Redis":
 		return c.queryRedis(input)
 	default:
+		if translator.IsDialect(c.dbType) {
+			return c.querySQL(input)
+		}
+		return nil, fmt.Errorf("unsupported database type: %s", c.dbType)
+	}
+}
+
+// ============================================
+// SUBSCRIPTIONS (LISTEN/NOTIFY)
+// ============================================
+
+// Subscribe executes a LISTEN channel query and returns the notifications
+// sent to it (NOTIFY) until the client context is cancelled
+func (c *Client) Subscribe(input string) (<-chan Notification, error) {
+	query, isOQL, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if !isOQL {
+		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
+	}
+
+	if query.Operation != "LISTEN" {
+		return nil, fmt.Errorf("Subscribe requires a LISTEN query, got %s", query.Operation)
+	}
+	if c.dbType != "PostgreSQL" {
+		return nil, mapping.NotSupported(c.dbType, "LISTEN", mapping.SupportsOperation)
+	}
+	if c.listen == nil {
+		return nil, fmt.Errorf("no listener configured: call SetListener first")
+	}
+	if err := pgbuilders.ValidateIdentifier(query.Channel); err != nil {
+		return nil, err
+	}
+
+	return c.listen(c.ctx, query.Channel)
+}
+
+// ============================================
+// WATCH (change streams)
+// ============================================
+
+// ChangeEvent is one change to an entity delivered by Watch
+type ChangeEvent struct {
+	Operation string         // INSERT, UPDATE or DELETE
+	Entity    string
+	Document  map[string]any // The row after the change; for DELETE the removed row (MongoDB: its _id only)
+}
+
+// Watch streams the inserts, updates and deletes on entity until the client
+// context is cancelled. conditions is an optional WHERE filter ("status = 'paid'")
+// applied to the changed row. MongoDB opens a change stream (replica set required);
+// PostgreSQL installs a notifying trigger on the table and listens through SetListener.
+func (c *Client) Watch(entity string, conditions string) (<-chan ChangeEvent, error) {
+	input := ":GET " + entity
+	if strings.TrimSpace(conditions) != "" {
+		input += " WHERE " + conditions
+	}
+	query, _, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	switch c.dbType {
+	case "MongoDB":
+		result, err := translator.TranslateWithOptions(query, c.dbType, c.tenantID, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("translation error: %w", err)
+		}
+		return c.watchMongo(entity, result.GetDocument())
+	case "PostgreSQL":
+		if c.listen == nil {
+			return nil, fmt.Errorf("no listener configured: call SetListener first")
+		}
+		result, err := translator.TranslateWithOptions(query, c.dbType, c.tenantID, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("translation error: %w", err)
+		}
+		return c.watchPostgres(entity, result.GetRelational())
+	default:
+		return nil, mapping.NotSupported(c.dbType, "WATCH", mapping.SupportsFeature)
+	}
+}
+
+// watchMongo opens a change stream on the collection
+func (c *Client) watchMongo(entity string, docQuery *pb.DocumentQuery) (<-chan ChangeEvent, error) {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	stream, err := c.mongoDB.Collection(docQuery.Collection).Watch(c.ctx, mongobuilders.BuildChangeStreamPipeline(docQuery.Conditions, c.options.MongoDB), opts)
+	if err != nil {
+		return nil, fmt.Errorf("watch error: %w", err)
+	}
+
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		defer stream.Close(context.Background())
+		for stream.Next(c.ctx) {
+			var change struct {
+				OperationType string `bson:"operationType"`
+				FullDocument  bson.M `bson:"fullDocument"`
+				DocumentKey   bson.M `bson:"documentKey"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				continue
+			}
+			event := ChangeEvent{
+				Operation: mongobuilders.ChangeOperation(change.OperationType),
+				Entity:    entity,
+				Document:  bsonToMap(change.FullDocument),
+			}
+			if change.OperationType == "delete" {
+				event.Document = bsonToMap(change.DocumentKey)
+			}
+			select {
+			case events <- event:
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// watchPostgres installs the table's watch trigger and listens on its channel
+// Rows are checked against the conditions by the database, so every WHERE
+// operator works as in queries.
+func (c *Client) watchPostgres(entity string, relQuery *pb.RelationalQuery) (<-chan ChangeEvent, error) {
+	if _, err := c.sqlDB.ExecContext(c.ctx, pgbuilders.BuildWatchTriggerSQL(relQuery.Table)); err != nil {
+		return nil, fmt.Errorf("watch trigger error: %w", err)
+	}
+	notifications, err := c.listen(c.ctx, pgbuilders.WatchChannel(relQuery.Table))
+	if err != nil {
+		return nil, err
+	}
+	filterSQL, filterArgs := pgbuilders.BuildWatchFilterSQL(relQuery.Table, relQuery.Conditions)
+
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		for n := range notifications {
+			var payload struct {
+				Op  string         `json:"op"`
+				Row map[string]any `json:"row"`
+			}
+			if err := json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+				continue
+			}
+			if len(relQuery.Conditions) > 0 {
+				var match int
+				args := append([]any{n.Payload}, filterArgs...)
+				if err := c.sqlDB.QueryRowContext(c.ctx, filterSQL, args...).Scan(&match); err != nil {
+					continue
+				}
+			}
+			select {
+			case events <- ChangeEvent{Operation: payload.Op, Entity: entity, Document: payload.Row}:
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// ============================================
+// PAGINATION
+// ============================================
+
+// PageResult holds one page of rows returned by QueryPage
+type PageResult struct {
+	Rows       []map[string]any
+	Page       int
+	Size       int
+	Total      int64 // Only set when SetPageCount(true)
+	TotalPages int   // Only set when SetPageCount(true)
+}
+
+// QueryPage executes a PAGE/SIZE query. When page counting is enabled it also
+// runs a COUNT over the same filter so callers get total rows and pages.
+func (c *Client) QueryPage(input string) (*PageResult, error) {
+	query, isOQL, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if !isOQL {
+		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
+	}
+
+	if query.Page == 0 || query.PageSize == 0 {
+		return nil, fmt.Errorf("QueryPage requires PAGE and SIZE clauses")
+	}
+
+	rows, err := c.execute(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &PageResult{
+		Rows: rows,
+		Page: query.Page,
+		Size: query.PageSize,
+	}
+
+	if !c.pageCount {
+		return page, nil
+	}
+
+	countRows, err := c.execute(pageCountQuery(query))
+	if err != nil {
+		return nil, fmt.Errorf("page count error: %w", err)
+	}
+
+	page.Total = firstCount(countRows)
+	page.TotalPages = int((page.Total + int64(page.Size) - 1) / int64(page.Size))
+	return page, nil
+}
+
+// CursorResult holds one keyset page returned by QueryCursor
+type CursorResult struct {
+	Rows       []map[string]any
+	NextCursor string // Pass to AFTER for the next page; empty on the last page
+}
+
+// QueryCursor executes an ORDER BY ... [AFTER cursor] LIMIT n query and returns
+// an opaque token built from the ORDER BY values of the last row
+func (c *Client) QueryCursor(input string) (*CursorResult, error) {
+	query, isOQL, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if !isOQL {
+		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
+	}
+
+	if len(query.OrderBy) == 0 || query.Limit == 0 {
+		return nil, fmt.Errorf("QueryCursor requires ORDER BY and LIMIT clauses")
+	}
+
+	rows, err := c.execute(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CursorResult{Rows: rows}
+	if len(rows) < query.Limit {
+		return result, nil
+	}
+
+	last := rows[len(rows)-1]
+	values := make([]*models.Expression, 0, len(query.OrderBy))
+	for _, ob := range query.OrderBy {
+		name := ob.FieldExpr.Value
+		v, ok := last[name]
+		if !ok {
+			return nil, fmt.Errorf("cursor field %s missing from result", name)
+		}
+		values = append(values, cursorValue(v))
+	}
+	result.NextCursor = parser.EncodeCursor(values)
+	return result, nil
+}
+
+// cursorValue converts a row value to the literal it is compared with on the
+// next page, keeping its type: numbers stay numbers, times are RFC 3339 and
+// MongoDB ObjectIDs their hex form
+func cursorValue(v any) *models.Expression {
+	switch val := v.(type) {
+	case nil:
+		return &models.Expression{Type: "FIELD", Value: "NULL"}
+	case bool:
+		return &models.Expression{Type: "BOOLEAN", Value: strconv.FormatBool(val)}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return &models.Expression{Type: "NUMBER", Value: fmt.Sprint(val)}
+	case float32:
+		return &models.Expression{Type: "NUMBER", Value: strconv.FormatFloat(float64(val), 'f', -1, 32)}
+	case float64:
+		return &models.Expression{Type: "NUMBER", Value: strconv.FormatFloat(val, 'f', -1, 64)}
+	case json.Number:
+		return &models.Expression{Type: "NUMBER", Value: val.String()}
+	case primitive.Decimal128:
+		return &models.Expression{Type: "NUMBER", Value: val.String()}
+	case time.Time:
+		return &models.Expression{Type: "STRING", Value: val.Format(time.RFC3339Nano)}
+	case primitive.DateTime:
+		return &models.Expression{Type: "STRING", Value: val.Time().UTC().Format(time.RFC3339Nano)}
+	case primitive.ObjectID:
+		return &models.Expression{Type: "STRING", Value: val.Hex()}
+	case []byte:
+		return &models.Expression{Type: "STRING", Value: string(val)}
+	default:
+		return &models.Expression{Type: "STRING", Value: fmt.Sprint(val)}
+	}
+}
+
+// execute runs an already parsed query against the wrapped database
+func (c *Client) execute(query *models.Query) ([]map[string]any, error) {
+	rows, err := c.executeNative(query)
+	return c.resultFields(rows), err
+}
+
+// resultFields renames the columns of rows to OQL field names when the
+// database has a column naming convention (mapping.ColumnNaming)
+func (c *Client) resultFields(rows []map[string]any) []map[string]any {
+	if mapping.ColumnNaming[c.dbType] == mapping.AsWritten {
+		return rows
+	}
+	for i, row := range rows {
+		renamed := make(map[string]any, len(row))
+		for column, value := range row {
+			renamed[mapping.ResultField(c.dbType, column)] = value
+		}
+		rows[i] = renamed
+	}
+	return rows
+}
+
+func (c *Client) executeNative(query *models.Query) ([]map[string]any, error) {
+	switch c.dbType {
+	case "PostgreSQL", "MySQL", "SQLite", "Oracle", "SQLServer", "CockroachDB", "ClickHouse", "BigQuery", "Snowflake", "Cassandra":
+		return c.execSQL(query)
+	case "MongoDB":
+		return c.execMongo(query)
+	case "Elasticsearch":
+		return c.execElasticsearch(query)
+	case "Redis":
+		return c.execRedis(query)
+	default:
+		if translator.IsDialect(c.dbType) {
+			return c.execSQL(query)
+		}
 		return nil, fmt.Errorf("unsupported database type: %s", c.dbType)
 	}
 }
 
+// pageCountQuery derives the total row count of a page query: COUNT * over the
+// same entity and filter, or, when grouping, DISTINCT or an aggregate make the
+// query return other rows than it matches, COUNT(*) over the query itself:
+// CTE page_rows AS (query without paging) GET page_rows WITH COUNT(*) AS total
+func pageCountQuery(query *models.Query) *models.Query {
+	if len(query.GroupBy) > 0 || query.Distinct || query.Aggregate != nil {
+		rows := *query
+		rows.Limit, rows.Offset, rows.Page, rows.PageSize = 0, 0, 0, 0
+		rows.OrderBy = nil
+		total := &models.Query{
+			Operation: "GET",
+			Entity:    pageRowsName,
+			SelectColumns: []models.SelectColumn{{
+				ExpressionObj: &models.Expression{
+					Type:         "FUNCTION",
+					FunctionName: "COUNT",
+					FunctionArgs: []*models.Expression{{Type: "FIELD", Value: "*"}},
+				},
+				Alias: "total",
+			}},
+		}
+		return &models.Query{
+			Operation: "CTE",
+			ViewName:  pageRowsName,
+			ViewQuery: &rows,
+			CTE:       &models.CTE{Name: pageRowsName, Query: &rows, MainQuery: total},
+		}
+	}
+
+	count := &models.Query{
+		Operation:  "COUNT",
+		Entity:     query.Entity,
+		Conditions: query.Conditions,
+		Joins:      query.Joins,
+		Aggregate: &models.Aggregation{
+			Function:  models.Count,
+			FieldExpr: &models.Expression{Type: "FIELD", Value: "*"},
+		},
+	}
+	return count
+}
+
+// pageRowsName names the rows of a page query counted through a CTE
+const pageRowsName = "page_rows"
+
+// firstCount extracts the count value from the first row of a COUNT result
+func firstCount(rows []map[string]any) int64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	for _, v := range rows[0] {
+		switch n := v.(type) {
+		case int64:
+			return n
+		case int32:
+			return int64(n)
+		case int:
+			return int64(n)
+		case float64:
+			return int64(n)
+		case string:
+			if parsed, err := strconv.ParseInt(n, 10, 64); err == nil {
+				return parsed
+			}
+		}
+	}
+	return 0
+}
+
 // ============================================
 // SQL IMPLEMENTATION (PostgreSQL, MySQL)
 // ============================================
 
 func (c *Client) querySQL(input string) ([]map[string]any, error) {
-	query, isOQL, err := Parse(input)
+	query, isOQL, err := ParseWithSchema(input, c.schema)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
 	}
@@ -114,24 +668,77 @@ func (c *Client) querySQL(input string) ([]map[string]any, error) {
 		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
 	}
 
-	result, err := translator.Translate(query, c.dbType, c.tenantID)
+	return c.execSQL(query)
+}
+
+func (c *Client) execSQL(query *models.Query) ([]map[string]any, error) {
+	// A pooled connection can't hold a LISTEN - notifications would be lost
+	if query.Operation == "LISTEN" || query.Operation == "UNLISTEN" {
+		return nil, fmt.Errorf("%s needs a dedicated connection: use Subscribe", query.Operation)
+	}
+
+	// Per-connection account host unless the statement names one (user@host)
+	if c.userHost != "" && query.Permission != nil && query.Permission.Host == "" {
+		query.Permission.Host = c.userHost
+	}
+
+	result, err := translator.TranslateWithOptions(query, c.dbType, c.tenantID, c.options)
 	if err != nil {
 		return nil, fmt.Errorf("translation error: %w", err)
 	}
 	sqlString := result.GetRelational().Sql
 
+	// Too many rows for one INSERT - load with COPY instead
+	if (c.dbType == "PostgreSQL" || c.dbType == "CockroachDB") && query.Operation == "BULK INSERT" && pgbuilders.UseCopy(result.GetRelational(), c.options.PostgreSQL) {
+		var results []map[string]any
+		err := c.withRetry(query, func() (err error) {
+			results, err = c.copySQL(result.GetRelational())
+			return err
+		})
+		return results, err
+	}
+
+	// BULK UPSERT may span several statements - run the batches in one transaction
+	if c.dbType == "MySQL" && query.Operation == "BULK UPSERT" {
+		var results []map[string]any
+		err := c.withRetry(query, func() (err error) {
+			results, err = c.batchSQL(result.GetRelational())
+			return err
+		})
+		return results, err
+	}
+
+	runner, release, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	upperSQL := strings.ToUpper(strings.TrimSpace(sqlString))
 
-	if strings.HasPrefix(upperSQL, "SELECT") || strings.HasPrefix(upperSQL, "WITH") {
-		rows, err := c.sqlDB.QueryContext(c.ctx, sqlString)
-		if err != nil {
-			return nil, fmt.Errorf("query error: %w", err)
-		}
-		defer rows.Close()
-		return rowsToMaps(rows)
+	// RETURNING makes INSERT/UPDATE/DELETE produce rows, a Cassandra
+	// IF NOT EXISTS returns whether it was [applied], and SHOW reads the
+	// schema (MySQL SHOW, SQLite PRAGMA)
+	if strings.HasPrefix(upperSQL, "SELECT") || strings.HasPrefix(upperSQL, "WITH") ||
+		len(query.Returning) > 0 || query.IfNotExists || strings.HasPrefix(query.Operation, "SHOW ") {
+		var results []map[string]any
+		err := c.withRetry(query, func() error {
+			rows, err := runner.QueryContext(c.ctx, sqlString)
+			if err != nil {
+				return fmt.Errorf("query error: %w", err)
+			}
+			defer rows.Close()
+			results, err = rowsToMaps(rows)
+			return err
+		})
+		return results, err
 	}
 
-	execResult, err := c.sqlDB.ExecContext(c.ctx, sqlString)
+	var execResult sql.Result
+	err = c.withRetry(query, func() error {
+		execResult, err = runner.ExecContext(c.ctx, sqlString)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("exec error: %w", err)
 	}
@@ -145,12 +752,88 @@ func (c *Client) querySQL(input string) ([]map[string]any, error) {
 	}}, nil
 }
 
+// copySQL runs a BULK INSERT through COPY FROM STDIN
+func (c *Client) copySQL(query *pb.RelationalQuery) ([]map[string]any, error) {
+	table, columns, rows, err := pgbuilders.BuildCopyRows(query)
+	if err != nil {
+		return nil, fmt.Errorf("translation error: %w", err)
+	}
+
+	if c.copyFrom != nil {
+		copied, err := c.copyFrom(c.ctx, table, columns, rows)
+		if err != nil {
+			return nil, fmt.Errorf("copy error: %w", err)
+		}
+		return []map[string]any{{"rows_affected": copied}}, nil
+	}
+
+	// database/sql COPY: prepare COPY ... FROM STDIN, one Exec per row, empty Exec to flush
+	tx, err := c.sqlDB.BeginTx(c.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("copy error: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(c.ctx, pgbuilders.BuildCopySQL(query))
+	if err != nil {
+		return nil, fmt.Errorf("copy error (driver may not support COPY, see SetCopyFrom): %w", err)
+	}
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(c.ctx, row...); err != nil {
+			stmt.Close()
+			return nil, fmt.Errorf("copy error: %w", err)
+		}
+	}
+	if _, err := stmt.ExecContext(c.ctx); err != nil {
+		stmt.Close()
+		return nil, fmt.Errorf("copy error: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return nil, fmt.Errorf("copy error: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("copy error: %w", err)
+	}
+
+	return []map[string]any{{"rows_affected": int64(len(rows))}}, nil
+}
+
+// batchSQL runs a MySQL BULK UPSERT, one statement per BulkBatchRows rows
+// (mysqlbuilders.Options)
+func (c *Client) batchSQL(query *pb.RelationalQuery) ([]map[string]any, error) {
+	statements, batchArgs, err := mysqlbuilders.BuildBulkUpsertSQL(query, c.options.MySQL)
+	if err != nil {
+		return nil, fmt.Errorf("translation error: %w", err)
+	}
+
+	tx, err := c.sqlDB.BeginTx(c.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("exec error: %w", err)
+	}
+	defer tx.Rollback()
+
+	var rowsAffected int64
+	for i, statement := range statements {
+		execResult, err := tx.ExecContext(c.ctx, statement, batchArgs[i]...)
+		if err != nil {
+			return nil, fmt.Errorf("exec error (batch %d of %d): %w", i+1, len(statements), err)
+		}
+		n, _ := execResult.RowsAffected()
+		rowsAffected += n
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("exec error: %w", err)
+	}
+
+	return []map[string]any{{"rows_affected": rowsAffected}}, nil
+}
+
 // ============================================
 // MONGODB IMPLEMENTATION
 // ============================================
 
 func (c *Client) queryMongo(input string) ([]map[string]any, error) {
-	query, isOQL, err := Parse(input)
+	query, isOQL, err := ParseWithSchema(input, c.schema)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
 	}
@@ -159,7 +842,13 @@ func (c *Client) queryMongo(input string) ([]map[string]any, error) {
 		return nil, fmt.Errorf("native MongoDB queries not supported, use OmniQL syntax")
 	}
 
-	result, err := translator.Translate(query, "MongoDB", c.tenantID)
+	return c.execMongo(query)
+}
+
+func (c *Client) execMongo(query *models.Query) ([]map[string]any, error) {
+	c.useTextIndex(query)
+
+	result, err := translator.TranslateWithOptions(query, "MongoDB", c.tenantID, c.options)
 	if err != nil {
 		return nil, fmt.Errorf("translation error: %w", err)
 	}
@@ -168,31 +857,153 @@ func (c *Client) queryMongo(input string) ([]map[string]any, error) {
 	collection := c.mongoDB.Collection(docQuery.Collection)
 	operation := strings.ToLower(docQuery.Operation)
 
+	// FACET: every aggregate comes back in one document, keyed by facet name
+	if len(docQuery.Facets) > 0 {
+		return c.mongoAggregate(collection, mongobuilders.BuildMongoDBAggregatePipeline(docQuery, c.options.MongoDB), docQuery.Collation)
+	}
+
+	// CREATE TABLE ... AS GET and CREATE / REPLACE / UPSERT ... FROM GET:
+	// the GET's collection is aggregated into this one with $out or $merge
+	if mongobuilders.WritesFromQuery(docQuery) {
+		pipeline, err := mongobuilders.BuildMongoWriteFromPipeline(docQuery, c.options.MongoDB)
+		if err != nil {
+			return nil, err
+		}
+		source := c.mongoDB.Collection(docQuery.ViewQuery.Collection)
+		return c.mongoAggregate(source, pipeline, docQuery.ViewQuery.Collation)
+	}
+
+	// Window functions, including aggregates such as SUM amount OVER (...)
+	if len(docQuery.WindowFunctions) > 0 {
+		pipeline, err := mongobuilders.BuildWindowFunctionPipeline(docQuery, c.options.MongoDB)
+		if err != nil {
+			return nil, err
+		}
+		return c.mongoAggregate(collection, pipeline, docQuery.Collation)
+	}
+
 	switch operation {
 	case "find":
-		return c.mongoFind(collection, docQuery.Query)
+		if mongobuilders.IsGroupedSelect(docQuery) {
+			pipeline, err := mongobuilders.BuildMongoDBGroupedPipeline(docQuery, c.options.MongoDB)
+			if err != nil {
+				return nil, err
+			}
+			return c.mongoAggregate(collection, pipeline, docQuery.Collation)
+		}
+		if mongobuilders.NeedsSelectPipeline(docQuery) {
+			pipeline, err := mongobuilders.BuildMongoDBSelectPipeline(docQuery, c.options.MongoDB)
+			if err != nil {
+				return nil, err
+			}
+			return c.mongoAggregate(collection, pipeline, docQuery.Collation)
+		}
+		return c.mongoFind(collection, docQuery)
 	case "insertone":
-		return c.mongoInsert(collection, docQuery.Fields)
+		return c.mongoInsert(collection, docQuery)
 	case "updateone":
-		return c.mongoUpdate(collection, docQuery.Query, docQuery.Fields)
+		return c.mongoUpdate(collection, docQuery)
 	case "deleteone":
-		return c.mongoDelete(collection, docQuery.Query)
+		return c.mongoDelete(collection, docQuery)
 	case "count":
-		return c.mongoCount(collection, docQuery.Query)
+		return c.mongoCount(collection, docQuery)
+	case "graphlookup":
+		return c.mongoAggregate(collection, mongobuilders.BuildGraphLookupPipeline(docQuery, c.options.MongoDB), docQuery.Collation)
+	case "unionwith", "intersect", "setdifference":
+		pipeline, err := mongobuilders.BuildSetOperationPipeline(docQuery, c.options.MongoDB)
+		if err != nil {
+			return nil, err
+		}
+		return c.mongoAggregate(collection, pipeline, docQuery.Collation)
+	case "createcollection":
+		return c.mongoCreateCollection(docQuery)
+	case "create_index":
+		return c.mongoCreateIndex(collection, docQuery)
 	default:
 		return nil, fmt.Errorf("unsupported MongoDB operation: %s", operation)
 	}
 }
 
-func (c *Client) mongoFind(coll *mongo.Collection, queryStr string) ([]map[string]any, error) {
-	var filter bson.M
-	if queryStr != "" && queryStr != "{}" {
-		if err := json.Unmarshal([]byte(queryStr), &filter); err != nil {
-			filter = bson.M{}
+// useTextIndex turns a LIKE '%words%' on a text-indexed field into SEARCH.
+// $text matches stemmed words anywhere in the index rather than a substring of
+// one field, and MongoDB allows a single $text per query, so only the first such
+// LIKE of an AND-only filter without a SEARCH of its own is rewritten.
+func (c *Client) useTextIndex(query *models.Query) {
+	var fields []string
+	for entity, indexed := range c.textIndexes {
+		if strings.EqualFold(entity, query.Entity) {
+			fields = indexed
+		}
+	}
+	if len(fields) == 0 || hasSearchCondition(query.Conditions) {
+		return
+	}
+	for _, cond := range query.Conditions {
+		if strings.ToUpper(cond.Logic) == "OR" {
+			return
+		}
+	}
+
+	for i, cond := range query.Conditions {
+		if cond.Operator != "LIKE" || cond.FieldExpr == nil || cond.ValueExpr == nil {
+			continue
+		}
+		words, ok := textSearchWords(cond.ValueExpr.Value)
+		if !ok || !slices.Contains(fields, cond.FieldExpr.Value) {
+			continue
+		}
+		value := *cond.ValueExpr
+		value.Value = words
+		query.Conditions[i].Operator = "SEARCH"
+		query.Conditions[i].ValueExpr = &value
+		return
+	}
+}
+
+// hasSearchCondition reports whether a SEARCH condition is present (recursive)
+func hasSearchCondition(conditions []models.Condition) bool {
+	for _, cond := range conditions {
+		if cond.Operator == "SEARCH" || hasSearchCondition(cond.Nested) {
+			return true
 		}
 	}
+	return false
+}
+
+// textSearchWords returns the words of an unanchored '%words%' pattern
+// Anchored patterns and inner wildcards have no $text equivalent.
+func textSearchWords(pattern string) (string, bool) {
+	if len(pattern) < 3 || !strings.HasPrefix(pattern, "%") || !strings.HasSuffix(pattern, "%") {
+		return "", false
+	}
+	words := strings.TrimSpace(pattern[1 : len(pattern)-1])
+	if words == "" || strings.ContainsAny(words, "%_") {
+		return "", false
+	}
+	return words, true
+}
+
+func (c *Client) mongoFind(coll *mongo.Collection, docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	filter := mongobuilders.BuildMongoFilter(docQuery.Conditions, c.options.MongoDB)
+
+	opts := options.Find()
+	if docQuery.Limit > 0 {
+		opts.SetLimit(int64(docQuery.Limit))
+	}
+	if docQuery.Skip > 0 {
+		opts.SetSkip(int64(docQuery.Skip))
+	}
+	if len(docQuery.OrderBy) > 0 {
+		opts.SetSort(mongobuilders.BuildMongoDBSortStage(docQuery.OrderBy)["$sort"])
+	}
+	if projection := mongobuilders.BuildMongoFindProjection(docQuery); projection != nil {
+		opts.SetProjection(projection)
+	}
+	if collation := mongobuilders.BuildMongoCollation(docQuery.Collation); collation != nil {
+		opts.SetCollation(collation)
+	}
 
-	cursor, err := coll.Find(c.ctx, filter)
+	cursor, err := coll.Find(c.ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("find error: %w", err)
 	}
@@ -210,13 +1021,11 @@ func (c *Client) mongoFind(coll *mongo.Collection, queryStr string) ([]map[strin
 	return results, nil
 }
 
-func (c *Client) mongoInsert(coll *mongo.Collection, fields []*pb.QueryField) ([]map[string]any, error) {
-	doc := bson.M{}
-	for _, f := range fields {
-		if f.NameExpr != nil && f.ValueExpr != nil {
-			doc[f.NameExpr.Value] = f.ValueExpr.Value
-		}
+func (c *Client) mongoInsert(coll *mongo.Collection, docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	if err := c.mongoEnsureTTLIndex(coll, docQuery); err != nil {
+		return nil, err
 	}
+	doc := mongobuilders.BuildMongoInsertDocument(docQuery, c.options.MongoDB)
 
 	result, err := coll.InsertOne(c.ctx, doc)
 	if err != nil {
@@ -229,37 +1038,68 @@ func (c *Client) mongoInsert(coll *mongo.Collection, fields []*pb.QueryField) ([
 	}}, nil
 }
 
-func (c *Client) mongoUpdate(coll *mongo.Collection, queryStr string, fields []*pb.QueryField) ([]map[string]any, error) {
-	var filter bson.M
-	if queryStr != "" {
-		json.Unmarshal([]byte(queryStr), &filter)
+func (c *Client) mongoUpdate(coll *mongo.Collection, docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	filter := mongobuilders.BuildMongoFilter(docQuery.Conditions, c.options.MongoDB)
+	update := mongobuilders.BuildMongoSimpleUpdate(docQuery.Fields, c.options.MongoDB)
+	// UPSERT matches on its conflict fields and inserts when nothing matches
+	if docQuery.Upsert != nil {
+		filter, update = mongobuilders.BuildMongoUpsert(docQuery, c.options.MongoDB)
+	}
+	if err := c.mongoEnsureTTLIndex(coll, docQuery); err != nil {
+		return nil, err
 	}
 
-	updateDoc := bson.M{}
-	for _, f := range fields {
-		if f.NameExpr != nil && f.ValueExpr != nil {
-			updateDoc[f.NameExpr.Value] = f.ValueExpr.Value
-		}
+	if len(docQuery.Returning) > 0 {
+		return c.mongoFindOneAndUpdate(coll, docQuery, filter, update)
 	}
-	update := bson.M{"$set": updateDoc}
 
-	result, err := coll.UpdateOne(c.ctx, filter, update)
+	opts := options.Update().SetUpsert(docQuery.Upsert != nil)
+	if len(docQuery.ArrayFilters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: mongobuilders.BuildMongoArrayFilters(docQuery.ArrayFilters, c.options.MongoDB)})
+	}
+	if collation := mongobuilders.BuildMongoCollation(docQuery.Collation); collation != nil {
+		opts.SetCollation(collation)
+	}
+
+	result, err := coll.UpdateOne(c.ctx, filter, update, opts)
 	if err != nil {
 		return nil, fmt.Errorf("update error: %w", err)
 	}
 
 	return []map[string]any{{
-		"rows_affected": result.ModifiedCount,
+		"rows_affected": result.ModifiedCount + result.UpsertedCount,
 	}}, nil
 }
 
-func (c *Client) mongoDelete(coll *mongo.Collection, queryStr string) ([]map[string]any, error) {
-	var filter bson.M
-	if queryStr != "" {
-		json.Unmarshal([]byte(queryStr), &filter)
+// mongoEnsureTTLIndex creates the TTL index that deletes expired documents
+// before the first write with a TTL to a collection
+func (c *Client) mongoEnsureTTLIndex(coll *mongo.Collection, docQuery *pb.DocumentQuery) error {
+	if docQuery.TtlMs <= 0 || c.ttlIndexes[coll.Name()] {
+		return nil
+	}
+	if _, err := coll.Indexes().CreateOne(c.ctx, mongobuilders.BuildMongoTTLIndex(c.options.MongoDB)); err != nil {
+		return fmt.Errorf("ttl index error: %w", err)
+	}
+	if c.ttlIndexes == nil {
+		c.ttlIndexes = map[string]bool{}
+	}
+	c.ttlIndexes[coll.Name()] = true
+	return nil
+}
+
+func (c *Client) mongoDelete(coll *mongo.Collection, docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	filter := mongobuilders.BuildMongoFilter(docQuery.Conditions, c.options.MongoDB)
+
+	if len(docQuery.Returning) > 0 {
+		return c.mongoFindOneAndDelete(coll, docQuery, filter)
+	}
+
+	opts := options.Delete()
+	if collation := mongobuilders.BuildMongoCollation(docQuery.Collation); collation != nil {
+		opts.SetCollation(collation)
 	}
 
-	result, err := coll.DeleteOne(c.ctx, filter)
+	result, err := coll.DeleteOne(c.ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("delete error: %w", err)
 	}
@@ -269,13 +1109,56 @@ func (c *Client) mongoDelete(coll *mongo.Collection, queryStr string) ([]map[str
 	}}, nil
 }
 
-func (c *Client) mongoCount(coll *mongo.Collection, queryStr string) ([]map[string]any, error) {
-	var filter bson.M
-	if queryStr != "" {
-		json.Unmarshal([]byte(queryStr), &filter)
+// mongoFindOneAndUpdate runs UPDATE ... RETURNING: the updated document comes
+// back as the only row, or no rows when nothing matched
+func (c *Client) mongoFindOneAndUpdate(coll *mongo.Collection, docQuery *pb.DocumentQuery, filter, update bson.M) ([]map[string]any, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetUpsert(docQuery.Upsert != nil)
+	if projection := mongobuilders.BuildMongoReturningProjection(docQuery.Returning); projection != nil {
+		opts.SetProjection(projection)
+	}
+	if len(docQuery.ArrayFilters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: mongobuilders.BuildMongoArrayFilters(docQuery.ArrayFilters, c.options.MongoDB)})
+	}
+	if collation := mongobuilders.BuildMongoCollation(docQuery.Collation); collation != nil {
+		opts.SetCollation(collation)
+	}
+	return returnedDocument(coll.FindOneAndUpdate(c.ctx, filter, update, opts), "update")
+}
+
+// mongoFindOneAndDelete runs DELETE ... RETURNING: the deleted document comes
+// back as the only row, or no rows when nothing matched
+func (c *Client) mongoFindOneAndDelete(coll *mongo.Collection, docQuery *pb.DocumentQuery, filter bson.M) ([]map[string]any, error) {
+	opts := options.FindOneAndDelete()
+	if projection := mongobuilders.BuildMongoReturningProjection(docQuery.Returning); projection != nil {
+		opts.SetProjection(projection)
+	}
+	if collation := mongobuilders.BuildMongoCollation(docQuery.Collation); collation != nil {
+		opts.SetCollation(collation)
+	}
+	return returnedDocument(coll.FindOneAndDelete(c.ctx, filter, opts), "delete")
+}
+
+// returnedDocument decodes the document of a findOneAnd* into RETURNING rows
+func returnedDocument(result *mongo.SingleResult, operation string) ([]map[string]any, error) {
+	var doc bson.M
+	if err := result.Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return []map[string]any{}, nil
+		}
+		return nil, fmt.Errorf("%s error: %w", operation, err)
+	}
+	return []map[string]any{bsonToMap(doc)}, nil
+}
+
+func (c *Client) mongoCount(coll *mongo.Collection, docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	filter := mongobuilders.BuildMongoFilter(docQuery.Conditions, c.options.MongoDB)
+
+	opts := options.Count()
+	if collation := mongobuilders.BuildMongoCollation(docQuery.Collation); collation != nil {
+		opts.SetCollation(collation)
 	}
 
-	count, err := coll.CountDocuments(c.ctx, filter)
+	count, err := coll.CountDocuments(c.ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("count error: %w", err)
 	}
@@ -285,12 +1168,232 @@ func (c *Client) mongoCount(coll *mongo.Collection, queryStr string) ([]map[stri
 	}}, nil
 }
 
+// mongoCreateCollection creates a collection, validated by $jsonSchema when
+// CREATE TABLE declared typed columns
+func (c *Client) mongoCreateCollection(docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	opts := options.CreateCollection()
+	if validator := mongobuilders.BuildMongoJSONSchema(docQuery.Fields); validator != nil {
+		opts.SetValidator(validator)
+	}
+
+	if err := c.mongoDB.CreateCollection(c.ctx, docQuery.Collection, opts); err != nil {
+		return nil, fmt.Errorf("create collection error: %w", err)
+	}
+
+	return []map[string]any{{
+		"collection": docQuery.Collection,
+	}}, nil
+}
+
+func (c *Client) mongoCreateIndex(coll *mongo.Collection, docQuery *pb.DocumentQuery) ([]map[string]any, error) {
+	model, err := mongobuilders.BuildMongoIndexModel(docQuery, c.options.MongoDB)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := coll.Indexes().CreateOne(c.ctx, model)
+	if err != nil {
+		return nil, fmt.Errorf("create index error: %w", err)
+	}
+
+	return []map[string]any{{
+		"index": name,
+	}}, nil
+}
+
+func (c *Client) mongoAggregate(coll *mongo.Collection, pipeline []bson.M, collation *pb.CollationClause) ([]map[string]any, error) {
+	opts := options.Aggregate()
+	if mongoCollation := mongobuilders.BuildMongoCollation(collation); mongoCollation != nil {
+		opts.SetCollation(mongoCollation)
+	}
+
+	cursor, err := coll.Aggregate(c.ctx, pipeline, opts)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate error: %w", err)
+	}
+	defer cursor.Close(c.ctx)
+
+	var results []map[string]any
+	for cursor.Next(c.ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		results = append(results, bsonToMap(doc))
+	}
+
+	return results, nil
+}
+
+// ============================================
+// BULK WRITE (MongoDB)
+// ============================================
+
+// BulkResult holds the totals of a BulkWrite and the writes that failed
+type BulkResult struct {
+	Inserted int64
+	Matched  int64
+	Modified int64
+	Deleted  int64
+	Upserted int64
+	Errors   []BulkError
+}
+
+// BulkError is one failed write; Index is its statement's position in the batch
+type BulkError struct {
+	Index   int
+	Code    int
+	Message string
+}
+
+// BulkWrite runs CREATE, BULK INSERT, UPDATE, UPSERT, REPLACE and DELETE
+// statements on one collection as a single MongoDB bulkWrite. Ordered stops at
+// the first failed write; unordered attempts them all. When writes fail the
+// result lists them and is returned along with the error.
+func (c *Client) BulkWrite(inputs []string, ordered bool) (*BulkResult, error) {
+	if !mapping.SupportsFeature(c.dbType, "BULK WRITE") {
+		return nil, mapping.NotSupported(c.dbType, "BULK WRITE", mapping.SupportsFeature)
+	}
+	if len(inputs) == 0 {
+		return &BulkResult{}, nil
+	}
+
+	var collection string
+	var writes []mongo.WriteModel
+	var owners []int // Statement index of each write
+	for i, input := range inputs {
+		query, isOQL, err := ParseWithSchema(input, c.schema)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: parse error: %w", i, err)
+		}
+		if !isOQL {
+			return nil, fmt.Errorf("statement %d: OmniQL syntax required: queries must start with ':'", i)
+		}
+		c.useTextIndex(query)
+
+		result, err := translator.TranslateWithOptions(query, "MongoDB", c.tenantID, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: translation error: %w", i, err)
+		}
+		docQuery := result.GetDocument()
+		if collection == "" {
+			collection = docQuery.Collection
+		} else if docQuery.Collection != collection {
+			return nil, fmt.Errorf("statement %d: a bulk write targets one collection (%s, got %s)", i, collection, docQuery.Collection)
+		}
+
+		statementWrites, err := mongobuilders.BuildMongoWriteModels(docQuery, c.options.MongoDB)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+		for range statementWrites {
+			owners = append(owners, i)
+		}
+		writes = append(writes, statementWrites...)
+	}
+
+	res, err := c.mongoDB.Collection(collection).BulkWrite(c.ctx, writes, options.BulkWrite().SetOrdered(ordered))
+	result := &BulkResult{}
+	if res != nil {
+		result.Inserted = res.InsertedCount
+		result.Matched = res.MatchedCount
+		result.Modified = res.ModifiedCount
+		result.Deleted = res.DeletedCount
+		result.Upserted = res.UpsertedCount
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+		for _, writeErr := range bulkErr.WriteErrors {
+			result.Errors = append(result.Errors, BulkError{
+				Index:   owners[writeErr.Index],
+				Code:    writeErr.Code,
+				Message: writeErr.Message,
+			})
+		}
+		return result, fmt.Errorf("bulk write error: %d of %d writes failed", len(bulkErr.WriteErrors), len(writes))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bulk write error: %w", err)
+	}
+	return result, nil
+}
+
+// ============================================
+// ELASTICSEARCH IMPLEMENTATION
+// ============================================
+
+func (c *Client) queryElasticsearch(input string) ([]map[string]any, error) {
+	query, isOQL, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if !isOQL {
+		return nil, fmt.Errorf("native Elasticsearch queries not supported, use OmniQL syntax")
+	}
+
+	return c.execElasticsearch(query)
+}
+
+func (c *Client) execElasticsearch(query *models.Query) ([]map[string]any, error) {
+	result, err := translator.TranslateWithOptions(query, "Elasticsearch", c.tenantID, c.options)
+	if err != nil {
+		return nil, fmt.Errorf("translation error: %w", err)
+	}
+
+	docQuery := result.GetDocument()
+	endpoint, body, err := esbuilders.BuildRequest(docQuery, c.options.Elasticsearch)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.esURL+"/"+url.PathEscape(docQuery.Collection)+"/"+endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := c.esClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("search error: %w", err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("search error: %w", err)
+	}
+	if response.StatusCode >= 300 {
+		return nil, elasticsearchError(response.Status, data)
+	}
+
+	return esbuilders.Rows(docQuery, endpoint, data)
+}
+
+// elasticsearchError reports a failed request by the error type and reason
+// in its body (index_not_found_exception: no such index [users])
+func elasticsearchError(status string, body []byte) error {
+	var failure struct {
+		Error struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &failure) == nil && failure.Error.Type != "" {
+		return fmt.Errorf("search error: %s: %s", failure.Error.Type, failure.Error.Reason)
+	}
+	return fmt.Errorf("search error: %s", status)
+}
+
 // ============================================
 // REDIS IMPLEMENTATION
 // ============================================
 
 func (c *Client) queryRedis(input string) ([]map[string]any, error) {
-	query, isOQL, err := Parse(input)
+	query, isOQL, err := ParseWithSchema(input, c.schema)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
 	}
@@ -299,7 +1402,11 @@ func (c *Client) queryRedis(input string) ([]map[string]any, error) {
 		return nil, fmt.Errorf("native Redis queries not supported, use OmniQL syntax")
 	}
 
-	result, err := translator.Translate(query, "Redis", c.tenantID)
+	return c.execRedis(query)
+}
+
+func (c *Client) execRedis(query *models.Query) ([]map[string]any, error) {
+	result, err := translator.TranslateWithOptions(query, "Redis", c.tenantID, c.options)
 	if err != nil {
 		return nil, fmt.Errorf("translation error: %w", err)
 	}
@@ -311,11 +1418,20 @@ func (c *Client) queryRedis(input string) ([]map[string]any, error) {
 	case "HGETALL":
 		return c.redisGet(kvQuery)
 	case "HMSET":
+		if kvQuery.Upsert != nil {
+			return c.redisUpsert(kvQuery)
+		}
 		return c.redisCreate(kvQuery)
+	case "BULK INSERT":
+		return c.redisBulkInsert(kvQuery)
 	case "HSET":
 		return c.redisUpdate(kvQuery)
 	case "DEL":
 		return c.redisDelete(kvQuery)
+	case "FT.SEARCH":
+		return c.redisSearch(kvQuery)
+	case "FT.CREATE":
+		return c.redisSearchCreate(kvQuery)
 	case "COUNT":
 		return c.redisCount(kvQuery)
 	case "SUM", "AVG", "MIN", "MAX":
@@ -333,9 +1449,6 @@ func (c *Client) queryRedis(input string) ([]map[string]any, error) {
 
 func (c *Client) redisGet(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
 	key := kvQuery.Key
-	conditions := kvQuery.Conditions
-	limit := int(kvQuery.Limit)
-	offset := int(kvQuery.Offset)
 
 	// Direct key lookup (no wildcard)
 	if !strings.Contains(key, "*") {
@@ -349,64 +1462,248 @@ func (c *Client) redisGet(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
 		return []map[string]any{stringMapToAnyMap(hash)}, nil
 	}
 
-	// Pattern scan + filter
+	// ORDER BY walks the field's sorted set, or sorts the matches in Go
+	if len(kvQuery.OrderBy) > 0 {
+		return c.redisGetOrdered(kvQuery)
+	}
+
+	// Index lookup or pattern scan + filter
+	stream, err := c.redisStream(kvQuery)
+	if err != nil {
+		return nil, err
+	}
 	var results []map[string]any
-	var cursor uint64
-	skipped := 0
+	for stream.Next() {
+		results = append(results, stream.Row())
+	}
+	return results, stream.Err()
+}
+
+// redisStream reads the records of a GET as the stream asks for them: each
+// candidate key is fetched with HGETALL and checked against the WHERE clause,
+// then OFFSET and LIMIT are applied
+func (c *Client) redisStream(kvQuery *pb.KeyValueQuery) (*RowStream, error) {
+	nextKey, err := c.redisKeys(kvQuery)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := int(kvQuery.Offset), int(kvQuery.Limit)
+	skipped, sent := 0, 0
+	return &RowStream{next: func() (map[string]any, error) {
+		for limit == 0 || sent < limit {
+			key, ok, err := nextKey()
+			if err != nil || !ok {
+				return nil, err
+			}
+			hash, err := c.redisDB.HGetAll(c.ctx, key).Result()
+			if err != nil {
+				return nil, fmt.Errorf("hgetall error: %w", err)
+			}
+			if len(hash) == 0 || !redisMatches(key, hash, kvQuery.Conditions) {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			sent++
+			return stringMapToAnyMap(hash), nil
+		}
+		return nil, nil
+	}}, nil
+}
+
+// redisGetOrdered runs a GET with ORDER BY. With secondary indexes, a single
+// field every record holds a number or date in is read in order from its
+// sorted set, so only the page is fetched; otherwise every match is sorted.
+func (c *Client) redisGetOrdered(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
+	if c.options.Redis.SecondaryIndexes {
+		if key, reverse, ok := redisbuilders.PlanSortedRange(c.tenantID, kvQuery.Entity, kvQuery.OrderBy, c.options.Redis); ok {
+			covered, err := c.redisSortedSetCovers(kvQuery.Entity, key)
+			if err != nil {
+				return nil, err
+			}
+			if covered {
+				return c.redisGetSorted(kvQuery, key, reverse)
+			}
+		}
+	}
+
+	keys, err := c.redisCandidateKeys(kvQuery)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]string
+	for _, k := range keys {
+		hash, err := c.redisDB.HGetAll(c.ctx, k).Result()
+		if err != nil || len(hash) == 0 || !redisMatches(k, hash, kvQuery.Conditions) {
+			continue
+		}
+		rows = append(rows, hash)
+	}
+	redisbuilders.SortRows(rows, kvQuery.OrderBy)
+
+	offset, limit := int(kvQuery.Offset), int(kvQuery.Limit)
+	if offset >= len(rows) {
+		return []map[string]any{}, nil
+	}
+	rows = rows[offset:]
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	results := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		results[i] = stringMapToAnyMap(row)
+	}
+	return results, nil
+}
+
+// redisSortedSetCovers reports whether every record of an entity is in a
+// field's sorted set; a record without a sortable value would be left out
+func (c *Client) redisSortedSetCovers(entity, key string) (bool, error) {
+	sorted, err := c.redisDB.ZCard(c.ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("zcard error: %w", err)
+	}
+	all, err := c.redisDB.SCard(c.ctx, redisbuilders.AllKey(redisbuilders.IndexPrefix(c.tenantID, entity, c.options.Redis))).Result()
+	if err != nil {
+		return false, fmt.Errorf("scard error: %w", err)
+	}
+	return sorted == all, nil
+}
+
+// redisGetSorted reads records in the order of a sorted set
+// Without WHERE the page is a single ZRANGE (ZREVRANGE for DESC); with one
+// the set is walked in batches, skipping keys the indexes rule out.
+func (c *Client) redisGetSorted(kvQuery *pb.KeyValueQuery, key string, reverse bool) ([]map[string]any, error) {
+	conditions := kvQuery.Conditions
+	offset, limit := int64(kvQuery.Offset), int64(kvQuery.Limit)
 
-	for {
-		keys, nextCursor, err := c.redisDB.Scan(c.ctx, cursor, key, 100).Result()
+	if len(conditions) == 0 {
+		stop := int64(-1)
+		if limit > 0 {
+			stop = offset + limit - 1
+		}
+		keys, err := c.redisDB.ZRangeArgs(c.ctx, redis.ZRangeArgs{Key: key, Start: offset, Stop: stop, Rev: reverse}).Result()
 		if err != nil {
-			return nil, fmt.Errorf("scan error: %w", err)
+			return nil, fmt.Errorf("zrange error: %w", err)
 		}
-
+		results := []map[string]any{}
 		for _, k := range keys {
 			hash, err := c.redisDB.HGetAll(c.ctx, k).Result()
 			if err != nil || len(hash) == 0 {
 				continue
 			}
+			results = append(results, stringMapToAnyMap(hash))
+		}
+		return results, nil
+	}
 
-			// Apply conditions filter
-			if len(conditions) > 0 {
-				if !redisbuilders.MatchesConditions(hash, conditions) {
-					continue
-				}
-			}
+	var candidates map[string]bool
+	if keys, ok, err := c.redisIndexedKeys(kvQuery); err != nil {
+		return nil, err
+	} else if ok {
+		candidates = make(map[string]bool, len(keys))
+		for _, k := range keys {
+			candidates[k] = true
+		}
+	}
 
-			// Handle offset
+	const batch = 100
+	results := []map[string]any{}
+	skipped := int64(0)
+	for start := int64(0); ; start += batch {
+		keys, err := c.redisDB.ZRangeArgs(c.ctx, redis.ZRangeArgs{Key: key, Start: start, Stop: start + batch - 1, Rev: reverse}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("zrange error: %w", err)
+		}
+		for _, k := range keys {
+			if candidates != nil && !candidates[k] {
+				continue
+			}
+			hash, err := c.redisDB.HGetAll(c.ctx, k).Result()
+			if err != nil || len(hash) == 0 || !redisMatches(k, hash, conditions) {
+				continue
+			}
 			if skipped < offset {
 				skipped++
 				continue
 			}
-
 			results = append(results, stringMapToAnyMap(hash))
-
-			// Handle limit
-			if limit > 0 && len(results) >= limit {
+			if limit > 0 && int64(len(results)) >= limit {
 				return results, nil
 			}
 		}
-
-		cursor = nextCursor
-		if cursor == 0 {
-			break
+		if len(keys) < batch {
+			return results, nil
 		}
 	}
+}
 
+// redisSearch runs a GET compiled to FT.SEARCH
+func (c *Client) redisSearch(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
+	args := []interface{}{"FT.SEARCH", kvQuery.Key}
+	for _, arg := range kvQuery.Args {
+		args = append(args, arg)
+	}
+	reply, err := c.redisDB.Do(c.ctx, args...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("ft.search error: %w", err)
+	}
+	rows, err := redisbuilders.SearchRows(reply)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		results[i] = stringMapToAnyMap(row)
+	}
 	return results, nil
 }
 
+// redisSearchCreate creates an entity's RediSearch index
+func (c *Client) redisSearchCreate(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
+	args := []interface{}{"FT.CREATE", kvQuery.Key}
+	for _, arg := range kvQuery.Args {
+		args = append(args, arg)
+	}
+	if err := c.redisDB.Do(c.ctx, args...).Err(); err != nil {
+		return nil, fmt.Errorf("ft.create error: %w", err)
+	}
+	return []map[string]any{{"status": "index_created", "index": kvQuery.Key}}, nil
+}
+
 func (c *Client) redisCreate(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
 	key := kvQuery.Key
 	args := kvQuery.Args
 
+	if c.options.Redis.SecondaryIndexes {
+		if err := c.redisIndexedWrite(kvQuery.Entity, key, args, kvQuery.TtlMs); err != nil {
+			return nil, fmt.Errorf("hmset error: %w", err)
+		}
+		return []map[string]any{{
+			"inserted_id":   key,
+			"rows_affected": 1,
+		}}, nil
+	}
+
 	// Convert args to field-value pairs
 	fieldValues := make([]interface{}, len(args))
 	for i, arg := range args {
 		fieldValues[i] = arg
 	}
 
-	err := c.redisDB.HMSet(c.ctx, key, fieldValues...).Err()
+	var err error
+	if kvQuery.TtlMs > 0 {
+		// TTL: the hash and its expiry are written together
+		_, err = c.redisDB.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			pipe.HMSet(c.ctx, key, fieldValues...)
+			pipe.Do(c.ctx, redisCommandArgs(redisbuilders.ExpireArgs(key, kvQuery.TtlMs))...)
+			return nil
+		})
+	} else {
+		err = c.redisDB.HMSet(c.ctx, key, fieldValues...).Err()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("hmset error: %w", err)
 	}
@@ -417,20 +1714,257 @@ func (c *Client) redisCreate(kvQuery *pb.KeyValueQuery) ([]map[string]any, error
 	}}, nil
 }
 
-func (c *Client) redisUpdate(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
-	key := kvQuery.Key
-	args := kvQuery.Args
+// redisUpsertAttempts bounds how often an UPSERT looks its record up again
+// after another client changed it between the lookup and the write
+const redisUpsertAttempts = 5
+
+// redisUpsertScript runs UpsertScript with EVALSHA, loading it with EVAL the
+// first time a server has not seen it
+var redisUpsertScript = redis.NewScript(redisbuilders.UpsertScript)
+
+// redisUpsert inserts a record or updates the one holding its conflict
+// values. The record is found by key for ON id, else by its conflict fields,
+// and UpsertScript writes it only if it still holds them, so a concurrent
+// write cannot be overwritten. With secondary indexes the write must move
+// index entries too, and redisWatchedUpsert runs it under WATCH instead.
+func (c *Client) redisUpsert(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
+	conflict, update := redisbuilders.UpsertFields(kvQuery.Upsert, kvQuery.Args)
+	if c.options.Redis.SecondaryIndexes {
+		return c.redisWatchedUpsert(kvQuery, conflict, update)
+	}
 
-	// Convert args to field-value pairs
-	for i := 0; i < len(args)-1; i += 2 {
-		err := c.redisDB.HSet(c.ctx, key, args[i], args[i+1]).Err()
+	argv := redisbuilders.UpsertScriptArgs(conflict, kvQuery.Args, update, kvQuery.TtlMs)
+	for attempt := 0; attempt < redisUpsertAttempts; attempt++ {
+		key := kvQuery.Key
+		if len(conflict) > 0 {
+			keys, err := c.redisMatchingKeys(&pb.KeyValueQuery{
+				Key:        redisbuilders.KeyPattern(c.tenantID, kvQuery.Entity, c.options.Redis),
+				Entity:     kvQuery.Entity,
+				Conditions: redisbuilders.UpsertConflictConditions(conflict),
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(keys) > 0 {
+				sort.Strings(keys)
+				key = keys[0]
+			}
+		}
+
+		reply, err := redisUpsertScript.Run(c.ctx, c.redisDB, []string{key}, argv...).Int64()
+		if err != nil {
+			return nil, fmt.Errorf("upsert script error: %w", err)
+		}
+		if reply != redisbuilders.UpsertScriptConflict {
+			return redisUpsertResult(key, reply == 1), nil
+		}
+	}
+	return nil, fmt.Errorf("upsert error: record changed concurrently %d times", redisUpsertAttempts)
+}
+
+// redisWatchedUpsert runs an UPSERT with secondary indexes: the conflict
+// sets and the record are WATCHed while the record is read, and the write
+// and its index changes go in one MULTI/EXEC that fails if either changed
+func (c *Client) redisWatchedUpsert(kvQuery *pb.KeyValueQuery, conflict, update map[string]string) ([]map[string]any, error) {
+	watched := []string{kvQuery.Key}
+	var sets []string
+	if len(conflict) > 0 {
+		sets, _ = redisbuilders.PlanAggregateSets(c.tenantID, kvQuery.Entity, redisbuilders.UpsertConflictConditions(conflict), c.options.Redis)
+		watched = sets
+	}
+	prefix := redisbuilders.IndexPrefix(c.tenantID, kvQuery.Entity, c.options.Redis)
+	if err := c.redisCheckSlots(append([]string{kvQuery.Key, redisbuilders.AllKey(prefix)}, watched...)...); err != nil {
+		return nil, err
+	}
+
+	var key string
+	var inserted bool
+	upsert := func(tx *redis.Tx) error {
+		key = kvQuery.Key
+		if len(sets) > 0 {
+			keys, err := tx.SInter(c.ctx, sets...).Result()
+			if err != nil {
+				return err
+			}
+			if len(keys) > 0 {
+				sort.Strings(keys)
+				key = keys[0]
+				if err := tx.Watch(c.ctx, key).Err(); err != nil {
+					return err
+				}
+			}
+		}
+		old, err := tx.HGetAll(c.ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		fields := update
+		inserted = len(old) == 0
+		if inserted {
+			old = nil
+			fields = make(map[string]string, len(kvQuery.Args)/2)
+			for i := 0; i+1 < len(kvQuery.Args); i += 2 {
+				fields[kvQuery.Args[i]] = kvQuery.Args[i+1]
+			}
+		}
+		updated := make(map[string]string, len(old)+len(fields))
+		for field, value := range old {
+			updated[field] = value
+		}
+		for field, value := range fields {
+			updated[field] = value
+		}
+
+		_, err = tx.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+			if len(fields) > 0 {
+				pipe.HSet(c.ctx, key, fields)
+			}
+			for _, command := range redisbuilders.IndexChanges(prefix, key, old, updated) {
+				pipe.Do(c.ctx, command...)
+			}
+			if kvQuery.TtlMs > 0 {
+				pipe.Do(c.ctx, redisCommandArgs(redisbuilders.ExpireArgs(key, kvQuery.TtlMs))...)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < redisUpsertAttempts; attempt++ {
+		err := c.redisDB.Watch(c.ctx, upsert, watched...)
+		if err == redis.TxFailedErr {
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("hset error: %w", err)
+			return nil, fmt.Errorf("upsert error: %w", err)
 		}
+		return redisUpsertResult(key, inserted), nil
 	}
+	return nil, fmt.Errorf("upsert error: record changed concurrently %d times", redisUpsertAttempts)
+}
 
+// redisUpsertResult reports the key an UPSERT wrote and whether it inserted it
+func redisUpsertResult(key string, inserted bool) []map[string]any {
 	return []map[string]any{{
+		"key":           key,
+		"inserted":      inserted,
 		"rows_affected": 1,
+	}}
+}
+
+// redisBulkInsert writes the rows of a BULK INSERT through pipelines of
+// BulkBatchSize rows (redisbuilders.Options), one round trip each. With
+// secondary indexes the batch's current fields are read in one pipeline first,
+// and the rows and their index changes are written together in one MULTI/EXEC.
+func (c *Client) redisBulkInsert(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
+	pairs := kvQuery.BulkPairs
+	batchSize := c.options.Redis.BatchSize(len(pairs))
+
+	for start := 0; start < len(pairs); start += batchSize {
+		batch := pairs[start:min(start+batchSize, len(pairs))]
+		hashes := make([]map[string]string, len(batch))
+		for i, pair := range batch {
+			hash, err := redisbuilders.BulkHash(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			hashes[i] = hash
+		}
+
+		var err error
+		if c.options.Redis.SecondaryIndexes {
+			err = c.redisIndexedBulkWrite(kvQuery.Entity, batch, hashes)
+		} else {
+			_, err = c.redisDB.Pipelined(c.ctx, func(pipe redis.Pipeliner) error {
+				for i, pair := range batch {
+					pipe.HSet(c.ctx, pair.Key, hashes[i])
+				}
+				return nil
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bulk insert error (rows %d-%d of %d): %w", start+1, start+len(batch), len(pairs), err)
+		}
+	}
+
+	return []map[string]any{{
+		"rows_affected": len(pairs),
+	}}, nil
+}
+
+// redisIndexedBulkWrite writes a batch of rows and moves their index entries
+func (c *Client) redisIndexedBulkWrite(entity string, batch []*pb.KeyValuePair, hashes []map[string]string) error {
+	reads := make([]*redis.MapStringStringCmd, len(batch))
+	if _, err := c.redisDB.Pipelined(c.ctx, func(pipe redis.Pipeliner) error {
+		for i, pair := range batch {
+			reads[i] = pipe.HGetAll(c.ctx, pair.Key)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	prefix := redisbuilders.IndexPrefix(c.tenantID, entity, c.options.Redis)
+	keys := []string{redisbuilders.AllKey(prefix)}
+	for _, pair := range batch {
+		keys = append(keys, pair.Key)
+	}
+	if err := c.redisCheckSlots(keys...); err != nil {
+		return err
+	}
+	_, err := c.redisDB.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+		for i, pair := range batch {
+			old := reads[i].Val()
+			if len(old) == 0 {
+				old = nil
+			}
+			updated := make(map[string]string, len(old)+len(hashes[i]))
+			for field, value := range old {
+				updated[field] = value
+			}
+			for field, value := range hashes[i] {
+				updated[field] = value
+			}
+			pipe.HSet(c.ctx, pair.Key, hashes[i])
+			for _, command := range redisbuilders.IndexChanges(prefix, pair.Key, old, updated) {
+				pipe.Do(c.ctx, command...)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (c *Client) redisUpdate(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
+	args := kvQuery.Args
+
+	keys := []string{kvQuery.Key}
+	if strings.Contains(kvQuery.Key, "*") {
+		var err error
+		if keys, err = c.redisMatchingKeys(kvQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range keys {
+		if c.options.Redis.SecondaryIndexes {
+			if err := c.redisIndexedWrite(kvQuery.Entity, key, args, 0); err != nil {
+				return nil, fmt.Errorf("hset error: %w", err)
+			}
+			continue
+		}
+		// Convert args to field-value pairs
+		for i := 0; i < len(args)-1; i += 2 {
+			err := c.redisDB.HSet(c.ctx, key, args[i], args[i+1]).Err()
+			if err != nil {
+				return nil, fmt.Errorf("hset error: %w", err)
+			}
+		}
+	}
+
+	return []map[string]any{{
+		"rows_affected": len(keys),
 	}}, nil
 }
 
@@ -438,23 +1972,33 @@ func (c *Client) redisDelete(kvQuery *pb.KeyValueQuery) ([]map[string]any, error
 	key := kvQuery.Key
 
 	// Handle pattern delete
+	keys := []string{key}
 	if strings.Contains(key, "*") {
-		keys, err := c.redisDB.Keys(c.ctx, key).Result()
-		if err != nil {
-			return nil, fmt.Errorf("keys error: %w", err)
+		var err error
+		if keys, err = c.redisMatchingKeys(kvQuery); err != nil {
+			return nil, err
 		}
 		if len(keys) == 0 {
 			return []map[string]any{{"rows_affected": 0}}, nil
 		}
-		deleted, err := c.redisDB.Del(c.ctx, keys...).Result()
-		if err != nil {
-			return nil, fmt.Errorf("del error: %w", err)
+	}
+
+	if c.options.Redis.SecondaryIndexes {
+		var deleted int64
+		for _, k := range keys {
+			n, err := c.redisIndexedDelete(kvQuery.Entity, k)
+			if err != nil {
+				return nil, fmt.Errorf("del error: %w", err)
+			}
+			deleted += n
 		}
 		return []map[string]any{{"rows_affected": deleted}}, nil
 	}
 
-	// Single key delete
-	deleted, err := c.redisDB.Del(c.ctx, key).Result()
+	if err := c.redisCheckSlots(keys...); err != nil {
+		return nil, err
+	}
+	deleted, err := c.redisDB.Del(c.ctx, keys...).Result()
 	if err != nil {
 		return nil, fmt.Errorf("del error: %w", err)
 	}
@@ -465,12 +2009,17 @@ func (c *Client) redisDelete(kvQuery *pb.KeyValueQuery) ([]map[string]any, error
 }
 
 func (c *Client) redisCount(kvQuery *pb.KeyValueQuery) ([]map[string]any, error) {
-	key := kvQuery.Key
 	conditions := kvQuery.Conditions
 
-	keys, err := c.redisDB.Keys(c.ctx, key).Result()
+	if totals, ok, err := c.redisServerAggregate(kvQuery, ""); err != nil {
+		return nil, err
+	} else if ok {
+		return []map[string]any{{"count": int(totals.Count)}}, nil
+	}
+
+	keys, err := c.redisCandidateKeys(kvQuery)
 	if err != nil {
-		return nil, fmt.Errorf("keys error: %w", err)
+		return nil, err
 	}
 
 	if len(conditions) == 0 {
@@ -484,7 +2033,7 @@ func (c *Client) redisCount(kvQuery *pb.KeyValueQuery) ([]map[string]any, error)
 		if err != nil || len(hash) == 0 {
 			continue
 		}
-		if redisbuilders.MatchesConditions(hash, conditions) {
+		if redisMatches(k, hash, conditions) {
 			count++
 		}
 	}
@@ -493,7 +2042,6 @@ func (c *Client) redisCount(kvQuery *pb.KeyValueQuery) ([]map[string]any, error)
 }
 
 func (c *Client) redisAggregate(kvQuery *pb.KeyValueQuery, operation string) ([]map[string]any, error) {
-	key := kvQuery.Key
 	conditions := kvQuery.Conditions
 	args := kvQuery.Args
 
@@ -503,9 +2051,15 @@ func (c *Client) redisAggregate(kvQuery *pb.KeyValueQuery, operation string) ([]
 		field = args[0]
 	}
 
-	keys, err := c.redisDB.Keys(c.ctx, key).Result()
+	if totals, ok, err := c.redisServerAggregate(kvQuery, field); err != nil {
+		return nil, err
+	} else if ok {
+		return []map[string]any{{strings.ToLower(operation): totals.Value(operation)}}, nil
+	}
+
+	keys, err := c.redisCandidateKeys(kvQuery)
 	if err != nil {
-		return nil, fmt.Errorf("keys error: %w", err)
+		return nil, err
 	}
 
 	var values []float64
@@ -514,7 +2068,7 @@ func (c *Client) redisAggregate(kvQuery *pb.KeyValueQuery, operation string) ([]
 		if err != nil || len(hash) == 0 {
 			continue
 		}
-		if len(conditions) > 0 && !redisbuilders.MatchesConditions(hash, conditions) {
+		if !redisMatches(k, hash, conditions) {
 			continue
 		}
 		if val, ok := hash[field]; ok {
@@ -558,6 +2112,275 @@ func (c *Client) redisAggregate(kvQuery *pb.KeyValueQuery, operation string) ([]
 	return []map[string]any{{strings.ToLower(operation): result}}, nil
 }
 
+// redisAggregateScript runs AggregateScript with EVALSHA, loading it with EVAL
+// the first time a server has not seen it
+var redisAggregateScript = redis.NewScript(redisbuilders.AggregateScript)
+
+// redisServerAggregate computes an aggregate on the server over the secondary
+// index sets that hold exactly the matching records; ok is false when the
+// indexes are off or the WHERE clause needs the records checked one by one
+func (c *Client) redisServerAggregate(kvQuery *pb.KeyValueQuery, field string) (redisbuilders.ScriptAggregate, bool, error) {
+	if !c.options.Redis.SecondaryIndexes {
+		return redisbuilders.ScriptAggregate{}, false, nil
+	}
+	sets, ok := redisbuilders.PlanAggregateSets(c.tenantID, kvQuery.Entity, kvQuery.Conditions, c.options.Redis)
+	if !ok {
+		return redisbuilders.ScriptAggregate{}, false, nil
+	}
+	if err := c.redisCheckSlots(append(sets, redisbuilders.KeyPattern(c.tenantID, kvQuery.Entity, c.options.Redis))...); err != nil {
+		return redisbuilders.ScriptAggregate{}, false, err
+	}
+	reply, err := redisAggregateScript.Run(c.ctx, c.redisDB, sets, field).Result()
+	if err != nil {
+		return redisbuilders.ScriptAggregate{}, false, fmt.Errorf("aggregate script error: %w", err)
+	}
+	totals, err := redisbuilders.ParseAggregateReply(reply)
+	if err != nil {
+		return redisbuilders.ScriptAggregate{}, false, err
+	}
+	return totals, true, nil
+}
+
+// redisMatches checks a record against a WHERE clause; its id is the last
+// segment of its key, not a hash field
+func redisMatches(key string, hash map[string]string, conditions []*pb.QueryCondition) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+	if _, ok := hash["id"]; !ok {
+		fields := make(map[string]string, len(hash)+1)
+		for k, v := range hash {
+			fields[k] = v
+		}
+		fields["id"] = key[strings.LastIndex(key, ":")+1:]
+		hash = fields
+	}
+	return redisbuilders.MatchesConditions(hash, conditions)
+}
+
+// redisIndexedKeys reads the candidate keys of a WHERE clause from the
+// secondary indexes; ok is false when they are off or cannot answer it
+func (c *Client) redisIndexedKeys(kvQuery *pb.KeyValueQuery) ([]string, bool, error) {
+	if !c.options.Redis.SecondaryIndexes || len(kvQuery.Conditions) == 0 {
+		return nil, false, nil
+	}
+	lookup, ok := redisbuilders.PlanIndexLookup(c.tenantID, kvQuery.Entity, kvQuery.Conditions, c.options.Redis)
+	if !ok {
+		return nil, false, nil
+	}
+
+	// Ranges come back in score order, which the intersection keeps
+	var lists [][]string
+	for _, r := range lookup.Ranges {
+		keys, err := c.redisDB.ZRangeByScore(c.ctx, r.Key, &redis.ZRangeBy{Min: r.Min, Max: r.Max}).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("zrangebyscore error: %w", err)
+		}
+		lists = append(lists, keys)
+	}
+	if len(lookup.Keys) > 0 {
+		lists = append(lists, lookup.Keys)
+	}
+	if len(lookup.Sets) > 0 {
+		if err := c.redisCheckSlots(lookup.Sets...); err != nil {
+			return nil, false, err
+		}
+		keys, err := c.redisDB.SInter(c.ctx, lookup.Sets...).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("sinter error: %w", err)
+		}
+		lists = append(lists, keys)
+	}
+	for _, union := range lookup.Unions {
+		if err := c.redisCheckSlots(union...); err != nil {
+			return nil, false, err
+		}
+		keys, err := c.redisDB.SUnion(c.ctx, union...).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("sunion error: %w", err)
+		}
+		lists = append(lists, keys)
+	}
+	if len(lookup.Ranges) == 0 {
+		sort.Strings(lists[0])
+	}
+	return redisbuilders.IntersectKeys(lists), true, nil
+}
+
+// redisScanCount is the COUNT hint of each SCAN: how many keys the server
+// looks at per call, so no call blocks it for long
+const redisScanCount = 100
+
+// redisKeys returns an iterator over the keys a WHERE clause has to check:
+// the index candidates when the indexes can answer it, else the keys of the
+// pattern, read with SCAN a batch at a time. SCAN may return a key twice
+// while the keyspace is rehashed, so repeats are dropped.
+func (c *Client) redisKeys(kvQuery *pb.KeyValueQuery) (func() (string, bool, error), error) {
+	if keys, ok, err := c.redisIndexedKeys(kvQuery); err != nil {
+		return nil, err
+	} else if ok {
+		i := 0
+		return func() (string, bool, error) {
+			if i >= len(keys) {
+				return "", false, nil
+			}
+			i++
+			return keys[i-1], true, nil
+		}, nil
+	}
+
+	scanner, err := c.redisScanner(kvQuery.Key)
+	if err != nil {
+		return nil, err
+	}
+	iter := scanner.Scan(c.ctx, 0, kvQuery.Key, redisScanCount).Iterator()
+	seen := map[string]bool{}
+	return func() (string, bool, error) {
+		for iter.Next(c.ctx) {
+			key := iter.Val()
+			if !seen[key] {
+				seen[key] = true
+				return key, true, nil
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return "", false, fmt.Errorf("scan error: %w", err)
+		}
+		return "", false, nil
+	}, nil
+}
+
+// redisScanner returns the connection that SCANs a pattern. A cluster node
+// only scans its own slots, so on Redis Cluster the pattern must carry a hash
+// tag and is scanned on the master that owns it.
+func (c *Client) redisScanner(pattern string) (redis.Cmdable, error) {
+	cluster, ok := c.redisDB.(*redis.ClusterClient)
+	if !ok {
+		return c.redisDB, nil
+	}
+	if !c.options.Redis.HashTags {
+		return nil, fmt.Errorf("cannot scan %s on Redis Cluster: set HashTags in the Redis options", pattern)
+	}
+	return cluster.MasterForKey(c.ctx, pattern)
+}
+
+// redisCheckSlots rejects keys that a single command, MULTI/EXEC or script
+// would use across Redis Cluster slots; on a single server any keys go
+func (c *Client) redisCheckSlots(keys ...string) error {
+	if _, ok := c.redisDB.(*redis.ClusterClient); !ok {
+		return nil
+	}
+	return redisbuilders.CheckSameSlot(keys...)
+}
+
+// redisCandidateKeys returns every key redisKeys iterates over
+func (c *Client) redisCandidateKeys(kvQuery *pb.KeyValueQuery) ([]string, error) {
+	nextKey, err := c.redisKeys(kvQuery)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for {
+		key, ok, err := nextKey()
+		if err != nil || !ok {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+}
+
+// redisMatchingKeys returns the keys of the records matching a WHERE clause
+func (c *Client) redisMatchingKeys(kvQuery *pb.KeyValueQuery) ([]string, error) {
+	keys, err := c.redisCandidateKeys(kvQuery)
+	if err != nil {
+		return nil, err
+	}
+	var matching []string
+	for _, k := range keys {
+		hash, err := c.redisDB.HGetAll(c.ctx, k).Result()
+		if err != nil || len(hash) == 0 {
+			continue
+		}
+		if redisMatches(k, hash, kvQuery.Conditions) {
+			matching = append(matching, k)
+		}
+	}
+	return matching, nil
+}
+
+// redisIndexedWrite sets fields of a record, moves its index entries and
+// applies a TTL in one MULTI/EXEC
+func (c *Client) redisIndexedWrite(entity, key string, args []string, ttlMs int64) error {
+	old, err := c.redisDB.HGetAll(c.ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if len(old) == 0 {
+		old = nil
+	}
+	updated := make(map[string]string, len(old)+len(args)/2)
+	for field, value := range old {
+		updated[field] = value
+	}
+	fieldValues := make([]interface{}, 0, len(args))
+	for i := 0; i < len(args)-1; i += 2 {
+		updated[args[i]] = args[i+1]
+		fieldValues = append(fieldValues, args[i], args[i+1])
+	}
+
+	prefix := redisbuilders.IndexPrefix(c.tenantID, entity, c.options.Redis)
+	if err := c.redisCheckSlots(key, redisbuilders.AllKey(prefix)); err != nil {
+		return err
+	}
+	_, err = c.redisDB.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(c.ctx, key, fieldValues...)
+		for _, command := range redisbuilders.IndexChanges(prefix, key, old, updated) {
+			pipe.Do(c.ctx, command...)
+		}
+		if ttlMs > 0 {
+			pipe.Do(c.ctx, redisCommandArgs(redisbuilders.ExpireArgs(key, ttlMs))...)
+		}
+		return nil
+	})
+	return err
+}
+
+// redisCommandArgs converts a built command to the arguments of Do
+func redisCommandArgs(command []string) []interface{} {
+	args := make([]interface{}, len(command))
+	for i, arg := range command {
+		args[i] = arg
+	}
+	return args
+}
+
+// redisIndexedDelete deletes a record and its index entries in one MULTI/EXEC
+func (c *Client) redisIndexedDelete(entity, key string) (int64, error) {
+	old, err := c.redisDB.HGetAll(c.ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(old) == 0 {
+		return 0, nil
+	}
+	prefix := redisbuilders.IndexPrefix(c.tenantID, entity, c.options.Redis)
+	if err := c.redisCheckSlots(key, redisbuilders.AllKey(prefix)); err != nil {
+		return 0, err
+	}
+	var deleted *redis.IntCmd
+	_, err = c.redisDB.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(c.ctx, key)
+		for _, command := range redisbuilders.IndexChanges(prefix, key, old, nil) {
+			pipe.Do(c.ctx, command...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted.Val(), nil
+}
+
 func (c *Client) redisMulti() ([]map[string]any, error) {
 	return []map[string]any{{"status": "transaction_started"}}, nil
 }
@@ -583,31 +2406,40 @@ func rowsToMaps(rows *sql.Rows) ([]map[string]any, error) {
 	var results []map[string]any
 
 	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
+		row, err := scanRow(rows, columns)
+		if err != nil {
 			return nil, err
 		}
-
-		row := make(map[string]any)
-		for i, col := range columns {
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				row[col] = string(b)
-			} else {
-				row[col] = val
-			}
-		}
 		results = append(results, row)
 	}
 
 	return results, rows.Err()
 }
 
+// scanRow reads the current row of rows into a map keyed by column
+func scanRow(rows *sql.Rows, columns []string) (map[string]any, error) {
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]any)
+	for i, col := range columns {
+		val := values[i]
+		if b, ok := val.([]byte); ok {
+			row[col] = string(b)
+		} else {
+			row[col] = val
+		}
+	}
+	return row, nil
+}
+
 func stringMapToAnyMap(m map[string]string) map[string]any {
 	result := make(map[string]any, len(m))
 	for k, v := range m {
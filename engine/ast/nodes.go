@@ -18,19 +18,37 @@ type QueryNode struct {
 	OrderBy     []OrderByNode
 	Limit       *int
 	Offset      *int
+	Page        *int             // PAGE n (desugared to LIMIT/OFFSET)
+	PageSize    *int             // SIZE n
+	After       []*ExpressionNode  // AFTER cursor values, one per ORDER BY field
 	Distinct    bool
+	Lock        string           // Keyword: UPDATE, SHARE (FOR UPDATE / FOR SHARE)
+	LockWait    string           // Keyword: NOWAIT, SKIP LOCKED
+	Collation   string           // COLLATE locale
+	CollationStrength int        // STRENGTH 1-5 (0 = locale default)
+	AsOf        string           // AS OF time: "-10s" or a timestamp
+	Before      string           // BEFORE time: "-10s", a timestamp or a query ID
+	Final       bool             // FINAL after the entity
+	Sample      string           // SAMPLE k after the entity: 0.1 or 10000
 	Columns     []*ExpressionNode  // 100% TrueAST
 	SelectColumns []SelectColumnNode
 	
 	// CRUD extensions
 	Upsert      *UpsertNode
 	BulkData    [][]FieldNode
+	Returning   []*ExpressionNode  // RETURNING columns (100% TrueAST)
+	TTL         int64            // TTL duration in milliseconds (CREATE / UPSERT)
+	IfNotExists bool             // CREATE ... IF NOT EXISTS: write only a new row
 	
 	// DDL
 	AlterAction  string         // ADD_COLUMN, DROP_COLUMN, RENAME_COLUMN, MODIFY_COLUMN
 	DatabaseName string         // Name identifier
+	DatabaseFile string         // CREATE DATABASE name FILE "path" (SQLite ATTACH)
 	ViewName     string         // Name identifier
 	ViewQuery    *QueryNode     // 100% TrueAST - parsed subquery
+	MainQuery    *QueryNode     // CTE: query that reads from the CTE (100% TrueAST)
+	Recursive    bool           // CTE: WITH RECURSIVE
+	MaxDepth     int            // CTE: DEPTH n, levels below the anchor (MongoDB $graphLookup)
 	NewName      string         // Name identifier
 
 	// PostgreSQL DDL
@@ -85,10 +103,29 @@ type QueryNode struct {
 	CommentText   string
 
 	Cascade bool
+
+	// Partitioning (PostgreSQL; BigQuery partitions by a time unit)
+	PartitionStrategy  string            // RANGE, LIST, HASH; DAY, HOUR, MONTH, YEAR
+	PartitionKeys      []*ExpressionNode // 100% TrueAST
+	PartitionName      string            // CREATE PARTITION name OF Entity
+	PartitionFrom      []*ExpressionNode // FOR VALUES FROM (...) TO (...)
+	PartitionTo        []*ExpressionNode
+	PartitionIn        []*ExpressionNode // FOR VALUES IN (...)
+	PartitionModulus   int64             // FOR VALUES WITH (MODULUS m, REMAINDER r)
+	PartitionRemainder int64
+	PartitionDefault   bool
+
+	// Clustering columns (BigQuery CLUSTER BY)
+	ClusterKeys []*ExpressionNode // 100% TrueAST
+
+	// Dialect table options (MySQL: ENGINE, CHARSET, COLLATE, AUTO_INCREMENT; SQLite: STRICT, WITHOUT_ROWID;
+	// BigQuery: PARTITION_EXPIRATION_DAYS, REQUIRE_PARTITION_FILTER)
+	TableOptions map[string]string
 	
 	// DQL
 	Joins           []JoinNode
 	Aggregate       *AggregateNode
+	Facets          []FacetNode        // FACET: more aggregates over the same rows
 	GroupBy         []*ExpressionNode  // 100% TrueAST
 	Having          []ConditionNode
 	WindowFunctions []WindowNode
@@ -99,6 +136,10 @@ type QueryNode struct {
 	
 	// DCL
 	Permission *PermissionNode
+
+	// PUBSUB
+	Channel string // LISTEN/UNLISTEN/NOTIFY channel (* = all for UNLISTEN)
+	Payload string // NOTIFY payload
 }
 
 func (n *QueryNode) node() {}
@@ -130,10 +171,11 @@ func (n *ConditionNode) Pos() int { return n.Position }
 
 // FieldNode represents a field assignment (100% TrueAST)
 type FieldNode struct {
-	NameExpr    *ExpressionNode  // 100% TrueAST - field name
-	ValueExpr   *ExpressionNode  // 100% TrueAST - field value
-	Constraints []string         // DDL keywords: UNIQUE, NOT_NULL, PRIMARY_KEY
-	Position    int
+	NameExpr      *ExpressionNode // 100% TrueAST - field name
+	ValueExpr     *ExpressionNode // 100% TrueAST - field value
+	Constraints   []string        // DDL keywords: UNIQUE, NOT_NULL, PRIMARY_KEY
+	GeneratedExpr *ExpressionNode // DDL: GENERATED AS (expr) - computed column
+	Position      int
 }
 
 func (n *FieldNode) node() {}
@@ -141,7 +183,7 @@ func (n *FieldNode) Pos() int { return n.Position }
 
 // ExpressionNode represents expressions (100% TrueAST - recursive)
 type ExpressionNode struct {
-	Type     string  // BINARY, FUNCTION, CASEWHEN, FIELD, LITERAL, WINDOW
+	Type     string  // BINARY, FUNCTION, CASEWHEN, FIELD, LITERAL, WINDOW, JSON_PATH
 	Position int
 	
 	// For leaf nodes (FIELD, LITERAL)
@@ -204,23 +246,40 @@ func (n *JoinNode) Pos() int { return n.Position }
 
 // AggregateNode represents aggregate functions (100% TrueAST)
 type AggregateNode struct {
-	Function  string           // Keyword: COUNT, SUM, AVG, MIN, MAX
+	Function  string           // Keyword: COUNT, SUM, AVG, MIN, MAX, STRING AGG
 	FieldExpr *ExpressionNode  // 100% TrueAST
+	Separator string           // STRING AGG only
+	OrderBy   []OrderByNode    // STRING AGG only: order inside the aggregate
 	Position  int
 }
 
 func (n *AggregateNode) node() {}
 func (n *AggregateNode) Pos() int { return n.Position }
 
+// FacetNode represents one named aggregate in a FACET clause
+type FacetNode struct {
+	Name      string             // Facet name identifier
+	Aggregate *AggregateNode     // 100% TrueAST
+	GroupBy   []*ExpressionNode  // 100% TrueAST
+	Position  int
+}
+
+func (n *FacetNode) node() {}
+func (n *FacetNode) Pos() int { return n.Position }
+
 // WindowNode represents window functions (100% TrueAST)
 type WindowNode struct {
-	Function    string              // Keyword: ROW NUMBER, RANK, DENSE RANK, LAG, LEAD, NTILE
+	Function    string              // Keyword: ROW NUMBER, RANK, DENSE RANK, LAG, LEAD, NTILE, COUNT, SUM, AVG, MIN, MAX
 	FieldExpr   *ExpressionNode     // 100% TrueAST
 	Alias       string              // Alias identifier
 	PartitionBy []*ExpressionNode   // 100% TrueAST
 	OrderBy     []OrderByNode
 	Offset      int
 	Buckets     int
+	Default     *ExpressionNode     // LAG/LEAD: value when no row is at the offset
+	FrameUnit   string              // ROWS, RANGE (empty = database default frame)
+	FrameStart  string              // UNBOUNDED PRECEDING, n PRECEDING, CURRENT ROW, n FOLLOWING
+	FrameEnd    string              // same bounds as FrameStart, up to UNBOUNDED FOLLOWING
 	Position    int
 }
 
@@ -229,9 +288,12 @@ func (n *WindowNode) Pos() int { return n.Position }
 
 // UpsertNode represents UPSERT operation (100% TrueAST)
 type UpsertNode struct {
-	ConflictFields []*ExpressionNode  // 100% TrueAST
-	UpdateFields   []FieldNode
-	Position       int
+	ConflictFields     []*ExpressionNode  // 100% TrueAST
+	ConflictConstraint string             // ON CONSTRAINT name
+	ConflictWhere      []ConditionNode    // Partial unique index predicate
+	UpdateFields       []FieldNode        // No ValueExpr = take the inserted value
+	DoNothing          bool               // DO NOTHING: skip a conflicting row
+	Position           int
 }
 
 func (n *UpsertNode) node() {}
@@ -254,22 +316,39 @@ type TransactionNode struct {
 	SavepointName  string  // Name identifier
 	IsolationLevel string  // Keyword: SERIALIZABLE, REPEATABLE READ, etc.
 	ReadOnly       bool
+	Mode           string  // BEGIN mode: DEFERRED, IMMEDIATE, EXCLUSIVE (SQLite)
+	LockTables     []TableLockNode  // LOCK TABLES entries
 	Position       int
 }
 
 func (n *TransactionNode) node() {}
 func (n *TransactionNode) Pos() int { return n.Position }
 
+// TableLockNode represents one table in LOCK TABLES
+type TableLockNode struct {
+	Entity   string  // Entity name (pluralized by the translator)
+	Mode     string  // Keyword: READ, WRITE
+	Position int
+}
+
+func (n *TableLockNode) node() {}
+func (n *TableLockNode) Pos() int { return n.Position }
+
 // PermissionNode represents DCL operations
 type PermissionNode struct {
-	Operation   string    // Keyword: GRANT, REVOKE, CREATE USER
-	Permissions []string  // Keywords: READ, WRITE, DELETE
-	Target      string    // Name identifier
-	RoleName    string    // Name identifier
-	UserName    string    // Name identifier
-	Password    string    // Literal value
-	Roles       []string  // Name identifiers
-	Position    int
+	Operation       string    // Keyword: GRANT, REVOKE, CREATE USER
+	Permissions     []string  // Keywords: READ, WRITE, DELETE
+	ObjectType      string    // Keyword: DATABASE, SCHEMA, SEQUENCE, FUNCTION, ALL TABLES IN SCHEMA (empty = table)
+	Target          string    // Name identifier
+	RoleName        string    // Name identifier
+	UserName        string    // Name identifier
+	Password        string    // Literal value
+	Roles           []string  // Name identifiers
+	RoleFlags       []string  // Keywords: LOGIN, NOLOGIN, SUPERUSER, CREATEDB, ...
+	ValidUntil      string    // Literal value
+	ConnectionLimit string    // Literal value
+	Host            string    // Literal value (user@host)
+	Position        int
 }
 
 func (n *PermissionNode) node() {}
@@ -0,0 +1,118 @@
+package bigquery
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// ARRAY CONDITIONS (IN UNNEST / EXISTS over UNNEST)
+// ============================================================================
+
+// arrayElement names the element of an array read with UNNEST
+const arrayElement = "elem"
+
+// isFunctionExpr checks if expr is a call to the named function
+func isFunctionExpr(expr *pb.Expression, name string) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == name
+}
+
+// isLiteralExpr checks if expr is a plain value (parameterized, never interpolated)
+func isLiteralExpr(expr *pb.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	switch expr.Type {
+	case "STRING", "NUMBER", "BOOLEAN", "LITERAL":
+		return true
+	}
+	return false
+}
+
+// buildArrayLiteral builds: [?, ?, ...] (non-literal elements are inlined)
+func buildArrayLiteral(elements []*pb.Expression) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, e := range elements {
+		if isLiteralExpr(e) {
+			parts = append(parts, "?")
+			args = append(args, bindValue(e))
+		} else {
+			parts = append(parts, BuildExpressionSQL(e))
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]", args
+}
+
+// buildArrayOperandSQL renders the array side of a condition: ARRAY('a', 'b')
+// as [?, ?], anything else (a column, a function) as it is
+func buildArrayOperandSQL(expr *pb.Expression) (string, []interface{}) {
+	if isFunctionExpr(expr, "ARRAY") {
+		return buildArrayLiteral(expr.FunctionArgs)
+	}
+	return buildValueSQL(expr)
+}
+
+// existsInArray renders EXISTS(SELECT 1 FROM UNNEST(array) AS elem WHERE predicate)
+func existsInArray(array, predicate string) string {
+	return fmt.Sprintf("EXISTS(SELECT 1 FROM UNNEST(%s) AS %s WHERE %s)", array, arrayElement, predicate)
+}
+
+// buildContainmentCondition renders tags @> ARRAY('a', 'b') as "no element of
+// [?, ?] is missing from tags":
+// NOT EXISTS(SELECT 1 FROM UNNEST([?, ?]) AS elem WHERE elem NOT IN UNNEST(tags))
+// and <@ the other way round
+func buildContainmentCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	valueSQL, args := buildArrayOperandSQL(cond.ValueExpr)
+	outer, inner := valueSQL, field
+	if cond.Operator == "<@" {
+		outer, inner = field, valueSQL
+	}
+	return "NOT " + existsInArray(outer, fmt.Sprintf("%s NOT IN UNNEST(%s)", arrayElement, inner)), args
+}
+
+// buildQuantifiedCondition builds value op ANY(array) and value op ALL(array)
+// 'admin' = ANY(roles) is ? IN UNNEST(roles); other comparisons test each
+// element: age > ALL(1, 2) is
+// NOT EXISTS(SELECT 1 FROM UNNEST([?, ?]) AS elem WHERE NOT (? > elem)).
+func buildQuantifiedCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	var leftArgs []interface{}
+	left := BuildExpressionSQL(cond.FieldExpr)
+	if isLiteralExpr(cond.FieldExpr) {
+		left = "?"
+		leftArgs = append(leftArgs, bindValue(cond.FieldExpr))
+	}
+
+	elements := cond.ValueExpr.FunctionArgs
+	var array string
+	var arrayArgs []interface{}
+	if len(elements) == 1 && !isLiteralExpr(elements[0]) {
+		array = BuildExpressionSQL(elements[0])
+	} else {
+		array, arrayArgs = buildArrayLiteral(elements)
+	}
+
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ANY" && cond.Operator == "=" {
+		return fmt.Sprintf("%s IN UNNEST(%s)", left, array), append(leftArgs, arrayArgs...)
+	}
+	// The array is read before the comparison; keep the arguments in the same order
+	args := append(arrayArgs, leftArgs...)
+	comparison := fmt.Sprintf("%s %s %s", left, bigQueryOperator(cond.Operator), arrayElement)
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ALL" {
+		return "NOT " + existsInArray(array, "NOT ("+comparison+")"), args
+	}
+	return existsInArray(array, comparison), args
+}
+
+// buildUnnestCondition builds UNNEST(tags) LIKE 'a%' as
+// EXISTS(SELECT 1 FROM UNNEST(tags) AS elem WHERE elem LIKE ?)
+func buildUnnestCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	array := ""
+	if len(cond.FieldExpr.FunctionArgs) > 0 {
+		array = BuildExpressionSQL(cond.FieldExpr.FunctionArgs[0])
+	}
+	innerSQL, args := buildComparison(arrayElement, cond)
+	return existsInArray(array, innerSQL), args
+}
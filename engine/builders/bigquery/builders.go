@@ -0,0 +1,1365 @@
+package bigquery
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// BigQuery speaks GoogleSQL (Standard SQL). Tables live in datasets and are
+// named dataset.table (project.dataset.table across projects); an unqualified
+// table is read from the job's default dataset. Values are typed, so numbers
+// and booleans bind as Go numbers and bools. The builders write ? placeholders,
+// which NumberParams turns into the named parameters @p1, @p2, ...
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getJoinLeft(join *pb.JoinClause) string {
+	if join == nil || join.LeftExpr == nil {
+		return ""
+	}
+	return join.LeftExpr.Value
+}
+
+func getJoinRight(join *pb.JoinClause) string {
+	if join == nil || join.RightExpr == nil {
+		return ""
+	}
+	return join.RightExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// buildValueSQL renders a value position: computed expressions inline, value
+// keywords in their GoogleSQL spelling, and anything else as a typed ? bind
+// that NumberParams later turns into @pN
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" {
+		if keyword, ok := valueKeywords[strings.ToUpper(expr.Value)]; ok {
+			return keyword, nil
+		}
+	}
+	return "?", []interface{}{bindValue(expr)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches and DDL take no parameters)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return formatLiteral(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// paginate appends LIMIT n [OFFSET m]; OFFSET is part of LIMIT, so an offset
+// without a limit is LIMIT <max INT64> OFFSET m
+func paginate(sql string, limit, offset int32) string {
+	switch {
+	case limit > 0 && offset > 0:
+		return sql + fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	case limit > 0:
+		return sql + fmt.Sprintf(" LIMIT %d", limit)
+	case offset > 0:
+		return sql + fmt.Sprintf(" LIMIT 9223372036854775807 OFFSET %d", offset)
+	}
+	return sql
+}
+
+// ============================================================================
+// CRUD OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates parameterized SELECT query with expression support
+func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+
+	var args []interface{}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			var colSQL string
+			switch {
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "CASEWHEN":
+				caseSQL := "CASE"
+				for _, cond := range col.ExpressionObj.CaseConditions {
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", buildConditionSQL(cond.Condition), thenSQL)
+					args = append(args, thenArgs...)
+				}
+				if col.ExpressionObj.CaseElse != nil {
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
+				}
+				colSQL = caseSQL + " END"
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW":
+				colSQL = buildWindowExprSQL(col.ExpressionObj)
+			default:
+				colSQL = BuildExpressionSQL(col.ExpressionObj)
+			}
+			if col.Alias != "" {
+				colSQL += " AS " + QuoteIdentifier(col.Alias)
+			}
+			colParts = append(colParts, colSQL)
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args, nil
+}
+
+// buildConditionSQL renders a condition with its values inline (CASE WHEN)
+func buildConditionSQL(cond *pb.QueryCondition) string {
+	if cond == nil {
+		return ""
+	}
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	case "ILIKE", "NOT_ILIKE":
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, bigQueryOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+	}
+	return fmt.Sprintf("%s %s %s", field, bigQueryOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+}
+
+// buildWindowExprSQL renders a window function selected inline
+func buildWindowExprSQL(expr *pb.Expression) string {
+	funcName := strings.ReplaceAll(expr.FunctionName, " ", "_")
+
+	var funcCall string
+	switch funcName {
+	case "LAG", "LEAD":
+		field := "id"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				field = QuoteIdentifier(arg.Value)
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, field)
+	case "NTILE":
+		buckets := "4"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				buckets = arg.Value
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("NTILE(%s)", buckets)
+	default:
+		funcCall = fmt.Sprintf("%s()", funcName)
+	}
+
+	var partitionParts, orderParts []string
+	for _, arg := range expr.FunctionArgs {
+		if strings.HasPrefix(arg.Value, "PARTITION:") {
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
+		} else if strings.HasPrefix(arg.Value, "ORDER:") {
+			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
+			if len(parts) >= 2 {
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
+			} else if len(parts) == 1 {
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
+			}
+		}
+	}
+
+	var overParts []string
+	if len(partitionParts) > 0 {
+		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionParts, ", "))
+	}
+	if len(orderParts) > 0 {
+		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
+	}
+	return funcCall + " OVER (" + strings.Join(overParts, " ") + ")"
+}
+
+// BuildInsertSQL creates parameterized INSERT query
+func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query)
+	}
+
+	var fields, placeholders []string
+	var args []interface{}
+
+	for _, field := range query.Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	return sql, args, nil
+}
+
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + sourceSQL, nil, nil
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildUpdateSQL creates parameterized UPDATE query
+// BigQuery requires a WHERE clause, so an unconditional UPDATE is WHERE TRUE.
+func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var setParts []string
+	var args []interface{}
+
+	// JSON path assignments are grouped by column and rendered where the column first appears
+	jsonSets := map[string][]*pb.QueryField{}
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			jsonSets[column.Value] = append(jsonSets[column.Value], field)
+		}
+	}
+
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			group, pending := jsonSets[column.Value]
+			if !pending {
+				continue
+			}
+			delete(jsonSets, column.Value)
+			setSQL, setArgs := buildJSONSetSQL(group)
+			setParts = append(setParts, setSQL)
+			args = append(args, setArgs...)
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
+	whereClause, whereArgs := requiredWhereClause(query.Conditions)
+	return sql + whereClause, append(args, whereArgs...)
+}
+
+// BuildDeleteSQL creates parameterized DELETE query; an unconditional DELETE
+// is WHERE TRUE
+func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql := fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table))
+	whereClause, args := requiredWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// requiredWhereClause renders the WHERE clause UPDATE and DELETE require
+func requiredWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return " WHERE TRUE", nil
+	}
+	return BuildWhereClause(conditions)
+}
+
+// BuildBulkInsertSQL creates BULK INSERT as one multi-row INSERT
+// A query takes at most 10,000 parameters.
+func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+
+	var fields []string
+	for _, field := range query.BulkData[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+	}
+
+	var rows []string
+	var args []interface{}
+	for _, row := range query.BulkData {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+		}
+		rows = append(rows, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(rows, ", "))
+	return sql, args, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+func BuildWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " WHERE " + clause, args
+}
+
+func buildConditionsRecursive(conditions []*pb.QueryCondition) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		var clause string
+		var clauseArgs []interface{}
+
+		if len(cond.Nested) > 0 {
+			nestedClause, nestedArgs := buildConditionsRecursive(cond.Nested)
+			clause = "(" + nestedClause + ")"
+			clauseArgs = nestedArgs
+		} else {
+			clause, clauseArgs = buildSingleCondition(cond)
+		}
+
+		if i > 0 {
+			logic := cond.Logic
+			if logic == "" {
+				logic = "AND"
+			}
+			parts = append(parts, logic)
+		}
+		parts = append(parts, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	return strings.Join(parts, " "), args
+}
+
+func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	// Array conditions: UNNEST(tags) LIKE 'a%', 'admin' = ANY(roles)
+	if isFunctionExpr(cond.FieldExpr, "UNNEST") {
+		return buildUnnestCondition(cond)
+	}
+	if isFunctionExpr(cond.ValueExpr, "ANY") || isFunctionExpr(cond.ValueExpr, "ALL") {
+		return buildQuantifiedCondition(cond)
+	}
+	return buildComparison(BuildExpressionSQL(cond.FieldExpr), cond)
+}
+
+// buildComparison renders cond against field, already rendered as SQL
+func buildComparison(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	switch cond.Operator {
+	case "IS_NULL":
+		return fmt.Sprintf("%s IS NULL", field), nil
+	case "IS_NOT_NULL":
+		return fmt.Sprintf("%s IS NOT NULL", field), nil
+	case "IN":
+		return buildInClause(field, "IN", cond.ValuesExpr)
+	case "NOT_IN":
+		return buildInClause(field, "NOT IN", cond.ValuesExpr)
+	case "BETWEEN":
+		return buildBetweenClause(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "NOT_BETWEEN":
+		return buildBetweenClause(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "ILIKE", "NOT_ILIKE":
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, bigQueryOperator(cond.Operator), valueSQL), args
+	case "@>", "<@":
+		return buildContainmentCondition(field, cond)
+	case "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
+	default:
+		valueSQL, args := buildArrayOperandSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, bigQueryOperator(cond.Operator), valueSQL), args
+	}
+}
+
+// bigQueryOperator maps an OQL operator (NOT_LIKE, NOT_ILIKE) to its BigQuery spelling
+func bigQueryOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["BigQuery"][op]; ok {
+		return mapped
+	}
+	return op
+}
+
+// buildInClause renders IN / NOT IN; an empty list matches nothing (IN) or everything (NOT IN)
+func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}) {
+	if len(values) == 0 {
+		if operator == "IN" {
+			return "FALSE", nil
+		}
+		return "TRUE", nil
+	}
+
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args
+}
+
+func buildBetweenClause(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}) {
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args
+}
+
+// BuildExpressionSQL converts an Expression to SQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		// ARRAY(a, b) is the OQL spelling of an array literal
+		if strings.ToUpper(expr.FunctionName) == "ARRAY" {
+			return "[" + strings.Join(args, ", ") + "]"
+		}
+		return mapping.FunctionSQL("BigQuery", expr.FunctionName, args)
+	case "CASEWHEN":
+		caseParts := []string{"CASE"}
+		for _, cond := range expr.CaseConditions {
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", buildConditionSQL(cond.Condition), buildLiteralSQL(cond.ThenExpr)))
+		}
+		if expr.CaseElse != nil {
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
+		}
+		caseParts = append(caseParts, "END")
+		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		return quoteColumnRef(expr.Value)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
+	default:
+		return expr.Value
+	}
+}
+
+// bindValue converts a literal to the Go value bound to its parameter
+// BigQuery does not coerce a STRING parameter to INT64 or BOOL, so numbers bind
+// as int64 / float64 and booleans as bool; everything else binds as a string.
+func bindValue(expr *pb.Expression) interface{} {
+	if expr == nil {
+		return nil
+	}
+	switch expr.Type {
+	case "NUMBER":
+		if n, err := strconv.ParseInt(expr.Value, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(expr.Value, 64); err == nil {
+			return f
+		}
+	case "BOOLEAN":
+		return strings.EqualFold(expr.Value, "true")
+	}
+	switch strings.ToLower(expr.Value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return expr.Value
+}
+
+// formatLiteral converts a value to SQL literal format for VIEW definitions
+func formatLiteral(v interface{}) string {
+	if v == nil {
+		return "NULL"
+	}
+	s := fmt.Sprintf("%v", v)
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return "TRUE"
+	case "FALSE":
+		return "FALSE"
+	}
+	return QuoteString(s)
+}
+
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	var b strings.Builder
+	inString := false
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(sql):
+			b.WriteByte(c)
+			i++
+			c = sql[i]
+		case c == '\'':
+			inString = !inString
+		case !inString && c == '?' && next < len(args):
+			b.WriteString(formatLiteral(args[next]))
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) (string, error) {
+	viewSQL, args, err := BuildSelectSQL(query)
+	if err != nil {
+		return "", err
+	}
+	return inlinePlaceholders(viewSQL, args), nil
+}
+
+// ============================================================================
+// DDL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCreateTableSQL creates a table; the AUTO and PRIMARY_KEY columns make
+// up a PRIMARY KEY (...) NOT ENFORCED, which BigQuery records for the query
+// optimizer but does not check. A time-unit PARTITION BY, CLUSTER BY and the
+// partition options follow the column list: CREATE TABLE t (...) PARTITION BY
+// TIMESTAMP_TRUNC(created_at, MONTH) CLUSTER BY user_id OPTIONS(...).
+// CREATE TABLE name AS GET ... copies rows into a new table.
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.ViewQuery != nil {
+		sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("CREATE TABLE %s AS %s", QuoteIdentifier(query.Table), sourceSQL), nil
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	var columns, primaryKey []string
+	for _, field := range query.Fields {
+		column, err := TranslateColumn(getFieldName(field), getFieldValue(field), field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		columns = append(columns, column)
+		if isKeyColumn(getFieldValue(field), field.Constraints) {
+			primaryKey = append(primaryKey, QuoteIdentifier(getFieldName(field)))
+		}
+	}
+	if len(primaryKey) > 0 {
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s) NOT ENFORCED", strings.Join(primaryKey, ", ")))
+	}
+	sql := fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdentifier(query.Table), strings.Join(columns, ", "))
+
+	if query.PartitionStrategy != "" {
+		partition, err := buildPartitionClause(query, typeMap)
+		if err != nil {
+			return "", err
+		}
+		sql += " PARTITION BY " + partition
+	}
+	if len(query.ClusterKeys) > 0 {
+		if len(query.ClusterKeys) > 4 {
+			return "", fmt.Errorf("CLUSTER BY takes at most 4 columns, got %d", len(query.ClusterKeys))
+		}
+		sql += " CLUSTER BY " + buildExpressionList(query.ClusterKeys)
+	}
+	options, err := buildTableOptions(query.TableOptions)
+	if err != nil {
+		return "", err
+	}
+	return sql + options, nil
+}
+
+// isKeyColumn reports whether a column belongs to the primary key
+func isKeyColumn(columnType string, constraints []string) bool {
+	switch strings.ToUpper(columnType) {
+	case "AUTO", "BIGAUTO":
+		return true
+	}
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			return true
+		}
+	}
+	return false
+}
+
+// buildPartitionClause renders PARTITION BY DAY (created_at) for the type of
+// the partitioning column: DATE_TRUNC, DATETIME_TRUNC or TIMESTAMP_TRUNC to
+// the unit, or the DATE column itself for daily partitions
+func buildPartitionClause(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	unit := strings.ToUpper(query.PartitionStrategy)
+	if len(query.PartitionKeys) != 1 {
+		return "", fmt.Errorf("PARTITION BY %s takes exactly one column", unit)
+	}
+	key := query.PartitionKeys[0]
+	if key == nil || key.Type != "FIELD" {
+		return "", fmt.Errorf("PARTITION BY %s takes a column", unit)
+	}
+
+	columnType := ""
+	for _, field := range query.Fields {
+		if getFieldName(field) == key.Value {
+			columnType = strings.ToUpper(nativeColumnType(getFieldValue(field), typeMap))
+			break
+		}
+	}
+	column := BuildExpressionSQL(key)
+	switch columnType {
+	case "DATE":
+		if unit == "HOUR" {
+			return "", fmt.Errorf("PARTITION BY HOUR needs a DATETIME or TIMESTAMP column, %s is a DATE", key.Value)
+		}
+		if unit == "DAY" {
+			return column, nil
+		}
+		return fmt.Sprintf("DATE_TRUNC(%s, %s)", column, unit), nil
+	case "DATETIME", "TIMESTAMP":
+		return fmt.Sprintf("%s_TRUNC(%s, %s)", columnType, column, unit), nil
+	case "":
+		return "", fmt.Errorf("PARTITION BY %s column %s is not a column of the table", unit, key.Value)
+	default:
+		return "", fmt.Errorf("PARTITION BY %s needs a DATE, DATETIME or TIMESTAMP column, %s is %s", unit, key.Value, columnType)
+	}
+}
+
+// buildTableOptions renders OPTIONS(partition_expiration_days = 30,
+// require_partition_filter = TRUE) from the table options
+func buildTableOptions(options map[string]string) (string, error) {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		value := options[name]
+		switch name {
+		case "PARTITION_EXPIRATION_DAYS":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return "", fmt.Errorf("invalid PARTITION_EXPIRATION_DAYS value '%s': expected a number of days", value)
+			}
+		case "REQUIRE_PARTITION_FILTER":
+			switch strings.ToLower(value) {
+			case "true", "false":
+				value = strings.ToUpper(value)
+			default:
+				return "", fmt.Errorf("invalid REQUIRE_PARTITION_FILTER value '%s': expected true or false", value)
+			}
+		default:
+			return "", fmt.Errorf("BigQuery has no %s table option", name)
+		}
+		parts = append(parts, fmt.Sprintf("%s = %s", strings.ToLower(name), value))
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return " OPTIONS(" + strings.Join(parts, ", ") + ")", nil
+}
+
+// BuildAlterTableSQL alters one column
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+	table := QuoteIdentifier(query.Table)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		column, err := TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, column), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
+	case "MODIFY_COLUMN":
+		// Only widening changes are allowed: INT64 to NUMERIC, NUMERIC(10) to NUMERIC(12)
+		if columnValue == "" {
+			return "", fmt.Errorf("MODIFY_COLUMN requires column type")
+		}
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DATA TYPE %s", table, QuoteIdentifier(columnName), bigQueryColumnType(columnValue, typeMap)), nil
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+// BuildDropTableSQL drops a table; BigQuery tables have no dependents to CASCADE to
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Cascade {
+		return "", fmt.Errorf("BigQuery has no DROP TABLE ... CASCADE")
+	}
+	return "DROP TABLE " + QuoteIdentifier(query.Table), nil
+}
+
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
+}
+
+// BuildRenameTableSQL renames a table within its dataset: ALTER TABLE
+// shop.users RENAME TO customers (the new name is never qualified)
+func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.NewName == "" {
+		return "", fmt.Errorf("no new name specified for RENAME TABLE")
+	}
+	newName := query.NewName
+	if dot := strings.LastIndex(newName, "."); dot >= 0 {
+		dataset := ""
+		if tableDot := strings.LastIndex(query.Table, "."); tableDot >= 0 {
+			dataset = query.Table[:tableDot]
+		}
+		if newName[:dot] != dataset {
+			return "", fmt.Errorf("BigQuery cannot move table %s to another dataset", query.Table)
+		}
+		newName = newName[dot+1:]
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(newName)), nil
+}
+
+func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE VIEW", query)
+}
+
+// BuildAlterViewSQL replaces a view's query: CREATE OR REPLACE VIEW
+func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE OR REPLACE VIEW", query)
+}
+
+func buildViewSQL(statement string, query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no view query specified")
+	}
+	viewSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s AS %s", statement, QuoteIdentifier(query.ViewName), viewSQL), nil
+}
+
+func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	return fmt.Sprintf("DROP VIEW %s", QuoteIdentifier(query.ViewName)), nil
+}
+
+// BuildCreateSchemaSQL creates a dataset; CREATE DATABASE and CREATE SCHEMA
+// both create one
+func BuildCreateSchemaSQL(query *pb.RelationalQuery) (string, error) {
+	name := datasetName(query)
+	if name == "" {
+		return "", fmt.Errorf("no dataset name specified for CREATE SCHEMA")
+	}
+	return fmt.Sprintf("CREATE SCHEMA %s", QuoteIdentifier(name)), nil
+}
+
+// BuildDropSchemaSQL drops a dataset. DROP SCHEMA fails while the dataset
+// holds tables unless it is CASCADE; DROP DATABASE drops them with it, as it
+// does on the other databases.
+func BuildDropSchemaSQL(query *pb.RelationalQuery) (string, error) {
+	name := datasetName(query)
+	if name == "" {
+		return "", fmt.Errorf("no dataset name specified for DROP SCHEMA")
+	}
+	sql := fmt.Sprintf("DROP SCHEMA IF EXISTS %s", QuoteIdentifier(name))
+	if query.Cascade || query.SchemaName == "" {
+		sql += " CASCADE"
+	}
+	return sql, nil
+}
+
+// datasetName returns the dataset named by CREATE / DROP SCHEMA or DATABASE
+func datasetName(query *pb.RelationalQuery) string {
+	if query.SchemaName != "" {
+		return query.SchemaName
+	}
+	return query.DatabaseName
+}
+
+// TranslateColumn renders a column definition from the BigQuery type map
+// A size is kept where the type takes one (STRING(100), NUMERIC(10,2)).
+// BigQuery enforces NOT NULL but not UNIQUE, and has no generated columns.
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) (string, error) {
+	notNull := false
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			return "", fmt.Errorf("BigQuery cannot enforce UNIQUE on column %s", columnName)
+		case "NOT_NULL", "NOTNULL":
+			notNull = true
+		}
+	}
+	if generated != nil {
+		return "", fmt.Errorf("BigQuery has no generated columns: compute %s in a view", columnName)
+	}
+
+	columnDef := fmt.Sprintf("%s %s", QuoteIdentifier(columnName), bigQueryColumnType(columnType, typeMap))
+	if notNull {
+		columnDef += " NOT NULL"
+	}
+	return columnDef, nil
+}
+
+// bigQueryColumnType maps a column type, arrays included: INT[] -> ARRAY<INT64>
+func bigQueryColumnType(oqlType string, typeMap map[string]map[string]string) string {
+	bigQueryType := nativeColumnType(mapping.ArrayElementType(oqlType), typeMap)
+	if mapping.IsArrayType(oqlType) {
+		bigQueryType = fmt.Sprintf(mapping.ArrayTypeMap["BigQuery"], bigQueryType)
+	}
+	return bigQueryType
+}
+
+// nativeColumnType maps the base of a column type: STRING(100) -> STRING(100),
+// DECIMAL(10,2) -> NUMERIC(10,2), TIMESTAMP(6) -> TIMESTAMP
+func nativeColumnType(oqlType string, typeMap map[string]map[string]string) string {
+	baseType := oqlType
+	params := ""
+	if idx := strings.Index(oqlType, "("); idx != -1 {
+		baseType = oqlType[:idx]
+		if endIdx := strings.LastIndex(oqlType, ")"); endIdx > idx {
+			params = oqlType[idx : endIdx+1]
+		}
+	}
+
+	native, exists := typeMap["BigQuery"][strings.ToUpper(baseType)]
+	if !exists {
+		return oqlType // A native type: INT64, BIGNUMERIC(40,2), STRUCT<...>
+	}
+	switch native {
+	case "STRING", "BYTES", "NUMERIC", "BIGNUMERIC":
+		return native + params
+	}
+	return native
+}
+
+// ============================================================================
+// DQL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectClause := "*"
+	if len(query.Columns) > 0 {
+		selectClause = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	for _, join := range query.Joins {
+		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		if joinType == "CROSS" {
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
+			continue
+		}
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
+		sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
+	}
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args
+}
+
+// buildStringAggSQL builds: STRING_AGG(CAST(field AS STRING), 'sep' ORDER BY ...)
+// The cast lets non-string columns be joined. With DISTINCT, BigQuery requires
+// ORDER BY to match the argument, so ordering by the field repeats the cast.
+func buildStringAggSQL(agg *pb.AggregateClause, distinct bool) string {
+	field := quoteColumnRef(getAggField(agg))
+	arg := fmt.Sprintf("CAST(%s AS STRING)", field)
+	if distinct {
+		arg = "DISTINCT " + arg
+	}
+
+	sql := fmt.Sprintf("STRING_AGG(%s, %s", arg, QuoteString(agg.Separator))
+	if len(agg.OrderBy) > 0 {
+		var orderParts []string
+		for _, ob := range agg.OrderBy {
+			orderField := BuildExpressionSQL(ob.FieldExpr)
+			if distinct && orderField == field {
+				orderField = fmt.Sprintf("CAST(%s AS STRING)", field)
+			}
+			orderParts = append(orderParts, fmt.Sprintf("%s %s", orderField, ob.Direction))
+		}
+		sql += " ORDER BY " + strings.Join(orderParts, ", ")
+	}
+	return sql + ")"
+}
+
+func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var selectClause string
+	var args []interface{}
+
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+
+		switch {
+		case aggField == "" || aggField == "*":
+			if query.Distinct {
+				selectClause = "COUNT(*)"
+			} else {
+				selectClause = fmt.Sprintf("%s(*)", aggFunc)
+			}
+		case aggFunc == "STRING AGG":
+			selectClause = buildStringAggSQL(query.Aggregate, query.Distinct)
+		case query.Distinct:
+			selectClause = fmt.Sprintf("%s(DISTINCT %s)", aggFunc, aggField)
+		default:
+			selectClause = fmt.Sprintf("%s(%s)", aggFunc, aggField)
+		}
+		if len(query.GroupBy) > 0 {
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
+		}
+	} else {
+		selectClause = "COUNT(*)"
+	}
+
+	// Paging an ungrouped aggregate pages the rows it reads
+	if (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0 {
+		innerSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
+		whereClause, whereArgs := BuildWhereClause(query.Conditions)
+		innerSQL += whereClause
+		args = append(args, whereArgs...)
+		if len(query.OrderBy) > 0 {
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		innerSQL = paginate(innerSQL, query.Limit, query.Offset)
+		return fmt.Sprintf("SELECT %s FROM (%s) AS subquery", selectClause, innerSQL), args, nil
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.Having) > 0 {
+		havingClause, havingArgs := BuildHavingClause(query.Having)
+		sql += havingClause
+		args = append(args, havingArgs...)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args, nil
+}
+
+// BuildWindowSQL creates window function queries
+func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectParts := []string{"*"}
+
+	for _, wf := range query.WindowFunctions {
+		windowFunc := strings.ReplaceAll(strings.ToUpper(wf.Function), " ", "_")
+
+		var funcSQL string
+		switch windowFunc {
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
+		case "NTILE":
+			buckets := wf.Buckets
+			if buckets <= 0 {
+				buckets = 4
+			}
+			funcSQL = fmt.Sprintf("NTILE(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
+		default:
+			funcSQL = fmt.Sprintf("%s()", windowFunc)
+		}
+
+		var overParts []string
+		if len(wf.PartitionBy) > 0 {
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
+		}
+		if len(wf.OrderBy) > 0 {
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		}
+		if wf.FrameUnit != "" {
+			overParts = append(overParts, fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd))
+		}
+
+		column := fmt.Sprintf("%s OVER (%s)", funcSQL, strings.Join(overParts, " "))
+		if wf.Alias != "" {
+			column += " AS " + QuoteIdentifier(wf.Alias)
+		}
+		selectParts = append(selectParts, column)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, buildLiteralSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM(amount), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// BuildSetOperationSQL joins two queries with a set operation
+// Each query is parenthesized and keeps its own ORDER BY / LIMIT; the outer
+// ORDER BY / LIMIT / OFFSET apply to the combined result.
+func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	setOp := query.SetOperation
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(setOp.LeftQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(setOp.RightQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql := fmt.Sprintf("(%s) %s (%s)", leftSQL, setOperator(setOp.OperationType), rightSQL)
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), append(leftArgs, rightArgs...), nil
+}
+
+// buildSetOperandSQL builds one query of a set operation, which may itself be
+// a set operation, a join or an aggregate
+func buildSetOperandSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	switch {
+	case query.SetOperation != nil:
+		return BuildSetOperationSQL(query)
+	case len(query.Joins) > 0:
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	case query.Aggregate != nil:
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// setOperator spells a set operation; BigQuery requires ALL or DISTINCT on
+// every one, so UNION, INTERSECT and EXCEPT are written DISTINCT
+func setOperator(operationType string) string {
+	switch strings.ToUpper(operationType) {
+	case "UNION_ALL", "UNION ALL":
+		return "UNION ALL"
+	case "INTERSECT":
+		return "INTERSECT DISTINCT"
+	case "EXCEPT":
+		return "EXCEPT DISTINCT"
+	default:
+		return "UNION DISTINCT"
+	}
+}
+
+func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+func BuildHavingClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " HAVING " + clause, args
+}
+
+// ============================================================================
+// CTE OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCTESQL creates WITH [RECURSIVE] name AS (...) SELECT ...
+// A recursive CTE joins its anchor and recursive member with UNION ALL.
+func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Cte == nil {
+		return "", nil, fmt.Errorf("no CTE specified")
+	}
+	cteSQL, params, err := buildCTEBodySQL(query.Cte.CteQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	with := "WITH"
+	if query.Cte.Recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", cteName)
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs, err = BuildSelectSQL(query.Cte.MainQuery)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("%s %s AS (%s) %s", with, cteName, cteSQL, mainSQL), params, nil
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+func buildCTEBodySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query == nil {
+		return "", nil, fmt.Errorf("no CTE query specified")
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		leftSQL, leftArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		rightSQL, rightArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL), append(leftArgs, rightArgs...), nil
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// ============================================================================
+// SUBQUERY OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSubquerySQL creates an IN subquery, or an EXISTS check selected as 1 / 0
+func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Subquery == nil || query.Subquery.Subquery == nil {
+		return "", nil, fmt.Errorf("no subquery specified")
+	}
+
+	subquerySQL, subArgs, err := BuildSelectSQL(query.Subquery.Subquery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.ToUpper(query.Subquery.SubqueryType) == "EXISTS" {
+		return fmt.Sprintf("SELECT EXISTS(%s)", subquerySQL), subArgs, nil
+	}
+	if query.Table == "" {
+		return "", nil, fmt.Errorf("IN subquery requires an outer table")
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
+	var args []interface{}
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := buildConditionsRecursive(query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
+	}
+	sql += fmt.Sprintf("%s IN (%s)", BuildExpressionSQL(query.Subquery.FieldExpr), subquerySQL)
+	return sql, append(args, subArgs...), nil
+}
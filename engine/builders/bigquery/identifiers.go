@@ -0,0 +1,57 @@
+package bigquery
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted, with their
+// BigQuery spelling
+var valueKeywords = map[string]string{
+	"CURRENT_TIMESTAMP": "CURRENT_TIMESTAMP()", "CURRENT_DATE": "CURRENT_DATE()",
+	"NULL": "NULL", "TRUE": "TRUE", "FALSE": "FALSE", "DEFAULT": "DEFAULT",
+}
+
+// QuoteIdentifier returns name backtick-quoted, with backslashes and
+// backticks escaped by a backslash: `users`. Qualified names
+// (dataset.table, project.dataset.table, table.column) are quoted part by
+// part, which BigQuery reads the same as one quoted path.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, "`") {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name) + "`"
+}
+
+// quoteColumnRef quotes a FIELD expression value, writing SQL value keywords
+// the BigQuery way (CURRENT_TIMESTAMP is CURRENT_TIMESTAMP())
+func quoteColumnRef(name string) string {
+	if keyword, ok := valueKeywords[strings.ToUpper(name)]; ok {
+		return keyword
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteString returns s as a single-quoted string literal; BigQuery reads
+// backslash escapes in strings, so backslashes are escaped too
+func QuoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
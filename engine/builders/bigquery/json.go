@@ -0,0 +1,111 @@
+package bigquery
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (JSON_VALUE / JSON_QUERY / JSON_SET)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and a JSONPath
+// data->'address'->>'city' = data, $.address.city; data->0 = data, $[0]
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []*pb.Expression
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]*pb.Expression{expr.Right}, keys...)
+		expr = expr.Left
+	}
+	path := "$"
+	for _, key := range keys {
+		path += jsonPathStep(key)
+	}
+	return expr, path
+}
+
+// jsonPathStep renders one path step: [n] for array indexes, .key or ."odd key" for members
+func jsonPathStep(key *pb.Expression) string {
+	if key == nil {
+		return ""
+	}
+	if key.Type == "NUMBER" {
+		return "[" + key.Value + "]"
+	}
+	if isJSONPathIdentifier(key.Value) {
+		return "." + key.Value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key.Value)
+	return `."` + escaped + `"`
+}
+
+// isJSONPathIdentifier reports whether key can appear unquoted in a JSON path
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			continue
+		}
+		if i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// buildJSONPathSQL renders ->> as JSON_VALUE (a scalar as STRING) and -> as
+// JSON_QUERY (the JSON value)
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, path := jsonPathTarget(expr)
+	function := "JSON_QUERY"
+	if expr.Operator == "->>" {
+		function = "JSON_VALUE"
+	}
+	return fmt.Sprintf("%s(%s, %s)", function, BuildExpressionSQL(column), QuoteString(path))
+}
+
+// buildJSONCondition renders the JSONB key operators ?, ?| and ?&: JSON_QUERY
+// is NULL only when the key is missing (a JSON null is the JSON value null)
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	if cond.Operator == "?" {
+		return fmt.Sprintf("JSON_QUERY(%s, %s) IS NOT NULL", field, QuoteString("$"+jsonPathStep(cond.ValueExpr))), nil
+	}
+	// ?| = any key, ?& = all keys
+	if len(cond.ValuesExpr) == 0 {
+		if cond.Operator == "?|" {
+			return "FALSE", nil
+		}
+		return "TRUE", nil
+	}
+	logic := " OR "
+	if cond.Operator == "?&" {
+		logic = " AND "
+	}
+	parts := make([]string, len(cond.ValuesExpr))
+	for i, key := range cond.ValuesExpr {
+		parts[i] = fmt.Sprintf("JSON_QUERY(%s, %s) IS NOT NULL", field, QuoteString("$"+jsonPathStep(key)))
+	}
+	return "(" + strings.Join(parts, logic) + ")", nil
+}
+
+// buildJSONSetSQL renders the JSON path assignments to one column as a single
+// col = JSON_SET(col, '$.a', v1, '$.b', v2); BigQuery rejects a column
+// assigned twice in one UPDATE. JSON_SET stores each value as its JSON type.
+func buildJSONSetSQL(fields []*pb.QueryField) (string, []interface{}) {
+	column, _ := jsonPathTarget(fields[0].NameExpr)
+	columnSQL := BuildExpressionSQL(column)
+	parts := []string{columnSQL}
+	var args []interface{}
+	for _, field := range fields {
+		_, path := jsonPathTarget(field.NameExpr)
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		parts = append(parts, QuoteString(path), valueSQL)
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("%s = JSON_SET(%s)", columnSQL, strings.Join(parts, ", ")), args
+}
@@ -0,0 +1,40 @@
+package bigquery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// NAMED PARAMETERS (@p1, @p2, ...)
+// ============================================================================
+
+// NumberParams turns the ? placeholders the builders write into named
+// parameters @p1, @p2, ... in argument order. A BigQuery query takes
+// positional or named parameters but not both; the Go client binds named
+// ones (bigquery.QueryParameter{Name: "p1"}), which also keeps a value
+// used twice in one place.
+func NumberParams(sql string) string {
+	n := 0
+	var b strings.Builder
+	var quote byte // ' inside a string literal, ` inside an identifier
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0 && c == '\\' && i+1 < len(sql):
+			b.WriteByte(c)
+			i++
+			c = sql[i]
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '`'):
+			quote = c
+		case quote == 0 && c == '?':
+			n++
+			b.WriteString("@p" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
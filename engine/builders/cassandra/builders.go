@@ -0,0 +1,920 @@
+package cassandra
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// Cassandra tables live in keyspaces: keyspace.table, or table in the
+// session's keyspace. Unquoted names fold to lower case. Rows are stored by
+// partition key, so every read and write names its partition; there are no
+// joins, no OR and no subqueries. The builders write ? placeholders, which
+// the driver binds in order.
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// isFunctionExpr checks if expr is a call to the named function
+func isFunctionExpr(expr *pb.Expression, name string) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == name
+}
+
+// isLiteralExpr checks if expr is a plain value (parameterized, never interpolated)
+func isLiteralExpr(expr *pb.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	switch expr.Type {
+	case "STRING", "NUMBER", "BOOLEAN", "LITERAL":
+		return true
+	}
+	return false
+}
+
+// checkExpression rejects what CQL cannot compute: CASE, JSON paths and
+// RANGE buckets
+func checkExpression(expr *pb.Expression) error {
+	if expr == nil {
+		return nil
+	}
+	switch {
+	case expr.Type == "CASEWHEN":
+		return mapping.NotSupported("Cassandra", "CASE", mapping.SupportsOperation)
+	case expr.Type == "JSON_PATH":
+		return fmt.Errorf("Cassandra has no JSON paths: %s is stored as text", BuildExpressionSQL(jsonPathColumn(expr)))
+	case expr.Type == "WINDOW":
+		return &mapping.ErrNotSupported{Database: "Cassandra", Feature: "OVER"}
+	case builderutil.IsRangeBucket(expr):
+		return fmt.Errorf("Cassandra cannot GROUP BY RANGE: group by key columns")
+	}
+	for _, sub := range append([]*pb.Expression{expr.Left, expr.Right}, expr.FunctionArgs...) {
+		if err := checkExpression(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonPathColumn returns the column a JSON path starts from
+func jsonPathColumn(expr *pb.Expression) *pb.Expression {
+	for expr.Type == "JSON_PATH" && expr.Left != nil {
+		expr = expr.Left
+	}
+	return expr
+}
+
+// buildValueSQL renders a value position: ARRAY(...) as a list literal of
+// parameters, other computed expressions inline, literals as ?
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if isFunctionExpr(expr, "ARRAY") {
+		return buildListLiteral(expr.FunctionArgs)
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" && valueKeywords[strings.ToUpper(expr.Value)] {
+		return strings.ToUpper(expr.Value), nil
+	}
+	return "?", []interface{}{bindValue(expr)}
+}
+
+// buildListLiteral builds: [?, ?, ...] (non-literal elements are inlined)
+func buildListLiteral(elements []*pb.Expression) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, e := range elements {
+		if isLiteralExpr(e) {
+			parts = append(parts, "?")
+			args = append(args, bindValue(e))
+		} else {
+			parts = append(parts, BuildExpressionSQL(e))
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]", args
+}
+
+// buildLiteralSQL renders a value inline (list elements inside an expression)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return strings.ToLower(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns and GROUP BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// usingTTL renders USING TTL n from a TTL in milliseconds; Cassandra counts
+// whole seconds
+func usingTTL(ttlMs int64) (string, error) {
+	if ttlMs <= 0 {
+		return "", nil
+	}
+	if ttlMs%1000 != 0 {
+		return "", fmt.Errorf("Cassandra TTL is a whole number of seconds, not %dms", ttlMs)
+	}
+	return fmt.Sprintf(" USING TTL %d", ttlMs/1000), nil
+}
+
+// ============================================================================
+// CRUD OPERATIONS - CQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates a parameterized SELECT; SELECT DISTINCT of the
+// partition key without a WHERE lists the partitions and is the only read
+// that may leave the partition key out
+func BuildSelectSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if !query.Distinct || len(query.Conditions) > 0 {
+		if err := checkPartitionKey(query.Table, query.Conditions, opts); err != nil {
+			return "", nil, err
+		}
+	}
+
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			if err := checkExpression(col.ExpressionObj); err != nil {
+				return "", nil, err
+			}
+			colSQL := BuildExpressionSQL(col.ExpressionObj)
+			if col.Alias != "" {
+				colSQL += " AS " + QuoteIdentifier(col.Alias)
+			}
+			colParts = append(colParts, colSQL)
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		for _, col := range query.Columns {
+			if err := checkExpression(col); err != nil {
+				return "", nil, err
+			}
+		}
+		columns = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
+	whereClause, args, err := BuildWhereClause(query.Conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += whereClause
+
+	if len(query.GroupBy) > 0 {
+		for _, expr := range query.GroupBy {
+			if err := checkExpression(expr); err != nil {
+				return "", nil, err
+			}
+		}
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	if query.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+	return sql, args, nil
+}
+
+// BuildInsertSQL creates a parameterized INSERT. IF NOT EXISTS makes it a
+// lightweight transaction that writes only when no row has the key, and
+// returns [applied]; USING TTL expires the written values.
+// UPSERT is the same INSERT: Cassandra writes a row over any row with the
+// same primary key, so the key decides what matches, not the ON columns.
+// UPSERT ... DO NOTHING is INSERT ... IF NOT EXISTS.
+func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.ViewQuery != nil {
+		return "", nil, fmt.Errorf("Cassandra cannot INSERT ... SELECT: read the rows and write them")
+	}
+	if len(query.Fields) == 0 {
+		return "", nil, fmt.Errorf("no values specified for INSERT")
+	}
+	if err := checkUpsert(query.Upsert); err != nil {
+		return "", nil, err
+	}
+	ttl, err := usingTTL(query.TtlMs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, args := buildInsertRowSQL(query.Table, query.Fields)
+	if query.IfNotExists || isDoNothing(query.Upsert) {
+		sql += " IF NOT EXISTS"
+	}
+	return sql + ttl, args, nil
+}
+
+// buildInsertRowSQL renders INSERT INTO t (a, b) VALUES (?, ?)
+func buildInsertRowSQL(table string, fields []*pb.QueryField) (string, []interface{}) {
+	var columns, values []string
+	var args []interface{}
+	for _, field := range fields {
+		columns = append(columns, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		values = append(values, valueSQL)
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", QuoteIdentifier(table), strings.Join(columns, ", "), strings.Join(values, ", ")), args
+}
+
+// isDoNothing reports whether upsert leaves an existing row as it is
+func isDoNothing(upsert *pb.UpsertClause) bool {
+	return upsert != nil && upsert.ConflictAction == "NOTHING"
+}
+
+// checkUpsert rejects the parts of an UPSERT an INSERT cannot honor: a
+// conflict target other than the primary key, and UPDATE SET values that
+// would differ from the inserted ones
+func checkUpsert(upsert *pb.UpsertClause) error {
+	if upsert == nil {
+		return nil
+	}
+	if upsert.ConflictConstraint != "" {
+		return fmt.Errorf("Cassandra cannot target constraint %s: rows conflict on their primary key", upsert.ConflictConstraint)
+	}
+	if len(upsert.ConflictWhere) > 0 {
+		return fmt.Errorf("Cassandra upserts have no conflict target predicate")
+	}
+	for _, field := range upsert.UpdateFields {
+		if field.ValueExpr != nil {
+			return fmt.Errorf("Cassandra UPSERT writes the inserted values: set %s with UPDATE instead", getFieldName(field))
+		}
+	}
+	return nil
+}
+
+// BuildUpdateSQL creates a parameterized UPDATE of the rows with the given key
+// A value is a literal, a function call, or the column itself plus or minus
+// a value: counters (visits = visits + 1) and collections
+// (tags = tags + ARRAY("new")) change in place.
+func BuildUpdateSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if len(query.Conditions) == 0 {
+		return "", nil, fmt.Errorf("UPDATE requires a WHERE on the primary key")
+	}
+	if err := checkPartitionKey(query.Table, query.Conditions, opts); err != nil {
+		return "", nil, err
+	}
+
+	var setParts []string
+	var args []interface{}
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			return "", nil, checkExpression(field.NameExpr)
+		}
+		setSQL, setArgs, err := buildSetSQL(field)
+		if err != nil {
+			return "", nil, err
+		}
+		setParts = append(setParts, setSQL)
+		args = append(args, setArgs...)
+	}
+
+	ttl, err := usingTTL(query.TtlMs)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := fmt.Sprintf("UPDATE %s%s SET %s", QuoteIdentifier(query.Table), ttl, strings.Join(setParts, ", "))
+	whereClause, whereArgs, err := BuildWhereClause(query.Conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql + whereClause, append(args, whereArgs...), nil
+}
+
+// buildSetSQL renders one assignment: col = ?, col = col + ?, col = ? + col
+func buildSetSQL(field *pb.QueryField) (string, []interface{}, error) {
+	name := getFieldName(field)
+	column := QuoteIdentifier(name)
+	value := field.ValueExpr
+	if err := checkExpression(value); err != nil {
+		return "", nil, err
+	}
+	if value == nil || value.Type != "BINARY" {
+		valueSQL, args := buildValueSQL(value)
+		return fmt.Sprintf("%s = %s", column, valueSQL), args, nil
+	}
+
+	isColumn := func(e *pb.Expression) bool {
+		return e != nil && e.Type == "FIELD" && strings.EqualFold(e.Value, name)
+	}
+	switch {
+	case (value.Operator == "+" || value.Operator == "-") && isColumn(value.Left):
+		valueSQL, args := buildValueSQL(value.Right)
+		return fmt.Sprintf("%s = %s %s %s", column, column, value.Operator, valueSQL), args, nil
+	case value.Operator == "+" && isColumn(value.Right):
+		// Prepend to a list
+		valueSQL, args := buildValueSQL(value.Left)
+		return fmt.Sprintf("%s = %s + %s", column, valueSQL, column), args, nil
+	}
+	return "", nil, fmt.Errorf("Cassandra cannot compute %s from other columns: only %s + value and %s - value change it in place", name, name, name)
+}
+
+// BuildDeleteSQL creates a parameterized DELETE of the rows with the given key
+func BuildDeleteSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if len(query.Conditions) == 0 {
+		return "", nil, fmt.Errorf("DELETE requires a WHERE on the primary key: use TRUNCATE TABLE to empty %s", query.Table)
+	}
+	if err := checkPartitionKey(query.Table, query.Conditions, opts); err != nil {
+		return "", nil, err
+	}
+	whereClause, args, err := BuildWhereClause(query.Conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	return "DELETE FROM " + QuoteIdentifier(query.Table) + whereClause, args, nil
+}
+
+// BuildBatchSQL creates BULK INSERT and BULK UPSERT as a logged batch of
+// INSERTs, which Cassandra applies all or none:
+// BEGIN BATCH INSERT ...; INSERT ...; APPLY BATCH
+// A batch over many partitions is slower than the same INSERTs sent one by
+// one; it buys atomicity, not speed. DO NOTHING makes every INSERT IF NOT
+// EXISTS, and Cassandra runs such a batch only within one partition.
+func BuildBatchSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+	if err := checkUpsert(query.Upsert); err != nil {
+		return "", nil, err
+	}
+	ttl, err := usingTTL(query.TtlMs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var statements []string
+	var args []interface{}
+	for _, row := range query.BulkData {
+		sql, rowArgs := buildInsertRowSQL(query.Table, row.Fields)
+		if isDoNothing(query.Upsert) {
+			sql += " IF NOT EXISTS"
+		}
+		statements = append(statements, sql+ttl)
+		args = append(args, rowArgs...)
+	}
+	return "BEGIN BATCH " + strings.Join(statements, "; ") + "; APPLY BATCH", args, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+// CQL takes only AND-ed relations and no parentheses, so groups are
+// flattened and OR is rejected.
+func BuildWhereClause(conditions []*pb.QueryCondition) (string, []interface{}, error) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}, nil
+	}
+	parts, args, err := buildConditionsRecursive(conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	return " WHERE " + strings.Join(parts, " AND "), args, nil
+}
+
+func buildConditionsRecursive(conditions []*pb.QueryCondition) ([]string, []interface{}, error) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		if i > 0 && cond.Logic != "" && strings.ToUpper(cond.Logic) != "AND" {
+			return nil, nil, mapping.NotSupported("Cassandra", strings.ToUpper(cond.Logic), mapping.SupportsOperator)
+		}
+		if len(cond.Nested) > 0 {
+			nestedParts, nestedArgs, err := buildConditionsRecursive(cond.Nested)
+			if err != nil {
+				return nil, nil, err
+			}
+			parts = append(parts, nestedParts...)
+			args = append(args, nestedArgs...)
+			continue
+		}
+		clause, clauseArgs, err := buildSingleCondition(cond)
+		if err != nil {
+			return nil, nil, err
+		}
+		parts = append(parts, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	return parts, args, nil
+}
+
+func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}, error) {
+	if isFunctionExpr(cond.FieldExpr, "UNNEST") {
+		return "", nil, fmt.Errorf("Cassandra cannot compare the elements of a collection: use CONTAINS (@> or = ANY)")
+	}
+	if err := checkExpression(cond.FieldExpr); err != nil {
+		return "", nil, err
+	}
+	if isFunctionExpr(cond.ValueExpr, "ANY") || isFunctionExpr(cond.ValueExpr, "ALL") {
+		return buildQuantifiedCondition(cond)
+	}
+	if _, ok := mapping.OperatorMap["Cassandra"][cond.Operator]; !ok {
+		return "", nil, mapping.NotSupported("Cassandra", cond.Operator, mapping.SupportsOperator)
+	}
+
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IN":
+		sql, args := buildInClause(field, cond.ValuesExpr)
+		return sql, args, nil
+	case "BETWEEN":
+		// No BETWEEN: a range on a clustering column is two bounds
+		low, args := buildValueSQL(cond.ValueExpr)
+		high, highArgs := buildValueSQL(cond.Value2Expr)
+		return fmt.Sprintf("%s >= %s AND %s <= %s", field, low, field, high), append(args, highArgs...), nil
+	case "@>":
+		return buildContainsCondition(field, cond.ValueExpr)
+	case "?":
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s CONTAINS KEY %s", field, valueSQL), args, nil
+	case "?&":
+		if len(cond.ValuesExpr) == 0 {
+			return "", nil, fmt.Errorf("?& requires at least one key")
+		}
+		var parts []string
+		var args []interface{}
+		for _, key := range cond.ValuesExpr {
+			valueSQL, valueArgs := buildValueSQL(key)
+			parts = append(parts, fmt.Sprintf("%s CONTAINS KEY %s", field, valueSQL))
+			args = append(args, valueArgs...)
+		}
+		return strings.Join(parts, " AND "), args, nil
+	default:
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, cond.Operator, valueSQL), args, nil
+	}
+}
+
+// buildInClause renders IN; an empty list is IN (), which matches nothing
+func buildInClause(field string, values []*pb.Expression) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("%s IN (%s)", field, strings.Join(placeholders, ", ")), args
+}
+
+// buildContainsCondition renders tags @> ARRAY('a', 'b') as one CONTAINS per
+// element: tags CONTAINS ? AND tags CONTAINS ?
+func buildContainsCondition(field string, value *pb.Expression) (string, []interface{}, error) {
+	elements := []*pb.Expression{value}
+	if isFunctionExpr(value, "ARRAY") {
+		elements = value.FunctionArgs
+	}
+	if len(elements) == 0 {
+		return "", nil, fmt.Errorf("@> requires at least one element")
+	}
+	var parts []string
+	var args []interface{}
+	for _, element := range elements {
+		if !isLiteralExpr(element) {
+			return "", nil, fmt.Errorf("Cassandra CONTAINS takes values, not %s", BuildExpressionSQL(element))
+		}
+		parts = append(parts, field+" CONTAINS ?")
+		args = append(args, bindValue(element))
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+// buildQuantifiedCondition builds the two forms of = ANY CQL has:
+// 'go' = ANY(tags) is tags CONTAINS ?, and status = ANY('a', 'b') is
+// status IN (?, ?)
+func buildQuantifiedCondition(cond *pb.QueryCondition) (string, []interface{}, error) {
+	elements := cond.ValueExpr.FunctionArgs
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ANY" && cond.Operator == "=" {
+		if isLiteralExpr(cond.FieldExpr) && len(elements) == 1 && elements[0].Type == "FIELD" {
+			return fmt.Sprintf("%s CONTAINS ?", BuildExpressionSQL(elements[0])), []interface{}{bindValue(cond.FieldExpr)}, nil
+		}
+		if cond.FieldExpr != nil && cond.FieldExpr.Type == "FIELD" {
+			sql, args := buildInClause(BuildExpressionSQL(cond.FieldExpr), elements)
+			return sql, args, nil
+		}
+	}
+	return "", nil, fmt.Errorf("Cassandra has %s %s %s(...) only as value = ANY(list column) or column = ANY(values)",
+		BuildExpressionSQL(cond.FieldExpr), cond.Operator, strings.ToUpper(cond.ValueExpr.FunctionName))
+}
+
+// BuildExpressionSQL converts an Expression to CQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "FUNCTION":
+		// ARRAY(a, b) is the OQL spelling of a list literal
+		if strings.ToUpper(expr.FunctionName) == "ARRAY" {
+			elements := make([]string, len(expr.FunctionArgs))
+			for i, arg := range expr.FunctionArgs {
+				elements[i] = buildLiteralSQL(arg)
+			}
+			return "[" + strings.Join(elements, ", ") + "]"
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		return mapping.FunctionSQL("Cassandra", expr.FunctionName, args)
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		return quoteColumnRef(expr.Value)
+	default:
+		return expr.Value
+	}
+}
+
+// bindValue converts a literal to the Go value bound to its parameter
+// Numbers bind as int64 / float64 and booleans as bool; the driver converts
+// them to the column's type. Everything else binds as a string.
+func bindValue(expr *pb.Expression) interface{} {
+	if expr == nil {
+		return nil
+	}
+	switch expr.Type {
+	case "NUMBER":
+		if n, err := strconv.ParseInt(expr.Value, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(expr.Value, 64); err == nil {
+			return f
+		}
+	case "BOOLEAN":
+		return strings.EqualFold(expr.Value, "true")
+	}
+	switch strings.ToLower(expr.Value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return expr.Value
+}
+
+// ============================================================================
+// DDL OPERATIONS - CQL BUILDERS
+// ============================================================================
+
+// BuildCreateTableSQL creates a table. The PRIMARY_KEY columns are the
+// partition key and the CLUSTER BY columns the clustering columns, which
+// order the rows within a partition:
+// CREATE TABLE events (..., PRIMARY KEY ((user_id), created_at))
+// DEFAULT_TIME_TO_LIVE sets the TTL, in seconds, of rows written without one.
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.ViewQuery != nil {
+		return "", fmt.Errorf("Cassandra cannot CREATE TABLE ... AS: create the table and write the rows")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	var columns, partitionKey []string
+	for _, field := range query.Fields {
+		column, err := TranslateColumn(getFieldName(field), getFieldValue(field), field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		columns = append(columns, column)
+		if isKeyColumn(field.Constraints) {
+			partitionKey = append(partitionKey, QuoteIdentifier(getFieldName(field)))
+		}
+	}
+	if len(partitionKey) == 0 {
+		return "", fmt.Errorf("Cassandra tables need a partition key: mark its columns PRIMARY_KEY")
+	}
+
+	var clustering []string
+	for _, key := range query.ClusterKeys {
+		if key == nil || key.Type != "FIELD" {
+			return "", fmt.Errorf("CLUSTER BY takes columns, not %s", BuildExpressionSQL(key))
+		}
+		clustering = append(clustering, QuoteIdentifier(key.Value))
+	}
+
+	key := partitionKey[0]
+	if len(partitionKey) > 1 {
+		key = "(" + strings.Join(partitionKey, ", ") + ")"
+	}
+	columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(append([]string{key}, clustering...), ", ")))
+	sql := fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdentifier(query.Table), strings.Join(columns, ", "))
+
+	options, err := buildTableOptions(query.TableOptions)
+	if err != nil {
+		return "", err
+	}
+	return sql + options, nil
+}
+
+// isKeyColumn reports whether a column belongs to the partition key
+func isKeyColumn(constraints []string) bool {
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			return true
+		}
+	}
+	return false
+}
+
+// buildTableOptions renders WITH default_time_to_live = 86400 from the table options
+func buildTableOptions(options map[string]string) (string, error) {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		value := options[name]
+		switch name {
+		case "DEFAULT_TIME_TO_LIVE":
+			if _, err := strconv.Atoi(value); err != nil {
+				return "", fmt.Errorf("invalid DEFAULT_TIME_TO_LIVE value '%s': expected a whole number of seconds", value)
+			}
+		default:
+			return "", fmt.Errorf("Cassandra has no %s table option", name)
+		}
+		parts = append(parts, fmt.Sprintf("%s = %s", strings.ToLower(name), value))
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return " WITH " + strings.Join(parts, " AND "), nil
+}
+
+// BuildAlterTableSQL alters one column; only clustering columns can be
+// renamed, and a column's type cannot change
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+	table := QuoteIdentifier(query.Table)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		if isKeyColumn(field.Constraints) {
+			return "", fmt.Errorf("Cassandra cannot add %s to the primary key of an existing table", columnName)
+		}
+		column, err := TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", table, column), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP %s", table, QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return fmt.Sprintf("ALTER TABLE %s RENAME %s TO %s", table, QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
+	case "MODIFY_COLUMN":
+		return "", fmt.Errorf("Cassandra cannot change the type of column %s", columnName)
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+// BuildDropTableSQL drops a table; its indexes go with it
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Cascade {
+		return "", fmt.Errorf("Cassandra has no foreign keys to DROP TABLE ... CASCADE")
+	}
+	return "DROP TABLE " + QuoteIdentifier(query.Table), nil
+}
+
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
+}
+
+// BuildCreateIndexSQL creates a secondary index on one column, which lets a
+// WHERE find rows by it within or across partitions
+func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index details specified")
+	}
+
+	indexName := getFieldName(query.Fields[0])
+	columnName := getFieldValue(query.Fields[0])
+	if strings.Contains(columnName, ",") {
+		return "", fmt.Errorf("Cassandra indexes cover one column, not %s", columnName)
+	}
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			return "", fmt.Errorf("Cassandra has no UNIQUE indexes: the primary key is the only unique column set")
+		case "FULLTEXT", "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by Cassandra", strings.ToUpper(constraint))
+		}
+	}
+
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", QuoteIdentifier(indexName), QuoteIdentifier(query.Table), QuoteIdentifier(columnName)), nil
+}
+
+func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index name specified")
+	}
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s", QuoteIdentifier(getFieldName(query.Fields[0]))), nil
+}
+
+// TranslateColumn renders a column definition from the Cassandra type map
+// Sizes are dropped: text and decimal have none. Cassandra has no NOT NULL
+// (primary key columns are always set), no UNIQUE and no generated columns.
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) (string, error) {
+	key, notNull := false, false
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			return "", fmt.Errorf("Cassandra cannot enforce UNIQUE on column %s", columnName)
+		case "NOT_NULL", "NOTNULL":
+			notNull = true
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			key = true
+		}
+	}
+	if notNull && !key {
+		return "", fmt.Errorf("Cassandra has no NOT NULL columns: only primary key columns are always set (%s)", columnName)
+	}
+	if generated != nil {
+		return "", fmt.Errorf("Cassandra has no generated columns: compute %s when writing it", columnName)
+	}
+
+	nativeType, err := cassandraColumnType(columnName, columnType, typeMap)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s", QuoteIdentifier(columnName), nativeType), nil
+}
+
+// cassandraColumnType maps a column type, lists included: TEXT[] -> list<text>
+func cassandraColumnType(columnName, oqlType string, typeMap map[string]map[string]string) (string, error) {
+	switch strings.ToUpper(oqlType) {
+	case "AUTO", "BIGAUTO":
+		return "", fmt.Errorf("Cassandra has no auto-increment columns: make %s a UUID and write uuid() or a client-generated id", columnName)
+	}
+	nativeType := nativeColumnType(mapping.ArrayElementType(oqlType), typeMap)
+	if mapping.IsArrayType(oqlType) {
+		nativeType = fmt.Sprintf(mapping.ArrayTypeMap["Cassandra"], nativeType)
+	}
+	return nativeType, nil
+}
+
+// nativeColumnType maps the base of a column type without its size:
+// STRING(100) -> text, DECIMAL(10,2) -> decimal
+func nativeColumnType(oqlType string, typeMap map[string]map[string]string) string {
+	baseType := oqlType
+	if idx := strings.Index(oqlType, "("); idx != -1 {
+		baseType = oqlType[:idx]
+	}
+	if native, exists := typeMap["Cassandra"][strings.ToUpper(baseType)]; exists {
+		return native
+	}
+	return strings.ToLower(oqlType) // A native type: timeuuid, counter, varint
+}
+
+// ============================================================================
+// DQL OPERATIONS - CQL BUILDERS
+// ============================================================================
+
+// BuildAggregateSQL creates COUNT / SUM / AVG / MIN / MAX over the rows of
+// the named partitions; GROUP BY takes partition key and clustering columns
+func BuildAggregateSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if err := checkPartitionKey(query.Table, query.Conditions, opts); err != nil {
+		return "", nil, err
+	}
+	if len(query.Having) > 0 {
+		return "", nil, mapping.NotSupported("Cassandra", "HAVING", mapping.SupportsOperation)
+	}
+	if query.Limit > 0 && len(query.GroupBy) == 0 {
+		return "", nil, fmt.Errorf("Cassandra cannot limit the rows an aggregate reads: narrow the WHERE instead")
+	}
+
+	selectClause := "COUNT(*)"
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+		if query.Distinct {
+			return "", nil, &mapping.ErrNotSupported{Database: "Cassandra", Feature: aggFunc + " DISTINCT"}
+		}
+		if aggField == "" || aggField == "*" {
+			selectClause = fmt.Sprintf("%s(*)", aggFunc)
+		} else {
+			selectClause = fmt.Sprintf("%s(%s)", aggFunc, aggField)
+		}
+	}
+	for _, expr := range query.GroupBy {
+		if err := checkExpression(expr); err != nil {
+			return "", nil, err
+		}
+	}
+	if len(query.GroupBy) > 0 {
+		selectClause += ", " + buildExpressionList(query.GroupBy)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	whereClause, args, err := BuildWhereClause(query.Conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += whereClause
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	if query.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+	return sql, args, nil
+}
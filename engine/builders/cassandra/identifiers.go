@@ -0,0 +1,82 @@
+package cassandra
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// plainIdentifier matches names that are safe to emit unquoted; CQL names
+// start with a letter
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// reservedWords are CQL reserved words that cannot be used as bare identifiers
+var reservedWords = map[string]bool{
+	"ADD": true, "ALLOW": true, "ALTER": true, "AND": true, "APPLY": true,
+	"ASC": true, "AUTHORIZE": true, "BATCH": true, "BEGIN": true, "BY": true,
+	"COLUMNFAMILY": true, "CREATE": true, "DELETE": true, "DESC": true, "DESCRIBE": true,
+	"DROP": true, "ENTRIES": true, "EXECUTE": true, "FROM": true, "FULL": true,
+	"GRANT": true, "IF": true, "IN": true, "INDEX": true, "INFINITY": true,
+	"INSERT": true, "INTO": true, "KEYSPACE": true, "LIMIT": true, "MODIFY": true,
+	"NAN": true, "NORECURSIVE": true, "NOT": true, "NULL": true, "OF": true,
+	"ON": true, "OR": true, "ORDER": true, "PRIMARY": true, "RENAME": true,
+	"REPLACE": true, "REVOKE": true, "SCHEMA": true, "SELECT": true, "SET": true,
+	"TABLE": true, "TO": true, "TOKEN": true, "TRUNCATE": true, "UNLOGGED": true,
+	"UPDATE": true, "USE": true, "USING": true, "VIEW": true, "WHERE": true,
+	"WITH": true,
+}
+
+// valueKeywords are CQL literals that appear as FIELD expressions
+// (SET deleted = NULL) and must not be quoted
+var valueKeywords = map[string]bool{
+	"NULL": true, "TRUE": true, "FALSE": true,
+}
+
+// QuoteIdentifier returns name safe for interpolation into CQL
+// Plain names (users, created_at) are left bare so Cassandra folds them to
+// lower case as usual. A reserved word is quoted in lower case ("order"), the
+// name Cassandra would have folded it to; any other name is double-quoted as
+// written. Qualified names (keyspace.table) are quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, `"`) {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	if plainIdentifier.MatchString(name) {
+		if !reservedWords[strings.ToUpper(name)] {
+			return name
+		}
+		name = strings.ToLower(name)
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteColumnRef quotes a FIELD expression value, leaving CQL literals alone
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return strings.ToUpper(name)
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteString returns s as a single-quoted string literal; CQL has no
+// backslash escapes, only doubled quotes
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,13 @@
+package cassandra
+
+// Options are the per-client settings of the Cassandra builders. The zero
+// value lists no partition keys.
+type Options struct {
+	// PartitionKeys lists the partition key columns of each table, by the
+	// table name the builders write: {"events": {"user_id"}}. A read, UPDATE
+	// or DELETE on a listed table must set every one of them with = or IN, so
+	// it goes to known partitions instead of scanning the cluster. Tables that
+	// are not listed are left to Cassandra, which rejects a WHERE it cannot
+	// serve without ALLOW FILTERING.
+	PartitionKeys map[string][]string
+}
@@ -0,0 +1,52 @@
+package cassandra
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// PARTITION KEYS (WHERE must name the partition)
+// ============================================================================
+
+// checkPartitionKey returns an error unless conditions set every partition
+// key column of table, listed in Options.PartitionKeys, with a top-level = or IN
+func checkPartitionKey(table string, conditions []*pb.QueryCondition, opts Options) error {
+	keys := opts.PartitionKeys[table]
+	if len(keys) == 0 {
+		return nil
+	}
+	var missing []string
+	for _, key := range keys {
+		if !restrictsKey(key, conditions) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("WHERE must set the partition key of %s (%s) with = or IN; missing %s",
+			table, strings.Join(keys, ", "), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// restrictsKey reports whether an AND-ed condition, or a parenthesized group
+// of them, pins key to one or more values
+func restrictsKey(key string, conditions []*pb.QueryCondition) bool {
+	for _, cond := range conditions {
+		if len(cond.Nested) > 0 {
+			if restrictsKey(key, cond.Nested) {
+				return true
+			}
+			continue
+		}
+		if cond.FieldExpr == nil || cond.FieldExpr.Type != "FIELD" || !strings.EqualFold(cond.FieldExpr.Value, key) {
+			continue
+		}
+		if cond.Operator == "=" || cond.Operator == "IN" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// ARRAY CONDITIONS (has / hasAll / arrayExists / arrayAll)
+// ============================================================================
+
+// arrayElement names the lambda parameter of arrayExists and arrayAll
+const arrayElement = "elem"
+
+// isFunctionExpr checks if expr is a call to the named function
+func isFunctionExpr(expr *pb.Expression, name string) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == name
+}
+
+// isLiteralExpr checks if expr is a plain value (parameterized, never interpolated)
+func isLiteralExpr(expr *pb.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	switch expr.Type {
+	case "STRING", "NUMBER", "BOOLEAN", "LITERAL":
+		return true
+	}
+	return false
+}
+
+// buildArrayLiteral builds: [?, ?, ...] (non-literal elements are inlined)
+func buildArrayLiteral(elements []*pb.Expression) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, e := range elements {
+		if isLiteralExpr(e) {
+			parts = append(parts, "?")
+			args = append(args, ConvertClickHouseValue(e.Value))
+		} else {
+			parts = append(parts, BuildExpressionSQL(e))
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]", args
+}
+
+// buildArrayOperandSQL renders the array side of a condition: ARRAY('a', 'b')
+// as [?, ?], anything else (a column, a function) as it is
+func buildArrayOperandSQL(expr *pb.Expression) (string, []interface{}) {
+	if isFunctionExpr(expr, "ARRAY") {
+		return buildArrayLiteral(expr.FunctionArgs)
+	}
+	return buildValueSQL(expr)
+}
+
+// buildContainmentCondition renders tags @> ARRAY('a', 'b') as
+// hasAll(tags, [?, ?]) and <@ as hasAll([?, ?], tags)
+func buildContainmentCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	valueSQL, args := buildArrayOperandSQL(cond.ValueExpr)
+	if cond.Operator == "<@" {
+		return fmt.Sprintf("hasAll(%s, %s)", valueSQL, field), args
+	}
+	return fmt.Sprintf("hasAll(%s, %s)", field, valueSQL), args
+}
+
+// buildQuantifiedCondition builds value op ANY(array) and value op ALL(array)
+// 'admin' = ANY(roles) is has(roles, ?); other comparisons test each element:
+// age > ALL(1, 2) is arrayAll(elem -> ? > elem, [?, ?]).
+func buildQuantifiedCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	var args []interface{}
+	left := BuildExpressionSQL(cond.FieldExpr)
+	if isLiteralExpr(cond.FieldExpr) {
+		left = "?"
+		args = append(args, ConvertClickHouseValue(cond.FieldExpr.Value))
+	}
+
+	elements := cond.ValueExpr.FunctionArgs
+	var array string
+	if len(elements) == 1 && !isLiteralExpr(elements[0]) {
+		array = BuildExpressionSQL(elements[0])
+	} else {
+		arraySQL, arrayArgs := buildArrayLiteral(elements)
+		array = arraySQL
+		args = append(args, arrayArgs...)
+	}
+
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ANY" && cond.Operator == "=" {
+		// has takes the array first; keep the arguments in the same order
+		if left == "?" && len(args) > 1 {
+			args = append(args[1:], args[0])
+		}
+		return fmt.Sprintf("has(%s, %s)", array, left), args
+	}
+	function := "arrayExists"
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ALL" {
+		function = "arrayAll"
+	}
+	return fmt.Sprintf("%s(%s -> %s %s %s, %s)", function, arrayElement, left, clickHouseOperator(cond.Operator), arrayElement, array), args
+}
+
+// buildUnnestCondition builds UNNEST(tags) LIKE 'a%' as
+// arrayExists(elem -> elem LIKE ?, tags)
+func buildUnnestCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	array := ""
+	if len(cond.FieldExpr.FunctionArgs) > 0 {
+		array = BuildExpressionSQL(cond.FieldExpr.FunctionArgs[0])
+	}
+	innerSQL, args := buildComparison(arrayElement, cond)
+	return fmt.Sprintf("arrayExists(%s -> %s, %s)", arrayElement, innerSQL, array), args
+}
@@ -0,0 +1,1333 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ClickHouse is a column store: rows are written in blocks and sorted by the
+// table's ORDER BY key, and UPDATE / DELETE are mutations that rewrite the
+// affected parts in the background (ALTER TABLE ... UPDATE / DELETE).
+// Statements use ? placeholders, which clickhouse-go binds.
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getJoinLeft(join *pb.JoinClause) string {
+	if join == nil || join.LeftExpr == nil {
+		return ""
+	}
+	return join.LeftExpr.Value
+}
+
+func getJoinRight(join *pb.JoinClause) string {
+	if join == nil || join.RightExpr == nil {
+		return ""
+	}
+	return join.RightExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// buildValueSQL renders a value position: computed expressions inline, value
+// keywords in their ClickHouse spelling (CURRENT_TIMESTAMP is now()), and
+// anything else as a ? bind
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" {
+		if keyword, ok := valueKeywords[strings.ToUpper(expr.Value)]; ok {
+			return keyword, nil
+		}
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{ConvertClickHouseValue(value)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches and DDL take no parameters)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return formatLiteral(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fromClause renders the table a query reads with its modifiers:
+// `events` FINAL SAMPLE 0.1
+func fromClause(query *pb.RelationalQuery) string {
+	from := QuoteIdentifier(query.Table)
+	if query.Final {
+		from += " FINAL"
+	}
+	if query.Sample != "" {
+		from += " SAMPLE " + query.Sample
+	}
+	return from
+}
+
+// paginate appends LIMIT n [OFFSET m]; an offset without a limit is OFFSET m ROWS
+func paginate(sql string, limit, offset int32) string {
+	switch {
+	case limit > 0 && offset > 0:
+		return sql + fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	case limit > 0:
+		return sql + fmt.Sprintf(" LIMIT %d", limit)
+	case offset > 0:
+		return sql + fmt.Sprintf(" OFFSET %d ROWS", offset)
+	}
+	return sql
+}
+
+// ============================================================================
+// CRUD OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates parameterized SELECT query with expression support
+func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+
+	var args []interface{}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			var colSQL string
+			switch {
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "CASEWHEN":
+				caseSQL := "CASE"
+				for _, cond := range col.ExpressionObj.CaseConditions {
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", buildConditionSQL(cond.Condition), thenSQL)
+					args = append(args, thenArgs...)
+				}
+				if col.ExpressionObj.CaseElse != nil {
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
+				}
+				colSQL = caseSQL + " END"
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW":
+				colSQL = buildWindowExprSQL(col.ExpressionObj)
+			default:
+				colSQL = BuildExpressionSQL(col.ExpressionObj)
+			}
+			if col.Alias != "" {
+				colSQL += " AS " + QuoteIdentifier(col.Alias)
+			}
+			colParts = append(colParts, colSQL)
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, fromClause(query))
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args, nil
+}
+
+// buildConditionSQL renders a condition with its values inline (CASE WHEN)
+func buildConditionSQL(cond *pb.QueryCondition) string {
+	if cond == nil {
+		return ""
+	}
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	}
+	return fmt.Sprintf("%s %s %s", field, clickHouseOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+}
+
+// buildWindowExprSQL renders a window function selected inline
+func buildWindowExprSQL(expr *pb.Expression) string {
+	funcName := strings.ReplaceAll(expr.FunctionName, " ", "_")
+
+	var funcCall string
+	shift := false
+	switch funcName {
+	case "LAG", "LEAD":
+		field := "id"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				field = QuoteIdentifier(arg.Value)
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", shiftFunction(funcName), field)
+		shift = true
+	case "NTILE":
+		buckets := "4"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				buckets = arg.Value
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("ntile(%s)", buckets)
+	default:
+		funcCall = fmt.Sprintf("%s()", strings.ToLower(funcName))
+	}
+
+	var partitionParts, orderParts []string
+	for _, arg := range expr.FunctionArgs {
+		if strings.HasPrefix(arg.Value, "PARTITION:") {
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
+		} else if strings.HasPrefix(arg.Value, "ORDER:") {
+			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
+			if len(parts) >= 2 {
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
+			} else if len(parts) == 1 {
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
+			}
+		}
+	}
+
+	var overParts []string
+	if len(partitionParts) > 0 {
+		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionParts, ", "))
+	}
+	if len(orderParts) > 0 {
+		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
+	}
+	if shift {
+		overParts = append(overParts, wholePartitionFrame)
+	}
+	return funcCall + " OVER (" + strings.Join(overParts, " ") + ")"
+}
+
+// BuildInsertSQL creates parameterized INSERT query
+func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query)
+	}
+
+	var fields, placeholders []string
+	var args []interface{}
+
+	for _, field := range query.Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	return sql, args, nil
+}
+
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + sourceSQL, nil, nil
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildUpdateSQL creates an ALTER TABLE ... UPDATE mutation. A mutation
+// needs a WHERE clause, so an unconditional UPDATE is WHERE 1. Columns of
+// the sorting key cannot be updated.
+func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var setParts []string
+	var args []interface{}
+
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			return "", nil, fmt.Errorf("ClickHouse cannot update part of a JSON value: set %s to the whole document", column.GetValue())
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
+	}
+	if len(setParts) == 0 {
+		return "", nil, fmt.Errorf("no columns specified for UPDATE")
+	}
+
+	sql := fmt.Sprintf("ALTER TABLE %s UPDATE %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
+	whereClause, whereArgs := mutationWhereClause(query.Conditions)
+	return sql + whereClause, append(args, whereArgs...), nil
+}
+
+// BuildDeleteSQL creates an ALTER TABLE ... DELETE mutation; an unconditional
+// DELETE is WHERE 1
+func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql := fmt.Sprintf("ALTER TABLE %s DELETE", QuoteIdentifier(query.Table))
+	whereClause, args := mutationWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// mutationWhereClause renders the WHERE clause a mutation requires
+func mutationWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return " WHERE 1", nil
+	}
+	return BuildWhereClause(conditions)
+}
+
+// BuildBulkInsertSQL creates BULK INSERT as one multi-row INSERT, which
+// ClickHouse writes as a single block
+func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+
+	var fields []string
+	for _, field := range query.BulkData[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+	}
+
+	var rows []string
+	var args []interface{}
+	for _, row := range query.BulkData {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+		}
+		rows = append(rows, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(rows, ", "))
+	return sql, args, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+func BuildWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " WHERE " + clause, args
+}
+
+func buildConditionsRecursive(conditions []*pb.QueryCondition) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		var clause string
+		var clauseArgs []interface{}
+
+		if len(cond.Nested) > 0 {
+			nestedClause, nestedArgs := buildConditionsRecursive(cond.Nested)
+			clause = "(" + nestedClause + ")"
+			clauseArgs = nestedArgs
+		} else {
+			clause, clauseArgs = buildSingleCondition(cond)
+		}
+
+		if i > 0 {
+			logic := cond.Logic
+			if logic == "" {
+				logic = "AND"
+			}
+			parts = append(parts, logic)
+		}
+		parts = append(parts, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	return strings.Join(parts, " "), args
+}
+
+func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	// Array conditions: UNNEST(tags) LIKE 'a%', 'admin' = ANY(roles)
+	if isFunctionExpr(cond.FieldExpr, "UNNEST") {
+		return buildUnnestCondition(cond)
+	}
+	if isFunctionExpr(cond.ValueExpr, "ANY") || isFunctionExpr(cond.ValueExpr, "ALL") {
+		return buildQuantifiedCondition(cond)
+	}
+	return buildComparison(BuildExpressionSQL(cond.FieldExpr), cond)
+}
+
+// buildComparison renders cond against field, already rendered as SQL
+func buildComparison(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	switch cond.Operator {
+	case "IS_NULL":
+		return fmt.Sprintf("%s IS NULL", field), nil
+	case "IS_NOT_NULL":
+		return fmt.Sprintf("%s IS NOT NULL", field), nil
+	case "IN":
+		return buildInClause(field, "IN", cond.ValuesExpr)
+	case "NOT_IN":
+		return buildInClause(field, "NOT IN", cond.ValuesExpr)
+	case "BETWEEN":
+		return buildBetweenClause(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "NOT_BETWEEN":
+		return buildBetweenClause(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "@>", "<@":
+		return buildContainmentCondition(field, cond)
+	case "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
+	default:
+		valueSQL, args := buildArrayOperandSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, clickHouseOperator(cond.Operator), valueSQL), args
+	}
+}
+
+// clickHouseOperator maps an OQL operator (NOT_LIKE, NOT_ILIKE) to its ClickHouse spelling
+func clickHouseOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["ClickHouse"][op]; ok {
+		return mapped
+	}
+	return op
+}
+
+// buildInClause renders IN / NOT IN; an empty list matches nothing (IN) or everything (NOT IN)
+func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}) {
+	if len(values) == 0 {
+		if operator == "IN" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args
+}
+
+func buildBetweenClause(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}) {
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args
+}
+
+// BuildExpressionSQL converts an Expression to SQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		// ARRAY(a, b) is the OQL spelling of an array literal
+		if strings.ToUpper(expr.FunctionName) == "ARRAY" {
+			return "[" + strings.Join(args, ", ") + "]"
+		}
+		return mapping.FunctionSQL("ClickHouse", expr.FunctionName, args)
+	case "CASEWHEN":
+		caseParts := []string{"CASE"}
+		for _, cond := range expr.CaseConditions {
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", buildConditionSQL(cond.Condition), buildLiteralSQL(cond.ThenExpr)))
+		}
+		if expr.CaseElse != nil {
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
+		}
+		caseParts = append(caseParts, "END")
+		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		return quoteColumnRef(expr.Value)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
+	default:
+		return expr.Value
+	}
+}
+
+// ConvertClickHouseValue converts values for ClickHouse compatibility
+// Booleans bind as Go bools, which clickhouse-go writes as true / false.
+func ConvertClickHouseValue(value string) interface{} {
+	switch strings.ToLower(value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return value
+	}
+}
+
+// formatLiteral converts a value to SQL literal format for VIEW definitions
+func formatLiteral(v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return "true"
+	case "FALSE":
+		return "false"
+	}
+	return QuoteString(s)
+}
+
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	var b strings.Builder
+	inString := false
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(sql):
+			b.WriteByte(c)
+			i++
+			c = sql[i]
+		case c == '\'':
+			inString = !inString
+		case !inString && c == '?' && next < len(args):
+			b.WriteString(formatLiteral(args[next]))
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) (string, error) {
+	viewSQL, args, err := BuildSelectSQL(query)
+	if err != nil {
+		return "", err
+	}
+	return inlinePlaceholders(viewSQL, args), nil
+}
+
+// ============================================================================
+// DDL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// DefaultEngine is the table engine of CREATE TABLE without ENGINE = ...
+const DefaultEngine = "MergeTree"
+
+// BuildCreateTableSQL creates a MergeTree-family table sorted by its primary
+// key: CREATE TABLE t (...) ENGINE = MergeTree ORDER BY (id). The AUTO and
+// PRIMARY_KEY columns make up the sorting key, in column order; a table
+// without them is ORDER BY tuple() (unsorted). CREATE TABLE name AS GET ...
+// copies rows into an unsorted MergeTree table.
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	engine, err := buildEngineClause(query.TableOptions)
+	if err != nil {
+		return "", err
+	}
+	if query.ViewQuery != nil {
+		sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("CREATE TABLE %s %s ORDER BY tuple() AS %s", QuoteIdentifier(query.Table), engine, sourceSQL), nil
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	var columns, sortingKey []string
+	for _, field := range query.Fields {
+		column, err := TranslateColumn(getFieldName(field), getFieldValue(field), field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		columns = append(columns, column)
+		if isKeyColumn(getFieldValue(field), field.Constraints) {
+			sortingKey = append(sortingKey, QuoteIdentifier(getFieldName(field)))
+		}
+	}
+
+	orderBy := "tuple()"
+	if len(sortingKey) > 0 {
+		orderBy = "(" + strings.Join(sortingKey, ", ") + ")"
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s) %s ORDER BY %s", QuoteIdentifier(query.Table), strings.Join(columns, ", "), engine, orderBy), nil
+}
+
+// buildEngineClause renders ENGINE = name from the ENGINE table option
+// (MergeTree when there is none). Engines that take parameters, such as
+// ReplacingMergeTree(version), are created natively.
+func buildEngineClause(options map[string]string) (string, error) {
+	engine := DefaultEngine
+	for name, value := range options {
+		if name != "ENGINE" {
+			return "", fmt.Errorf("ClickHouse has no %s table option", name)
+		}
+		if !isEngineName(value) {
+			return "", fmt.Errorf("invalid ENGINE value '%s'", value)
+		}
+		engine = value
+	}
+	return "ENGINE = " + engine, nil
+}
+
+func isEngineName(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isKeyColumn reports whether a column belongs to the sorting key
+func isKeyColumn(columnType string, constraints []string) bool {
+	switch strings.ToUpper(columnType) {
+	case "AUTO", "BIGAUTO":
+		return true
+	}
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			return true
+		}
+	}
+	return false
+}
+
+// BuildAlterTableSQL alters one column
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+	table := QuoteIdentifier(query.Table)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		column, err := TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, column), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
+	case "MODIFY_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("MODIFY_COLUMN requires column type")
+		}
+		column, err := TranslateColumn(columnName, columnValue, field.Constraints, nil, typeMap)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", table, column), nil
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+// BuildDropTableSQL drops a table; ClickHouse has no foreign keys, so there
+// is nothing to CASCADE to
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Cascade {
+		return "", fmt.Errorf("ClickHouse has no DROP TABLE ... CASCADE")
+	}
+	return "DROP TABLE " + QuoteIdentifier(query.Table), nil
+}
+
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
+}
+
+func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.NewName == "" {
+		return "", fmt.Errorf("no new name specified for RENAME TABLE")
+	}
+	return fmt.Sprintf("RENAME TABLE %s TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(query.NewName)), nil
+}
+
+// BuildCreateIndexSQL adds a data skipping index, which lets a read skip the
+// granules whose values cannot match: ALTER TABLE t ADD INDEX name (cols)
+// TYPE bloom_filter GRANULARITY 1. It applies to parts written afterwards;
+// MATERIALIZE INDEX builds it for existing rows.
+func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index details specified")
+	}
+
+	indexName := getFieldName(query.Fields[0])
+	columnName := getFieldValue(query.Fields[0])
+
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			return "", fmt.Errorf("ClickHouse has no unique indexes: use a ReplacingMergeTree table to keep one row per key")
+		case "FULLTEXT", "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by ClickHouse", strings.ToUpper(constraint))
+		}
+	}
+
+	columns := quoteIdentifierList(columnName)
+	if strings.Contains(columnName, ",") {
+		columns = "(" + columns + ")"
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD INDEX %s %s TYPE bloom_filter GRANULARITY 1", QuoteIdentifier(query.Table), QuoteIdentifier(indexName), columns), nil
+}
+
+// BuildDropIndexSQL drops a data skipping index of the table
+func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index name specified")
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", QuoteIdentifier(query.Table), QuoteIdentifier(getFieldName(query.Fields[0]))), nil
+}
+
+func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE VIEW", query)
+}
+
+// BuildAlterViewSQL replaces a view's query: CREATE OR REPLACE VIEW
+func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE OR REPLACE VIEW", query)
+}
+
+func buildViewSQL(statement string, query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no view query specified")
+	}
+	viewSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s AS %s", statement, QuoteIdentifier(query.ViewName), viewSQL), nil
+}
+
+func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	return fmt.Sprintf("DROP VIEW %s", QuoteIdentifier(query.ViewName)), nil
+}
+
+func BuildCreateDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified for CREATE DATABASE")
+	}
+	return fmt.Sprintf("CREATE DATABASE %s", QuoteIdentifier(query.DatabaseName)), nil
+}
+
+func BuildDropDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified for DROP DATABASE")
+	}
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", QuoteIdentifier(query.DatabaseName)), nil
+}
+
+// TranslateColumn renders a column definition from the ClickHouse type map
+// A size replaces the mapped default where the type takes one (DECIMAL(10,2)
+// is Decimal(10,2)) and is dropped for String. Columns are not Nullable: a
+// NULL written to one stores the type's default. A generated column is
+// MATERIALIZED, computed on insert.
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) (string, error) {
+	for _, constraint := range constraints {
+		if strings.ToUpper(constraint) == "UNIQUE" {
+			return "", fmt.Errorf("ClickHouse cannot enforce UNIQUE on column %s: use a ReplacingMergeTree table to keep one row per key", columnName)
+		}
+	}
+
+	clickHouseType := nativeColumnType(mapping.ArrayElementType(columnType), typeMap)
+	if mapping.IsArrayType(columnType) {
+		clickHouseType = fmt.Sprintf(mapping.ArrayTypeMap["ClickHouse"], clickHouseType)
+	}
+
+	columnDef := fmt.Sprintf("%s %s", QuoteIdentifier(columnName), clickHouseType)
+	if generated != nil {
+		columnDef += " MATERIALIZED " + BuildExpressionSQL(generated)
+	}
+	return columnDef, nil
+}
+
+// nativeColumnType maps the base of a column type: STRING(100) -> String,
+// TIMESTAMP(6) -> DateTime64(6)
+func nativeColumnType(columnType string, typeMap map[string]map[string]string) string {
+	baseType := columnType
+	params := ""
+	if idx := strings.Index(columnType, "("); idx != -1 {
+		baseType = columnType[:idx]
+		if endIdx := strings.LastIndex(columnType, ")"); endIdx > idx {
+			params = columnType[idx : endIdx+1]
+		}
+	}
+
+	native, exists := typeMap["ClickHouse"][strings.ToUpper(baseType)]
+	if !exists {
+		return columnType // A native type: LowCardinality(String), Enum8(...)
+	}
+	idx := strings.Index(native, "(")
+	if params == "" || idx == -1 || strings.Contains(native[:idx], " ") {
+		return native // String, Int32, ...: no size
+	}
+	return native[:idx] + params
+}
+
+// ============================================================================
+// DQL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectClause := "*"
+	if len(query.Columns) > 0 {
+		selectClause = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, fromClause(query))
+	var args []interface{}
+
+	for _, join := range query.Joins {
+		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		if joinType == "CROSS" {
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
+			continue
+		}
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
+		sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
+	}
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args
+}
+
+// buildGroupArraySQL builds STRING AGG from the group's values collected in
+// an array: arrayStringConcat(groupArray(field), 'sep'). An ordered
+// aggregate collects (field, key) pairs, sorts them by key and keeps the
+// fields; groupUniqArray collects distinct values, in no particular order.
+func buildGroupArraySQL(agg *pb.AggregateClause, distinct bool) (string, error) {
+	field := quoteColumnRef(getAggField(agg))
+	separator := QuoteString(agg.Separator)
+	if len(agg.OrderBy) == 0 {
+		function := "groupArray"
+		if distinct {
+			function = "groupUniqArray"
+		}
+		return fmt.Sprintf("arrayStringConcat(%s(%s), %s)", function, field, separator), nil
+	}
+	if distinct {
+		return "", fmt.Errorf("ClickHouse cannot order the distinct values of STRING AGG")
+	}
+
+	sort := ""
+	var keys []string
+	for _, ob := range agg.OrderBy {
+		direction := "arraySort"
+		if strings.EqualFold(ob.Direction, "DESC") {
+			direction = "arrayReverseSort"
+		}
+		if sort != "" && sort != direction {
+			return "", fmt.Errorf("ClickHouse STRING AGG sorts in one direction: ORDER BY keys must be all ASC or all DESC")
+		}
+		sort = direction
+		keys = append(keys, BuildExpressionSQL(ob.FieldExpr))
+	}
+
+	// Pair element 1 is the field, elements 2.. the sort keys
+	sortKeys := make([]string, len(keys))
+	for i := range keys {
+		sortKeys[i] = fmt.Sprintf("p.%d", i+2)
+	}
+	sortKey := sortKeys[0]
+	if len(sortKeys) > 1 {
+		sortKey = "(" + strings.Join(sortKeys, ", ") + ")"
+	}
+	pairs := fmt.Sprintf("groupArray((%s, %s))", field, strings.Join(keys, ", "))
+	return fmt.Sprintf("arrayStringConcat(arrayMap(p -> p.1, %s(p -> %s, %s)), %s)", sort, sortKey, pairs, separator), nil
+}
+
+func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var selectClause string
+	var args []interface{}
+
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+
+		switch {
+		case aggField == "" || aggField == "*":
+			if query.Distinct {
+				selectClause = "COUNT(*)"
+			} else {
+				selectClause = fmt.Sprintf("%s(*)", aggFunc)
+			}
+		case aggFunc == "STRING AGG":
+			groupArray, err := buildGroupArraySQL(query.Aggregate, query.Distinct)
+			if err != nil {
+				return "", nil, err
+			}
+			selectClause = groupArray
+		case query.Distinct:
+			selectClause = fmt.Sprintf("%s(DISTINCT %s)", aggFunc, aggField)
+		default:
+			selectClause = fmt.Sprintf("%s(%s)", aggFunc, aggField)
+		}
+		if len(query.GroupBy) > 0 {
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
+		}
+	} else {
+		selectClause = "COUNT(*)"
+	}
+
+	// Paging an ungrouped aggregate pages the rows it reads
+	if (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0 {
+		innerSQL := fmt.Sprintf("SELECT * FROM %s", fromClause(query))
+		whereClause, whereArgs := BuildWhereClause(query.Conditions)
+		innerSQL += whereClause
+		args = append(args, whereArgs...)
+		if len(query.OrderBy) > 0 {
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		innerSQL = paginate(innerSQL, query.Limit, query.Offset)
+		return fmt.Sprintf("SELECT %s FROM (%s) AS subquery", selectClause, innerSQL), args, nil
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, fromClause(query))
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.Having) > 0 {
+		havingClause, havingArgs := BuildHavingClause(query.Having)
+		sql += havingClause
+		args = append(args, havingArgs...)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args, nil
+}
+
+// wholePartitionFrame is the frame lagInFrame and leadInFrame need to reach
+// rows outside the default frame (the start of the partition up to the
+// current row)
+const wholePartitionFrame = "ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING"
+
+// shiftFunction names LAG / LEAD: lagInFrame / leadInFrame
+func shiftFunction(function string) string {
+	return strings.ToLower(function) + "InFrame"
+}
+
+// BuildWindowSQL creates window function queries
+func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectParts := []string{"*"}
+
+	for _, wf := range query.WindowFunctions {
+		windowFunc := strings.ReplaceAll(strings.ToUpper(wf.Function), " ", "_")
+
+		var funcSQL string
+		shift := false
+		switch windowFunc {
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
+			shift = true
+		case "NTILE":
+			buckets := wf.Buckets
+			if buckets <= 0 {
+				buckets = 4
+			}
+			funcSQL = fmt.Sprintf("ntile(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
+		default:
+			funcSQL = fmt.Sprintf("%s()", strings.ToLower(windowFunc))
+		}
+
+		var overParts []string
+		if len(wf.PartitionBy) > 0 {
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
+		}
+		if len(wf.OrderBy) > 0 {
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		}
+		if wf.FrameUnit != "" {
+			overParts = append(overParts, fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd))
+		} else if shift {
+			overParts = append(overParts, wholePartitionFrame)
+		}
+
+		column := fmt.Sprintf("%s OVER (%s)", funcSQL, strings.Join(overParts, " "))
+		if wf.Alias != "" {
+			column += " AS " + QuoteIdentifier(wf.Alias)
+		}
+		selectParts = append(selectParts, column)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), fromClause(query))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// buildShiftSQL renders LAG / LEAD (field, offset[, default]) as lagInFrame /
+// leadInFrame
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", shiftFunction(function), QuoteIdentifier(field), offset, buildLiteralSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", shiftFunction(function), QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM(amount), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// BuildSetOperationSQL joins two queries with a set operation
+// Each query is parenthesized and keeps its own ORDER BY / LIMIT. ClickHouse
+// applies an ORDER BY / LIMIT after the last query to that query alone, so
+// the combined result is read from a subquery to order or page it.
+func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	setOp := query.SetOperation
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(setOp.LeftQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(setOp.RightQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql := fmt.Sprintf("(%s) %s (%s)", leftSQL, setOperator(setOp.OperationType), rightSQL)
+	if len(query.OrderBy) > 0 || query.Limit > 0 || query.Offset > 0 {
+		sql = "SELECT * FROM (" + sql + ")"
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), append(leftArgs, rightArgs...), nil
+}
+
+// buildSetOperandSQL builds one query of a set operation, which may itself be
+// a set operation, a join or an aggregate
+func buildSetOperandSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	switch {
+	case query.SetOperation != nil:
+		return BuildSetOperationSQL(query)
+	case len(query.Joins) > 0:
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	case query.Aggregate != nil:
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// setOperator spells a set operation; a bare UNION is an error unless the
+// union_default_mode setting is set, so it is written UNION DISTINCT
+func setOperator(operationType string) string {
+	switch strings.ToUpper(operationType) {
+	case "UNION_ALL", "UNION ALL":
+		return "UNION ALL"
+	case "INTERSECT":
+		return "INTERSECT"
+	case "EXCEPT":
+		return "EXCEPT"
+	default:
+		return "UNION DISTINCT"
+	}
+}
+
+func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, fromClause(query))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+func BuildHavingClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " HAVING " + clause, args
+}
+
+// ============================================================================
+// CTE OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCTESQL creates WITH [RECURSIVE] name AS (...) SELECT ...
+// Recursive CTEs need ClickHouse 24.4 or later.
+func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Cte == nil {
+		return "", nil, fmt.Errorf("no CTE specified")
+	}
+	cteSQL, params, err := buildCTEBodySQL(query.Cte.CteQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	with := "WITH"
+	if query.Cte.Recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", cteName)
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs, err = BuildSelectSQL(query.Cte.MainQuery)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("%s %s AS (%s) %s", with, cteName, cteSQL, mainSQL), params, nil
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+func buildCTEBodySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query == nil {
+		return "", nil, fmt.Errorf("no CTE query specified")
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		leftSQL, leftArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		rightSQL, rightArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL), append(leftArgs, rightArgs...), nil
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// ============================================================================
+// SUBQUERY OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSubquerySQL creates an IN subquery, or an EXISTS check selected as 1 / 0
+func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Subquery == nil || query.Subquery.Subquery == nil {
+		return "", nil, fmt.Errorf("no subquery specified")
+	}
+
+	subquerySQL, subArgs, err := BuildSelectSQL(query.Subquery.Subquery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.ToUpper(query.Subquery.SubqueryType) == "EXISTS" {
+		return fmt.Sprintf("SELECT EXISTS(%s)", subquerySQL), subArgs, nil
+	}
+	if query.Table == "" {
+		return "", nil, fmt.Errorf("IN subquery requires an outer table")
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", fromClause(query))
+	var args []interface{}
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := buildConditionsRecursive(query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
+	}
+	sql += fmt.Sprintf("%s IN (%s)", BuildExpressionSQL(query.Subquery.FieldExpr), subquerySQL)
+	return sql, append(args, subArgs...), nil
+}
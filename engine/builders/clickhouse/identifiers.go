@@ -0,0 +1,65 @@
+package clickhouse
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted, with their
+// ClickHouse spelling
+var valueKeywords = map[string]string{
+	"CURRENT_TIMESTAMP": "now()", "CURRENT_DATE": "today()",
+	"NULL": "NULL", "TRUE": "true", "FALSE": "false", "DEFAULT": "DEFAULT",
+}
+
+// QuoteIdentifier returns name backtick-quoted, with backslashes and
+// backticks escaped by a backslash: `users`. Qualified names (db.table,
+// table.column) are quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, "`") {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name) + "`"
+}
+
+// quoteIdentifierList quotes a comma-separated list of names (index columns)
+func quoteIdentifierList(names string) string {
+	parts := strings.Split(names, ",")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteColumnRef quotes a FIELD expression value, writing SQL value keywords
+// the ClickHouse way (CURRENT_TIMESTAMP is now())
+func quoteColumnRef(name string) string {
+	if keyword, ok := valueKeywords[strings.ToUpper(name)]; ok {
+		return keyword
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteString returns s as a single-quoted string literal; ClickHouse reads
+// backslash escapes in strings, so backslashes are escaped too
+func QuoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,76 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (JSON text read with JSONExtract* / JSONHas)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and the path
+// arguments of the JSON functions: data->'address'->>'city' = data,
+// 'address', 'city'. JSON functions count array elements from 1, so
+// data->0 is data, 1.
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, []string) {
+	var keys []string
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]string{jsonPathKey(expr.Right)}, keys...)
+		expr = expr.Left
+	}
+	return expr, keys
+}
+
+// jsonPathKey renders one path step: an array index moved to 1-based, or a
+// quoted member name
+func jsonPathKey(key *pb.Expression) string {
+	if key == nil {
+		return "''"
+	}
+	if key.Type == "NUMBER" {
+		if n, err := strconv.Atoi(key.Value); err == nil && n >= 0 {
+			return strconv.Itoa(n + 1)
+		}
+	}
+	return QuoteString(key.Value)
+}
+
+// buildJSONPathSQL renders ->> as JSONExtractString (a scalar as text) and
+// -> as JSONExtractRaw (the JSON text of the value)
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, keys := jsonPathTarget(expr)
+	function := "JSONExtractRaw"
+	if expr.Operator == "->>" {
+		function = "JSONExtractString"
+	}
+	args := append([]string{BuildExpressionSQL(column)}, keys...)
+	return fmt.Sprintf("%s(%s)", function, strings.Join(args, ", "))
+}
+
+// buildJSONCondition renders the JSONB key operators ?, ?| and ?& with
+// JSONHas, which checks a top-level key of the JSON text
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	if cond.Operator == "?" {
+		return fmt.Sprintf("JSONHas(%s, %s)", field, jsonPathKey(cond.ValueExpr)), nil
+	}
+	// ?| = any key, ?& = all keys
+	if len(cond.ValuesExpr) == 0 {
+		if cond.Operator == "?|" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+	logic := " OR "
+	if cond.Operator == "?&" {
+		logic = " AND "
+	}
+	parts := make([]string, len(cond.ValuesExpr))
+	for i, key := range cond.ValuesExpr {
+		parts[i] = fmt.Sprintf("JSONHas(%s, %s)", field, jsonPathKey(key))
+	}
+	return "(" + strings.Join(parts, logic) + ")", nil
+}
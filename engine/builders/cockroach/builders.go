@@ -0,0 +1,104 @@
+package cockroach
+
+import (
+	"strings"
+
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CockroachDB speaks the PostgreSQL wire protocol and dialect, so its
+// statements are built by the PostgreSQL builders; this package holds what
+// differs: UPSERT INTO, its column types and the functions it lacks.
+
+// ============================================================================
+// COLUMN TYPES
+// ============================================================================
+
+// MapColumnTypes replaces the universal column types of CREATE TABLE and
+// ALTER TABLE ... ADD with CockroachDB's (INT -> INT4, BINARY -> BYTES),
+// keeping sizes and [] array suffixes. AUTO is left for the PostgreSQL
+// builder, which makes it a SERIAL PRIMARY KEY.
+func MapColumnTypes(query *pb.RelationalQuery) {
+	if query.Operation == "alter_table" && !strings.EqualFold(query.AlterAction, "ADD_COLUMN") {
+		return
+	}
+	for _, field := range query.Fields {
+		if field.ValueExpr != nil {
+			field.ValueExpr.Value = nativeColumnType(field.ValueExpr.Value)
+		}
+	}
+}
+
+// nativeColumnType maps the base of a column type: STRING(100)[] -> VARCHAR(100)[]
+func nativeColumnType(columnType string) string {
+	element := mapping.ArrayElementType(columnType)
+	suffix := columnType[len(element):]
+	base, params := element, ""
+	if idx := strings.Index(element, "("); idx != -1 {
+		base, params = element[:idx], element[idx:]
+	}
+	if strings.EqualFold(base, "AUTO") {
+		return columnType
+	}
+	native, ok := mapping.TypeMap["CockroachDB"][strings.ToUpper(base)]
+	if !ok {
+		return columnType // A native or user-defined type
+	}
+	return native + params + suffix
+}
+
+// ============================================================================
+// ADVISORY LOCKS
+// ============================================================================
+
+// advisoryLockPrefixes start the names of PostgreSQL's advisory lock
+// functions (pg_advisory_lock, pg_try_advisory_xact_lock, ...), which
+// CockroachDB does not have
+var advisoryLockPrefixes = []string{"pg_advisory_", "pg_try_advisory_"}
+
+// FindAdvisoryLock returns the first advisory lock function called anywhere
+// in query (columns, conditions, values, subqueries), or "" if there is none
+func FindAdvisoryLock(query *pb.RelationalQuery) string {
+	return findAdvisoryLock(query.ProtoReflect())
+}
+
+func findAdvisoryLock(msg protoreflect.Message) string {
+	if expr, ok := msg.Interface().(*pb.Expression); ok && expr.Type == "FUNCTION" && isAdvisoryLock(expr.FunctionName) {
+		return expr.FunctionName
+	}
+	found := ""
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				value.Map().Range(func(_ protoreflect.MapKey, entry protoreflect.Value) bool {
+					found = findAdvisoryLock(entry.Message())
+					return found == ""
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := value.List()
+				for i := 0; i < list.Len() && found == ""; i++ {
+					found = findAdvisoryLock(list.Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			found = findAdvisoryLock(value.Message())
+		}
+		return found == ""
+	})
+	return found
+}
+
+func isAdvisoryLock(function string) bool {
+	name := strings.ToLower(function)
+	for _, prefix := range advisoryLockPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package cockroach
+
+import (
+	"strings"
+
+	pgbuilders "github.com/omniql-engine/omniql/engine/builders/postgres"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// UPSERT INTO
+// ============================================================================
+
+// BuildUpsertSQL creates UPSERT. Without conflict fields (UPSERT ... with no
+// ON, REPLACE) it is CockroachDB's UPSERT INTO, which inserts the row or
+// overwrites the one with the same primary key; with them it is
+// PostgreSQL's INSERT ... ON CONFLICT DO UPDATE.
+func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query.Upsert != nil {
+		return pgbuilders.BuildUpsertSQL(query)
+	}
+	sql, args := pgbuilders.BuildInsertSQL(query)
+	return upsertInto(sql), args
+}
+
+// upsertInto turns INSERT INTO ... into UPSERT INTO ...; the rest of the
+// statement (VALUES, SELECT, RETURNING) is the same
+func upsertInto(sql string) string {
+	if !strings.HasPrefix(sql, "INSERT INTO ") {
+		return sql
+	}
+	return "UPSERT" + strings.TrimPrefix(sql, "INSERT")
+}
@@ -0,0 +1,156 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// AGGREGATIONS (COUNT, SUM, AVG, MIN, MAX)
+// ============================================================================
+
+// BuildAggregation builds the _search body of an aggregate: no hits, the
+// query, and a metric aggregation named after the function
+// ("sum": {"sum": {"field": "price"}}). Each GROUP BY field is a terms
+// aggregation named after the field and nested in the one before it, so
+// the metric is computed per bucket; COUNT * reads the buckets' doc_count.
+// COUNT of a field is a value_count, and COUNT DISTINCT a cardinality,
+// which is approximate above a few thousand values.
+func BuildAggregation(query *pb.DocumentQuery, opts Options) (map[string]interface{}, error) {
+	if len(query.Having) > 0 {
+		return nil, mapping.NotSupported("Elasticsearch", "HAVING", mapping.SupportsOperation)
+	}
+	if query.Skip > 0 {
+		return nil, fmt.Errorf("Elasticsearch cannot skip the buckets of a terms aggregation: drop the OFFSET")
+	}
+	if query.Limit > 0 && len(query.GroupBy) == 0 {
+		return nil, fmt.Errorf("Elasticsearch cannot limit the documents an aggregate reads: narrow the WHERE instead")
+	}
+
+	body := map[string]interface{}{"size": 0}
+	if err := addQuery(body, query.Conditions); err != nil {
+		return nil, err
+	}
+
+	name, metric, err := buildMetric(query)
+	if err != nil {
+		return nil, err
+	}
+	aggs := map[string]interface{}{}
+	if metric != nil {
+		aggs[name] = metric
+	}
+
+	orders, err := bucketOrders(query, name)
+	if err != nil {
+		return nil, err
+	}
+	size := opts.termsSize()
+	if query.Limit > 0 {
+		size = int(query.Limit)
+	}
+	// Innermost field first: each terms aggregation wraps the ones after it
+	for i := len(query.GroupBy) - 1; i >= 0; i-- {
+		field, err := fieldName(query.GroupBy[i])
+		if err != nil {
+			return nil, fmt.Errorf("Elasticsearch groups on document fields, not %s", expressionText(query.GroupBy[i]))
+		}
+		terms := map[string]interface{}{"field": field, "size": size}
+		switch len(orders[i]) {
+		case 0:
+		case 1:
+			terms["order"] = orders[i][0]
+		default:
+			terms["order"] = orders[i]
+		}
+		bucket := map[string]interface{}{"terms": terms}
+		if len(aggs) > 0 {
+			bucket["aggs"] = aggs
+		}
+		aggs = map[string]interface{}{field: bucket}
+	}
+	if len(aggs) > 0 {
+		body["aggs"] = aggs
+	}
+	return body, nil
+}
+
+// MetricName is the name of an aggregate's metric aggregation, and the
+// column of its value in the result rows: count, sum, avg, min or max
+func MetricName(query *pb.DocumentQuery) string {
+	if query.Aggregate == nil {
+		return "count"
+	}
+	return strings.ToLower(query.Aggregate.Function)
+}
+
+// buildMetric returns the metric aggregation of query and its name. COUNT *
+// has none: every bucket counts its documents.
+func buildMetric(query *pb.DocumentQuery) (string, map[string]interface{}, error) {
+	name := MetricName(query)
+	field := getAggField(query.Aggregate)
+	if field == "" || field == "*" {
+		if name != "count" {
+			return "", nil, fmt.Errorf("%s requires a field", strings.ToUpper(name))
+		}
+		return name, nil, nil
+	}
+	if query.Aggregate.FieldExpr.Type != "FIELD" {
+		return "", nil, fmt.Errorf("Elasticsearch aggregates document fields, not %s", expressionText(query.Aggregate.FieldExpr))
+	}
+
+	kind := name
+	switch {
+	case name == "count" && query.Distinct:
+		kind = "cardinality"
+	case name == "count":
+		kind = "value_count"
+	case query.Distinct:
+		return "", nil, &mapping.ErrNotSupported{Database: "Elasticsearch", Feature: strings.ToUpper(name) + " DISTINCT"}
+	}
+	return name, map[string]interface{}{kind: map[string]interface{}{"field": field}}, nil
+}
+
+// bucketOrders returns the terms order of each GROUP BY field. ORDER BY a
+// group field orders its buckets by key; ORDER BY the metric (count, sum,
+// ...) orders the buckets of a single GROUP BY field by it.
+func bucketOrders(query *pb.DocumentQuery, metric string) ([][]interface{}, error) {
+	orders := make([][]interface{}, len(query.GroupBy))
+	if len(query.GroupBy) == 0 {
+		return orders, nil
+	}
+	for _, ob := range query.OrderBy {
+		direction := "asc"
+		if strings.ToUpper(ob.Direction) == "DESC" {
+			direction = "desc"
+		}
+		if ob.FieldExpr == nil || ob.FieldExpr.Type != "FIELD" {
+			return nil, fmt.Errorf("Elasticsearch orders buckets by key or by the aggregate, not %s", expressionText(ob.FieldExpr))
+		}
+		level := -1
+		for i, expr := range query.GroupBy {
+			if expr.Type == "FIELD" && expr.Value == ob.FieldExpr.Value {
+				level = i
+			}
+		}
+		if level >= 0 {
+			orders[level] = append(orders[level], map[string]interface{}{"_key": direction})
+			continue
+		}
+		if !strings.EqualFold(ob.FieldExpr.Value, metric) {
+			return nil, fmt.Errorf("Elasticsearch orders buckets by a GROUP BY field or by %s, not %s", metric, ob.FieldExpr.Value)
+		}
+		if len(query.GroupBy) > 1 {
+			return nil, fmt.Errorf("Elasticsearch orders by %s the buckets of one GROUP BY field only", metric)
+		}
+		path := metric
+		if field := getAggField(query.Aggregate); field == "" || field == "*" {
+			path = "_count"
+		}
+		orders[0] = append(orders[0], map[string]interface{}{path: direction})
+	}
+	return orders, nil
+}
@@ -0,0 +1,257 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// Elasticsearch is read through the Query DSL: a GET is a _search request
+// and an aggregate a _search with size 0 and aggregations (COUNT * alone is
+// a _count). Conditions become a bool query: AND-ed conditions are filter
+// clauses, which are cached and not scored, negations must_not clauses, and
+// OR a should clause. Only SEARCH is a scored (must) clause, so ORDER BY
+// RELEVANCE sorts on it.
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// isFunctionExpr checks if expr is a call to the named function
+func isFunctionExpr(expr *pb.Expression, name string) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == name
+}
+
+// fieldName returns the document field expr names; JSON paths arrive from
+// the translator as dotted FIELDs (data.address.city)
+func fieldName(expr *pb.Expression) (string, error) {
+	if expr == nil || expr.Type != "FIELD" || expr.Value == "" || expr.Value == "*" {
+		return "", fmt.Errorf("Elasticsearch queries document fields, not %s", expressionText(expr))
+	}
+	return expr.Value, nil
+}
+
+// expressionText renders expr for an error message
+func expressionText(expr *pb.Expression) string {
+	if expr == nil {
+		return "an empty expression"
+	}
+	switch expr.Type {
+	case "BINARY":
+		return expressionText(expr.Left) + " " + expr.Operator + " " + expressionText(expr.Right)
+	case "FUNCTION":
+		args := make([]string, len(expr.FunctionArgs))
+		for i, arg := range expr.FunctionArgs {
+			args[i] = expressionText(arg)
+		}
+		return strings.ToUpper(expr.FunctionName) + "(" + strings.Join(args, ", ") + ")"
+	case "CASEWHEN":
+		return "CASE"
+	case "STRING":
+		return `"` + expr.Value + `"`
+	default:
+		return expr.Value
+	}
+}
+
+// value converts a literal to its JSON value. Numbers keep their digits,
+// and NOW() is the date math "now". A bare word (status = active) is the
+// string "active"; true, false and null keep their JSON meaning.
+func value(expr *pb.Expression) (interface{}, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return json.Number(expr.Value), nil
+	case "BOOLEAN":
+		return strings.EqualFold(expr.Value, "true"), nil
+	case "STRING", "LITERAL":
+		return expr.Value, nil
+	case "FIELD":
+		switch strings.ToLower(expr.Value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return expr.Value, nil
+	}
+	if isFunctionExpr(expr, "NOW") && len(expr.FunctionArgs) == 0 {
+		return "now", nil
+	}
+	return nil, fmt.Errorf("Elasticsearch compares fields with values, not %s", expressionText(expr))
+}
+
+// values converts a list of literals (IN, ARRAY elements)
+func values(exprs []*pb.Expression) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(exprs))
+	for _, expr := range exprs {
+		v, err := value(expr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// REQUESTS
+// ============================================================================
+
+// BuildRequest returns the endpoint (_search or _count) and the body of the
+// request that runs query on its index
+func BuildRequest(query *pb.DocumentQuery, opts Options) (string, map[string]interface{}, error) {
+	switch strings.ToLower(query.Operation) {
+	case "search":
+		body, err := BuildSearch(query, opts)
+		return "_search", body, err
+	case "count", "sum", "avg", "min", "max":
+		if isDocumentCount(query) {
+			body, err := BuildCount(query)
+			return "_count", body, err
+		}
+		body, err := BuildAggregation(query, opts)
+		return "_search", body, err
+	default:
+		return "", nil, fmt.Errorf("unsupported Elasticsearch operation: %s", query.Operation)
+	}
+}
+
+// isDocumentCount reports whether query is COUNT * without GROUP BY, which
+// the _count API answers
+func isDocumentCount(query *pb.DocumentQuery) bool {
+	field := getAggField(query.Aggregate)
+	return strings.ToLower(query.Operation) == "count" && len(query.GroupBy) == 0 && (field == "" || field == "*")
+}
+
+// BuildSearch builds the _search body of a GET: the bool query, _source for
+// the selected fields, sort, size and from. DISTINCT collapses the hits on
+// the one selected field.
+func BuildSearch(query *pb.DocumentQuery, opts Options) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if err := addQuery(body, query.Conditions); err != nil {
+		return nil, err
+	}
+
+	fields, err := selectedFields(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		body["_source"] = fields
+	}
+	if query.Distinct {
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("Elasticsearch collapses hits on one field: GET DISTINCT needs exactly one field")
+		}
+		body["collapse"] = map[string]interface{}{"field": fields[0]}
+	}
+
+	if len(query.OrderBy) > 0 {
+		sort, err := BuildSort(query.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		body["sort"] = sort
+	}
+
+	from := int(query.Skip)
+	size := int(query.Limit)
+	if size == 0 {
+		size = opts.maxResultWindow() - from
+		if size <= 0 {
+			return nil, fmt.Errorf("OFFSET %d is past the %d hits Elasticsearch pages through: page with search_after instead", from, opts.maxResultWindow())
+		}
+	}
+	body["size"] = size
+	if from > 0 {
+		body["from"] = from
+	}
+	return body, nil
+}
+
+// selectedFields returns the fields a GET selects; nil selects the whole document
+func selectedFields(query *pb.DocumentQuery) ([]string, error) {
+	for _, col := range query.SelectColumns {
+		if col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW" {
+			return nil, &mapping.ErrNotSupported{Database: "Elasticsearch", Feature: "OVER"}
+		}
+		if col.Alias != "" || (col.ExpressionObj != nil && col.ExpressionObj.Type != "FIELD") {
+			return nil, fmt.Errorf("Elasticsearch returns the stored fields of a document: cannot select %s", expressionText(col.ExpressionObj))
+		}
+	}
+	var fields []string
+	for _, col := range query.Columns {
+		if col != nil && col.Type == "FIELD" && col.Value == "*" {
+			return nil, nil
+		}
+		name, err := fieldName(col)
+		if err != nil {
+			return nil, fmt.Errorf("Elasticsearch returns the stored fields of a document: cannot select %s", expressionText(col))
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// BuildSort builds the sort of a _search: [{"created_at": "desc"}, ...].
+// ORDER BY RELEVANCE sorts on _score.
+func BuildSort(orderBy []*pb.OrderByClause) ([]interface{}, error) {
+	var sort []interface{}
+	for _, ob := range orderBy {
+		direction := "asc"
+		if strings.ToUpper(ob.Direction) == "DESC" {
+			direction = "desc"
+		}
+		if ob.FieldExpr != nil && ob.FieldExpr.Type == "FIELD" && mapping.IsSearchRankField(ob.FieldExpr.Value) {
+			sort = append(sort, map[string]interface{}{"_score": direction})
+			continue
+		}
+		field, err := fieldName(ob.FieldExpr)
+		if err != nil {
+			return nil, fmt.Errorf("Elasticsearch sorts on document fields, not %s", expressionText(ob.FieldExpr))
+		}
+		sort = append(sort, map[string]interface{}{field: direction})
+	}
+	return sort, nil
+}
+
+// BuildCount builds the _count body of COUNT *: the query alone
+func BuildCount(query *pb.DocumentQuery) (map[string]interface{}, error) {
+	if query.Limit > 0 || query.Skip > 0 {
+		return nil, fmt.Errorf("Elasticsearch cannot limit the documents it counts: narrow the WHERE instead")
+	}
+	body := map[string]interface{}{}
+	if err := addQuery(body, query.Conditions); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// addQuery sets body's query when there are conditions; without, the
+// request matches every document
+func addQuery(body map[string]interface{}, conditions []*pb.QueryCondition) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+	q, err := BuildQuery(conditions)
+	if err != nil {
+		return err
+	}
+	body["query"] = q
+	return nil
+}
@@ -0,0 +1,36 @@
+package elasticsearch
+
+// Options are the per-client settings of the Elasticsearch builders. The zero
+// value suits an index with the default index.max_result_window.
+type Options struct {
+	// MaxResultWindow is the most hits a _search pages through (from + size),
+	// index.max_result_window on the index, 10000 when zero. A GET without
+	// LIMIT asks for all of them, as Elasticsearch returns only 10 hits by default.
+	MaxResultWindow int
+
+	// TermsSize is the number of buckets a GROUP BY field returns when the
+	// aggregate has no LIMIT, 10000 when zero
+	TermsSize int
+}
+
+// Defaults of the zero Options
+const (
+	defaultMaxResultWindow = 10000
+	defaultTermsSize       = 10000
+)
+
+// maxResultWindow returns MaxResultWindow, or its default when unset
+func (o Options) maxResultWindow() int {
+	if o.MaxResultWindow == 0 {
+		return defaultMaxResultWindow
+	}
+	return o.MaxResultWindow
+}
+
+// termsSize returns TermsSize, or its default when unset
+func (o Options) termsSize() int {
+	if o.TermsSize == 0 {
+		return defaultTermsSize
+	}
+	return o.TermsSize
+}
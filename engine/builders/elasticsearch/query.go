@@ -0,0 +1,368 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// BOOL QUERIES (WHERE)
+// ============================================================================
+
+// Occurrences of a clause in a bool query
+const (
+	occurFilter  = "filter"
+	occurMust    = "must"
+	occurMustNot = "must_not"
+)
+
+// boolQuery collects the clauses of one AND-ed run of conditions
+type boolQuery struct {
+	filter  []interface{}
+	must    []interface{}
+	mustNot []interface{}
+}
+
+// add adds clause; a filter that is itself a bool of filters (@>, ?&, an
+// AND-ed group) adds its clauses instead
+func (b *boolQuery) add(occur string, clause interface{}) {
+	if filters := filterClauses(clause); occur == occurFilter && filters != nil {
+		b.filter = append(b.filter, filters...)
+		return
+	}
+	switch occur {
+	case occurMust:
+		b.must = append(b.must, clause)
+	case occurMustNot:
+		b.mustNot = append(b.mustNot, clause)
+	default:
+		b.filter = append(b.filter, clause)
+	}
+}
+
+// filterClauses returns the clauses of {"bool": {"filter": [...]}}, or nil
+// when clause has other occurrences
+func filterClauses(clause interface{}) []interface{} {
+	q, ok := clause.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	clauses, ok := q["bool"].(map[string]interface{})
+	if !ok || len(clauses) != 1 {
+		return nil
+	}
+	filters, _ := clauses[occurFilter].([]interface{})
+	return filters
+}
+
+// query returns {"bool": {...}} with the occurrences that have clauses
+func (b *boolQuery) query() map[string]interface{} {
+	clauses := map[string]interface{}{}
+	if len(b.filter) > 0 {
+		clauses[occurFilter] = b.filter
+	}
+	if len(b.must) > 0 {
+		clauses[occurMust] = b.must
+	}
+	if len(b.mustNot) > 0 {
+		clauses[occurMustNot] = b.mustNot
+	}
+	return map[string]interface{}{"bool": clauses}
+}
+
+// clause returns the run as one clause of a should: its only clause when it
+// has one that is not negated, the whole bool query otherwise
+func (b *boolQuery) clause() interface{} {
+	if len(b.mustNot) == 0 && len(b.filter)+len(b.must) == 1 {
+		return append(b.filter, b.must...)[0]
+	}
+	return b.query()
+}
+
+// BuildQuery builds the bool query of a WHERE. AND binds tighter than OR, so
+// a = 1 AND b = 2 OR c = 3 is a should of [a = 1 AND b = 2] and [c = 3], of
+// which one must match. Parenthesized groups are nested bool queries.
+func BuildQuery(conditions []*pb.QueryCondition) (map[string]interface{}, error) {
+	runs := splitOr(conditions)
+	if len(runs) == 1 {
+		b, err := buildAnd(runs[0])
+		if err != nil {
+			return nil, err
+		}
+		return b.query(), nil
+	}
+	var should []interface{}
+	for _, run := range runs {
+		b, err := buildAnd(run)
+		if err != nil {
+			return nil, err
+		}
+		should = append(should, b.clause())
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{
+		"should":               should,
+		"minimum_should_match": 1,
+	}}, nil
+}
+
+// splitOr splits conditions into the runs of AND-ed conditions between ORs
+// The Logic of a condition joins it to the one before.
+func splitOr(conditions []*pb.QueryCondition) [][]*pb.QueryCondition {
+	var runs [][]*pb.QueryCondition
+	var run []*pb.QueryCondition
+	for i, cond := range conditions {
+		if i > 0 && strings.ToUpper(cond.Logic) == "OR" {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, cond)
+	}
+	return append(runs, run)
+}
+
+// buildAnd builds the clauses of one run of AND-ed conditions
+func buildAnd(run []*pb.QueryCondition) (*boolQuery, error) {
+	b := &boolQuery{}
+	for _, cond := range run {
+		if len(cond.Nested) > 0 {
+			nested, err := BuildQuery(cond.Nested)
+			if err != nil {
+				return nil, err
+			}
+			// A group holding a SEARCH keeps its score
+			occur := occurFilter
+			if hasSearch(cond.Nested) {
+				occur = occurMust
+			}
+			b.add(occur, nested)
+			continue
+		}
+		clause, occur, err := buildCondition(cond)
+		if err != nil {
+			return nil, err
+		}
+		b.add(occur, clause)
+	}
+	return b, nil
+}
+
+// hasSearch reports whether conditions hold a SEARCH (recursive)
+func hasSearch(conditions []*pb.QueryCondition) bool {
+	for _, cond := range conditions {
+		if cond.Operator == "SEARCH" || hasSearch(cond.Nested) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCondition builds the query clause of one condition and how it occurs
+// in its bool query: negated operators are must_not clauses of the positive
+// query, SEARCH is a scored must clause, and the rest are filters.
+func buildCondition(cond *pb.QueryCondition) (interface{}, string, error) {
+	if isFunctionExpr(cond.FieldExpr, "UNNEST") {
+		return nil, "", fmt.Errorf("Elasticsearch matches array fields element by element: compare the field itself")
+	}
+	if isFunctionExpr(cond.ValueExpr, "ANY") || isFunctionExpr(cond.ValueExpr, "ALL") {
+		clause, err := buildQuantifiedCondition(cond)
+		return clause, occurFilter, err
+	}
+	if _, ok := mapping.OperatorMap["Elasticsearch"][cond.Operator]; !ok {
+		return nil, "", mapping.NotSupported("Elasticsearch", cond.Operator, mapping.SupportsOperator)
+	}
+	field, err := fieldName(cond.FieldExpr)
+	if err != nil {
+		return nil, "", fmt.Errorf("Elasticsearch filters on document fields, not %s", expressionText(cond.FieldExpr))
+	}
+
+	switch cond.Operator {
+	case "=", "!=":
+		v, err := value(cond.ValueExpr)
+		if err != nil {
+			return nil, "", err
+		}
+		return map[string]interface{}{"term": map[string]interface{}{field: v}}, negated(cond.Operator == "!="), nil
+	case ">", ">=", "<", "<=":
+		v, err := value(cond.ValueExpr)
+		if err != nil {
+			return nil, "", err
+		}
+		bound := map[string]string{">": "gt", ">=": "gte", "<": "lt", "<=": "lte"}[cond.Operator]
+		return rangeQuery(field, map[string]interface{}{bound: v}), occurFilter, nil
+	case "BETWEEN", "NOT_BETWEEN":
+		low, err := value(cond.ValueExpr)
+		if err != nil {
+			return nil, "", err
+		}
+		high, err := value(cond.Value2Expr)
+		if err != nil {
+			return nil, "", err
+		}
+		return rangeQuery(field, map[string]interface{}{"gte": low, "lte": high}), negated(cond.Operator == "NOT_BETWEEN"), nil
+	case "IN", "NOT_IN":
+		vals, err := values(cond.ValuesExpr)
+		if err != nil {
+			return nil, "", err
+		}
+		return map[string]interface{}{"terms": map[string]interface{}{field: vals}}, negated(cond.Operator == "NOT_IN"), nil
+	case "LIKE", "NOT_LIKE", "ILIKE", "NOT_ILIKE":
+		return buildWildcard(field, cond)
+	case "IS_NULL", "IS_NOT_NULL":
+		return existsQuery(field), negated(cond.Operator == "IS_NULL"), nil
+	case "SEARCH":
+		v, err := value(cond.ValueExpr)
+		if err != nil {
+			return nil, "", err
+		}
+		return map[string]interface{}{"match": map[string]interface{}{field: map[string]interface{}{"query": v}}}, occurMust, nil
+	case "@>":
+		clause, err := buildContains(field, cond.ValueExpr)
+		return clause, occurFilter, err
+	case "?", "?|", "?&":
+		clause, err := buildKeyExists(field, cond)
+		return clause, occurFilter, err
+	default:
+		return nil, "", mapping.NotSupported("Elasticsearch", cond.Operator, mapping.SupportsOperator)
+	}
+}
+
+// negated returns the occurrence of a clause that is negated or not
+func negated(not bool) string {
+	if not {
+		return occurMustNot
+	}
+	return occurFilter
+}
+
+func rangeQuery(field string, bounds map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"range": map[string]interface{}{field: bounds}}
+}
+
+func existsQuery(field string) map[string]interface{} {
+	return map[string]interface{}{"exists": map[string]interface{}{"field": field}}
+}
+
+// buildWildcard builds LIKE and ILIKE as a wildcard query; ILIKE matches
+// case-insensitively
+func buildWildcard(field string, cond *pb.QueryCondition) (interface{}, string, error) {
+	if cond.ValueExpr == nil || (cond.ValueExpr.Type != "STRING" && cond.ValueExpr.Type != "FIELD") {
+		return nil, "", fmt.Errorf("%s takes a pattern string, not %s", strings.ReplaceAll(cond.Operator, "_", " "), expressionText(cond.ValueExpr))
+	}
+	query := map[string]interface{}{"value": wildcardPattern(cond.ValueExpr.Value)}
+	if strings.HasSuffix(cond.Operator, "ILIKE") {
+		query["case_insensitive"] = true
+	}
+	clause := map[string]interface{}{"wildcard": map[string]interface{}{field: query}}
+	return clause, negated(strings.HasPrefix(cond.Operator, "NOT_")), nil
+}
+
+// wildcardPattern converts a LIKE pattern: % is *, _ is ?, and the * and ?
+// of the pattern itself are escaped, as is a character escaped with \
+func wildcardPattern(like string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range like {
+		switch {
+		case escaped:
+			if r == '*' || r == '?' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteByte('*')
+		case r == '_':
+			b.WriteByte('?')
+		case r == '*' || r == '?':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if escaped {
+		b.WriteString(`\\`)
+	}
+	return b.String()
+}
+
+// buildContains builds tags @> ARRAY('a', 'b'). An array is a multi-valued
+// field, so it holds every element when a term on each matches.
+func buildContains(field string, expr *pb.Expression) (interface{}, error) {
+	elements := []*pb.Expression{expr}
+	if isFunctionExpr(expr, "ARRAY") {
+		elements = expr.FunctionArgs
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("@> requires at least one element")
+	}
+	vals, err := values(elements)
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) == 1 {
+		return map[string]interface{}{"term": map[string]interface{}{field: vals[0]}}, nil
+	}
+	var terms []interface{}
+	for _, v := range vals {
+		terms = append(terms, map[string]interface{}{"term": map[string]interface{}{field: v}})
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{occurFilter: terms}}, nil
+}
+
+// buildKeyExists builds the key operators on an object field: data ? 'trial'
+// is an exists on data.trial, ?| any of the keys and ?& all of them
+func buildKeyExists(field string, cond *pb.QueryCondition) (interface{}, error) {
+	keys := cond.ValuesExpr
+	if cond.Operator == "?" {
+		keys = []*pb.Expression{cond.ValueExpr}
+	}
+	if len(keys) == 0 || keys[0] == nil {
+		return nil, fmt.Errorf("%s requires at least one key", cond.Operator)
+	}
+	var clauses []interface{}
+	for _, key := range keys {
+		if key.Type != "STRING" && key.Type != "FIELD" {
+			return nil, fmt.Errorf("%s takes key names, not %s", cond.Operator, expressionText(key))
+		}
+		clauses = append(clauses, existsQuery(field+"."+key.Value))
+	}
+	switch {
+	case len(clauses) == 1:
+		return clauses[0], nil
+	case cond.Operator == "?|":
+		return map[string]interface{}{"bool": map[string]interface{}{"should": clauses, "minimum_should_match": 1}}, nil
+	default:
+		return map[string]interface{}{"bool": map[string]interface{}{occurFilter: clauses}}, nil
+	}
+}
+
+// buildQuantifiedCondition builds the two forms of = ANY a term query has:
+// 'go' = ANY(tags) is a term on the array field, and
+// status = ANY('a', 'b') is terms
+func buildQuantifiedCondition(cond *pb.QueryCondition) (interface{}, error) {
+	elements := cond.ValueExpr.FunctionArgs
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ANY" && cond.Operator == "=" && cond.FieldExpr != nil {
+		if cond.FieldExpr.Type != "FIELD" && len(elements) == 1 && elements[0].Type == "FIELD" {
+			v, err := value(cond.FieldExpr)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"term": map[string]interface{}{elements[0].Value: v}}, nil
+		}
+		if cond.FieldExpr.Type == "FIELD" {
+			vals, err := values(elements)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"terms": map[string]interface{}{cond.FieldExpr.Value: vals}}, nil
+		}
+	}
+	return nil, fmt.Errorf("Elasticsearch has %s %s %s only as value = ANY(array field) or field = ANY(values)",
+		expressionText(cond.FieldExpr), cond.Operator, expressionText(cond.ValueExpr))
+}
@@ -0,0 +1,130 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// RESPONSES (rows)
+// ============================================================================
+
+// Rows converts the response to a BuildRequest request into rows: one per
+// hit for a GET, one per bucket for a grouped aggregate, and a single row
+// otherwise
+func Rows(query *pb.DocumentQuery, endpoint string, response []byte) ([]map[string]interface{}, error) {
+	var decoded map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decode Elasticsearch response: %w", err)
+	}
+
+	switch {
+	case endpoint == "_count":
+		return []map[string]interface{}{{"count": jsonValue(decoded["count"])}}, nil
+	case query.Operation != "search":
+		return aggregationRows(query, object(decoded["aggregations"])), nil
+	default:
+		return hitRows(object(decoded["hits"])), nil
+	}
+}
+
+// hitRows returns each hit's _source with its _id
+func hitRows(hits map[string]interface{}) []map[string]interface{} {
+	list, _ := hits["hits"].([]interface{})
+	rows := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		hit := object(item)
+		row := map[string]interface{}{}
+		for field, v := range object(hit["_source"]) {
+			row[field] = jsonValue(v)
+		}
+		row["_id"] = hit["_id"]
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// aggregationRows walks the terms buckets down to the metric: each
+// innermost bucket is a row of its keys and the metric value
+func aggregationRows(query *pb.DocumentQuery, aggs map[string]interface{}) []map[string]interface{} {
+	var groupBy []string
+	for _, expr := range query.GroupBy {
+		groupBy = append(groupBy, expr.Value)
+	}
+	metric := MetricName(query)
+	countDocs := metric == "count" && (getAggField(query.Aggregate) == "" || getAggField(query.Aggregate) == "*")
+
+	var rows []map[string]interface{}
+	var walk func(level map[string]interface{}, fields []string, row map[string]interface{})
+	walk = func(level map[string]interface{}, fields []string, row map[string]interface{}) {
+		if len(fields) == 0 {
+			if countDocs {
+				row[metric] = jsonValue(level["doc_count"])
+			} else {
+				row[metric] = jsonValue(object(level[metric])["value"])
+			}
+			rows = append(rows, row)
+			return
+		}
+		buckets, _ := object(level[fields[0]])["buckets"].([]interface{})
+		for _, item := range buckets {
+			bucket := object(item)
+			next := make(map[string]interface{}, len(row)+1)
+			for k, v := range row {
+				next[k] = v
+			}
+			// Dates and booleans have their key as a string too
+			if key, ok := bucket["key_as_string"]; ok {
+				next[fields[0]] = key
+			} else {
+				next[fields[0]] = jsonValue(bucket["key"])
+			}
+			walk(bucket, fields[1:], next)
+		}
+	}
+	walk(aggs, groupBy, map[string]interface{}{})
+
+	// Every level returns up to LIMIT buckets
+	if query.Limit > 0 && len(rows) > int(query.Limit) {
+		rows = rows[:query.Limit]
+	}
+	return rows
+}
+
+// object returns v as a JSON object, or an empty one
+func object(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+// jsonValue converts the numbers of a decoded value to int64 when they are
+// whole and float64 otherwise
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = jsonValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,75 @@
+// Package builderutil holds the expression and query helpers the dialect
+// builders share. Anything that depends on a dialect's quoting or bind style
+// is passed in by the caller.
+package builderutil
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// ============================================================================
+// VALUE POSITIONS
+// ============================================================================
+
+// IsComputed reports whether a value-position expression is computed
+// (price * 2, NOW(), CASE, data->>'x') and must be rendered inline
+// A FIELD here is a bare word such as pending or CURRENT_TIMESTAMP; each
+// dialect decides whether it binds as a literal or stays a keyword.
+func IsComputed(expr *pb.Expression) bool {
+	return expr != nil && (expr.Type == "BINARY" || expr.Type == "FUNCTION" || expr.Type == "CASEWHEN" || expr.Type == "JSON_PATH")
+}
+
+// ============================================================================
+// RANGE BUCKETS
+// ============================================================================
+
+// IsRangeBucket reports whether a GROUP BY item is RANGE(field, bound, ...)
+func IsRangeBucket(expr *pb.Expression) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == "RANGE" && len(expr.FunctionArgs) > 2
+}
+
+// RangeBucketAlias names a RANGE bucket, the SQL column or the key in a MongoDB
+// compound _id: RANGE(amount, ...) = amount_range
+func RangeBucketAlias(expr *pb.Expression) string {
+	field := expr.FunctionArgs[0].Value
+	return field[strings.LastIndex(field, ".")+1:] + "_range"
+}
+
+// RangeBucketSQL renders RANGE(amount, 0, 100, 500) as the lower bound of
+// each row's bucket; rows outside every bucket group under NULL. field is the
+// bucketed column as the dialect writes it:
+// CASE WHEN amount >= 0 AND amount < 100 THEN 0 WHEN amount >= 100 AND amount < 500 THEN 100 END
+func RangeBucketSQL(expr *pb.Expression, field string) string {
+	bounds := expr.FunctionArgs[1:]
+	parts := []string{"CASE"}
+	for i := 0; i+1 < len(bounds); i++ {
+		parts = append(parts, fmt.Sprintf("WHEN %s >= %s AND %s < %s THEN %s",
+			field, bounds[i].Value, field, bounds[i+1].Value, bounds[i].Value))
+	}
+	return strings.Join(append(parts, "END"), " ")
+}
+
+// ============================================================================
+// CTE MEMBERS
+// ============================================================================
+
+// UnionMember narrows a join without a column list to its base table's columns
+// (categories JOIN tree selects categories.*), so the member lines up with the
+// anchor of a recursive CTE. The input is left untouched.
+func UnionMember(query *pb.RelationalQuery) *pb.RelationalQuery {
+	if query == nil || len(query.Joins) == 0 || !SelectsAll(query.Columns) {
+		return query
+	}
+	member := proto.Clone(query).(*pb.RelationalQuery)
+	member.Columns = []*pb.Expression{{Type: "FIELD", Value: query.Table + ".*"}}
+	return member
+}
+
+// SelectsAll reports whether a column list is empty or just *
+func SelectsAll(columns []*pb.Expression) bool {
+	return len(columns) == 0 || (len(columns) == 1 && columns[0] != nil && columns[0].Value == "*")
+}
@@ -5,13 +5,17 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"        
 	"go.mongodb.org/mongo-driver/mongo/readconcern"    
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"   
 
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 )
 
@@ -81,7 +85,7 @@ func getAggField(agg *pb.AggregateClause) string {
 // ============================================================================
 
 // BuildMongoFilter builds a BSON filter from conditions with full operator support
-func BuildMongoFilter(conditions []*pb.QueryCondition) bson.M {
+func BuildMongoFilter(conditions []*pb.QueryCondition, opts Options) bson.M {
 	if len(conditions) == 0 {
 		return bson.M{}
 	}
@@ -95,12 +99,12 @@ func BuildMongoFilter(conditions []*pb.QueryCondition) bson.M {
 	}
 
 	if hasOrLogic || hasNestedConditions(conditions) {
-		return buildFilterRecursive(conditions)
+		return buildFilterRecursive(conditions, opts)
 	}
 
 	filter := bson.M{}
 	for _, cond := range conditions {
-		addConditionToFilter(filter, cond)
+		addConditionToFilter(filter, cond, opts)
 	}
 	return filter
 }
@@ -114,7 +118,7 @@ func hasNestedConditions(conditions []*pb.QueryCondition) bool {
 	return false
 }
 
-func buildFilterRecursive(conditions []*pb.QueryCondition) bson.M {
+func buildFilterRecursive(conditions []*pb.QueryCondition, opts Options) bson.M {
 	if len(conditions) == 0 {
 		return bson.M{}
 	}
@@ -122,9 +126,9 @@ func buildFilterRecursive(conditions []*pb.QueryCondition) bson.M {
 	if len(conditions) == 1 {
 		cond := conditions[0]
 		if len(cond.Nested) > 0 {
-			return buildFilterRecursive(cond.Nested)
+			return buildFilterRecursive(cond.Nested, opts)
 		}
-		return buildSingleConditionFilter(cond)
+		return buildSingleConditionFilter(cond, opts)
 	}
 
 	var andGroups []bson.M
@@ -133,9 +137,9 @@ func buildFilterRecursive(conditions []*pb.QueryCondition) bson.M {
 	for i, cond := range conditions {
 		var condFilter bson.M
 		if len(cond.Nested) > 0 {
-			condFilter = buildFilterRecursive(cond.Nested)
+			condFilter = buildFilterRecursive(cond.Nested, opts)
 		} else {
-			condFilter = buildSingleConditionFilter(cond)
+			condFilter = buildSingleConditionFilter(cond, opts)
 		}
 
 		if i == 0 || cond.Logic == "" || cond.Logic == "AND" {
@@ -172,11 +176,11 @@ func buildFilterRecursive(conditions []*pb.QueryCondition) bson.M {
 	return bson.M{"$and": andGroups}
 }
 
-func buildSingleConditionFilter(cond *pb.QueryCondition) bson.M {
+func buildSingleConditionFilter(cond *pb.QueryCondition, opts Options) bson.M {
 	// TrueAST: Handle BINARY/FUNCTION expressions via AST traversal
 	if cond.FieldExpr != nil && (cond.FieldExpr.Type == "BINARY" || cond.FieldExpr.Type == "FUNCTION") {
-		leftExpr := BuildMongoExpressionFromAST(cond.FieldExpr)
-		rightValue := ParseMongoValue(cond.ValueExpr.Value)
+		leftExpr := BuildMongoExpressionFromAST(cond.FieldExpr, opts)
+		rightValue := parseMongoExprValue(cond.ValueExpr, opts)
 
 		var compOp string
 		switch cond.Operator {
@@ -201,7 +205,7 @@ func buildSingleConditionFilter(cond *pb.QueryCondition) bson.M {
 
 	// Legacy fallback for string-based expressions
 	if IsFieldExpression(field) {
-		return bson.M{"$expr": BuildExprCondition(cond)}
+		return bson.M{"$expr": BuildExprCondition(cond, opts)}
 	}
 
 	switch operator {
@@ -210,75 +214,163 @@ func buildSingleConditionFilter(cond *pb.QueryCondition) bson.M {
 	case "IS_NOT_NULL":
 		return bson.M{field: bson.M{"$ne": nil}}
 	case "$in":
-		values := exprSliceToStrings(cond.ValuesExpr)
-		return bson.M{field: bson.M{"$in": parseMongoValues(values)}}
+		return bson.M{field: bson.M{"$in": parseMongoValues(cond.ValuesExpr, opts)}}
 	case "$nin":
-		values := exprSliceToStrings(cond.ValuesExpr)
-		return bson.M{field: bson.M{"$nin": parseMongoValues(values)}}
+		return bson.M{field: bson.M{"$nin": parseMongoValues(cond.ValuesExpr, opts)}}
 	case "BETWEEN":
-		v1 := ParseMongoValue(cond.ValueExpr.Value)
-		v2 := ParseMongoValue(cond.Value2Expr.Value)
+		v1 := parseMongoExprValue(cond.ValueExpr, opts)
+		v2 := parseMongoExprValue(cond.Value2Expr, opts)
 		return bson.M{field: bson.M{"$gte": v1, "$lte": v2}}
 	case "NOT_BETWEEN":
-		v1 := ParseMongoValue(cond.ValueExpr.Value)
-		v2 := ParseMongoValue(cond.Value2Expr.Value)
+		v1 := parseMongoExprValue(cond.ValueExpr, opts)
+		v2 := parseMongoExprValue(cond.Value2Expr, opts)
 		return bson.M{"$or": bson.A{
 			bson.M{field: bson.M{"$lt": v1}},
 			bson.M{field: bson.M{"$gt": v2}},
 		}}
 	case "$eq":
-		return bson.M{field: ParseMongoValue(cond.ValueExpr.Value)}
+		return bson.M{field: parseMongoExprValue(cond.ValueExpr, opts)}
+	case "$text":
+		// $text searches the collection's text index, not a single field
+		return bson.M{"$text": bson.M{"$search": cond.ValueExpr.Value}}
 	case "$regex":
 		pattern := cond.ValueExpr.Value
 		pattern = strings.ReplaceAll(pattern, "%", ".*")
 		pattern = strings.ReplaceAll(pattern, "_", ".")
 		return bson.M{field: bson.M{"$regex": pattern, "$options": "i"}}
+	case "$near":
+		// $near needs a 2dsphere index and returns the closest documents first
+		return bson.M{field: bson.M{"$near": bson.M{
+			"$geometry":    buildGeoJSONPoint(cond.ValueExpr),
+			"$maxDistance": parseMongoExprValue(cond.Value2Expr, opts),
+		}}}
+	case "$geoWithin":
+		return bson.M{field: bson.M{"$geoWithin": bson.M{"$geometry": buildGeoJSONPolygon(cond.ValueExpr)}}}
 	default:
-		return bson.M{field: bson.M{operator: ParseMongoValue(cond.ValueExpr.Value)}}
+		return bson.M{field: bson.M{operator: parseMongoExprValue(cond.ValueExpr, opts)}}
 	}
 }
 
-// exprSliceToStrings extracts Value strings from Expression slice
-func exprSliceToStrings(exprs []*pb.Expression) []string {
-	result := make([]string, len(exprs))
-	for i, e := range exprs {
-		result[i] = e.Value
+// buildGeoJSONPoint converts POINT(lng, lat) to {type: "Point", coordinates: [lng, lat]}
+func buildGeoJSONPoint(point *pb.Expression) bson.M {
+	return bson.M{"type": "Point", "coordinates": geoCoordinates(point)}
+}
+
+// buildGeoJSONPolygon converts POLYGON(POINT(...), ...) to a single-ring GeoJSON polygon
+// The parser closes the ring, so the first and last points are the same.
+func buildGeoJSONPolygon(polygon *pb.Expression) bson.M {
+	ring := bson.A{}
+	for _, point := range polygon.FunctionArgs {
+		ring = append(ring, geoCoordinates(point))
 	}
-	return result
+	return bson.M{"type": "Polygon", "coordinates": bson.A{ring}}
+}
+
+// geoCoordinates returns [lng, lat] of a POINT expression
+func geoCoordinates(point *pb.Expression) bson.A {
+	coordinates := bson.A{}
+	for _, arg := range point.FunctionArgs {
+		value, _ := strconv.ParseFloat(arg.Value, 64)
+		coordinates = append(coordinates, value)
+	}
+	return coordinates
 }
 
-func parseMongoValues(values []string) bson.A {
+func parseMongoValues(values []*pb.Expression, opts Options) bson.A {
 	result := bson.A{}
 	for _, v := range values {
-		result = append(result, ParseMongoValue(v))
+		result = append(result, parseMongoExprValue(v, opts))
 	}
 	return result
 }
 
-func addConditionToFilter(filter bson.M, cond *pb.QueryCondition) {
-	singleFilter := buildSingleConditionFilter(cond)
+func addConditionToFilter(filter bson.M, cond *pb.QueryCondition, opts Options) {
+	singleFilter := buildSingleConditionFilter(cond, opts)
 	for k, v := range singleFilter {
 		filter[k] = v
 	}
 }
 
+// BuildMongoCollation returns the collation for COLLATE locale [STRENGTH n]
+// It applies to the whole operation: string comparisons in the filter and the sort.
+func BuildMongoCollation(clause *pb.CollationClause) *options.Collation {
+	if clause == nil || clause.Locale == "" {
+		return nil
+	}
+	return &options.Collation{Locale: clause.Locale, Strength: int(clause.Strength)}
+}
+
+// BuildMongoCollationDocument renders the collation as it appears in a command
+func BuildMongoCollationDocument(clause *pb.CollationClause) bson.M {
+	if clause == nil || clause.Locale == "" {
+		return nil
+	}
+	doc := bson.M{"locale": clause.Locale}
+	if clause.Strength > 0 {
+		doc["strength"] = clause.Strength
+	}
+	return doc
+}
+
 // ============================================================================
 // DOCUMENT BUILDING
 // ============================================================================
 
-func BuildMongoDocument(fields []*pb.QueryField) bson.M {
+func BuildMongoDocument(fields []*pb.QueryField, opts Options) bson.M {
 	document := bson.M{}
 	for _, field := range fields {
-		document[field.NameExpr.Value] = ParseMongoValue(field.ValueExpr.Value)
+		document[field.NameExpr.Value] = parseMongoExprValue(field.ValueExpr, opts)
+	}
+	return document
+}
+
+// ============================================================================
+// EXPIRY (TTL)
+// ============================================================================
+
+// BuildMongoExpireAt returns the expiry date of a document written now
+func BuildMongoExpireAt(ttlMs int64) primitive.DateTime {
+	return primitive.NewDateTimeFromTime(time.Now().Add(time.Duration(ttlMs) * time.Millisecond))
+}
+
+// BuildMongoTTLIndex returns the index that deletes expired documents
+// {expireAt: 1} (or the ExpireField option) with expireAfterSeconds: 0
+func BuildMongoTTLIndex(opts Options) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: opts.expireField(), Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+}
+
+// BuildMongoInsertDocument returns the document a CREATE inserts, with its
+// expiry date when it has a TTL
+func BuildMongoInsertDocument(query *pb.DocumentQuery, opts Options) bson.M {
+	document := BuildMongoDocument(query.Fields, opts)
+	if query.TtlMs > 0 {
+		document[opts.expireField()] = BuildMongoExpireAt(query.TtlMs)
 	}
 	return document
 }
 
+// BuildMongoReturningProjection projects the RETURNING fields of a findOneAndUpdate
+// or findOneAndDelete: nil for RETURNING *, otherwise the named fields, with _id
+// only when it is named, as SQL returns only the listed columns
+func BuildMongoReturningProjection(returning []*pb.Expression) bson.M {
+	projection := bson.M{"_id": 0}
+	for _, expr := range returning {
+		if expr == nil || expr.Value == "*" {
+			return nil
+		}
+		projection[expr.Value] = 1
+	}
+	return projection
+}
+
 // ============================================================================
 // UPDATE BUILDING - SIMPLE
 // ============================================================================
 
-func BuildMongoSimpleUpdate(fields []*pb.QueryField) bson.M {
+func BuildMongoSimpleUpdate(fields []*pb.QueryField, opts Options) bson.M {
 	update := bson.M{}
 	setFields := bson.M{}
 	incFields := bson.M{}
@@ -290,7 +382,7 @@ func BuildMongoSimpleUpdate(fields []*pb.QueryField) bson.M {
 		if field.ValueExpr != nil && field.ValueExpr.Type == "BINARY" {
 			expr := field.ValueExpr
 			if expr.Left != nil && expr.Left.Type == "FIELD" && expr.Right != nil && expr.Right.Type != "FIELD" {
-				rightValue := ParseMongoValue(expr.Right.Value)
+				rightValue := parseMongoExprValue(expr.Right, opts)
 				
 				switch expr.Operator {
 				case "+":
@@ -312,7 +404,7 @@ func BuildMongoSimpleUpdate(fields []*pb.QueryField) bson.M {
 				setFields[fieldName] = field.ValueExpr.Value
 			}
 		} else {
-			setFields[fieldName] = ParseMongoValue(field.ValueExpr.Value)
+			setFields[fieldName] = parseMongoExprValue(field.ValueExpr, opts)
 		}
 	}
 	
@@ -333,11 +425,124 @@ func BuildMongoSimpleUpdate(fields []*pb.QueryField) bson.M {
 	return update
 }
 
+// ============================================================================
+// BULK WRITE
+// ============================================================================
+
+// BuildMongoWriteModels converts a translated write into bulkWrite models
+// BULK INSERT gives one insert per row; reads and DDL cannot be batched.
+func BuildMongoWriteModels(query *pb.DocumentQuery, opts Options) ([]mongo.WriteModel, error) {
+	switch strings.ToLower(query.Operation) {
+	case "insertone":
+		return []mongo.WriteModel{mongo.NewInsertOneModel().SetDocument(BuildMongoInsertDocument(query, opts))}, nil
+	case "insertmany":
+		var writes []mongo.WriteModel
+		for _, row := range query.BulkData {
+			writes = append(writes, mongo.NewInsertOneModel().SetDocument(BuildMongoDocument(row.Fields, opts)))
+		}
+		return writes, nil
+	case "updateone":
+		if query.Upsert != nil {
+			return []mongo.WriteModel{buildUpsertWrite(query, opts)}, nil
+		}
+		update := mongo.NewUpdateOneModel().
+			SetFilter(BuildMongoFilter(query.Conditions, opts)).
+			SetUpdate(BuildMongoSimpleUpdate(query.Fields, opts))
+		if len(query.ArrayFilters) > 0 {
+			update.SetArrayFilters(options.ArrayFilters{Filters: BuildMongoArrayFilters(query.ArrayFilters, opts)})
+		}
+		return []mongo.WriteModel{update}, nil
+	case "replaceone":
+		return []mongo.WriteModel{mongo.NewReplaceOneModel().
+			SetFilter(BuildMongoFilter(query.Conditions, opts)).
+			SetReplacement(BuildMongoDocument(query.Fields, opts))}, nil
+	case "deleteone":
+		return []mongo.WriteModel{mongo.NewDeleteOneModel().SetFilter(BuildMongoFilter(query.Conditions, opts))}, nil
+	case "deletemany":
+		return []mongo.WriteModel{mongo.NewDeleteManyModel().SetFilter(BuildMongoFilter(query.Conditions, opts))}, nil
+	default:
+		return nil, fmt.Errorf("%s cannot be part of a bulk write", query.Operation)
+	}
+}
+
+// buildUpsertWrite is the upsert model of an UPSERT
+func buildUpsertWrite(query *pb.DocumentQuery, opts Options) mongo.WriteModel {
+	filter, update := BuildMongoUpsert(query, opts)
+	return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true)
+}
+
+// BuildMongoUpsert returns the filter and update of an UPSERT: it matches on
+// the conflict fields and sets the others (or the UPDATE SET list); the
+// matched values are stored on insert. A TTL sets a new expiry date either way.
+// DO NOTHING only writes on insert ($setOnInsert) and leaves a match as it is.
+func BuildMongoUpsert(query *pb.DocumentQuery, opts Options) (bson.M, bson.M) {
+	document := BuildMongoDocument(query.Fields, opts)
+	filter := bson.M{}
+	for _, field := range query.Upsert.ConflictFields {
+		filter[field.Value] = document[field.Value]
+	}
+
+	if query.Upsert.ConflictAction == "NOTHING" {
+		if query.TtlMs > 0 {
+			document[opts.expireField()] = BuildMongoExpireAt(query.TtlMs)
+		}
+		return filter, bson.M{"$setOnInsert": document}
+	}
+
+	setFields := bson.M{}
+	if len(query.Upsert.UpdateFields) > 0 {
+		for _, field := range query.Upsert.UpdateFields {
+			name := field.NameExpr.Value
+			if field.ValueExpr != nil {
+				setFields[name] = parseMongoExprValue(field.ValueExpr, opts)
+			} else {
+				setFields[name] = document[name]
+			}
+		}
+	} else {
+		for name, value := range document {
+			if _, ok := filter[name]; !ok {
+				setFields[name] = value
+			}
+		}
+	}
+	if query.TtlMs > 0 {
+		setFields[opts.expireField()] = BuildMongoExpireAt(query.TtlMs)
+	}
+
+	// Every inserted field is a conflict field: insert if missing, else leave it
+	update := bson.M{"$set": setFields}
+	if len(setFields) == 0 {
+		update = bson.M{"$setOnInsert": filter}
+	}
+	return filter, update
+}
+
+// BuildMongoArrayFilters groups array filter conditions by identifier, one
+// document each as arrayFilters requires: [{i.sku: 'x', i.qty: {$gt: 0}}, ...]
+func BuildMongoArrayFilters(conditions []*pb.QueryCondition, opts Options) []interface{} {
+	var names []string
+	groups := map[string][]*pb.QueryCondition{}
+	for _, cond := range conditions {
+		name, _, _ := strings.Cut(cond.FieldExpr.Value, ".")
+		if _, seen := groups[name]; !seen {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], cond)
+	}
+
+	var filters []interface{}
+	for _, name := range names {
+		filters = append(filters, BuildMongoFilter(groups[name], opts))
+	}
+	return filters
+}
+
 // ============================================================================
 // UPDATE BUILDING - PIPELINE
 // ============================================================================
 
-func BuildMongoPipelineUpdate(fields []*pb.QueryField) mongo.Pipeline {
+func BuildMongoPipelineUpdate(fields []*pb.QueryField, opts Options) mongo.Pipeline {
 	setStage := bson.M{}
 	
 	for _, field := range fields {
@@ -350,13 +555,13 @@ func BuildMongoPipelineUpdate(fields []*pb.QueryField) mongo.Pipeline {
 		
 		switch field.ValueExpr.Type {
 		case "BINARY":
-			result := BuildMongoBinaryExpression(field.ValueExpr)
+			result := BuildMongoBinaryExpression(field.ValueExpr, opts)
 			log.Printf("🔍 Field '%s' built as: %+v", fieldName, result)
 			setStage[fieldName] = result
 		case "FUNCTION":
-			setStage[fieldName] = BuildMongoFunctionExpression(field.ValueExpr)
+			setStage[fieldName] = BuildMongoFunctionExpression(field.ValueExpr, opts)
 		case "CASEWHEN":
-			setStage[fieldName] = BuildMongoCaseWhenExpression(field.ValueExpr)
+			setStage[fieldName] = BuildMongoCaseWhenExpression(field.ValueExpr, opts)
 		default:
 			setStage[fieldName] = field.ValueExpr.Value
 		}
@@ -370,19 +575,19 @@ func BuildMongoPipelineUpdate(fields []*pb.QueryField) mongo.Pipeline {
 }
 
 // BuildMongoExpressionFromAST converts TrueAST Expression to MongoDB expression
-func BuildMongoExpressionFromAST(expr *pb.Expression) interface{} {
+func BuildMongoExpressionFromAST(expr *pb.Expression, opts Options) interface{} {
 	if expr == nil {
 		return nil
 	}
 	switch expr.Type {
 	case "BINARY":
-		return BuildMongoBinaryExpression(expr)
+		return BuildMongoBinaryExpression(expr, opts)
 	case "FUNCTION":
-		return BuildMongoFunctionExpression(expr)
+		return BuildMongoFunctionExpression(expr, opts)
 	case "FIELD":
 		return "$" + expr.Value
 	case "LITERAL":
-		return ParseMongoValue(expr.Value)
+		return parseMongoExprValue(expr, opts)
 	default:
 		if expr.Value != "" {
 			return "$" + expr.Value
@@ -391,9 +596,9 @@ func BuildMongoExpressionFromAST(expr *pb.Expression) interface{} {
 	}
 }
 
-func BuildMongoBinaryExpression(expr *pb.Expression) interface{} {
-	leftValue := buildOperand(expr.Left)
-	rightValue := buildOperand(expr.Right)
+func BuildMongoBinaryExpression(expr *pb.Expression, opts Options) interface{} {
+	leftValue := buildOperand(expr.Left, opts)
+	rightValue := buildOperand(expr.Right, opts)
 	
 	switch expr.Operator {
 	case "+":
@@ -411,7 +616,7 @@ func BuildMongoBinaryExpression(expr *pb.Expression) interface{} {
 	}
 }
 
-func buildOperand(expr *pb.Expression) interface{} {
+func buildOperand(expr *pb.Expression, opts Options) interface{} {
 	if expr == nil {
 		return nil
 	}
@@ -419,71 +624,59 @@ func buildOperand(expr *pb.Expression) interface{} {
 	case "FIELD":
 		return "$" + expr.Value
 	case "BINARY":
-		return BuildMongoBinaryExpression(expr)
+		return BuildMongoBinaryExpression(expr, opts)
 	case "FUNCTION":
-		return BuildMongoFunctionExpression(expr)
+		return BuildMongoFunctionExpression(expr, opts)
 	case "LITERAL":
-		return ParseMongoValue(expr.Value)
+		return parseMongoExprValue(expr, opts)
 	default:
-		return ParseMongoValue(expr.Value)
+		return parseMongoExprValue(expr, opts)
 	}
 }
 
-func BuildMongoFunctionExpression(expr *pb.Expression) interface{} {
-	funcName := strings.ToUpper(expr.FunctionName)
-	
+// BuildMongoFunctionExpression writes a function call as the operator
+// mapping.FunctionMap gives it: {$op: arg}, {$op: [args]}, or {$op: {name: arg}}
+func BuildMongoFunctionExpression(expr *pb.Expression, opts Options) interface{} {
 	var args []interface{}
 	for _, arg := range expr.FunctionArgs {
-		if arg.Type == "FIELD" {
-			args = append(args, "$"+arg.Value)
-		} else {
-			args = append(args, ParseMongoValue(arg.Value))
-		}
+		args = append(args, buildOperand(arg, opts))
 	}
-	
-	switch funcName {
-	case "UPPER":
-		if len(args) > 0 {
-			return bson.M{"$toUpper": args[0]}
-		}
-	case "LOWER":
-		if len(args) > 0 {
-			return bson.M{"$toLower": args[0]}
-		}
-	case "CONCAT":
-		return bson.M{"$concat": args}
-	case "LENGTH":
-		if len(args) > 0 {
-			return bson.M{"$strLenCP": args[0]}
-		}
-	case "ABS":
+
+	spelling, ok := mapping.GetFunctionMapping("MongoDB", expr.FunctionName)
+	switch {
+	case !ok:
+		// No operator: the first argument as is
 		if len(args) > 0 {
-			return bson.M{"$abs": args[0]}
+			return args[0]
 		}
-	case "ROUND":
-		if len(args) > 0 {
-			return bson.M{"$round": bson.A{args[0], 0}}
+		return ""
+	case len(spelling.Args) > 0:
+		named := bson.M{}
+		for i, name := range spelling.Args {
+			if i < len(args) {
+				named[name] = args[i]
+			}
 		}
+		return bson.M{spelling.Name: named}
+	case len(args) == 1:
+		return bson.M{spelling.Name: args[0]}
+	default:
+		return bson.M{spelling.Name: args}
 	}
-	
-	if len(args) > 0 {
-		return args[0]
-	}
-	return ""
 }
 
-func BuildMongoCaseWhenExpression(expr *pb.Expression) interface{} {
+func BuildMongoCaseWhenExpression(expr *pb.Expression, opts Options) interface{} {
 	branches := bson.A{}
 	
 	for _, caseCondition := range expr.CaseConditions {
-		condExpr := ParseMongoConditionExpression(caseCondition.Condition)
+		condExpr := ParseMongoConditionExpression(caseCondition.Condition, opts)
 		
 		// Check if THEN is an expression or literal
 		var thenValue interface{}
 		if caseCondition.ThenExpr != nil && (caseCondition.ThenExpr.Type == "BINARY" || caseCondition.ThenExpr.Type == "FUNCTION") {
-			thenValue = BuildMongoExpressionFromAST(caseCondition.ThenExpr)
+			thenValue = BuildMongoExpressionFromAST(caseCondition.ThenExpr, opts)
 		} else {
-			thenValue = ParseMongoValue(caseCondition.ThenExpr.Value)
+			thenValue = parseMongoExprValue(caseCondition.ThenExpr, opts)
 		}
 		
 		branch := bson.M{
@@ -502,22 +695,22 @@ func BuildMongoCaseWhenExpression(expr *pb.Expression) interface{} {
 	if expr.CaseElse != nil {
 		// Check if ELSE is an expression or literal
 		if expr.CaseElse.Type == "BINARY" || expr.CaseElse.Type == "FUNCTION" {
-			switchExpr["default"] = BuildMongoExpressionFromAST(expr.CaseElse)
+			switchExpr["default"] = BuildMongoExpressionFromAST(expr.CaseElse, opts)
 		} else {
-			switchExpr["default"] = ParseMongoValue(expr.CaseElse.Value)
+			switchExpr["default"] = parseMongoExprValue(expr.CaseElse, opts)
 		}
 	}
 	
 	return bson.M{"$switch": switchExpr}
 }
 
-func ParseMongoConditionExpression(cond *pb.QueryCondition) interface{} {
+func ParseMongoConditionExpression(cond *pb.QueryCondition, opts Options) interface{} {
 	if cond == nil {
 		return true
 	}
 	
 	leftValue := "$" + cond.FieldExpr.Value
-	rightValue := ParseMongoValue(cond.ValueExpr.Value)
+	rightValue := parseMongoExprValue(cond.ValueExpr, opts)
 	
 	switch cond.Operator {
 	case ">=", "$gte":
@@ -537,14 +730,14 @@ func ParseMongoConditionExpression(cond *pb.QueryCondition) interface{} {
 	}
 }
 
-func BuildMongoProjectionExpression(expr *pb.Expression) interface{} {
+func BuildMongoProjectionExpression(expr *pb.Expression, opts Options) interface{} {
 	switch expr.Type {
 	case "BINARY":
-		return BuildMongoBinaryExpression(expr)
+		return BuildMongoBinaryExpression(expr, opts)
 	case "FUNCTION":
-		return BuildMongoFunctionExpression(expr)
+		return BuildMongoFunctionExpression(expr, opts)
 	case "CASEWHEN":
-		return BuildMongoCaseWhenExpression(expr)
+		return BuildMongoCaseWhenExpression(expr, opts)
 	default:
 		return nil
 	}
@@ -567,9 +760,9 @@ func IsFieldExpression(field string) bool {
 	return false
 }
 
-func BuildExprCondition(cond *pb.QueryCondition) interface{} {
-	leftExpr := ParseFieldExpression(cond.FieldExpr.Value)
-	rightValue := ParseMongoValue(cond.ValueExpr.Value)
+func BuildExprCondition(cond *pb.QueryCondition, opts Options) interface{} {
+	leftExpr := ParseFieldExpression(cond.FieldExpr.Value, opts)
+	rightValue := parseMongoExprValue(cond.ValueExpr, opts)
 	
 	var comparisonOp string
 	switch cond.Operator {
@@ -590,13 +783,13 @@ func BuildExprCondition(cond *pb.QueryCondition) interface{} {
 	return bson.M{comparisonOp: bson.A{leftExpr, rightValue}}
 }
 
-func ParseFieldExpression(fieldStr string) interface{} {
+func ParseFieldExpression(fieldStr string, opts Options) interface{} {
 	fieldStr = strings.TrimSpace(fieldStr)
 	
 	if strings.Contains(fieldStr, "(") && strings.Contains(fieldStr, ")") {
 		openIdx := strings.Index(fieldStr, "(")
 		if openIdx > 0 && IsLetter(rune(fieldStr[openIdx-1])) {
-			return ParseFunctionExpressionInWhere(fieldStr)
+			return ParseFunctionExpressionInWhere(fieldStr, opts)
 		}
 	}
 	
@@ -615,7 +808,7 @@ func ParseFieldExpression(fieldStr string) interface{} {
 				mongoOp = "$mod"
 			}
 			
-			return bson.M{mongoOp: bson.A{ParseFieldExpression(left), ParseFieldExpression(right)}}
+			return bson.M{mongoOp: bson.A{ParseFieldExpression(left, opts), ParseFieldExpression(right, opts)}}
 		}
 	}
 	
@@ -632,11 +825,11 @@ func ParseFieldExpression(fieldStr string) interface{} {
 				mongoOp = "$subtract"
 			}
 			
-			return bson.M{mongoOp: bson.A{ParseFieldExpression(left), ParseFieldExpression(right)}}
+			return bson.M{mongoOp: bson.A{ParseFieldExpression(left, opts), ParseFieldExpression(right, opts)}}
 		}
 	}
 	
-	val := ParseMongoValue(fieldStr)
+	val := ParseMongoValue(fieldStr, opts)
 	if _, ok := val.(int); ok {
 		return val
 	}
@@ -647,7 +840,7 @@ func ParseFieldExpression(fieldStr string) interface{} {
 	return "$" + fieldStr
 }
 
-func ParseFunctionExpressionInWhere(fieldStr string) interface{} {
+func ParseFunctionExpressionInWhere(fieldStr string, opts Options) interface{} {
 	openIdx := strings.Index(fieldStr, "(")
 	if openIdx == -1 {
 		return "$" + fieldStr
@@ -666,7 +859,7 @@ func ParseFunctionExpressionInWhere(fieldStr string) interface{} {
 		rawArgs := strings.Split(argsStr, ",")
 		for _, arg := range rawArgs {
 			arg = strings.TrimSpace(arg)
-			val := ParseMongoValue(arg)
+			val := ParseMongoValue(arg, opts)
 			if _, ok := val.(int); ok {
 				args = append(args, val)
 			} else if _, ok := val.(float64); ok {
@@ -710,19 +903,98 @@ func IsLetter(r rune) bool {
 // VALUE PARSING
 // ============================================================================
 
-func ParseMongoValue(value string) interface{} {
+// dateLayouts are the ISO 8601 forms read as dates; without a zone they are UTC
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// maxDoubleDigits is the number of significant digits a double always keeps
+const maxDoubleDigits = 15
+
+func ParseMongoValue(value string, opts Options) interface{} {
 	// Try parsing as int first (requires full string match)
 	if intVal, err := strconv.Atoi(value); err == nil {
 		return intVal
 	}
+	if !opts.UntypedValues {
+		if typed, ok := parseTypedValue(value); ok {
+			return typed
+		}
+	}
 	// Try parsing as float (requires full string match)
 	if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
 		return floatVal
 	}
-	// Return as string (preserves ObjectID strings)
+	// Return as string
 	return value
 }
 
+// parseMongoExprValue parses a literal expression. A quoted 'true', 'false' or
+// 'null' is a string; only the bare keywords are typed.
+func parseMongoExprValue(expr *pb.Expression, opts Options) interface{} {
+	if expr == nil {
+		return nil
+	}
+	if expr.Type == "STRING" {
+		switch strings.ToLower(expr.Value) {
+		case "true", "false", "null":
+			return expr.Value
+		}
+	}
+	return ParseMongoValue(expr.Value, opts)
+}
+
+// parseTypedValue converts a literal spelling a boolean, null, date, ObjectID
+// or high-precision number
+func parseTypedValue(value string) (interface{}, bool) {
+	switch strings.ToLower(value) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	if significantDigits(value) > maxDoubleDigits {
+		if decimal, err := primitive.ParseDecimal128(value); err == nil {
+			return decimal, true
+		}
+	}
+	if len(value) == 24 {
+		if id, err := primitive.ObjectIDFromHex(value); err == nil {
+			return id, true
+		}
+	}
+	if len(value) >= 10 && value[4] == '-' {
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return primitive.NewDateTimeFromTime(t), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// significantDigits counts the significant digits of a decimal number, or 0
+// when value is not one: 0.000123 has 3, 12345678901234567890 has 20
+func significantDigits(value string) int {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return 0
+	}
+	mantissa := strings.TrimLeft(value, "+-")
+	if i := strings.IndexAny(mantissa, "eE"); i >= 0 {
+		mantissa = mantissa[:i]
+	}
+	digits := strings.TrimLeft(strings.Replace(mantissa, ".", "", 1), "0")
+	if strings.Contains(mantissa, ".") {
+		digits = strings.TrimRight(digits, "0")
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0
+		}
+	}
+	return len(digits)
+}
+
 // ============================================================================
 // DCL OPERATIONS
 // ============================================================================
@@ -858,6 +1130,80 @@ func BuildRenameCollectionCommand(dbName, oldName, newName string) (bson.D, erro
 	}, nil
 }
 
+// BuildMongoIndexModel builds the index for CREATE INDEX
+// Columns are keyed ascending, descending when marked DESC (created_at DESC),
+// or "text" for FULLTEXT; UNIQUE, SPARSE, TTL seconds (expireAfterSeconds)
+// and PARTIAL WHERE (partialFilterExpression) become index options.
+func BuildMongoIndexModel(query *pb.DocumentQuery, opts Options) (mongo.IndexModel, error) {
+	if len(query.Fields) == 0 || query.Fields[0].NameExpr == nil || query.Fields[0].ValueExpr == nil {
+		return mongo.IndexModel{}, fmt.Errorf("no index details specified")
+	}
+	field := query.Fields[0]
+	indexOpts := options.Index().SetName(field.NameExpr.Value)
+
+	var direction interface{} = 1
+	constraints := field.Constraints
+	for i := 0; i < len(constraints); i++ {
+		switch strings.ToUpper(constraints[i]) {
+		case "UNIQUE":
+			indexOpts.SetUnique(true)
+		case "SPARSE":
+			indexOpts.SetSparse(true)
+		case "FULLTEXT":
+			direction = "text"
+		case "TTL":
+			// Followed by the seconds: TTL, 3600
+			if i+1 < len(constraints) {
+				i++
+				seconds, err := strconv.Atoi(constraints[i])
+				if err != nil {
+					return mongo.IndexModel{}, fmt.Errorf("invalid TTL seconds: %s", constraints[i])
+				}
+				indexOpts.SetExpireAfterSeconds(int32(seconds))
+			}
+		case "PARTIAL":
+			indexOpts.SetPartialFilterExpression(BuildMongoFilter(query.Conditions, opts))
+		}
+	}
+
+	keys := bson.D{}
+	for _, column := range strings.Split(field.ValueExpr.Value, ",") {
+		column = strings.TrimSpace(column)
+		var key interface{} = direction
+		if name, desc := strings.CutSuffix(column, " DESC"); desc && direction == 1 {
+			column, key = strings.TrimSpace(name), -1
+		}
+		keys = append(keys, bson.E{Key: column, Value: key})
+	}
+	return mongo.IndexModel{Keys: keys, Options: indexOpts}, nil
+}
+
+// BuildCreateIndexCommand renders the createIndexes command for an index model
+func BuildCreateIndexCommand(collection string, model mongo.IndexModel) bson.D {
+	spec := bson.D{{Key: "key", Value: model.Keys}}
+	if opts := model.Options; opts != nil {
+		if opts.Name != nil {
+			spec = append(spec, bson.E{Key: "name", Value: *opts.Name})
+		}
+		if opts.Unique != nil {
+			spec = append(spec, bson.E{Key: "unique", Value: *opts.Unique})
+		}
+		if opts.Sparse != nil {
+			spec = append(spec, bson.E{Key: "sparse", Value: *opts.Sparse})
+		}
+		if opts.ExpireAfterSeconds != nil {
+			spec = append(spec, bson.E{Key: "expireAfterSeconds", Value: *opts.ExpireAfterSeconds})
+		}
+		if opts.PartialFilterExpression != nil {
+			spec = append(spec, bson.E{Key: "partialFilterExpression", Value: opts.PartialFilterExpression})
+		}
+	}
+	return bson.D{
+		{Key: "createIndexes", Value: collection},
+		{Key: "indexes", Value: bson.A{spec}},
+	}
+}
+
 func BuildCreateViewCommand(viewName, collectionName string) (bson.D, error) {
 	if viewName == "" {
 		return nil, fmt.Errorf("view name required for CREATE VIEW")
@@ -872,6 +1218,89 @@ func BuildCreateViewCommand(viewName, collectionName string) (bson.D, error) {
 	}, nil
 }
 
+// BuildMongoJSONSchema builds a createCollection validator from CREATE TABLE columns
+// name:STRING(50):NOT_NULL = required, {bsonType: "string", maxLength: 50};
+// ENUM('a', 'b') = {enum: [...]}; TYPE[] = {bsonType: "array", items: {...}}.
+// Optional columns also accept null. Returns nil when no column can be validated.
+func BuildMongoJSONSchema(fields []*pb.QueryField) bson.M {
+	properties := bson.M{}
+	required := bson.A{}
+	for _, field := range fields {
+		if field.NameExpr == nil || field.ValueExpr == nil {
+			continue
+		}
+		name := field.NameExpr.Value
+		property := buildJSONSchemaProperty(field.ValueExpr.Value)
+		if property == nil {
+			continue
+		}
+
+		notNull := false
+		for _, constraint := range field.Constraints {
+			switch strings.ToUpper(constraint) {
+			case "NOT_NULL", "NOTNULL", "PRIMARY_KEY":
+				notNull = true
+			}
+		}
+		if notNull {
+			required = append(required, name)
+		} else if bsonType, ok := property["bsonType"].(bson.A); ok {
+			property["bsonType"] = append(bsonType, "null")
+		} else if values, ok := property["enum"].(bson.A); ok {
+			property["enum"] = append(values, nil)
+		}
+		properties[name] = property
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+
+	schema := bson.M{"bsonType": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return bson.M{"$jsonSchema": schema}
+}
+
+// buildJSONSchemaProperty maps one column type (STRING(50), ENUM('a'), INT[]) to a property
+func buildJSONSchemaProperty(columnType string) bson.M {
+	if mapping.IsArrayType(columnType) {
+		items := buildJSONSchemaProperty(mapping.ArrayElementType(columnType))
+		if items == nil {
+			return nil
+		}
+		return bson.M{"bsonType": bson.A{"array"}, "items": items}
+	}
+
+	base, params, _ := strings.Cut(columnType, "(")
+	base = strings.ToUpper(strings.TrimSpace(base))
+	params = strings.TrimSuffix(strings.TrimSpace(params), ")")
+
+	if base == "ENUM" {
+		values := bson.A{}
+		for _, value := range strings.Split(params, ",") {
+			values = append(values, strings.Trim(strings.TrimSpace(value), `'"`))
+		}
+		return bson.M{"enum": values}
+	}
+
+	aliases, ok := mapping.JSONSchemaTypeMap[base]
+	if !ok {
+		return nil
+	}
+	bsonType := bson.A{}
+	for _, alias := range aliases {
+		bsonType = append(bsonType, alias)
+	}
+	property := bson.M{"bsonType": bsonType}
+	if (base == "STRING" || base == "CHAR") && params != "" {
+		if maxLength, err := strconv.Atoi(params); err == nil {
+			property["maxLength"] = maxLength
+		}
+	}
+	return property
+}
+
 func ExtractCollectionNameFromQuery(viewQuery string) (string, error) {
 	if viewQuery == "" {
 		return "", fmt.Errorf("viewQuery is empty")
@@ -891,33 +1320,191 @@ func ExtractCollectionNameFromQuery(viewQuery string) (string, error) {
 	return strings.ToLower(words[0]), nil
 }
 
+// ============================================================================
+// WRITE FROM QUERY ($out / $merge)
+// ============================================================================
+
+// mergeWhenMatched maps a write that reads from a query to $merge's whenMatched:
+// CREATE fails on an existing _id like INSERT ... SELECT, REPLACE swaps the
+// document and UPSERT merges the new fields into it
+var mergeWhenMatched = map[string]string{
+	"insertone":  "fail",
+	"replaceone": "replace",
+	"updateone":  "merge",
+}
+
+// WritesFromQuery reports whether a write takes its documents from a GET:
+// CREATE TABLE ... AS GET, or CREATE / REPLACE / UPSERT ... FROM GET
+func WritesFromQuery(query *pb.DocumentQuery) bool {
+	if query.ViewQuery == nil {
+		return false
+	}
+	operation := strings.ToLower(query.Operation)
+	return operation == "createcollection" || mergeWhenMatched[operation] != ""
+}
+
+// BuildMongoWriteFromPipeline builds the pipeline run on the source collection:
+// the GET's stages, then $out for CREATE TABLE ... AS (the collection is
+// replaced) or $merge into the collection for CREATE / REPLACE / UPSERT ... FROM
+func BuildMongoWriteFromPipeline(query *pb.DocumentQuery, opts Options) ([]bson.M, error) {
+	if !WritesFromQuery(query) {
+		return nil, fmt.Errorf("%s does not read from a query", query.Operation)
+	}
+	pipeline, err := buildSourceStages(query.ViewQuery, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := strings.ToLower(query.Operation)
+	if operation == "createcollection" {
+		return append(pipeline, bson.M{"$out": query.Collection}), nil
+	}
+	return append(pipeline, bson.M{"$merge": bson.M{
+		"into":           query.Collection,
+		"whenMatched":    mergeWhenMatched[operation],
+		"whenNotMatched": "insert",
+	}}), nil
+}
+
+// buildSourceStages renders the source GET as pipeline stages
+func buildSourceStages(source *pb.DocumentQuery, opts Options) ([]bson.M, error) {
+	switch strings.ToLower(source.Operation) {
+	case "find":
+		if IsGroupedSelect(source) {
+			return BuildMongoDBGroupedPipeline(source, opts)
+		}
+		if NeedsSelectPipeline(source) {
+			return BuildMongoDBSelectPipeline(source, opts)
+		}
+		pipeline := []bson.M{}
+		if len(source.Conditions) > 0 {
+			pipeline = append(pipeline, BuildMongoDBMatchStage(source.Conditions, opts))
+		}
+		if len(source.OrderBy) > 0 {
+			pipeline = append(pipeline, BuildMongoDBSortStage(source.OrderBy))
+		}
+		if source.Skip > 0 {
+			pipeline = append(pipeline, bson.M{"$skip": source.Skip})
+		}
+		if source.Limit > 0 {
+			pipeline = append(pipeline, bson.M{"$limit": source.Limit})
+		}
+		if projection := BuildMongoFindProjection(source); projection != nil {
+			pipeline = append(pipeline, bson.M{"$project": projection})
+		}
+		return pipeline, nil
+	case "lookup":
+		return BuildMongoDBJoinPipeline(source, opts), nil
+	case "count", "sum", "avg", "min", "max", "string_agg", "group":
+		return BuildMongoDBAggregatePipeline(source, opts), nil
+	default:
+		return nil, fmt.Errorf("%s cannot be used as a source query", source.Operation)
+	}
+}
+
+
+// ============================================================================
+// CHANGE STREAMS
+// ============================================================================
+
+// changeOperations maps change stream operation types to Watch operations;
+// other events (drop, rename, invalidate) are not delivered
+var changeOperations = map[string]string{
+	"insert":  "INSERT",
+	"update":  "UPDATE",
+	"replace": "UPDATE",
+	"delete":  "DELETE",
+}
+
+// ChangeOperation returns the Watch operation of a change stream event type
+func ChangeOperation(operationType string) string {
+	return changeOperations[operationType]
+}
+
+// BuildChangeStreamPipeline builds the $match of a Watch change stream
+// Conditions apply to the document after the change. Deletes carry only the
+// document key, so they cannot be filtered and are always delivered.
+func BuildChangeStreamPipeline(conditions []*pb.QueryCondition, opts Options) []bson.M {
+	match := bson.M{"operationType": bson.M{"$in": []string{"insert", "update", "replace", "delete"}}}
+	if len(conditions) > 0 {
+		match["$or"] = []bson.M{
+			{"operationType": "delete"},
+			prefixFilterFields(BuildMongoFilter(conditions, opts), false).(bson.M),
+		}
+	}
+	return []bson.M{{"$match": match}}
+}
+
+// prefixFilterFields moves a filter onto the change event's fullDocument:
+// field names gain the prefix, and so do $field references inside $expr
+func prefixFilterFields(value interface{}, inExpr bool) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		prefixed := bson.M{}
+		for key, val := range v {
+			switch {
+			case strings.HasPrefix(key, "$"):
+				prefixed[key] = prefixFilterFields(val, inExpr || key == "$expr")
+			case inExpr:
+				prefixed[key] = prefixFilterFields(val, true)
+			default:
+				prefixed["fullDocument."+key] = val
+			}
+		}
+		return prefixed
+	case []bson.M:
+		prefixed := make([]bson.M, len(v))
+		for i, item := range v {
+			prefixed[i] = prefixFilterFields(item, inExpr).(bson.M)
+		}
+		return prefixed
+	case []interface{}:
+		prefixed := make([]interface{}, len(v))
+		for i, item := range v {
+			prefixed[i] = prefixFilterFields(item, inExpr)
+		}
+		return prefixed
+	case string:
+		if inExpr && strings.HasPrefix(v, "$") && !strings.HasPrefix(v, "$$") {
+			return "$fullDocument." + v[1:]
+		}
+		return v
+	default:
+		return value
+	}
+}
+
 // ============================================================================
 // DQL OPERATIONS
 // ============================================================================
 
-func BuildMongoDBJoinPipeline(query *pb.DocumentQuery) []bson.M {
+func BuildMongoDBJoinPipeline(query *pb.DocumentQuery, opts Options) []bson.M {
 	pipeline := []bson.M{}
 
+	// Each join can use the collections joined before it
+	paths := fieldPaths{collection: query.Collection}
 	for _, join := range query.Joins {
+		localField, foreignField := paths.joinFields(join)
 		lookupStage := bson.M{
 			"$lookup": bson.M{
 				"from":         join.Table,
-				"localField":   ExtractFieldName(join.LeftExpr.Value),
-				"foreignField": ExtractFieldName(join.RightExpr.Value),
-				"as":           join.Table + "_joined",
+				"localField":   localField,
+				"foreignField": foreignField,
+				"as":           joinedField(join.Table),
 			},
 		}
 		pipeline = append(pipeline, lookupStage)
 
 		if strings.ToLower(join.JoinType) == "inner" || strings.ToLower(join.JoinType) == "inner_join" {
-			pipeline = append(pipeline, bson.M{"$unwind": bson.M{"path": "$" + join.Table + "_joined"}})
+			pipeline = append(pipeline, bson.M{"$unwind": bson.M{"path": "$" + joinedField(join.Table)}})
 		} else {
-			pipeline = append(pipeline, bson.M{"$unwind": bson.M{"path": "$" + join.Table + "_joined", "preserveNullAndEmptyArrays": true}})
+			pipeline = append(pipeline, bson.M{"$unwind": bson.M{"path": "$" + joinedField(join.Table), "preserveNullAndEmptyArrays": true}})
 		}
+		paths.joined = append(paths.joined, join.Table)
 	}
 
 	if len(query.Conditions) > 0 {
-		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions))
+		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions, opts))
 	}
 	if len(query.OrderBy) > 0 {
 		pipeline = append(pipeline, BuildMongoDBSortStage(query.OrderBy))
@@ -932,9 +1519,8 @@ func BuildMongoDBJoinPipeline(query *pb.DocumentQuery) []bson.M {
 	if len(query.Columns) > 0 {
 		projection := bson.M{}
 		for _, col := range query.Columns {
-			parts := strings.Split(col.Value, ".")
-			if len(parts) == 2 {
-				projection[parts[1]] = 1
+			if col.Value != "*" {
+				projection[col.Value] = 1
 			}
 		}
 		if len(projection) > 0 {
@@ -945,15 +1531,98 @@ func BuildMongoDBJoinPipeline(query *pb.DocumentQuery) []bson.M {
 	return pipeline
 }
 
-func BuildMongoDBAggregatePipeline(query *pb.DocumentQuery) []bson.M {
+// BuildGraphLookupPipeline walks a recursive CTE with $graphLookup
+// Each anchor document collects its descendants under the CTE name; the anchor
+// and its descendants are then unwound into one document per CTE row, which the
+// main query filters, sorts and pages.
+func BuildGraphLookupPipeline(query *pb.DocumentQuery, opts Options) []bson.M {
+	lookup := query.GraphLookup
+	pipeline := []bson.M{}
+
+	if len(query.Conditions) > 0 {
+		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions, opts))
+	}
+
+	graphLookup := bson.M{
+		"from":             lookup.From,
+		"startWith":        "$" + lookup.StartWith,
+		"connectFromField": lookup.ConnectFromField,
+		"connectToField":   lookup.ConnectToField,
+		"as":               lookup.CteName,
+	}
+	if lookup.MaxDepth > 0 {
+		// maxDepth 0 already stops at the anchor's direct children
+		graphLookup["maxDepth"] = lookup.MaxDepth - 1
+	}
+	rows := "$" + lookup.CteName
+	pipeline = append(pipeline,
+		bson.M{"$graphLookup": graphLookup},
+		bson.M{"$set": bson.M{lookup.CteName: bson.M{"$concatArrays": bson.A{bson.A{"$$ROOT"}, rows}}}},
+		bson.M{"$unwind": rows},
+		bson.M{"$replaceRoot": bson.M{"newRoot": rows}},
+		bson.M{"$unset": lookup.CteName},
+	)
+
+	if main := lookup.MainQuery; main != nil {
+		if len(main.Conditions) > 0 {
+			pipeline = append(pipeline, BuildMongoDBMatchStage(main.Conditions, opts))
+		}
+		if len(main.OrderBy) > 0 {
+			pipeline = append(pipeline, BuildMongoDBSortStage(main.OrderBy))
+		}
+		if main.Skip > 0 {
+			pipeline = append(pipeline, bson.M{"$skip": main.Skip})
+		}
+		if main.Limit > 0 {
+			pipeline = append(pipeline, bson.M{"$limit": main.Limit})
+		}
+	}
+	return pipeline
+}
+
+func BuildMongoDBAggregatePipeline(query *pb.DocumentQuery, opts Options) []bson.M {
 	pipeline := []bson.M{}
 
 	if len(query.Conditions) > 0 {
-		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions))
+		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions, opts))
 	}
+	if len(query.Facets) > 0 {
+		return append(pipeline, BuildMongoDBFacetStage(query, opts))
+	}
+	return append(pipeline, buildAggregateStages(query, opts)...)
+}
+
+// BuildMongoDBFacetStage runs the main aggregate and every FACET over the same
+// matched documents in one $facet stage. The main aggregate's pipeline is the
+// "result" facet; each named facet groups on its own.
+func BuildMongoDBFacetStage(query *pb.DocumentQuery, opts Options) bson.M {
+	facets := bson.M{"result": buildAggregateStages(query, opts)}
+	for _, facet := range query.Facets {
+		stages := []bson.M{}
+		// STRING AGG order: documents reach $push in sorted order
+		if len(facet.Aggregate.OrderBy) > 0 {
+			stages = append(stages, BuildMongoDBSortStage(facet.Aggregate.OrderBy))
+		}
+		stages = append(stages, buildGroupStage(facet.GroupBy, facet.Aggregate, false, opts))
+		if strings.ToLower(facet.Aggregate.Function) == "string_agg" {
+			stages = append(stages, buildStringAggProjectStage(facet.Aggregate.Separator))
+		}
+		facets[facet.Name] = stages
+	}
+	return bson.M{"$facet": facets}
+}
+
+// buildAggregateStages builds the stages after $match for the main aggregate
+func buildAggregateStages(query *pb.DocumentQuery, opts Options) []bson.M {
+	pipeline := []bson.M{}
+
 	if len(query.GroupBy) == 0 && len(query.OrderBy) > 0 {
 		pipeline = append(pipeline, BuildMongoDBSortStage(query.OrderBy))
 	}
+	// STRING AGG order: documents reach $push in sorted order
+	if query.Aggregate != nil && len(query.Aggregate.OrderBy) > 0 {
+		pipeline = append(pipeline, BuildMongoDBSortStage(query.Aggregate.OrderBy))
+	}
 	if query.Skip > 0 {
 		pipeline = append(pipeline, bson.M{"$skip": query.Skip})
 	}
@@ -962,23 +1631,41 @@ func BuildMongoDBAggregatePipeline(query *pb.DocumentQuery) []bson.M {
 	}
 
 	if query.Aggregate != nil {
-		pipeline = append(pipeline, BuildMongoDBGroupStage(query))
-		
-		aggField := query.Aggregate.FieldExpr.Value
-		if query.Distinct && aggField != "" {
+		// HAVING names the aggregate ("result"), a GROUP BY field or another
+		// aggregate, which is computed alongside
+		aggField := getAggField(query.Aggregate)
+		outputs := newGroupOutputs()
+		outputs.byAgg[aggregateKey(query.Aggregate.Function, aggField)] = "result"
+		for name, path := range groupKeyPaths(query.GroupBy) {
+			outputs.fields[name] = path
+		}
+		having := outputs.havingConditions(query.Having)
+
+		groupStage := BuildMongoDBGroupStage(query, opts)
+		addGroupAccumulators(groupStage, outputs.extra)
+		pipeline = append(pipeline, groupStage)
+
+		var project bson.M
+		if strings.ToLower(query.Aggregate.Function) == "string_agg" {
+			project = buildStringAggProjectStage(query.Aggregate.Separator)
+		} else if query.Distinct && aggField != "" {
 			aggFunc := strings.ToLower(query.Aggregate.Function)
 			if aggFunc == "count" {
-				pipeline = append(pipeline, bson.M{"$project": bson.M{"_id": "$_id", "result": bson.M{"$size": "$result"}}})
+				project = bson.M{"$project": bson.M{"_id": "$_id", "result": bson.M{"$size": "$result"}}}
 			} else if aggFunc == "sum" {
-				pipeline = append(pipeline, bson.M{"$project": bson.M{"_id": "$_id", "result": bson.M{"$sum": "$result"}}})
+				project = bson.M{"$project": bson.M{"_id": "$_id", "result": bson.M{"$sum": "$result"}}}
 			} else if aggFunc == "avg" {
-				pipeline = append(pipeline, bson.M{"$project": bson.M{"_id": "$_id", "result": bson.M{"$avg": "$result"}}})
+				project = bson.M{"$project": bson.M{"_id": "$_id", "result": bson.M{"$avg": "$result"}}}
 			}
 		}
-
-		if len(query.Having) > 0 {
-			pipeline = append(pipeline, BuildMongoDBHavingStage(query.Having))
+		if project != nil {
+			for _, name := range outputs.hidden {
+				project["$project"].(bson.M)[name] = 1
+			}
+			pipeline = append(pipeline, project)
 		}
+
+		pipeline = append(pipeline, outputs.havingStages(having, opts)...)
 		if len(query.GroupBy) > 0 && len(query.OrderBy) > 0 {
 			pipeline = append(pipeline, BuildMongoDBSortStage(query.OrderBy))
 		}
@@ -989,15 +1676,23 @@ func BuildMongoDBAggregatePipeline(query *pb.DocumentQuery) []bson.M {
 	return pipeline
 }
 
-func BuildMongoDBGroupStage(query *pb.DocumentQuery) bson.M {
+func BuildMongoDBGroupStage(query *pb.DocumentQuery, opts Options) bson.M {
+	return buildGroupStage(query.GroupBy, query.Aggregate, query.Distinct, opts)
+}
+
+func buildGroupStage(groupBy []*pb.Expression, aggregate *pb.AggregateClause, distinct bool, opts Options) bson.M {
 	groupID := interface{}(nil)
 
-	if len(query.GroupBy) > 0 {
-		if len(query.GroupBy) == 1 {
-			groupID = "$" + query.GroupBy[0].Value
+	if len(groupBy) > 0 {
+		if len(groupBy) == 1 {
+			groupID = "$" + groupBy[0].Value
 		} else {
 			groupFields := bson.M{}
-			for _, field := range query.GroupBy {
+			for _, field := range groupBy {
+				if builderutil.IsRangeBucket(field) {
+					groupFields[builderutil.RangeBucketAlias(field)] = buildRangeBucketSwitch(field, opts)
+					continue
+				}
 				groupFields[field.Value] = "$" + field.Value
 			}
 			groupID = groupFields
@@ -1005,12 +1700,19 @@ func BuildMongoDBGroupStage(query *pb.DocumentQuery) bson.M {
 	}
 
 	var aggExpr bson.M
-	aggFunc := strings.ToLower(query.Aggregate.Function)
-	aggField := getAggField(query.Aggregate)  // ✅ Uses nil-safe helper
+	aggFunc := strings.ToLower(aggregate.Function)
+	aggField := getAggField(aggregate)  // ✅ Uses nil-safe helper
 
-	if query.Distinct && aggField != "" {
-		switch aggFunc {
-		case "count", "sum", "avg":
+	if aggFunc == "string_agg" {
+		// Collected here, joined by buildStringAggProjectStage
+		if distinct {
+			aggExpr = bson.M{"$addToSet": "$" + aggField}
+		} else {
+			aggExpr = bson.M{"$push": "$" + aggField}
+		}
+	} else if distinct && aggField != "" {
+		switch aggFunc {
+		case "count", "sum", "avg":
 			aggExpr = bson.M{"$addToSet": "$" + aggField}
 		default:
 			aggExpr = bson.M{"$" + aggFunc: "$" + aggField}
@@ -1032,48 +1734,450 @@ func BuildMongoDBGroupStage(query *pb.DocumentQuery) bson.M {
 		}
 	}
 
+	if len(groupBy) == 1 && builderutil.IsRangeBucket(groupBy[0]) {
+		return buildBucketStage(groupBy[0], aggExpr, opts)
+	}
 	return bson.M{"$group": bson.M{"_id": groupID, "result": aggExpr}}
 }
 
-func BuildMongoDBHavingStage(having []*pb.QueryCondition) bson.M {
-	matchConditions := bson.M{}
+// buildBucketStage groups by RANGE(field, b0, b1, ...) with $bucket
+// _id is the lower bound of each bucket; documents outside every bucket
+// (or without the field) land in the null bucket, as they group under NULL on SQL.
+func buildBucketStage(bucket *pb.Expression, aggExpr bson.M, opts Options) bson.M {
+	boundaries := bson.A{}
+	for _, bound := range bucket.FunctionArgs[1:] {
+		boundaries = append(boundaries, parseMongoExprValue(bound, opts))
+	}
+	return bson.M{"$bucket": bson.M{
+		"groupBy":    "$" + bucket.FunctionArgs[0].Value,
+		"boundaries": boundaries,
+		"default":    nil,
+		"output":     bson.M{"result": aggExpr},
+	}}
+}
+
+// buildRangeBucketSwitch computes a RANGE bucket as a $switch, for grouping
+// on a bucket together with other fields ($bucket takes a single key)
+func buildRangeBucketSwitch(bucket *pb.Expression, opts Options) bson.M {
+	field := "$" + bucket.FunctionArgs[0].Value
+	bounds := bucket.FunctionArgs[1:]
+	branches := bson.A{}
+	for i := 0; i+1 < len(bounds); i++ {
+		lower, upper := parseMongoExprValue(bounds[i], opts), parseMongoExprValue(bounds[i+1], opts)
+		branches = append(branches, bson.M{
+			"case": bson.M{"$and": bson.A{
+				bson.M{"$gte": bson.A{field, lower}},
+				bson.M{"$lt": bson.A{field, upper}},
+			}},
+			"then": lower,
+		})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": nil}}
+}
+
+// buildStringAggProjectStage joins the values collected by $push into one string
+// Nulls are skipped and other values converted with $toString, matching
+// STRING_AGG / GROUP_CONCAT.
+func buildStringAggProjectStage(separator string) bson.M {
+	values := bson.M{"$filter": bson.M{"input": "$result", "cond": bson.M{"$ne": bson.A{"$$this", nil}}}}
+	joined := bson.M{"$reduce": bson.M{
+		"input":        values,
+		"initialValue": nil,
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$$value", nil}},
+			bson.M{"$toString": "$$this"},
+			bson.M{"$concat": bson.A{"$$value", separator, bson.M{"$toString": "$$this"}}},
+		}},
+	}}
+	return bson.M{"$project": bson.M{"_id": "$_id", "result": joined}}
+}
+
+// ============================================================================
+// SELECT PROJECTION
+// ============================================================================
+
+// selectColumnName names a selected column: its alias, the field, or columnN
+// for an unnamed expression
+func selectColumnName(col *pb.SelectColumn, i int) string {
+	switch {
+	case col.Alias != "":
+		return col.Alias
+	case col.ExpressionObj != nil && col.ExpressionObj.Type == "FIELD":
+		return col.ExpressionObj.Value
+	default:
+		return fmt.Sprintf("column%d", i+1)
+	}
+}
+
+// isPlainColumn reports whether a selected column is a field kept under its own name
+func isPlainColumn(col *pb.SelectColumn) bool {
+	return col.Alias == "" && col.ExpressionObj != nil && col.ExpressionObj.Type == "FIELD"
+}
+
+// IsGroupedSelect reports whether a GET groups, by GROUP BY or by selecting
+// an aggregate: GET Order WITH COUNT(*) AS orders
+func IsGroupedSelect(query *pb.DocumentQuery) bool {
+	if len(query.GroupBy) > 0 {
+		return true
+	}
+	for _, col := range query.SelectColumns {
+		if _, _, ok := aggregateCall(col.ExpressionObj); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsSelectPipeline reports whether a GET's columns need an aggregation:
+// a find projection can keep fields, but not compute or rename them
+func NeedsSelectPipeline(query *pb.DocumentQuery) bool {
+	for _, col := range query.SelectColumns {
+		if !isPlainColumn(col) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildMongoFindProjection returns the projection of a find: the selected
+// fields ({name: 1, email: 1, _id: 0}) and the $text score; nil for GET *
+func BuildMongoFindProjection(query *pb.DocumentQuery) bson.M {
+	projection := bson.M{}
+	for _, col := range query.SelectColumns {
+		if col.ExpressionObj.Value == "*" {
+			projection = bson.M{}
+			break
+		}
+		projection[col.ExpressionObj.Value] = 1
+	}
+	if len(query.SelectColumns) == 0 {
+		// GET name, email FROM User
+		for _, col := range query.Columns {
+			if col.Value == "*" {
+				projection = bson.M{}
+				break
+			}
+			projection[col.Value] = 1
+		}
+	}
+	if len(projection) > 0 && projection["_id"] == nil {
+		projection["_id"] = 0
+	}
+	for field, score := range BuildTextScoreProjection(query.Conditions) {
+		projection[field] = score
+	}
+	if len(projection) == 0 {
+		return nil
+	}
+	return projection
+}
+
+// selectExpression converts a selected column to an aggregation expression
+func selectExpression(expr *pb.Expression, opts Options) (interface{}, error) {
+	switch expr.Type {
+	case "FIELD":
+		return "$" + expr.Value, nil
+	case "BINARY", "FUNCTION", "CASEWHEN":
+		return BuildMongoProjectionExpression(expr, opts), nil
+	case "STRING", "NUMBER", "BOOLEAN", "LITERAL":
+		return bson.M{"$literal": parseMongoExprValue(expr, opts)}, nil
+	default:
+		return nil, fmt.Errorf("%s columns cannot be selected on MongoDB", strings.ToLower(expr.Type))
+	}
+}
+
+// BuildMongoDBSelectPipeline runs a GET with computed or renamed columns as an
+// aggregation. The columns are added with $addFields first, so ORDER BY can
+// name an alias as in SQL; a final $project then keeps only the selected
+// columns, unless the GET also selects *.
+func BuildMongoDBSelectPipeline(query *pb.DocumentQuery, opts Options) ([]bson.M, error) {
+	pipeline := []bson.M{}
+	if len(query.Conditions) > 0 {
+		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions, opts))
+	}
+
+	computed := bson.M{}
+	project := bson.M{"_id": 0}
+	all := false
+	for i, col := range query.SelectColumns {
+		if col.ExpressionObj == nil {
+			continue
+		}
+		if col.ExpressionObj.Value == "*" {
+			all = true
+			continue
+		}
+		name := selectColumnName(col, i)
+		project[name] = 1
+		if isPlainColumn(col) {
+			continue
+		}
+		expr, err := selectExpression(col.ExpressionObj, opts)
+		if err != nil {
+			return nil, err
+		}
+		computed[name] = expr
+	}
+	if project["_id"] == 1 {
+		delete(project, "_id")
+	}
+	if len(computed) > 0 {
+		pipeline = append(pipeline, bson.M{"$addFields": computed})
+	}
+
+	if len(query.OrderBy) > 0 {
+		pipeline = append(pipeline, BuildMongoDBSortStage(query.OrderBy))
+	}
+	if query.Skip > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": query.Skip})
+	}
+	if query.Limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": query.Limit})
+	}
+	if !all {
+		pipeline = append(pipeline, bson.M{"$project": project})
+	}
+	return pipeline, nil
+}
+
+// ============================================================================
+// GROUP OUTPUTS (HAVING on aggregates and aliases)
+// ============================================================================
+
+// groupOutputs tracks the fields a grouping produces, so HAVING can name an
+// aggregate by its alias or repeat the aggregate itself (HAVING COUNT(*) > 10).
+// An aggregate HAVING needs that is not otherwise computed gets a hidden
+// __having_n output, removed once HAVING has run.
+type groupOutputs struct {
+	fields map[string]string // GROUP BY field or alias -> field in the grouped document
+	byAgg  map[string]string // aggregate (SUM(total)) -> field in the grouped document
+	extra  bson.M            // accumulators added for HAVING
+	hidden []string          // the fields of extra, in order
+}
+
+func newGroupOutputs() *groupOutputs {
+	return &groupOutputs{fields: map[string]string{}, byAgg: map[string]string{}, extra: bson.M{}}
+}
+
+// aggregateKey identifies an aggregate: COUNT(*), SUM(total)
+func aggregateKey(function, field string) string {
+	if field == "" {
+		field = "*"
+	}
+	return strings.ToUpper(function) + "(" + field + ")"
+}
+
+// aggregateCall returns the function and field of an aggregate expression
+// such as SUM(total); ok is false for anything else
+func aggregateCall(expr *pb.Expression) (function, field string, ok bool) {
+	if expr == nil || expr.Type != "FUNCTION" {
+		return "", "", false
+	}
+	function = strings.ToUpper(expr.FunctionName)
+	switch function {
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+	default:
+		return "", "", false
+	}
+	field = "*"
+	if len(expr.FunctionArgs) > 0 && expr.FunctionArgs[0] != nil && expr.FunctionArgs[0].Value != "" {
+		field = expr.FunctionArgs[0].Value
+	}
+	return function, field, true
+}
+
+// aggregateAccumulator returns the $group accumulator of an aggregate
+// COUNT(field) counts the documents where field is set, as in SQL
+func aggregateAccumulator(function, field string) bson.M {
+	if function == "COUNT" {
+		if field == "*" {
+			return bson.M{"$sum": 1}
+		}
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$" + field, nil}}, 1, 0}}}
+	}
+	return bson.M{"$" + strings.ToLower(function): "$" + field}
+}
+
+// resolve returns the grouped field a HAVING operand refers to
+func (g *groupOutputs) resolve(expr *pb.Expression) string {
+	if function, field, ok := aggregateCall(expr); ok {
+		key := aggregateKey(function, field)
+		if name, ok := g.byAgg[key]; ok {
+			return name
+		}
+		name := fmt.Sprintf("__having_%d", len(g.hidden)+1)
+		g.extra[name] = aggregateAccumulator(function, field)
+		g.hidden = append(g.hidden, name)
+		g.byAgg[key] = name
+		return name
+	}
+	if expr == nil {
+		return ""
+	}
+	if name, ok := g.fields[expr.Value]; ok {
+		return name
+	}
+	return expr.Value
+}
+
+// havingConditions rewrites HAVING conditions onto the grouped fields
+func (g *groupOutputs) havingConditions(having []*pb.QueryCondition) []*pb.QueryCondition {
+	var result []*pb.QueryCondition
 	for _, cond := range having {
-		field := "result"
-		valueStr := cond.ValueExpr.Value
-		var value interface{} = valueStr
-		if intVal, err := strconv.Atoi(valueStr); err == nil {
-			value = intVal
+		rewritten := &pb.QueryCondition{
+			Operator:   cond.Operator,
+			ValueExpr:  cond.ValueExpr,
+			Value2Expr: cond.Value2Expr,
+			ValuesExpr: cond.ValuesExpr,
+			Logic:      cond.Logic,
+			Nested:     g.havingConditions(cond.Nested),
+		}
+		if cond.FieldExpr != nil {
+			rewritten.FieldExpr = &pb.Expression{Type: "FIELD", Value: g.resolve(cond.FieldExpr)}
 		}
+		result = append(result, rewritten)
+	}
+	return result
+}
 
-		switch cond.Operator {
-		case "$gt", ">":
-			matchConditions[field] = bson.M{"$gt": value}
-		case "$gte", ">=":
-			matchConditions[field] = bson.M{"$gte": value}
-		case "$lt", "<":
-			matchConditions[field] = bson.M{"$lt": value}
-		case "$lte", "<=":
-			matchConditions[field] = bson.M{"$lte": value}
-		case "$ne", "!=":
-			matchConditions[field] = bson.M{"$ne": value}
-		default:
-			matchConditions[field] = value
+// havingStages filters the groups, then drops the hidden HAVING outputs
+func (g *groupOutputs) havingStages(having []*pb.QueryCondition, opts Options) []bson.M {
+	if len(having) == 0 {
+		return nil
+	}
+	stages := []bson.M{{"$match": BuildMongoFilter(having, opts)}}
+	if len(g.hidden) > 0 {
+		hidden := bson.A{}
+		for _, name := range g.hidden {
+			hidden = append(hidden, name)
+		}
+		stages = append(stages, bson.M{"$unset": hidden})
+	}
+	return stages
+}
+
+// addGroupAccumulators adds accumulators to a $group or $bucket stage
+func addGroupAccumulators(stage bson.M, accumulators bson.M) {
+	outputs, ok := stage["$group"].(bson.M)
+	if bucket, isBucket := stage["$bucket"].(bson.M); isBucket {
+		outputs, ok = bucket["output"].(bson.M)
+	}
+	if !ok {
+		return
+	}
+	for name, accumulator := range accumulators {
+		outputs[name] = accumulator
+	}
+}
+
+// groupKeyPaths maps each GROUP BY field to where the aggregate pipeline keeps
+// it: _id for a single key, _id.<field> (or _id.<field>_range) for several
+func groupKeyPaths(groupBy []*pb.Expression) map[string]string {
+	paths := map[string]string{}
+	for _, key := range groupBy {
+		name := key.Value
+		if builderutil.IsRangeBucket(key) {
+			name = key.FunctionArgs[0].Value
+		}
+		if len(groupBy) == 1 {
+			paths[name] = "_id"
+		} else if builderutil.IsRangeBucket(key) {
+			paths[name] = "_id." + builderutil.RangeBucketAlias(key)
+		} else {
+			paths[name] = "_id." + key.Value
+		}
+	}
+	return paths
+}
+
+// BuildMongoDBGroupedPipeline runs GET ... GROUP BY: documents are grouped on
+// the GROUP BY fields and each selected aggregate becomes an output named by its
+// alias (or the function). The keys are lifted out of _id so rows look as they
+// do in SQL, and HAVING and ORDER BY can name keys and aliases alike.
+func BuildMongoDBGroupedPipeline(query *pb.DocumentQuery, opts Options) ([]bson.M, error) {
+	pipeline := []bson.M{}
+	if len(query.Conditions) > 0 {
+		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions, opts))
+	}
+
+	// Keys are numbered in _id: a dotted field cannot name a subdocument field
+	groupID := bson.M{}
+	project := bson.M{"_id": 0}
+	keys := map[string]string{}
+	for i, key := range query.GroupBy {
+		slot := fmt.Sprintf("k%d", i)
+		name := key.Value
+		if builderutil.IsRangeBucket(key) {
+			groupID[slot] = buildRangeBucketSwitch(key, opts)
+			name = builderutil.RangeBucketAlias(key)
+		} else {
+			groupID[slot] = "$" + key.Value
+		}
+		keys[key.Value] = "$_id." + slot
+		project[name] = "$_id." + slot
+	}
+	group := bson.M{"_id": groupID}
+
+	outputs := newGroupOutputs()
+	for _, col := range query.SelectColumns {
+		expr := col.ExpressionObj
+		if function, field, ok := aggregateCall(expr); ok {
+			name := col.Alias
+			if name == "" {
+				name = strings.ToLower(function)
+			}
+			group[name] = aggregateAccumulator(function, field)
+			project[name] = 1
+			outputs.byAgg[aggregateKey(function, field)] = name
+			continue
+		}
+		if expr == nil || expr.Type != "FIELD" {
+			return nil, fmt.Errorf("only GROUP BY fields and aggregates can be selected with GROUP BY")
+		}
+		if expr.Value == "*" {
+			continue
+		}
+		path, grouped := keys[expr.Value]
+		if !grouped {
+			return nil, fmt.Errorf("%s must appear in GROUP BY or be used in an aggregate", expr.Value)
 		}
+		if col.Alias != "" {
+			delete(project, expr.Value)
+			project[col.Alias] = path
+		}
+	}
+
+	having := outputs.havingConditions(query.Having)
+	for name, accumulator := range outputs.extra {
+		group[name] = accumulator
+		project[name] = 1
 	}
-	return bson.M{"$match": matchConditions}
+	pipeline = append(pipeline, bson.M{"$group": group}, bson.M{"$project": project})
+	pipeline = append(pipeline, outputs.havingStages(having, opts)...)
+
+	if len(query.OrderBy) > 0 {
+		pipeline = append(pipeline, BuildMongoDBSortStage(query.OrderBy))
+	}
+	if query.Skip > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": query.Skip})
+	}
+	if query.Limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": query.Limit})
+	}
+	return pipeline, nil
 }
 
-func BuildWindowFunctionPipeline(query *pb.DocumentQuery) ([]bson.M, error) {
+func BuildWindowFunctionPipeline(query *pb.DocumentQuery, opts Options) ([]bson.M, error) {
 	pipeline := []bson.M{}
 	if len(query.Conditions) > 0 {
-		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions))
+		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions, opts))
 	}
 	for _, wf := range query.WindowFunctions {
-		windowStage, err := BuildWindowStage(wf)
+		windowStages, err := BuildWindowStages(wf, opts)
 		if err != nil {
 			return nil, err
 		}
-		pipeline = append(pipeline, windowStage)
+		pipeline = append(pipeline, windowStages...)
 	}
 	if query.Limit > 0 {
 		pipeline = append(pipeline, bson.M{"$limit": query.Limit})
@@ -1081,7 +2185,9 @@ func BuildWindowFunctionPipeline(query *pb.DocumentQuery) ([]bson.M, error) {
 	return pipeline, nil
 }
 
-func BuildWindowStage(wf *pb.WindowClause) (bson.M, error) {
+// BuildWindowStages builds the $setWindowFields stage of a window function
+// NTILE has no window operator and takes three stages (see buildNtileStages)
+func BuildWindowStages(wf *pb.WindowClause, opts Options) ([]bson.M, error) {
 	windowSpec := bson.M{}
 
 	if len(wf.PartitionBy) > 0 {
@@ -1119,51 +2225,287 @@ func BuildWindowStage(wf *pb.WindowClause) (bson.M, error) {
 	case "$denserank", "dense_rank":
 		windowExpr = bson.M{wf.Alias: bson.M{"$denseRank": bson.M{}}}
 	case "$shift", "shift":
+		// LAG and LEAD: Offset is negative for LAG (rows back), positive for LEAD
+		if wf.FieldExpr == nil || wf.FieldExpr.Value == "" {
+			return nil, fmt.Errorf("LAG and LEAD require a field")
+		}
 		offset := int(wf.Offset)
 		if offset == 0 {
 			offset = -1
 		}
-		windowExpr = bson.M{wf.Alias: bson.M{"$shift": bson.M{"output": "$" + wf.Alias, "by": offset}}}
+		shift := bson.M{"output": "$" + wf.FieldExpr.Value, "by": offset}
+		if wf.DefaultValue != nil {
+			shift["default"] = windowDefaultValue(wf.DefaultValue, opts)
+		}
+		windowExpr = bson.M{wf.Alias: bson.M{"$shift": shift}}
 	case "ntile":
-		windowExpr = bson.M{wf.Alias: bson.M{"$rank": bson.M{}}}
+		return buildNtileStages(wf, windowSpec)
+	case "count", "sum", "avg", "min", "max":
+		// Aggregates over a window: running totals, moving averages
+		hasField := wf.FieldExpr != nil && wf.FieldExpr.Value != "" && wf.FieldExpr.Value != "*"
+		var accumulator bson.M
+		switch {
+		case function == "count" && !hasField:
+			accumulator = bson.M{"$count": bson.M{}}
+		case function == "count":
+			// COUNT field counts the documents where it is set, as in SQL
+			accumulator = bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$" + wf.FieldExpr.Value, nil}}, 1, 0}}}
+		case !hasField:
+			return nil, fmt.Errorf("%s OVER requires a field", strings.ToUpper(function))
+		default:
+			accumulator = bson.M{"$" + function: "$" + wf.FieldExpr.Value}
+		}
+		window, err := buildWindowFrame(wf)
+		if err != nil {
+			return nil, err
+		}
+		if window != nil {
+			accumulator["window"] = window
+		}
+		windowExpr = bson.M{wf.Alias: accumulator}
 	default:
 		return nil, fmt.Errorf("unsupported window function: %s", function)
 	}
 
 	windowSpec["output"] = windowExpr
-	return bson.M{"$setWindowFields": windowSpec}, nil
+	return []bson.M{{"$setWindowFields": windowSpec}}, nil
 }
 
-func BuildSetOperationPipeline(query *pb.DocumentQuery) ([]bson.M, error) {
-	pipeline := []bson.M{}
-	if len(query.Conditions) > 0 {
-		pipeline = append(pipeline, BuildMongoDBMatchStage(query.Conditions))
+// buildWindowFrame converts a ROWS / RANGE frame to the window of an aggregate
+// output: ROWS BETWEEN 2 PRECEDING AND CURRENT ROW = {documents: [-2, "current"]}
+// Without a frame MongoDB aggregates the whole partition, not up to the current row.
+func buildWindowFrame(wf *pb.WindowClause) (bson.M, error) {
+	if wf.FrameUnit == "" {
+		return nil, nil
+	}
+	lower, err := windowFrameBound(wf.FrameStart)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := windowFrameBound(wf.FrameEnd)
+	if err != nil {
+		return nil, err
 	}
+	unit := "documents"
+	if strings.ToUpper(wf.FrameUnit) == "RANGE" {
+		unit = "range"
+	}
+	return bson.M{unit: bson.A{lower, upper}}, nil
+}
 
-	operation := strings.ToLower(query.Operation)
-	switch operation {
-	case "unionwith":
-		pipeline = append(pipeline, bson.M{"$unionWith": bson.M{"coll": query.Collection}})
-	case "intersect", "setdifference":
-		// Already handled by combining conditions in translator
-	default:
-		return nil, fmt.Errorf("unsupported set operation: %s", operation)
+// windowFrameBound converts one frame bound: UNBOUNDED PRECEDING / FOLLOWING =
+// "unbounded", CURRENT ROW = "current", n PRECEDING = -n, n FOLLOWING = n
+func windowFrameBound(bound string) (interface{}, error) {
+	parts := strings.Fields(strings.ToUpper(bound))
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid window frame bound: %s", bound)
+	}
+	switch parts[0] {
+	case "UNBOUNDED":
+		return "unbounded", nil
+	case "CURRENT":
+		return "current", nil
+	}
+	n, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid window frame bound: %s", bound)
+	}
+	if parts[1] == "PRECEDING" {
+		return -n, nil
+	}
+	return n, nil
+}
+
+// windowDefaultValue converts the LAG/LEAD DEFAULT value; $shift takes constants only
+func windowDefaultValue(expr *pb.Expression, opts Options) interface{} {
+	return parseMongoExprValue(expr, opts)
+}
+
+// buildNtileStages splits each partition into Buckets groups as SQL's NTILE does:
+// with n rows and b buckets, the first n mod b buckets hold one row more than
+// the rest. The row number and partition size are computed in $setWindowFields,
+// the bucket in $set, and the helper fields are then removed.
+func buildNtileStages(wf *pb.WindowClause, windowSpec bson.M) ([]bson.M, error) {
+	buckets := int(wf.Buckets)
+	if buckets <= 0 {
+		return nil, fmt.Errorf("NTILE requires a positive bucket count")
+	}
+	rowField := "__" + wf.Alias + "_row"
+	rowsField := "__" + wf.Alias + "_rows"
+
+	windowSpec["output"] = bson.M{
+		rowField:  bson.M{"$documentNumber": bson.M{}},
+		rowsField: bson.M{"$count": bson.M{}, "window": bson.M{"documents": bson.A{"unbounded", "unbounded"}}},
+	}
+
+	// q = rows per small bucket, m = number of large buckets (q + 1 rows each)
+	row := bson.M{"$subtract": bson.A{"$" + rowField, 1}}
+	large := bson.M{"$add": bson.A{"$$q", 1}}
+	largeRows := bson.M{"$multiply": bson.A{"$$m", large}}
+	bucket := bson.M{"$let": bson.M{
+		"vars": bson.M{
+			"q": bson.M{"$floor": bson.M{"$divide": bson.A{"$" + rowsField, buckets}}},
+			"m": bson.M{"$mod": bson.A{"$" + rowsField, buckets}},
+		},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$lt": bson.A{row, largeRows}},
+			bson.M{"$add": bson.A{bson.M{"$floor": bson.M{"$divide": bson.A{row, large}}}, 1}},
+			bson.M{"$add": bson.A{"$$m", bson.M{"$floor": bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{row, largeRows}}, "$$q"}}}, 1}},
+		}},
+	}}
+
+	return []bson.M{
+		{"$setWindowFields": windowSpec},
+		{"$set": bson.M{wf.Alias: bucket}},
+		{"$unset": bson.A{rowField, rowsField}},
+	}, nil
+}
+
+// BuildSetOperationPipeline builds UNION, UNION ALL, INTERSECT and EXCEPT on the
+// left query's collection, bringing the right query in with $unionWith.
+// Documents are compared the way SQL compares rows: by the selected columns,
+// matched by position and named after the left query's, or whole for GET *.
+// UNION ALL keeps every document; the others tag each row with its side and
+// group equal rows, keeping those found on the sides the operation asks for.
+// Columns are grouped as an array, which compares by position where a
+// document would also compare by field order.
+func BuildSetOperationPipeline(query *pb.DocumentQuery, opts Options) ([]bson.M, error) {
+	setOp := query.SetOperation
+	if setOp == nil || setOp.LeftQuery == nil || setOp.RightQuery == nil {
+		return nil, fmt.Errorf("set operation requires two queries")
+	}
+	operation := strings.ReplaceAll(strings.ToUpper(setOp.OperationType), "_", " ")
+
+	names := setColumnNames(setOperandColumns(setOp.LeftQuery))
+	if len(setOperandColumns(setOp.RightQuery)) != len(names) {
+		return nil, fmt.Errorf("both queries of %s must select the same number of columns", operation)
+	}
+
+	all := operation == "UNION ALL"
+	pipeline, err := setOperandStages(setOp.LeftQuery, names, 0, all, opts)
+	if err != nil {
+		return nil, err
+	}
+	right, err := setOperandStages(setOp.RightQuery, names, 1, all, opts)
+	if err != nil {
+		return nil, err
+	}
+	pipeline = append(pipeline, bson.M{"$unionWith": bson.M{"coll": setOp.RightQuery.Collection, "pipeline": right}})
+
+	if !all {
+		pipeline = append(pipeline, bson.M{"$group": bson.M{"_id": "$row", "sides": bson.M{"$addToSet": "$side"}}})
+		switch operation {
+		case "UNION":
+		case "INTERSECT":
+			pipeline = append(pipeline, bson.M{"$match": bson.M{"sides": bson.M{"$all": bson.A{0, 1}}}})
+		case "EXCEPT":
+			pipeline = append(pipeline, bson.M{"$match": bson.M{"sides": bson.M{"$ne": 1}}})
+		default:
+			return nil, fmt.Errorf("unsupported set operation: %s", operation)
+		}
+		pipeline = append(pipeline, bson.M{"$replaceRoot": bson.M{"newRoot": setResultRoot(names)}})
 	}
 
+	// Outer ORDER BY / OFFSET / LIMIT apply to the combined result
+	if len(query.OrderBy) > 0 {
+		pipeline = append(pipeline, BuildMongoDBSortStage(query.OrderBy))
+	}
+	if query.Skip > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": query.Skip})
+	}
 	if query.Limit > 0 {
 		pipeline = append(pipeline, bson.M{"$limit": query.Limit})
 	}
 	return pipeline, nil
 }
 
-func BuildMongoDBMatchStage(conditions []*pb.QueryCondition) bson.M {
-	return bson.M{"$match": BuildMongoFilter(conditions)}
+// setOperandColumns returns the columns a set operation operand selects, nil
+// for GET *; a nested set operation selects its left query's columns
+func setOperandColumns(operand *pb.DocumentQuery) []*pb.SelectColumn {
+	if operand.SetOperation != nil {
+		return setOperandColumns(operand.SetOperation.LeftQuery)
+	}
+	for _, col := range operand.SelectColumns {
+		if col.ExpressionObj != nil && col.ExpressionObj.Value == "*" {
+			return nil
+		}
+	}
+	return operand.SelectColumns
+}
+
+// setColumnNames names the columns of a set operation's result
+func setColumnNames(columns []*pb.SelectColumn) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = selectColumnName(col, i)
+	}
+	return names
+}
+
+// setOperandStages renders one operand of a set operation: its own stages, then
+// its documents reshaped into the rows being compared. Rows are tagged with
+// side (0 = left, 1 = right) unless every document is kept.
+func setOperandStages(operand *pb.DocumentQuery, names []string, side int, all bool, opts Options) ([]bson.M, error) {
+	var stages []bson.M
+	var err error
+	if operand.SetOperation != nil {
+		stages, err = BuildSetOperationPipeline(operand, opts)
+	} else {
+		stages, err = buildSourceStages(operand, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The operand's stages have already named its columns
+	columns := setOperandColumns(operand)
+	if columns == nil {
+		if !all {
+			stages = append(stages, bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"row": "$$ROOT", "side": side}}})
+		}
+		return stages, nil
+	}
+	values := bson.A{}
+	for _, name := range setColumnNames(columns) {
+		values = append(values, "$"+name)
+	}
+
+	if !all {
+		return append(stages, bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"row": values, "side": side}}}), nil
+	}
+	row := bson.M{}
+	for i, value := range values {
+		row[names[i]] = value
+	}
+	return append(stages, bson.M{"$replaceRoot": bson.M{"newRoot": row}}), nil
+}
+
+// setResultRoot rebuilds a grouped row: the document itself for GET *, or the
+// column array named after the left query's columns
+func setResultRoot(names []string) interface{} {
+	if len(names) == 0 {
+		return "$_id"
+	}
+	root := bson.M{}
+	for i, name := range names {
+		root[name] = bson.M{"$arrayElemAt": bson.A{"$_id", i}}
+	}
+	return root
+}
+
+func BuildMongoDBMatchStage(conditions []*pb.QueryCondition, opts Options) bson.M {
+	return bson.M{"$match": BuildMongoFilter(conditions, opts)}
 }
 
 func BuildMongoDBSortStage(orderBy []*pb.OrderByClause) bson.M {
 	sortFields := bson.M{}
 	for _, ob := range orderBy {
-		field := ExtractFieldName(ob.FieldExpr.Value)
+		// ORDER BY RELEVANCE sorts $text matches by score (always best first)
+		if mapping.IsSearchRankField(ob.FieldExpr.Value) {
+			sortFields["score"] = bson.M{"$meta": "textScore"}
+			continue
+		}
+		field := ob.FieldExpr.Value
 		direction := 1
 		if ob.Direction == "-1" || strings.ToUpper(ob.Direction) == "DESC" {
 			direction = -1
@@ -1173,14 +2515,160 @@ func BuildMongoDBSortStage(orderBy []*pb.OrderByClause) bson.M {
 	return bson.M{"$sort": sortFields}
 }
 
-func ExtractFieldName(field string) string {
-	parts := strings.Split(field, ".")
-	if len(parts) == 2 {
-		return parts[1]
+// HasTextSearch reports whether a $text condition is present (recursive)
+func HasTextSearch(conditions []*pb.QueryCondition) bool {
+	for _, cond := range conditions {
+		if cond.Operator == "$text" || HasTextSearch(cond.Nested) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildTextScoreProjection returns {score: {$meta: "textScore"}} for a $text
+// search, so each document carries its relevance; nil otherwise
+func BuildTextScoreProjection(conditions []*pb.QueryCondition) bson.M {
+	if !HasTextSearch(conditions) {
+		return nil
+	}
+	return bson.M{"score": bson.M{"$meta": "textScore"}}
+}
+
+// ============================================================================
+// FIELD PATHS
+// ============================================================================
+
+// Field references are document paths: profile.address.city reads embedded
+// documents. A first segment naming the queried collection (User.name) is a
+// table qualifier and is dropped, and one naming a joined collection
+// (Order.total) reads the $lookup output, orders_joined.total. Any other path
+// is kept whole.
+
+// fieldPaths resolves the field references of one query
+type fieldPaths struct {
+	collection string
+	joined     []string
+}
+
+// joinedField names the field a $lookup stores a joined collection's documents in
+func joinedField(table string) string {
+	return table + "_joined"
+}
+
+// namesCollection reports whether a qualifier names a collection, by its own
+// name (orders) or its entity (Order)
+func namesCollection(qualifier, collection string) bool {
+	if collection == "" {
+		return false
+	}
+	return strings.ToLower(qualifier) == collection || mapping.EntityTable(qualifier) == collection
+}
+
+// resolve maps a field reference to its document path
+func (p fieldPaths) resolve(field string) string {
+	qualifier, rest, ok := strings.Cut(field, ".")
+	if !ok || rest == "" {
+		return field
+	}
+	if namesCollection(qualifier, p.collection) {
+		return rest
+	}
+	for _, table := range p.joined {
+		if namesCollection(qualifier, table) {
+			return joinedField(table) + "." + rest
+		}
 	}
 	return field
 }
 
+// joinFields splits a join's ON condition into $lookup's localField, a path in
+// the documents joined so far, and foreignField, a path in the joined
+// collection. Either side may name the joined collection.
+func (p fieldPaths) joinFields(join *pb.JoinClause) (string, string) {
+	local, foreign := join.LeftExpr.Value, join.RightExpr.Value
+	if qualifier, _, ok := strings.Cut(local, "."); ok &&
+		namesCollection(qualifier, join.Table) && !namesCollection(qualifier, p.collection) {
+		local, foreign = foreign, local
+	}
+	if qualifier, rest, ok := strings.Cut(foreign, "."); ok && namesCollection(qualifier, join.Table) {
+		foreign = rest
+	}
+	return p.resolve(local), foreign
+}
+
+func (p fieldPaths) expression(expr *pb.Expression) {
+	if expr == nil {
+		return
+	}
+	if expr.Type == "FIELD" {
+		expr.Value = p.resolve(expr.Value)
+	}
+	p.expression(expr.Left)
+	p.expression(expr.Right)
+	for _, arg := range expr.FunctionArgs {
+		p.expression(arg)
+	}
+	for _, when := range expr.CaseConditions {
+		if when.Condition != nil {
+			p.conditions([]*pb.QueryCondition{when.Condition})
+		}
+		p.expression(when.ThenExpr)
+	}
+	p.expression(expr.CaseElse)
+}
+
+func (p fieldPaths) conditions(conditions []*pb.QueryCondition) {
+	for _, cond := range conditions {
+		p.expression(cond.FieldExpr)
+		p.expression(cond.ValueExpr)
+		p.expression(cond.Value2Expr)
+		for _, value := range cond.ValuesExpr {
+			p.expression(value)
+		}
+		p.conditions(cond.Nested)
+	}
+}
+
+func (p fieldPaths) orderBy(orderBy []*pb.OrderByClause) {
+	for _, ob := range orderBy {
+		p.expression(ob.FieldExpr)
+	}
+}
+
+// ResolveFieldPaths rewrites the field references of a query to document
+// paths, in place. Join conditions are kept: BuildMongoDBJoinPipeline resolves
+// each side against its own collection.
+func ResolveFieldPaths(query *pb.DocumentQuery) {
+	paths := fieldPaths{collection: query.Collection}
+	for _, join := range query.Joins {
+		paths.joined = append(paths.joined, join.Table)
+	}
+
+	paths.conditions(query.Conditions)
+	paths.conditions(query.Having)
+	paths.orderBy(query.OrderBy)
+	for _, expr := range query.Columns {
+		paths.expression(expr)
+	}
+	for _, expr := range query.GroupBy {
+		paths.expression(expr)
+	}
+	for _, col := range query.SelectColumns {
+		paths.expression(col.ExpressionObj)
+	}
+	if query.Aggregate != nil {
+		paths.expression(query.Aggregate.FieldExpr)
+		paths.orderBy(query.Aggregate.OrderBy)
+	}
+	for _, window := range query.WindowFunctions {
+		paths.expression(window.FieldExpr)
+		for _, expr := range window.PartitionBy {
+			paths.expression(expr)
+		}
+		paths.orderBy(window.OrderBy)
+	}
+}
+
 // ============================================================================
 // TCL OPERATIONS
 // ============================================================================
@@ -0,0 +1,138 @@
+package mongodb_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/builders/mongodb"
+	"github.com/omniql-engine/omniql/engine/parser"
+	"github.com/omniql-engine/omniql/engine/translator"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// documentQuery translates an OQL query to MongoDB
+func documentQuery(t *testing.T, input string) *pb.DocumentQuery {
+	t.Helper()
+	query, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	result, err := translator.Translate(query, "MongoDB", "")
+	if err != nil {
+		t.Fatalf("Translate(%q): %v", input, err)
+	}
+	return result.GetDocument()
+}
+
+// toJSON renders a builder result for comparison; map keys come out sorted
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal(%v): %v", v, err)
+	}
+	return string(data)
+}
+
+// WHERE clauses become query filters
+func TestBuildMongoFilter(t *testing.T) {
+	tests := []struct {
+		where string
+		want  string
+	}{
+		{`age > 21`, `{"age":{"$gt":21}}`},
+		{`name = "Alice" AND age <= 30`, `{"age":{"$lte":30},"name":"Alice"}`},
+		{`status IN ("a", "b")`, `{"status":{"$in":["a","b"]}}`},
+		{`status NOT IN ("a")`, `{"status":{"$nin":["a"]}}`},
+		{`age BETWEEN 18 AND 30`, `{"age":{"$gte":18,"$lte":30}}`},
+		{`role = "admin" OR role = "owner"`, `{"$or":[{"role":"admin"},{"role":"owner"}]}`},
+		{`deleted_at IS NULL`, `{"deleted_at":{"$eq":null}}`},
+	}
+	for _, tt := range tests {
+		query := documentQuery(t, "GET User WHERE "+tt.where)
+		if got := toJSON(t, mongodb.BuildMongoFilter(query.Conditions, mongodb.Options{})); got != tt.want {
+			t.Errorf("BuildMongoFilter(%s):\n got %s\nwant %s", tt.where, got, tt.want)
+		}
+	}
+}
+
+// An UPSERT matches on its conflict fields and sets the others, or only
+// writes on insert with DO NOTHING or when every field is a conflict field
+func TestBuildMongoUpsert(t *testing.T) {
+	tests := []struct {
+		input  string
+		filter string
+		update string
+	}{
+		{
+			`UPSERT User WITH email = "a", name = "b" ON email`,
+			`{"email":"a"}`, `{"$set":{"name":"b"}}`,
+		},
+		{
+			`UPSERT User WITH email = "a", name = "b", age = 3 ON email UPDATE SET name = "c"`,
+			`{"email":"a"}`, `{"$set":{"name":"c"}}`,
+		},
+		{
+			`UPSERT User WITH email = "a", name = "b" ON email DO NOTHING`,
+			`{"email":"a"}`, `{"$setOnInsert":{"email":"a","name":"b"}}`,
+		},
+		{
+			`UPSERT User WITH email = "a" ON email`,
+			`{"email":"a"}`, `{"$setOnInsert":{"email":"a"}}`,
+		},
+	}
+	for _, tt := range tests {
+		filter, update := mongodb.BuildMongoUpsert(documentQuery(t, tt.input), mongodb.Options{})
+		if got := toJSON(t, filter); got != tt.filter {
+			t.Errorf("BuildMongoUpsert(%s) filter:\n got %s\nwant %s", tt.input, got, tt.filter)
+		}
+		if got := toJSON(t, update); got != tt.update {
+			t.Errorf("BuildMongoUpsert(%s) update:\n got %s\nwant %s", tt.input, got, tt.update)
+		}
+	}
+}
+
+// A find projects the selected fields without _id, and the text score of a
+// SEARCH; GET * projects nothing
+func TestBuildMongoFindProjection(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`GET User`, `null`},
+		{`GET name, email FROM User`, `{"_id":0,"email":1,"name":1}`},
+		{`GET User WHERE body SEARCH "redis"`, `{"score":{"$meta":"textScore"}}`},
+	}
+	for _, tt := range tests {
+		if got := toJSON(t, mongodb.BuildMongoFindProjection(documentQuery(t, tt.input))); got != tt.want {
+			t.Errorf("BuildMongoFindProjection(%s):\n got %s\nwant %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+// Conditions on array elements become one array filter per identifier
+func TestBuildMongoArrayFilters(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			`UPDATE Order SET items.$[i].qty = qty + 1 WHERE id = 5 AND items.i.sku = "A-100" AND items.i.qty > 0`,
+			`[{"i.qty":{"$gt":0},"i.sku":"A-100"}]`,
+		},
+		{
+			`UPDATE Order SET items.$[i].qty = 1, items.$[j].x = 1 WHERE items.i.sku = "A-100" AND items.j.ok = true`,
+			`[{"i.sku":"A-100"},{"j.ok":true}]`,
+		},
+		{
+			`UPDATE Order SET items.$.status = "shipped" WHERE items.sku = "A-100"`,
+			`null`,
+		},
+	}
+	for _, tt := range tests {
+		query := documentQuery(t, tt.input)
+		if got := toJSON(t, mongodb.BuildMongoArrayFilters(query.ArrayFilters, mongodb.Options{})); got != tt.want {
+			t.Errorf("BuildMongoArrayFilters(%s):\n got %s\nwant %s", tt.input, got, tt.want)
+		}
+	}
+}
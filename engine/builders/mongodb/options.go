@@ -0,0 +1,30 @@
+package mongodb
+
+// Options are the per-client settings of the MongoDB builders. The zero value
+// types literals and expires TTL documents through expireAt.
+type Options struct {
+	// UntypedValues keeps every literal but an int or a double a string.
+	// Otherwise literals are converted to the BSON type they spell, so they
+	// match documents stored with that type: true and false become booleans,
+	// null becomes null, ISO dates become dates, 24-digit hex strings become
+	// ObjectIDs, and numbers too precise for a double become Decimal128.
+	UntypedValues bool
+
+	// ExpireField holds the date a document written with TTL expires at,
+	// "expireAt" when empty. A TTL index on it with expireAfterSeconds: 0 has
+	// the server delete the document once that date has passed; the TTL
+	// monitor runs every 60 seconds, so a document can outlive its TTL by up
+	// to a minute.
+	ExpireField string
+}
+
+// defaultExpireField is the ExpireField of the zero Options
+const defaultExpireField = "expireAt"
+
+// expireField returns ExpireField, or its default when unset
+func (o Options) expireField() string {
+	if o.ExpireField == "" {
+		return defaultExpireField
+	}
+	return o.ExpireField
+}
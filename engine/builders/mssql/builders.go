@@ -0,0 +1,1358 @@
+package mssql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// Builders write ? placeholders while a statement is put together, so parts
+// can be built in any order; NumberParams then turns them into SQL Server's
+// named parameters @p1, @p2, ... (the translator applies it once per
+// statement).
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getJoinLeft(join *pb.JoinClause) string {
+	if join == nil || join.LeftExpr == nil {
+		return ""
+	}
+	return join.LeftExpr.Value
+}
+
+func getJoinRight(join *pb.JoinClause) string {
+	if join == nil || join.RightExpr == nil {
+		return ""
+	}
+	return join.RightExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// buildValueSQL renders a value position: computed expressions inline, value
+// keywords bare, excluded.col as the MERGE source column, and anything else
+// as a ? bind that NumberParams later turns into @pN
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" {
+		if valueKeywords[strings.ToUpper(expr.Value)] {
+			return strings.ToUpper(expr.Value), nil
+		}
+		if column, ok := excludedColumn(expr.Value); ok {
+			return mergeSource + "." + QuoteIdentifier(column), nil
+		}
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{ConvertSQLServerValue(value)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches and DDL take no parameters)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return formatLiteral(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ============================================================================
+// CRUD OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates parameterized SELECT query with expression support
+func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	return buildSelectSQL(query, "")
+}
+
+// buildSelectSQL builds a SELECT, or with into a SELECT ... INTO new table
+// Row locks are table hints: FROM [users] WITH (UPDLOCK, ROWLOCK).
+func buildSelectSQL(query *pb.RelationalQuery, into string) (string, []interface{}, error) {
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+	selectClause += topClause(query.Limit, query.Offset)
+
+	var args []interface{}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			var colSQL string
+			switch {
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "CASEWHEN":
+				caseSQL := "CASE"
+				for _, cond := range col.ExpressionObj.CaseConditions {
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", buildConditionSQL(cond.Condition), thenSQL)
+					args = append(args, thenArgs...)
+				}
+				if col.ExpressionObj.CaseElse != nil {
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
+				}
+				colSQL = caseSQL + " END"
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW":
+				colSQL = buildWindowExprSQL(col.ExpressionObj)
+			default:
+				colSQL = BuildExpressionSQL(col.ExpressionObj)
+			}
+			if col.Alias != "" {
+				colSQL += " AS " + QuoteIdentifier(col.Alias)
+			}
+			colParts = append(colParts, colSQL)
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+
+	from := QuoteIdentifier(query.Table)
+	if query.Lock != "" {
+		hints, err := lockHints(query.Lock, query.LockWait)
+		if err != nil {
+			return "", nil, err
+		}
+		from += " WITH (" + hints + ")"
+	}
+
+	sql := fmt.Sprintf("%s %s", selectClause, columns)
+	if into != "" {
+		sql += " INTO " + QuoteIdentifier(into)
+	}
+	sql += " FROM " + from
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, len(query.OrderBy) > 0, query.Limit, query.Offset), args, nil
+}
+
+// lockHints renders FOR UPDATE / FOR SHARE as table hints that hold the row
+// locks until the transaction ends; NOWAIT stays NOWAIT and SKIP LOCKED is READPAST
+func lockHints(lock, wait string) (string, error) {
+	var hints []string
+	switch strings.ToUpper(lock) {
+	case "UPDATE":
+		hints = []string{"UPDLOCK", "ROWLOCK"}
+	case "SHARE":
+		hints = []string{"REPEATABLEREAD", "ROWLOCK"}
+	default:
+		return "", fmt.Errorf("SQL Server cannot lock rows FOR %s", lock)
+	}
+	switch strings.ToUpper(wait) {
+	case "NOWAIT":
+		hints = append(hints, "NOWAIT")
+	case "SKIP LOCKED":
+		hints = append(hints, "READPAST")
+	}
+	return strings.Join(hints, ", "), nil
+}
+
+// buildConditionSQL renders a condition with its values inline (CASE WHEN)
+func buildConditionSQL(cond *pb.QueryCondition) string {
+	if cond == nil {
+		return ""
+	}
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	case "ILIKE", "NOT_ILIKE":
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, sqlServerOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+	}
+	return fmt.Sprintf("%s %s %s", field, sqlServerOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+}
+
+// buildWindowExprSQL renders a window function selected inline
+// SQL Server requires an ORDER BY in the OVER clause of ranking and offset
+// functions; without one the rows are taken in no particular order.
+func buildWindowExprSQL(expr *pb.Expression) string {
+	funcName := strings.ReplaceAll(expr.FunctionName, " ", "_")
+
+	var funcCall string
+	switch funcName {
+	case "LAG", "LEAD":
+		field := "id"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				field = QuoteIdentifier(arg.Value)
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, field)
+	case "NTILE":
+		buckets := "4"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				buckets = arg.Value
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, buckets)
+	default:
+		funcCall = fmt.Sprintf("%s()", funcName)
+	}
+
+	var partitionParts, orderParts []string
+	for _, arg := range expr.FunctionArgs {
+		if strings.HasPrefix(arg.Value, "PARTITION:") {
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
+		} else if strings.HasPrefix(arg.Value, "ORDER:") {
+			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
+			if len(parts) >= 2 {
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
+			} else if len(parts) == 1 {
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
+			}
+		}
+	}
+
+	var overParts []string
+	if len(partitionParts) > 0 {
+		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionParts, ", "))
+	}
+	if len(orderParts) > 0 {
+		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
+	} else {
+		overParts = append(overParts, "ORDER BY (SELECT NULL)")
+	}
+	return funcCall + " OVER (" + strings.Join(overParts, " ") + ")"
+}
+
+// BuildInsertSQL creates parameterized INSERT query
+func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query)
+	}
+
+	var fields, placeholders []string
+	var args []interface{}
+
+	for _, field := range query.Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s)%s VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), BuildOutputClause(query.Returning, "INSERTED"), strings.Join(placeholders, ", "))
+	return sql, args, nil
+}
+
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + BuildOutputClause(query.Returning, "INSERTED") + " " + sourceSQL, nil, nil
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildOutputClause builds RETURNING as: OUTPUT INSERTED.col, ... (DELETED.col
+// for a DELETE); empty when no columns are requested
+func BuildOutputClause(returning []*pb.Expression, row string) string {
+	if len(returning) == 0 {
+		return ""
+	}
+	var cols []string
+	for _, expr := range returning {
+		cols = append(cols, BuildExpressionSQL(qualifyColumns(expr, row)))
+	}
+	return " OUTPUT " + strings.Join(cols, ", ")
+}
+
+// BuildUpdateSQL creates parameterized UPDATE query with expression support
+func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var setParts []string
+	var args []interface{}
+
+	// JSON path assignments are grouped by column and rendered where the column first appears
+	jsonSets := map[string][]*pb.QueryField{}
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			jsonSets[column.Value] = append(jsonSets[column.Value], field)
+		}
+	}
+
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			group, pending := jsonSets[column.Value]
+			if !pending {
+				continue
+			}
+			delete(jsonSets, column.Value)
+			setSQL, setArgs := buildJSONSetSQL(group)
+			setParts = append(setParts, setSQL)
+			args = append(args, setArgs...)
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s%s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "), BuildOutputClause(query.Returning, "INSERTED"))
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	return sql + whereClause, append(args, whereArgs...)
+}
+
+// BuildDeleteSQL creates parameterized DELETE query
+func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql := fmt.Sprintf("DELETE FROM %s%s", QuoteIdentifier(query.Table), BuildOutputClause(query.Returning, "DELETED"))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// BuildBulkInsertSQL creates BULK INSERT as one multi-row INSERT
+// SQL Server takes at most 1000 rows in a VALUES list and 2100 parameters.
+func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+
+	var fields []string
+	for _, field := range query.BulkData[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+	}
+
+	var rows []string
+	var args []interface{}
+	for _, row := range query.BulkData {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+		}
+		rows = append(rows, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s)%s VALUES %s",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), BuildOutputClause(query.Returning, "INSERTED"), strings.Join(rows, ", "))
+	return sql, args, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+func BuildWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " WHERE " + clause, args
+}
+
+func buildConditionsRecursive(conditions []*pb.QueryCondition) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		var clause string
+		var clauseArgs []interface{}
+
+		if len(cond.Nested) > 0 {
+			nestedClause, nestedArgs := buildConditionsRecursive(cond.Nested)
+			clause = "(" + nestedClause + ")"
+			clauseArgs = nestedArgs
+		} else {
+			clause, clauseArgs = buildSingleCondition(cond)
+		}
+
+		if i > 0 {
+			logic := cond.Logic
+			if logic == "" {
+				logic = "AND"
+			}
+			parts = append(parts, logic)
+		}
+		parts = append(parts, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	return strings.Join(parts, " "), args
+}
+
+func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	field := BuildExpressionSQL(cond.FieldExpr)
+
+	switch cond.Operator {
+	case "IS_NULL":
+		return fmt.Sprintf("%s IS NULL", field), nil
+	case "IS_NOT_NULL":
+		return fmt.Sprintf("%s IS NOT NULL", field), nil
+	case "IN":
+		return buildInClause(field, "IN", cond.ValuesExpr)
+	case "NOT_IN":
+		return buildInClause(field, "NOT IN", cond.ValuesExpr)
+	case "BETWEEN":
+		return buildBetweenClause(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "NOT_BETWEEN":
+		return buildBetweenClause(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "ILIKE", "NOT_ILIKE":
+		// No ILIKE: compare both sides in lower case, whatever the collation
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, sqlServerOperator(cond.Operator), valueSQL), args
+	case "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
+	default:
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, sqlServerOperator(cond.Operator), valueSQL), args
+	}
+}
+
+// sqlServerOperator maps an OQL operator (NOT_LIKE, ILIKE) to its T-SQL spelling
+func sqlServerOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["SQLServer"][op]; ok {
+		return mapped
+	}
+	return op
+}
+
+// buildInClause renders IN / NOT IN; an empty list matches nothing (IN) or everything (NOT IN)
+func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}) {
+	if len(values) == 0 {
+		if operator == "IN" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args
+}
+
+func buildBetweenClause(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}) {
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args
+}
+
+// BuildExpressionSQL converts an Expression to SQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		return mapping.FunctionSQL("SQLServer", expr.FunctionName, args)
+	case "CASEWHEN":
+		caseParts := []string{"CASE"}
+		for _, cond := range expr.CaseConditions {
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", buildConditionSQL(cond.Condition), buildLiteralSQL(cond.ThenExpr)))
+		}
+		if expr.CaseElse != nil {
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
+		}
+		caseParts = append(caseParts, "END")
+		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		if column, ok := excludedColumn(expr.Value); ok {
+			return mergeSource + "." + QuoteIdentifier(column)
+		}
+		if row, column, ok := outputColumn(expr.Value); ok {
+			return row + "." + QuoteIdentifier(column)
+		}
+		return quoteColumnRef(expr.Value)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
+	default:
+		return expr.Value
+	}
+}
+
+// ConvertSQLServerValue converts values for SQL Server compatibility
+// Booleans are BIT 1 and 0.
+func ConvertSQLServerValue(value string) interface{} {
+	switch strings.ToLower(value) {
+	case "true":
+		return 1
+	case "false":
+		return 0
+	default:
+		return value
+	}
+}
+
+// formatLiteral converts a value to SQL literal format for VIEW definitions
+func formatLiteral(v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return "1"
+	case "FALSE":
+		return "0"
+	}
+	return QuoteString(s)
+}
+
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	next := 0
+	return replacePlaceholders(sql, func() string {
+		if next >= len(args) {
+			return "?"
+		}
+		next++
+		return formatLiteral(args[next-1])
+	})
+}
+
+// NumberParams rewrites the ? placeholders of a built statement as SQL
+// Server's parameters @p1, @p2, ... in the order the arguments were
+// collected. ? inside string literals is left alone.
+func NumberParams(sql string) string {
+	n := 0
+	return replacePlaceholders(sql, func() string {
+		n++
+		return "@p" + strconv.Itoa(n)
+	})
+}
+
+// replacePlaceholders replaces each ? outside string literals with next()
+func replacePlaceholders(sql string, next func() string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			if inString && i+1 < len(sql) && sql[i+1] == '\'' {
+				b.WriteString("''")
+				i++
+				continue
+			}
+			inString = !inString
+		case !inString && c == '?':
+			b.WriteString(next())
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) (string, error) {
+	viewSQL, args, err := BuildSelectSQL(query)
+	if err != nil {
+		return "", err
+	}
+	return inlinePlaceholders(viewSQL, args), nil
+}
+
+// ============================================================================
+// DDL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCreateTableSQL creates a table; CREATE TABLE name AS GET ... copies rows
+// with SELECT ... INTO, as SQL Server has no CREATE TABLE AS
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.ViewQuery != nil {
+		sourceSQL, args, err := buildSelectSQL(query.ViewQuery, query.Table)
+		if err != nil {
+			return "", err
+		}
+		return inlinePlaceholders(sourceSQL, args), nil
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	var columns []string
+	for _, field := range query.Fields {
+		columns = append(columns, TranslateColumn(getFieldName(field), getFieldValue(field), field.Constraints, field.GeneratedExpr, typeMap))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdentifier(query.Table), strings.Join(columns, ", ")), nil
+}
+
+// BuildAlterTableSQL alters one column; a rename goes through sp_rename
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+	table := QuoteIdentifier(query.Table)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", table, TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return buildRenameSQL(table+"."+QuoteIdentifier(columnName), columnValue, "COLUMN"), nil
+	case "MODIFY_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("MODIFY_COLUMN requires column type")
+		}
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", table, TranslateColumn(columnName, columnValue, field.Constraints, nil, typeMap)), nil
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+// buildRenameSQL renders EXEC sp_rename N'[users].[name]', N'full_name', N'COLUMN'
+// The object is named as in SQL; the new name is taken literally, so it is
+// never bracketed and never schema-qualified.
+func buildRenameSQL(object, newName, objectType string) string {
+	if dot := strings.LastIndex(newName, "."); dot >= 0 {
+		newName = newName[dot+1:]
+	}
+	sql := fmt.Sprintf("EXEC sp_rename %s, %s", QuoteString(object), QuoteString(newName))
+	if objectType != "" {
+		sql += ", " + QuoteString(objectType)
+	}
+	return sql
+}
+
+// BuildDropTableSQL drops a table; SQL Server cannot drop the foreign keys
+// that reference it along with it
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Cascade {
+		return "", fmt.Errorf("SQL Server has no DROP TABLE ... CASCADE: drop the foreign keys that reference %s first", query.Table)
+	}
+	return "DROP TABLE " + QuoteIdentifier(query.Table), nil
+}
+
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
+}
+
+func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.NewName == "" {
+		return "", fmt.Errorf("no new name specified for RENAME TABLE")
+	}
+	return buildRenameSQL(QuoteIdentifier(query.Table), query.NewName, ""), nil
+}
+
+func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index details specified")
+	}
+
+	indexName := getFieldName(query.Fields[0])
+	columnName := getFieldValue(query.Fields[0])
+
+	indexType := "INDEX"
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			indexType = "UNIQUE INDEX"
+		case "FULLTEXT", "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by SQL Server", strings.ToUpper(constraint))
+		}
+	}
+
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, QuoteIdentifier(indexName), QuoteIdentifier(query.Table), quoteIdentifierList(columnName)), nil
+}
+
+// BuildDropIndexSQL drops an index; index names are unique per table, so the table is named
+func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index name specified")
+	}
+	return fmt.Sprintf("DROP INDEX %s ON %s", QuoteIdentifier(getFieldName(query.Fields[0])), QuoteIdentifier(query.Table)), nil
+}
+
+func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE VIEW", query)
+}
+
+func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("ALTER VIEW", query)
+}
+
+func buildViewSQL(statement string, query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no view query specified")
+	}
+	viewSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s AS %s", statement, QuoteIdentifier(query.ViewName), viewSQL), nil
+}
+
+func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	return fmt.Sprintf("DROP VIEW %s", QuoteIdentifier(query.ViewName)), nil
+}
+
+func BuildCreateDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified for CREATE DATABASE")
+	}
+	return fmt.Sprintf("CREATE DATABASE %s", QuoteIdentifier(query.DatabaseName)), nil
+}
+
+func BuildDropDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified for DROP DATABASE")
+	}
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", QuoteIdentifier(query.DatabaseName)), nil
+}
+
+// TranslateColumn renders a column definition from the SQL Server type map
+// A size replaces the mapped default: STRING(100) is NVARCHAR(100). A
+// generated column is a persisted computed column, which takes no type.
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) string {
+	if generated != nil {
+		columnDef := fmt.Sprintf("%s AS (%s) PERSISTED", QuoteIdentifier(columnName), BuildExpressionSQL(generated))
+		for _, constraint := range constraints {
+			switch strings.ToUpper(constraint) {
+			case "UNIQUE":
+				columnDef += " UNIQUE"
+			case "NOT_NULL", "NOTNULL":
+				columnDef += " NOT NULL"
+			}
+		}
+		return columnDef
+	}
+
+	// SQL Server has no arrays - TYPE[] is stored as JSON text
+	if mapping.IsArrayType(columnType) {
+		columnType = mapping.ArrayTypeMap["SQLServer"]
+	}
+
+	baseType := columnType
+	params := ""
+	if idx := strings.Index(columnType, "("); idx != -1 {
+		baseType = columnType[:idx]
+		if endIdx := strings.Index(columnType, ")"); endIdx != -1 {
+			params = columnType[idx : endIdx+1]
+		}
+	}
+
+	sqlServerType, exists := typeMap["SQLServer"][strings.ToUpper(baseType)]
+	if !exists {
+		sqlServerType = baseType
+	}
+	if params != "" && !strings.Contains(sqlServerType, " ") {
+		if idx := strings.Index(sqlServerType, "("); idx != -1 {
+			sqlServerType = sqlServerType[:idx]
+		}
+		sqlServerType += params
+	}
+
+	columnDef := fmt.Sprintf("%s %s", QuoteIdentifier(columnName), sqlServerType)
+
+	// AUTO already carries PRIMARY KEY
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			columnDef += " UNIQUE"
+		case "NOT_NULL", "NOTNULL":
+			columnDef += " NOT NULL"
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			if !strings.Contains(columnDef, "PRIMARY KEY") {
+				columnDef += " PRIMARY KEY"
+			}
+		}
+	}
+
+	return columnDef
+}
+
+// ============================================================================
+// DQL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectClause := "*"
+	if len(query.Columns) > 0 {
+		selectClause = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("SELECT%s %s FROM %s", topClause(query.Limit, query.Offset), selectClause, QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	for _, join := range query.Joins {
+		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		if joinType == "CROSS" {
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
+			continue
+		}
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
+		sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
+	}
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, len(query.OrderBy) > 0, query.Limit, query.Offset), args
+}
+
+// buildStringAggSQL builds: STRING_AGG(field, 'sep') [WITHIN GROUP (ORDER BY ...)]
+// STRING_AGG needs SQL Server 2017 and takes no DISTINCT.
+func buildStringAggSQL(agg *pb.AggregateClause, distinct bool) (string, error) {
+	if distinct {
+		return "", fmt.Errorf("SQL Server STRING_AGG cannot take DISTINCT values")
+	}
+	sql := fmt.Sprintf("STRING_AGG(%s, %s)", quoteColumnRef(getAggField(agg)), QuoteString(agg.Separator))
+	if len(agg.OrderBy) > 0 {
+		sql += " WITHIN GROUP (ORDER BY " + buildOrderByList(agg.OrderBy) + ")"
+	}
+	return sql, nil
+}
+
+func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var selectClause string
+	var args []interface{}
+
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+
+		switch {
+		case aggField == "" || aggField == "*":
+			if query.Distinct {
+				selectClause = "COUNT(*)"
+			} else {
+				selectClause = fmt.Sprintf("%s(*)", aggFunc)
+			}
+		case aggFunc == "STRING AGG":
+			stringAgg, err := buildStringAggSQL(query.Aggregate, query.Distinct)
+			if err != nil {
+				return "", nil, err
+			}
+			selectClause = stringAgg
+		case query.Distinct:
+			selectClause = fmt.Sprintf("%s(DISTINCT %s)", aggFunc, aggField)
+		default:
+			selectClause = fmt.Sprintf("%s(%s)", aggFunc, aggField)
+		}
+		if len(query.GroupBy) > 0 {
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
+		}
+	} else {
+		selectClause = "COUNT(*)"
+	}
+
+	// Paging an ungrouped aggregate pages the rows it reads
+	if (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0 {
+		innerSQL := fmt.Sprintf("SELECT%s * FROM %s", topClause(query.Limit, query.Offset), QuoteIdentifier(query.Table))
+		whereClause, whereArgs := BuildWhereClause(query.Conditions)
+		innerSQL += whereClause
+		args = append(args, whereArgs...)
+		if len(query.OrderBy) > 0 {
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		innerSQL = paginate(innerSQL, len(query.OrderBy) > 0, query.Limit, query.Offset)
+		return fmt.Sprintf("SELECT %s FROM (%s) AS subquery", selectClause, innerSQL), args, nil
+	}
+
+	sql := fmt.Sprintf("SELECT%s %s FROM %s", topClause(query.Limit, query.Offset), selectClause, QuoteIdentifier(query.Table))
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.Having) > 0 {
+		havingClause, havingArgs := BuildHavingClause(query.Having)
+		sql += havingClause
+		args = append(args, havingArgs...)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, len(query.OrderBy) > 0, query.Limit, query.Offset), args, nil
+}
+
+// BuildWindowSQL creates window function queries
+// T-SQL RANGE frames take only UNBOUNDED and CURRENT ROW bounds, so a
+// numeric RANGE offset is not supported.
+func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	selectParts := []string{"*"}
+
+	for _, wf := range query.WindowFunctions {
+		windowFunc := strings.ReplaceAll(strings.ToUpper(wf.Function), " ", "_")
+
+		var funcSQL string
+		ordered := true
+		switch windowFunc {
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
+		case "NTILE":
+			buckets := wf.Buckets
+			if buckets <= 0 {
+				buckets = 4
+			}
+			funcSQL = fmt.Sprintf("NTILE(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
+			ordered = false
+		default:
+			funcSQL = fmt.Sprintf("%s()", windowFunc)
+		}
+
+		var overParts []string
+		if len(wf.PartitionBy) > 0 {
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
+		}
+		if len(wf.OrderBy) > 0 {
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		} else if ordered {
+			// Ranking and offset functions require an ORDER BY
+			overParts = append(overParts, "ORDER BY (SELECT NULL)")
+		}
+		if wf.FrameUnit != "" {
+			if strings.ToUpper(wf.FrameUnit) == "RANGE" && (isOffsetBound(wf.FrameStart) || isOffsetBound(wf.FrameEnd)) {
+				return "", nil, &mapping.ErrNotSupported{Database: "SQLServer", Feature: "RANGE frame with an n PRECEDING or n FOLLOWING bound"}
+			}
+			overParts = append(overParts, fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd))
+		}
+
+		column := fmt.Sprintf("%s OVER (%s)", funcSQL, strings.Join(overParts, " "))
+		if wf.Alias != "" {
+			column += " AS " + QuoteIdentifier(wf.Alias)
+		}
+		selectParts = append(selectParts, column)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args, nil
+}
+
+// isOffsetBound reports whether a frame bound is n PRECEDING or n FOLLOWING
+func isOffsetBound(bound string) bool {
+	upper := strings.ToUpper(bound)
+	return !strings.HasPrefix(upper, "UNBOUNDED") && upper != "CURRENT ROW"
+}
+
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, buildLiteralSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM(amount), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// BuildSetOperationSQL joins two queries with UNION / INTERSECT / EXCEPT
+// T-SQL takes no ORDER BY, TOP or OFFSET on a query inside a set operation,
+// so a query with its own, or a nested set operation, is read from a derived
+// table. The outer ORDER BY applies to the combined result, which is also read
+// from a derived table when it is paged.
+func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	setOp := query.SetOperation
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(setOp.LeftQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(setOp.RightQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql := fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL)
+	if query.Limit > 0 || query.Offset > 0 {
+		sql = fmt.Sprintf("SELECT%s * FROM (%s) AS %s", topClause(query.Limit, query.Offset), sql, QuoteIdentifier("u"))
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, len(query.OrderBy) > 0, query.Limit, query.Offset), append(leftArgs, rightArgs...), nil
+}
+
+// buildSetOperandSQL builds one query of a set operation, which may itself be
+// a set operation, a join or an aggregate
+// A derived table may only be ordered to pick its TOP or OFFSET rows, so an
+// ORDER BY without LIMIT or OFFSET is rejected rather than dropped.
+func buildSetOperandSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var sql string
+	var args []interface{}
+	var err error
+	switch {
+	case query.SetOperation != nil:
+		sql, args, err = BuildSetOperationSQL(query)
+	case len(query.Joins) > 0:
+		sql, args = BuildJoinSQL(query)
+	case query.Aggregate != nil:
+		sql, args, err = BuildAggregateSQL(query)
+	default:
+		sql, args, err = BuildSelectSQL(query)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if len(query.OrderBy) > 0 && query.Limit <= 0 && query.Offset <= 0 {
+		return "", nil, fmt.Errorf("SQL Server cannot order a query inside a set operation without LIMIT or OFFSET: order the combined result instead")
+	}
+	if query.SetOperation != nil || len(query.OrderBy) > 0 || query.Limit > 0 || query.Offset > 0 {
+		sql = fmt.Sprintf("SELECT * FROM (%s) AS %s", sql, QuoteIdentifier("q"))
+	}
+	return sql, args, nil
+}
+
+func setOperator(operationType string) string {
+	switch strings.ToUpper(operationType) {
+	case "UNION_ALL", "UNION ALL":
+		return "UNION ALL"
+	case "INTERSECT":
+		return "INTERSECT"
+	case "EXCEPT":
+		return "EXCEPT"
+	default:
+		return "UNION"
+	}
+}
+
+func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+func BuildHavingClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " HAVING " + clause, args
+}
+
+// ============================================================================
+// TCL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSetTransactionSQL sets the isolation level of the session's following
+// transactions. SQL Server has no read-only transactions.
+func BuildSetTransactionSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ReadOnly {
+		return "", fmt.Errorf("SQL Server has no READ ONLY transactions")
+	}
+	return "SET TRANSACTION ISOLATION LEVEL " + TranslateIsolationLevel(query.IsolationLevel), nil
+}
+
+func TranslateIsolationLevel(level string) string {
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(level), "_", " ")) {
+	case "READ UNCOMMITTED":
+		return "READ UNCOMMITTED"
+	case "REPEATABLE READ":
+		return "REPEATABLE READ"
+	case "SERIALIZABLE":
+		return "SERIALIZABLE"
+	case "SNAPSHOT":
+		return "SNAPSHOT"
+	default:
+		return "READ COMMITTED"
+	}
+}
+
+// BuildSavepointSQL marks a savepoint: SAVE TRANSACTION name
+func BuildSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("SAVE TRANSACTION %s", QuoteIdentifier(savepointName)), nil
+}
+
+// BuildRollbackToSavepointSQL rolls back to a savepoint: ROLLBACK TRANSACTION name
+func BuildRollbackToSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("ROLLBACK TRANSACTION %s", QuoteIdentifier(savepointName)), nil
+}
+
+// ============================================================================
+// CTE OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCTESQL creates WITH name AS (...) SELECT ...
+// T-SQL writes a recursive CTE without RECURSIVE, and its members must be
+// joined with UNION ALL.
+func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Cte == nil {
+		return "", nil, fmt.Errorf("no CTE specified")
+	}
+	if query.Cte.Recursive {
+		if setOp := query.Cte.CteQuery.GetSetOperation(); setOp != nil && setOperator(setOp.OperationType) != "UNION ALL" {
+			return "", nil, fmt.Errorf("SQL Server joins the members of recursive CTE %s with UNION ALL only", query.Cte.CteName)
+		}
+	}
+	cteSQL, params, err := buildCTEBodySQL(query.Cte.CteQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Cte.CteName))
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs, err = BuildSelectSQL(query.Cte.MainQuery)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("WITH %s AS (%s) %s", QuoteIdentifier(query.Cte.CteName), cteSQL, mainSQL), params, nil
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+func buildCTEBodySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query == nil {
+		return "", nil, fmt.Errorf("no CTE query specified")
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		leftSQL, leftArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		rightSQL, rightArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL), append(leftArgs, rightArgs...), nil
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// ============================================================================
+// SUBQUERY OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSubquerySQL creates an IN subquery, or an EXISTS check selected as 1 / 0
+func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Subquery == nil || query.Subquery.Subquery == nil {
+		return "", nil, fmt.Errorf("no subquery specified")
+	}
+
+	subquerySQL, subArgs, err := BuildSelectSQL(query.Subquery.Subquery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.ToUpper(query.Subquery.SubqueryType) == "EXISTS" {
+		return fmt.Sprintf("SELECT CASE WHEN EXISTS (%s) THEN 1 ELSE 0 END", subquerySQL), subArgs, nil
+	}
+	if query.Table == "" {
+		return "", nil, fmt.Errorf("IN subquery requires an outer table")
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
+	var args []interface{}
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := buildConditionsRecursive(query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
+	}
+	sql += fmt.Sprintf("%s IN (%s)", BuildExpressionSQL(query.Subquery.FieldExpr), subquerySQL)
+	return sql, append(args, subArgs...), nil
+}
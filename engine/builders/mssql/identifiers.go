@@ -0,0 +1,62 @@
+package mssql
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted
+var valueKeywords = map[string]bool{
+	"CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "SYSTEM_USER": true,
+	"NULL": true, "DEFAULT": true,
+}
+
+// QuoteIdentifier returns name in brackets with embedded ] doubled: [users]
+// Brackets work whatever QUOTED_IDENTIFIER is set to. Qualified names
+// (schema.table, table.column) are bracketed part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, "[") {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// quoteIdentifierList quotes a comma-separated list of names (index columns)
+func quoteIdentifierList(names string) string {
+	parts := strings.Split(names, ",")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteColumnRef quotes a FIELD expression value, leaving SQL value keywords alone
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return strings.ToUpper(name)
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteString returns s as a Unicode (N'...') string literal
+func QuoteString(s string) string {
+	return "N'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,147 @@
+package mssql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (JSON_VALUE / JSON_QUERY / JSON_MODIFY / JSON_PATH_EXISTS)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and a SQL Server path
+// data->'address'->>'city' = data, $.address.city; data->0 = data, $[0]
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []*pb.Expression
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]*pb.Expression{expr.Right}, keys...)
+		expr = expr.Left
+	}
+	path := "$"
+	for _, key := range keys {
+		path += jsonPathStep(key)
+	}
+	return expr, path
+}
+
+// jsonPathStep renders one path step: [n] for array indexes, .key or ."odd key" for members
+func jsonPathStep(key *pb.Expression) string {
+	if key == nil {
+		return ""
+	}
+	if key.Type == "NUMBER" {
+		return "[" + key.Value + "]"
+	}
+	if isJSONPathIdentifier(key.Value) {
+		return "." + key.Value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key.Value)
+	return `."` + escaped + `"`
+}
+
+// isJSONPathIdentifier reports whether key can appear unquoted in a JSON path
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			continue
+		}
+		if i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// buildJSONPathSQL renders ->> as JSON_VALUE (a scalar) and -> as JSON_QUERY
+// (an object or array); each returns NULL for the other kind of value
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, path := jsonPathTarget(expr)
+	function := "JSON_QUERY"
+	if expr.Operator == "->>" {
+		function = "JSON_VALUE"
+	}
+	return fmt.Sprintf("%s(%s, %s)", function, BuildExpressionSQL(column), QuoteString(path))
+}
+
+// buildJSONCondition renders the JSONB key operators ?, ?| and ?& with
+// JSON_PATH_EXISTS, which needs SQL Server 2022
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	if cond.Operator == "?" {
+		return fmt.Sprintf("JSON_PATH_EXISTS(%s, %s) = 1", field, QuoteString("$"+jsonPathStep(cond.ValueExpr))), nil
+	}
+	// ?| = any key, ?& = all keys
+	if len(cond.ValuesExpr) == 0 {
+		if cond.Operator == "?|" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+	logic := " OR "
+	if cond.Operator == "?&" {
+		logic = " AND "
+	}
+	parts := make([]string, len(cond.ValuesExpr))
+	for i, key := range cond.ValuesExpr {
+		parts[i] = fmt.Sprintf("JSON_PATH_EXISTS(%s, %s) = 1", field, QuoteString("$"+jsonPathStep(key)))
+	}
+	return "(" + strings.Join(parts, logic) + ")", nil
+}
+
+// buildJSONValueSQL renders a value stored into a JSON document
+// JSON_MODIFY writes the JSON type of the SQL value it is given, so numbers
+// bind as numbers and booleans are BIT; NULL deletes the key.
+func buildJSONValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr == nil {
+		return "NULL", nil
+	}
+	if expr.Type != "STRING" {
+		switch strings.ToUpper(expr.Value) {
+		case "NULL":
+			return "NULL", nil
+		case "TRUE":
+			return "CAST(1 AS BIT)", nil
+		case "FALSE":
+			return "CAST(0 AS BIT)", nil
+		}
+	}
+	if expr.Type == "FIELD" && valueKeywords[strings.ToUpper(expr.Value)] {
+		return strings.ToUpper(expr.Value), nil
+	}
+	if expr.Type == "NUMBER" {
+		if n, err := strconv.ParseInt(expr.Value, 10, 64); err == nil {
+			return "?", []interface{}{n}
+		}
+		if f, err := strconv.ParseFloat(expr.Value, 64); err == nil {
+			return "?", []interface{}{f}
+		}
+	}
+	return "?", []interface{}{expr.Value}
+}
+
+// buildJSONSetSQL renders the JSON path assignments to one column as a single
+// col = JSON_MODIFY(JSON_MODIFY(col, '$.a', v1), '$.b', v2); SQL Server
+// rejects a column assigned twice in one UPDATE, so they are nested.
+func buildJSONSetSQL(fields []*pb.QueryField) (string, []interface{}) {
+	column, _ := jsonPathTarget(fields[0].NameExpr)
+	columnSQL := BuildExpressionSQL(column)
+	modified := columnSQL
+	var args []interface{}
+	for _, field := range fields {
+		_, path := jsonPathTarget(field.NameExpr)
+		valueSQL, valueArgs := buildJSONValueSQL(field.ValueExpr)
+		modified = fmt.Sprintf("JSON_MODIFY(%s, %s, %s)", modified, QuoteString(path), valueSQL)
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("%s = %s", columnSQL, modified), args
+}
@@ -0,0 +1,168 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// ============================================================================
+// UPSERT - MERGE
+// ============================================================================
+
+// Aliases of the MERGE target table and of the rows being merged; EXCLUDED.col
+// in an update expression reads the merged row, as s.[col]
+const (
+	mergeTarget = "t"
+	mergeSource = "s"
+)
+
+// BuildUpsertSQL creates UPSERT as a MERGE of one row
+func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Upsert == nil {
+		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
+	}
+	return buildMergeSQL(query.Table, []*pb.BulkInsertRow{{Fields: query.Fields}}, query.Upsert, query.Returning)
+}
+
+// BuildBulkUpsertSQL creates BULK UPSERT as one MERGE of all rows
+func BuildBulkUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_UPSERT requires data rows")
+	}
+	if query.Upsert == nil {
+		return "", nil, fmt.Errorf("BULK_UPSERT requires conflict fields")
+	}
+	return buildMergeSQL(query.Table, query.BulkData, query.Upsert, query.Returning)
+}
+
+// buildMergeSQL renders:
+//
+//	MERGE INTO [users] WITH (HOLDLOCK) AS t
+//	USING (VALUES (?, ?), ...) AS s ([email], [name])
+//	ON (t.[email] = s.[email])
+//	WHEN MATCHED THEN UPDATE SET [name] = s.[name]
+//	WHEN NOT MATCHED THEN INSERT ([email], [name]) VALUES (s.[email], s.[name]);
+//
+// HOLDLOCK keeps two concurrent upserts of a new key from both inserting it.
+// SQL Server requires the terminating semicolon on MERGE.
+func buildMergeSQL(table string, rows []*pb.BulkInsertRow, upsert *pb.UpsertClause, returning []*pb.Expression) (string, []interface{}, error) {
+	if upsert.ConflictConstraint != "" {
+		return "", nil, fmt.Errorf("SQL Server cannot target constraint %s: name the conflict columns instead", upsert.ConflictConstraint)
+	}
+	if len(upsert.ConflictFields) == 0 {
+		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
+	}
+	if len(upsert.ConflictWhere) > 0 {
+		return "", nil, fmt.Errorf("SQL Server MERGE has no conflict target predicate")
+	}
+
+	var columns []string
+	for _, field := range rows[0].Fields {
+		columns = append(columns, QuoteIdentifier(getFieldName(field)))
+	}
+
+	var values []string
+	var args []interface{}
+	for _, row := range rows {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	keys := map[string]bool{}
+	var on []string
+	for _, cf := range upsert.ConflictFields {
+		column := QuoteIdentifier(cf.Value)
+		keys[strings.ToLower(cf.Value)] = true
+		on = append(on, fmt.Sprintf("%s.%s = %s.%s", mergeTarget, column, mergeSource, column))
+	}
+
+	sql := fmt.Sprintf("MERGE INTO %s WITH (HOLDLOCK) AS %s USING (VALUES %s) AS %s (%s) ON (%s)",
+		QuoteIdentifier(table), mergeTarget, strings.Join(values, ", "), mergeSource, strings.Join(columns, ", "), strings.Join(on, " AND "))
+
+	var updateParts []string
+	for _, field := range upsert.UpdateFields {
+		name := getFieldName(field)
+		if keys[strings.ToLower(name)] {
+			continue
+		}
+		column := QuoteIdentifier(name)
+		if field.ValueExpr == nil {
+			// No value: take the row being merged
+			updateParts = append(updateParts, fmt.Sprintf("%s = %s.%s", column, mergeSource, column))
+			continue
+		}
+		value := field.ValueExpr
+		if builderutil.IsComputed(value) {
+			value = qualifyColumns(value, mergeTarget)
+		}
+		valueSQL, valueArgs := buildValueSQL(value)
+		updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, valueSQL))
+		args = append(args, valueArgs...)
+	}
+	if len(updateParts) > 0 {
+		sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(updateParts, ", ")
+	}
+
+	sourceColumns := make([]string, len(columns))
+	for i, column := range columns {
+		sourceColumns[i] = mergeSource + "." + column
+	}
+	sql += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(columns, ", "), strings.Join(sourceColumns, ", "))
+
+	return sql + BuildOutputClause(returning, "INSERTED") + ";", args, nil
+}
+
+// qualifyColumns returns a copy of expr with its bare column references read
+// from row: visits + 1 on the MERGE target is t.visits + 1, and an OUTPUT
+// column name is INSERTED.name
+func qualifyColumns(expr *pb.Expression, row string) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	qualified := proto.Clone(expr).(*pb.Expression)
+	var walk func(e *pb.Expression)
+	walk = func(e *pb.Expression) {
+		if e == nil {
+			return
+		}
+		if e.Type == "FIELD" && !strings.Contains(e.Value, ".") && !valueKeywords[strings.ToUpper(e.Value)] {
+			e.Value = row + "." + e.Value
+		}
+		walk(e.Left)
+		walk(e.Right)
+		for _, arg := range e.FunctionArgs {
+			walk(arg)
+		}
+	}
+	walk(qualified)
+	return qualified
+}
+
+// excludedColumn returns col for an EXCLUDED.col reference
+func excludedColumn(name string) (string, bool) {
+	if len(name) > len("EXCLUDED.") && strings.EqualFold(name[:len("EXCLUDED.")], "EXCLUDED.") {
+		return name[len("EXCLUDED."):], true
+	}
+	return "", false
+}
+
+// outputColumn splits an INSERTED.col or DELETED.col reference, whose row
+// prefix is written as a keyword rather than a bracketed name
+func outputColumn(name string) (string, string, bool) {
+	for _, row := range []string{"INSERTED", "DELETED"} {
+		prefix := row + "."
+		if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			return row, name[len(prefix):], true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,33 @@
+package mssql
+
+import "fmt"
+
+// ============================================================================
+// PAGING (TOP / OFFSET ... FETCH NEXT)
+// ============================================================================
+
+// topClause returns TOP (n) for a LIMIT without OFFSET, placed after SELECT
+// [DISTINCT]; a page with an offset is written by paginate instead
+func topClause(limit, offset int32) string {
+	if limit <= 0 || offset > 0 {
+		return ""
+	}
+	return fmt.Sprintf(" TOP (%d)", limit)
+}
+
+// paginate appends OFFSET n ROWS FETCH NEXT m ROWS ONLY when the query has an
+// offset. OFFSET is part of ORDER BY in T-SQL, so an unordered query is
+// ordered by (SELECT NULL), which keeps the rows in no particular order.
+func paginate(sql string, hasOrderBy bool, limit, offset int32) string {
+	if offset <= 0 {
+		return sql
+	}
+	if !hasOrderBy {
+		sql += " ORDER BY (SELECT NULL)"
+	}
+	sql += fmt.Sprintf(" OFFSET %d ROWS", offset)
+	if limit > 0 {
+		sql += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return sql
+}
@@ -5,6 +5,10 @@ import (
 	"strconv"
 	"strings"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 )
 
@@ -71,6 +75,73 @@ func getAggField(agg *pb.AggregateClause) string {
 	return agg.FieldExpr.Value
 }
 
+// buildValueSQL renders a value position: computed expressions inline,
+// CURRENT_TIMESTAMP and the other value keywords bare, and anything else as
+// a ? bind, so a bare word such as pending is stored as the string 'pending'
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" && valueKeywords[strings.ToUpper(expr.Value)] {
+		return expr.Value, nil
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{ConvertMySQLValue(value)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches cannot always take ? placeholders)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER", "BOOLEAN":
+		return expr.Value
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
 
 // ============================================================================
 // CRUD OPERATIONS - SQL BUILDERS
@@ -94,54 +165,40 @@ func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
 				caseSQL := "CASE"
 				for _, cond := range col.ExpressionObj.CaseConditions {
 					condSQL := buildConditionSQL(cond.Condition)
-					// Check if THEN is an expression or literal
-					if cond.ThenExpr != nil && (cond.ThenExpr.Type == "BINARY" || cond.ThenExpr.Type == "FUNCTION") {
-						thenSQL := BuildExpressionSQL(cond.ThenExpr)
-						caseSQL += fmt.Sprintf(" WHEN %s THEN %s", condSQL, thenSQL)
-					} else {
-						caseSQL += fmt.Sprintf(" WHEN %s THEN ?", condSQL)
-						args = append(args, cond.ThenExpr.Value)
-					}
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", condSQL, thenSQL)
+					args = append(args, thenArgs...)
 				}
 				if col.ExpressionObj.CaseElse != nil {
-					// Check if ELSE is an expression or literal
-					if col.ExpressionObj.CaseElse.Type == "BINARY" || col.ExpressionObj.CaseElse.Type == "FUNCTION" {
-						elseSQL := BuildExpressionSQL(col.ExpressionObj.CaseElse)
-						caseSQL += fmt.Sprintf(" ELSE %s", elseSQL)
-					} else {
-						caseSQL += " ELSE ?"
-						args = append(args, col.ExpressionObj.CaseElse.Value)
-					}
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
 				}
 				caseSQL += " END"
 				if col.Alias != "" {
-					caseSQL += " AS " + col.Alias
+					caseSQL += " AS " + QuoteIdentifier(col.Alias)
 				}
 				colParts = append(colParts, caseSQL)
 			} else if col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW" {
 				windowSQL := buildWindowExprSQL(col.ExpressionObj)
 				if col.Alias != "" {
-					windowSQL += " AS " + col.Alias
+					windowSQL += " AS " + QuoteIdentifier(col.Alias)
 				}
 				colParts = append(colParts, windowSQL)
 			} else {
 				colStr := BuildExpressionSQL(col.ExpressionObj)
 				if col.Alias != "" {
-					colStr += " AS " + col.Alias
+					colStr += " AS " + QuoteIdentifier(col.Alias)
 				}
 				colParts = append(colParts, colStr)
 			}
 		}
 		columns = strings.Join(colParts, ", ")
 	} else if len(query.Columns) > 0 {
-		var colStrs []string
-		for _, col := range query.Columns {
-			colStrs = append(colStrs, col.Value)
-		}
-		columns = strings.Join(colStrs, ", ")
+		columns = buildExpressionList(query.Columns)
 	}
 	
-	sql := fmt.Sprintf("%s %s FROM `%s`", selectClause, columns, query.Table)
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
 	
 	whereClause, whereArgs := BuildWhereClause(query.Conditions)
 	sql += whereClause
@@ -151,38 +208,87 @@ func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		sql += " ORDER BY "
 		orderParts := []string{}
 		for _, ob := range query.OrderBy {
-			orderParts = append(orderParts, fmt.Sprintf("%s %s", ob.FieldExpr.Value, ob.Direction))
+			field := BuildExpressionSQL(ob.FieldExpr)
+			if mapping.IsSearchRankField(getOrderByField(ob)) {
+				if rank := buildSearchRank(query.Conditions); rank != "" {
+					field = rank
+				}
+			}
+			orderParts = append(orderParts, fmt.Sprintf("%s %s", field, ob.Direction))
 		}
 		sql += strings.Join(orderParts, ", ")
 	}
 	
-	if query.Limit > 0 {
-		sql += fmt.Sprintf(" LIMIT %d", query.Limit)
-		if query.Offset > 0 {
-			sql += fmt.Sprintf(" OFFSET %d", query.Offset)
+	sql += buildLimitSQL(query.Limit, query.Offset)
+	sql += buildLockClause(query)
+
+	return sql, args
+}
+
+// buildLimitSQL appends LIMIT n [OFFSET m]; MySQL has no OFFSET without a
+// LIMIT, so an offset alone takes the largest row count
+func buildLimitSQL(limit, offset int32) string {
+	if limit > 0 {
+		if offset > 0 {
+			return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
 		}
-	} else if query.Offset > 0 {
-		sql += fmt.Sprintf(" LIMIT 18446744073709551615 OFFSET %d", query.Offset)
+		return fmt.Sprintf(" LIMIT %d", limit)
+	}
+	if offset > 0 {
+		return fmt.Sprintf(" LIMIT 18446744073709551615 OFFSET %d", offset)
 	}
+	return ""
+}
 
-	return sql, args
+// buildLockClause renders the row lock after LIMIT
+// A plain FOR SHARE becomes LOCK IN SHARE MODE so it also runs on 5.7;
+// NOWAIT, SKIP LOCKED and FOR SHARE with either need MySQL 8.0+
+func buildLockClause(query *pb.RelationalQuery) string {
+	if query.Lock == "" {
+		return ""
+	}
+	if query.Lock == "SHARE" && query.LockWait == "" {
+		return " LOCK IN SHARE MODE"
+	}
+	sql := " FOR " + query.Lock
+	if query.LockWait != "" {
+		sql += " " + query.LockWait
+	}
+	return sql
 }
 
 func buildConditionSQL(cond *pb.QueryCondition) string {
 	if cond == nil {
 		return ""
 	}
-	field := BuildExpressionSQL(cond.FieldExpr)
-	value := getCondValue(cond)
-	// Quote string values (non-numeric)
-	if _, err := strconv.ParseFloat(value, 64); err != nil {
-		// Not a number - check if boolean
-		upper := strings.ToUpper(value)
-		if upper != "TRUE" && upper != "FALSE" && upper != "NULL" {
-			value = fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
 		}
+		return strings.Join(parts, " ")
 	}
-	return fmt.Sprintf("%s %s %s", field, cond.Operator, value)
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	case "ILIKE", "NOT_ILIKE":
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, mysqlOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+	}
+	return fmt.Sprintf("%s %s %s", field, mysqlOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
 }
 
 func buildWindowExprSQL(expr *pb.Expression) string {
@@ -194,7 +300,7 @@ func buildWindowExprSQL(expr *pb.Expression) string {
 		field := "id"
 		for _, arg := range expr.FunctionArgs {
 			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
-				field = arg.Value
+				field = QuoteIdentifier(arg.Value)
 				break
 			}
 		}
@@ -215,13 +321,13 @@ func buildWindowExprSQL(expr *pb.Expression) string {
 	var partitionParts, orderParts []string
 	for _, arg := range expr.FunctionArgs {
 		if strings.HasPrefix(arg.Value, "PARTITION:") {
-			partitionParts = append(partitionParts, strings.TrimPrefix(arg.Value, "PARTITION:"))
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
 		} else if strings.HasPrefix(arg.Value, "ORDER:") {
 			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
 			if len(parts) >= 2 {
-				orderParts = append(orderParts, fmt.Sprintf("%s %s", parts[0], parts[1]))
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
 			} else if len(parts) == 1 {
-				orderParts = append(orderParts, parts[0]+" ASC")
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
 			}
 		}
 	}
@@ -243,39 +349,71 @@ func buildWindowExprSQL(expr *pb.Expression) string {
 
 // BuildInsertSQL creates parameterized INSERT query
 func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query), nil
+	}
+
 	var fields, placeholders []string
 	var args []interface{}
 
 	for _, field := range query.Fields {
-		fields = append(fields, getFieldName(field))
-		placeholders = append(placeholders, "?")
-		args = append(args, ConvertMySQLValue(getFieldValue(field)))
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
 	}
 
-	sql := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)",
-		query.Table, strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
 
 	return sql, args
 }
 
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) string {
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + buildViewQuerySQL(query.ViewQuery)
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
 // BuildUpdateSQL creates parameterized UPDATE query with expression support
 func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	var setParts []string
 	var args []interface{}
 
 	for _, field := range query.Fields {
-		fieldName := getFieldName(field)
-		
-		if field.ValueExpr != nil && (field.ValueExpr.Type == "BINARY" || field.ValueExpr.Type == "FUNCTION" || field.ValueExpr.Type == "CASEWHEN") {
-			exprSQL := BuildExpressionSQL(field.ValueExpr)
-			setParts = append(setParts, fmt.Sprintf("%s = %s", fieldName, exprSQL))
-		} else {
-			setParts = append(setParts, fmt.Sprintf("%s = ?", fieldName))
-			args = append(args, ConvertMySQLValue(getFieldValue(field)))
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			setSQL, setArgs := buildJSONSetSQL(field)
+			setParts = append(setParts, setSQL)
+			args = append(args, setArgs...)
+			continue
 		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
 	}
 
-	sql := fmt.Sprintf("UPDATE `%s` SET %s", query.Table, strings.Join(setParts, ", "))
+	sql := fmt.Sprintf("UPDATE %s SET %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
 
 	whereClause, whereArgs := BuildWhereClause(query.Conditions)
 	sql += whereClause
@@ -286,15 +424,18 @@ func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 
 // BuildDeleteSQL creates parameterized DELETE query
 func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
-	sql := fmt.Sprintf("DELETE FROM `%s`", query.Table)
+	sql := fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table))
 	whereClause, args := BuildWhereClause(query.Conditions)
 	sql += whereClause
 	return sql, args
 }
 
-// BuildUpsertSQL creates UPSERT using MySQL's ON DUPLICATE KEY UPDATE
-func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
-	if query.Upsert == nil || len(query.Upsert.ConflictFields) == 0 {
+// BuildUpsertSQL creates UPSERT using MySQL's ON DUPLICATE KEY UPDATE, or
+// INSERT IGNORE for DO NOTHING
+func BuildUpsertSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	// ON DUPLICATE KEY and INSERT IGNORE cover every unique key, so a named
+	// constraint needs no target and DO NOTHING none at all
+	if query.Upsert == nil || (len(query.Upsert.ConflictFields) == 0 && query.Upsert.ConflictConstraint == "" && query.Upsert.ConflictAction != "NOTHING") {
 		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
 	}
 
@@ -302,31 +443,124 @@ func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
 	var args []interface{}
 
 	for _, field := range query.Fields {
-		fieldName := getFieldName(field)
-		fields = append(fields, fieldName)
-		placeholders = append(placeholders, "?")
-		args = append(args, ConvertMySQLValue(getFieldValue(field)))
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
 	}
 
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	if query.Upsert.ConflictAction == "NOTHING" {
+		return insertIgnore(sql), args, nil
+	}
+	updateSQL, updateArgs := buildOnDuplicateKeySQL(query.Upsert, opts)
+	sql += updateSQL
+	args = append(args, updateArgs...)
+
+	return sql, args, nil
+}
+
+// insertIgnore turns INSERT INTO into INSERT IGNORE INTO. Besides duplicate
+// keys, MySQL then also downgrades errors such as out-of-range values to warnings.
+func insertIgnore(sql string) string {
+	return strings.Replace(sql, "INSERT INTO", "INSERT IGNORE INTO", 1)
+}
+
+// buildOnDuplicateKeySQL renders [AS new] ON DUPLICATE KEY UPDATE col = ..., ...
+func buildOnDuplicateKeySQL(upsert *pb.UpsertClause, opts Options) (string, []interface{}) {
 	var updateParts []string
-	for _, field := range query.Fields {
-		fieldName := field.NameExpr.Value
-		isConflictField := false
-		for _, cf := range query.Upsert.ConflictFields {
-			if fieldName == cf.Value {
-				isConflictField = true
-				break
-			}
+	var args []interface{}
+	for _, field := range upsert.UpdateFields {
+		column := QuoteIdentifier(getFieldName(field))
+		if field.ValueExpr == nil {
+			updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, insertedValueRef(getFieldName(field), opts)))
+			continue
 		}
-		if !isConflictField {
-			updateParts = append(updateParts, fmt.Sprintf("%s = VALUES(%s)", fieldName, fieldName))
+		if ref, ok := excludedColumn(field.ValueExpr.Value); ok && field.ValueExpr.Type == "FIELD" {
+			updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, insertedValueRef(ref, opts)))
+			continue
 		}
+		valueSQL, valueArgs := buildValueSQL(rewriteExcludedRefs(field.ValueExpr, opts))
+		updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, valueSQL))
+		args = append(args, valueArgs...)
 	}
 
-	sql := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
-		query.Table, strings.Join(fields, ", "), strings.Join(placeholders, ", "), strings.Join(updateParts, ", "))
+	sql := ""
+	if opts.UseUpsertRowAlias {
+		sql += " AS " + QuoteIdentifier(upsertRowAlias)
+	}
+	return sql + " ON DUPLICATE KEY UPDATE " + strings.Join(updateParts, ", "), args
+}
 
-	return sql, args, nil
+// upsertRowAlias names the proposed row with Options.UseUpsertRowAlias
+const upsertRowAlias = "new"
+
+// insertedValueRef references the proposed value of column in ON DUPLICATE KEY UPDATE
+func insertedValueRef(column string, opts Options) string {
+	if opts.UseUpsertRowAlias {
+		return QuoteIdentifier(upsertRowAlias + "." + column)
+	}
+	return "VALUES(" + QuoteIdentifier(column) + ")"
+}
+
+// rewriteExcludedRefs maps portable EXCLUDED.col references (PostgreSQL
+// spelling) onto the proposed row. The input is left untouched.
+func rewriteExcludedRefs(expr *pb.Expression, opts Options) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	expr = proto.Clone(expr).(*pb.Expression)
+	rewriteExcludedExpr(expr, opts)
+	return expr
+}
+
+func rewriteExcludedExpr(expr *pb.Expression, opts Options) {
+	if expr == nil {
+		return
+	}
+	if expr.Type == "FIELD" {
+		if column, ok := excludedColumn(expr.Value); ok {
+			if opts.UseUpsertRowAlias {
+				expr.Value = upsertRowAlias + "." + column
+			} else {
+				expr.Type = "FUNCTION"
+				expr.FunctionName = "VALUES"
+				expr.FunctionArgs = []*pb.Expression{{Type: "FIELD", Value: column}}
+				expr.Value = ""
+			}
+		}
+		return
+	}
+	rewriteExcludedExpr(expr.Left, opts)
+	rewriteExcludedExpr(expr.Right, opts)
+	for _, arg := range expr.FunctionArgs {
+		rewriteExcludedExpr(arg, opts)
+	}
+	for _, cc := range expr.CaseConditions {
+		rewriteExcludedCondition(cc.Condition, opts)
+		rewriteExcludedExpr(cc.ThenExpr, opts)
+	}
+	rewriteExcludedExpr(expr.CaseElse, opts)
+}
+
+func rewriteExcludedCondition(cond *pb.QueryCondition, opts Options) {
+	if cond == nil {
+		return
+	}
+	rewriteExcludedExpr(cond.FieldExpr, opts)
+	rewriteExcludedExpr(cond.ValueExpr, opts)
+	for _, nested := range cond.Nested {
+		rewriteExcludedCondition(nested, opts)
+	}
+}
+
+// excludedColumn returns col for an EXCLUDED.col reference
+func excludedColumn(name string) (string, bool) {
+	if len(name) > len("EXCLUDED.") && strings.EqualFold(name[:len("EXCLUDED.")], "EXCLUDED.") {
+		return name[len("EXCLUDED."):], true
+	}
+	return "", false
 }
 
 // BuildBulkInsertSQL creates BULK INSERT using multi-row VALUES
@@ -335,28 +569,71 @@ func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error
 		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
 	}
 
-	firstRow := query.BulkData[0]
+	sql, args := buildMultiRowInsertSQL(query.Table, query.BulkData)
+	return sql, args, nil
+}
+
+// BuildBulkUpsertSQL creates BULK UPSERT as multi-row INSERT ... ON DUPLICATE KEY UPDATE
+// (INSERT IGNORE for DO NOTHING), one statement per Options.BulkBatchRows rows
+func BuildBulkUpsertSQL(query *pb.RelationalQuery, opts Options) ([]string, [][]interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return nil, nil, fmt.Errorf("BULK_UPSERT requires data rows")
+	}
+	if query.Upsert == nil || (len(query.Upsert.ConflictFields) == 0 && query.Upsert.ConflictConstraint == "" && query.Upsert.ConflictAction != "NOTHING") {
+		return nil, nil, fmt.Errorf("BULK_UPSERT requires conflict fields")
+	}
+
+	batchRows := opts.bulkBatchRows()
+	if batchRows < 0 {
+		batchRows = len(query.BulkData)
+	}
+
+	updateSQL, updateArgs := buildOnDuplicateKeySQL(query.Upsert, opts)
+
+	var statements []string
+	var batchArgs [][]interface{}
+	for start := 0; start < len(query.BulkData); start += batchRows {
+		end := start + batchRows
+		if end > len(query.BulkData) {
+			end = len(query.BulkData)
+		}
+		sql, args := buildMultiRowInsertSQL(query.Table, query.BulkData[start:end])
+		if query.Upsert.ConflictAction == "NOTHING" {
+			statements = append(statements, insertIgnore(sql))
+			batchArgs = append(batchArgs, args)
+			continue
+		}
+		statements = append(statements, sql+updateSQL)
+		batchArgs = append(batchArgs, append(args, updateArgs...))
+	}
+
+	return statements, batchArgs, nil
+}
+
+// buildMultiRowInsertSQL renders INSERT INTO t (cols) VALUES (...), (...) with the first row's columns
+func buildMultiRowInsertSQL(table string, rows []*pb.BulkInsertRow) (string, []interface{}) {
 	var fields []string
-	for _, field := range firstRow.Fields {
-		fields = append(fields, getFieldName(field))
+	for _, field := range rows[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
 	}
 
 	var valueClauses []string
 	var args []interface{}
 
-	for _, row := range query.BulkData {
+	for _, row := range rows {
 		placeholders := make([]string, len(row.Fields))
 		for i, field := range row.Fields {
-			placeholders[i] = "?"
-			args = append(args, ConvertMySQLValue(getFieldValue(field)))
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
 		}
 		valueClauses = append(valueClauses, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
 	}
 
-	sql := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES %s",
-		query.Table, strings.Join(fields, ", "), strings.Join(valueClauses, ", "))
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		QuoteIdentifier(table), strings.Join(fields, ", "), strings.Join(valueClauses, ", "))
 
-	return sql, args, nil
+	return sql, args
 }
 
 // ============================================================================
@@ -425,9 +702,52 @@ func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}, int)
 		return buildBetweenClauseExpr(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
 	case "NOT_BETWEEN":
 		return buildBetweenClauseExpr(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "LIKE", "NOT_LIKE":
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, mysqlOperator(cond.Operator), valueSQL), args, len(args)
+	case "ILIKE", "NOT_ILIKE":
+		// MySQL has no ILIKE - compare both sides lowercased
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, mysqlOperator(cond.Operator), valueSQL), args, len(args)
+	case "SEARCH":
+		return fmt.Sprintf("MATCH(%s) AGAINST(? IN BOOLEAN MODE)", field), []interface{}{value}, 1
+	case "@>", "<@", "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
 	default:
-		return fmt.Sprintf("%s %s ?", field, cond.Operator), []interface{}{ConvertMySQLValue(value)}, 1
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, cond.Operator, valueSQL), args, len(args)
+	}
+}
+
+// mysqlOperator maps an OQL operator (NOT_LIKE, ILIKE) to its MySQL spelling
+func mysqlOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["MySQL"][op]; ok {
+		return mapped
 	}
+	return op
+}
+
+// findSearchCondition returns the first SEARCH condition (recursive)
+func findSearchCondition(conditions []*pb.QueryCondition) *pb.QueryCondition {
+	for _, cond := range conditions {
+		if cond.Operator == "SEARCH" {
+			return cond
+		}
+		if found := findSearchCondition(cond.Nested); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// buildSearchRank builds the ORDER BY RELEVANCE expression (MATCH score)
+func buildSearchRank(conditions []*pb.QueryCondition) string {
+	cond := findSearchCondition(conditions)
+	if cond == nil {
+		return ""
+	}
+	return fmt.Sprintf("MATCH(%s) AGAINST(%s IN BOOLEAN MODE)",
+		BuildExpressionSQL(cond.FieldExpr), QuoteString(getCondValue(cond)))
 }
 
 func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}, int) {
@@ -439,13 +759,14 @@ func buildInClause(field, operator string, values []*pb.Expression) (string, []i
 	}
 
 	placeholders := make([]string, len(values))
-	args := make([]interface{}, len(values))
+	var args []interface{}
 	for i, v := range values {
-		placeholders[i] = "?"
-		args[i] = ConvertMySQLValue(v.Value)
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
 	}
 
-	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args, len(values)
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args, len(args)
 }
 
 func buildBetweenClause(field, operator, value1, value2 string) (string, []interface{}, int) {
@@ -453,22 +774,11 @@ func buildBetweenClause(field, operator, value1, value2 string) (string, []inter
 }
 
 func buildBetweenClauseExpr(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}, int) {
-	// Check if expressions are complex (BINARY/FUNCTION)
-	if value1Expr != nil && (value1Expr.Type == "BINARY" || value1Expr.Type == "FUNCTION") {
-		val1SQL := BuildExpressionSQL(value1Expr)
-		val2SQL := BuildExpressionSQL(value2Expr)
-		return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), nil, 0
-	}
-	// Simple literals - parameterize
-	val1 := ""
-	val2 := ""
-	if value1Expr != nil {
-		val1 = value1Expr.Value
-	}
-	if value2Expr != nil {
-		val2 = value2Expr.Value
-	}
-	return fmt.Sprintf("%s %s ? AND ?", field, operator), []interface{}{ConvertMySQLValue(val1), ConvertMySQLValue(val2)}, 2
+	// Each bound is rendered on its own: BETWEEN ? AND NOW() is valid
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args, len(args)
 }
 
 // BuildExpressionSQL converts an Expression to SQL
@@ -488,32 +798,35 @@ func BuildExpressionSQL(expr *pb.Expression) string {
 			right = "(" + right + ")"
 		}
 		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
 	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
 		var args []string
 		for _, arg := range expr.FunctionArgs {
 			args = append(args, BuildExpressionSQL(arg))
 		}
-		return fmt.Sprintf("%s(%s)", expr.FunctionName, strings.Join(args, ", "))
+		return mapping.FunctionSQL("MySQL", expr.FunctionName, args)
 	case "CASEWHEN":
 		var caseParts []string
 		caseParts = append(caseParts, "CASE")
 		for _, cond := range expr.CaseConditions {
-			thenValue := cond.ThenExpr.Value
-			if _, err := strconv.Atoi(thenValue); err != nil {
-				thenValue = fmt.Sprintf("'%s'", thenValue)
-			}
 			condSQL := buildConditionSQL(cond.Condition)
-			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", condSQL, thenValue))
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", condSQL, buildLiteralSQL(cond.ThenExpr)))
 		}
 		if expr.CaseElse != nil {
-			elseValue := expr.CaseElse.Value
-			if _, err := strconv.Atoi(elseValue); err != nil {
-				elseValue = fmt.Sprintf("'%s'", elseValue)
-			}
-			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", elseValue))
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
 		}
 		caseParts = append(caseParts, "END")
 		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		return quoteColumnRef(expr.Value)
+	case "COLLATE":
+		return buildCollateSQL(expr)
 	default:
 		return expr.Value
 	}
@@ -565,10 +878,14 @@ func BuildGrantSQL(query *pb.RelationalQuery, isRole bool) (string, error) {
 	}
 
 	privileges := TranslatePermissions(query.Permissions)
+	object, err := buildGrantObject(query)
+	if err != nil {
+		return "", err
+	}
 	if isRole {
-		return fmt.Sprintf("GRANT %s ON %s.* TO %s", strings.Join(privileges, ", "), query.Table, query.PermissionTarget), nil
+		return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), object, QuoteIdentifier(query.PermissionTarget)), nil
 	}
-	return fmt.Sprintf("GRANT %s ON %s.* TO '%s'@'%s'", strings.Join(privileges, ", "), query.Table, query.PermissionTarget, DefaultMySQLUserHost), nil
+	return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), object, quoteAccount(query.PermissionTarget, query.UserHost)), nil
 }
 
 func BuildRevokeSQL(query *pb.RelationalQuery, isRole bool) (string, error) {
@@ -580,24 +897,65 @@ func BuildRevokeSQL(query *pb.RelationalQuery, isRole bool) (string, error) {
 	}
 
 	privileges := TranslatePermissions(query.Permissions)
+	object, err := buildGrantObject(query)
+	if err != nil {
+		return "", err
+	}
 	if isRole {
-		return fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(privileges, ", "), query.Table, query.PermissionTarget), nil
+		return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privileges, ", "), object, QuoteIdentifier(query.PermissionTarget)), nil
+	}
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privileges, ", "), object, quoteAccount(query.PermissionTarget, query.UserHost)), nil
+}
+
+// buildGrantObject renders the ON target
+// MySQL has no schema/database distinction and no sequences.
+func buildGrantObject(query *pb.RelationalQuery) (string, error) {
+	switch query.PermissionObject {
+	case "":
+		return QuoteIdentifier(query.Table) + ".*", nil
+	case "DATABASE", "SCHEMA", "ALL TABLES IN SCHEMA":
+		return QuoteIdentifier(query.Table) + ".*", nil
+	case "FUNCTION":
+		return "FUNCTION " + QuoteIdentifier(query.Table), nil
+	default:
+		return "", fmt.Errorf("GRANT ON %s is not supported by MySQL", query.PermissionObject)
 	}
-	return fmt.Sprintf("REVOKE %s ON %s.* FROM '%s'@'%s'", strings.Join(privileges, ", "), query.Table, query.PermissionTarget, DefaultMySQLUserHost), nil
 }
 
 func BuildCreateRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.RoleName == "" {
 		return "", fmt.Errorf("no role name specified for CREATE_ROLE")
 	}
-	return fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s", query.RoleName), nil
+	// MySQL roles are locked accounts without attributes
+	if len(query.RoleFlags) > 0 || query.ValidUntil != "" || query.ConnectionLimit != "" || query.Password != "" {
+		return "", fmt.Errorf("MySQL CREATE ROLE takes no options; use ALTER ROLE")
+	}
+	return fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s", QuoteIdentifier(query.RoleName)), nil
+}
+
+// BuildAlterRoleSQL sets account options on a role (roles are accounts in MySQL)
+func BuildAlterRoleSQL(query *pb.RelationalQuery) (string, error) {
+	if query.RoleName == "" {
+		return "", fmt.Errorf("no role name specified for ALTER_ROLE")
+	}
+	options, err := buildAccountOptions(query)
+	if err != nil {
+		return "", err
+	}
+	if query.Password != "" {
+		options = " IDENTIFIED BY " + QuoteString(query.Password) + options
+	}
+	if options == "" {
+		return "", fmt.Errorf("no options specified for ALTER_ROLE")
+	}
+	return fmt.Sprintf("ALTER USER %s", QuoteIdentifier(query.RoleName)) + options, nil
 }
 
 func BuildDropRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.RoleName == "" {
 		return "", fmt.Errorf("no role name specified for DROP_ROLE")
 	}
-	return fmt.Sprintf("DROP ROLE IF EXISTS %s", query.RoleName), nil
+	return fmt.Sprintf("DROP ROLE IF EXISTS %s", QuoteIdentifier(query.RoleName)), nil
 }
 
 func BuildAssignRoleSQL(query *pb.RelationalQuery) (string, error) {
@@ -607,7 +965,7 @@ func BuildAssignRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no user name specified for ASSIGN_ROLE")
 	}
-	return fmt.Sprintf("GRANT '%s' TO '%s'@'%s'", query.RoleName, query.UserName, DefaultMySQLUserHost), nil
+	return fmt.Sprintf("GRANT %s TO %s", QuoteIdentifier(query.RoleName), quoteAccount(query.UserName, query.UserHost)), nil
 }
 
 func BuildRevokeRoleSQL(query *pb.RelationalQuery) (string, error) {
@@ -617,7 +975,7 @@ func BuildRevokeRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no user name specified for REVOKE_ROLE")
 	}
-	return fmt.Sprintf("REVOKE '%s' FROM '%s'@'%s'", query.RoleName, query.UserName, DefaultMySQLUserHost), nil
+	return fmt.Sprintf("REVOKE %s FROM %s", QuoteIdentifier(query.RoleName), quoteAccount(query.UserName, query.UserHost)), nil
 }
 
 func BuildCreateUserSQL(query *pb.RelationalQuery) (string, error) {
@@ -627,28 +985,75 @@ func BuildCreateUserSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Password == "" {
 		return "", fmt.Errorf("no password specified for CREATE_USER")
 	}
-	return fmt.Sprintf("CREATE USER IF NOT EXISTS '%s'@'%s' IDENTIFIED BY '%s'", query.UserName, DefaultMySQLUserHost, query.Password), nil
+	options, err := buildAccountOptions(query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("CREATE USER IF NOT EXISTS %s IDENTIFIED BY %s", quoteAccount(query.UserName, query.UserHost), QuoteString(query.Password)) + options, nil
 }
 
 func BuildDropUserSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for DROP_USER")
 	}
-	return fmt.Sprintf("DROP USER IF EXISTS '%s'@'%s'", query.UserName, DefaultMySQLUserHost), nil
+	return fmt.Sprintf("DROP USER IF EXISTS %s", quoteAccount(query.UserName, query.UserHost)), nil
 }
 
 func BuildAlterUserSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for ALTER_USER")
 	}
-	if query.Password == "" {
-		return "", fmt.Errorf("no password specified for ALTER_USER")
+	options, err := buildAccountOptions(query)
+	if err != nil {
+		return "", err
 	}
-	return fmt.Sprintf("ALTER USER '%s'@'%s' IDENTIFIED BY '%s'", query.UserName, DefaultMySQLUserHost, query.Password), nil
+	if query.Password != "" {
+		options = " IDENTIFIED BY " + QuoteString(query.Password) + options
+	}
+	if options == "" {
+		return "", fmt.Errorf("no password or options specified for ALTER_USER")
+	}
+	return fmt.Sprintf("ALTER USER %s", quoteAccount(query.UserName, query.UserHost)) + options, nil
+}
+
+// buildAccountOptions maps role attributes to MySQL account options
+// LOGIN/NOLOGIN -> ACCOUNT UNLOCK/LOCK, CONNECTION LIMIT -> MAX_USER_CONNECTIONS.
+// SUPERUSER, CREATEDB etc. are privileges in MySQL (use GRANT).
+func buildAccountOptions(query *pb.RelationalQuery) (string, error) {
+	if query.ValidUntil != "" {
+		return "", fmt.Errorf("VALID UNTIL is not supported by MySQL (use PASSWORD EXPIRE)")
+	}
+
+	var options string
+	if query.ConnectionLimit != "" {
+		limit, err := strconv.Atoi(query.ConnectionLimit)
+		if err != nil {
+			return "", fmt.Errorf("invalid CONNECTION LIMIT %q", query.ConnectionLimit)
+		}
+		if limit == 0 {
+			return "", fmt.Errorf("CONNECTION LIMIT 0 has no MySQL equivalent; use NOLOGIN")
+		}
+		if limit < 0 {
+			limit = 0 // unlimited
+		}
+		options += fmt.Sprintf(" WITH MAX_USER_CONNECTIONS %d", limit)
+	}
+
+	for _, flag := range query.RoleFlags {
+		switch strings.ToUpper(flag) {
+		case "LOGIN":
+			options += " ACCOUNT UNLOCK"
+		case "NOLOGIN":
+			options += " ACCOUNT LOCK"
+		default:
+			return "", fmt.Errorf("role option %s is not supported by MySQL (use GRANT)", flag)
+		}
+	}
+	return options, nil
 }
 
 func BuildGrantRoleToUserSQL(roleName, userName string) string {
-	return fmt.Sprintf("GRANT '%s' TO '%s'@'%s'", roleName, userName, DefaultMySQLUserHost)
+	return fmt.Sprintf("GRANT %s TO %s", QuoteIdentifier(roleName), quoteAccount(userName, ""))
 }
 
 func TranslatePermissions(permissions []string) []string {
@@ -677,17 +1082,67 @@ func TranslatePermissions(permissions []string) []string {
 // ============================================================================
 
 func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	// CREATE TABLE entity AS GET ...
+	if query.ViewQuery != nil {
+		return fmt.Sprintf("CREATE TABLE %s AS %s", QuoteIdentifier(query.Table), buildViewQuerySQL(query.ViewQuery)), nil
+	}
 	if len(query.Fields) == 0 {
 		return "", fmt.Errorf("no columns specified for CREATE TABLE")
 	}
 
 	var columns []string
 	for _, field := range query.Fields {
-		columnDef := TranslateColumn(field.NameExpr.Value, field.ValueExpr.Value, field.Constraints, typeMap)
+		columnDef := TranslateColumn(field.NameExpr.Value, field.ValueExpr.Value, field.Constraints, field.GeneratedExpr, typeMap)
 		columns = append(columns, columnDef)
 	}
 
-	return fmt.Sprintf("CREATE TABLE `%s` (%s)", query.Table, strings.Join(columns, ", ")), nil
+	options, err := buildTableOptions(query.TableOptions)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)%s", QuoteIdentifier(query.Table), strings.Join(columns, ", "), options), nil
+}
+
+// tableOptionOrder is the order SHOW CREATE TABLE prints table options in
+var tableOptionOrder = []string{"ENGINE", "AUTO_INCREMENT", "CHARSET", "COLLATE"}
+
+// buildTableOptions renders ENGINE=InnoDB AUTO_INCREMENT=1000 DEFAULT CHARSET=utf8mb4 COLLATE=...
+// Values are bare words in MySQL, so anything but letters, digits and _ is rejected
+func buildTableOptions(options map[string]string) (string, error) {
+	var parts []string
+	for _, name := range tableOptionOrder {
+		value, ok := options[name]
+		if !ok {
+			continue
+		}
+		if !isTableOptionValue(value, name == "AUTO_INCREMENT") {
+			return "", fmt.Errorf("invalid %s value '%s'", name, value)
+		}
+		if name == "CHARSET" {
+			name = "DEFAULT CHARSET"
+		}
+		parts = append(parts, name+"="+value)
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return " " + strings.Join(parts, " "), nil
+}
+
+func isTableOptionValue(value string, numeric bool) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+		case !numeric && (r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')):
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
@@ -708,28 +1163,28 @@ func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string
 		if columnValue == "" {
 			return "", fmt.Errorf("ADD_COLUMN requires column type")
 		}
-		columnDef := TranslateColumn(columnName, columnValue, field.Constraints, typeMap)
-		return fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s", query.Table, columnDef), nil
+		columnDef := TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", QuoteIdentifier(query.Table), columnDef), nil
 	case "DROP_COLUMN":
-		return fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN %s", query.Table, columnName), nil
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", QuoteIdentifier(query.Table), QuoteIdentifier(columnName)), nil
 	case "RENAME_COLUMN":
 		if columnValue == "" {
 			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
 		}
-		return fmt.Sprintf("ALTER TABLE `%s` RENAME COLUMN %s TO %s", query.Table, columnName, columnValue), nil
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
 	case "MODIFY_COLUMN":
 		if columnValue == "" {
 			return "", fmt.Errorf("MODIFY_COLUMN requires new column type")
 		}
-		columnDef := TranslateColumn(columnName, columnValue, field.Constraints, typeMap)
-		return fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN %s", query.Table, columnDef), nil
+		columnDef := TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", QuoteIdentifier(query.Table), columnDef), nil
 	default:
 		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
 	}
 }
 
 func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
-	return fmt.Sprintf("DROP TABLE IF EXISTS `%s`", query.Table), nil
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", QuoteIdentifier(query.Table)), nil
 }
 
 func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
@@ -740,29 +1195,31 @@ func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
 	indexName := query.Fields[0].NameExpr.Value
 	columnName := query.Fields[0].ValueExpr.Value
 
-	// Check for UNIQUE constraint
+	// UNIQUE, or FULLTEXT for MATCH ... AGAINST (SEARCH)
 	indexType := "INDEX"
-	if len(query.Fields[0].Constraints) > 0 {
-		for _, constraint := range query.Fields[0].Constraints {
-			if strings.ToUpper(constraint) == "UNIQUE" {
-				indexType = "UNIQUE INDEX"
-				break
-			}
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			indexType = "UNIQUE INDEX"
+		case "FULLTEXT":
+			indexType = "FULLTEXT INDEX"
+		case "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by MySQL (MongoDB only)", strings.ToUpper(constraint))
 		}
 	}
 
-	return fmt.Sprintf("CREATE %s %s ON `%s` (%s)", indexType, indexName, query.Table, columnName), nil
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, QuoteIdentifier(indexName), QuoteIdentifier(query.Table), quoteIdentifierList(columnName)), nil
 }
 
 func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
 	if len(query.Fields) == 0 {
 		return "", fmt.Errorf("no index name specified")
 	}
-	return fmt.Sprintf("DROP INDEX %s ON `%s`", query.Fields[0].NameExpr.Value, query.Table), nil
+	return fmt.Sprintf("DROP INDEX %s ON %s", QuoteIdentifier(getFieldName(query.Fields[0])), QuoteIdentifier(query.Table)), nil
 }
 
 func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
-	return fmt.Sprintf("TRUNCATE TABLE `%s`", query.Table), nil
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
 }
 
 // formatLiteral converts a value to SQL literal format for VIEW definitions
@@ -784,6 +1241,71 @@ func formatLiteral(v interface{}) string {
 	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
 }
 
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	var b strings.Builder
+	inString := false
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(sql):
+			b.WriteByte(c)
+			i++
+			c = sql[i]
+		case c == '\'':
+			if inString && i+1 < len(sql) && sql[i+1] == '\'' {
+				b.WriteString("''")
+				i++
+				continue
+			}
+			inString = !inString
+		case !inString && c == '?' && next < len(args):
+			b.WriteString(formatLiteral(args[next]))
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// QuoteString returns s as a single-quoted MySQL string literal
+// Escapes backslashes too, since MySQL treats them as escape characters by default
+func QuoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'':
+			b.WriteString("\\'")
+		case '\\':
+			b.WriteString("\\\\")
+		case 0:
+			b.WriteString("\\0")
+		case '\n':
+			b.WriteString("\\n")
+		case '\r':
+			b.WriteString("\\r")
+		case 0x1a:
+			b.WriteString("\\Z")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) string {
+	viewSQL, args := BuildSelectSQL(query)
+	return inlinePlaceholders(viewSQL, args)
+}
+
 func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewName == "" {
 		return "", fmt.Errorf("no view name specified for CREATE VIEW")
@@ -791,12 +1313,7 @@ func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewQuery == nil {
 		return "", fmt.Errorf("no query specified for CREATE VIEW")
 	}
-	viewSQL, args := BuildSelectSQL(query.ViewQuery)
-	// Substitute ? placeholders with literal values
-	for _, arg := range args {
-		viewSQL = strings.Replace(viewSQL, "?", formatLiteral(arg), 1)
-	}
-	return fmt.Sprintf("CREATE VIEW %s AS %s", query.ViewName, viewSQL), nil
+	return fmt.Sprintf("CREATE VIEW %s AS %s", QuoteIdentifier(query.ViewName), buildViewQuerySQL(query.ViewQuery)), nil
 }
 
 func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
@@ -806,43 +1323,64 @@ func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewQuery == nil {
 		return "", fmt.Errorf("no query specified for ALTER VIEW")
 	}
-	viewSQL, args := BuildSelectSQL(query.ViewQuery)
-	// Substitute ? placeholders with literal values
-	for _, arg := range args {
-		viewSQL = strings.Replace(viewSQL, "?", formatLiteral(arg), 1)
-	}
-	return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", query.ViewName, viewSQL), nil
+	return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", QuoteIdentifier(query.ViewName), buildViewQuerySQL(query.ViewQuery)), nil
 }
 
 func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewName == "" {
 		return "", fmt.Errorf("no view name specified for DROP VIEW")
 	}
-	return fmt.Sprintf("DROP VIEW IF EXISTS %s", query.ViewName), nil
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s", QuoteIdentifier(query.ViewName)), nil
 }
 
 func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
 	if query.NewName == "" {
 		return "", fmt.Errorf("no new name specified for RENAME TABLE")
 	}
-	return fmt.Sprintf("RENAME TABLE `%s` TO `%s`", query.Table, query.NewName), nil
+	return fmt.Sprintf("RENAME TABLE %s TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(query.NewName)), nil
+}
+
+// BuildShowTablesSQL lists the tables of the current database (SHOW TABLES)
+func BuildShowTablesSQL() string {
+	return "SHOW TABLES"
+}
+
+// BuildShowColumnsSQL lists the columns of a table (SHOW COLUMNS)
+func BuildShowColumnsSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Table == "" {
+		return "", fmt.Errorf("no table name specified for SHOW COLUMNS")
+	}
+	return fmt.Sprintf("SHOW COLUMNS FROM %s", QuoteIdentifier(query.Table)), nil
+}
+
+// BuildShowIndexSQL lists the indexes of a table, one row per indexed column (SHOW INDEXES)
+func BuildShowIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Table == "" {
+		return "", fmt.Errorf("no table name specified for SHOW INDEXES")
+	}
+	return fmt.Sprintf("SHOW INDEX FROM %s", QuoteIdentifier(query.Table)), nil
 }
 
 func BuildCreateDatabaseSQL(query *pb.RelationalQuery) (string, error) {
 	if query.DatabaseName == "" {
 		return "", fmt.Errorf("no database name specified for CREATE DATABASE")
 	}
-	return fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", query.DatabaseName), nil
+	return fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", QuoteIdentifier(query.DatabaseName)), nil
 }
 
 func BuildDropDatabaseSQL(query *pb.RelationalQuery) (string, error) {
 	if query.DatabaseName == "" {
 		return "", fmt.Errorf("no database name specified for DROP DATABASE")
 	}
-	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", query.DatabaseName), nil
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", QuoteIdentifier(query.DatabaseName)), nil
 }
 
-func TranslateColumn(columnName, columnType string, constraints []string, typeMap map[string]map[string]string) string {
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) string {
+	// MySQL has no arrays - TYPE[] becomes JSON
+	if mapping.IsArrayType(columnType) {
+		columnType = mapping.ArrayTypeMap["MySQL"]
+	}
+
 	baseType := columnType
 	params := ""
 
@@ -862,9 +1400,9 @@ func TranslateColumn(columnName, columnType string, constraints []string, typeMa
 	var columnDef string
 	// Don't append params if mysqlType already has size
 	if strings.Contains(mysqlType, "(") {
-		columnDef = fmt.Sprintf("%s %s", columnName, mysqlType)
+		columnDef = fmt.Sprintf("%s %s", QuoteIdentifier(columnName), mysqlType)
 	} else {
-		columnDef = fmt.Sprintf("%s %s%s", columnName, mysqlType, params)
+		columnDef = fmt.Sprintf("%s %s%s", QuoteIdentifier(columnName), mysqlType, params)
 	}
 
 	// Handle AUTO_INCREMENT PRIMARY KEY
@@ -872,6 +1410,11 @@ func TranslateColumn(columnName, columnType string, constraints []string, typeMa
 		columnDef += " PRIMARY KEY"
 	}
 
+	// Computed column - must come before NOT NULL/UNIQUE in MySQL
+	if generated != nil {
+		columnDef += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", BuildExpressionSQL(generated))
+	}
+
 	// Handle constraints from AST
 	for _, constraint := range constraints {
 		switch strings.ToUpper(constraint) {
@@ -896,29 +1439,27 @@ func TranslateColumn(columnName, columnType string, constraints []string, typeMa
 func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	selectClause := "*"
 	if len(query.Columns) > 0 {
-		var colStrs []string
-		for _, col := range query.Columns {
-			colStrs = append(colStrs, col.Value)
-		}
-		selectClause = strings.Join(colStrs, ", ")
+		selectClause = buildExpressionList(query.Columns)
 	}
 	
-	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, query.Table)
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
 	var args []interface{}
 	
 	for _, join := range query.Joins {
 		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
 		if joinType == "CROSS" {
-			sql += fmt.Sprintf(" CROSS JOIN %s", join.Table)
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
 		} else if joinType == "FULL" {
 			// MySQL doesn't support FULL JOIN - emulate with LEFT JOIN UNION RIGHT JOIN
 			leftJoin := fmt.Sprintf("SELECT * FROM %s LEFT JOIN %s ON %s.%s = %s.%s",
-				query.Table, join.Table, query.Table, join.LeftExpr.Value, join.Table, join.RightExpr.Value)
+				table, joinTable, table, left, joinTable, right)
 			rightJoin := fmt.Sprintf("SELECT * FROM %s RIGHT JOIN %s ON %s.%s = %s.%s WHERE %s.%s IS NULL",
-				query.Table, join.Table, query.Table, join.LeftExpr.Value, join.Table, join.RightExpr.Value, query.Table, join.LeftExpr.Value)
+				table, joinTable, table, left, joinTable, right, table, left)
 			sql = fmt.Sprintf("(%s) UNION (%s)", leftJoin, rightJoin)
 		} else {
-			sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, join.Table, query.Table, join.LeftExpr.Value, join.Table, join.RightExpr.Value)
+			sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
 		}
 	}
 	
@@ -930,11 +1471,7 @@ func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	
 	if len(query.OrderBy) > 0 {
 		sql += " ORDER BY "
-		orderParts := []string{}
-		for _, ob := range query.OrderBy {
-			orderParts = append(orderParts, fmt.Sprintf("%s %s", ob.FieldExpr.Value, ob.Direction))
-		}
-		sql += strings.Join(orderParts, ", ")
+		sql += buildOrderByList(query.OrderBy)
 	}
 	
 	if query.Limit > 0 {
@@ -947,13 +1484,27 @@ func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	return sql, args
 }
 
+// buildGroupConcatSQL builds: GROUP_CONCAT([DISTINCT] field ORDER BY ... SEPARATOR 'sep')
+// SEPARATOR only takes a string literal, so it is inlined rather than bound.
+func buildGroupConcatSQL(agg *pb.AggregateClause, distinct bool) string {
+	sql := "GROUP_CONCAT("
+	if distinct {
+		sql += "DISTINCT "
+	}
+	sql += quoteColumnRef(getAggField(agg))
+	if len(agg.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(agg.OrderBy)
+	}
+	return sql + " SEPARATOR " + QuoteString(agg.Separator) + ")"
+}
+
 func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	var selectClause string
 	var args []interface{}
 	
 	if query.Aggregate != nil {
 		aggFunc := strings.ToUpper(query.Aggregate.Function)
-		aggField := getAggField(query.Aggregate)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
 		
 		if aggField == "" || aggField == "*" {
 			if query.Distinct {
@@ -962,24 +1513,18 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 				selectClause = fmt.Sprintf("SELECT %s(*)", aggFunc)
 			}
 			if len(query.GroupBy) > 0 {
-				var groupByStrs []string
-				for _, gb := range query.GroupBy {
-					groupByStrs = append(groupByStrs, gb.Value)
-				}
-				selectClause += ", " + strings.Join(groupByStrs, ", ")
+				selectClause += ", " + buildGroupByColumns(query.GroupBy)
 			}
 		} else {
-			if query.Distinct {
+			if aggFunc == "STRING AGG" {
+				selectClause = "SELECT " + buildGroupConcatSQL(query.Aggregate, query.Distinct)
+			} else if query.Distinct {
 				selectClause = fmt.Sprintf("SELECT %s(DISTINCT %s)", aggFunc, aggField)
 			} else {
 				selectClause = fmt.Sprintf("SELECT %s(%s)", aggFunc, aggField)
 			}
 			if len(query.GroupBy) > 0 {
-				var groupByStrs []string
-				for _, gb := range query.GroupBy {
-					groupByStrs = append(groupByStrs, gb.Value)
-				}
-				selectClause += ", " + strings.Join(groupByStrs, ", ")
+				selectClause += ", " + buildGroupByColumns(query.GroupBy)
 			}
 		}
 	} else {
@@ -990,19 +1535,14 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	var sql string
 	
 	if needsSubquery {
-		innerSQL := fmt.Sprintf("SELECT * FROM `%s`", query.Table)
+		innerSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
 		if len(query.Conditions) > 0 {
 			whereClause, whereArgs := BuildWhereClause(query.Conditions)
 			innerSQL += whereClause
 			args = append(args, whereArgs...)
 		}
 		if len(query.OrderBy) > 0 {
-			innerSQL += " ORDER BY "
-			orderParts := []string{}
-			for _, ob := range query.OrderBy {
-				orderParts = append(orderParts, fmt.Sprintf("%s %s", ob.FieldExpr.Value, ob.Direction))
-			}
-			innerSQL += strings.Join(orderParts, ", ")
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
 		}
 		if query.Offset > 0 && query.Limit == 0 {
 			innerSQL += fmt.Sprintf(" LIMIT 18446744073709551615 OFFSET %d", query.Offset)
@@ -1013,18 +1553,14 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		}
 		sql = fmt.Sprintf("%s FROM (%s) AS subquery", selectClause, innerSQL)
 	} else {
-		sql = fmt.Sprintf("%s FROM `%s`", selectClause, query.Table)
+		sql = fmt.Sprintf("%s FROM %s", selectClause, QuoteIdentifier(query.Table))
 		if len(query.Conditions) > 0 {
 			whereClause, whereArgs := BuildWhereClause(query.Conditions)
 			sql += whereClause
 			args = append(args, whereArgs...)
 		}
 		if len(query.GroupBy) > 0 {
-			var groupByStrs []string
-			for _, gb := range query.GroupBy {
-				groupByStrs = append(groupByStrs, gb.Value)
-			}
-			sql += " GROUP BY " + strings.Join(groupByStrs, ", ")
+			sql += " GROUP BY " + buildExpressionList(query.GroupBy)
 		}
 		if len(query.Having) > 0 {
 			havingClause, havingArgs := BuildHavingClause(query.Having)
@@ -1032,12 +1568,7 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 			args = append(args, havingArgs...)
 		}
 		if len(query.OrderBy) > 0 {
-			sql += " ORDER BY "
-			orderParts := []string{}
-			for _, ob := range query.OrderBy {
-				orderParts = append(orderParts, fmt.Sprintf("%s %s", ob.FieldExpr.Value, ob.Direction))
-			}
-			sql += strings.Join(orderParts, ", ")
+			sql += " ORDER BY " + buildOrderByList(query.OrderBy)
 		}
 		if query.Offset > 0 && query.Limit == 0 {
 			sql += fmt.Sprintf(" LIMIT 18446744073709551615 OFFSET %d", query.Offset)
@@ -1067,24 +1598,16 @@ func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
 			funcSQL = "RANK()"
 		case "DENSE_RANK":
 			funcSQL = "DENSE_RANK()"
-		case "LAG":
-			if wf.Alias != "" {
-				funcSQL = fmt.Sprintf("LAG(%s)", wf.Alias)
-			} else {
-				funcSQL = "LAG(*)"
-			}
-		case "LEAD":
-			if wf.Alias != "" {
-				funcSQL = fmt.Sprintf("LEAD(%s)", wf.Alias)
-			} else {
-				funcSQL = "LEAD(*)"
-			}
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
 		case "NTILE":
 			buckets := wf.Buckets
 			if buckets <= 0 {
 				buckets = 4
 			}
 			funcSQL = fmt.Sprintf("NTILE(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
 		default:
 			funcSQL = fmt.Sprintf("%s()", windowFunc)
 		}
@@ -1093,28 +1616,28 @@ func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		var overParts []string
 
 		if len(wf.PartitionBy) > 0 {
-			var partitionStrs []string
-			for _, pb := range wf.PartitionBy {
-				partitionStrs = append(partitionStrs, pb.Value)
-			}
-			overParts = append(overParts, fmt.Sprintf("PARTITION BY %s", strings.Join(partitionStrs, ", ")))
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
 		}
 
 		if len(wf.OrderBy) > 0 {
-			orderParts := []string{}
-			for _, ob := range wf.OrderBy {
-				orderParts = append(orderParts, fmt.Sprintf("%s %s", ob.FieldExpr.Value, ob.Direction))
-			}
-			overParts = append(overParts, fmt.Sprintf("ORDER BY %s", strings.Join(orderParts, ", ")))
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		}
+
+		if frame := buildWindowFrameSQL(wf); frame != "" {
+			overParts = append(overParts, frame)
 		}
 
 		overClause += strings.Join(overParts, " ")
 		overClause += ")"
 
+		if wf.Alias != "" {
+			overClause += " AS " + QuoteIdentifier(wf.Alias)
+		}
+
 		selectParts = append(selectParts, fmt.Sprintf("%s %s", funcSQL, overClause))
 	}
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), query.Table)
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), QuoteIdentifier(query.Table))
 	var args []interface{}
 
 	if len(query.Conditions) > 0 {
@@ -1126,16 +1649,76 @@ func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	return sql, args
 }
 
-func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}) {
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, windowDefaultSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM(`amount`), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// buildWindowFrameSQL renders the window frame: ROWS BETWEEN 2 PRECEDING AND CURRENT ROW
+func buildWindowFrameSQL(wf *pb.WindowClause) string {
+	if wf.FrameUnit == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd)
+}
+
+// windowDefaultSQL renders the LAG / LEAD default as a literal
+func windowDefaultSQL(expr *pb.Expression) string {
+	if expr.Type == "STRING" {
+		return QuoteString(expr.Value)
+	}
+	return formatLiteral(expr.Value)
+}
+
+// BuildSetOperationSQL joins two queries with UNION / INTERSECT / EXCEPT
+// Each query is parenthesized and keeps its own ORDER BY / LIMIT; the outer
+// ORDER BY / LIMIT / OFFSET apply to the combined result. INTERSECT and
+// EXCEPT are rewritten unless opts.NativeSetOperations is set.
+func BuildSetOperationSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
 	setOp := query.SetOperation
-	leftSQL, leftArgs := BuildSimpleSelectSQL(setOp.LeftQuery)
-	rightSQL, rightArgs := BuildSimpleSelectSQL(setOp.RightQuery)
+	operationType := strings.ToUpper(setOp.OperationType)
+
+	if !opts.NativeSetOperations && (operationType == "INTERSECT" || operationType == "EXCEPT") {
+		sql, args, err := buildEmulatedSetOperationSQL(setOp.LeftQuery, setOp.RightQuery, operationType == "EXCEPT", opts)
+		if err != nil {
+			return "", nil, err
+		}
+		return sql + buildSetOperationTail(query), args, nil
+	}
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(setOp.LeftQuery, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(setOp.RightQuery, opts)
+	if err != nil {
+		return "", nil, err
+	}
 	
 	var operator string
-	switch strings.ToUpper(setOp.OperationType) {
+	switch operationType {
 	case "UNION":
 		operator = "UNION"
-	case "UNION_ALL":
+	case "UNION_ALL", "UNION ALL":
 		operator = "UNION ALL"
 	case "INTERSECT":
 		operator = "INTERSECT"
@@ -1145,13 +1728,176 @@ func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		operator = "UNION"
 	}
 	
-	sql := fmt.Sprintf("(%s) %s (%s)", leftSQL, operator, rightSQL)
+	sql := fmt.Sprintf("(%s) %s (%s)", leftSQL, operator, rightSQL) + buildSetOperationTail(query)
 	args := append(leftArgs, rightArgs...)
-	return sql, args
+	return sql, args, nil
+}
+
+// buildSetOperandSQL builds one query of a set operation, which may itself be
+// a set operation, a join or an aggregate
+func buildSetOperandSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if query.SetOperation != nil {
+		return BuildSetOperationSQL(query, opts)
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		sql, args := BuildAggregateSQL(query)
+		return sql, args, nil
+	}
+	sql, args := BuildSelectSQL(query)
+	return sql, args, nil
+}
+
+// buildSetOperationTail renders the ORDER BY / LIMIT / OFFSET of a combined result
+func buildSetOperationTail(query *pb.RelationalQuery) string {
+	sql := ""
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return sql + buildLimitSQL(query.Limit, query.Offset)
+}
+
+// buildEmulatedSetOperationSQL rewrites INTERSECT / EXCEPT for servers without them
+// Rows are compared with the NULL-safe <=> (set operations treat NULLs as equal),
+// so a correlated [NOT] EXISTS is used rather than [NOT] IN, which never
+// matches NULLs and returns nothing at all when the subquery yields one.
+// Queries whose result columns are unknown cannot be rewritten and fail.
+func buildEmulatedSetOperationSQL(left, right *pb.RelationalQuery, except bool, opts Options) (string, []interface{}, error) {
+	operator := "INTERSECT"
+	if except {
+		operator = "EXCEPT"
+	}
+	if left == nil || right == nil {
+		return "", nil, fmt.Errorf("%s needs two queries", operator)
+	}
+
+	// Same table, all columns: both sides are filters over one row set
+	if left.Table == right.Table && isFilterOnly(left) && isFilterOnly(right) {
+		sql := fmt.Sprintf("SELECT DISTINCT * FROM %s", QuoteIdentifier(left.Table))
+		var where []string
+		var args []interface{}
+		if len(left.Conditions) > 0 {
+			clause, clauseArgs, _ := buildConditionsRecursive(left.Conditions)
+			where = append(where, "("+clause+")")
+			args = append(args, clauseArgs...)
+		}
+		rightClause := "TRUE"
+		if len(right.Conditions) > 0 {
+			clause, clauseArgs, _ := buildConditionsRecursive(right.Conditions)
+			rightClause = "(" + clause + ")"
+			args = append(args, clauseArgs...)
+		}
+		if except {
+			// A row whose predicate is NULL is not in the right-hand set
+			where = append(where, fmt.Sprintf("NOT COALESCE(%s, FALSE)", rightClause))
+		} else if rightClause != "TRUE" {
+			where = append(where, rightClause)
+		}
+		if len(where) > 0 {
+			sql += " WHERE " + strings.Join(where, " AND ")
+		}
+		return sql, args, nil
+	}
+
+	// Explicit columns: match rows position by position
+	leftCols, leftKnown := setOperandColumns(left)
+	rightCols, rightKnown := setOperandColumns(right)
+	if !leftKnown || !rightKnown {
+		return "", nil, fmt.Errorf("MySQL before 8.0.31 has no %s: list the columns of both queries (GET User WITH email), or enable native set operations on 8.0.31+", operator)
+	}
+	if len(leftCols) != len(rightCols) {
+		return "", nil, fmt.Errorf("%s queries select %d and %d columns", operator, len(leftCols), len(rightCols))
+	}
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(left, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(right, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var matches []string
+	for i := range leftCols {
+		matches = append(matches, fmt.Sprintf("%s <=> %s",
+			QuoteIdentifier("l."+leftCols[i]), QuoteIdentifier("r."+rightCols[i])))
+	}
+
+	exists := "EXISTS"
+	if except {
+		exists = "NOT EXISTS"
+	}
+	sql := fmt.Sprintf("SELECT DISTINCT %s FROM (%s) AS `l` WHERE %s (SELECT 1 FROM (%s) AS `r` WHERE %s)",
+		QuoteIdentifier("l.*"), leftSQL, exists, rightSQL, strings.Join(matches, " AND "))
+	return sql, append(leftArgs, rightArgs...), nil
+}
+
+// isFilterOnly reports whether a set operation operand reads whole rows of
+// its table through a WHERE alone, so it can be merged into one filter
+func isFilterOnly(query *pb.RelationalQuery) bool {
+	return query.SetOperation == nil && query.Aggregate == nil && len(query.Joins) == 0 &&
+		len(query.SelectColumns) == 0 && builderutil.SelectsAll(query.Columns) && !query.Distinct &&
+		len(query.GroupBy) == 0 && len(query.WindowFunctions) == 0 &&
+		len(query.OrderBy) == 0 && query.Limit == 0 && query.Offset == 0
+}
+
+// setOperandColumns returns the result column names of a set operation operand
+// Plain fields are named by their column, other expressions by their alias;
+// "*" and unaliased computed columns report false.
+func setOperandColumns(query *pb.RelationalQuery) ([]string, bool) {
+	if query.SetOperation != nil {
+		return setOperandColumns(query.SetOperation.LeftQuery)
+	}
+	if len(query.SelectColumns) > 0 {
+		var names []string
+		for _, col := range query.SelectColumns {
+			if col == nil {
+				return nil, false
+			}
+			if col.Alias != "" {
+				names = append(names, col.Alias)
+				continue
+			}
+			name, ok := plainColumnName(col.ExpressionObj)
+			if !ok {
+				return nil, false
+			}
+			names = append(names, name)
+		}
+		return names, true
+	}
+	if builderutil.SelectsAll(query.Columns) {
+		return nil, false
+	}
+	var names []string
+	for _, col := range query.Columns {
+		name, ok := plainColumnName(col)
+		if !ok {
+			return nil, false
+		}
+		names = append(names, name)
+	}
+	return names, true
+}
+
+// plainColumnName names the result column of a plain field (users.email is email)
+func plainColumnName(expr *pb.Expression) (string, bool) {
+	if expr == nil || expr.Type != "FIELD" || expr.Value == "*" || strings.HasSuffix(expr.Value, ".*") {
+		return "", false
+	}
+	return expr.Value[strings.LastIndex(expr.Value, ".")+1:], true
 }
 
 func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
-	sql := fmt.Sprintf("SELECT * FROM %s", query.Table)
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, QuoteIdentifier(query.Table))
 	var args []interface{}
 	
 	if len(query.Conditions) > 0 {
@@ -1185,7 +1931,7 @@ func BuildSetTransactionOptionsSQL(query *pb.RelationalQuery) string {
 		options = append(options, "READ ONLY")
 	}
 	if len(options) > 0 {
-		parts = append(parts, strings.Join(options, " "))
+		parts = append(parts, strings.Join(options, ", "))
 	}
 	return strings.Join(parts, " ")
 }
@@ -1194,21 +1940,38 @@ func BuildSavepointSQL(savepointName string) (string, error) {
 	if savepointName == "" {
 		return "", fmt.Errorf("savepoint name is required")
 	}
-	return fmt.Sprintf("SAVEPOINT %s", savepointName), nil
+	return fmt.Sprintf("SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
 }
 
 func BuildRollbackToSavepointSQL(savepointName string) (string, error) {
 	if savepointName == "" {
 		return "", fmt.Errorf("savepoint name is required")
 	}
-	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", savepointName), nil
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
 }
 
 func BuildReleaseSavepointSQL(savepointName string) (string, error) {
 	if savepointName == "" {
 		return "", fmt.Errorf("savepoint name is required")
 	}
-	return fmt.Sprintf("RELEASE SAVEPOINT %s", savepointName), nil
+	return fmt.Sprintf("RELEASE SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
+}
+
+// BuildLockTablesSQL creates LOCK TABLES t1 READ, t2 WRITE
+// The locks last until UNLOCK TABLES or the session ends, and LOCK TABLES
+// implicitly commits any open transaction
+func BuildLockTablesSQL(locks []*pb.TableLock) (string, error) {
+	if len(locks) == 0 {
+		return "", fmt.Errorf("LOCK TABLES requires at least one table")
+	}
+	parts := make([]string, len(locks))
+	for i, lock := range locks {
+		if lock.Mode != "READ" && lock.Mode != "WRITE" {
+			return "", fmt.Errorf("invalid lock mode for %s: %s (expected READ or WRITE)", lock.Table, lock.Mode)
+		}
+		parts[i] = QuoteIdentifier(lock.Table) + " " + lock.Mode
+	}
+	return "LOCK TABLES " + strings.Join(parts, ", "), nil
 }
 
 func BuildSetTransactionSQL(isolationLevel string) string {
@@ -1235,12 +1998,68 @@ func TranslateIsolationLevel(level string) string {
 // CTE OPERATIONS - SQL BUILDERS
 // ============================================================================
 
-func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}) {
+func BuildCTESQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
 	if query.Cte == nil {
-		return "", nil
+		return "", nil, nil
+	}
+	cteSQL, params, err := buildCTEBodySQL(query.Cte.CteQuery, opts)
+	if err != nil {
+		return "", nil, err
 	}
-	cteSQL, params := BuildSelectSQL(query.Cte.CteQuery)
-	return fmt.Sprintf("WITH %s AS (%s) SELECT * FROM %s", query.Cte.CteName, cteSQL, query.Cte.CteName), params
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	with := "WITH"
+	if query.Cte.Recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", cteName)
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs = BuildSelectSQL(query.Cte.MainQuery)
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("%s %s AS (%s) %s", with, cteName, cteSQL, mainSQL), params, nil
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+// UNION members are left unparenthesized: a recursive CTE must read
+// "anchor UNION [ALL] recursive member".
+func buildCTEBodySQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if query == nil {
+		return "", nil, nil
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		operator := ""
+		switch strings.ToUpper(setOp.OperationType) {
+		case "UNION":
+			operator = "UNION"
+		case "UNION_ALL", "UNION ALL":
+			operator = "UNION ALL"
+		default:
+			return BuildSetOperationSQL(query, opts)
+		}
+		leftSQL, leftArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery), opts)
+		if err != nil {
+			return "", nil, err
+		}
+		rightSQL, rightArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery), opts)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s %s", leftSQL, operator, rightSQL), append(leftArgs, rightArgs...), nil
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		sql, args := BuildAggregateSQL(query)
+		return sql, args, nil
+	}
+	sql, args := BuildSelectSQL(query)
+	return sql, args, nil
 }
 
 // ============================================================================
@@ -1265,20 +2084,17 @@ func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}) {
 		return "", nil
 	}
 
-	sql := fmt.Sprintf("SELECT * FROM `%s` WHERE ", query.Table)
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
 	var args []interface{}
 
 	if len(query.Conditions) > 0 {
-		whereParts := []string{}
-		for _, cond := range query.Conditions {
-			whereParts = append(whereParts, fmt.Sprintf("%s %s ?", cond.FieldExpr.Value, cond.Operator))
-			args = append(args, cond.ValueExpr.Value)
-		}
-		sql += strings.Join(whereParts, " AND ") + " AND "
+		whereClause, whereArgs, _ := buildConditionsRecursive(query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
 	}
 
-	subField := query.Subquery.FieldExpr.Value
-    sql += fmt.Sprintf("%s IN (%s)", subField, subquerySQL)
+	subField := BuildExpressionSQL(query.Subquery.FieldExpr)
+	sql += fmt.Sprintf("%s IN (%s)", subField, subquerySQL)
 	args = append(args, subArgs...)
 
 	return sql, args
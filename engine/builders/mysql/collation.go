@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// COLLATION (utf8mb4, MySQL 8.0)
+// ============================================================================
+
+// COLLATE locale [STRENGTH n] is applied to string comparisons and ORDER BY
+// columns as an explicit COLLATE on the UCA 9.0.0 collations: strength 1 is
+// _ai_ci, 2 is _as_ci and 3 (the default) is _as_cs.
+
+// collationLanguages are the locales with language-specific 0900 collations
+// (ai_ci and as_cs); other locales use the root collation
+var collationLanguages = map[string]bool{
+	"cs": true, "da": true, "eo": true, "es": true, "et": true, "hr": true, "hu": true,
+	"is": true, "la": true, "lt": true, "lv": true, "pl": true, "ro": true, "ru": true,
+	"sk": true, "sl": true, "sv": true, "tr": true, "vi": true,
+}
+
+// CollationName returns the collation for a locale and MongoDB strength
+// en STRENGTH 1 = utf8mb4_0900_ai_ci; sv = utf8mb4_sv_0900_as_cs; simple = utf8mb4_bin
+func CollationName(locale string, strength int) string {
+	if strings.EqualFold(locale, "simple") {
+		return "utf8mb4_bin"
+	}
+	// fr_CA, fr-CA = fr
+	language, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "-", "_")), "_")
+
+	switch strength {
+	case 1:
+		if collationLanguages[language] {
+			return "utf8mb4_" + language + "_0900_ai_ci"
+		}
+		return "utf8mb4_0900_ai_ci"
+	case 2:
+		// No language-specific accent-sensitive, case-insensitive collations
+		return "utf8mb4_0900_as_ci"
+	default:
+		if collationLanguages[language] {
+			return "utf8mb4_" + language + "_0900_as_cs"
+		}
+		return "utf8mb4_0900_as_cs"
+	}
+}
+
+// buildCollateSQL renders a COLLATE expression: `name` COLLATE `utf8mb4_0900_ai_ci`
+func buildCollateSQL(expr *pb.Expression) string {
+	return BuildExpressionSQL(expr.Left) + " COLLATE " + quoteIdentifierPart(expr.Value)
+}
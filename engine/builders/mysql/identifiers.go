@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted
+var valueKeywords = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "CURRENT_USER": true,
+	"NULL": true, "TRUE": true, "FALSE": true, "DEFAULT": true,
+}
+
+// QuoteIdentifier returns name backtick-quoted with embedded backticks doubled
+// MySQL folds nothing inside backticks, so every name is quoted the same way.
+// Qualified names (db.table, table.column) are quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, "`") {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteIdentifierList quotes a comma-separated list of names (index columns)
+func quoteIdentifierList(names string) string {
+	parts := strings.Split(names, ",")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteColumnRef quotes a FIELD expression value, leaving SQL value keywords alone
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return name
+	}
+	return QuoteIdentifier(name)
+}
+
+// quoteAccount renders a user account as 'name'@'host' (empty host = DefaultMySQLUserHost)
+func quoteAccount(name, host string) string {
+	if host == "" {
+		host = DefaultMySQLUserHost
+	}
+	return QuoteString(name) + "@" + QuoteString(host)
+}
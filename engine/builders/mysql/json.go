@@ -0,0 +1,161 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (JSON_EXTRACT / JSON_SET / JSON_CONTAINS)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and a MySQL path
+// data->'address'->>'city' = data, $.address.city; data->0 = data, $[0]
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []*pb.Expression
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]*pb.Expression{expr.Right}, keys...)
+		expr = expr.Left
+	}
+	path := "$"
+	for _, key := range keys {
+		path += jsonPathStep(key)
+	}
+	return expr, path
+}
+
+// jsonPathStep renders one path leg: [n] for array indexes, .key or ."odd key" for members
+func jsonPathStep(key *pb.Expression) string {
+	if key == nil {
+		return ""
+	}
+	if key.Type == "NUMBER" {
+		return "[" + key.Value + "]"
+	}
+	if isJSONPathIdentifier(key.Value) {
+		return "." + key.Value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key.Value)
+	return `."` + escaped + `"`
+}
+
+// isJSONPathIdentifier reports whether key can appear unquoted in a MySQL JSON path
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			continue
+		}
+		if i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// buildJSONPathSQL renders -> as JSON_EXTRACT and ->> as JSON_UNQUOTE(JSON_EXTRACT(...))
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, path := jsonPathTarget(expr)
+	sql := fmt.Sprintf("JSON_EXTRACT(%s, %s)", BuildExpressionSQL(column), QuoteString(path))
+	if expr.Operator == "->>" {
+		return "JSON_UNQUOTE(" + sql + ")"
+	}
+	return sql
+}
+
+// buildJSONCondition renders the JSONB containment and key operators
+// @> and <@ become JSON_CONTAINS; ?, ?| and ?& become JSON_CONTAINS_PATH
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}, int) {
+	switch cond.Operator {
+	case "@>", "<@":
+		valueSQL, args := buildJSONDocumentSQL(cond.ValueExpr)
+		if cond.Operator == "<@" {
+			return fmt.Sprintf("JSON_CONTAINS(%s, %s)", valueSQL, field), args, len(args)
+		}
+		return fmt.Sprintf("JSON_CONTAINS(%s, %s)", field, valueSQL), args, len(args)
+	case "?":
+		return fmt.Sprintf("JSON_CONTAINS_PATH(%s, 'one', ?)", field),
+			[]interface{}{"$" + jsonPathStep(cond.ValueExpr)}, 1
+	default:
+		// ?| = any key, ?& = all keys
+		if len(cond.ValuesExpr) == 0 {
+			if cond.Operator == "?|" {
+				return "1 = 0", nil, 0
+			}
+			return "1 = 1", nil, 0
+		}
+		mode := "one"
+		if cond.Operator == "?&" {
+			mode = "all"
+		}
+		placeholders := make([]string, len(cond.ValuesExpr))
+		args := make([]interface{}, len(cond.ValuesExpr))
+		for i, key := range cond.ValuesExpr {
+			placeholders[i] = "?"
+			args[i] = "$" + jsonPathStep(key)
+		}
+		return fmt.Sprintf("JSON_CONTAINS_PATH(%s, '%s', %s)", field, mode, strings.Join(placeholders, ", ")), args, len(args)
+	}
+}
+
+// buildJSONDocumentSQL renders a JSON candidate: ARRAY('a', 'b') as JSON_ARRAY(?, ?),
+// computed expressions inline, anything else as a ? bound to the JSON text
+func buildJSONDocumentSQL(expr *pb.Expression) (string, []interface{}) {
+	if expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == "ARRAY" {
+		var parts []string
+		var args []interface{}
+		for _, arg := range expr.FunctionArgs {
+			argSQL, argArgs := buildJSONValueSQL(arg)
+			parts = append(parts, argSQL)
+			args = append(args, argArgs...)
+		}
+		return "JSON_ARRAY(" + strings.Join(parts, ", ") + ")", args
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{value}
+}
+
+// buildJSONValueSQL renders a value stored into a JSON document
+// Strings bind as JSON strings; numbers, booleans and NULL are cast so they keep their JSON type
+func buildJSONValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr == nil {
+		return "CAST('null' AS JSON)", nil
+	}
+	switch strings.ToUpper(expr.Value) {
+	case "NULL", "TRUE", "FALSE":
+		if expr.Type != "STRING" {
+			return fmt.Sprintf("CAST('%s' AS JSON)", strings.ToLower(expr.Value)), nil
+		}
+	}
+	if expr.Type == "FIELD" && valueKeywords[strings.ToUpper(expr.Value)] {
+		return expr.Value, nil
+	}
+	if expr.Type == "NUMBER" {
+		return "CAST(? AS JSON)", []interface{}{expr.Value}
+	}
+	return "?", []interface{}{expr.Value}
+}
+
+// buildJSONSetSQL renders a JSON path assignment as col = JSON_SET(col, '$.path', value)
+// Later assignments to the same column see the earlier ones (MySQL evaluates SET left to right)
+func buildJSONSetSQL(field *pb.QueryField) (string, []interface{}) {
+	column, path := jsonPathTarget(field.NameExpr)
+	columnSQL := BuildExpressionSQL(column)
+	valueSQL, args := buildJSONValueSQL(field.ValueExpr)
+	return fmt.Sprintf("%s = JSON_SET(%s, %s, %s)", columnSQL, columnSQL, QuoteString(path), valueSQL), args
+}
@@ -0,0 +1,31 @@
+package mysql
+
+// Options are the per-client settings of the MySQL builders. The zero value
+// suits MySQL 8.0 before 8.0.31.
+type Options struct {
+	// NativeSetOperations emits INTERSECT and EXCEPT as-is. Only MySQL 8.0.31+
+	// understands them; older servers get an equivalent rewrite instead.
+	NativeSetOperations bool
+
+	// UseUpsertRowAlias makes UPSERT reference the proposed row through a row
+	// alias (INSERT ... AS new ON DUPLICATE KEY UPDATE col = new.col) instead
+	// of the VALUES() function, which is deprecated since MySQL 8.0.20.
+	UseUpsertRowAlias bool
+
+	// BulkBatchRows is the most rows one BULK UPSERT statement carries, 1000
+	// when zero; a negative value puts every row in one statement. Larger
+	// inputs are split into several statements so each stays well inside
+	// max_allowed_packet and the 65,535 placeholder limit of prepared statements.
+	BulkBatchRows int
+}
+
+// defaultBulkBatchRows is the BulkBatchRows of the zero Options
+const defaultBulkBatchRows = 1000
+
+// bulkBatchRows returns BulkBatchRows, or its default when unset
+func (o Options) bulkBatchRows() int {
+	if o.BulkBatchRows == 0 {
+		return defaultBulkBatchRows
+	}
+	return o.BulkBatchRows
+}
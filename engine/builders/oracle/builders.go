@@ -0,0 +1,1279 @@
+package oracle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// Builders write ? placeholders while a statement is put together, so parts
+// can be built in any order; NumberBinds then turns them into Oracle's
+// positional bind variables :1, :2, ... (the translator applies it once per
+// statement).
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getJoinLeft(join *pb.JoinClause) string {
+	if join == nil || join.LeftExpr == nil {
+		return ""
+	}
+	return join.LeftExpr.Value
+}
+
+func getJoinRight(join *pb.JoinClause) string {
+	if join == nil || join.RightExpr == nil {
+		return ""
+	}
+	return join.RightExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// buildValueSQL renders a value position: computed expressions inline, value
+// keywords bare, excluded.col as the MERGE source column, and anything else
+// as a ? bind that NumberBinds later turns into :n
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" {
+		if valueKeywords[strings.ToUpper(expr.Value)] {
+			return strings.ToUpper(expr.Value), nil
+		}
+		if column, ok := excludedColumn(expr.Value); ok {
+			return mergeSource + "." + QuoteIdentifier(column), nil
+		}
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{ConvertOracleValue(value)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches and DDL take no binds)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return formatLiteral(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ============================================================================
+// CRUD OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates parameterized SELECT query with expression support
+// Oracle locks rows FOR UPDATE only, and not in a paged query (ORA-02014).
+func BuildSelectSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+
+	var args []interface{}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			var colSQL string
+			switch {
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "CASEWHEN":
+				caseSQL := "CASE"
+				for _, cond := range col.ExpressionObj.CaseConditions {
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", buildConditionSQL(cond.Condition), thenSQL)
+					args = append(args, thenArgs...)
+				}
+				if col.ExpressionObj.CaseElse != nil {
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
+				}
+				colSQL = caseSQL + " END"
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW":
+				colSQL = buildWindowExprSQL(col.ExpressionObj)
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "FIELD" && col.ExpressionObj.Value == "*":
+				// Oracle allows * next to other columns only qualified: users.*
+				colSQL = QuoteIdentifier(query.Table) + ".*"
+			default:
+				colSQL = BuildExpressionSQL(col.ExpressionObj)
+			}
+			if col.Alias != "" {
+				colSQL += " AS " + QuoteIdentifier(col.Alias)
+			}
+			colParts = append(colParts, colSQL)
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	sql = paginate(sql, query.Limit, query.Offset, opts)
+
+	if query.Lock != "" {
+		if strings.ToUpper(query.Lock) != "UPDATE" {
+			return "", nil, fmt.Errorf("Oracle locks rows FOR UPDATE only, not FOR %s", query.Lock)
+		}
+		if query.Limit > 0 || query.Offset > 0 {
+			return "", nil, fmt.Errorf("Oracle cannot lock the rows of a query with LIMIT or OFFSET")
+		}
+		sql += " FOR UPDATE"
+		if query.LockWait != "" {
+			sql += " " + query.LockWait
+		}
+	}
+
+	return sql, args, nil
+}
+
+// buildConditionSQL renders a condition with its values inline (CASE WHEN)
+func buildConditionSQL(cond *pb.QueryCondition) string {
+	if cond == nil {
+		return ""
+	}
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	case "ILIKE", "NOT_ILIKE":
+		return fmt.Sprintf("UPPER(%s) %s UPPER(%s)", field, oracleOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+	}
+	return fmt.Sprintf("%s %s %s", field, oracleOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+}
+
+func buildWindowExprSQL(expr *pb.Expression) string {
+	funcName := strings.ReplaceAll(expr.FunctionName, " ", "_")
+
+	var funcCall string
+	switch funcName {
+	case "LAG", "LEAD":
+		field := "id"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				field = QuoteIdentifier(arg.Value)
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, field)
+	case "NTILE":
+		buckets := "4"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				buckets = arg.Value
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, buckets)
+	default:
+		funcCall = fmt.Sprintf("%s()", funcName)
+	}
+
+	var partitionParts, orderParts []string
+	for _, arg := range expr.FunctionArgs {
+		if strings.HasPrefix(arg.Value, "PARTITION:") {
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
+		} else if strings.HasPrefix(arg.Value, "ORDER:") {
+			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
+			if len(parts) >= 2 {
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
+			} else if len(parts) == 1 {
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
+			}
+		}
+	}
+
+	var overParts []string
+	if len(partitionParts) > 0 {
+		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionParts, ", "))
+	}
+	if len(orderParts) > 0 {
+		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
+	}
+	return funcCall + " OVER (" + strings.Join(overParts, " ") + ")"
+}
+
+// BuildInsertSQL creates parameterized INSERT query
+func BuildInsertSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query, opts)
+	}
+
+	var fields, placeholders []string
+	var args []interface{}
+
+	for _, field := range query.Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	return sql, args, nil
+}
+
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	sourceSQL, err := buildViewQuerySQL(query.ViewQuery, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + sourceSQL, nil, nil
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildUpdateSQL creates parameterized UPDATE query with expression support
+func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var setParts []string
+	var args []interface{}
+
+	// JSON path assignments are grouped by column and rendered where the column first appears
+	jsonSets := map[string][]*pb.QueryField{}
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			jsonSets[column.Value] = append(jsonSets[column.Value], field)
+		}
+	}
+
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			group, pending := jsonSets[column.Value]
+			if !pending {
+				continue
+			}
+			delete(jsonSets, column.Value)
+			setSQL, setArgs := buildJSONSetSQL(group)
+			setParts = append(setParts, setSQL)
+			args = append(args, setArgs...)
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	return sql + whereClause, append(args, whereArgs...)
+}
+
+// BuildDeleteSQL creates parameterized DELETE query
+func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql := fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// BuildBulkInsertSQL creates BULK INSERT as one INSERT ALL statement:
+// INSERT ALL INTO t (cols) VALUES (...) INTO t (cols) VALUES (...) SELECT 1 FROM DUAL
+func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+
+	var fields []string
+	for _, field := range query.BulkData[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+	}
+	into := fmt.Sprintf("INTO %s (%s)", QuoteIdentifier(query.Table), strings.Join(fields, ", "))
+
+	parts := []string{"INSERT ALL"}
+	var args []interface{}
+	for _, row := range query.BulkData {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+		}
+		parts = append(parts, fmt.Sprintf("%s VALUES (%s)", into, strings.Join(placeholders, ", ")))
+	}
+	parts = append(parts, "SELECT 1 FROM DUAL")
+
+	return strings.Join(parts, " "), args, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+func BuildWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " WHERE " + clause, args
+}
+
+func buildConditionsRecursive(conditions []*pb.QueryCondition) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		var clause string
+		var clauseArgs []interface{}
+
+		if len(cond.Nested) > 0 {
+			nestedClause, nestedArgs := buildConditionsRecursive(cond.Nested)
+			clause = "(" + nestedClause + ")"
+			clauseArgs = nestedArgs
+		} else {
+			clause, clauseArgs = buildSingleCondition(cond)
+		}
+
+		if i == 0 {
+			parts = append(parts, clause)
+		} else {
+			logic := cond.Logic
+			if logic == "" {
+				logic = "AND"
+			}
+			parts = append(parts, logic+" "+clause)
+		}
+
+		args = append(args, clauseArgs...)
+	}
+
+	return strings.Join(parts, " "), args
+}
+
+func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	field := BuildExpressionSQL(cond.FieldExpr)
+
+	switch cond.Operator {
+	case "IS_NULL":
+		return fmt.Sprintf("%s IS NULL", field), nil
+	case "IS_NOT_NULL":
+		return fmt.Sprintf("%s IS NOT NULL", field), nil
+	case "IN":
+		return buildInClause(field, "IN", cond.ValuesExpr)
+	case "NOT_IN":
+		return buildInClause(field, "NOT IN", cond.ValuesExpr)
+	case "BETWEEN":
+		return buildBetweenClause(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "NOT_BETWEEN":
+		return buildBetweenClause(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "ILIKE", "NOT_ILIKE":
+		// Oracle has no ILIKE: compare both sides in upper case
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("UPPER(%s) %s UPPER(%s)", field, oracleOperator(cond.Operator), valueSQL), args
+	case "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
+	default:
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, oracleOperator(cond.Operator), valueSQL), args
+	}
+}
+
+// oracleOperator maps an OQL operator (NOT_LIKE, ILIKE) to its Oracle spelling
+func oracleOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["Oracle"][op]; ok {
+		return mapped
+	}
+	return op
+}
+
+// buildInClause renders IN / NOT IN; an empty list matches nothing (IN) or everything (NOT IN)
+func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}) {
+	if len(values) == 0 {
+		if operator == "IN" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args
+}
+
+func buildBetweenClause(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}) {
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args
+}
+
+// BuildExpressionSQL converts an Expression to SQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		// Oracle has no % operator
+		if expr.Operator == "%" {
+			return fmt.Sprintf("MOD(%s, %s)", left, right)
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		return mapping.FunctionSQL("Oracle", expr.FunctionName, args)
+	case "CASEWHEN":
+		caseParts := []string{"CASE"}
+		for _, cond := range expr.CaseConditions {
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", buildConditionSQL(cond.Condition), buildLiteralSQL(cond.ThenExpr)))
+		}
+		if expr.CaseElse != nil {
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
+		}
+		caseParts = append(caseParts, "END")
+		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		if column, ok := excludedColumn(expr.Value); ok {
+			return mergeSource + "." + QuoteIdentifier(column)
+		}
+		return quoteColumnRef(expr.Value)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
+	case "COLLATE":
+		return fmt.Sprintf("NLSSORT(%s, 'NLS_SORT=%s')", BuildExpressionSQL(expr.Left), expr.Value)
+	default:
+		return expr.Value
+	}
+}
+
+// ConvertOracleValue converts values for Oracle compatibility
+// Oracle before 23ai has no BOOLEAN column type; booleans are NUMBER(1) 1 and 0.
+func ConvertOracleValue(value string) interface{} {
+	switch strings.ToLower(value) {
+	case "true":
+		return 1
+	case "false":
+		return 0
+	default:
+		return value
+	}
+}
+
+// formatLiteral converts a value to SQL literal format for VIEW definitions
+func formatLiteral(v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return "1"
+	case "FALSE":
+		return "0"
+	}
+	return QuoteString(s)
+}
+
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	next := 0
+	return replacePlaceholders(sql, func() string {
+		if next >= len(args) {
+			return "?"
+		}
+		next++
+		return formatLiteral(args[next-1])
+	})
+}
+
+// NumberBinds rewrites the ? placeholders of a built statement as Oracle's
+// positional bind variables :1, :2, ... in the order the arguments were
+// collected. ? inside string literals is left alone.
+func NumberBinds(sql string) string {
+	n := 0
+	return replacePlaceholders(sql, func() string {
+		n++
+		return ":" + strconv.Itoa(n)
+	})
+}
+
+// replacePlaceholders replaces each ? outside string literals with next()
+func replacePlaceholders(sql string, next func() string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			if inString && i+1 < len(sql) && sql[i+1] == '\'' {
+				b.WriteString("''")
+				i++
+				continue
+			}
+			inString = !inString
+		case !inString && c == '?':
+			b.WriteString(next())
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes binds)
+func buildViewQuerySQL(query *pb.RelationalQuery, opts Options) (string, error) {
+	viewSQL, args, err := BuildSelectSQL(query, opts)
+	if err != nil {
+		return "", err
+	}
+	return inlinePlaceholders(viewSQL, args), nil
+}
+
+// ============================================================================
+// DDL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCreateTableSQL creates a table; CREATE TABLE name AS GET ... copies rows
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string, opts Options) (string, error) {
+	if query.ViewQuery != nil {
+		sourceSQL, err := buildViewQuerySQL(query.ViewQuery, opts)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("CREATE TABLE %s AS %s", QuoteIdentifier(query.Table), sourceSQL), nil
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	var columns []string
+	for _, field := range query.Fields {
+		columns = append(columns, TranslateColumn(getFieldName(field), getFieldValue(field), field.Constraints, field.GeneratedExpr, typeMap))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdentifier(query.Table), strings.Join(columns, ", ")), nil
+}
+
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+	table := QuoteIdentifier(query.Table)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD (%s)", table, TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
+	case "MODIFY_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("MODIFY_COLUMN requires column type")
+		}
+		return fmt.Sprintf("ALTER TABLE %s MODIFY (%s)", table, TranslateColumn(columnName, columnValue, field.Constraints, nil, typeMap)), nil
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+// BuildDropTableSQL drops a table with the foreign keys that reference it;
+// Oracle has no DROP TABLE IF EXISTS before 23ai
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	sql := "DROP TABLE " + QuoteIdentifier(query.Table)
+	if query.Cascade {
+		sql += " CASCADE CONSTRAINTS"
+	}
+	return sql, nil
+}
+
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
+}
+
+func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.NewName == "" {
+		return "", fmt.Errorf("no new name specified for RENAME TABLE")
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(query.NewName)), nil
+}
+
+func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index details specified")
+	}
+
+	indexName := getFieldName(query.Fields[0])
+	columnName := getFieldValue(query.Fields[0])
+
+	indexType := "INDEX"
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			indexType = "UNIQUE INDEX"
+		case "FULLTEXT", "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by Oracle", strings.ToUpper(constraint))
+		}
+	}
+
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, QuoteIdentifier(indexName), QuoteIdentifier(query.Table), quoteIdentifierList(columnName)), nil
+}
+
+// BuildDropIndexSQL drops an index; index names are unique per schema, so the table is not named
+func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index name specified")
+	}
+	return fmt.Sprintf("DROP INDEX %s", QuoteIdentifier(getFieldName(query.Fields[0]))), nil
+}
+
+func BuildCreateViewSQL(query *pb.RelationalQuery, opts Options) (string, error) {
+	return buildViewSQL("CREATE VIEW", query, opts)
+}
+
+// BuildAlterViewSQL replaces a view's query with CREATE OR REPLACE VIEW
+func BuildAlterViewSQL(query *pb.RelationalQuery, opts Options) (string, error) {
+	return buildViewSQL("CREATE OR REPLACE VIEW", query, opts)
+}
+
+func buildViewSQL(statement string, query *pb.RelationalQuery, opts Options) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no view query specified")
+	}
+	viewSQL, err := buildViewQuerySQL(query.ViewQuery, opts)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s AS %s", statement, QuoteIdentifier(query.ViewName), viewSQL), nil
+}
+
+func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	return fmt.Sprintf("DROP VIEW %s", QuoteIdentifier(query.ViewName)), nil
+}
+
+// TranslateColumn renders a column definition from the Oracle type map
+// A size replaces the mapped default: STRING(100) is VARCHAR2(100).
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) string {
+	// Oracle has no arrays - TYPE[] is stored as a JSON array
+	if mapping.IsArrayType(columnType) {
+		columnType = mapping.ArrayTypeMap["Oracle"]
+	}
+
+	baseType := columnType
+	params := ""
+	if idx := strings.Index(columnType, "("); idx != -1 {
+		baseType = columnType[:idx]
+		if endIdx := strings.Index(columnType, ")"); endIdx != -1 {
+			params = columnType[idx : endIdx+1]
+		}
+	}
+
+	oracleType, exists := typeMap["Oracle"][strings.ToUpper(baseType)]
+	if !exists {
+		oracleType = baseType
+	}
+	if params != "" && !strings.Contains(oracleType, " ") {
+		if idx := strings.Index(oracleType, "("); idx != -1 {
+			oracleType = oracleType[:idx]
+		}
+		oracleType += params
+	}
+
+	columnDef := fmt.Sprintf("%s %s", QuoteIdentifier(columnName), oracleType)
+
+	if generated != nil {
+		columnDef += fmt.Sprintf(" GENERATED ALWAYS AS (%s) VIRTUAL", BuildExpressionSQL(generated))
+	}
+
+	// AUTO already carries PRIMARY KEY
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			columnDef += " UNIQUE"
+		case "NOT_NULL", "NOTNULL":
+			columnDef += " NOT NULL"
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			if !strings.Contains(columnDef, "PRIMARY KEY") {
+				columnDef += " PRIMARY KEY"
+			}
+		}
+	}
+
+	return columnDef
+}
+
+// ============================================================================
+// DQL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildJoinSQL creates joins; Oracle has no AS before a table name
+func BuildJoinSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}) {
+	selectClause := "*"
+	if len(query.Columns) > 0 {
+		selectClause = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	for _, join := range query.Joins {
+		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		if joinType == "CROSS" {
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
+			continue
+		}
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
+		sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
+	}
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset, opts), args
+}
+
+// buildListAggSQL builds: LISTAGG([DISTINCT] field, 'sep') WITHIN GROUP (ORDER BY ...)
+// Without an ORDER BY the values are listed in the order of the field itself.
+// LISTAGG(DISTINCT ...) needs Oracle 19c.
+func buildListAggSQL(agg *pb.AggregateClause, distinct bool) string {
+	field := quoteColumnRef(getAggField(agg))
+	arg := field
+	if distinct {
+		arg = "DISTINCT " + field
+	}
+	order := field
+	if len(agg.OrderBy) > 0 {
+		order = buildOrderByList(agg.OrderBy)
+	}
+	return fmt.Sprintf("LISTAGG(%s, %s) WITHIN GROUP (ORDER BY %s)", arg, QuoteString(agg.Separator), order)
+}
+
+func BuildAggregateSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}) {
+	var selectClause string
+	var args []interface{}
+
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+
+		switch {
+		case aggField == "" || aggField == "*":
+			if query.Distinct {
+				selectClause = "SELECT COUNT(*)"
+			} else {
+				selectClause = fmt.Sprintf("SELECT %s(*)", aggFunc)
+			}
+		case aggFunc == "STRING AGG":
+			selectClause = "SELECT " + buildListAggSQL(query.Aggregate, query.Distinct)
+		case query.Distinct:
+			selectClause = fmt.Sprintf("SELECT %s(DISTINCT %s)", aggFunc, aggField)
+		default:
+			selectClause = fmt.Sprintf("SELECT %s(%s)", aggFunc, aggField)
+		}
+		if len(query.GroupBy) > 0 {
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
+		}
+	} else {
+		selectClause = "SELECT COUNT(*)"
+	}
+
+	// Paging an ungrouped aggregate pages the rows it reads
+	if (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0 {
+		innerSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
+		whereClause, whereArgs := BuildWhereClause(query.Conditions)
+		innerSQL += whereClause
+		args = append(args, whereArgs...)
+		if len(query.OrderBy) > 0 {
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		innerSQL = paginate(innerSQL, query.Limit, query.Offset, opts)
+		return fmt.Sprintf("%s FROM (%s) subquery", selectClause, innerSQL), args
+	}
+
+	sql := fmt.Sprintf("%s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.Having) > 0 {
+		havingClause, havingArgs := BuildHavingClause(query.Having)
+		sql += havingClause
+		args = append(args, havingArgs...)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset, opts), args
+}
+
+// BuildWindowSQL creates window function queries
+// Oracle allows * next to other columns only qualified: users.*, RANK() OVER (...)
+func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	table := QuoteIdentifier(query.Table)
+	selectParts := []string{table + ".*"}
+
+	for _, wf := range query.WindowFunctions {
+		windowFunc := strings.ReplaceAll(strings.ToUpper(wf.Function), " ", "_")
+
+		var funcSQL string
+		switch windowFunc {
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
+		case "NTILE":
+			buckets := wf.Buckets
+			if buckets <= 0 {
+				buckets = 4
+			}
+			funcSQL = fmt.Sprintf("NTILE(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
+		default:
+			funcSQL = fmt.Sprintf("%s()", windowFunc)
+		}
+
+		var overParts []string
+		if len(wf.PartitionBy) > 0 {
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
+		}
+		if len(wf.OrderBy) > 0 {
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		}
+		if wf.FrameUnit != "" {
+			overParts = append(overParts, fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd))
+		}
+
+		column := fmt.Sprintf("%s OVER (%s)", funcSQL, strings.Join(overParts, " "))
+		if wf.Alias != "" {
+			column += " AS " + QuoteIdentifier(wf.Alias)
+		}
+		selectParts = append(selectParts, column)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), table)
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, buildLiteralSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM(amount), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// BuildSetOperationSQL joins two queries with UNION / INTERSECT / MINUS
+// (Oracle's EXCEPT before 21c). A query with its own ORDER BY or row limit, or
+// a nested set operation, is read from an inline view; the outer ORDER BY and
+// row limit apply to the combined result.
+func BuildSetOperationSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	setOp := query.SetOperation
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(setOp.LeftQuery, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(setOp.RightQuery, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql := fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL)
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset, opts), append(leftArgs, rightArgs...), nil
+}
+
+// buildSetOperandSQL builds one query of a set operation, which may itself be
+// a set operation, a join or an aggregate
+func buildSetOperandSQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	var sql string
+	var args []interface{}
+	var err error
+	switch {
+	case query.SetOperation != nil:
+		sql, args, err = BuildSetOperationSQL(query, opts)
+	case len(query.Joins) > 0:
+		sql, args = BuildJoinSQL(query, opts)
+	case query.Aggregate != nil:
+		sql, args = BuildAggregateSQL(query, opts)
+	default:
+		sql, args, err = BuildSelectSQL(query, opts)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if query.SetOperation != nil || len(query.OrderBy) > 0 || query.Limit > 0 || query.Offset > 0 {
+		sql = "SELECT * FROM (" + sql + ")"
+	}
+	return sql, args, nil
+}
+
+func setOperator(operationType string) string {
+	switch strings.ToUpper(operationType) {
+	case "UNION_ALL", "UNION ALL":
+		return "UNION ALL"
+	case "INTERSECT":
+		return "INTERSECT"
+	case "EXCEPT":
+		return "MINUS"
+	default:
+		return "UNION"
+	}
+}
+
+func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+func BuildHavingClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " HAVING " + clause, args
+}
+
+// ============================================================================
+// TCL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildBeginSQL starts a transaction explicitly. Oracle otherwise starts one
+// with the first statement that changes data.
+func BuildBeginSQL() string {
+	return "SET TRANSACTION READ WRITE"
+}
+
+// BuildSetTransactionSQL sets the current transaction READ ONLY or its
+// isolation level; Oracle has READ COMMITTED and SERIALIZABLE only, so the
+// other levels are raised to the next one up
+func BuildSetTransactionSQL(query *pb.RelationalQuery) string {
+	if query.ReadOnly {
+		return "SET TRANSACTION READ ONLY"
+	}
+	return "SET TRANSACTION ISOLATION LEVEL " + TranslateIsolationLevel(query.IsolationLevel)
+}
+
+// TranslateIsolationLevel maps an isolation level onto one Oracle has
+func TranslateIsolationLevel(level string) string {
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(level), "_", " ")) {
+	case "READ UNCOMMITTED", "READ COMMITTED":
+		return "READ COMMITTED"
+	default:
+		return "SERIALIZABLE"
+	}
+}
+
+func BuildSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
+}
+
+func BuildRollbackToSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
+}
+
+// ============================================================================
+// CTE OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCTESQL creates WITH name AS (...) SELECT ...
+// Oracle writes a recursive CTE without RECURSIVE but with its column list,
+// which is taken from the anchor's columns.
+func BuildCTESQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if query.Cte == nil {
+		return "", nil, fmt.Errorf("no CTE specified")
+	}
+	cteSQL, params, err := buildCTEBodySQL(query.Cte.CteQuery, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	if query.Cte.Recursive {
+		columns := cteColumns(query.Cte.CteQuery)
+		if len(columns) == 0 {
+			return "", nil, fmt.Errorf("Oracle needs the columns of recursive CTE %s: list them in its first GET", query.Cte.CteName)
+		}
+		cteName += " (" + strings.Join(columns, ", ") + ")"
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Cte.CteName))
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs, err = BuildSelectSQL(query.Cte.MainQuery, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("WITH %s AS (%s) %s", cteName, cteSQL, mainSQL), params, nil
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+func buildCTEBodySQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if query == nil {
+		return "", nil, fmt.Errorf("no CTE query specified")
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		leftSQL, leftArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery), opts)
+		if err != nil {
+			return "", nil, err
+		}
+		rightSQL, rightArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery), opts)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL), append(leftArgs, rightArgs...), nil
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query, opts)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		sql, args := BuildAggregateSQL(query, opts)
+		return sql, args, nil
+	}
+	return BuildSelectSQL(query, opts)
+}
+
+// cteColumns names the columns of a recursive CTE after its anchor (the left
+// side of its UNION); nil when the anchor selects * or computed columns
+func cteColumns(query *pb.RelationalQuery) []string {
+	for query != nil && query.SetOperation != nil {
+		query = query.SetOperation.LeftQuery
+	}
+	if query == nil {
+		return nil
+	}
+	return sourceColumnNames(query.Columns)
+}
+
+// ============================================================================
+// SUBQUERY OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSubquerySQL creates an IN subquery, or an EXISTS check selected from DUAL
+func BuildSubquerySQL(query *pb.RelationalQuery, opts Options) (string, []interface{}, error) {
+	if query.Subquery == nil || query.Subquery.Subquery == nil {
+		return "", nil, fmt.Errorf("no subquery specified")
+	}
+
+	subquerySQL, subArgs, err := BuildSelectSQL(query.Subquery.Subquery, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.ToUpper(query.Subquery.SubqueryType) == "EXISTS" {
+		return fmt.Sprintf("SELECT CASE WHEN EXISTS (%s) THEN 1 ELSE 0 END FROM DUAL", subquerySQL), subArgs, nil
+	}
+	if query.Table == "" {
+		return "", nil, fmt.Errorf("IN subquery requires an outer table")
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
+	var args []interface{}
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := buildConditionsRecursive(query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
+	}
+	sql += fmt.Sprintf("%s IN (%s)", BuildExpressionSQL(query.Subquery.FieldExpr), subquerySQL)
+	return sql, append(args, subArgs...), nil
+}
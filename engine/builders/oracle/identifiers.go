@@ -0,0 +1,103 @@
+package oracle
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// plainIdentifier matches names Oracle reads unquoted: a letter, then letters,
+// digits, _, $ or #
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*$`)
+
+// reservedWords are Oracle reserved words that cannot be used as bare identifiers
+var reservedWords = map[string]bool{
+	"ACCESS": true, "ADD": true, "ALL": true, "ALTER": true, "AND": true,
+	"ANY": true, "AS": true, "ASC": true, "AUDIT": true, "BETWEEN": true,
+	"BY": true, "CHAR": true, "CHECK": true, "CLUSTER": true, "COLUMN": true,
+	"COMMENT": true, "COMPRESS": true, "CONNECT": true, "CREATE": true, "CURRENT": true,
+	"DATE": true, "DECIMAL": true, "DEFAULT": true, "DELETE": true, "DESC": true,
+	"DISTINCT": true, "DROP": true, "ELSE": true, "EXCLUSIVE": true, "EXISTS": true,
+	"FILE": true, "FLOAT": true, "FOR": true, "FROM": true, "GRANT": true,
+	"GROUP": true, "HAVING": true, "IDENTIFIED": true, "IMMEDIATE": true, "IN": true,
+	"INCREMENT": true, "INDEX": true, "INITIAL": true, "INSERT": true, "INTEGER": true,
+	"INTERSECT": true, "INTO": true, "IS": true, "LEVEL": true, "LIKE": true,
+	"LOCK": true, "LONG": true, "MAXEXTENTS": true, "MINUS": true, "MLSLABEL": true,
+	"MODE": true, "MODIFY": true, "NOAUDIT": true, "NOCOMPRESS": true, "NOT": true,
+	"NOWAIT": true, "NULL": true, "NUMBER": true, "OF": true, "OFFLINE": true,
+	"ON": true, "ONLINE": true, "OPTION": true, "OR": true, "ORDER": true,
+	"PCTFREE": true, "PRIOR": true, "PUBLIC": true, "RAW": true, "RENAME": true,
+	"RESOURCE": true, "REVOKE": true, "ROW": true, "ROWID": true, "ROWNUM": true,
+	"ROWS": true, "SELECT": true, "SESSION": true, "SET": true, "SHARE": true,
+	"SIZE": true, "SMALLINT": true, "START": true, "SUCCESSFUL": true, "SYNONYM": true,
+	"SYSDATE": true, "TABLE": true, "THEN": true, "TO": true, "TRIGGER": true,
+	"UID": true, "UNION": true, "UNIQUE": true, "UPDATE": true, "USER": true,
+	"VALIDATE": true, "VALUES": true, "VARCHAR": true, "VARCHAR2": true, "VIEW": true,
+	"WHENEVER": true, "WHERE": true, "WITH": true,
+}
+
+// valueKeywords are Oracle value functions and pseudo-columns that appear as
+// FIELD expressions (SET updated_at = SYSTIMESTAMP) and must not be quoted
+var valueKeywords = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIMESTAMP": true, "LOCALTIMESTAMP": true,
+	"SYSDATE": true, "SYSTIMESTAMP": true, "NULL": true, "DEFAULT": true,
+}
+
+// QuoteIdentifier returns name safe for interpolation into SQL
+// Plain names (users, created_at) are left bare so Oracle folds them to upper
+// case as usual. A reserved word is quoted in upper case ("LEVEL"), the name
+// Oracle would have folded it to, so it still matches bare references; any
+// other name is double-quoted as written. Qualified names (schema.table,
+// table.column) are quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, `"`) {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	if plainIdentifier.MatchString(name) {
+		if !reservedWords[strings.ToUpper(name)] {
+			return name
+		}
+		name = strings.ToUpper(name)
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteIdentifierList quotes a comma-separated list of names (index columns)
+func quoteIdentifierList(names string) string {
+	parts := strings.Split(names, ",")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteColumnRef quotes a FIELD expression value; value keywords such as
+// SYSDATE are written bare in upper case
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return strings.ToUpper(name)
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteString returns s as a single-quoted Oracle string literal
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,139 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (JSON_VALUE / JSON_QUERY / JSON_TRANSFORM / JSON_EXISTS)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and an Oracle path
+// data->'address'->>'city' = data, $.address.city; data->0 = data, $[0]
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []*pb.Expression
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]*pb.Expression{expr.Right}, keys...)
+		expr = expr.Left
+	}
+	path := "$"
+	for _, key := range keys {
+		path += jsonPathStep(key)
+	}
+	return expr, path
+}
+
+// jsonPathStep renders one path leg: [n] for array indexes, .key or ."odd key" for members
+func jsonPathStep(key *pb.Expression) string {
+	if key == nil {
+		return ""
+	}
+	if key.Type == "NUMBER" {
+		return "[" + key.Value + "]"
+	}
+	if isJSONPathIdentifier(key.Value) {
+		return "." + key.Value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key.Value)
+	return `."` + escaped + `"`
+}
+
+// isJSONPathIdentifier reports whether key can appear unquoted in an Oracle JSON path
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			continue
+		}
+		if i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// buildJSONPathSQL renders ->> as JSON_VALUE (a scalar) and -> as JSON_QUERY
+// (an object or array). Oracle takes the path as a literal, never a bind.
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, path := jsonPathTarget(expr)
+	function := "JSON_QUERY"
+	if expr.Operator == "->>" {
+		function = "JSON_VALUE"
+	}
+	return fmt.Sprintf("%s(%s, %s)", function, BuildExpressionSQL(column), QuoteString(path))
+}
+
+// buildJSONCondition renders the JSONB key operators ?, ?| and ?& as JSON_EXISTS
+// The containment operators @> and <@ have no Oracle equivalent and are
+// rejected by the translator before they get here.
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	if cond.Operator == "?" {
+		return fmt.Sprintf("JSON_EXISTS(%s, %s)", field, QuoteString("$"+jsonPathStep(cond.ValueExpr))), nil
+	}
+	// ?| = any key, ?& = all keys
+	if len(cond.ValuesExpr) == 0 {
+		if cond.Operator == "?|" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+	logic := " OR "
+	if cond.Operator == "?&" {
+		logic = " AND "
+	}
+	parts := make([]string, len(cond.ValuesExpr))
+	for i, key := range cond.ValuesExpr {
+		parts[i] = fmt.Sprintf("JSON_EXISTS(%s, %s)", field, QuoteString("$"+jsonPathStep(key)))
+	}
+	return "(" + strings.Join(parts, logic) + ")", nil
+}
+
+// buildJSONValueSQL renders a value stored into a JSON document
+// Strings and numbers bind as they are; true, false and null are written as
+// JSON text so they keep their JSON type
+func buildJSONValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr == nil {
+		return "'null' FORMAT JSON", nil
+	}
+	switch strings.ToUpper(expr.Value) {
+	case "NULL", "TRUE", "FALSE":
+		if expr.Type != "STRING" {
+			return fmt.Sprintf("'%s' FORMAT JSON", strings.ToLower(expr.Value)), nil
+		}
+	}
+	if expr.Type == "FIELD" && valueKeywords[strings.ToUpper(expr.Value)] {
+		return strings.ToUpper(expr.Value), nil
+	}
+	if expr.Type == "NUMBER" {
+		return "?", []interface{}{ConvertOracleValue(expr.Value)}
+	}
+	return "?", []interface{}{expr.Value}
+}
+
+// buildJSONSetSQL renders the JSON path assignments to one column as a single
+// col = JSON_TRANSFORM(col, SET '$.a' = v1, SET '$.b' = v2); Oracle rejects
+// a column assigned twice in one UPDATE, so they are merged.
+// JSON_TRANSFORM needs Oracle 21c (or 19c from release update 19.10).
+func buildJSONSetSQL(fields []*pb.QueryField) (string, []interface{}) {
+	column, _ := jsonPathTarget(fields[0].NameExpr)
+	columnSQL := BuildExpressionSQL(column)
+	var parts []string
+	var args []interface{}
+	for _, field := range fields {
+		_, path := jsonPathTarget(field.NameExpr)
+		valueSQL, valueArgs := buildJSONValueSQL(field.ValueExpr)
+		parts = append(parts, fmt.Sprintf("SET %s = %s", QuoteString(path), valueSQL))
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("%s = JSON_TRANSFORM(%s, %s)", columnSQL, columnSQL, strings.Join(parts, ", ")), args
+}
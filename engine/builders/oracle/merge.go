@@ -0,0 +1,152 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// ============================================================================
+// UPSERT - MERGE
+// ============================================================================
+
+// Aliases of the MERGE target table and of the rows being merged; EXCLUDED.col
+// in an update expression reads the merged row, as s.col
+const (
+	mergeTarget = "t"
+	mergeSource = "s"
+)
+
+// BuildUpsertSQL creates UPSERT as a MERGE of one row selected from DUAL
+func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Upsert == nil {
+		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
+	}
+	return buildMergeSQL(query.Table, []*pb.BulkInsertRow{{Fields: query.Fields}}, query.Upsert)
+}
+
+// BuildBulkUpsertSQL creates BULK UPSERT as one MERGE of all rows
+func BuildBulkUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_UPSERT requires data rows")
+	}
+	if query.Upsert == nil {
+		return "", nil, fmt.Errorf("BULK_UPSERT requires conflict fields")
+	}
+	return buildMergeSQL(query.Table, query.BulkData, query.Upsert)
+}
+
+// buildMergeSQL renders:
+//
+//	MERGE INTO users t
+//	USING (SELECT ? AS email, ? AS name FROM DUAL UNION ALL ...) s
+//	ON (t.email = s.email)
+//	WHEN MATCHED THEN UPDATE SET t.name = s.name
+//	WHEN NOT MATCHED THEN INSERT (email, name) VALUES (s.email, s.name)
+//
+// The ON columns are matched like a unique key but need not be one; Oracle
+// cannot update them, so they are left out of the SET list.
+func buildMergeSQL(table string, rows []*pb.BulkInsertRow, upsert *pb.UpsertClause) (string, []interface{}, error) {
+	if upsert.ConflictConstraint != "" {
+		return "", nil, fmt.Errorf("Oracle cannot target constraint %s: name the conflict columns instead", upsert.ConflictConstraint)
+	}
+	if len(upsert.ConflictFields) == 0 {
+		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
+	}
+	if len(upsert.ConflictWhere) > 0 {
+		return "", nil, fmt.Errorf("Oracle MERGE has no conflict target predicate")
+	}
+
+	var columns []string
+	for _, field := range rows[0].Fields {
+		columns = append(columns, QuoteIdentifier(getFieldName(field)))
+	}
+
+	var selects []string
+	var args []interface{}
+	for _, row := range rows {
+		values := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			values[i] = fmt.Sprintf("%s AS %s", valueSQL, columns[i])
+			args = append(args, valueArgs...)
+		}
+		selects = append(selects, fmt.Sprintf("SELECT %s FROM DUAL", strings.Join(values, ", ")))
+	}
+
+	keys := map[string]bool{}
+	var on []string
+	for _, cf := range upsert.ConflictFields {
+		column := QuoteIdentifier(cf.Value)
+		keys[strings.ToUpper(cf.Value)] = true
+		on = append(on, fmt.Sprintf("%s.%s = %s.%s", mergeTarget, column, mergeSource, column))
+	}
+
+	sql := fmt.Sprintf("MERGE INTO %s %s USING (%s) %s ON (%s)",
+		QuoteIdentifier(table), mergeTarget, strings.Join(selects, " UNION ALL "), mergeSource, strings.Join(on, " AND "))
+
+	var updateParts []string
+	for _, field := range upsert.UpdateFields {
+		name := getFieldName(field)
+		if keys[strings.ToUpper(name)] {
+			continue
+		}
+		column := QuoteIdentifier(name)
+		if field.ValueExpr == nil {
+			// No value: take the row being merged
+			updateParts = append(updateParts, fmt.Sprintf("%s.%s = %s.%s", mergeTarget, column, mergeSource, column))
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(qualifyColumns(field.ValueExpr))
+		updateParts = append(updateParts, fmt.Sprintf("%s.%s = %s", mergeTarget, column, valueSQL))
+		args = append(args, valueArgs...)
+	}
+	if len(updateParts) > 0 {
+		sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(updateParts, ", ")
+	}
+
+	sourceColumns := make([]string, len(columns))
+	for i, column := range columns {
+		sourceColumns[i] = mergeSource + "." + column
+	}
+	sql += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(columns, ", "), strings.Join(sourceColumns, ", "))
+
+	return sql, args, nil
+}
+
+// qualifyColumns returns a copy of an update expression with its bare column
+// references (visits + 1) read from the target row (t.visits + 1); both
+// sides of a MERGE have the same column names
+func qualifyColumns(expr *pb.Expression) *pb.Expression {
+	if !builderutil.IsComputed(expr) {
+		return expr
+	}
+	qualified := proto.Clone(expr).(*pb.Expression)
+	var walk func(e *pb.Expression)
+	walk = func(e *pb.Expression) {
+		if e == nil {
+			return
+		}
+		if e.Type == "FIELD" && !strings.Contains(e.Value, ".") && !valueKeywords[strings.ToUpper(e.Value)] {
+			e.Value = mergeTarget + "." + e.Value
+		}
+		walk(e.Left)
+		walk(e.Right)
+		for _, arg := range e.FunctionArgs {
+			walk(arg)
+		}
+	}
+	walk(qualified)
+	return qualified
+}
+
+// excludedColumn returns col for an EXCLUDED.col reference
+func excludedColumn(name string) (string, bool) {
+	if len(name) > len("EXCLUDED.") && strings.EqualFold(name[:len("EXCLUDED.")], "EXCLUDED.") {
+		return name[len("EXCLUDED."):], true
+	}
+	return "", false
+}
@@ -0,0 +1,9 @@
+package oracle
+
+// Options are the per-client settings of the Oracle builders. The zero value
+// suits Oracle 12c and later.
+type Options struct {
+	// LegacyPagination pages with ROWNUM instead of OFFSET ... FETCH, for
+	// databases older than Oracle 12c
+	LegacyPagination bool
+}
@@ -0,0 +1,36 @@
+package oracle
+
+import "fmt"
+
+// paginate applies LIMIT / OFFSET to a built query
+// Oracle 12c+ writes OFFSET n ROWS FETCH NEXT m ROWS ONLY; with
+// Options.LegacyPagination the query is wrapped in ROWNUM filters instead.
+func paginate(sql string, limit, offset int32, opts Options) string {
+	if limit <= 0 && offset <= 0 {
+		return sql
+	}
+	if opts.LegacyPagination {
+		return paginateRownum(sql, limit, offset)
+	}
+	if offset > 0 {
+		sql += fmt.Sprintf(" OFFSET %d ROWS", offset)
+	}
+	if limit > 0 {
+		sql += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return sql
+}
+
+// paginateRownum pages with ROWNUM, which is numbered before ORDER BY is
+// applied, so the ordered query is nested and filtered from outside
+// The extra oql_rn column is returned with each row of an offset page.
+func paginateRownum(sql string, limit, offset int32) string {
+	if offset <= 0 {
+		return fmt.Sprintf("SELECT * FROM (%s) WHERE ROWNUM <= %d", sql, limit)
+	}
+	inner := fmt.Sprintf("SELECT q.*, ROWNUM oql_rn FROM (%s) q", sql)
+	if limit > 0 {
+		inner += fmt.Sprintf(" WHERE ROWNUM <= %d", offset+limit)
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) WHERE oql_rn > %d", inner, offset)
+}
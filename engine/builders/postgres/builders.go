@@ -1,10 +1,15 @@
 package postgres
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 )
 
@@ -119,6 +124,14 @@ func buildConditionsRecursive(conditions []*pb.QueryCondition, startParamNum int
 }
 
 func buildSingleCondition(cond *pb.QueryCondition, paramNum int) (string, []interface{}, int) {
+	// Array conditions: UNNEST(tags) LIKE 'a%', 'admin' = ANY(roles)
+	if isFunctionExpr(cond.FieldExpr, "UNNEST") {
+		return buildUnnestCondition(cond, paramNum)
+	}
+	if isFunctionExpr(cond.ValueExpr, "ANY") || isFunctionExpr(cond.ValueExpr, "ALL") {
+		return buildQuantifiedCondition(cond, paramNum)
+	}
+
 	// Build field expression (handles BINARY, FUNCTION, FIELD)
 	field := BuildExpressionSQL(cond.FieldExpr)
 	
@@ -135,9 +148,22 @@ func buildSingleCondition(cond *pb.QueryCondition, paramNum int) (string, []inte
 		return buildBetweenClauseExpr(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr, paramNum)
 	case "NOT_BETWEEN":
 		return buildBetweenClauseExpr(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr, paramNum)
+	case "?|", "?&":
+		return buildKeyArrayClause(field, cond.Operator, cond.ValuesExpr, paramNum)
+	case "SEARCH":
+		return fmt.Sprintf("to_tsvector(%s) @@ plainto_tsquery($%d)", field, paramNum), []interface{}{getCondValue(cond)}, 1
+	case "NEAR":
+		return buildNearSQL(field, cond, paramNum)
+	case "WITHIN":
+		return buildWithinSQL(field, cond, paramNum)
 	default:
-		// Check if ValueExpr is a complex expression (BINARY/FUNCTION)
-		if cond.ValueExpr != nil && (cond.ValueExpr.Type == "BINARY" || cond.ValueExpr.Type == "FUNCTION") {
+		// Array literal: tags @> ARRAY('a', 'b')
+		if isFunctionExpr(cond.ValueExpr, "ARRAY") {
+			arraySQL, args, n := buildArrayLiteral(cond.ValueExpr.FunctionArgs, paramNum)
+			return fmt.Sprintf("%s %s %s", field, cond.Operator, arraySQL), args, n
+		}
+		// Check if ValueExpr is a complex expression (BINARY/FUNCTION/JSON_PATH)
+		if cond.ValueExpr != nil && (cond.ValueExpr.Type == "BINARY" || cond.ValueExpr.Type == "FUNCTION" || cond.ValueExpr.Type == "JSON_PATH") {
 			valueSQL := BuildExpressionSQL(cond.ValueExpr)
 			return fmt.Sprintf("%s %s %s", field, cond.Operator, valueSQL), nil, 0
 		}
@@ -165,6 +191,124 @@ func buildInClause(field, operator string, values []*pb.Expression, startParam i
 	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args, len(values)
 }
 
+// ============================================================================
+// ARRAY CONDITIONS
+// ============================================================================
+
+// isFunctionExpr checks if expr is a call to the named function
+func isFunctionExpr(expr *pb.Expression, name string) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == name
+}
+
+// isLiteralExpr checks if expr is a plain value (parameterized, never interpolated)
+func isLiteralExpr(expr *pb.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	switch expr.Type {
+	case "STRING", "NUMBER", "BOOLEAN", "LITERAL":
+		return true
+	}
+	return false
+}
+
+// buildArrayLiteral builds: ARRAY[$1, $2, ...] (non-literal elements are inlined)
+func buildArrayLiteral(elements []*pb.Expression, startParam int) (string, []interface{}, int) {
+	var parts []string
+	var args []interface{}
+	for _, e := range elements {
+		if isLiteralExpr(e) {
+			parts = append(parts, fmt.Sprintf("$%d", startParam+len(args)))
+			args = append(args, e.Value)
+		} else {
+			parts = append(parts, BuildExpressionSQL(e))
+		}
+	}
+	return "ARRAY[" + strings.Join(parts, ", ") + "]", args, len(args)
+}
+
+// buildQuantifiedCondition builds: value op ANY(array) / value op ALL(array)
+// ANY(roles) uses the column; ANY('a', 'b') becomes ANY(ARRAY[$1, $2])
+func buildQuantifiedCondition(cond *pb.QueryCondition, paramNum int) (string, []interface{}, int) {
+	var args []interface{}
+	consumed := 0
+
+	left := BuildExpressionSQL(cond.FieldExpr)
+	if isLiteralExpr(cond.FieldExpr) {
+		left = fmt.Sprintf("$%d", paramNum)
+		args = append(args, cond.FieldExpr.Value)
+		consumed++
+	}
+
+	quantifier := strings.ToUpper(cond.ValueExpr.FunctionName)
+	elements := cond.ValueExpr.FunctionArgs
+	var right string
+	if len(elements) == 1 && !isLiteralExpr(elements[0]) {
+		right = BuildExpressionSQL(elements[0])
+	} else {
+		arraySQL, arrayArgs, n := buildArrayLiteral(elements, paramNum+consumed)
+		right = arraySQL
+		args = append(args, arrayArgs...)
+		consumed += n
+	}
+
+	operator := strings.ReplaceAll(cond.Operator, "_", " ")
+	return fmt.Sprintf("%s %s %s(%s)", left, operator, quantifier, right), args, consumed
+}
+
+// buildUnnestCondition builds: EXISTS (SELECT 1 FROM UNNEST(array) AS elem WHERE elem op value)
+func buildUnnestCondition(cond *pb.QueryCondition, paramNum int) (string, []interface{}, int) {
+	inner := &pb.QueryCondition{
+		FieldExpr:  &pb.Expression{Type: "FIELD", Value: "elem"},
+		Operator:   cond.Operator,
+		ValueExpr:  cond.ValueExpr,
+		Value2Expr: cond.Value2Expr,
+		ValuesExpr: cond.ValuesExpr,
+	}
+	innerSQL, args, consumed := buildSingleCondition(inner, paramNum)
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS elem WHERE %s)", BuildExpressionSQL(cond.FieldExpr), innerSQL), args, consumed
+}
+
+// ============================================================================
+// FULL-TEXT SEARCH
+// ============================================================================
+
+// findSearchCondition returns the first SEARCH condition (recursive)
+func findSearchCondition(conditions []*pb.QueryCondition) *pb.QueryCondition {
+	for _, cond := range conditions {
+		if cond.Operator == "SEARCH" {
+			return cond
+		}
+		if found := findSearchCondition(cond.Nested); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// buildSearchRank builds the ORDER BY RELEVANCE expression for a SEARCH query
+// The search text is inlined as a literal so the WHERE parameter numbering is untouched
+func buildSearchRank(conditions []*pb.QueryCondition) string {
+	cond := findSearchCondition(conditions)
+	if cond == nil {
+		return ""
+	}
+	return fmt.Sprintf("ts_rank(to_tsvector(%s), plainto_tsquery(%s))",
+		BuildExpressionSQL(cond.FieldExpr), QuoteLiteral(getCondValue(cond)))
+}
+
+// buildKeyArrayClause builds JSONB key existence: field ?| ARRAY[$1, $2]
+func buildKeyArrayClause(field, operator string, keys []*pb.Expression, startParam int) (string, []interface{}, int) {
+	placeholders := make([]string, len(keys))
+	args := make([]interface{}, len(keys))
+	for i, k := range keys {
+		placeholders[i] = fmt.Sprintf("$%d", startParam+i)
+		args[i] = k.Value
+	}
+
+	return fmt.Sprintf("%s %s ARRAY[%s]::text[]", field, operator, strings.Join(placeholders, ", ")), args, len(keys)
+}
+
 func buildBetweenClause(field, operator, value1, value2 string, startParam int) (string, []interface{}, int) {
 	return fmt.Sprintf("%s %s $%d AND $%d", field, operator, startParam, startParam+1), []interface{}{value1, value2}, 2
 }
@@ -189,13 +333,29 @@ func buildBetweenClauseExpr(field, operator string, value1Expr, value2Expr *pb.E
 }
 
 func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	return buildSelectSQL(query, 0)
+}
+
+// buildAsOfClause renders a historical read, which ends the FROM clause:
+// AS OF SYSTEM TIME '-10s'. Only CockroachDB queries carry one: PostgreSQL
+// has no historical reads and rejects AS OF before translation.
+func buildAsOfClause(asOf string) string {
+	if asOf == "" {
+		return ""
+	}
+	return " AS OF SYSTEM TIME " + QuoteLiteral(asOf)
+}
+
+// buildSelectSQL numbers parameters from argOffset+1 so the result can be
+// embedded after other parameterized SQL (CTE main query)
+func buildSelectSQL(query *pb.RelationalQuery, argOffset int) (string, []interface{}) {
 	selectClause := "SELECT"
 	if query.Distinct {
 		selectClause = "SELECT DISTINCT"
 	}
 	
 	var args []interface{}
-	paramNum := 1
+	paramNum := argOffset + 1
 	
 	columns := "*"
 	if len(query.SelectColumns) > 0 {
@@ -228,19 +388,19 @@ func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
 				}
 				caseSQL += " END"
 				if col.Alias != "" {
-					caseSQL += " AS " + col.Alias
+					caseSQL += " AS " + QuoteIdentifier(col.Alias)
 				}
 				colParts = append(colParts, caseSQL)
 			} else if col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW" {
 				windowSQL := buildWindowExprSQL(col.ExpressionObj)
 				if col.Alias != "" {
-					windowSQL += " AS " + col.Alias
+					windowSQL += " AS " + QuoteIdentifier(col.Alias)
 				}
 				colParts = append(colParts, windowSQL)
 			} else {
 				colStr := BuildExpressionSQL(col.ExpressionObj)
 				if col.Alias != "" {
-					colStr += " AS " + col.Alias
+					colStr += " AS " + QuoteIdentifier(col.Alias)
 				}
 				colParts = append(colParts, colStr)
 			}
@@ -249,23 +409,20 @@ func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	} else if len(query.Columns) > 0 {
 		var colStrs []string
 		for _, col := range query.Columns {
-			colStrs = append(colStrs, col.Value)
+			colStrs = append(colStrs, quoteColumnRef(col.Value))
 		}
 		columns = strings.Join(colStrs, ", ")
 	}
 	
-	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, query.Table)
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
+	sql += buildAsOfClause(query.AsOf)
 	whereClause, whereArgs := BuildWhereClause(query.Conditions, paramNum)
 	sql += whereClause
 	args = append(args, whereArgs...)
 
 	// GROUP BY
 	if len(query.GroupBy) > 0 {
-		var groupByStrs []string
-		for _, gb := range query.GroupBy {
-			groupByStrs = append(groupByStrs, gb.Value)
-		}
-		sql += " GROUP BY " + strings.Join(groupByStrs, ", ")
+		sql += " GROUP BY " + buildGroupByList(query.GroupBy)
 	}
 
 	// ORDER BY
@@ -273,7 +430,13 @@ func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		sql += " ORDER BY "
 		var orderParts []string
 		for _, ob := range query.OrderBy {
-			orderParts = append(orderParts, fmt.Sprintf("%s %s", getOrderByField(ob), ob.Direction))
+			field := getOrderByField(ob)
+			if ob.FieldExpr != nil && mapping.IsSearchRankField(ob.FieldExpr.Value) {
+				if rank := buildSearchRank(query.Conditions); rank != "" {
+					field = rank
+				}
+			}
+			orderParts = append(orderParts, fmt.Sprintf("%s %s", field, ob.Direction))
 		}
 		sql += strings.Join(orderParts, ", ")
 	}
@@ -284,6 +447,12 @@ func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	if query.Offset > 0 {
 		sql += fmt.Sprintf(" OFFSET %d", query.Offset)
 	}
+	if query.Lock != "" {
+		sql += " FOR " + query.Lock
+		if query.LockWait != "" {
+			sql += " " + query.LockWait
+		}
+	}
 	
 	return sql, args
 }
@@ -300,7 +469,7 @@ func buildConditionSQL(cond *pb.QueryCondition) string {
         value = fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
     }
     
-    return fmt.Sprintf("%s %s %s", field, cond.Operator, value)
+    return fmt.Sprintf("%s %s %s", quoteColumnRef(field), cond.Operator, value)
 }
 
 func buildWindowExprSQL(expr *pb.Expression) string {
@@ -312,7 +481,7 @@ func buildWindowExprSQL(expr *pb.Expression) string {
 		field := "id"
 		for _, arg := range expr.FunctionArgs {
 			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
-				field = arg.Value
+				field = QuoteIdentifier(arg.Value)
 				break
 			}
 		}
@@ -333,13 +502,13 @@ func buildWindowExprSQL(expr *pb.Expression) string {
 	var partitionParts, orderParts []string
 	for _, arg := range expr.FunctionArgs {
 		if strings.HasPrefix(arg.Value, "PARTITION:") {
-			partitionParts = append(partitionParts, strings.TrimPrefix(arg.Value, "PARTITION:"))
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
 		} else if strings.HasPrefix(arg.Value, "ORDER:") {
 			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
 			if len(parts) >= 2 {
-				orderParts = append(orderParts, fmt.Sprintf("%s %s", parts[0], parts[1]))
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
 			} else if len(parts) == 1 {
-				orderParts = append(orderParts, parts[0]+" ASC")
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
 			}
 		}
 	}
@@ -370,48 +539,140 @@ func BuildExpressionSQL(expr *pb.Expression) string {
 		right := BuildExpressionSQL(expr.Right)
 		
 		// Add parentheses around nested BINARY to preserve precedence
-		if expr.Left != nil && expr.Left.Type == "BINARY" {
+		// (-> and ->> bind looser than arithmetic in PostgreSQL)
+		if expr.Left != nil && (expr.Left.Type == "BINARY" || expr.Left.Type == "JSON_PATH") {
 			left = "(" + left + ")"
 		}
-		if expr.Right != nil && expr.Right.Type == "BINARY" {
+		if expr.Right != nil && (expr.Right.Type == "BINARY" || expr.Right.Type == "JSON_PATH") {
 			right = "(" + right + ")"
 		}
 		
 		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "JSON_PATH":
+		// data->'key' / data->>'key' / data->0
+		key := ""
+		if expr.Right != nil {
+			key = expr.Right.Value
+			if expr.Right.Type != "NUMBER" {
+				key = QuoteLiteral(key)
+			}
+		}
+		return fmt.Sprintf("%s%s%s", BuildExpressionSQL(expr.Left), expr.Operator, key)
 	case "FUNCTION":
 		var args []string
 		for _, arg := range expr.FunctionArgs {
 			args = append(args, BuildExpressionSQL(arg))
 		}
-		return fmt.Sprintf("%s(%s)", expr.FunctionName, strings.Join(args, ", "))
+		// ARRAY(a, b) is the OQL spelling of an ARRAY[a, b] literal
+		if strings.ToUpper(expr.FunctionName) == "ARRAY" {
+			for i, arg := range expr.FunctionArgs {
+				if arg.Type == "STRING" {
+					args[i] = QuoteLiteral(arg.Value)
+				}
+			}
+			return "ARRAY[" + strings.Join(args, ", ") + "]"
+		}
+		return mapping.FunctionSQL("PostgreSQL", expr.FunctionName, args)
+	case "STRING":
+		return QuoteLiteral(expr.Value)
+	case "FIELD":
+		return quoteColumnRef(expr.Value)
+	case "COLLATE":
+		return buildCollateSQL(expr)
 	default:
 		return expr.Value
 	}
 }
 
 func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query), nil
+	}
+
 	var fields, placeholders []string
 	var args []interface{}
 
 	for i, field := range query.Fields {
-		fields = append(fields, getFieldName(field))
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
 		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
 		args = append(args, getFieldValue(field))
 	}
 
 	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		query.Table, strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	sql += BuildReturningClause(query.Returning)
 
 	return sql, args
 }
 
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) string {
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + buildViewQuerySQL(query.ViewQuery)
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildReturningClause builds: RETURNING col, ... (empty when no columns requested)
+func BuildReturningClause(returning []*pb.Expression) string {
+	if len(returning) == 0 {
+		return ""
+	}
+	var cols []string
+	for _, expr := range returning {
+		cols = append(cols, BuildExpressionSQL(expr))
+	}
+	return " RETURNING " + strings.Join(cols, ", ")
+}
+
 func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	var setParts []string
 	var args []interface{}
 	paramNum := 1
+	jsonTargets := map[string]int{}
 	
 	for _, field := range query.Fields {
-		fieldName := getFieldName(field)
+		// metadata->'plan' = "pro": jsonb_set, nested when a column is set more than once
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, path := jsonbPathTarget(field.NameExpr)
+			target := BuildExpressionSQL(column)
+			source := target
+			idx, seen := jsonTargets[target]
+			if seen {
+				source = strings.TrimPrefix(setParts[idx], target+" = ")
+			}
+			valueSQL, valueArgs := buildJSONBValueSQL(field.ValueExpr, paramNum)
+			setSQL := fmt.Sprintf("%s = jsonb_set(%s, %s, %s)", target, source, QuoteLiteral(path), valueSQL)
+			if seen {
+				setParts[idx] = setSQL
+			} else {
+				jsonTargets[target] = len(setParts)
+				setParts = append(setParts, setSQL)
+			}
+			args = append(args, valueArgs...)
+			paramNum += len(valueArgs)
+			continue
+		}
+		fieldName := QuoteIdentifier(getFieldName(field))
 		
 		if field.ValueExpr != nil && field.ValueExpr.Type == "BINARY" {
 			exprSQL := BuildExpressionSQL(field.ValueExpr)
@@ -443,14 +704,62 @@ func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		}
 	}
 	
-	sql := fmt.Sprintf("UPDATE %s SET %s", query.Table, strings.Join(setParts, ", "))
+	sql := fmt.Sprintf("UPDATE %s SET %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
 	whereClause, whereArgs := BuildWhereClause(query.Conditions, paramNum)
 	sql += whereClause
+	sql += BuildReturningClause(query.Returning)
 	args = append(args, whereArgs...)
 	
 	return sql, args
 }
 
+// jsonbPathTarget splits a JSON_PATH chain into its column and a jsonb_set path
+// data->'address'->'city' = data, {address,city}
+func jsonbPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []string
+	for expr != nil && expr.Type == "JSON_PATH" {
+		key := ""
+		if expr.Right != nil {
+			key = expr.Right.Value
+		}
+		if key == "" || strings.ContainsAny(key, `{},"\ `) {
+			key = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"`
+		}
+		keys = append([]string{key}, keys...)
+		expr = expr.Left
+	}
+	return expr, "{" + strings.Join(keys, ",") + "}"
+}
+
+// buildJSONBValueSQL renders the new value of a jsonb_set: literals bind as JSON text,
+// computed expressions go through to_jsonb
+func buildJSONBValueSQL(expr *pb.Expression, paramNum int) (string, []interface{}) {
+	if expr == nil {
+		return "'null'::jsonb", nil
+	}
+	switch expr.Type {
+	case "BINARY", "FUNCTION", "CASEWHEN", "JSON_PATH":
+		return fmt.Sprintf("to_jsonb(%s)", BuildExpressionSQL(expr)), nil
+	case "NUMBER":
+		return fmt.Sprintf("$%d::jsonb", paramNum), []interface{}{expr.Value}
+	case "STRING":
+		return fmt.Sprintf("$%d::jsonb", paramNum), []interface{}{jsonString(expr.Value)}
+	}
+	switch upper := strings.ToUpper(expr.Value); {
+	case upper == "NULL" || upper == "TRUE" || upper == "FALSE":
+		return fmt.Sprintf("'%s'::jsonb", strings.ToLower(upper)), nil
+	case expr.Type == "FIELD" && valueKeywords[upper]:
+		return fmt.Sprintf("to_jsonb(%s)", expr.Value), nil
+	}
+	return fmt.Sprintf("$%d::jsonb", paramNum), []interface{}{jsonString(expr.Value)}
+}
+
+// jsonString encodes s as a JSON string literal
+func jsonString(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
+
 func isIdentifier(s string) bool {
 	s = strings.TrimSpace(s)
 	if (strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'")) ||
@@ -462,9 +771,10 @@ func isIdentifier(s string) bool {
 }
 
 func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
-	sql := fmt.Sprintf("DELETE FROM %s", query.Table)
+	sql := fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table))
 	whereClause, args := BuildWhereClause(query.Conditions, 1)
 	sql += whereClause
+	sql += BuildReturningClause(query.Returning)
 	return sql, args
 }
 
@@ -477,42 +787,111 @@ func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	var args []interface{}
 
 	for i, field := range query.Fields {
-		fields = append(fields, getFieldName(field))
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
 		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
 		args = append(args, getFieldValue(field))
 	}
 
 	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		query.Table, strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
 
-	if len(query.Upsert.ConflictFields) > 0 {
+	conflictSQL, args := buildOnConflictSQL(query.Upsert, args)
+	sql += conflictSQL
+	sql += BuildReturningClause(query.Returning)
+
+		return sql, args
+}
+
+// buildOnConflictSQL renders ON CONFLICT target DO UPDATE SET ... or
+// ON CONFLICT [target] DO NOTHING, numbering parameters after args and
+// returning args with its own appended
+func buildOnConflictSQL(upsert *pb.UpsertClause, args []interface{}) (string, []interface{}) {
+	var target string
+	if upsert.ConflictConstraint != "" {
+		target = " ON CONSTRAINT " + QuoteIdentifier(upsert.ConflictConstraint)
+	} else if len(upsert.ConflictFields) > 0 {
 		var conflictFieldStrs []string
-		for _, cf := range query.Upsert.ConflictFields {
-			conflictFieldStrs = append(conflictFieldStrs, cf.Value)
+		for _, cf := range upsert.ConflictFields {
+			conflictFieldStrs = append(conflictFieldStrs, QuoteIdentifier(cf.Value))
 		}
-		sql += fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET ", strings.Join(conflictFieldStrs, ", "))
+		target = fmt.Sprintf(" (%s)", strings.Join(conflictFieldStrs, ", "))
+
+		// Partial unique index: the predicate lets PostgreSQL infer it
+		if len(upsert.ConflictWhere) > 0 {
+			where, whereArgs := BuildWhereClause(upsert.ConflictWhere, len(args)+1)
+			target += where
+			args = append(args, whereArgs...)
+		}
+	}
+
+	// DO NOTHING needs no target: without one any unique violation skips the row
+	if upsert.ConflictAction == "NOTHING" {
+		return " ON CONFLICT" + target + " DO NOTHING", args
+	}
+
+	sql := ""
+	if target != "" {
+		sql += fmt.Sprintf(" ON CONFLICT%s DO UPDATE SET ", target)
 
 		var updateParts []string
-	for _, field := range query.Upsert.UpdateFields {
-			fieldName := getFieldName(field)
-			updateParts = append(updateParts, fmt.Sprintf("%s = EXCLUDED.%s", fieldName, fieldName))
+		for _, field := range upsert.UpdateFields {
+			fieldName := QuoteIdentifier(getFieldName(field))
+			switch {
+			case field.ValueExpr == nil:
+				// No value: take the row proposed for insertion
+				updateParts = append(updateParts, fmt.Sprintf("%s = EXCLUDED.%s", fieldName, fieldName))
+			case isExcludedRef(field.ValueExpr), field.ValueExpr.Type == "BINARY",
+				field.ValueExpr.Type == "FUNCTION", field.ValueExpr.Type == "CASEWHEN",
+				field.ValueExpr.Type == "FIELD" && valueKeywords[strings.ToUpper(field.ValueExpr.Value)]:
+				updateParts = append(updateParts, fmt.Sprintf("%s = %s", fieldName, BuildExpressionSQL(field.ValueExpr)))
+			default:
+				args = append(args, getFieldValue(field))
+				updateParts = append(updateParts, fmt.Sprintf("%s = $%d", fieldName, len(args)))
+			}
 		}
 		sql += strings.Join(updateParts, ", ")
 	}
 
-		return sql, args
+	return sql, args
 }
 
+// isExcludedRef reports whether expr references the proposed row (EXCLUDED.col)
+func isExcludedRef(expr *pb.Expression) bool {
+	return expr != nil && expr.Type == "FIELD" && strings.HasPrefix(strings.ToUpper(expr.Value), "EXCLUDED.")
+}
 
 func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	if len(query.BulkData) == 0 {
 		return "", []interface{}{}
 	}
 
+	sql, args := buildMultiRowInsertSQL(query)
+	sql += BuildReturningClause(query.Returning)
+
+	return sql, args
+}
+
+// BuildBulkUpsertSQL creates BULK UPSERT as a multi-row INSERT ... ON CONFLICT DO UPDATE
+// PostgreSQL rejects a statement that updates the same row twice, so conflict keys must be unique across rows.
+func BuildBulkUpsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if len(query.BulkData) == 0 || query.Upsert == nil {
+		return "", []interface{}{}
+	}
+
+	sql, args := buildMultiRowInsertSQL(query)
+	conflictSQL, args := buildOnConflictSQL(query.Upsert, args)
+	sql += conflictSQL
+	sql += BuildReturningClause(query.Returning)
+
+	return sql, args
+}
+
+// buildMultiRowInsertSQL renders INSERT INTO t (cols) VALUES ($1, ...), (...) with the first row's columns
+func buildMultiRowInsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	firstRow := query.BulkData[0]
 	var fields []string
 	for _, field := range firstRow.Fields {
-		fields = append(fields, getFieldName(field))
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
 	}
 
 	var valueClauses []string
@@ -530,11 +909,83 @@ func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	}
 
 	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
-		query.Table, strings.Join(fields, ", "), strings.Join(valueClauses, ", "))
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(valueClauses, ", "))
 
 	return sql, args
 }
 
+// ============================================================================
+// COPY (large BULK INSERT)
+// ============================================================================
+
+// UseCopy reports whether a BULK INSERT should be loaded with COPY
+// RETURNING needs an INSERT, so those queries never switch.
+func UseCopy(query *pb.RelationalQuery, opts Options) bool {
+	if len(query.BulkData) == 0 || len(query.Returning) > 0 {
+		return false
+	}
+	return len(query.BulkData)*len(query.BulkData[0].Fields) > opts.copyThreshold()
+}
+
+// BuildCopySQL builds: COPY table (col, ...) FROM STDIN
+func BuildCopySQL(query *pb.RelationalQuery) string {
+	if len(query.BulkData) == 0 {
+		return ""
+	}
+	var columns []string
+	for _, field := range query.BulkData[0].Fields {
+		columns = append(columns, QuoteIdentifier(getFieldName(field)))
+	}
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", QuoteIdentifier(query.Table), strings.Join(columns, ", "))
+}
+
+// BuildCopyRows returns the unquoted table, column names and row values for a
+// COPY load (the shape pgx CopyFrom takes). Column order follows the first row,
+// and every row must set the same columns: COPY has no DEFAULT for the ones a
+// row leaves out.
+func BuildCopyRows(query *pb.RelationalQuery) (string, []string, [][]interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return query.Table, nil, nil, nil
+	}
+
+	var columns []string
+	position := map[string]int{}
+	for _, field := range query.BulkData[0].Fields {
+		position[getFieldName(field)] = len(columns)
+		columns = append(columns, getFieldName(field))
+	}
+
+	rows := make([][]interface{}, 0, len(query.BulkData))
+	for i, row := range query.BulkData {
+		values := make([]interface{}, len(columns))
+		set := make([]bool, len(columns))
+		for _, field := range row.Fields {
+			column, ok := position[getFieldName(field)]
+			if !ok || set[column] {
+				return "", nil, nil, copyColumnsError(i, row, columns)
+			}
+			values[column] = getFieldValue(field)
+			set[column] = true
+		}
+		if len(row.Fields) != len(columns) {
+			return "", nil, nil, copyColumnsError(i, row, columns)
+		}
+		rows = append(rows, values)
+	}
+
+	return query.Table, columns, rows, nil
+}
+
+// copyColumnsError reports a BULK INSERT row whose columns differ from the first row's
+func copyColumnsError(i int, row *pb.BulkInsertRow, columns []string) error {
+	var names []string
+	for _, field := range row.Fields {
+		names = append(names, getFieldName(field))
+	}
+	return fmt.Errorf("BULK INSERT row %d sets (%s), not the columns of the first row (%s): COPY needs the same columns in every row",
+		i+1, strings.Join(names, ", "), strings.Join(columns, ", "))
+}
+
 // ============================================================================
 // DCL OPERATIONS - SQL BUILDERS
 // ============================================================================
@@ -547,7 +998,11 @@ func BuildGrantSQL(query *pb.RelationalQuery) (string, error) {
 		return "", fmt.Errorf("no target user/role specified for GRANT")
 	}
 	privileges := TranslatePermissions(query.Permissions)
-	return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), query.Table, query.PermissionTarget), nil
+	object, err := buildGrantObject(query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), object, QuoteIdentifier(query.PermissionTarget)), nil
 }
 
 func BuildRevokeSQL(query *pb.RelationalQuery) (string, error) {
@@ -558,50 +1013,144 @@ func BuildRevokeSQL(query *pb.RelationalQuery) (string, error) {
 		return "", fmt.Errorf("no target user/role specified for REVOKE")
 	}
 	privileges := TranslatePermissions(query.Permissions)
-	return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privileges, ", "), query.Table, query.PermissionTarget), nil
+	object, err := buildGrantObject(query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privileges, ", "), object, QuoteIdentifier(query.PermissionTarget)), nil
+}
+
+// grantObjectPrivileges lists the privileges each non-table object accepts;
+// ALL PRIVILEGES is valid on every object
+var grantObjectPrivileges = map[string][]string{
+	"DATABASE": {"CONNECT", "CREATE", "TEMP", "TEMPORARY"},
+	"SCHEMA":   {"USAGE", "CREATE"},
+	"SEQUENCE": {"USAGE", "SELECT", "UPDATE"},
+	"FUNCTION": {"EXECUTE"},
+}
+
+// ValidateGrantPrivileges rejects a GRANT or REVOKE of a privilege its object
+// does not have, such as SELECT ON DATABASE
+func ValidateGrantPrivileges(query *pb.RelationalQuery) error {
+	allowed, ok := grantObjectPrivileges[query.PermissionObject]
+	if !ok {
+		return nil
+	}
+	for _, privilege := range TranslatePermissions(query.Permissions) {
+		privilege = strings.ToUpper(privilege)
+		if privilege != "ALL PRIVILEGES" && !slices.Contains(allowed, privilege) {
+			return fmt.Errorf("%s cannot be granted on a %s (use %s or ALL)", privilege, strings.ToLower(query.PermissionObject), strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// buildGrantObject renders the ON target: a table, or DATABASE/SCHEMA/SEQUENCE/FUNCTION/ALL TABLES IN SCHEMA name
+func buildGrantObject(query *pb.RelationalQuery) (string, error) {
+	if query.PermissionObject == "" {
+		return QuoteIdentifier(query.Table), nil
+	}
+	if err := ValidateGrantPrivileges(query); err != nil {
+		return "", err
+	}
+	return query.PermissionObject + " " + QuoteIdentifier(query.Table), nil
 }
 
 func BuildCreateUserSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for CREATE USER")
 	}
-	sql := fmt.Sprintf("CREATE USER %s", query.UserName)
-	if query.Password != "" {
-		sql += fmt.Sprintf(" WITH PASSWORD '%s'", query.Password)
+	options, err := buildRoleOptions(query)
+	if err != nil {
+		return "", err
 	}
-	return sql, nil
+	return fmt.Sprintf("CREATE USER %s", QuoteIdentifier(query.UserName)) + options, nil
 }
 
 func BuildDropUserSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for DROP USER")
 	}
-	return fmt.Sprintf("DROP USER IF EXISTS %s", query.UserName), nil
+	return fmt.Sprintf("DROP USER IF EXISTS %s", QuoteIdentifier(query.UserName)), nil
 }
 
 func BuildAlterUserSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for ALTER USER")
 	}
-	sql := fmt.Sprintf("ALTER USER %s", query.UserName)
-	if query.Password != "" {
-		sql += fmt.Sprintf(" WITH PASSWORD '%s'", query.Password)
+	options, err := buildRoleOptions(query)
+	if err != nil {
+		return "", err
 	}
-	return sql, nil
+	return fmt.Sprintf("ALTER USER %s", QuoteIdentifier(query.UserName)) + options, nil
 }
 
 func BuildCreateRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.RoleName == "" {
 		return "", fmt.Errorf("no role name specified for CREATE ROLE")
 	}
-	return fmt.Sprintf("CREATE ROLE %s", query.RoleName), nil
+	options, err := buildRoleOptions(query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("CREATE ROLE %s", QuoteIdentifier(query.RoleName)) + options, nil
+}
+
+func BuildAlterRoleSQL(query *pb.RelationalQuery) (string, error) {
+	if query.RoleName == "" {
+		return "", fmt.Errorf("no role name specified for ALTER ROLE")
+	}
+	options, err := buildRoleOptions(query)
+	if err != nil {
+		return "", err
+	}
+	if options == "" {
+		return "", fmt.Errorf("no options specified for ALTER ROLE")
+	}
+	return fmt.Sprintf("ALTER ROLE %s", QuoteIdentifier(query.RoleName)) + options, nil
+}
+
+// roleFlags are the role attributes PostgreSQL accepts as bare keywords
+var roleFlags = map[string]bool{
+	"LOGIN": true, "NOLOGIN": true, "SUPERUSER": true, "NOSUPERUSER": true,
+	"CREATEDB": true, "NOCREATEDB": true, "CREATEROLE": true, "NOCREATEROLE": true,
+	"INHERIT": true, "NOINHERIT": true, "REPLICATION": true, "NOREPLICATION": true,
+	"BYPASSRLS": true, "NOBYPASSRLS": true,
+}
+
+// buildRoleOptions renders " WITH LOGIN PASSWORD '...' CONNECTION LIMIT n VALID UNTIL '...'"
+func buildRoleOptions(query *pb.RelationalQuery) (string, error) {
+	var options []string
+	for _, flag := range query.RoleFlags {
+		if !roleFlags[strings.ToUpper(flag)] {
+			return "", fmt.Errorf("unknown role option %q", flag)
+		}
+		options = append(options, strings.ToUpper(flag))
+	}
+	if query.Password != "" {
+		options = append(options, "PASSWORD "+QuoteLiteral(query.Password))
+	}
+	if query.ConnectionLimit != "" {
+		limit, err := strconv.Atoi(query.ConnectionLimit)
+		if err != nil {
+			return "", fmt.Errorf("invalid CONNECTION LIMIT %q", query.ConnectionLimit)
+		}
+		options = append(options, fmt.Sprintf("CONNECTION LIMIT %d", limit))
+	}
+	if query.ValidUntil != "" {
+		options = append(options, "VALID UNTIL "+QuoteLiteral(query.ValidUntil))
+	}
+	if len(options) == 0 {
+		return "", nil
+	}
+	return " WITH " + strings.Join(options, " "), nil
 }
 
 func BuildDropRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.RoleName == "" {
 		return "", fmt.Errorf("no role name specified for DROP ROLE")
 	}
-	return fmt.Sprintf("DROP ROLE IF EXISTS %s", query.RoleName), nil
+	return fmt.Sprintf("DROP ROLE IF EXISTS %s", QuoteIdentifier(query.RoleName)), nil
 }
 
 func BuildAssignRoleSQL(query *pb.RelationalQuery) (string, error) {
@@ -611,7 +1160,7 @@ func BuildAssignRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for ASSIGN ROLE")
 	}
-	return fmt.Sprintf("GRANT %s TO %s", query.RoleName, query.UserName), nil
+	return fmt.Sprintf("GRANT %s TO %s", QuoteIdentifier(query.RoleName), QuoteIdentifier(query.UserName)), nil
 }
 
 func BuildRevokeRoleSQL(query *pb.RelationalQuery) (string, error) {
@@ -621,7 +1170,7 @@ func BuildRevokeRoleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.UserName == "" {
 		return "", fmt.Errorf("no username specified for REVOKE ROLE")
 	}
-	return fmt.Sprintf("REVOKE %s FROM %s", query.RoleName, query.UserName), nil
+	return fmt.Sprintf("REVOKE %s FROM %s", QuoteIdentifier(query.RoleName), QuoteIdentifier(query.UserName)), nil
 }
 
 func TranslatePermissions(permissions []string) []string {
@@ -659,20 +1208,20 @@ func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	if len(query.Columns) > 0 {
 		var colStrs []string
 		for _, col := range query.Columns {
-			colStrs = append(colStrs, col.Value)
+			colStrs = append(colStrs, quoteColumnRef(col.Value))
 		}
 		selectClause = strings.Join(colStrs, ", ")
 	}
 	
-	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, query.Table)
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
 	var args []interface{}
 	paramNum := 1
 
 	for _, join := range query.Joins {
 		joinType := strings.ToUpper(join.JoinType)
-		sql += fmt.Sprintf(" %s JOIN %s", joinType, join.Table)
+		sql += fmt.Sprintf(" %s JOIN %s", joinType, QuoteIdentifier(join.Table))
 	if joinType != "CROSS" {
-			sql += fmt.Sprintf(" ON %s.%s = %s.%s", query.Table, getJoinLeft(join), join.Table, getJoinRight(join))
+			sql += fmt.Sprintf(" ON %s.%s = %s.%s", QuoteIdentifier(query.Table), QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(join.Table), QuoteIdentifier(getJoinRight(join)))
 		}
 	}
 
@@ -686,17 +1235,75 @@ func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
 }
 
 func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	return buildAggregateSQL(query, 0)
+}
+
+// buildStringAggSQL builds: STRING_AGG(CAST(field AS TEXT), 'sep' ORDER BY ...)
+// The cast lets non-text columns be joined, as GROUP_CONCAT does on MySQL.
+// With DISTINCT, ordering by the aggregated field itself repeats the cast
+// since PostgreSQL requires ORDER BY to match the argument.
+func buildStringAggSQL(agg *pb.AggregateClause, distinct bool) string {
+	field := quoteColumnRef(getAggField(agg))
+	arg := fmt.Sprintf("CAST(%s AS TEXT)", field)
+	if distinct {
+		arg = "DISTINCT " + arg
+	}
+
+	sql := fmt.Sprintf("STRING_AGG(%s, %s", arg, QuoteLiteral(agg.Separator))
+	if len(agg.OrderBy) > 0 {
+		var orderParts []string
+		for _, ob := range agg.OrderBy {
+			orderField := getOrderByField(ob)
+			if distinct && orderField == field {
+				orderField = fmt.Sprintf("CAST(%s AS TEXT)", field)
+			}
+			orderParts = append(orderParts, fmt.Sprintf("%s %s", orderField, ob.Direction))
+		}
+		sql += " ORDER BY " + strings.Join(orderParts, ", ")
+	}
+	return sql + ")"
+}
+
+// buildGroupByList renders GROUP BY items; RANGE buckets group by their CASE
+func buildGroupByList(groupBy []*pb.Expression) string {
+	var parts []string
+	for _, gb := range groupBy {
+		if builderutil.IsRangeBucket(gb) {
+			parts = append(parts, builderutil.RangeBucketSQL(gb, quoteColumnRef(gb.FunctionArgs[0].Value)))
+			continue
+		}
+		parts = append(parts, quoteColumnRef(gb.Value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	var parts []string
+	for _, gb := range groupBy {
+		if builderutil.IsRangeBucket(gb) {
+			parts = append(parts, builderutil.RangeBucketSQL(gb, quoteColumnRef(gb.FunctionArgs[0].Value))+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(gb)))
+			continue
+		}
+		parts = append(parts, quoteColumnRef(gb.Value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildAggregateSQL numbers parameters from argOffset+1 (set operation branches)
+func buildAggregateSQL(query *pb.RelationalQuery, argOffset int) (string, []interface{}) {
 	aggFunc := strings.ToUpper(query.Aggregate.Function)
-	aggField := getAggField(query.Aggregate)
+	aggField := quoteColumnRef(getAggField(query.Aggregate))
 	
 	var args []interface{}
-	paramNum := 1
+	paramNum := argOffset + 1
 	
 	needsSubquery := (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0
 	
 	var innerSQL string
 	if needsSubquery {
-		innerSQL = fmt.Sprintf("SELECT * FROM %s", query.Table)
+		innerSQL = fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
 		if len(query.Conditions) > 0 {
 			whereClause, whereArgs := BuildWhereClause(query.Conditions, paramNum)
 			innerSQL += whereClause
@@ -723,24 +1330,18 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	if aggField == "" || aggField == "*" {
 		selectClause = "SELECT COUNT(*)"
 		if len(query.GroupBy) > 0 {
-			var groupByStrs []string
-			for _, gb := range query.GroupBy {
-				groupByStrs = append(groupByStrs, gb.Value)
-			}
-			selectClause += ", " + strings.Join(groupByStrs, ", ")
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
 		}
 	} else {
-		if query.Distinct {
+		if aggFunc == "STRING AGG" {
+			selectClause = "SELECT " + buildStringAggSQL(query.Aggregate, query.Distinct)
+		} else if query.Distinct {
 			selectClause = fmt.Sprintf("SELECT %s(DISTINCT %s)", aggFunc, aggField)
 		} else {
 			selectClause = fmt.Sprintf("SELECT %s(%s)", aggFunc, aggField)
 		}
 		if len(query.GroupBy) > 0 {
-			var groupByStrs []string
-			for _, gb := range query.GroupBy {
-				groupByStrs = append(groupByStrs, gb.Value)
-			}
-			selectClause += ", " + strings.Join(groupByStrs, ", ")
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
 		}
 	}
 	
@@ -748,7 +1349,7 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	if needsSubquery {
 		sql = fmt.Sprintf("%s FROM (%s) AS subquery", selectClause, innerSQL)
 	} else {
-		sql = fmt.Sprintf("%s FROM %s", selectClause, query.Table)
+		sql = fmt.Sprintf("%s FROM %s", selectClause, QuoteIdentifier(query.Table))
 		if len(query.Conditions) > 0 {
 			whereClause, whereArgs := BuildWhereClause(query.Conditions, paramNum)
 			sql += whereClause
@@ -758,11 +1359,7 @@ func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	}
 	
 	if len(query.GroupBy) > 0 {
-		var groupByStrs []string
-		for _, gb := range query.GroupBy {
-			groupByStrs = append(groupByStrs, gb.Value)
-		}
-		sql += " GROUP BY " + strings.Join(groupByStrs, ", ")
+		sql += " GROUP BY " + buildGroupByList(query.GroupBy)
 	}
 	
 	if len(query.Having) > 0 {
@@ -807,30 +1404,24 @@ func BuildWindowFunctionSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		var windowFunc string
 		switch funcName {
 		case "LAG", "LEAD":
-			field := wf.Alias
-			if field == "" {
-				field = "id"
-			}
-			offset := wf.Offset
-			if offset == 0 {
-				offset = 1
-			}
-			windowFunc = fmt.Sprintf("%s(%s, %d)", funcName, field, offset)
+			windowFunc = buildShiftSQL(funcName, wf)
 		case "NTILE":
 			buckets := wf.Buckets
 			if buckets == 0 {
 				buckets = 4
 			}
 			windowFunc = fmt.Sprintf("%s(%d)", funcName, buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			windowFunc = buildWindowAggregateSQL(funcName, wf)
 		default:
 			windowFunc = fmt.Sprintf("%s()", funcName)
 		}
 
-		overClause := " OVER ("
+		overClause := "OVER ("
 		if len(wf.PartitionBy) > 0 {
 			var partitionStrs []string
 			for _, pb := range wf.PartitionBy {
-				partitionStrs = append(partitionStrs, pb.Value)
+				partitionStrs = append(partitionStrs, quoteColumnRef(pb.Value))
 			}
 			overClause += "PARTITION BY " + strings.Join(partitionStrs, ", ")
 		}
@@ -845,20 +1436,26 @@ func BuildWindowFunctionSQL(query *pb.RelationalQuery) (string, []interface{}) {
 			}
 			overClause += strings.Join(orderParts, ", ")
 		}
+		if frame := buildWindowFrameSQL(wf); frame != "" {
+			if len(wf.PartitionBy) > 0 || len(wf.OrderBy) > 0 {
+				overClause += " "
+			}
+			overClause += frame
+		}
 		overClause += ")"
 
 		alias := wf.Alias
 		if alias == "" {
 			alias = strings.ToLower(funcName)
-		}
-		if funcName == "LAG" || funcName == "LEAD" {
-			alias = strings.ToLower(funcName) + "_result"
+			if funcName == "LAG" || funcName == "LEAD" {
+				alias += "_result"
+			}
 		}
 
-		selectParts = append(selectParts, fmt.Sprintf("%s %s AS %s", windowFunc, overClause, alias))
+		selectParts = append(selectParts, fmt.Sprintf("%s %s AS %s", windowFunc, overClause, QuoteIdentifier(alias)))
 	}
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), query.Table)
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), QuoteIdentifier(query.Table))
 	var args []interface{}
 	paramNum := 1
 
@@ -871,12 +1468,67 @@ func BuildWindowFunctionSQL(query *pb.RelationalQuery) (string, []interface{}) {
 	return sql, args
 }
 
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, windowDefaultSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM("amount"), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// buildWindowFrameSQL renders the window frame: ROWS BETWEEN 2 PRECEDING AND CURRENT ROW
+func buildWindowFrameSQL(wf *pb.WindowClause) string {
+	if wf.FrameUnit == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd)
+}
+
+// windowDefaultSQL renders the LAG / LEAD default as a literal
+func windowDefaultSQL(expr *pb.Expression) string {
+	if expr.Type == "STRING" {
+		return QuoteLiteral(expr.Value)
+	}
+	return formatLiteral(expr.Value)
+}
+
 func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}) {
 	if query.Cte == nil {
 		return "", nil
 	}
-	cteSQL, params := BuildSelectSQL(query.Cte.CteQuery)
-	return fmt.Sprintf("WITH %s AS (%s) SELECT * FROM %s", query.Cte.CteName, cteSQL, query.Cte.CteName), params
+	cteSQL, params := BuildQuerySQL(query.Cte.CteQuery, 0)
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	with := "WITH"
+	if query.Cte.Recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	// Main query parameters continue after the CTE's own
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", cteName)
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs = buildSelectSQL(query.Cte.MainQuery, len(params))
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("%s %s AS (%s) %s", with, cteName, cteSQL, mainSQL), params
 }
 
 func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}) {
@@ -897,17 +1549,17 @@ func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}) {
                 return "", nil
         }
         
-		subField := query.Subquery.FieldExpr.Value
+		subField := quoteColumnRef(query.Subquery.FieldExpr.Value)
         
         subquerySQL, subArgs := BuildSelectSQL(query.Subquery.Subquery)
         
-        sql := fmt.Sprintf("SELECT * FROM %s WHERE ", query.Table)
+        sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
         var args []interface{}
         
         if len(query.Conditions) > 0 {
                 whereParts := []string{}
                 for _, cond := range query.Conditions {
-                        whereParts = append(whereParts, fmt.Sprintf("%s %s $%d", cond.FieldExpr.Value, cond.Operator, len(args)+1))
+                        whereParts = append(whereParts, fmt.Sprintf("%s %s $%d", quoteColumnRef(cond.FieldExpr.Value), cond.Operator, len(args)+1))
                         args = append(args, cond.ValueExpr.Value)
                 }
                 sql += strings.Join(whereParts, " AND ") + " AND "
@@ -920,7 +1572,7 @@ func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}) {
 }
 
 func BuildLikeSQL(query *pb.RelationalQuery) (string, []interface{}) {
-	sql := fmt.Sprintf("SELECT * FROM %s", query.Table)
+	sql := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
 	var args []interface{}
 	paramNum := 1
 
@@ -950,7 +1602,7 @@ func BuildCaseSQL(query *pb.RelationalQuery) string {
 				if alias == "" {
 					alias = "case_result"
 				}
-				return fmt.Sprintf("SELECT *, %s AS %s FROM %s", caseSQL, alias, query.Table)
+				return fmt.Sprintf("SELECT *, %s AS %s FROM %s", caseSQL, QuoteIdentifier(alias), QuoteIdentifier(query.Table))
 			}
 		}
 	}
@@ -958,12 +1610,15 @@ func BuildCaseSQL(query *pb.RelationalQuery) string {
 }
 
 func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	return buildSetOperationSQL(query, 0)
+}
+
+func buildSetOperationSQL(query *pb.RelationalQuery, argOffset int) (string, []interface{}) {
 	if query.SetOperation == nil {
 		return "", []interface{}{}
 	}
 
 	var allArgs []interface{}
-	argOffset := 0
 
 	leftSQL, leftArgs := BuildQuerySQL(query.SetOperation.LeftQuery, argOffset)
 	allArgs = append(allArgs, leftArgs...)
@@ -977,25 +1632,9 @@ func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}) {
 		operationType = "UNION ALL"
 	}
 
-	return fmt.Sprintf("(%s) %s (%s)", leftSQL, operationType, rightSQL), allArgs
-}
-
-func BuildQuerySQL(query *pb.RelationalQuery, argOffset int) (string, []interface{}) {
-	if query.Aggregate != nil {
-		return BuildAggregateSQL(query)
-	}
-
-	sql := fmt.Sprintf("SELECT * FROM %s", query.Table)
-	var args []interface{}
-	paramNum := argOffset + 1
-
-	if len(query.Conditions) > 0 {
-		whereClause, whereArgs := BuildWhereClause(query.Conditions, paramNum)
-		sql += whereClause
-		args = append(args, whereArgs...)
-		paramNum += len(whereArgs)
-	}
+	sql := fmt.Sprintf("(%s) %s (%s)", leftSQL, operationType, rightSQL)
 
+	// Outer ORDER BY / LIMIT / OFFSET apply to the combined result
 	if len(query.OrderBy) > 0 {
 		sql += " ORDER BY "
 		orderParts := []string{}
@@ -1004,7 +1643,6 @@ func BuildQuerySQL(query *pb.RelationalQuery, argOffset int) (string, []interfac
 		}
 		sql += strings.Join(orderParts, ", ")
 	}
-
 	if query.Limit > 0 {
 		sql += fmt.Sprintf(" LIMIT %d", query.Limit)
 	}
@@ -1012,7 +1650,19 @@ func BuildQuerySQL(query *pb.RelationalQuery, argOffset int) (string, []interfac
 		sql += fmt.Sprintf(" OFFSET %d", query.Offset)
 	}
 
-	return sql, args
+	return sql, allArgs
+}
+
+// BuildQuerySQL builds one set operation branch, numbering parameters from argOffset+1
+// Each branch keeps its own ORDER BY/LIMIT; branches may themselves be set operations
+func BuildQuerySQL(query *pb.RelationalQuery, argOffset int) (string, []interface{}) {
+	if query.SetOperation != nil {
+		return buildSetOperationSQL(query, argOffset)
+	}
+	if query.Aggregate != nil {
+		return buildAggregateSQL(query, argOffset)
+	}
+	return buildSelectSQL(query, argOffset)
 }
 
 func BuildHavingClause(conditions []*pb.QueryCondition, startParamNum int) (string, []interface{}) {
@@ -1049,21 +1699,21 @@ func BuildSavepointSQL(query *pb.RelationalQuery) (string, error) {
 	if query.SavepointName == "" {
 		return "", fmt.Errorf("savepoint name is required")
 	}
-	return fmt.Sprintf("SAVEPOINT %s", query.SavepointName), nil
+	return fmt.Sprintf("SAVEPOINT %s", QuoteIdentifier(query.SavepointName)), nil
 }
 
 func BuildRollbackToSavepointSQL(query *pb.RelationalQuery) (string, error) {
 	if query.SavepointName == "" {
 		return "", fmt.Errorf("savepoint name is required")
 	}
-	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", query.SavepointName), nil
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", QuoteIdentifier(query.SavepointName)), nil
 }
 
 func BuildReleaseSavepointSQL(query *pb.RelationalQuery) (string, error) {
 	if query.SavepointName == "" {
 		return "", fmt.Errorf("savepoint name is required")
 	}
-	return fmt.Sprintf("RELEASE SAVEPOINT %s", query.SavepointName), nil
+	return fmt.Sprintf("RELEASE SAVEPOINT %s", QuoteIdentifier(query.SavepointName)), nil
 }
 
 func TranslateIsolationLevel(level string) string {
@@ -1080,4 +1730,33 @@ func TranslateIsolationLevel(level string) string {
 	default:
 		return "READ COMMITTED"
 	}
-}
\ No newline at end of file
+}
+// ============================================================================
+// PUBSUB OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildListenSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Channel == "" {
+		return "", fmt.Errorf("channel is required for LISTEN")
+	}
+	return fmt.Sprintf("LISTEN %s", QuoteIdentifier(query.Channel)), nil
+}
+
+func BuildUnlistenSQL(query *pb.RelationalQuery) (string, error) {
+	if query.Channel == "" {
+		return "", fmt.Errorf("channel is required for UNLISTEN")
+	}
+	return fmt.Sprintf("UNLISTEN %s", QuoteIdentifier(query.Channel)), nil
+}
+
+// BuildNotifySQL inlines the payload - NOTIFY does not accept bind parameters
+func BuildNotifySQL(query *pb.RelationalQuery) (string, error) {
+	if query.Channel == "" {
+		return "", fmt.Errorf("channel is required for NOTIFY")
+	}
+	sql := fmt.Sprintf("NOTIFY %s", QuoteIdentifier(query.Channel))
+	if query.Payload != "" {
+		sql += ", " + QuoteLiteral(query.Payload)
+	}
+	return sql, nil
+}
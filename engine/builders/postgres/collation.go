@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// COLLATION (ICU)
+// ============================================================================
+
+// COLLATE locale [STRENGTH n] is applied to string comparisons and ORDER BY
+// columns as an explicit COLLATE. Ordering uses the predefined ICU collation;
+// ignoring case or accents needs a nondeterministic collation, which PostgreSQL
+// cannot express inline, so those are looked up by name:
+//
+//	CREATE COLLATION en_ci (provider = icu, locale = 'en-u-ks-level2', deterministic = false);
+//	CREATE COLLATION en_ai_ci (provider = icu, locale = 'en-u-ks-level1', deterministic = false);
+
+// CollationName returns the collation for a locale and MongoDB strength
+// en = "en-x-icu"; en STRENGTH 2 = en_ci; en STRENGTH 1 = en_ai_ci; simple = "C"
+func CollationName(locale string, strength int) string {
+	if strings.EqualFold(locale, "simple") {
+		return "C"
+	}
+	switch strength {
+	case 1:
+		return strings.ToLower(strings.ReplaceAll(locale, "-", "_")) + "_ai_ci"
+	case 2:
+		return strings.ToLower(strings.ReplaceAll(locale, "-", "_")) + "_ci"
+	default:
+		return strings.ReplaceAll(locale, "_", "-") + "-x-icu"
+	}
+}
+
+// buildCollateSQL renders a COLLATE expression: "name" COLLATE "en-x-icu"
+// Collation names are case-sensitive, so they are always quoted.
+func buildCollateSQL(expr *pb.Expression) string {
+	return BuildExpressionSQL(expr.Left) + ` COLLATE "` + strings.ReplaceAll(expr.Value, `"`, `""`) + `"`
+}
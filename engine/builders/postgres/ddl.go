@@ -5,6 +5,8 @@ import (
 	"strings"
 	"strconv"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/omniql-engine/omniql/mapping"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 )
@@ -14,15 +16,27 @@ import (
 // ============================================================================
 
 func BuildCreateTableSQL(query *pb.RelationalQuery) string {
+	// CREATE TABLE entity AS GET ...
+	if query.ViewQuery != nil {
+		return fmt.Sprintf("CREATE TABLE %s AS %s", QuoteIdentifier(query.Table), buildViewQuerySQL(query.ViewQuery))
+	}
 	if len(query.Fields) == 0 {
 		return ""
 	}
 	var columns []string
 	for _, field := range query.Fields {
-		columnDef := buildColumnDefinition(field.NameExpr.Value, field.ValueExpr.Value, field.Constraints)
+		columnDef := buildColumnDefinition(field.NameExpr.Value, field.ValueExpr.Value, field.Constraints, field.GeneratedExpr)
 		columns = append(columns, columnDef)
 	}
-	return fmt.Sprintf("CREATE TABLE %s (%s)", query.Table, strings.Join(columns, ", "))
+	sql := fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdentifier(query.Table), strings.Join(columns, ", "))
+	if query.PartitionStrategy != "" {
+		var keys []string
+		for _, key := range query.PartitionKeys {
+			keys = append(keys, BuildExpressionSQL(key))
+		}
+		sql += fmt.Sprintf(" PARTITION BY %s (%s)", query.PartitionStrategy, strings.Join(keys, ", "))
+	}
+	return sql
 }
 
 func BuildAlterTableSQL(query *pb.RelationalQuery) (string, error) {
@@ -37,15 +51,15 @@ func BuildAlterTableSQL(query *pb.RelationalQuery) (string, error) {
 		}
 		colName := getFieldName(query.Fields[0])
 		colType := getFieldValue(query.Fields[0])
-		columnDef := buildColumnDefinition(colName, colType, query.Fields[0].Constraints)
-		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", query.Table, columnDef), nil
+		columnDef := buildColumnDefinition(colName, colType, query.Fields[0].Constraints, query.Fields[0].GeneratedExpr)
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", QuoteIdentifier(query.Table), columnDef), nil
 
 	case "DROP_COLUMN":
 		if len(query.Fields) == 0 {
 			return "", fmt.Errorf("no column specified for DROP_COLUMN")
 		}
 		colName := getFieldName(query.Fields[0])
-		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", query.Table, colName), nil
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", QuoteIdentifier(query.Table), QuoteIdentifier(colName)), nil
 
 	case "RENAME_COLUMN":
 		if len(query.Fields) == 0 {
@@ -53,10 +67,10 @@ func BuildAlterTableSQL(query *pb.RelationalQuery) (string, error) {
 		}
 		oldName := getFieldName(query.Fields[0])
 		newName := getFieldValue(query.Fields[0])
-		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", query.Table, oldName, newName), nil
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(oldName), QuoteIdentifier(newName)), nil
 
 	case "RENAME_TABLE":
-		return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", query.Table, query.NewName), nil
+		return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(query.NewName)), nil
 
 	default:
 		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
@@ -64,11 +78,11 @@ func BuildAlterTableSQL(query *pb.RelationalQuery) (string, error) {
 }
 
 func BuildDropTableSQL(query *pb.RelationalQuery) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s", query.Table)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", QuoteIdentifier(query.Table))
 }
 
 func BuildTruncateTableSQL(query *pb.RelationalQuery) string {
-	return fmt.Sprintf("TRUNCATE TABLE %s", query.Table)
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table))
 }
 
 func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
@@ -78,7 +92,7 @@ func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
 	if query.NewName == "" {
 		return "", fmt.Errorf("no new table name specified")
 	}
-	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", query.Table, query.NewName), nil
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(query.NewName)), nil
 }
 
 func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
@@ -90,37 +104,39 @@ func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
 	columnName := query.Fields[0].ValueExpr.Value
 
 	indexType := "INDEX"
-	if len(query.Fields[0].Constraints) > 0 {
-		for _, constraint := range query.Fields[0].Constraints {
-			if strings.ToUpper(constraint) == "UNIQUE" {
-				indexType = "UNIQUE INDEX"
-				break
-			}
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			indexType = "UNIQUE INDEX"
+		case "FULLTEXT":
+			return "", fmt.Errorf("FULLTEXT indexes are not supported by PostgreSQL (SEARCH uses to_tsvector)")
+		case "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by PostgreSQL (MongoDB only)", strings.ToUpper(constraint))
 		}
 	}
 
-	return fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, indexName, query.Table, columnName), nil
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, QuoteIdentifier(indexName), QuoteIdentifier(query.Table), quoteIdentifierList(columnName)), nil
 }
 
 func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
 	if len(query.Fields) == 0 {
 		return "", fmt.Errorf("no index name specified")
 	}
-	return fmt.Sprintf("DROP INDEX IF EXISTS %s", query.Fields[0].NameExpr.Value), nil
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s", QuoteIdentifier(query.Fields[0].NameExpr.Value)), nil
 }
 
 func BuildCreateDatabaseSQL(query *pb.RelationalQuery) (string, error) {
 	if query.DatabaseName == "" {
 		return "", fmt.Errorf("no database name specified")
 	}
-	return fmt.Sprintf("CREATE DATABASE %s", query.DatabaseName), nil
+	return fmt.Sprintf("CREATE DATABASE %s", QuoteIdentifier(query.DatabaseName)), nil
 }
 
 func BuildDropDatabaseSQL(query *pb.RelationalQuery) (string, error) {
 	if query.DatabaseName == "" {
 		return "", fmt.Errorf("no database name specified")
 	}
-	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", query.DatabaseName), nil
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", QuoteIdentifier(query.DatabaseName)), nil
 }
 
 // formatLiteral formats a value as SQL literal (for VIEW definitions)
@@ -136,6 +152,17 @@ func formatLiteral(v interface{}) string {
 	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
 }
 
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) string {
+	viewSQL, args := BuildSelectSQL(query)
+	for i, arg := range args {
+		placeholder := fmt.Sprintf("$%d", i+1)
+		viewSQL = strings.Replace(viewSQL, placeholder, formatLiteral(arg), 1)
+	}
+	return viewSQL
+}
+
 func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewName == "" {
 		return "", fmt.Errorf("no view name specified")
@@ -143,19 +170,15 @@ func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewQuery == nil {
 		return "", fmt.Errorf("no view query specified")
 	}
-	viewSQL, args := BuildSelectSQL(query.ViewQuery)
-	for i, arg := range args {
-		placeholder := fmt.Sprintf("$%d", i+1)
-		viewSQL = strings.Replace(viewSQL, placeholder, formatLiteral(arg), 1)
-	}
-	return fmt.Sprintf("CREATE VIEW %s AS %s", query.ViewName, viewSQL), nil
+	viewSQL := buildViewQuerySQL(query.ViewQuery)
+	return fmt.Sprintf("CREATE VIEW %s AS %s", QuoteIdentifier(query.ViewName), viewSQL), nil
 }
 
 func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewName == "" {
 		return "", fmt.Errorf("no view name specified")
 	}
-	return fmt.Sprintf("DROP VIEW IF EXISTS %s", query.ViewName), nil
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s", QuoteIdentifier(query.ViewName)), nil
 }
 
 func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
@@ -165,15 +188,17 @@ func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ViewQuery == nil {
 		return "", fmt.Errorf("no view query specified")
 	}
-	viewSQL, args := BuildSelectSQL(query.ViewQuery)
-	for i, arg := range args {
-		placeholder := fmt.Sprintf("$%d", i+1)
-		viewSQL = strings.Replace(viewSQL, placeholder, formatLiteral(arg), 1)
-	}
-	return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", query.ViewName, viewSQL), nil
+	viewSQL := buildViewQuerySQL(query.ViewQuery)
+	return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", QuoteIdentifier(query.ViewName), viewSQL), nil
 }
 
-func buildColumnDefinition(name, columnType string, constraints []string) string {
+func buildColumnDefinition(name, columnType string, constraints []string, generated *pb.Expression) string {
+	name = QuoteIdentifier(name)
+
+	// TYPE[] - map the element type, then wrap it
+	isArray := mapping.IsArrayType(columnType)
+	columnType = mapping.ArrayElementType(columnType)
+
 	baseType := columnType
 	params := ""
 
@@ -192,11 +217,18 @@ func buildColumnDefinition(name, columnType string, constraints []string) string
 	}
 
 	columnDef := fmt.Sprintf("%s %s%s", name, pgType, params)
+	if isArray {
+		columnDef = fmt.Sprintf("%s "+mapping.ArrayTypeMap["PostgreSQL"], name, pgType+params)
+	}
 
 	if strings.ToUpper(baseType) == "AUTO" {
 		return fmt.Sprintf("%s SERIAL PRIMARY KEY", name)
 	}
 
+	if generated != nil {
+		columnDef += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", BuildExpressionSQL(inlineStringLiterals(generated)))
+	}
+
 	for _, constraint := range constraints {
 		switch strings.ToUpper(constraint) {
 		case "UNIQUE":
@@ -211,6 +243,30 @@ func buildColumnDefinition(name, columnType string, constraints []string) string
 	return columnDef
 }
 
+// inlineStringLiterals returns a copy of expr with STRING values quoted in place
+// DDL cannot take bind parameters, so literals must be part of the SQL text
+func inlineStringLiterals(expr *pb.Expression) *pb.Expression {
+	expr = proto.Clone(expr).(*pb.Expression)
+	var walk func(e *pb.Expression)
+	walk = func(e *pb.Expression) {
+		if e == nil {
+			return
+		}
+		if e.Type == "STRING" {
+			e.Type = "LITERAL"
+			e.Value = QuoteLiteral(e.Value)
+			return
+		}
+		walk(e.Left)
+		walk(e.Right)
+		for _, arg := range e.FunctionArgs {
+			walk(arg)
+		}
+	}
+	walk(expr)
+	return expr
+}
+
 // ============================================================================
 // POSTGRESQL-SPECIFIC DDL OPERATIONS
 // ============================================================================
@@ -223,7 +279,7 @@ func BuildCreateSequenceSQL(query *pb.RelationalQuery) (string, error) {
 	if query.SequenceName == "" {
 		return "", fmt.Errorf("no sequence name specified")
 	}
-	sql := fmt.Sprintf("CREATE SEQUENCE %s", query.SequenceName)
+	sql := fmt.Sprintf("CREATE SEQUENCE %s", QuoteIdentifier(query.SequenceName))
 	if query.SequenceStart > 0 {
 		sql += fmt.Sprintf(" START WITH %d", query.SequenceStart)
 	}
@@ -249,7 +305,7 @@ func BuildAlterSequenceSQL(query *pb.RelationalQuery) (string, error) {
 	if query.SequenceName == "" {
 		return "", fmt.Errorf("no sequence name specified")
 	}
-	sql := fmt.Sprintf("ALTER SEQUENCE %s", query.SequenceName)
+	sql := fmt.Sprintf("ALTER SEQUENCE %s", QuoteIdentifier(query.SequenceName))
 	if query.SequenceRestart > 0 {
 		sql += fmt.Sprintf(" RESTART WITH %d", query.SequenceRestart)
 	}
@@ -267,7 +323,7 @@ func BuildDropSequenceSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP SEQUENCE IF EXISTS %s%s", query.SequenceName, cascade), nil
+	return fmt.Sprintf("DROP SEQUENCE IF EXISTS %s%s", QuoteIdentifier(query.SequenceName), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -278,9 +334,9 @@ func BuildCreateExtensionSQL(query *pb.RelationalQuery) (string, error) {
 	if query.ExtensionName == "" {
 		return "", fmt.Errorf("no extension name specified")
 	}
-	sql := fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", query.ExtensionName)
+	sql := fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", QuoteIdentifier(query.ExtensionName))
 	if query.SchemaName != "" {
-		sql += fmt.Sprintf(" SCHEMA %s", query.SchemaName)
+		sql += fmt.Sprintf(" SCHEMA %s", QuoteIdentifier(query.SchemaName))
 	}
 	return sql, nil
 }
@@ -293,7 +349,7 @@ func BuildDropExtensionSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP EXTENSION IF EXISTS %s%s", query.ExtensionName, cascade), nil
+	return fmt.Sprintf("DROP EXTENSION IF EXISTS %s%s", QuoteIdentifier(query.ExtensionName), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -304,9 +360,9 @@ func BuildCreateSchemaSQL(query *pb.RelationalQuery) (string, error) {
 	if query.SchemaName == "" {
 		return "", fmt.Errorf("no schema name specified")
 	}
-	sql := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", query.SchemaName)
+	sql := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", QuoteIdentifier(query.SchemaName))
 	if query.SchemaOwner != "" {
-		sql += fmt.Sprintf(" AUTHORIZATION %s", query.SchemaOwner)
+		sql += fmt.Sprintf(" AUTHORIZATION %s", QuoteIdentifier(query.SchemaOwner))
 	}
 	return sql, nil
 }
@@ -319,7 +375,7 @@ func BuildDropSchemaSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP SCHEMA IF EXISTS %s%s", query.SchemaName, cascade), nil
+	return fmt.Sprintf("DROP SCHEMA IF EXISTS %s%s", QuoteIdentifier(query.SchemaName), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -336,15 +392,15 @@ func BuildCreateTypeSQL(query *pb.RelationalQuery) (string, error) {
 		for _, v := range query.EnumValues {
 			quotedValues = append(quotedValues, fmt.Sprintf("'%s'", v))
 		}
-		return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s)", query.TypeName, strings.Join(quotedValues, ", ")), nil
+		return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s)", QuoteIdentifier(query.TypeName), strings.Join(quotedValues, ", ")), nil
 	}
 	// COMPOSITE type
 	if len(query.Fields) > 0 {
 		var columns []string
 		for _, field := range query.Fields {
-			columns = append(columns, fmt.Sprintf("%s %s", getFieldName(field), getFieldValue(field)))
+			columns = append(columns, fmt.Sprintf("%s %s", QuoteIdentifier(getFieldName(field)), getFieldValue(field)))
 		}
-		return fmt.Sprintf("CREATE TYPE %s AS (%s)", query.TypeName, strings.Join(columns, ", ")), nil
+		return fmt.Sprintf("CREATE TYPE %s AS (%s)", QuoteIdentifier(query.TypeName), strings.Join(columns, ", ")), nil
 	}
 	return "", fmt.Errorf("CREATE TYPE requires ENUM values or composite fields")
 }
@@ -354,10 +410,10 @@ func BuildAlterTypeSQL(query *pb.RelationalQuery) (string, error) {
 		return "", fmt.Errorf("no type name specified")
 	}
 	if query.AlterAction == "ADD_VALUE" && query.EnumValue != "" {
-		return fmt.Sprintf("ALTER TYPE %s ADD VALUE '%s'", query.TypeName, query.EnumValue), nil
+		return fmt.Sprintf("ALTER TYPE %s ADD VALUE '%s'", QuoteIdentifier(query.TypeName), query.EnumValue), nil
 	}
 	if query.AlterAction == "RENAME_VALUE" && query.EnumValue != "" && query.NewEnumValue != "" {
-		return fmt.Sprintf("ALTER TYPE %s RENAME VALUE '%s' TO '%s'", query.TypeName, query.EnumValue, query.NewEnumValue), nil
+		return fmt.Sprintf("ALTER TYPE %s RENAME VALUE '%s' TO '%s'", QuoteIdentifier(query.TypeName), query.EnumValue, query.NewEnumValue), nil
 	}
 	return "", fmt.Errorf("ALTER TYPE requires ADD_VALUE or RENAME_VALUE action")
 }
@@ -370,7 +426,7 @@ func BuildDropTypeSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP TYPE IF EXISTS %s%s", query.TypeName, cascade), nil
+	return fmt.Sprintf("DROP TYPE IF EXISTS %s%s", QuoteIdentifier(query.TypeName), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -384,7 +440,7 @@ func BuildCreateDomainSQL(query *pb.RelationalQuery) (string, error) {
 	if query.DomainType == "" {
 		return "", fmt.Errorf("no domain type specified")
 	}
-	sql := fmt.Sprintf("CREATE DOMAIN %s AS %s", query.DomainName, query.DomainType)
+	sql := fmt.Sprintf("CREATE DOMAIN %s AS %s", QuoteIdentifier(query.DomainName), query.DomainType)
 	if query.DomainDefault != "" {
 		sql += fmt.Sprintf(" DEFAULT %s", query.DomainDefault)
 	}
@@ -402,7 +458,7 @@ func BuildDropDomainSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP DOMAIN IF EXISTS %s%s", query.DomainName, cascade), nil
+	return fmt.Sprintf("DROP DOMAIN IF EXISTS %s%s", QuoteIdentifier(query.DomainName), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -438,7 +494,7 @@ func BuildCreateFunctionSQL(query *pb.RelationalQuery) (string, error) {
 	}
 
 	return fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(%s) RETURNS %s LANGUAGE %s AS $$%s$$",
-		query.FuncName, args, returnType, language, query.FuncBody), nil
+		QuoteIdentifier(query.FuncName), args, returnType, language, query.FuncBody), nil
 }
 
 func BuildAlterFunctionSQL(query *pb.RelationalQuery) (string, error) {
@@ -446,10 +502,10 @@ func BuildAlterFunctionSQL(query *pb.RelationalQuery) (string, error) {
 		return "", fmt.Errorf("no function name specified")
 	}
 	if query.FuncOwner != "" {
-		return fmt.Sprintf("ALTER FUNCTION %s OWNER TO %s", query.FuncName, query.FuncOwner), nil
+		return fmt.Sprintf("ALTER FUNCTION %s OWNER TO %s", QuoteIdentifier(query.FuncName), QuoteIdentifier(query.FuncOwner)), nil
 	}
 	if query.SchemaName != "" {
-		return fmt.Sprintf("ALTER FUNCTION %s SET SCHEMA %s", query.FuncName, query.SchemaName), nil
+		return fmt.Sprintf("ALTER FUNCTION %s SET SCHEMA %s", QuoteIdentifier(query.FuncName), QuoteIdentifier(query.SchemaName)), nil
 	}
 	return "", fmt.Errorf("ALTER FUNCTION requires OWNER or SET SCHEMA")
 }
@@ -462,7 +518,7 @@ func BuildDropFunctionSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP FUNCTION IF EXISTS %s%s", query.FuncName, cascade), nil
+	return fmt.Sprintf("DROP FUNCTION IF EXISTS %s%s", QuoteIdentifier(query.FuncName), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -496,7 +552,7 @@ func BuildCreateTriggerSQL(query *pb.RelationalQuery) (string, error) {
 	}
 
 	return fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH %s EXECUTE FUNCTION %s()",
-		query.TriggerName, timing, events, query.Table, forEach, query.FuncName), nil
+		QuoteIdentifier(query.TriggerName), timing, events, QuoteIdentifier(query.Table), forEach, QuoteIdentifier(query.FuncName)), nil
 }
 
 func BuildDropTriggerSQL(query *pb.RelationalQuery) (string, error) {
@@ -510,7 +566,7 @@ func BuildDropTriggerSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s%s", query.TriggerName, query.Table, cascade), nil
+	return fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s%s", QuoteIdentifier(query.TriggerName), QuoteIdentifier(query.Table), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -525,13 +581,13 @@ func BuildCreatePolicySQL(query *pb.RelationalQuery) (string, error) {
 		return "", fmt.Errorf("no table specified for policy")
 	}
 
-	sql := fmt.Sprintf("CREATE POLICY %s ON %s", query.PolicyName, query.Table)
+	sql := fmt.Sprintf("CREATE POLICY %s ON %s", QuoteIdentifier(query.PolicyName), QuoteIdentifier(query.Table))
 
 	if query.PolicyFor != "" {
 		sql += fmt.Sprintf(" FOR %s", query.PolicyFor)
 	}
 	if query.PolicyTo != "" {
-		sql += fmt.Sprintf(" TO %s", query.PolicyTo)
+		sql += fmt.Sprintf(" TO %s", QuoteIdentifier(query.PolicyTo))
 	}
 	if query.PolicyUsing != "" {
 		sql += fmt.Sprintf(" USING (%s)", query.PolicyUsing)
@@ -550,7 +606,7 @@ func BuildDropPolicySQL(query *pb.RelationalQuery) (string, error) {
 	if query.Table == "" {
 		return "", fmt.Errorf("no table specified for DROP POLICY")
 	}
-	return fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s", query.PolicyName, query.Table), nil
+	return fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s", QuoteIdentifier(query.PolicyName), QuoteIdentifier(query.Table)), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -574,7 +630,7 @@ func BuildCreateRuleSQL(query *pb.RelationalQuery) (string, error) {
 	}
 
 	return fmt.Sprintf("CREATE OR REPLACE RULE %s AS ON %s TO %s DO %s",
-		query.RuleName, query.RuleEvent, query.Table, action), nil
+		QuoteIdentifier(query.RuleName), query.RuleEvent, QuoteIdentifier(query.Table), action), nil
 }
 
 func BuildDropRuleSQL(query *pb.RelationalQuery) (string, error) {
@@ -588,7 +644,7 @@ func BuildDropRuleSQL(query *pb.RelationalQuery) (string, error) {
 	if query.Cascade {
 		cascade = " CASCADE"
 	}
-	return fmt.Sprintf("DROP RULE IF EXISTS %s ON %s%s", query.RuleName, query.Table, cascade), nil
+	return fmt.Sprintf("DROP RULE IF EXISTS %s ON %s%s", QuoteIdentifier(query.RuleName), QuoteIdentifier(query.Table), cascade), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -603,4 +659,80 @@ func BuildCommentOnSQL(query *pb.RelationalQuery) (string, error) {
 		return fmt.Sprintf("COMMENT ON %s IS NULL", query.CommentTarget), nil
 	}
 	return fmt.Sprintf("COMMENT ON %s IS '%s'", query.CommentTarget, strings.ReplaceAll(query.CommentText, "'", "''")), nil
-}
\ No newline at end of file
+}
+// ----------------------------------------------------------------------------
+// PARTITION OPERATIONS
+// ----------------------------------------------------------------------------
+
+func BuildCreatePartitionSQL(query *pb.RelationalQuery) (string, error) {
+	if query.PartitionName == "" {
+		return "", fmt.Errorf("no partition name specified")
+	}
+	if query.Table == "" {
+		return "", fmt.Errorf("no parent table specified for partition")
+	}
+
+	sql := fmt.Sprintf("CREATE TABLE %s PARTITION OF %s", QuoteIdentifier(query.PartitionName), QuoteIdentifier(query.Table))
+
+	switch {
+	case query.PartitionDefault:
+		return sql + " DEFAULT", nil
+	case len(query.PartitionFrom) > 0:
+		if len(query.PartitionTo) != len(query.PartitionFrom) {
+			return "", fmt.Errorf("range partition needs the same number of FROM and TO values")
+		}
+		return sql + fmt.Sprintf(" FOR VALUES FROM (%s) TO (%s)",
+			buildPartitionBound(query.PartitionFrom), buildPartitionBound(query.PartitionTo)), nil
+	case len(query.PartitionIn) > 0:
+		return sql + fmt.Sprintf(" FOR VALUES IN (%s)", buildPartitionBound(query.PartitionIn)), nil
+	case query.PartitionModulus > 0:
+		return sql + fmt.Sprintf(" FOR VALUES WITH (MODULUS %d, REMAINDER %d)", query.PartitionModulus, query.PartitionRemainder), nil
+	default:
+		return "", fmt.Errorf("no partition bounds specified")
+	}
+}
+
+// buildPartitionBound renders bound values inline (DDL takes no parameters)
+// MINVALUE/MAXVALUE parse as FIELD expressions and are emitted unquoted
+func buildPartitionBound(values []*pb.Expression) string {
+	var parts []string
+	for _, v := range values {
+		parts = append(parts, BuildExpressionSQL(inlineStringLiterals(v)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BuildShowTablesSQL lists the tables of the current schema (SHOW TABLES)
+func BuildShowTablesSQL() string {
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name"
+}
+
+// BuildShowColumnsSQL lists the columns of a table in order (SHOW COLUMNS)
+func BuildShowColumnsSQL(query *pb.RelationalQuery) (string, error) {
+	schema, table, err := introspectedTable(query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns WHERE table_schema = %s AND table_name = %s ORDER BY ordinal_position", schema, table), nil
+}
+
+// BuildShowIndexesSQL lists the indexes of a table with their definitions (SHOW INDEXES)
+func BuildShowIndexesSQL(query *pb.RelationalQuery) (string, error) {
+	schema, table, err := introspectedTable(query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("SELECT indexname, indexdef FROM pg_indexes WHERE schemaname = %s AND tablename = %s ORDER BY indexname", schema, table), nil
+}
+
+// introspectedTable returns the schema and table the catalogs are filtered
+// on, as literals: schema.table names its schema, a bare table the current one
+func introspectedTable(query *pb.RelationalQuery) (string, string, error) {
+	if query.Table == "" {
+		return "", "", fmt.Errorf("no table name specified")
+	}
+	if dot := strings.LastIndex(query.Table, "."); dot > 0 {
+		return QuoteLiteral(query.Table[:dot]), QuoteLiteral(query.Table[dot+1:]), nil
+	}
+	return "current_schema()", QuoteLiteral(query.Table), nil
+}
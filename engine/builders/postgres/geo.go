@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// GEOSPATIAL (PostGIS)
+// ============================================================================
+
+// Points and polygons are WGS 84 longitude/latitude (SRID 4326), as on
+// MongoDB. The column may be geometry or geography: it is cast to whichever
+// the function needs.
+
+// buildNearSQL renders NEAR as a distance check in meters
+// location NEAR POINT(-73.97, 40.77) DISTANCE 5000 =
+// ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)
+func buildNearSQL(field string, cond *pb.QueryCondition, paramNum int) (string, []interface{}, int) {
+	args := append(geoCoordinates(cond.ValueExpr), cond.Value2Expr.Value)
+	return fmt.Sprintf("ST_DWithin(%s::geography, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, $%d)",
+		field, paramNum, paramNum+1, paramNum+2), args, 3
+}
+
+// buildWithinSQL renders WITHIN as containment in a polygon bound as WKT
+// location WITHIN POLYGON(POINT(0, 0), ...) =
+// ST_Contains(ST_GeomFromText($1, 4326), location::geometry) with $1 = 'POLYGON((0 0, ...))'
+func buildWithinSQL(field string, cond *pb.QueryCondition, paramNum int) (string, []interface{}, int) {
+	var corners []string
+	for _, point := range cond.ValueExpr.FunctionArgs {
+		corners = append(corners, fmt.Sprintf("%v %v", geoCoordinates(point)...))
+	}
+	wkt := "POLYGON((" + strings.Join(corners, ", ") + "))"
+	return fmt.Sprintf("ST_Contains(ST_GeomFromText($%d, 4326), %s::geometry)", paramNum, field),
+		[]interface{}{wkt}, 1
+}
+
+// geoCoordinates returns the longitude and latitude of a POINT expression
+func geoCoordinates(point *pb.Expression) []interface{} {
+	var coordinates []interface{}
+	for _, arg := range point.FunctionArgs {
+		coordinates = append(coordinates, arg.Value)
+	}
+	return coordinates
+}
@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// plainIdentifier matches names that are safe to emit unquoted
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// reservedWords are PostgreSQL keywords that cannot be used as bare identifiers
+var reservedWords = map[string]bool{
+	"ALL": true, "ANALYSE": true, "ANALYZE": true, "AND": true, "ANY": true,
+	"ARRAY": true, "AS": true, "ASC": true, "ASYMMETRIC": true, "AUTHORIZATION": true,
+	"BINARY": true, "BOTH": true, "CASE": true, "CAST": true, "CHECK": true,
+	"COLLATE": true, "COLLATION": true, "COLUMN": true, "CONCURRENTLY": true, "CONSTRAINT": true,
+	"CREATE": true, "CROSS": true, "CURRENT_CATALOG": true, "CURRENT_DATE": true, "CURRENT_ROLE": true,
+	"CURRENT_SCHEMA": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "DEFAULT": true,
+	"DEFERRABLE": true, "DESC": true, "DISTINCT": true, "DO": true, "ELSE": true,
+	"END": true, "EXCEPT": true, "FALSE": true, "FETCH": true, "FOR": true,
+	"FOREIGN": true, "FREEZE": true, "FROM": true, "FULL": true, "GRANT": true,
+	"GROUP": true, "HAVING": true, "ILIKE": true, "IN": true, "INITIALLY": true,
+	"INNER": true, "INTERSECT": true, "INTO": true, "IS": true, "ISNULL": true,
+	"JOIN": true, "LATERAL": true, "LEADING": true, "LEFT": true, "LIKE": true,
+	"LIMIT": true, "LOCALTIME": true, "LOCALTIMESTAMP": true, "NATURAL": true, "NOT": true,
+	"NOTNULL": true, "NULL": true, "OFFSET": true, "ON": true, "ONLY": true,
+	"OR": true, "ORDER": true, "OUTER": true, "OVERLAPS": true, "PLACING": true,
+	"PRIMARY": true, "REFERENCES": true, "RETURNING": true, "RIGHT": true, "SELECT": true,
+	"SESSION_USER": true, "SIMILAR": true, "SOME": true, "SYMMETRIC": true, "SYSTEM_USER": true,
+	"TABLE": true, "TABLESAMPLE": true, "THEN": true, "TO": true, "TRAILING": true,
+	"TRUE": true, "UNION": true, "UNIQUE": true, "USER": true, "USING": true,
+	"VARIADIC": true, "VERBOSE": true, "WHEN": true, "WHERE": true, "WINDOW": true,
+	"WITH": true,
+}
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted
+var valueKeywords = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "CURRENT_USER": true,
+	"SESSION_USER": true, "NULL": true, "TRUE": true, "FALSE": true, "DEFAULT": true,
+}
+
+// QuoteIdentifier returns name safe for interpolation into SQL
+// Plain names (users, created_at) are left as-is so PostgreSQL case folding
+// still applies; anything else is double-quoted with embedded quotes doubled.
+// Qualified names (schema.table, table.column) are quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, `"`) {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	if plainIdentifier.MatchString(name) && !reservedWords[strings.ToUpper(name)] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteIdentifierList quotes a comma-separated list of names (index columns)
+func quoteIdentifierList(names string) string {
+	parts := strings.Split(names, ",")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteColumnRef quotes a FIELD expression value, leaving SQL value keywords alone
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return name
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteLiteral returns s as a PostgreSQL string literal
+// Used where bind parameters are not allowed (CREATE USER ... PASSWORD).
+// Strings containing backslashes use the E'' form so the result is correct
+// regardless of standard_conforming_strings.
+func QuoteLiteral(s string) string {
+	quoted := "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	if strings.Contains(s, `\`) {
+		return "E" + strings.ReplaceAll(quoted, `\`, `\\`)
+	}
+	return quoted
+}
+
+// ValidateIdentifier rejects names that cannot be a legitimate identifier
+func ValidateIdentifier(name string) error {
+	if strings.Contains(name, `"`) {
+		return fmt.Errorf("invalid identifier %q: embedded double quotes are not allowed", name)
+	}
+	if strings.ContainsRune(name, 0) {
+		return fmt.Errorf("invalid identifier %q: NUL bytes are not allowed", name)
+	}
+	return nil
+}
+
+// ValidateIdentifiers checks every identifier a builder will interpolate
+// Called by the translator before any Build*SQL function runs.
+func ValidateIdentifiers(query *pb.RelationalQuery) error {
+	if query == nil {
+		return nil
+	}
+
+	names := []string{
+		query.Table, query.NewName, query.ViewName, query.DatabaseName,
+		query.RoleName, query.UserName, query.PermissionTarget, query.SavepointName,
+		query.SequenceName, query.ExtensionName, query.SchemaName, query.SchemaOwner,
+		query.TypeName, query.DomainName, query.FuncName, query.FuncOwner,
+		query.TriggerName, query.PolicyName, query.PolicyTo, query.RuleName,
+		query.PartitionName, query.Channel,
+	}
+	if query.Upsert != nil {
+		names = append(names, query.Upsert.ConflictConstraint)
+	}
+	names = append(names, query.UserRoles...)
+
+	var exprs []*pb.Expression
+	exprs = append(exprs, query.Columns...)
+	exprs = append(exprs, query.GroupBy...)
+	exprs = append(exprs, query.Returning...)
+	exprs = append(exprs, query.PartitionKeys...)
+	for _, sc := range query.SelectColumns {
+		names = append(names, sc.Alias)
+		exprs = append(exprs, sc.ExpressionObj)
+	}
+	for _, f := range query.Fields {
+		exprs = append(exprs, f.NameExpr)
+	}
+	for _, row := range query.BulkData {
+		for _, f := range row.Fields {
+			exprs = append(exprs, f.NameExpr)
+		}
+	}
+	if query.Upsert != nil {
+		exprs = append(exprs, query.Upsert.ConflictFields...)
+		exprs = append(exprs, conditionFieldExprs(query.Upsert.ConflictWhere)...)
+		for _, f := range query.Upsert.UpdateFields {
+			exprs = append(exprs, f.NameExpr)
+		}
+	}
+	exprs = append(exprs, conditionFieldExprs(query.Conditions)...)
+	exprs = append(exprs, conditionFieldExprs(query.Having)...)
+	for _, ob := range query.OrderBy {
+		exprs = append(exprs, ob.FieldExpr)
+	}
+	for _, join := range query.Joins {
+		names = append(names, join.Table)
+		exprs = append(exprs, join.LeftExpr, join.RightExpr)
+	}
+	if query.Aggregate != nil {
+		exprs = append(exprs, query.Aggregate.FieldExpr)
+		for _, ob := range query.Aggregate.OrderBy {
+			exprs = append(exprs, ob.FieldExpr)
+		}
+	}
+	for _, wf := range query.WindowFunctions {
+		names = append(names, wf.Alias)
+		exprs = append(exprs, wf.FieldExpr)
+		exprs = append(exprs, wf.PartitionBy...)
+	}
+
+	for _, name := range names {
+		if err := ValidateIdentifier(name); err != nil {
+			return err
+		}
+	}
+	for _, expr := range exprs {
+		if err := validateExprIdentifiers(expr); err != nil {
+			return err
+		}
+	}
+
+	// Nested queries
+	if query.Cte != nil {
+		if err := ValidateIdentifier(query.Cte.CteName); err != nil {
+			return err
+		}
+		if err := ValidateIdentifiers(query.Cte.CteQuery); err != nil {
+			return err
+		}
+		if err := ValidateIdentifiers(query.Cte.MainQuery); err != nil {
+			return err
+		}
+	}
+	if query.Subquery != nil {
+		if err := validateExprIdentifiers(query.Subquery.FieldExpr); err != nil {
+			return err
+		}
+		if err := ValidateIdentifiers(query.Subquery.Subquery); err != nil {
+			return err
+		}
+	}
+	if query.SetOperation != nil {
+		if err := ValidateIdentifiers(query.SetOperation.LeftQuery); err != nil {
+			return err
+		}
+		if err := ValidateIdentifiers(query.SetOperation.RightQuery); err != nil {
+			return err
+		}
+	}
+	return ValidateIdentifiers(query.ViewQuery)
+}
+
+// validateExprIdentifiers walks an expression tree and validates FIELD names
+func validateExprIdentifiers(expr *pb.Expression) error {
+	if expr == nil {
+		return nil
+	}
+	if expr.Type == "FIELD" {
+		return ValidateIdentifier(expr.Value)
+	}
+	for _, child := range append([]*pb.Expression{expr.Left, expr.Right}, expr.FunctionArgs...) {
+		if err := validateExprIdentifiers(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conditionFieldExprs collects the left-hand side of every condition (recursive)
+func conditionFieldExprs(conditions []*pb.QueryCondition) []*pb.Expression {
+	var exprs []*pb.Expression
+	for _, cond := range conditions {
+		if cond == nil {
+			continue
+		}
+		if len(cond.Nested) > 0 {
+			exprs = append(exprs, conditionFieldExprs(cond.Nested)...)
+			continue
+		}
+		exprs = append(exprs, cond.FieldExpr)
+	}
+	return exprs
+}
@@ -0,0 +1,21 @@
+package postgres
+
+// Options are the per-client settings of the PostgreSQL builders, which
+// CockroachDB shares. The zero value is the default of both databases.
+type Options struct {
+	// CopyThreshold is the rows x columns count above which BULK INSERT
+	// switches from a multi-VALUES INSERT to COPY FROM STDIN, 65535 when zero.
+	// PostgreSQL rejects statements with more than 65535 bind parameters.
+	CopyThreshold int
+}
+
+// defaultCopyThreshold is the CopyThreshold of the zero Options
+const defaultCopyThreshold = 65535
+
+// copyThreshold returns CopyThreshold, or its default when unset
+func (o Options) copyThreshold() int {
+	if o.CopyThreshold == 0 {
+		return defaultCopyThreshold
+	}
+	return o.CopyThreshold
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// WATCH (trigger-based change notifications)
+// ============================================================================
+
+// PostgreSQL has no change streams, so Watch installs a row trigger that sends
+// every change to a per-table channel with pg_notify. The payload is
+// {"op": "INSERT" | "UPDATE" | "DELETE", "row": {...}}, with the new row, or
+// the old one for deletes. NOTIFY payloads are limited to 8000 bytes.
+
+// WatchChannel returns the channel a table's watch trigger notifies
+// users = oql_watch_users; billing.invoices = oql_watch_billing_invoices
+func WatchChannel(table string) string {
+	return "oql_watch_" + strings.ReplaceAll(table, ".", "_")
+}
+
+// BuildWatchTriggerSQL creates or replaces the trigger function and row trigger
+// that notify WatchChannel(table). Both are shared by every Watch on the table.
+// CREATE OR REPLACE TRIGGER needs PostgreSQL 14+.
+func BuildWatchTriggerSQL(table string) string {
+	channel := WatchChannel(table)
+	function := QuoteIdentifier(channel)
+	notify := "PERFORM pg_notify(%s, json_build_object('op', TG_OP, 'row', row_to_json(%s))::text);"
+	body := fmt.Sprintf("BEGIN IF TG_OP = 'DELETE' THEN "+notify+" RETURN OLD; END IF; "+notify+" RETURN NEW; END",
+		QuoteLiteral(channel), "OLD", QuoteLiteral(channel), "NEW")
+	return fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $oql$ %s $oql$ LANGUAGE plpgsql;\n"+
+		"CREATE OR REPLACE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION %s()",
+		function, body, QuoteIdentifier("oql_watch"), QuoteIdentifier(table), function)
+}
+
+// BuildWatchFilterSQL checks a notified row against the Watch conditions
+// SELECT 1 FROM json_populate_record(NULL::users, $1::json->'row') AS users WHERE ...
+// The notification payload is bound as $1, so condition parameters start at $2.
+func BuildWatchFilterSQL(table string, conditions []*pb.QueryCondition) (string, []interface{}) {
+	alias := table
+	if dot := strings.LastIndex(table, "."); dot >= 0 {
+		alias = table[dot+1:]
+	}
+	where, args := BuildWhereClause(conditions, 2)
+	return fmt.Sprintf("SELECT 1 FROM json_populate_record(NULL::%s, $1::json->'row') AS %s%s",
+		QuoteIdentifier(table), QuoteIdentifier(alias), where), args
+}
@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// SERVER-SIDE AGGREGATES (Lua)
+// ============================================================================
+
+// AggregateScript computes COUNT, SUM, MIN and MAX of a field over the records
+// in the intersection of index sets, so the hashes never leave the server.
+// KEYS are the sets; ARGV[1] is the field, empty for COUNT *. Keys of expired
+// records are skipped. It replies {count, sum, min, max}, the numbers as
+// strings since Redis would truncate Lua floats to integers.
+const AggregateScript = `
+local keys
+if #KEYS == 1 then
+  keys = redis.call('SMEMBERS', KEYS[1])
+else
+  keys = redis.call('SINTER', unpack(KEYS))
+end
+local field = ARGV[1]
+local count, sum, min, max = 0, 0, nil, nil
+for _, key in ipairs(keys) do
+  if field == '' then
+    count = count + redis.call('EXISTS', key)
+  else
+    local value = tonumber(redis.call('HGET', key, field))
+    if value then
+      count = count + 1
+      sum = sum + value
+      if min == nil or value < min then min = value end
+      if max == nil or value > max then max = value end
+    end
+  end
+end
+local function num(n)
+  if n == nil then return false end
+  return string.format('%.17g', n)
+end
+return {count, num(sum), num(min), num(max)}
+`
+
+// PlanAggregateSets returns the index sets whose intersection holds exactly
+// the records a WHERE clause matches, so AggregateScript can run over them:
+// every record without one, and the equality sets of a clause made only of
+// field = value joined by AND. It reports false for any other clause.
+func PlanAggregateSets(tenantID, entity string, conditions []*pb.QueryCondition, opts Options) ([]string, bool) {
+	prefix := IndexPrefix(tenantID, entity, opts)
+	if len(conditions) == 0 {
+		return []string{AllKey(prefix)}, true
+	}
+	var sets []string
+	for i, cond := range conditions {
+		if i > 0 && strings.ToUpper(cond.Logic) == "OR" {
+			return nil, false
+		}
+		if cond.FieldExpr == nil || cond.FieldExpr.Type != "FIELD" || cond.ValueExpr == nil ||
+			(cond.Operator != "=" && cond.Operator != "==") || strings.ToLower(cond.FieldExpr.Value) == "id" {
+			return nil, false
+		}
+		sets = append(sets, ValueKey(prefix, cond.FieldExpr.Value, cond.ValueExpr.Value))
+	}
+	return sets, true
+}
+
+// ScriptAggregate holds the totals AggregateScript replies with
+type ScriptAggregate struct {
+	Count int64
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// ParseAggregateReply reads the reply of AggregateScript
+func ParseAggregateReply(reply interface{}) (ScriptAggregate, error) {
+	values, ok := reply.([]interface{})
+	if !ok || len(values) == 0 {
+		return ScriptAggregate{}, fmt.Errorf("unexpected aggregate script reply %T", reply)
+	}
+	count, ok := values[0].(int64)
+	if !ok {
+		return ScriptAggregate{}, fmt.Errorf("unexpected aggregate script count %T", values[0])
+	}
+	result := ScriptAggregate{Count: count}
+	totals := []*float64{&result.Sum, &result.Min, &result.Max}
+	for i, total := range totals {
+		if i+1 >= len(values) {
+			break
+		}
+		// Lua false (no numeric values) comes back as nil
+		if s, ok := values[i+1].(string); ok {
+			*total, _ = strconv.ParseFloat(s, 64)
+		}
+	}
+	return result, nil
+}
+
+// Value returns the result of an aggregate function; 0 when no record held a number
+func (a ScriptAggregate) Value(function string) float64 {
+	switch strings.ToUpper(function) {
+	case "COUNT":
+		return float64(a.Count)
+	case "SUM":
+		return a.Sum
+	case "AVG":
+		if a.Count == 0 {
+			return 0
+		}
+		return a.Sum / float64(a.Count)
+	case "MIN":
+		return a.Min
+	case "MAX":
+		return a.Max
+	}
+	return 0
+}
@@ -11,8 +11,15 @@ package redis
 // the translator (oql/translator/redis.go) without requiring complex SQL or
 // aggregation pipeline construction.
 //
-// This package exists for consistency with the project structure but contains
-// no builder functions.
+// This package holds what the client needs to run them against hashes:
+//   - filters.go (WHERE evaluation on a fetched hash)
+//   - index.go (secondary index sets and the lookups that use them)
+//   - search.go (RediSearch FT.CREATE / FT.SEARCH arguments)
+//   - expire.go (EXPIRE / PEXPIRE for the TTL clause)
+//   - aggregate.go (the Lua script computing aggregates over index sets)
+//   - bulk.go (BULK INSERT batching and row decoding)
+//   - cluster.go (hash-tag key names and slot checks for Redis Cluster)
+//   - upsert.go (the Lua compare-and-set script behind UPSERT)
 //
 // For Redis command construction, see:
 //   - oql/translator/redis.go (command building)
@@ -0,0 +1,189 @@
+package redis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/builders/redis"
+	"github.com/omniql-engine/omniql/engine/parser"
+	"github.com/omniql-engine/omniql/engine/translator"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// keyValueQuery translates an OQL query to Redis for tenant t1
+func keyValueQuery(t *testing.T, input string) *pb.KeyValueQuery {
+	t.Helper()
+	query, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	result, err := translator.Translate(query, "Redis", "t1")
+	if err != nil {
+		t.Fatalf("Translate(%q): %v", input, err)
+	}
+	return result.GetKeyValue()
+}
+
+// WHERE clauses compile to RediSearch: numbers as NUMERIC ranges, strings as
+// TAGs, and each AND-ed run parenthesized when ORs join several
+func TestBuildSearchQuery(t *testing.T) {
+	tests := []struct {
+		where string
+		want  string
+		ok    bool
+	}{
+		{`age > 21`, `@age:[(21 +inf]`, true},
+		{`age <= 65`, `@age:[-inf 65]`, true},
+		{`age = 30`, `@age:[30 30]`, true},
+		{`status = "active"`, `@status:{active}`, true},
+		{`status != "banned"`, `-@status:{banned}`, true},
+		{`email = "a@b.com"`, `@email:{a\@b\.com}`, true},
+		{`age BETWEEN 18 AND 30`, `@age:[18 30]`, true},
+		{`status IN ("a", "b")`, `@status:{a | b}`, true},
+		{`age IN (1, 2)`, `(@age:[1 1] | @age:[2 2])`, true},
+		{`name LIKE "Al%"`, `@name:{Al*}`, true},
+		{`age > 21 AND status = "active"`, `@age:[(21 +inf] @status:{active}`, true},
+		{`age > 21 AND status = "a" OR role = "admin"`, `(@age:[(21 +inf] @status:{a}) | (@role:{admin})`, true},
+		{`name LIKE "%li%"`, ``, false},
+		{`id = 1`, ``, false},
+	}
+	for _, tt := range tests {
+		conditions := keyValueQuery(t, "GET User WHERE "+tt.where).Conditions
+		got, ok := redis.BuildSearchQuery(conditions)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("BuildSearchQuery(%s) = %q, %v; want %q, %v", tt.where, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// Conditions filter a record's hash the way WHERE filters a row
+func TestMatchesConditions(t *testing.T) {
+	hash := map[string]string{"name": "Alice", "age": "30", "status": "active"}
+	tests := []struct {
+		where string
+		want  bool
+	}{
+		{`age > 21`, true},
+		{`age > 30`, false},
+		{`age >= 30 AND status = "active"`, true},
+		{`status = "banned" OR name = "Alice"`, true},
+		{`status IN ("a", "active")`, true},
+		{`age BETWEEN 31 AND 40`, false},
+		{`name LIKE "Al%"`, true},
+		{`missing = "x"`, false},
+	}
+	for _, tt := range tests {
+		conditions := keyValueQuery(t, "GET User WHERE "+tt.where).Conditions
+		if got := redis.MatchesConditions(hash, conditions); got != tt.want {
+			t.Errorf("MatchesConditions(%s) = %v, want %v", tt.where, got, tt.want)
+		}
+	}
+}
+
+// Rows sort numerically when both values are numbers, and rows missing the
+// field sort last ascending and first descending, as in SQL
+func TestSortRows(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"age", []string{"b", "c", "a", "d"}},
+		{"age DESC", []string{"d", "a", "c", "b"}},
+		{"name", []string{"a", "b", "c", "d"}},
+	}
+	for _, tt := range tests {
+		rows := []map[string]string{
+			{"name": "a", "age": "100"},
+			{"name": "b", "age": "9"},
+			{"name": "c", "age": "20"},
+			{"name": "d"},
+		}
+		redis.SortRows(rows, keyValueQuery(t, "GET User ORDER BY "+tt.order).OrderBy)
+		var got []string
+		for _, row := range rows {
+			got = append(got, row["name"])
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SortRows(%s) = %v, want %v", tt.order, got, tt.want)
+		}
+	}
+}
+
+// AND-ed equalities, IN lists and comparisons each narrow the candidates
+// through their index; an OR or a condition without an index cannot
+func TestPlanIndexLookup(t *testing.T) {
+	const prefix = "tenant:t1:_idx:user"
+	tests := []struct {
+		where string
+		want  *redis.IndexLookup
+	}{
+		{`id = 7`, &redis.IndexLookup{Keys: []string{"tenant:t1:user:7"}}},
+		{`status = "active" AND role = "admin"`, &redis.IndexLookup{
+			Sets: []string{prefix + ":eq:status:active", prefix + ":eq:role:admin"},
+		}},
+		{`role IN ("a", "b")`, &redis.IndexLookup{
+			Unions: [][]string{{prefix + ":eq:role:a", prefix + ":eq:role:b"}},
+		}},
+		{`age > 21 AND age <= 65`, &redis.IndexLookup{Ranges: []redis.ScoreRange{
+			{Key: prefix + ":score:age", Min: "(21", Max: "+inf"},
+			{Key: prefix + ":score:age", Min: "-inf", Max: "65"},
+		}}},
+		{`age BETWEEN 18 AND 30`, &redis.IndexLookup{Ranges: []redis.ScoreRange{
+			{Key: prefix + ":score:age", Min: "18", Max: "30"},
+		}}},
+		{`status = "a" OR role = "b"`, nil},
+		{`name LIKE "Al%"`, nil},
+	}
+	for _, tt := range tests {
+		conditions := keyValueQuery(t, "GET User WHERE "+tt.where).Conditions
+		got, ok := redis.PlanIndexLookup("t1", "User", conditions, redis.Options{})
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PlanIndexLookup(%s) = %+v, %v; want %+v", tt.where, got, ok, tt.want)
+		}
+	}
+}
+
+// Keys hash to the CRC16 of their hash tag, so keys sharing a tag share a slot
+func TestKeySlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"123456789", 12739},
+		{"foo", 12182},
+		{"{user1000}.following", 3443},
+		{"{user1000}.followers", 3443},
+		{"foo{}{bar}", 8363},
+	}
+	for _, tt := range tests {
+		if got := redis.KeySlot(tt.key); got != tt.want {
+			t.Errorf("KeySlot(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+// A BULK INSERT row drops its id, which is already in the key, and an empty
+// row gets the placeholder field
+func TestBulkHash(t *testing.T) {
+	tests := []struct {
+		row  string
+		want map[string]string
+	}{
+		{`{"id":"1","name":"Alice"}`, map[string]string{"name": "Alice"}},
+		{`{"ID":"1"}`, map[string]string{"_placeholder": "empty"}},
+		{`{}`, map[string]string{"_placeholder": "empty"}},
+	}
+	for _, tt := range tests {
+		got, err := redis.BulkHash(tt.row)
+		if err != nil {
+			t.Errorf("BulkHash(%s): %v", tt.row, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BulkHash(%s) = %v, want %v", tt.row, got, tt.want)
+		}
+	}
+	if _, err := redis.BulkHash(`not json`); err == nil {
+		t.Error("BulkHash(not json): expected an error")
+	}
+}
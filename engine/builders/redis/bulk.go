@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// BULK INSERT
+// ============================================================================
+
+// BulkHash decodes the fields of a BULK INSERT row, which the translator
+// carries as a JSON object. The id is already the last segment of the key,
+// so it is not stored in the hash, and a row with no other field gets the
+// same placeholder as on CREATE, since Redis has no empty hashes.
+func BulkHash(value string) (map[string]string, error) {
+	var hash map[string]string
+	if err := json.Unmarshal([]byte(value), &hash); err != nil {
+		return nil, fmt.Errorf("invalid bulk row %s: %w", value, err)
+	}
+	for field := range hash {
+		if strings.ToLower(field) == "id" {
+			delete(hash, field)
+		}
+	}
+	if len(hash) == 0 {
+		hash = map[string]string{"_placeholder": "empty"}
+	}
+	return hash, nil
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// REDIS CLUSTER (hash tags)
+// ============================================================================
+
+// clusterSlots is the number of Redis Cluster hash slots
+const clusterSlots = 16384
+
+// EntityKeyPrefix is the prefix of every record key of an entity
+func EntityKeyPrefix(tenantID, entity string, opts Options) string {
+	if opts.HashTags {
+		return fmt.Sprintf("{tenant:%s:%s}:", tenantID, strings.ToLower(entity))
+	}
+	return fmt.Sprintf("tenant:%s:%s:", tenantID, strings.ToLower(entity))
+}
+
+// KeyPattern matches every record key of an entity
+func KeyPattern(tenantID, entity string, opts Options) string {
+	return EntityKeyPrefix(tenantID, entity, opts) + "*"
+}
+
+// TenantKeyPattern matches every key of a tenant, records and indexes alike
+func TenantKeyPattern(tenantID string, opts Options) string {
+	if opts.HashTags {
+		return fmt.Sprintf("{tenant:%s:*", tenantID)
+	}
+	return fmt.Sprintf("tenant:%s:*", tenantID)
+}
+
+// KeySlot returns the cluster slot of a key: CRC16 of its hash tag, or of the
+// whole key when it has none, modulo 16384
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % clusterSlots)
+}
+
+// crc16 is CRC-16/XMODEM, the checksum Redis Cluster hashes keys with
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// CheckSameSlot reports an error when keys used by one multi-key command,
+// transaction or script fall in different slots, which Redis Cluster would
+// reject with CROSSSLOT
+func CheckSameSlot(keys ...string) error {
+	for _, key := range keys[min(1, len(keys)):] {
+		if KeySlot(key) != KeySlot(keys[0]) {
+			return fmt.Errorf("keys %s and %s are in different Redis Cluster slots (CROSSSLOT): set HashTags in the Redis options", keys[0], key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package redis
+
+import "strconv"
+
+// ============================================================================
+// EXPIRY (TTL)
+// ============================================================================
+
+// ExpireArgs returns the command that expires a key after ttlMs milliseconds:
+// EXPIRE in whole seconds when it can, PEXPIRE otherwise
+// TTL 30s = EXPIRE key 30; TTL 1500ms = PEXPIRE key 1500
+func ExpireArgs(key string, ttlMs int64) []string {
+	if ttlMs%1000 == 0 {
+		return []string{"EXPIRE", key, strconv.FormatInt(ttlMs/1000, 10)}
+	}
+	return []string{"PEXPIRE", key, strconv.FormatInt(ttlMs, 10)}
+}
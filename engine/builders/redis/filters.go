@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
@@ -188,4 +189,38 @@ func compareNumeric(a, b string) int {
 		return 1
 	}
 	return 0
+}
+
+// SortRows orders hashes by ORDER BY, comparing numerically when both values
+// are numbers. As in SQL, missing fields sort last ascending and first descending.
+func SortRows(rows []map[string]string, orderBy []*pb.OrderByClause) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, ob := range orderBy {
+			if ob.FieldExpr == nil {
+				continue
+			}
+			a, aok := rows[i][ob.FieldExpr.Value]
+			b, bok := rows[j][ob.FieldExpr.Value]
+			desc := strings.ToUpper(ob.Direction) == "DESC"
+			var cmp int
+			switch {
+			case aok == bok && aok:
+				cmp = compareNumeric(a, b)
+			case aok == bok:
+				cmp = 0
+			case !aok:
+				cmp = 1
+			default:
+				cmp = -1
+			}
+			if cmp == 0 {
+				continue
+			}
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
 }
\ No newline at end of file
@@ -0,0 +1,269 @@
+package redis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// SECONDARY INDEXES
+// ============================================================================
+
+// Records are hashes at tenant:{tenant}:{entity}:{id}. With SecondaryIndexes
+// on in the Options, every write also keeps, under tenant:{tenant}:_idx:{entity}
+// (with HashTags, {tenant:{tenant}:{entity}}_idx):
+//
+//	all                  SET  of every record key
+//	eq:{field}:{value}   SET  of the record keys whose field holds value
+//	score:{field}        ZSET of the record keys whose field holds a number or
+//	                     an ISO date, scored by it (dates in Unix milliseconds)
+//
+// so WHERE can read candidate keys with SINTER, SUNION and ZRANGEBYSCORE, and
+// ORDER BY can walk a field's ZSET, instead of scanning the keyspace. The
+// index keys stay inside the tenant's ACL pattern but outside the entity's
+// record pattern.
+
+// RecordKey is the key of a record hash: tenant:{tenant}:{entity}:{id}
+func RecordKey(tenantID, entity, id string, opts Options) string {
+	return EntityKeyPrefix(tenantID, entity, opts) + id
+}
+
+// IndexPrefix is the prefix of an entity's index keys
+// With Options.HashTags it shares the records' hash tag but not their key pattern.
+func IndexPrefix(tenantID, entity string, opts Options) string {
+	if opts.HashTags {
+		return fmt.Sprintf("{tenant:%s:%s}_idx", tenantID, strings.ToLower(entity))
+	}
+	return fmt.Sprintf("tenant:%s:_idx:%s", tenantID, strings.ToLower(entity))
+}
+
+// AllKey is the set of every record key of an entity
+func AllKey(prefix string) string {
+	return prefix + ":all"
+}
+
+// ValueKey is the set of record keys whose field holds value
+func ValueKey(prefix, field, value string) string {
+	return prefix + ":eq:" + field + ":" + value
+}
+
+// ScoreKey is the sorted set of record keys scored by a field
+func ScoreKey(prefix, field string) string {
+	return prefix + ":score:" + field
+}
+
+// IndexChanges returns the commands that move a record's index entries from
+// its old fields to its new ones. A nil old is a new record and a nil new a
+// deleted one; unchanged fields produce no commands.
+func IndexChanges(prefix, key string, old, new map[string]string) [][]interface{} {
+	var commands [][]interface{}
+	if old == nil && new != nil {
+		commands = append(commands, []interface{}{"SADD", AllKey(prefix), key})
+	}
+	if new == nil && old != nil {
+		commands = append(commands, []interface{}{"SREM", AllKey(prefix), key})
+	}
+
+	for _, field := range sortedFields(old) {
+		value := old[field]
+		if current, ok := new[field]; ok && current == value {
+			continue
+		}
+		commands = append(commands, []interface{}{"SREM", ValueKey(prefix, field, value), key})
+		if _, scored := score(value); scored {
+			commands = append(commands, []interface{}{"ZREM", ScoreKey(prefix, field), key})
+		}
+	}
+	for _, field := range sortedFields(new) {
+		value := new[field]
+		if previous, ok := old[field]; ok && previous == value {
+			continue
+		}
+		commands = append(commands, []interface{}{"SADD", ValueKey(prefix, field, value), key})
+		if s, scored := score(value); scored {
+			commands = append(commands, []interface{}{"ZADD", ScoreKey(prefix, field), s, key})
+		}
+	}
+	return commands
+}
+
+func sortedFields(hash map[string]string) []string {
+	fields := make([]string, 0, len(hash))
+	for field := range hash {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// scoreDateLayouts are the ISO 8601 forms scored as dates; without a zone they are UTC
+var scoreDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// score parses a value kept in a field's sorted set: a number, or an ISO date
+// as Unix milliseconds
+func score(value string) (float64, bool) {
+	if s, err := strconv.ParseFloat(value, 64); err == nil {
+		return s, true
+	}
+	if len(value) >= 10 && value[4] == '-' {
+		for _, layout := range scoreDateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return float64(t.UnixMilli()), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// formatScore renders a score as a ZRANGEBYSCORE bound
+func formatScore(s float64) string {
+	return strconv.FormatFloat(s, 'f', -1, 64)
+}
+
+// ============================================================================
+// INDEX LOOKUPS
+// ============================================================================
+
+// IndexLookup reads the candidate keys of a WHERE clause from the indexes.
+// Candidates are the intersection of every list it names; they are still
+// checked against the whole WHERE, so a lookup may be looser than the clause.
+type IndexLookup struct {
+	Keys   []string     // id = x names its record key directly
+	Sets   []string     // equalities, read together with SINTER
+	Unions [][]string   // IN lists, one SUNION each
+	Ranges []ScoreRange // comparisons, one ZRANGEBYSCORE each
+}
+
+// ScoreRange is a ZRANGEBYSCORE over a field's sorted set; "(" marks an exclusive bound
+type ScoreRange struct {
+	Key string
+	Min string
+	Max string
+}
+
+// PlanIndexLookup builds the index lookup of a WHERE clause. Only clauses
+// joined by AND can use the indexes; each =, IN, BETWEEN and comparison with
+// a number or date narrows the candidates, and the other conditions are left to
+// MatchesConditions. It reports false when no condition can use an index.
+func PlanIndexLookup(tenantID, entity string, conditions []*pb.QueryCondition, opts Options) (*IndexLookup, bool) {
+	prefix := IndexPrefix(tenantID, entity, opts)
+	lookup := &IndexLookup{}
+	for i, cond := range conditions {
+		if i > 0 && strings.ToUpper(cond.Logic) == "OR" {
+			return nil, false
+		}
+		if cond.FieldExpr == nil || cond.FieldExpr.Type != "FIELD" {
+			continue
+		}
+		field := cond.FieldExpr.Value
+		isID := strings.ToLower(field) == "id"
+
+		switch strings.ToUpper(cond.Operator) {
+		case "=", "==":
+			if cond.ValueExpr == nil {
+				continue
+			}
+			if isID {
+				lookup.Keys = append(lookup.Keys, RecordKey(tenantID, entity, cond.ValueExpr.Value, opts))
+			} else {
+				lookup.Sets = append(lookup.Sets, ValueKey(prefix, field, cond.ValueExpr.Value))
+			}
+		case "IN":
+			var keys []string
+			for _, value := range cond.ValuesExpr {
+				if isID {
+					keys = append(keys, RecordKey(tenantID, entity, value.Value, opts))
+				} else {
+					keys = append(keys, ValueKey(prefix, field, value.Value))
+				}
+			}
+			if isID {
+				lookup.Keys = append(lookup.Keys, keys...)
+			} else if len(keys) > 0 {
+				lookup.Unions = append(lookup.Unions, keys)
+			}
+		case ">", ">=", "<", "<=", "BETWEEN":
+			if r, ok := scoreRange(ScoreKey(prefix, field), cond); ok && !isID {
+				lookup.Ranges = append(lookup.Ranges, r)
+			}
+		}
+	}
+	if len(lookup.Keys)+len(lookup.Sets)+len(lookup.Unions)+len(lookup.Ranges) == 0 {
+		return nil, false
+	}
+	return lookup, true
+}
+
+// scoreRange converts a comparison with a number or date to a ZRANGEBYSCORE range
+func scoreRange(key string, cond *pb.QueryCondition) (ScoreRange, bool) {
+	if cond.ValueExpr == nil {
+		return ScoreRange{}, false
+	}
+	s, ok := score(cond.ValueExpr.Value)
+	if !ok {
+		return ScoreRange{}, false
+	}
+	value := formatScore(s)
+	r := ScoreRange{Key: key, Min: "-inf", Max: "+inf"}
+	switch strings.ToUpper(cond.Operator) {
+	case ">":
+		r.Min = "(" + value
+	case ">=":
+		r.Min = value
+	case "<":
+		r.Max = "(" + value
+	case "<=":
+		r.Max = value
+	case "BETWEEN":
+		if cond.Value2Expr == nil {
+			return ScoreRange{}, false
+		}
+		s2, ok := score(cond.Value2Expr.Value)
+		if !ok {
+			return ScoreRange{}, false
+		}
+		r.Min, r.Max = value, formatScore(s2)
+	}
+	return r, true
+}
+
+// PlanSortedRange returns the sorted set an ORDER BY on a single field walks,
+// and whether it walks it from the highest score (DESC)
+func PlanSortedRange(tenantID, entity string, orderBy []*pb.OrderByClause, opts Options) (string, bool, bool) {
+	if len(orderBy) != 1 || orderBy[0].FieldExpr == nil || orderBy[0].FieldExpr.Type != "FIELD" {
+		return "", false, false
+	}
+	field := orderBy[0].FieldExpr.Value
+	if strings.ToLower(field) == "id" {
+		return "", false, false
+	}
+	reverse := strings.ToUpper(orderBy[0].Direction) == "DESC"
+	return ScoreKey(IndexPrefix(tenantID, entity, opts), field), reverse, true
+}
+
+// IntersectKeys returns the keys present in every list, in the order of the first
+func IntersectKeys(lists [][]string) []string {
+	if len(lists) == 0 {
+		return nil
+	}
+	result := lists[0]
+	for _, list := range lists[1:] {
+		present := make(map[string]bool, len(list))
+		for _, key := range list {
+			present[key] = true
+		}
+		var kept []string
+		for _, key := range result {
+			if present[key] {
+				kept = append(kept, key)
+			}
+		}
+		result = kept
+	}
+	return result
+}
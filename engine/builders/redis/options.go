@@ -0,0 +1,46 @@
+package redis
+
+// Options are the per-client settings of the Redis builders. The zero value
+// suits a single Redis server without modules: records are only read by key
+// or by scanning the entity's keys.
+type Options struct {
+	// HashTags formats keys for Redis Cluster. An entity's record keys become
+	// {tenant:{tenant}:{entity}}:{id} and its index keys
+	// {tenant:{tenant}:{entity}}_idx:..., so the part in braces, the hash tag,
+	// puts all of them in one slot: MULTI/EXEC, SINTER and the aggregate
+	// script then never span slots, and SCAN finds every record on one node.
+	HashTags bool
+
+	// SecondaryIndexes maintains the index sets on CREATE, UPDATE and DELETE
+	// and answers WHERE and ORDER BY clauses from them. Records written while
+	// it was off are not indexed, so turn it on for new data.
+	SecondaryIndexes bool
+
+	// RediSearch runs GET through a RediSearch index on the entity's hashes:
+	// CREATE TABLE creates the index with FT.CREATE, and GET compiles its
+	// WHERE, ORDER BY and LIMIT to FT.SEARCH. A GET the index cannot answer
+	// (IS NULL, string ranges, several ORDER BY fields) is still filtered by
+	// the client.
+	RediSearch bool
+
+	// BulkBatchSize is the most rows one pipeline of a BULK INSERT carries,
+	// 1000 when zero; a negative value sends every row in one pipeline. Each
+	// batch is one round trip (two with SecondaryIndexes, which read the rows'
+	// current fields first), so larger batches mean fewer round trips but
+	// bigger replies held in memory.
+	BulkBatchSize int
+}
+
+// defaultBulkBatchSize is the BulkBatchSize of the zero Options
+const defaultBulkBatchSize = 1000
+
+// BatchSize returns the rows per pipeline of a BULK INSERT of rows rows
+func (o Options) BatchSize(rows int) int {
+	switch {
+	case o.BulkBatchSize == 0:
+		return defaultBulkBatchSize
+	case o.BulkBatchSize < 0:
+		return rows
+	}
+	return o.BulkBatchSize
+}
@@ -0,0 +1,266 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// REDISEARCH (FT.CREATE / FT.SEARCH)
+// ============================================================================
+
+// searchMaxResults is the LIMIT of a GET without one: FT.SEARCH returns 10
+// documents unless told otherwise, and 10000 is the server's default cap
+const searchMaxResults = 10000
+
+// SearchIndexName names the RediSearch index over an entity's hashes
+func SearchIndexName(tenantID, entity string) string {
+	return fmt.Sprintf("tenant:%s:_ft:%s", tenantID, strings.ToLower(entity))
+}
+
+// SearchField is a column of a RediSearch schema: its name and OmniQL type
+type SearchField struct {
+	Name string
+	Type string
+}
+
+// searchFieldType maps an OmniQL column type to a RediSearch field type
+// Numbers are NUMERIC, TEXT is full-text, and every other value is a TAG
+// matched exactly.
+func searchFieldType(oqlType string) string {
+	switch strings.ToUpper(oqlType) {
+	case "TEXT":
+		return "TEXT"
+	case "INT", "INTEGER", "BIGINT", "SMALLINT", "DECIMAL", "NUMERIC", "FLOAT", "REAL", "DOUBLE", "AUTO", "BIGAUTO":
+		return "NUMERIC"
+	default:
+		return "TAG"
+	}
+}
+
+// BuildSearchCreateArgs returns the FT.CREATE arguments indexing the hashes
+// under prefix; every field is SORTABLE so GET can ORDER BY it. The id lives
+// in the key, not the hash, and is not indexed.
+func BuildSearchCreateArgs(prefix string, fields []SearchField) []string {
+	args := []string{"ON", "HASH", "PREFIX", "1", prefix, "SCHEMA"}
+	for _, field := range fields {
+		if strings.ToLower(field.Name) == "id" {
+			continue
+		}
+		args = append(args, field.Name, searchFieldType(field.Type), "SORTABLE")
+	}
+	return args
+}
+
+// BuildSearchArgs compiles a GET to FT.SEARCH arguments: the query, SORTBY
+// and LIMIT. It reports false when a condition or the ORDER BY cannot be
+// expressed in the query language.
+func BuildSearchArgs(conditions []*pb.QueryCondition, orderBy []*pb.OrderByClause, limit, offset int) ([]string, bool) {
+	query := "*"
+	if len(conditions) > 0 {
+		var ok bool
+		if query, ok = BuildSearchQuery(conditions); !ok {
+			return nil, false
+		}
+	}
+	args := []string{query}
+
+	if len(orderBy) > 1 {
+		return nil, false
+	}
+	for _, ob := range orderBy {
+		if ob.FieldExpr == nil || ob.FieldExpr.Type != "FIELD" {
+			return nil, false
+		}
+		direction := "ASC"
+		if strings.ToUpper(ob.Direction) == "DESC" {
+			direction = "DESC"
+		}
+		args = append(args, "SORTBY", ob.FieldExpr.Value, direction)
+	}
+
+	if limit <= 0 {
+		limit = searchMaxResults
+	}
+	return append(args, "LIMIT", strconv.Itoa(offset), strconv.Itoa(limit)), true
+}
+
+// BuildSearchQuery compiles a WHERE clause to the RediSearch query language.
+// RediSearch binds | tighter than the implicit AND, so each run of AND-ed
+// conditions is parenthesized before the runs are joined with |.
+func BuildSearchQuery(conditions []*pb.QueryCondition) (string, bool) {
+	var groups [][]string
+	for i, cond := range conditions {
+		term, ok := searchTerm(cond)
+		if !ok {
+			return "", false
+		}
+		if i == 0 || strings.ToUpper(cond.Logic) == "OR" {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], term)
+	}
+	if len(groups) == 1 {
+		return strings.Join(groups[0], " "), true
+	}
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		parts[i] = "(" + strings.Join(group, " ") + ")"
+	}
+	return strings.Join(parts, " | "), true
+}
+
+// searchTerm compiles one condition
+// age > 21 = @age:[(21 +inf]; status = "active" = @status:{active};
+// body SEARCH "redis" = @body:(redis)
+func searchTerm(cond *pb.QueryCondition) (string, bool) {
+	if cond.Operator == "GROUP" {
+		nested, ok := BuildSearchQuery(cond.Nested)
+		return "(" + nested + ")", ok
+	}
+	if cond.FieldExpr == nil || cond.FieldExpr.Type != "FIELD" || strings.ToLower(cond.FieldExpr.Value) == "id" {
+		return "", false
+	}
+	field := "@" + escapeSearch(cond.FieldExpr.Value)
+	operator := strings.ToUpper(cond.Operator)
+
+	switch operator {
+	case "IN", "NOT_IN":
+		if len(cond.ValuesExpr) == 0 {
+			return "", false
+		}
+		var term string
+		if isSearchNumber(cond.ValuesExpr[0]) {
+			ranges := make([]string, len(cond.ValuesExpr))
+			for i, value := range cond.ValuesExpr {
+				if !isSearchNumber(value) {
+					return "", false
+				}
+				ranges[i] = fmt.Sprintf("%s:[%s %s]", field, value.Value, value.Value)
+			}
+			term = "(" + strings.Join(ranges, " | ") + ")"
+		} else {
+			tags := make([]string, len(cond.ValuesExpr))
+			for i, value := range cond.ValuesExpr {
+				tags[i] = escapeSearch(value.Value)
+			}
+			term = fmt.Sprintf("%s:{%s}", field, strings.Join(tags, " | "))
+		}
+		if operator == "NOT_IN" {
+			term = "-" + term
+		}
+		return term, true
+	case "BETWEEN", "NOT_BETWEEN":
+		if !isSearchNumber(cond.ValueExpr) || !isSearchNumber(cond.Value2Expr) {
+			return "", false
+		}
+		term := fmt.Sprintf("%s:[%s %s]", field, cond.ValueExpr.Value, cond.Value2Expr.Value)
+		if operator == "NOT_BETWEEN" {
+			term = "-" + term
+		}
+		return term, true
+	case "SEARCH":
+		if cond.ValueExpr == nil {
+			return "", false
+		}
+		return fmt.Sprintf("%s:(%s)", field, cond.ValueExpr.Value), true
+	}
+
+	if cond.ValueExpr == nil {
+		return "", false
+	}
+	value := cond.ValueExpr.Value
+	if isSearchNumber(cond.ValueExpr) {
+		switch operator {
+		case "=", "==":
+			return fmt.Sprintf("%s:[%s %s]", field, value, value), true
+		case "!=", "<>":
+			return fmt.Sprintf("-%s:[%s %s]", field, value, value), true
+		case ">":
+			return fmt.Sprintf("%s:[(%s +inf]", field, value), true
+		case ">=":
+			return fmt.Sprintf("%s:[%s +inf]", field, value), true
+		case "<":
+			return fmt.Sprintf("%s:[-inf (%s]", field, value), true
+		case "<=":
+			return fmt.Sprintf("%s:[-inf %s]", field, value), true
+		}
+		return "", false
+	}
+	switch operator {
+	case "=", "==":
+		return fmt.Sprintf("%s:{%s}", field, escapeSearch(value)), true
+	case "!=", "<>":
+		return fmt.Sprintf("-%s:{%s}", field, escapeSearch(value)), true
+	case "LIKE", "ILIKE":
+		// Only prefixes: TAG matching has no infix or suffix wildcards
+		prefix := strings.TrimSuffix(value, "%")
+		if prefix == value || prefix == "" || strings.ContainsAny(prefix, "%_") {
+			return "", false
+		}
+		return fmt.Sprintf("%s:{%s*}", field, escapeSearch(prefix)), true
+	}
+	return "", false
+}
+
+// isSearchNumber reports whether a value is compared as a NUMERIC field
+func isSearchNumber(expr *pb.Expression) bool {
+	if expr == nil || expr.Type != "NUMBER" {
+		return false
+	}
+	_, err := strconv.ParseFloat(expr.Value, 64)
+	return err == nil
+}
+
+// escapeSearch backslash-escapes the punctuation and spaces that split
+// RediSearch tags and field names
+func escapeSearch(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SearchRows reads the documents of an FT.SEARCH reply: an array of the total
+// followed by key, [field, value, ...] pairs on RESP2, or a map whose results
+// hold extra_attributes on RESP3
+func SearchRows(reply interface{}) ([]map[string]string, error) {
+	switch v := reply.(type) {
+	case []interface{}:
+		var rows []map[string]string
+		for i := 2; i < len(v); i += 2 {
+			pairs, ok := v[i].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected FT.SEARCH document %T", v[i])
+			}
+			row := make(map[string]string, len(pairs)/2)
+			for j := 0; j+1 < len(pairs); j += 2 {
+				row[fmt.Sprint(pairs[j])] = fmt.Sprint(pairs[j+1])
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	case map[interface{}]interface{}:
+		results, _ := v["results"].([]interface{})
+		rows := make([]map[string]string, 0, len(results))
+		for _, result := range results {
+			doc, _ := result.(map[interface{}]interface{})
+			attributes, _ := doc["extra_attributes"].(map[interface{}]interface{})
+			row := make(map[string]string, len(attributes))
+			for field, value := range attributes {
+				row[fmt.Sprint(field)] = fmt.Sprint(value)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unexpected FT.SEARCH reply %T", reply)
+	}
+}
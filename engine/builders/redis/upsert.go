@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// UPSERT (Lua compare-and-set)
+// ============================================================================
+
+// UpsertScript inserts or updates the record at KEYS[1] in one step. ARGV[1]
+// is the TTL in milliseconds (0 for none), followed by three lists, each a
+// length and then field, value pairs: the conflict values, the inserted
+// fields and the fields updated on conflict. A record that exists must still
+// hold the conflict values, else nothing is written and it replies -1 so the
+// caller can look the record up again; otherwise it replies 1 for an insert
+// and 0 for an update.
+const UpsertScript = `
+local pos = 2
+local function take()
+  local n = tonumber(ARGV[pos])
+  local list = {}
+  for i = pos + 1, pos + n do list[#list + 1] = ARGV[i] end
+  pos = pos + n + 1
+  return list
+end
+local conflict, insert, update = take(), take(), take()
+local inserted = 0
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  for i = 1, #conflict, 2 do
+    if redis.call('HGET', KEYS[1], conflict[i]) ~= conflict[i + 1] then
+      return -1
+    end
+  end
+  if #update > 0 then redis.call('HSET', KEYS[1], unpack(update)) end
+else
+  redis.call('HSET', KEYS[1], unpack(insert))
+  inserted = 1
+end
+local ttl = tonumber(ARGV[1])
+if ttl > 0 then redis.call('PEXPIRE', KEYS[1], ttl) end
+return inserted
+`
+
+// UpsertScriptConflict is the reply of UpsertScript when the record no longer
+// holds the conflict values it was found by
+const UpsertScriptConflict = -1
+
+// UpsertFields splits an UPSERT into the values a record must hold to conflict
+// and the fields set when it does. args are the inserted field, value pairs.
+// A conflict on id is the record key itself and needs no values. UPDATE SET
+// values replace the inserted ones; with only conflict fields inserted,
+// nothing is updated.
+func UpsertFields(upsert *pb.UpsertClause, args []string) (map[string]string, map[string]string) {
+	inserted := make(map[string]string, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		inserted[args[i]] = args[i+1]
+	}
+
+	conflict := map[string]string{}
+	for _, field := range upsert.ConflictFields {
+		if strings.ToLower(field.Value) == "id" {
+			conflict = map[string]string{}
+			break
+		}
+		conflict[field.Value] = inserted[field.Value]
+	}
+
+	update := map[string]string{}
+	for _, field := range upsert.UpdateFields {
+		name := field.NameExpr.Value
+		if strings.ToLower(name) == "id" {
+			continue
+		}
+		if field.ValueExpr != nil {
+			update[name] = field.ValueExpr.Value
+		} else if value, ok := inserted[name]; ok {
+			update[name] = value
+		}
+	}
+	return conflict, update
+}
+
+// UpsertScriptArgs returns the ARGV of UpsertScript
+func UpsertScriptArgs(conflict map[string]string, args []string, update map[string]string, ttlMs int64) []interface{} {
+	argv := []interface{}{strconv.FormatInt(ttlMs, 10)}
+	argv = appendPairs(argv, hashPairs(conflict))
+	argv = appendPairs(argv, args)
+	return appendPairs(argv, hashPairs(update))
+}
+
+// appendPairs appends a list of UpsertScript: its length, then its items
+func appendPairs(argv []interface{}, pairs []string) []interface{} {
+	argv = append(argv, strconv.Itoa(len(pairs)))
+	for _, item := range pairs {
+		argv = append(argv, item)
+	}
+	return argv
+}
+
+// hashPairs flattens a hash into field, value pairs in field order
+func hashPairs(hash map[string]string) []string {
+	pairs := make([]string, 0, len(hash)*2)
+	for _, field := range sortedFields(hash) {
+		pairs = append(pairs, field, hash[field])
+	}
+	return pairs
+}
+
+// UpsertConflictConditions returns the WHERE clause that finds the record an
+// UPSERT conflicts with: every conflict field equal to its inserted value
+func UpsertConflictConditions(conflict map[string]string) []*pb.QueryCondition {
+	fields := sortedFields(conflict)
+	conditions := make([]*pb.QueryCondition, len(fields))
+	for i, field := range fields {
+		conditions[i] = &pb.QueryCondition{
+			FieldExpr: &pb.Expression{Type: "FIELD", Value: field},
+			Operator:  "=",
+			ValueExpr: &pb.Expression{Type: "STRING", Value: conflict[field]},
+			Logic:     "AND",
+		}
+	}
+	return conditions
+}
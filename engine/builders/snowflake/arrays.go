@@ -0,0 +1,130 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// ARRAY CONDITIONS (ARRAY_CONTAINS / FILTER over the elements)
+// ============================================================================
+
+// arrayElement names the element of an array in a FILTER lambda
+const arrayElement = "elem"
+
+// isFunctionExpr checks if expr is a call to the named function
+func isFunctionExpr(expr *pb.Expression, name string) bool {
+	return expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == name
+}
+
+// isLiteralExpr checks if expr is a plain value (parameterized, never interpolated)
+func isLiteralExpr(expr *pb.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	switch expr.Type {
+	case "STRING", "NUMBER", "BOOLEAN", "LITERAL":
+		return true
+	}
+	return false
+}
+
+// buildArrayLiteral builds: ARRAY_CONSTRUCT(?, ?, ...) (non-literal elements are inlined)
+func buildArrayLiteral(elements []*pb.Expression) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, e := range elements {
+		if isLiteralExpr(e) {
+			parts = append(parts, "?")
+			args = append(args, bindValue(e))
+		} else {
+			parts = append(parts, BuildExpressionSQL(e))
+		}
+	}
+	return "ARRAY_CONSTRUCT(" + strings.Join(parts, ", ") + ")", args
+}
+
+// buildArrayOperandSQL renders the array side of a condition: ARRAY('a', 'b')
+// as ARRAY_CONSTRUCT(?, ?), anything else (a column, a function) as it is
+func buildArrayOperandSQL(expr *pb.Expression) (string, []interface{}) {
+	if isFunctionExpr(expr, "ARRAY") {
+		return buildArrayLiteral(expr.FunctionArgs)
+	}
+	return buildValueSQL(expr)
+}
+
+// anyElement renders ARRAY_SIZE(FILTER(array, elem -> predicate)) > 0
+func anyElement(array, predicate string) string {
+	return fmt.Sprintf("ARRAY_SIZE(FILTER(%s, %s -> %s)) > 0", array, arrayElement, predicate)
+}
+
+// noElement renders ARRAY_SIZE(FILTER(array, elem -> predicate)) = 0
+func noElement(array, predicate string) string {
+	return fmt.Sprintf("ARRAY_SIZE(FILTER(%s, %s -> %s)) = 0", array, arrayElement, predicate)
+}
+
+// buildContainmentCondition renders tags @> ARRAY('a', 'b') as "no element of
+// the list is missing from tags":
+// ARRAY_SIZE(ARRAY_EXCEPT(ARRAY_DISTINCT(ARRAY_CONSTRUCT(?, ?)), tags)) = 0
+// and <@ the other way round. ARRAY_EXCEPT removes one match per element, so
+// the list is made distinct first.
+func buildContainmentCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	valueSQL, args := buildArrayOperandSQL(cond.ValueExpr)
+	outer, inner := valueSQL, field
+	if cond.Operator == "<@" {
+		outer, inner = field, valueSQL
+	}
+	return fmt.Sprintf("ARRAY_SIZE(ARRAY_EXCEPT(ARRAY_DISTINCT(%s), %s)) = 0", outer, inner), args
+}
+
+// buildQuantifiedCondition builds value op ANY(array) and value op ALL(array)
+// 'admin' = ANY(roles) is ARRAY_CONTAINS(?::VARIANT, roles); other comparisons
+// test each element: age > ALL(1, 2) is
+// ARRAY_SIZE(FILTER(ARRAY_CONSTRUCT(?, ?), elem -> NOT (? > elem))) = 0.
+func buildQuantifiedCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	var leftArgs []interface{}
+	left := BuildExpressionSQL(cond.FieldExpr)
+	if isLiteralExpr(cond.FieldExpr) {
+		left = "?"
+		leftArgs = append(leftArgs, bindValue(cond.FieldExpr))
+	}
+
+	elements := cond.ValueExpr.FunctionArgs
+	var array string
+	var arrayArgs []interface{}
+	if len(elements) == 1 && !isLiteralExpr(elements[0]) {
+		array = BuildExpressionSQL(elements[0])
+	} else {
+		array, arrayArgs = buildArrayLiteral(elements)
+	}
+
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ANY" && cond.Operator == "=" {
+		return fmt.Sprintf("ARRAY_CONTAINS(%s::VARIANT, %s)", left, array), append(leftArgs, arrayArgs...)
+	}
+	// The array is read before the comparison; keep the arguments in the same order
+	args := append(arrayArgs, leftArgs...)
+	comparison := fmt.Sprintf("%s %s %s", left, snowflakeOperator(cond.Operator), arrayElement)
+	if strings.ToUpper(cond.ValueExpr.FunctionName) == "ALL" {
+		return noElement(array, "NOT ("+comparison+")"), args
+	}
+	return anyElement(array, comparison), args
+}
+
+// buildUnnestCondition builds UNNEST(tags) LIKE 'a%' as
+// ARRAY_SIZE(FILTER(tags, elem -> elem::STRING LIKE ?)) > 0
+// Elements are VARIANTs; the LIKE family reads them as strings.
+func buildUnnestCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	array := ""
+	if len(cond.FieldExpr.FunctionArgs) > 0 {
+		array = BuildExpressionSQL(cond.FieldExpr.FunctionArgs[0])
+	}
+	element := arrayElement
+	switch cond.Operator {
+	case "LIKE", "NOT_LIKE", "ILIKE", "NOT_ILIKE":
+		element += "::STRING"
+	}
+	innerSQL, args := buildComparison(element, cond)
+	return anyElement(array, innerSQL), args
+}
@@ -0,0 +1,1280 @@
+package snowflake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// Snowflake tables live in schemas inside databases: database.schema.table,
+// or schema.table and table in the session's current database and schema.
+// Unquoted names fold to upper case. The builders write ? placeholders, which
+// the Go driver binds in order. JSON and arrays are VARIANT values, read with
+// path brackets (data['address']['city']) and the ARRAY_* functions.
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getJoinLeft(join *pb.JoinClause) string {
+	if join == nil || join.LeftExpr == nil {
+		return ""
+	}
+	return join.LeftExpr.Value
+}
+
+func getJoinRight(join *pb.JoinClause) string {
+	if join == nil || join.RightExpr == nil {
+		return ""
+	}
+	return join.RightExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// buildValueSQL renders a value position: computed expressions inline, value
+// keywords bare, excluded.col as the MERGE source column, and anything else
+// as a ? bind
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" {
+		if valueKeywords[strings.ToUpper(expr.Value)] {
+			return strings.ToUpper(expr.Value), nil
+		}
+		if column, ok := excludedColumn(expr.Value); ok {
+			return mergeSource + "." + QuoteIdentifier(column), nil
+		}
+	}
+	return "?", []interface{}{bindValue(expr)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches and DDL take no parameters)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return formatLiteral(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// paginate appends LIMIT n [OFFSET m]; an offset without a limit is LIMIT NULL OFFSET m
+func paginate(sql string, limit, offset int32) string {
+	switch {
+	case limit > 0 && offset > 0:
+		return sql + fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	case limit > 0:
+		return sql + fmt.Sprintf(" LIMIT %d", limit)
+	case offset > 0:
+		return sql + fmt.Sprintf(" LIMIT NULL OFFSET %d", offset)
+	}
+	return sql
+}
+
+// ============================================================================
+// CRUD OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates parameterized SELECT query with expression support
+func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+
+	var args []interface{}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			var colSQL string
+			switch {
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "CASEWHEN":
+				caseSQL := "CASE"
+				for _, cond := range col.ExpressionObj.CaseConditions {
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", buildConditionSQL(cond.Condition), thenSQL)
+					args = append(args, thenArgs...)
+				}
+				if col.ExpressionObj.CaseElse != nil {
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
+				}
+				colSQL = caseSQL + " END"
+			case col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW":
+				colSQL = buildWindowExprSQL(col.ExpressionObj)
+			default:
+				colSQL = BuildExpressionSQL(col.ExpressionObj)
+			}
+			if col.Alias != "" {
+				colSQL += " AS " + QuoteIdentifier(col.Alias)
+			}
+			colParts = append(colParts, colSQL)
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
+	sql += buildTimeTravelClause(query)
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args, nil
+}
+
+// buildConditionSQL renders a condition with its values inline (CASE WHEN)
+func buildConditionSQL(cond *pb.QueryCondition) string {
+	if cond == nil {
+		return ""
+	}
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	}
+	return fmt.Sprintf("%s %s %s", field, snowflakeOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+}
+
+// buildWindowExprSQL renders a window function selected inline
+func buildWindowExprSQL(expr *pb.Expression) string {
+	funcName := strings.ReplaceAll(expr.FunctionName, " ", "_")
+
+	var funcCall string
+	switch funcName {
+	case "LAG", "LEAD":
+		field := "id"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				field = QuoteIdentifier(arg.Value)
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, field)
+	case "NTILE":
+		buckets := "4"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				buckets = arg.Value
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("NTILE(%s)", buckets)
+	default:
+		funcCall = fmt.Sprintf("%s()", funcName)
+	}
+
+	var partitionParts, orderParts []string
+	for _, arg := range expr.FunctionArgs {
+		if strings.HasPrefix(arg.Value, "PARTITION:") {
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
+		} else if strings.HasPrefix(arg.Value, "ORDER:") {
+			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
+			if len(parts) >= 2 {
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
+			} else if len(parts) == 1 {
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
+			}
+		}
+	}
+
+	var overParts []string
+	if len(partitionParts) > 0 {
+		overParts = append(overParts, "PARTITION BY "+strings.Join(partitionParts, ", "))
+	}
+	if len(orderParts) > 0 {
+		overParts = append(overParts, "ORDER BY "+strings.Join(orderParts, ", "))
+	}
+	return funcCall + " OVER (" + strings.Join(overParts, " ") + ")"
+}
+
+// BuildInsertSQL creates parameterized INSERT query
+func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query)
+	}
+	sql, args := buildInsertRowsSQL(query.Table, []*pb.BulkInsertRow{{Fields: query.Fields}})
+	return sql, args, nil
+}
+
+// buildInsertRowsSQL renders INSERT INTO t (a, b) VALUES (?, ?), (?, ?)
+// VALUES takes no semi-structured functions (ARRAY_CONSTRUCT, PARSE_JSON), so
+// rows with a computed value are selected instead:
+// INSERT INTO t (a, b) SELECT ?, ARRAY_CONSTRUCT(?, ?) UNION ALL SELECT ...
+func buildInsertRowsSQL(table string, rows []*pb.BulkInsertRow) (string, []interface{}) {
+	var fields []string
+	for _, field := range rows[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+	}
+
+	computed := false
+	var values []string
+	var args []interface{}
+	for _, row := range rows {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+			computed = computed || builderutil.IsComputed(field.ValueExpr)
+		}
+		values = append(values, strings.Join(placeholders, ", "))
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s)", QuoteIdentifier(table), strings.Join(fields, ", "))
+	if computed {
+		return sql + " SELECT " + strings.Join(values, " UNION ALL SELECT "), args
+	}
+	return sql + " VALUES (" + strings.Join(values, "), (") + ")", args
+}
+
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + sourceSQL, nil, nil
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildUpdateSQL creates parameterized UPDATE query
+func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var setParts []string
+	var args []interface{}
+
+	// JSON path assignments are grouped by column and rendered where the column first appears
+	jsonSets := map[string][]*pb.QueryField{}
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			jsonSets[column.Value] = append(jsonSets[column.Value], field)
+		}
+	}
+
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			group, pending := jsonSets[column.Value]
+			if !pending {
+				continue
+			}
+			delete(jsonSets, column.Value)
+			setSQL, setArgs, err := buildJSONSetSQL(group)
+			if err != nil {
+				return "", nil, err
+			}
+			setParts = append(setParts, setSQL)
+			args = append(args, setArgs...)
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	return sql + whereClause, append(args, whereArgs...), nil
+}
+
+// BuildDeleteSQL creates parameterized DELETE query
+func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql := fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// BuildBulkInsertSQL creates BULK INSERT as one multi-row INSERT
+func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+	sql, args := buildInsertRowsSQL(query.Table, query.BulkData)
+	return sql, args, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+func BuildWhereClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " WHERE " + clause, args
+}
+
+func buildConditionsRecursive(conditions []*pb.QueryCondition) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		var clause string
+		var clauseArgs []interface{}
+
+		if len(cond.Nested) > 0 {
+			nestedClause, nestedArgs := buildConditionsRecursive(cond.Nested)
+			clause = "(" + nestedClause + ")"
+			clauseArgs = nestedArgs
+		} else {
+			clause, clauseArgs = buildSingleCondition(cond)
+		}
+
+		if i > 0 {
+			logic := cond.Logic
+			if logic == "" {
+				logic = "AND"
+			}
+			parts = append(parts, logic)
+		}
+		parts = append(parts, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	return strings.Join(parts, " "), args
+}
+
+func buildSingleCondition(cond *pb.QueryCondition) (string, []interface{}) {
+	// Array conditions: UNNEST(tags) LIKE 'a%', 'admin' = ANY(roles)
+	if isFunctionExpr(cond.FieldExpr, "UNNEST") {
+		return buildUnnestCondition(cond)
+	}
+	if isFunctionExpr(cond.ValueExpr, "ANY") || isFunctionExpr(cond.ValueExpr, "ALL") {
+		return buildQuantifiedCondition(cond)
+	}
+	return buildComparison(BuildExpressionSQL(cond.FieldExpr), cond)
+}
+
+// buildComparison renders cond against field, already rendered as SQL
+func buildComparison(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	switch cond.Operator {
+	case "IS_NULL":
+		return fmt.Sprintf("%s IS NULL", field), nil
+	case "IS_NOT_NULL":
+		return fmt.Sprintf("%s IS NOT NULL", field), nil
+	case "IN":
+		return buildInClause(field, "IN", cond.ValuesExpr)
+	case "NOT_IN":
+		return buildInClause(field, "NOT IN", cond.ValuesExpr)
+	case "BETWEEN":
+		return buildBetweenClause(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "NOT_BETWEEN":
+		return buildBetweenClause(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "@>", "<@":
+		return buildContainmentCondition(field, cond)
+	case "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
+	default:
+		valueSQL, args := buildArrayOperandSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, snowflakeOperator(cond.Operator), valueSQL), args
+	}
+}
+
+// snowflakeOperator maps an OQL operator (NOT_LIKE, NOT_ILIKE) to its Snowflake spelling
+func snowflakeOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["Snowflake"][op]; ok {
+		return mapped
+	}
+	return op
+}
+
+// buildInClause renders IN / NOT IN; an empty list matches nothing (IN) or everything (NOT IN)
+func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}) {
+	if len(values) == 0 {
+		if operator == "IN" {
+			return "FALSE", nil
+		}
+		return "TRUE", nil
+	}
+
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args
+}
+
+func buildBetweenClause(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}) {
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args
+}
+
+// BuildExpressionSQL converts an Expression to SQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		// ARRAY(a, b) is the OQL spelling of an array literal
+		if strings.ToUpper(expr.FunctionName) == "ARRAY" {
+			return "ARRAY_CONSTRUCT(" + strings.Join(args, ", ") + ")"
+		}
+		return mapping.FunctionSQL("Snowflake", expr.FunctionName, args)
+	case "CASEWHEN":
+		caseParts := []string{"CASE"}
+		for _, cond := range expr.CaseConditions {
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", buildConditionSQL(cond.Condition), buildLiteralSQL(cond.ThenExpr)))
+		}
+		if expr.CaseElse != nil {
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
+		}
+		caseParts = append(caseParts, "END")
+		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		if column, ok := excludedColumn(expr.Value); ok {
+			return mergeSource + "." + QuoteIdentifier(column)
+		}
+		return quoteColumnRef(expr.Value)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
+	default:
+		return expr.Value
+	}
+}
+
+// bindValue converts a literal to the Go value bound to its parameter
+// Numbers bind as int64 / float64 and booleans as bool, so a value compared
+// with a VARIANT keeps its JSON type; everything else binds as a string.
+func bindValue(expr *pb.Expression) interface{} {
+	if expr == nil {
+		return nil
+	}
+	switch expr.Type {
+	case "NUMBER":
+		if n, err := strconv.ParseInt(expr.Value, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(expr.Value, 64); err == nil {
+			return f
+		}
+	case "BOOLEAN":
+		return strings.EqualFold(expr.Value, "true")
+	}
+	switch strings.ToLower(expr.Value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return expr.Value
+}
+
+// formatLiteral converts a value to SQL literal format for VIEW definitions
+func formatLiteral(v interface{}) string {
+	if v == nil {
+		return "NULL"
+	}
+	s := fmt.Sprintf("%v", v)
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return "TRUE"
+	case "FALSE":
+		return "FALSE"
+	}
+	return QuoteString(s)
+}
+
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	var b strings.Builder
+	inString := false
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(sql):
+			b.WriteByte(c)
+			i++
+			c = sql[i]
+		case c == '\'':
+			inString = !inString
+		case !inString && c == '?' && next < len(args):
+			b.WriteString(formatLiteral(args[next]))
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) (string, error) {
+	viewSQL, args, err := BuildSelectSQL(query)
+	if err != nil {
+		return "", err
+	}
+	return inlinePlaceholders(viewSQL, args), nil
+}
+
+// ============================================================================
+// DDL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCreateTableSQL creates a table; the AUTO and PRIMARY_KEY columns make
+// up a PRIMARY KEY (...), which Snowflake records but does not check.
+// CLUSTER BY (...) and DATA_RETENTION_TIME_IN_DAYS, the days of Time Travel
+// kept for the table, follow the column list.
+// CREATE TABLE name AS GET ... copies rows into a new table.
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.ViewQuery != nil {
+		sourceSQL, err := buildViewQuerySQL(query.ViewQuery)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("CREATE TABLE %s AS %s", QuoteIdentifier(query.Table), sourceSQL), nil
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	var columns, primaryKey []string
+	for _, field := range query.Fields {
+		column, err := TranslateColumn(getFieldName(field), getFieldValue(field), field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		columns = append(columns, column)
+		if isKeyColumn(getFieldValue(field), field.Constraints) {
+			primaryKey = append(primaryKey, QuoteIdentifier(getFieldName(field)))
+		}
+	}
+	if len(primaryKey) > 0 {
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKey, ", ")))
+	}
+	sql := fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdentifier(query.Table), strings.Join(columns, ", "))
+
+	if len(query.ClusterKeys) > 0 {
+		sql += " CLUSTER BY (" + buildExpressionList(query.ClusterKeys) + ")"
+	}
+	options, err := buildTableOptions(query.TableOptions)
+	if err != nil {
+		return "", err
+	}
+	return sql + options, nil
+}
+
+// isKeyColumn reports whether a column belongs to the primary key
+func isKeyColumn(columnType string, constraints []string) bool {
+	switch strings.ToUpper(columnType) {
+	case "AUTO", "BIGAUTO":
+		return true
+	}
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			return true
+		}
+	}
+	return false
+}
+
+// buildTableOptions renders DATA_RETENTION_TIME_IN_DAYS = 30 from the table options
+func buildTableOptions(options map[string]string) (string, error) {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		value := options[name]
+		switch name {
+		case "DATA_RETENTION_TIME_IN_DAYS":
+			if _, err := strconv.Atoi(value); err != nil {
+				return "", fmt.Errorf("invalid DATA_RETENTION_TIME_IN_DAYS value '%s': expected a whole number of days", value)
+			}
+		default:
+			return "", fmt.Errorf("Snowflake has no %s table option", name)
+		}
+		parts = append(parts, fmt.Sprintf(" %s = %s", name, value))
+	}
+	return strings.Join(parts, ""), nil
+}
+
+// BuildAlterTableSQL alters one column
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+	table := QuoteIdentifier(query.Table)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		column, err := TranslateColumn(columnName, columnValue, field.Constraints, field.GeneratedExpr, typeMap)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, column), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
+	case "MODIFY_COLUMN":
+		// Only widening changes are allowed: VARCHAR(50) to VARCHAR(100), NUMBER(10,2) to NUMBER(12,2)
+		if columnValue == "" {
+			return "", fmt.Errorf("MODIFY_COLUMN requires column type")
+		}
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DATA TYPE %s", table, QuoteIdentifier(columnName), snowflakeColumnType(columnValue, typeMap)), nil
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+// BuildDropTableSQL drops a table; CASCADE also drops the foreign keys of
+// other tables that reference it
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	sql := "DROP TABLE " + QuoteIdentifier(query.Table)
+	if query.Cascade {
+		sql += " CASCADE"
+	}
+	return sql, nil
+}
+
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("TRUNCATE TABLE %s", QuoteIdentifier(query.Table)), nil
+}
+
+// BuildRenameTableSQL renames a table; a qualified new name also moves it to
+// that schema
+func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.NewName == "" {
+		return "", fmt.Errorf("no new name specified for RENAME TABLE")
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(query.NewName)), nil
+}
+
+func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE VIEW", query)
+}
+
+// BuildAlterViewSQL replaces a view's query: CREATE OR REPLACE VIEW
+func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
+	return buildViewSQL("CREATE OR REPLACE VIEW", query)
+}
+
+func buildViewSQL(statement string, query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no view query specified")
+	}
+	viewSQL, err := buildViewQuerySQL(query.ViewQuery)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s AS %s", statement, QuoteIdentifier(query.ViewName), viewSQL), nil
+}
+
+func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified")
+	}
+	return fmt.Sprintf("DROP VIEW %s", QuoteIdentifier(query.ViewName)), nil
+}
+
+func BuildCreateDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified")
+	}
+	return fmt.Sprintf("CREATE DATABASE %s", QuoteIdentifier(query.DatabaseName)), nil
+}
+
+func BuildDropDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified")
+	}
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", QuoteIdentifier(query.DatabaseName)), nil
+}
+
+func BuildCreateSchemaSQL(query *pb.RelationalQuery) (string, error) {
+	if query.SchemaName == "" {
+		return "", fmt.Errorf("no schema name specified")
+	}
+	return fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", QuoteIdentifier(query.SchemaName)), nil
+}
+
+func BuildDropSchemaSQL(query *pb.RelationalQuery) (string, error) {
+	if query.SchemaName == "" {
+		return "", fmt.Errorf("no schema name specified")
+	}
+	sql := fmt.Sprintf("DROP SCHEMA IF EXISTS %s", QuoteIdentifier(query.SchemaName))
+	if query.Cascade {
+		sql += " CASCADE"
+	}
+	return sql, nil
+}
+
+// TranslateColumn renders a column definition from the Snowflake type map
+// A size is kept where the type takes one (VARCHAR(100), NUMBER(10,2)).
+// Snowflake enforces NOT NULL only: UNIQUE is recorded like the primary key,
+// and there are no generated columns.
+func TranslateColumn(columnName, columnType string, constraints []string, generated *pb.Expression, typeMap map[string]map[string]string) (string, error) {
+	notNull, unique := false, false
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			unique = true
+		case "NOT_NULL", "NOTNULL":
+			notNull = true
+		}
+	}
+	if generated != nil {
+		return "", fmt.Errorf("Snowflake has no generated columns: compute %s in a view", columnName)
+	}
+
+	columnDef := fmt.Sprintf("%s %s", QuoteIdentifier(columnName), snowflakeColumnType(columnType, typeMap))
+	if notNull {
+		columnDef += " NOT NULL"
+	}
+	if unique {
+		columnDef += " UNIQUE"
+	}
+	return columnDef, nil
+}
+
+// snowflakeColumnType maps a column type; arrays of any type are ARRAY, a
+// list of VARIANT values
+func snowflakeColumnType(oqlType string, typeMap map[string]map[string]string) string {
+	if mapping.IsArrayType(oqlType) {
+		return mapping.ArrayTypeMap["Snowflake"]
+	}
+	return nativeColumnType(oqlType, typeMap)
+}
+
+// nativeColumnType maps the base of a column type: STRING(100) -> VARCHAR(100),
+// DECIMAL(10,2) -> NUMBER(10,2), TIMESTAMP(6) -> TIMESTAMP_NTZ(6)
+func nativeColumnType(oqlType string, typeMap map[string]map[string]string) string {
+	baseType := oqlType
+	params := ""
+	if idx := strings.Index(oqlType, "("); idx != -1 {
+		baseType = oqlType[:idx]
+		if endIdx := strings.LastIndex(oqlType, ")"); endIdx > idx {
+			params = oqlType[idx : endIdx+1]
+		}
+	}
+
+	native, exists := typeMap["Snowflake"][strings.ToUpper(baseType)]
+	if !exists {
+		return oqlType // A native type: NUMBER(38,0), TIMESTAMP_TZ, OBJECT
+	}
+	switch native {
+	case "VARCHAR", "CHAR", "BINARY", "NUMBER", "TIMESTAMP_NTZ", "TIME":
+		return native + params
+	}
+	return native
+}
+
+// ============================================================================
+// DQL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectClause := "*"
+	if len(query.Columns) > 0 {
+		selectClause = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	for _, join := range query.Joins {
+		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		if joinType == "CROSS" {
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
+			continue
+		}
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
+		sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
+	}
+
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args
+}
+
+// buildStringAggSQL builds: LISTAGG(field, 'sep') WITHIN GROUP (ORDER BY ...)
+// With DISTINCT, Snowflake requires the ORDER BY to be the aggregated field.
+func buildStringAggSQL(agg *pb.AggregateClause, distinct bool) string {
+	field := quoteColumnRef(getAggField(agg))
+	if distinct {
+		field = "DISTINCT " + field
+	}
+
+	sql := fmt.Sprintf("LISTAGG(%s, %s)", field, QuoteString(agg.Separator))
+	if len(agg.OrderBy) > 0 {
+		sql += " WITHIN GROUP (ORDER BY " + buildOrderByList(agg.OrderBy) + ")"
+	}
+	return sql
+}
+
+func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	var selectClause string
+	var args []interface{}
+
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+
+		switch {
+		case aggField == "" || aggField == "*":
+			if query.Distinct {
+				selectClause = "COUNT(*)"
+			} else {
+				selectClause = fmt.Sprintf("%s(*)", aggFunc)
+			}
+		case aggFunc == "STRING AGG":
+			selectClause = buildStringAggSQL(query.Aggregate, query.Distinct)
+		case query.Distinct:
+			selectClause = fmt.Sprintf("%s(DISTINCT %s)", aggFunc, aggField)
+		default:
+			selectClause = fmt.Sprintf("%s(%s)", aggFunc, aggField)
+		}
+		if len(query.GroupBy) > 0 {
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
+		}
+	} else {
+		selectClause = "COUNT(*)"
+	}
+
+	// Paging an ungrouped aggregate pages the rows it reads
+	if (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0 {
+		innerSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
+		whereClause, whereArgs := BuildWhereClause(query.Conditions)
+		innerSQL += whereClause
+		args = append(args, whereArgs...)
+		if len(query.OrderBy) > 0 {
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		innerSQL = paginate(innerSQL, query.Limit, query.Offset)
+		return fmt.Sprintf("SELECT %s FROM (%s) AS subquery", selectClause, innerSQL), args, nil
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	whereClause, whereArgs := BuildWhereClause(query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+	if len(query.GroupBy) > 0 {
+		sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+	}
+	if len(query.Having) > 0 {
+		havingClause, havingArgs := BuildHavingClause(query.Having)
+		sql += havingClause
+		args = append(args, havingArgs...)
+	}
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), args, nil
+}
+
+// BuildWindowSQL creates window function queries
+func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectParts := []string{"*"}
+
+	for _, wf := range query.WindowFunctions {
+		windowFunc := strings.ReplaceAll(strings.ToUpper(wf.Function), " ", "_")
+
+		var funcSQL string
+		switch windowFunc {
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
+		case "NTILE":
+			buckets := wf.Buckets
+			if buckets <= 0 {
+				buckets = 4
+			}
+			funcSQL = fmt.Sprintf("NTILE(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
+		default:
+			funcSQL = fmt.Sprintf("%s()", windowFunc)
+		}
+
+		var overParts []string
+		if len(wf.PartitionBy) > 0 {
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
+		}
+		if len(wf.OrderBy) > 0 {
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		}
+		if wf.FrameUnit != "" {
+			overParts = append(overParts, fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd))
+		}
+
+		column := fmt.Sprintf("%s OVER (%s)", funcSQL, strings.Join(overParts, " "))
+		if wf.Alias != "" {
+			column += " AS " + QuoteIdentifier(wf.Alias)
+		}
+		selectParts = append(selectParts, column)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, buildLiteralSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM(amount), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// BuildSetOperationSQL joins two queries with a set operation
+// Each query is parenthesized and keeps its own ORDER BY / LIMIT; the outer
+// ORDER BY / LIMIT / OFFSET apply to the combined result.
+func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	setOp := query.SetOperation
+
+	leftSQL, leftArgs, err := buildSetOperandSQL(setOp.LeftQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	rightSQL, rightArgs, err := buildSetOperandSQL(setOp.RightQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql := fmt.Sprintf("(%s) %s (%s)", leftSQL, setOperator(setOp.OperationType), rightSQL)
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	return paginate(sql, query.Limit, query.Offset), append(leftArgs, rightArgs...), nil
+}
+
+// buildSetOperandSQL builds one query of a set operation, which may itself be
+// a set operation, a join or an aggregate
+func buildSetOperandSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	switch {
+	case query.SetOperation != nil:
+		return BuildSetOperationSQL(query)
+	case len(query.Joins) > 0:
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	case query.Aggregate != nil:
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// setOperator spells a set operation: UNION, UNION ALL, INTERSECT, EXCEPT
+func setOperator(operationType string) string {
+	switch strings.ToUpper(operationType) {
+	case "UNION_ALL", "UNION ALL":
+		return "UNION ALL"
+	case "INTERSECT":
+		return "INTERSECT"
+	case "EXCEPT":
+		return "EXCEPT"
+	default:
+		return "UNION"
+	}
+}
+
+func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Conditions)
+	return sql + whereClause, args
+}
+
+func BuildHavingClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(conditions)
+	return " HAVING " + clause, args
+}
+
+// ============================================================================
+// CTE OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCTESQL creates WITH [RECURSIVE] name AS (...) SELECT ...
+// A recursive CTE joins its anchor and recursive member with UNION ALL.
+func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Cte == nil {
+		return "", nil, fmt.Errorf("no CTE specified")
+	}
+	cteSQL, params, err := buildCTEBodySQL(query.Cte.CteQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	with := "WITH"
+	if query.Cte.Recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", cteName)
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs, err = BuildSelectSQL(query.Cte.MainQuery)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("%s %s AS (%s) %s", with, cteName, cteSQL, mainSQL), params, nil
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+func buildCTEBodySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query == nil {
+		return "", nil, fmt.Errorf("no CTE query specified")
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		leftSQL, leftArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		rightSQL, rightArgs, err := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery))
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s %s", leftSQL, setOperator(setOp.OperationType), rightSQL), append(leftArgs, rightArgs...), nil
+	}
+	if len(query.Joins) > 0 {
+		sql, args := BuildJoinSQL(query)
+		return sql, args, nil
+	}
+	if query.Aggregate != nil {
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// ============================================================================
+// SUBQUERY OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSubquerySQL creates an IN subquery, or an EXISTS check selected as 1 / 0
+func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Subquery == nil || query.Subquery.Subquery == nil {
+		return "", nil, fmt.Errorf("no subquery specified")
+	}
+
+	subquerySQL, subArgs, err := BuildSelectSQL(query.Subquery.Subquery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.ToUpper(query.Subquery.SubqueryType) == "EXISTS" {
+		return fmt.Sprintf("SELECT EXISTS(%s)", subquerySQL), subArgs, nil
+	}
+	if query.Table == "" {
+		return "", nil, fmt.Errorf("IN subquery requires an outer table")
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
+	var args []interface{}
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := buildConditionsRecursive(query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
+	}
+	sql += fmt.Sprintf("%s IN (%s)", BuildExpressionSQL(query.Subquery.FieldExpr), subquerySQL)
+	return sql, append(args, subArgs...), nil
+}
@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// plainIdentifier matches names that are safe to emit unquoted
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// reservedWords are Snowflake reserved words that cannot be used as bare identifiers
+var reservedWords = map[string]bool{
+	"ACCOUNT": true, "ALL": true, "ALTER": true, "AND": true, "ANY": true,
+	"AS": true, "BETWEEN": true, "BY": true, "CASE": true, "CAST": true,
+	"CHECK": true, "COLUMN": true, "CONNECT": true, "CONNECTION": true, "CONSTRAINT": true,
+	"CREATE": true, "CROSS": true, "CURRENT": true, "CURRENT_DATE": true, "CURRENT_TIME": true,
+	"CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "DATABASE": true, "DELETE": true, "DISTINCT": true,
+	"DROP": true, "ELSE": true, "EXISTS": true, "FALSE": true, "FOLLOWING": true,
+	"FOR": true, "FROM": true, "FULL": true, "GRANT": true, "GROUP": true,
+	"GSCLUSTER": true, "HAVING": true, "ILIKE": true, "IN": true, "INCREMENT": true,
+	"INNER": true, "INSERT": true, "INTERSECT": true, "INTO": true, "IS": true,
+	"ISSUE": true, "JOIN": true, "LATERAL": true, "LEFT": true, "LIKE": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "MINUS": true, "NATURAL": true, "NOT": true,
+	"NULL": true, "OF": true, "ON": true, "OR": true, "ORDER": true,
+	"ORGANIZATION": true, "QUALIFY": true, "REGEXP": true, "REVOKE": true, "RIGHT": true,
+	"RLIKE": true, "ROW": true, "ROWS": true, "SAMPLE": true, "SCHEMA": true,
+	"SELECT": true, "SET": true, "SOME": true, "START": true, "TABLE": true,
+	"TABLESAMPLE": true, "THEN": true, "TO": true, "TRIGGER": true, "TRUE": true,
+	"TRY_CAST": true, "UNION": true, "UNIQUE": true, "UPDATE": true, "USING": true,
+	"VALUES": true, "VIEW": true, "WHEN": true, "WHENEVER": true, "WHERE": true,
+	"WITH": true,
+}
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted
+var valueKeywords = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "NULL": true, "TRUE": true, "FALSE": true, "DEFAULT": true,
+}
+
+// QuoteIdentifier returns name safe for interpolation into SQL
+// Plain names (users, created_at) are left bare so Snowflake folds them to
+// upper case as usual. A reserved word is quoted in upper case ("ORDER"), the
+// name Snowflake would have folded it to, so it still matches bare references;
+// any other name is double-quoted as written. Qualified names
+// (database.schema.table, table.column) are quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, `"`) {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	if plainIdentifier.MatchString(name) {
+		if !reservedWords[strings.ToUpper(name)] {
+			return name
+		}
+		name = strings.ToUpper(name)
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteColumnRef quotes a FIELD expression value, leaving SQL value keywords alone
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return strings.ToUpper(name)
+	}
+	return QuoteIdentifier(name)
+}
+
+// QuoteString returns s as a single-quoted string literal; Snowflake reads
+// backslash escapes in strings, so backslashes are escaped too
+func QuoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+}
@@ -0,0 +1,94 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (VARIANT paths / OBJECT_INSERT)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and the bracket
+// path into the VARIANT: data->'address'->>'city' = data, ['address']['city'];
+// data->0 = data, [0]
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []*pb.Expression
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]*pb.Expression{expr.Right}, keys...)
+		expr = expr.Left
+	}
+	path := ""
+	for _, key := range keys {
+		path += jsonPathStep(key)
+	}
+	return expr, path
+}
+
+// jsonPathStep renders one path step: [n] for array indexes, ['key'] for members
+func jsonPathStep(key *pb.Expression) string {
+	if key == nil {
+		return ""
+	}
+	if key.Type == "NUMBER" {
+		return "[" + key.Value + "]"
+	}
+	return "[" + QuoteString(key.Value) + "]"
+}
+
+// buildJSONPathSQL renders -> as the VARIANT at the path and ->> as its
+// value cast to STRING (a JSON null reads as NULL)
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, path := jsonPathTarget(expr)
+	sql := BuildExpressionSQL(column) + path
+	if expr.Operator == "->>" {
+		sql += "::STRING"
+	}
+	return sql
+}
+
+// buildJSONCondition renders the JSONB key operators ?, ?| and ?&: a missing
+// key reads as SQL NULL, a JSON null as a VARIANT null, which is not
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	if cond.Operator == "?" {
+		return fmt.Sprintf("%s%s IS NOT NULL", field, jsonPathStep(cond.ValueExpr)), nil
+	}
+	// ?| = any key, ?& = all keys
+	if len(cond.ValuesExpr) == 0 {
+		if cond.Operator == "?|" {
+			return "FALSE", nil
+		}
+		return "TRUE", nil
+	}
+	logic := " OR "
+	if cond.Operator == "?&" {
+		logic = " AND "
+	}
+	parts := make([]string, len(cond.ValuesExpr))
+	for i, key := range cond.ValuesExpr {
+		parts[i] = fmt.Sprintf("%s%s IS NOT NULL", field, jsonPathStep(key))
+	}
+	return "(" + strings.Join(parts, logic) + ")", nil
+}
+
+// buildJSONSetSQL renders the JSON key assignments to one column as a single
+// col = OBJECT_INSERT(OBJECT_INSERT(col, 'a', v1, TRUE), 'b', v2, TRUE);
+// OBJECT_INSERT replaces a top-level key, so nested paths are rejected
+func buildJSONSetSQL(fields []*pb.QueryField) (string, []interface{}, error) {
+	column, _ := jsonPathTarget(fields[0].NameExpr)
+	columnSQL := BuildExpressionSQL(column)
+	sql := columnSQL
+	var args []interface{}
+	for _, field := range fields {
+		key := field.NameExpr.Right
+		if field.NameExpr.Left.Type == "JSON_PATH" || key == nil || key.Type == "NUMBER" {
+			return "", nil, fmt.Errorf("Snowflake can only set a top-level key of a VARIANT: set %s to the whole document", column.GetValue())
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		sql = fmt.Sprintf("OBJECT_INSERT(%s, %s, %s, TRUE)", sql, QuoteString(key.Value), valueSQL)
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("%s = %s", columnSQL, sql), args, nil
+}
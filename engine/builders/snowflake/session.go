@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// SESSION SETTINGS (USE WAREHOUSE / ALTER SESSION)
+// ============================================================================
+
+// parameterName matches a session parameter name: QUERY_TAG, TIMEZONE
+var parameterName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// BuildSessionSQL creates the statements that prepare a connection: USE
+// WAREHOUSE to pick the compute that runs its queries, then one ALTER
+// SESSION SET per parameter, in name order. Numbers and TRUE / FALSE are
+// written as they are, other values as strings.
+func BuildSessionSQL(warehouse string, params map[string]string) ([]string, error) {
+	var statements []string
+	if warehouse != "" {
+		statements = append(statements, "USE WAREHOUSE "+QuoteIdentifier(warehouse))
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if !parameterName.MatchString(name) {
+			return nil, fmt.Errorf("invalid session parameter name '%s'", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		statements = append(statements, fmt.Sprintf("ALTER SESSION SET %s = %s", strings.ToUpper(name), sessionValue(params[name])))
+	}
+	return statements, nil
+}
+
+// sessionValue writes a parameter value: 60, TRUE, 'etl-nightly'
+func sessionValue(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	switch strings.ToUpper(value) {
+	case "TRUE", "FALSE":
+		return strings.ToUpper(value)
+	}
+	return QuoteString(value)
+}
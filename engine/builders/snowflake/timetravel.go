@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// TIME TRAVEL (AT / BEFORE)
+// ============================================================================
+
+// queryID matches a Snowflake query ID: 01b2c3d4-0000-5e6f-0000-000000000001
+var queryID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// buildTimeTravelClause renders AS OF as AT(...) and BEFORE as BEFORE(...),
+// which follow the table name: FROM orders AT(OFFSET => -60)
+func buildTimeTravelClause(query *pb.RelationalQuery) string {
+	switch {
+	case query.AsOf != "":
+		return " AT(" + travelPoint(query.AsOf) + ")"
+	case query.Before != "":
+		return " BEFORE(" + travelPoint(query.Before) + ")"
+	}
+	return ""
+}
+
+// travelPoint names the point in time: a relative interval ("-10m") as an
+// OFFSET in seconds, a query ID as the STATEMENT it ran, anything else as a
+// TIMESTAMP in the session time zone
+func travelPoint(value string) string {
+	if d, err := time.ParseDuration(value); err == nil {
+		return "OFFSET => " + strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+	}
+	if queryID.MatchString(value) {
+		return "STATEMENT => " + QuoteString(value)
+	}
+	return "TIMESTAMP => " + QuoteString(value) + "::TIMESTAMP_LTZ"
+}
+
+// CheckTimeTravel rejects a point Snowflake cannot travel to: an interval
+// that is not in the past
+func CheckTimeTravel(value string) error {
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return fmt.Errorf("time travel interval %q is not in the past: use a negative interval such as \"-10m\"", value)
+	}
+	return nil
+}
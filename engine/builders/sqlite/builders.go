@@ -0,0 +1,1568 @@
+package sqlite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// NIL-SAFE HELPERS (TrueAST)
+// ============================================================================
+
+func getCondValue(cond *pb.QueryCondition) string {
+	if cond == nil || cond.ValueExpr == nil {
+		return ""
+	}
+	return cond.ValueExpr.Value
+}
+
+func getFieldName(field *pb.QueryField) string {
+	if field == nil || field.NameExpr == nil {
+		return ""
+	}
+	return field.NameExpr.Value
+}
+
+func getFieldValue(field *pb.QueryField) string {
+	if field == nil || field.ValueExpr == nil {
+		return ""
+	}
+	return field.ValueExpr.Value
+}
+
+func getJoinLeft(join *pb.JoinClause) string {
+	if join == nil || join.LeftExpr == nil {
+		return ""
+	}
+	return join.LeftExpr.Value
+}
+
+func getJoinRight(join *pb.JoinClause) string {
+	if join == nil || join.RightExpr == nil {
+		return ""
+	}
+	return join.RightExpr.Value
+}
+
+func getAggField(agg *pb.AggregateClause) string {
+	if agg == nil || agg.FieldExpr == nil {
+		return ""
+	}
+	return agg.FieldExpr.Value
+}
+
+// buildValueSQL renders a value position: computed expressions inline, value
+// keywords bare, excluded.col (the row an upsert proposed) as a column
+// reference, and anything else as a ? bind
+func buildValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr != nil && expr.Type == "FIELD" {
+		if valueKeywords[strings.ToUpper(expr.Value)] {
+			return expr.Value, nil
+		}
+		if _, ok := excludedColumn(expr.Value); ok {
+			return quoteColumnRef(expr.Value), nil
+		}
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{ConvertSQLiteValue(value)}
+}
+
+// buildLiteralSQL renders a value inline (CASE branches cannot always take ? placeholders)
+func buildLiteralSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return "NULL"
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr)
+	}
+	switch expr.Type {
+	case "NUMBER":
+		return expr.Value
+	case "BOOLEAN":
+		return formatLiteral(expr.Value)
+	}
+	if strings.ToUpper(expr.Value) == "NULL" {
+		return "NULL"
+	}
+	return QuoteString(expr.Value)
+}
+
+// buildExpressionList renders columns, GROUP BY and PARTITION BY expressions
+func buildExpressionList(exprs []*pb.Expression) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildGroupByColumns renders the GROUP BY items selected next to an aggregate
+// RANGE buckets are named <field>_range.
+func buildGroupByColumns(groupBy []*pb.Expression) string {
+	parts := make([]string, 0, len(groupBy))
+	for _, expr := range groupBy {
+		if builderutil.IsRangeBucket(expr) {
+			parts = append(parts, BuildExpressionSQL(expr)+" AS "+QuoteIdentifier(builderutil.RangeBucketAlias(expr)))
+			continue
+		}
+		parts = append(parts, BuildExpressionSQL(expr))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildOrderByList renders ORDER BY items
+func buildOrderByList(orderBy []*pb.OrderByClause) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		parts = append(parts, fmt.Sprintf("%s %s", BuildExpressionSQL(ob.FieldExpr), ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildRankedOrderByList renders ORDER BY items, with RELEVANCE as the FTS5 rank
+// of the query's SEARCH condition
+func buildRankedOrderByList(table string, orderBy []*pb.OrderByClause, conditions []*pb.QueryCondition) string {
+	parts := make([]string, 0, len(orderBy))
+	for _, ob := range orderBy {
+		field := BuildExpressionSQL(ob.FieldExpr)
+		if ob.FieldExpr != nil && mapping.IsSearchRankField(ob.FieldExpr.Value) {
+			if rank := buildSearchRank(table, conditions); rank != "" {
+				field = rank
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", field, ob.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildLimitSQL renders LIMIT / OFFSET
+// SQLite has no OFFSET without LIMIT; a negative limit means no limit.
+func buildLimitSQL(limit, offset int32) string {
+	if limit > 0 {
+		if offset > 0 {
+			return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+		}
+		return fmt.Sprintf(" LIMIT %d", limit)
+	}
+	if offset > 0 {
+		return fmt.Sprintf(" LIMIT -1 OFFSET %d", offset)
+	}
+	return ""
+}
+
+// ============================================================================
+// CRUD OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildSelectSQL creates parameterized SELECT query with expression support
+// SQLite locks the whole database rather than rows, so FOR UPDATE / FOR SHARE
+// are dropped: BEGIN IMMEDIATE already serializes writers.
+func BuildSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectClause := "SELECT"
+	if query.Distinct {
+		selectClause = "SELECT DISTINCT"
+	}
+
+	var args []interface{}
+
+	columns := "*"
+	if len(query.SelectColumns) > 0 {
+		var colParts []string
+		for _, col := range query.SelectColumns {
+			if col.ExpressionObj != nil && col.ExpressionObj.Type == "CASEWHEN" {
+				caseSQL := "CASE"
+				for _, cond := range col.ExpressionObj.CaseConditions {
+					condSQL := buildConditionSQL(cond.Condition)
+					thenSQL, thenArgs := buildValueSQL(cond.ThenExpr)
+					caseSQL += fmt.Sprintf(" WHEN %s THEN %s", condSQL, thenSQL)
+					args = append(args, thenArgs...)
+				}
+				if col.ExpressionObj.CaseElse != nil {
+					elseSQL, elseArgs := buildValueSQL(col.ExpressionObj.CaseElse)
+					caseSQL += " ELSE " + elseSQL
+					args = append(args, elseArgs...)
+				}
+				caseSQL += " END"
+				if col.Alias != "" {
+					caseSQL += " AS " + QuoteIdentifier(col.Alias)
+				}
+				colParts = append(colParts, caseSQL)
+			} else if col.ExpressionObj != nil && col.ExpressionObj.Type == "WINDOW" {
+				windowSQL := buildWindowExprSQL(col.ExpressionObj)
+				if col.Alias != "" {
+					windowSQL += " AS " + QuoteIdentifier(col.Alias)
+				}
+				colParts = append(colParts, windowSQL)
+			} else {
+				colStr := BuildExpressionSQL(col.ExpressionObj)
+				if col.Alias != "" {
+					colStr += " AS " + QuoteIdentifier(col.Alias)
+				}
+				colParts = append(colParts, colStr)
+			}
+		}
+		columns = strings.Join(colParts, ", ")
+	} else if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("%s %s FROM %s", selectClause, columns, QuoteIdentifier(query.Table))
+
+	whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildRankedOrderByList(query.Table, query.OrderBy, query.Conditions)
+	}
+	sql += buildLimitSQL(query.Limit, query.Offset)
+
+	return sql, args
+}
+
+func buildConditionSQL(cond *pb.QueryCondition) string {
+	if cond == nil {
+		return ""
+	}
+	if len(cond.Nested) > 0 {
+		var parts []string
+		for i, nested := range cond.Nested {
+			part := buildConditionSQL(nested)
+			if len(nested.Nested) > 0 {
+				part = "(" + part + ")"
+			}
+			if i > 0 {
+				logic := nested.Logic
+				if logic == "" {
+					logic = "AND"
+				}
+				part = logic + " " + part
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	field := BuildExpressionSQL(cond.FieldExpr)
+	switch cond.Operator {
+	case "IS_NULL":
+		return field + " IS NULL"
+	case "IS_NOT_NULL":
+		return field + " IS NOT NULL"
+	}
+	return fmt.Sprintf("%s %s %s", field, sqliteOperator(cond.Operator), buildLiteralSQL(cond.ValueExpr))
+}
+
+func buildWindowExprSQL(expr *pb.Expression) string {
+	funcName := strings.ReplaceAll(expr.FunctionName, " ", "_")
+
+	var funcCall string
+	switch funcName {
+	case "LAG", "LEAD":
+		field := "id"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				field = QuoteIdentifier(arg.Value)
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, field)
+	case "NTILE":
+		buckets := "4"
+		for _, arg := range expr.FunctionArgs {
+			if !strings.HasPrefix(arg.Value, "PARTITION:") && !strings.HasPrefix(arg.Value, "ORDER:") {
+				buckets = arg.Value
+				break
+			}
+		}
+		funcCall = fmt.Sprintf("%s(%s)", funcName, buckets)
+	default:
+		funcCall = fmt.Sprintf("%s()", funcName)
+	}
+
+	var partitionParts, orderParts []string
+	for _, arg := range expr.FunctionArgs {
+		if strings.HasPrefix(arg.Value, "PARTITION:") {
+			partitionParts = append(partitionParts, QuoteIdentifier(strings.TrimPrefix(arg.Value, "PARTITION:")))
+		} else if strings.HasPrefix(arg.Value, "ORDER:") {
+			parts := strings.Split(strings.TrimPrefix(arg.Value, "ORDER:"), ":")
+			if len(parts) >= 2 {
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", QuoteIdentifier(parts[0]), parts[1]))
+			} else if len(parts) == 1 {
+				orderParts = append(orderParts, QuoteIdentifier(parts[0])+" ASC")
+			}
+		}
+	}
+
+	overClause := " OVER ("
+	if len(partitionParts) > 0 {
+		overClause += "PARTITION BY " + strings.Join(partitionParts, ", ")
+		if len(orderParts) > 0 {
+			overClause += " "
+		}
+	}
+	if len(orderParts) > 0 {
+		overClause += "ORDER BY " + strings.Join(orderParts, ", ")
+	}
+	overClause += ")"
+
+	return funcCall + overClause
+}
+
+// BuildInsertSQL creates parameterized INSERT query
+func BuildInsertSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query.ViewQuery != nil {
+		return buildInsertFromSQL(query), nil
+	}
+
+	var fields, placeholders []string
+	var args []interface{}
+
+	for _, field := range query.Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		placeholders = append(placeholders, valueSQL)
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdentifier(query.Table), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	sql += BuildReturningClause(query.Returning)
+
+	return sql, args
+}
+
+// buildInsertFromSQL renders CREATE entity FROM GET ... as INSERT ... SELECT
+// Plain GET columns name the target columns; otherwise they match by position.
+func buildInsertFromSQL(query *pb.RelationalQuery) string {
+	sql := "INSERT INTO " + QuoteIdentifier(query.Table)
+	if columns := sourceColumnNames(query.ViewQuery.Columns); len(columns) > 0 {
+		sql += " (" + strings.Join(columns, ", ") + ")"
+	}
+	return sql + " " + buildViewQuerySQL(query.ViewQuery)
+}
+
+// sourceColumnNames quotes the target columns of INSERT ... SELECT: the bare
+// names of the GET's columns, or nil for GET * and computed columns
+func sourceColumnNames(columns []*pb.Expression) []string {
+	var names []string
+	for _, col := range columns {
+		if col == nil || col.Type != "FIELD" || col.Value == "*" {
+			return nil
+		}
+		name := col.Value
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:]
+		}
+		names = append(names, QuoteIdentifier(name))
+	}
+	return names
+}
+
+// BuildReplaceSQL creates INSERT OR REPLACE, which deletes the conflicting row
+// first: columns not listed fall back to their defaults. Use UPSERT to keep them.
+func BuildReplaceSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql, args := BuildInsertSQL(query)
+	return strings.Replace(sql, "INSERT", "INSERT OR REPLACE", 1), args
+}
+
+// BuildReturningClause renders RETURNING (SQLite 3.35+)
+func BuildReturningClause(returning []*pb.Expression) string {
+	if len(returning) == 0 {
+		return ""
+	}
+	return " RETURNING " + buildExpressionList(returning)
+}
+
+// BuildUpdateSQL creates parameterized UPDATE query with expression support
+func BuildUpdateSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var setParts []string
+	var args []interface{}
+
+	// JSON path assignments are grouped by column and rendered where the column first appears
+	jsonSets := map[string][]*pb.QueryField{}
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			jsonSets[column.Value] = append(jsonSets[column.Value], field)
+		}
+	}
+
+	for _, field := range query.Fields {
+		if field.NameExpr != nil && field.NameExpr.Type == "JSON_PATH" {
+			column, _ := jsonPathTarget(field.NameExpr)
+			group, pending := jsonSets[column.Value]
+			if !pending {
+				continue
+			}
+			delete(jsonSets, column.Value)
+			setSQL, setArgs := buildJSONSetSQL(group)
+			setParts = append(setParts, setSQL)
+			args = append(args, setArgs...)
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		setParts = append(setParts, fmt.Sprintf("%s = %s", QuoteIdentifier(getFieldName(field)), valueSQL))
+		args = append(args, valueArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s", QuoteIdentifier(query.Table), strings.Join(setParts, ", "))
+
+	whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+	sql += whereClause
+	args = append(args, whereArgs...)
+	sql += BuildReturningClause(query.Returning)
+
+	return sql, args
+}
+
+// BuildDeleteSQL creates parameterized DELETE query
+func BuildDeleteSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	sql := fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table))
+	whereClause, args := BuildWhereClause(query.Table, query.Conditions)
+	sql += whereClause
+	sql += BuildReturningClause(query.Returning)
+	return sql, args
+}
+
+// BuildUpsertSQL creates UPSERT using INSERT ... ON CONFLICT (cols) DO UPDATE SET (SQLite 3.24+)
+// Unlike INSERT OR REPLACE the existing row is updated in place, so columns
+// that are not assigned keep their values.
+func BuildUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if query.Upsert == nil {
+		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
+	}
+
+	sql, args := BuildInsertSQL(&pb.RelationalQuery{Table: query.Table, Fields: query.Fields})
+	conflictSQL, conflictArgs, err := buildOnConflictSQL(query.Upsert)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += conflictSQL + BuildReturningClause(query.Returning)
+	args = append(args, conflictArgs...)
+
+	return sql, args, nil
+}
+
+// buildOnConflictSQL renders ON CONFLICT (cols) [WHERE ...] DO UPDATE SET col = excluded.col, ...
+// or ON CONFLICT [(cols) [WHERE ...]] DO NOTHING
+// The target must match a PRIMARY KEY or UNIQUE index (the WHERE clause picks
+// a partial one); SQLite cannot name a constraint instead.
+func buildOnConflictSQL(upsert *pb.UpsertClause) (string, []interface{}, error) {
+	if upsert.ConflictConstraint != "" {
+		return "", nil, fmt.Errorf("SQLite cannot target constraint %s: name the conflict columns instead", upsert.ConflictConstraint)
+	}
+	// DO NOTHING may leave out the target: a conflict on any unique index skips the row
+	if len(upsert.ConflictFields) == 0 && upsert.ConflictAction == "NOTHING" {
+		return " ON CONFLICT DO NOTHING", nil, nil
+	}
+	if len(upsert.ConflictFields) == 0 {
+		return "", nil, fmt.Errorf("UPSERT requires conflict fields")
+	}
+
+	var targets []string
+	for _, cf := range upsert.ConflictFields {
+		targets = append(targets, QuoteIdentifier(cf.Value))
+	}
+	sql := fmt.Sprintf(" ON CONFLICT (%s)", strings.Join(targets, ", "))
+
+	var args []interface{}
+	if len(upsert.ConflictWhere) > 0 {
+		where, whereArgs := BuildWhereClause("", upsert.ConflictWhere)
+		sql += where
+		args = append(args, whereArgs...)
+	}
+
+	// DO NOTHING, or every column is part of the conflict target: the row
+	// already holds these values
+	if upsert.ConflictAction == "NOTHING" || len(upsert.UpdateFields) == 0 {
+		return sql + " DO NOTHING", args, nil
+	}
+
+	var updateParts []string
+	for _, field := range upsert.UpdateFields {
+		column := QuoteIdentifier(getFieldName(field))
+		if field.ValueExpr == nil {
+			// No value: take the row proposed for insertion
+			updateParts = append(updateParts, fmt.Sprintf("%s = excluded.%s", column, column))
+			continue
+		}
+		valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+		updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, valueSQL))
+		args = append(args, valueArgs...)
+	}
+
+	return sql + " DO UPDATE SET " + strings.Join(updateParts, ", "), args, nil
+}
+
+// excludedColumn returns col for an EXCLUDED.col reference
+func excludedColumn(name string) (string, bool) {
+	if len(name) > len("EXCLUDED.") && strings.EqualFold(name[:len("EXCLUDED.")], "EXCLUDED.") {
+		return name[len("EXCLUDED."):], true
+	}
+	return "", false
+}
+
+// BuildBulkInsertSQL creates BULK INSERT using multi-row VALUES
+func BuildBulkInsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_INSERT requires data rows")
+	}
+
+	sql, args := buildMultiRowInsertSQL(query.Table, query.BulkData)
+	return sql + BuildReturningClause(query.Returning), args, nil
+}
+
+// BuildBulkUpsertSQL creates BULK UPSERT as one multi-row INSERT ... ON CONFLICT DO UPDATE
+func BuildBulkUpsertSQL(query *pb.RelationalQuery) (string, []interface{}, error) {
+	if len(query.BulkData) == 0 {
+		return "", nil, fmt.Errorf("BULK_UPSERT requires data rows")
+	}
+	if query.Upsert == nil {
+		return "", nil, fmt.Errorf("BULK_UPSERT requires conflict fields")
+	}
+
+	sql, args := buildMultiRowInsertSQL(query.Table, query.BulkData)
+	conflictSQL, conflictArgs, err := buildOnConflictSQL(query.Upsert)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql += conflictSQL + BuildReturningClause(query.Returning)
+	return sql, append(args, conflictArgs...), nil
+}
+
+// buildMultiRowInsertSQL renders INSERT INTO t (cols) VALUES (...), (...) with the first row's columns
+func buildMultiRowInsertSQL(table string, rows []*pb.BulkInsertRow) (string, []interface{}) {
+	var fields []string
+	for _, field := range rows[0].Fields {
+		fields = append(fields, QuoteIdentifier(getFieldName(field)))
+	}
+
+	var valueClauses []string
+	var args []interface{}
+
+	for _, row := range rows {
+		placeholders := make([]string, len(row.Fields))
+		for i, field := range row.Fields {
+			valueSQL, valueArgs := buildValueSQL(field.ValueExpr)
+			placeholders[i] = valueSQL
+			args = append(args, valueArgs...)
+		}
+		valueClauses = append(valueClauses, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		QuoteIdentifier(table), strings.Join(fields, ", "), strings.Join(valueClauses, ", "))
+
+	return sql, args
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// BuildWhereClause creates a parameterized WHERE clause
+// table is the one SEARCH conditions look up in its FTS5 table
+func BuildWhereClause(table string, conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive(table, conditions)
+	return " WHERE " + clause, args
+}
+
+func buildConditionsRecursive(table string, conditions []*pb.QueryCondition) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	for i, cond := range conditions {
+		var clause string
+		var clauseArgs []interface{}
+
+		if len(cond.Nested) > 0 {
+			nestedClause, nestedArgs := buildConditionsRecursive(table, cond.Nested)
+			clause = "(" + nestedClause + ")"
+			clauseArgs = nestedArgs
+		} else {
+			clause, clauseArgs = buildSingleCondition(table, cond)
+		}
+
+		if i == 0 {
+			parts = append(parts, clause)
+		} else {
+			logic := cond.Logic
+			if logic == "" {
+				logic = "AND"
+			}
+			parts = append(parts, logic+" "+clause)
+		}
+
+		args = append(args, clauseArgs...)
+	}
+
+	return strings.Join(parts, " "), args
+}
+
+func buildSingleCondition(table string, cond *pb.QueryCondition) (string, []interface{}) {
+	field := BuildExpressionSQL(cond.FieldExpr)
+
+	switch cond.Operator {
+	case "IS_NULL":
+		return fmt.Sprintf("%s IS NULL", field), nil
+	case "IS_NOT_NULL":
+		return fmt.Sprintf("%s IS NOT NULL", field), nil
+	case "IN":
+		return buildInClause(field, "IN", cond.ValuesExpr)
+	case "NOT_IN":
+		return buildInClause(field, "NOT IN", cond.ValuesExpr)
+	case "BETWEEN":
+		return buildBetweenClause(field, "BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "NOT_BETWEEN":
+		return buildBetweenClause(field, "NOT BETWEEN", cond.ValueExpr, cond.Value2Expr)
+	case "SEARCH":
+		return buildSearchSQL(table, cond)
+	case "@>", "<@", "?", "?|", "?&":
+		return buildJSONCondition(field, cond)
+	default:
+		// ILIKE is plain LIKE: SQLite's LIKE already ignores ASCII case
+		valueSQL, args := buildValueSQL(cond.ValueExpr)
+		return fmt.Sprintf("%s %s %s", field, sqliteOperator(cond.Operator), valueSQL), args
+	}
+}
+
+// sqliteOperator maps an OQL operator (NOT_LIKE, ILIKE) to its SQLite spelling
+func sqliteOperator(op string) string {
+	if mapped, ok := mapping.OperatorMap["SQLite"][op]; ok {
+		return mapped
+	}
+	return op
+}
+
+func buildInClause(field, operator string, values []*pb.Expression) (string, []interface{}) {
+	if len(values) == 0 {
+		if operator == "IN" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+
+	placeholders := make([]string, len(values))
+	var args []interface{}
+	for i, v := range values {
+		valueSQL, valueArgs := buildValueSQL(v)
+		placeholders[i] = valueSQL
+		args = append(args, valueArgs...)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", field, operator, strings.Join(placeholders, ", ")), args
+}
+
+func buildBetweenClause(field, operator string, value1Expr, value2Expr *pb.Expression) (string, []interface{}) {
+	// Each bound is rendered on its own: BETWEEN ? AND date('now') is valid
+	val1SQL, args := buildValueSQL(value1Expr)
+	val2SQL, val2Args := buildValueSQL(value2Expr)
+	args = append(args, val2Args...)
+	return fmt.Sprintf("%s %s %s AND %s", field, operator, val1SQL, val2SQL), args
+}
+
+// BuildExpressionSQL converts an Expression to SQL
+func BuildExpressionSQL(expr *pb.Expression) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case "BINARY":
+		left := BuildExpressionSQL(expr.Left)
+		right := BuildExpressionSQL(expr.Right)
+		// Add parentheses around nested BINARY to preserve precedence
+		if expr.Left != nil && expr.Left.Type == "BINARY" {
+			left = "(" + left + ")"
+		}
+		if expr.Right != nil && expr.Right.Type == "BINARY" {
+			right = "(" + right + ")"
+		}
+		return fmt.Sprintf("%s %s %s", left, expr.Operator, right)
+	case "JSON_PATH":
+		return buildJSONPathSQL(expr)
+	case "FUNCTION":
+		if builderutil.IsRangeBucket(expr) {
+			return builderutil.RangeBucketSQL(expr, BuildExpressionSQL(expr.FunctionArgs[0]))
+		}
+		var args []string
+		for _, arg := range expr.FunctionArgs {
+			args = append(args, BuildExpressionSQL(arg))
+		}
+		return mapping.FunctionSQL("SQLite", expr.FunctionName, args)
+	case "CASEWHEN":
+		var caseParts []string
+		caseParts = append(caseParts, "CASE")
+		for _, cond := range expr.CaseConditions {
+			condSQL := buildConditionSQL(cond.Condition)
+			caseParts = append(caseParts, fmt.Sprintf("WHEN %s THEN %s", condSQL, buildLiteralSQL(cond.ThenExpr)))
+		}
+		if expr.CaseElse != nil {
+			caseParts = append(caseParts, fmt.Sprintf("ELSE %s", buildLiteralSQL(expr.CaseElse)))
+		}
+		caseParts = append(caseParts, "END")
+		return strings.Join(caseParts, " ")
+	case "STRING":
+		return QuoteString(expr.Value)
+	case "FIELD":
+		return quoteColumnRef(expr.Value)
+	case "COLLATE":
+		return buildCollateSQL(expr)
+	default:
+		return expr.Value
+	}
+}
+
+// ConvertSQLiteValue converts values for SQLite compatibility
+// SQLite has no boolean storage class; booleans are the integers 1 and 0.
+func ConvertSQLiteValue(value string) interface{} {
+	switch strings.ToLower(value) {
+	case "true":
+		return 1
+	case "false":
+		return 0
+	default:
+		return value
+	}
+}
+
+// ============================================================================
+// DDL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildCreateTableSQL creates the table, followed by the triggers that keep
+// generated columns up to date
+func BuildCreateTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	// CREATE TABLE entity AS GET ...
+	if query.ViewQuery != nil {
+		return fmt.Sprintf("CREATE TABLE %s AS %s", QuoteIdentifier(query.Table), buildViewQuerySQL(query.ViewQuery)), nil
+	}
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no columns specified for CREATE TABLE")
+	}
+
+	strict := query.TableOptions["STRICT"] == "true"
+	withoutRowid := query.TableOptions["WITHOUT_ROWID"] == "true"
+
+	// Generated-column triggers find their row by rowid, or by the primary key
+	// of a WITHOUT ROWID table
+	rowKey := "rowid"
+	var columns []string
+	for _, field := range query.Fields {
+		columnType := field.ValueExpr.Value
+		if strict {
+			// STRICT accepts bare type names only
+			columnType = stripTypeParams(columnType)
+		}
+		columnDef := TranslateColumn(field.NameExpr.Value, columnType, field.Constraints, typeMap)
+		if withoutRowid && strings.Contains(columnDef, "AUTOINCREMENT") {
+			return "", fmt.Errorf("WITHOUT ROWID table cannot have AUTO column %s: use INT with PRIMARY_KEY", field.NameExpr.Value)
+		}
+		if withoutRowid && strings.Contains(columnDef, "PRIMARY KEY") {
+			rowKey = QuoteIdentifier(field.NameExpr.Value)
+		}
+		columns = append(columns, columnDef)
+	}
+	if withoutRowid && rowKey == "rowid" {
+		return "", fmt.Errorf("WITHOUT ROWID table needs a PRIMARY KEY")
+	}
+
+	var triggers []string
+	for _, field := range query.Fields {
+		if field.GeneratedExpr != nil {
+			triggers = append(triggers, buildGeneratedTriggers(query.Table, field.NameExpr.Value, field.GeneratedExpr, rowKey)...)
+		}
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (%s)%s", QuoteIdentifier(query.Table), strings.Join(columns, ", "), buildTableOptions(strict, withoutRowid))}
+	return strings.Join(append(statements, triggers...), ";\n"), nil
+}
+
+// buildTableOptions renders the table options after the column list (SQLite 3.37+ for STRICT)
+func buildTableOptions(strict, withoutRowid bool) string {
+	var options []string
+	if strict {
+		options = append(options, "STRICT")
+	}
+	if withoutRowid {
+		options = append(options, "WITHOUT ROWID")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return " " + strings.Join(options, ", ")
+}
+
+// stripTypeParams drops a size from a column type: STRING(100) = STRING
+func stripTypeParams(columnType string) string {
+	if idx := strings.Index(columnType, "("); idx != -1 && !strings.HasSuffix(columnType, "[]") {
+		return columnType[:idx]
+	}
+	return columnType
+}
+
+// buildGeneratedTriggers keeps a computed column current with AFTER INSERT and
+// AFTER UPDATE triggers. The trigger's own UPDATE does not fire it again
+// because recursive_triggers is off by default. A trigger lives in its table's
+// database and may only name that table unqualified. key is the column the
+// row is found by: rowid, or the primary key of a WITHOUT ROWID table.
+func buildGeneratedTriggers(table, column string, generated *pb.Expression, key string) []string {
+	schema, bare := splitSchema(table)
+	update := fmt.Sprintf("UPDATE %s SET %s = (%s) WHERE %s = NEW.%s",
+		quoteIdentifierPart(bare), QuoteIdentifier(column), BuildExpressionSQL(generated), key, key)
+
+	var triggers []string
+	for _, event := range []string{"INSERT", "UPDATE"} {
+		name := fmt.Sprintf("%s_%s_%s", bare, column, strings.ToLower(event))
+		triggers = append(triggers, fmt.Sprintf("CREATE TRIGGER %s AFTER %s ON %s BEGIN %s; END",
+			qualifyName(schema, name), event, quoteIdentifierPart(bare), update))
+	}
+	return triggers
+}
+
+func BuildAlterTableSQL(query *pb.RelationalQuery, typeMap map[string]map[string]string) (string, error) {
+	if query.AlterAction == "" {
+		return "", fmt.Errorf("no ALTER operation specified")
+	}
+
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no column specified for ALTER TABLE")
+	}
+
+	field := query.Fields[0]
+	columnName := getFieldName(field)
+	columnValue := getFieldValue(field)
+
+	switch strings.ToUpper(query.AlterAction) {
+	case "ADD_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("ADD_COLUMN requires column type")
+		}
+		columnDef := TranslateColumn(columnName, columnValue, field.Constraints, typeMap)
+		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", QuoteIdentifier(query.Table), columnDef)
+		if field.GeneratedExpr == nil {
+			return sql, nil
+		}
+		// Fill in existing rows, then keep new ones current
+		statements := []string{sql, fmt.Sprintf("UPDATE %s SET %s = (%s)",
+			QuoteIdentifier(query.Table), QuoteIdentifier(columnName), BuildExpressionSQL(field.GeneratedExpr))}
+		statements = append(statements, buildGeneratedTriggers(query.Table, columnName, field.GeneratedExpr, "rowid")...)
+		return strings.Join(statements, ";\n"), nil
+	case "DROP_COLUMN":
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", QuoteIdentifier(query.Table), QuoteIdentifier(columnName)), nil
+	case "RENAME_COLUMN":
+		if columnValue == "" {
+			return "", fmt.Errorf("RENAME_COLUMN requires new column name")
+		}
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", QuoteIdentifier(query.Table), QuoteIdentifier(columnName), QuoteIdentifier(columnValue)), nil
+	case "MODIFY_COLUMN":
+		return "", fmt.Errorf("SQLite cannot change a column's type: recreate the table with the new definition")
+	default:
+		return "", fmt.Errorf("unknown ALTER operation: %s", query.AlterAction)
+	}
+}
+
+func BuildDropTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", QuoteIdentifier(query.Table)), nil
+}
+
+// BuildTruncateTableSQL empties a table: SQLite has no TRUNCATE, but an
+// unqualified DELETE takes the same fast path
+func BuildTruncateTableSQL(query *pb.RelationalQuery) (string, error) {
+	return fmt.Sprintf("DELETE FROM %s", QuoteIdentifier(query.Table)), nil
+}
+
+func BuildCreateIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index details specified")
+	}
+
+	indexName := query.Fields[0].NameExpr.Value
+	columnName := query.Fields[0].ValueExpr.Value
+
+	indexType := "INDEX"
+	for _, constraint := range query.Fields[0].Constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			indexType = "UNIQUE INDEX"
+		case "FULLTEXT":
+			// An FTS5 table named after the table, not the index (see fts.go)
+			return buildCreateFTSIndexSQL(query.Table, columnName), nil
+		case "TTL", "SPARSE", "PARTIAL":
+			return "", fmt.Errorf("%s indexes are not supported by SQLite (MongoDB only)", strings.ToUpper(constraint))
+		}
+	}
+
+	// An index on an attached table is created in that database: CREATE INDEX alias.idx ON table
+	schema, table := splitSchema(query.Table)
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, qualifyName(schema, indexName), quoteIdentifierPart(table), quoteIdentifierList(columnName)), nil
+}
+
+// BuildDropIndexSQL drops an index; index names are unique per database, so
+// only the table's database alias is kept
+func BuildDropIndexSQL(query *pb.RelationalQuery) (string, error) {
+	if len(query.Fields) == 0 {
+		return "", fmt.Errorf("no index name specified")
+	}
+	for _, constraint := range query.Fields[0].Constraints {
+		if strings.ToUpper(constraint) == "FULLTEXT" {
+			return buildDropFTSIndexSQL(query.Table), nil
+		}
+	}
+	schema, _ := splitSchema(query.Table)
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s", qualifyName(schema, getFieldName(query.Fields[0]))), nil
+}
+
+// formatLiteral converts a value to SQL literal format for VIEW definitions
+func formatLiteral(v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	// Check if numeric
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	// Check if boolean
+	upper := strings.ToUpper(s)
+	if upper == "TRUE" {
+		return "1"
+	}
+	if upper == "FALSE" {
+		return "0"
+	}
+	return QuoteString(s)
+}
+
+// inlinePlaceholders substitutes ? placeholders with literal values in one pass
+// Inlined values are never rescanned and ? inside string literals is skipped,
+// so a value like 'what?' cannot shift later arguments onto the wrong placeholder.
+func inlinePlaceholders(sql string, args []interface{}) string {
+	var b strings.Builder
+	inString := false
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			if inString && i+1 < len(sql) && sql[i+1] == '\'' {
+				b.WriteString("''")
+				i++
+				continue
+			}
+			inString = !inString
+		case !inString && c == '?' && next < len(args):
+			b.WriteString(formatLiteral(args[next]))
+			next++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// QuoteString returns s as a single-quoted SQLite string literal
+// Backslashes are ordinary characters in SQLite, so only quotes are doubled.
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// buildViewQuerySQL renders a view body or CREATE TABLE ... AS source with its
+// values inlined (neither takes parameters)
+func buildViewQuerySQL(query *pb.RelationalQuery) string {
+	viewSQL, args := BuildSelectSQL(query)
+	return inlinePlaceholders(viewSQL, args)
+}
+
+func BuildCreateViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified for CREATE VIEW")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no query specified for CREATE VIEW")
+	}
+	return fmt.Sprintf("CREATE VIEW %s AS %s", QuoteIdentifier(query.ViewName), buildViewQuerySQL(query.ViewQuery)), nil
+}
+
+// BuildAlterViewSQL replaces a view: SQLite has no CREATE OR REPLACE VIEW,
+// so it is dropped and created again
+func BuildAlterViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified for ALTER VIEW")
+	}
+	if query.ViewQuery == nil {
+		return "", fmt.Errorf("no query specified for ALTER VIEW")
+	}
+	viewName := QuoteIdentifier(query.ViewName)
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s;\nCREATE VIEW %s AS %s", viewName, viewName, buildViewQuerySQL(query.ViewQuery)), nil
+}
+
+func BuildDropViewSQL(query *pb.RelationalQuery) (string, error) {
+	if query.ViewName == "" {
+		return "", fmt.Errorf("no view name specified for DROP VIEW")
+	}
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s", QuoteIdentifier(query.ViewName)), nil
+}
+
+func BuildRenameTableSQL(query *pb.RelationalQuery) (string, error) {
+	if query.NewName == "" {
+		return "", fmt.Errorf("no new name specified for RENAME TABLE")
+	}
+	// The table stays in its database, so the new name is never qualified
+	_, newName := splitSchema(query.NewName)
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", QuoteIdentifier(query.Table), quoteIdentifierPart(newName)), nil
+}
+
+// BuildSchemaPragmaSQL reads the schema through a PRAGMA: table_list (SHOW
+// TABLES), or table_info (SHOW COLUMNS) and index_list (SHOW INDEXES) of a
+// table. The PRAGMA of a table in an attached database names the database.
+func BuildSchemaPragmaSQL(query *pb.RelationalQuery) (string, error) {
+	pragma := strings.ToLower(query.Operation)
+	if pragma == "table_list" {
+		return "PRAGMA table_list", nil
+	}
+	if query.Table == "" {
+		return "", fmt.Errorf("no table name specified for PRAGMA %s", pragma)
+	}
+	schema, name := splitSchema(query.Table)
+	if schema != "" {
+		pragma = quoteIdentifierPart(schema) + "." + pragma
+	}
+	return fmt.Sprintf("PRAGMA %s(%s)", pragma, quoteIdentifierPart(name)), nil
+}
+
+// BuildAttachDatabaseSQL creates ATTACH DATABASE 'file' AS alias
+// The file is created if missing and defaults to <alias>.db. Tables in it are
+// then addressed as alias.Entity. Attachments belong to one connection.
+func BuildAttachDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified for CREATE DATABASE")
+	}
+	file := query.DatabaseFile
+	if file == "" {
+		file = query.DatabaseName + ".db"
+	}
+	return fmt.Sprintf("ATTACH DATABASE %s AS %s", QuoteString(file), quoteIdentifierPart(query.DatabaseName)), nil
+}
+
+// BuildDetachDatabaseSQL creates DETACH DATABASE alias; the file itself is kept
+func BuildDetachDatabaseSQL(query *pb.RelationalQuery) (string, error) {
+	if query.DatabaseName == "" {
+		return "", fmt.Errorf("no database name specified for DROP DATABASE")
+	}
+	return fmt.Sprintf("DETACH DATABASE %s", quoteIdentifierPart(query.DatabaseName)), nil
+}
+
+// TranslateColumn renders a column definition from the SQLite type map
+// Generated columns are plain columns here; see buildGeneratedTriggers.
+func TranslateColumn(columnName, columnType string, constraints []string, typeMap map[string]map[string]string) string {
+	// SQLite has no arrays - TYPE[] is stored as JSON text
+	if mapping.IsArrayType(columnType) {
+		columnType = mapping.ArrayTypeMap["SQLite"]
+	}
+
+	baseType := columnType
+	params := ""
+
+	if idx := strings.Index(columnType, "("); idx != -1 {
+		baseType = columnType[:idx]
+		if endIdx := strings.Index(columnType, ")"); endIdx != -1 {
+			params = columnType[idx : endIdx+1]
+		}
+	}
+
+	sqliteType, exists := typeMap["SQLite"][strings.ToUpper(baseType)]
+	if !exists {
+		sqliteType = baseType
+	}
+
+	// Sizes are accepted and ignored by SQLite; keep them for readability
+	columnDef := fmt.Sprintf("%s %s", QuoteIdentifier(columnName), sqliteType)
+	if !strings.Contains(sqliteType, " ") {
+		columnDef += params
+	}
+
+	// Handle constraints from AST (AUTO already carries PRIMARY KEY)
+	for _, constraint := range constraints {
+		switch strings.ToUpper(constraint) {
+		case "UNIQUE":
+			columnDef += " UNIQUE"
+		case "NOT_NULL", "NOTNULL":
+			columnDef += " NOT NULL"
+		case "PRIMARY_KEY", "PRIMARYKEY":
+			if !strings.Contains(columnDef, "PRIMARY KEY") {
+				columnDef += " PRIMARY KEY"
+			}
+		}
+	}
+
+	return columnDef
+}
+
+// ============================================================================
+// DQL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildJoinSQL creates joins; RIGHT and FULL joins need SQLite 3.39+
+func BuildJoinSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	selectClause := "*"
+	if len(query.Columns) > 0 {
+		selectClause = buildExpressionList(query.Columns)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selectClause, QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	for _, join := range query.Joins {
+		joinType := strings.ToUpper(strings.Replace(join.JoinType, "_", " ", -1))
+		table, joinTable := QuoteIdentifier(query.Table), QuoteIdentifier(join.Table)
+		left, right := QuoteIdentifier(getJoinLeft(join)), QuoteIdentifier(getJoinRight(join))
+		if joinType == "CROSS" {
+			sql += fmt.Sprintf(" CROSS JOIN %s", joinTable)
+		} else {
+			sql += fmt.Sprintf(" %s JOIN %s ON %s.%s = %s.%s", joinType, joinTable, table, left, joinTable, right)
+		}
+	}
+
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+		sql += whereClause
+		args = append(args, whereArgs...)
+	}
+
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildRankedOrderByList(query.Table, query.OrderBy, query.Conditions)
+	}
+	sql += buildLimitSQL(query.Limit, query.Offset)
+
+	return sql, args
+}
+
+// buildGroupConcatSQL builds: GROUP_CONCAT([DISTINCT] field[, 'sep'] [ORDER BY ...])
+// A DISTINCT aggregate takes a single argument, so it keeps the default ','
+// separator. ORDER BY inside the call needs SQLite 3.44+.
+func buildGroupConcatSQL(agg *pb.AggregateClause, distinct bool) string {
+	sql := "GROUP_CONCAT("
+	if distinct {
+		sql += "DISTINCT " + quoteColumnRef(getAggField(agg))
+	} else {
+		sql += quoteColumnRef(getAggField(agg)) + ", " + QuoteString(agg.Separator)
+	}
+	if len(agg.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(agg.OrderBy)
+	}
+	return sql + ")"
+}
+
+func BuildAggregateSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var selectClause string
+	var args []interface{}
+
+	if query.Aggregate != nil {
+		aggFunc := strings.ToUpper(query.Aggregate.Function)
+		aggField := quoteColumnRef(getAggField(query.Aggregate))
+
+		if aggField == "" || aggField == "*" {
+			if query.Distinct {
+				selectClause = "SELECT COUNT(*)"
+			} else {
+				selectClause = fmt.Sprintf("SELECT %s(*)", aggFunc)
+			}
+		} else if aggFunc == "STRING AGG" {
+			selectClause = "SELECT " + buildGroupConcatSQL(query.Aggregate, query.Distinct)
+		} else if query.Distinct {
+			selectClause = fmt.Sprintf("SELECT %s(DISTINCT %s)", aggFunc, aggField)
+		} else {
+			selectClause = fmt.Sprintf("SELECT %s(%s)", aggFunc, aggField)
+		}
+		if len(query.GroupBy) > 0 {
+			selectClause += ", " + buildGroupByColumns(query.GroupBy)
+		}
+	} else {
+		selectClause = "SELECT COUNT(*)"
+	}
+
+	needsSubquery := (query.Limit > 0 || query.Offset > 0) && len(query.GroupBy) == 0
+	var sql string
+
+	if needsSubquery {
+		innerSQL := fmt.Sprintf("SELECT * FROM %s", QuoteIdentifier(query.Table))
+		if len(query.Conditions) > 0 {
+			whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+			innerSQL += whereClause
+			args = append(args, whereArgs...)
+		}
+		if len(query.OrderBy) > 0 {
+			innerSQL += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		innerSQL += buildLimitSQL(query.Limit, query.Offset)
+		sql = fmt.Sprintf("%s FROM (%s) AS subquery", selectClause, innerSQL)
+	} else {
+		sql = fmt.Sprintf("%s FROM %s", selectClause, QuoteIdentifier(query.Table))
+		if len(query.Conditions) > 0 {
+			whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+			sql += whereClause
+			args = append(args, whereArgs...)
+		}
+		if len(query.GroupBy) > 0 {
+			sql += " GROUP BY " + buildExpressionList(query.GroupBy)
+		}
+		if len(query.Having) > 0 {
+			havingClause, havingArgs := BuildHavingClause(query.Having)
+			sql += havingClause
+			args = append(args, havingArgs...)
+		}
+		if len(query.OrderBy) > 0 {
+			sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+		}
+		sql += buildLimitSQL(query.Limit, query.Offset)
+	}
+
+	return sql, args
+}
+
+// BuildWindowSQL creates window function queries (SQLite 3.25+)
+func BuildWindowSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var selectParts []string
+	selectParts = append(selectParts, "*")
+
+	for _, wf := range query.WindowFunctions {
+		windowFunc := strings.ToUpper(wf.Function)
+		windowFunc = strings.ReplaceAll(windowFunc, " ", "_")
+
+		var funcSQL string
+		switch windowFunc {
+		case "LAG", "LEAD":
+			funcSQL = buildShiftSQL(windowFunc, wf)
+		case "NTILE":
+			buckets := wf.Buckets
+			if buckets <= 0 {
+				buckets = 4
+			}
+			funcSQL = fmt.Sprintf("NTILE(%d)", buckets)
+		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+			funcSQL = buildWindowAggregateSQL(windowFunc, wf)
+		default:
+			funcSQL = fmt.Sprintf("%s()", windowFunc)
+		}
+
+		var overParts []string
+		if len(wf.PartitionBy) > 0 {
+			overParts = append(overParts, "PARTITION BY "+buildExpressionList(wf.PartitionBy))
+		}
+		if len(wf.OrderBy) > 0 {
+			overParts = append(overParts, "ORDER BY "+buildOrderByList(wf.OrderBy))
+		}
+		if frame := buildWindowFrameSQL(wf); frame != "" {
+			overParts = append(overParts, frame)
+		}
+
+		column := fmt.Sprintf("%s OVER (%s)", funcSQL, strings.Join(overParts, " "))
+		if wf.Alias != "" {
+			column += " AS " + QuoteIdentifier(wf.Alias)
+		}
+		selectParts = append(selectParts, column)
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+		sql += whereClause
+		args = append(args, whereArgs...)
+	}
+
+	return sql, args
+}
+
+// buildShiftSQL renders LAG / LEAD (field, offset[, default])
+func buildShiftSQL(function string, wf *pb.WindowClause) string {
+	field := "id"
+	if wf.FieldExpr != nil && wf.FieldExpr.Value != "" {
+		field = wf.FieldExpr.Value
+	}
+	offset := wf.Offset
+	if offset == 0 {
+		offset = 1
+	}
+	if wf.DefaultValue != nil {
+		return fmt.Sprintf("%s(%s, %d, %s)", function, QuoteIdentifier(field), offset, windowDefaultSQL(wf.DefaultValue))
+	}
+	return fmt.Sprintf("%s(%s, %d)", function, QuoteIdentifier(field), offset)
+}
+
+// buildWindowAggregateSQL renders an aggregate used as a window function: SUM("amount"), COUNT(*)
+func buildWindowAggregateSQL(function string, wf *pb.WindowClause) string {
+	if wf.FieldExpr == nil || wf.FieldExpr.Value == "" || wf.FieldExpr.Value == "*" {
+		return function + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", function, QuoteIdentifier(wf.FieldExpr.Value))
+}
+
+// buildWindowFrameSQL renders the window frame: ROWS BETWEEN 2 PRECEDING AND CURRENT ROW
+func buildWindowFrameSQL(wf *pb.WindowClause) string {
+	if wf.FrameUnit == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", wf.FrameUnit, wf.FrameStart, wf.FrameEnd)
+}
+
+// windowDefaultSQL renders the LAG / LEAD default as a literal
+func windowDefaultSQL(expr *pb.Expression) string {
+	if expr.Type == "STRING" {
+		return QuoteString(expr.Value)
+	}
+	return formatLiteral(expr.Value)
+}
+
+// BuildSetOperationSQL joins two SELECTs with UNION / INTERSECT / EXCEPT
+// SQLite rejects parenthesized members of a compound SELECT, so they are written bare;
+// a member with its own ORDER BY / LIMIT, or a nested set operation, is read
+// from a subquery. The outer ORDER BY / LIMIT / OFFSET apply to the combined result.
+func BuildSetOperationSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	setOp := query.SetOperation
+
+	leftSQL, leftArgs := buildSetOperandSQL(setOp.LeftQuery)
+	rightSQL, rightArgs := buildSetOperandSQL(setOp.RightQuery)
+
+	var operator string
+	switch strings.ToUpper(setOp.OperationType) {
+	case "UNION_ALL", "UNION ALL":
+		operator = "UNION ALL"
+	case "INTERSECT":
+		operator = "INTERSECT"
+	case "EXCEPT":
+		operator = "EXCEPT"
+	default:
+		operator = "UNION"
+	}
+
+	sql := fmt.Sprintf("%s %s %s", leftSQL, operator, rightSQL)
+	if len(query.OrderBy) > 0 {
+		sql += " ORDER BY " + buildOrderByList(query.OrderBy)
+	}
+	sql += buildLimitSQL(query.Limit, query.Offset)
+	return sql, append(leftArgs, rightArgs...)
+}
+
+// buildSetOperandSQL builds one member of a compound SELECT
+func buildSetOperandSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	var sql string
+	var args []interface{}
+	switch {
+	case query.SetOperation != nil:
+		sql, args = BuildSetOperationSQL(query)
+	case len(query.Joins) > 0:
+		sql, args = BuildJoinSQL(query)
+	case query.Aggregate != nil:
+		sql, args = BuildAggregateSQL(query)
+	default:
+		sql, args = BuildSelectSQL(query)
+	}
+	if query.SetOperation != nil || len(query.OrderBy) > 0 || query.Limit > 0 || query.Offset > 0 {
+		sql = "SELECT * FROM (" + sql + ")"
+	}
+	return sql, args
+}
+
+func BuildSimpleSelectSQL(query *pb.RelationalQuery) (string, []interface{}) {
+	columns := "*"
+	if len(query.Columns) > 0 {
+		columns = buildExpressionList(query.Columns)
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s", columns, QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := BuildWhereClause(query.Table, query.Conditions)
+		sql += whereClause
+		args = append(args, whereArgs...)
+	}
+	return sql, args
+}
+
+func BuildHavingClause(conditions []*pb.QueryCondition) (string, []interface{}) {
+	if len(conditions) == 0 {
+		return "", []interface{}{}
+	}
+	clause, args := buildConditionsRecursive("", conditions)
+	return " HAVING " + clause, args
+}
+
+// ============================================================================
+// TCL OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+// BuildBeginSQL starts a transaction: DEFERRED (default) takes locks on first
+// use, IMMEDIATE takes the write lock at once, EXCLUSIVE also keeps readers out
+// outside WAL mode
+func BuildBeginSQL(mode string) string {
+	switch mode {
+	case "DEFERRED", "IMMEDIATE", "EXCLUSIVE":
+		return "BEGIN " + mode + " TRANSACTION"
+	}
+	return "BEGIN TRANSACTION"
+}
+
+// BuildSetTransactionSQL maps SET TRANSACTION onto connection PRAGMAs
+// SQLite transactions are always serializable; READ UNCOMMITTED only has an
+// effect on shared-cache connections. READ ONLY sets query_only, and any other
+// SET TRANSACTION clears it. Unlike SET TRANSACTION, the PRAGMAs last until
+// changed, not for one transaction.
+func BuildSetTransactionSQL(query *pb.RelationalQuery) string {
+	var statements []string
+	if query.IsolationLevel != "" {
+		readUncommitted := "OFF"
+		if TranslateIsolationLevel(query.IsolationLevel) == "READ UNCOMMITTED" {
+			readUncommitted = "ON"
+		}
+		statements = append(statements, "PRAGMA read_uncommitted = "+readUncommitted)
+	}
+	queryOnly := "OFF"
+	if query.ReadOnly {
+		queryOnly = "ON"
+	}
+	statements = append(statements, "PRAGMA query_only = "+queryOnly)
+	return strings.Join(statements, ";\n")
+}
+
+// TranslateIsolationLevel normalizes an isolation level keyword
+func TranslateIsolationLevel(level string) string {
+	level = strings.ToUpper(strings.TrimSpace(level))
+	switch level {
+	case "READ_UNCOMMITTED", "READ UNCOMMITTED":
+		return "READ UNCOMMITTED"
+	case "READ_COMMITTED", "READ COMMITTED":
+		return "READ COMMITTED"
+	case "REPEATABLE_READ", "REPEATABLE READ":
+		return "REPEATABLE READ"
+	default:
+		return "SERIALIZABLE"
+	}
+}
+
+func BuildSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
+}
+
+func BuildRollbackToSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
+}
+
+func BuildReleaseSavepointSQL(savepointName string) (string, error) {
+	if savepointName == "" {
+		return "", fmt.Errorf("savepoint name is required")
+	}
+	return fmt.Sprintf("RELEASE SAVEPOINT %s", QuoteIdentifier(savepointName)), nil
+}
+
+// ============================================================================
+// CTE OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildCTESQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query.Cte == nil {
+		return "", nil
+	}
+	cteSQL, params := buildCTEBodySQL(query.Cte.CteQuery)
+	cteName := QuoteIdentifier(query.Cte.CteName)
+
+	with := "WITH"
+	if query.Cte.Recursive {
+		with = "WITH RECURSIVE"
+	}
+
+	mainSQL := fmt.Sprintf("SELECT * FROM %s", cteName)
+	if query.Cte.MainQuery != nil {
+		var mainArgs []interface{}
+		mainSQL, mainArgs = BuildSelectSQL(query.Cte.MainQuery)
+		params = append(params, mainArgs...)
+	}
+
+	return fmt.Sprintf("%s %s AS (%s) %s", with, cteName, cteSQL, mainSQL), params
+}
+
+// buildCTEBodySQL builds the query inside WITH name AS (...)
+// A recursive CTE must read "anchor UNION [ALL] recursive member".
+func buildCTEBodySQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query == nil {
+		return "", nil
+	}
+	if setOp := query.SetOperation; setOp != nil {
+		operator := ""
+		switch strings.ToUpper(setOp.OperationType) {
+		case "UNION":
+			operator = "UNION"
+		case "UNION_ALL", "UNION ALL":
+			operator = "UNION ALL"
+		default:
+			return BuildSetOperationSQL(query)
+		}
+		leftSQL, leftArgs := buildCTEBodySQL(builderutil.UnionMember(setOp.LeftQuery))
+		rightSQL, rightArgs := buildCTEBodySQL(builderutil.UnionMember(setOp.RightQuery))
+		return fmt.Sprintf("%s %s %s", leftSQL, operator, rightSQL), append(leftArgs, rightArgs...)
+	}
+	if len(query.Joins) > 0 {
+		return BuildJoinSQL(query)
+	}
+	if query.Aggregate != nil {
+		return BuildAggregateSQL(query)
+	}
+	return BuildSelectSQL(query)
+}
+
+// ============================================================================
+// SUBQUERY OPERATIONS - SQL BUILDERS
+// ============================================================================
+
+func BuildSubquerySQL(query *pb.RelationalQuery) (string, []interface{}) {
+	if query.Subquery == nil {
+		return "", nil
+	}
+
+	subqueryType := strings.ToUpper(query.Subquery.SubqueryType)
+	subquerySQL, subArgs := BuildSelectSQL(query.Subquery.Subquery)
+
+	// EXISTS is a standalone existence check
+	if subqueryType == "EXISTS" {
+		return fmt.Sprintf("SELECT EXISTS(%s)", subquerySQL), subArgs
+	}
+
+	// IN subquery requires outer table
+	if query.Table == "" {
+		return "", nil
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE ", QuoteIdentifier(query.Table))
+	var args []interface{}
+
+	if len(query.Conditions) > 0 {
+		whereClause, whereArgs := buildConditionsRecursive(query.Table, query.Conditions)
+		sql += "(" + whereClause + ") AND "
+		args = append(args, whereArgs...)
+	}
+
+	subField := BuildExpressionSQL(query.Subquery.FieldExpr)
+	sql += fmt.Sprintf("%s IN (%s)", subField, subquerySQL)
+	args = append(args, subArgs...)
+
+	return sql, args
+}
@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// COLLATION (BINARY / NOCASE)
+// ============================================================================
+
+// CollationName returns the built-in collation closest to a MongoDB strength
+// SQLite has no locale collations: strength 1 and 2 fold ASCII case with
+// NOCASE (accents still count), anything else is the default BINARY and
+// needs no COLLATE, so "" is returned.
+func CollationName(locale string, strength int) string {
+	if strings.EqualFold(locale, "simple") {
+		return ""
+	}
+	if strength == 1 || strength == 2 {
+		return "NOCASE"
+	}
+	return ""
+}
+
+// buildCollateSQL renders a COLLATE expression: "name" COLLATE "NOCASE"
+func buildCollateSQL(expr *pb.Expression) string {
+	return BuildExpressionSQL(expr.Left) + " COLLATE " + quoteIdentifierPart(expr.Value)
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// FULL-TEXT SEARCH (FTS5)
+// ============================================================================
+
+// A FULLTEXT index on SQLite is an external-content FTS5 table named
+// <table>_fts, kept in sync with the table by triggers. SEARCH finds it by
+// that name, so each table has at most one.
+
+// ftsTable returns the database alias and name of a table's FTS5 table
+// posts = posts_fts; analytics.events = analytics, events_fts
+func ftsTable(table string) (string, string) {
+	schema, bare := splitSchema(table)
+	return schema, bare + "_fts"
+}
+
+// buildCreateFTSIndexSQL creates the FTS5 table for a FULLTEXT index, its sync
+// triggers, and indexes the rows already in the table
+func buildCreateFTSIndexSQL(table, columns string) string {
+	schema, bare := splitSchema(table)
+	_, fts := ftsTable(table)
+	ftsName := quoteIdentifierPart(fts)
+	columnList := quoteIdentifierList(columns)
+
+	var newValues, oldValues []string
+	for _, column := range strings.Split(columns, ",") {
+		column = QuoteIdentifier(strings.TrimSpace(column))
+		newValues = append(newValues, "NEW."+column)
+		oldValues = append(oldValues, "OLD."+column)
+	}
+	insertNew := fmt.Sprintf("INSERT INTO %s (rowid, %s) VALUES (NEW.rowid, %s)",
+		ftsName, columnList, strings.Join(newValues, ", "))
+	deleteOld := fmt.Sprintf("INSERT INTO %s (%s, rowid, %s) VALUES ('delete', OLD.rowid, %s)",
+		ftsName, ftsName, columnList, strings.Join(oldValues, ", "))
+
+	statements := []string{fmt.Sprintf("CREATE VIRTUAL TABLE %s USING fts5(%s, content=%s, content_rowid='rowid')",
+		qualifyName(schema, fts), columnList, QuoteString(bare))}
+	for _, trigger := range []struct{ event, body string }{
+		{"INSERT", insertNew + ";"},
+		{"DELETE", deleteOld + ";"},
+		{"UPDATE", deleteOld + "; " + insertNew + ";"},
+	} {
+		name := fmt.Sprintf("%s_%s", fts, strings.ToLower(trigger.event))
+		statements = append(statements, fmt.Sprintf("CREATE TRIGGER %s AFTER %s ON %s BEGIN %s END",
+			qualifyName(schema, name), trigger.event, quoteIdentifierPart(bare), trigger.body))
+	}
+	statements = append(statements, fmt.Sprintf("INSERT INTO %s (%s) VALUES ('rebuild')", qualifyName(schema, fts), ftsName))
+	return strings.Join(statements, ";\n")
+}
+
+// buildDropFTSIndexSQL drops a table's FTS5 table and its sync triggers
+func buildDropFTSIndexSQL(table string) string {
+	schema, fts := ftsTable(table)
+	var statements []string
+	for _, event := range []string{"insert", "delete", "update"} {
+		statements = append(statements, fmt.Sprintf("DROP TRIGGER IF EXISTS %s", qualifyName(schema, fts+"_"+event)))
+	}
+	statements = append(statements, fmt.Sprintf("DROP TABLE IF EXISTS %s", qualifyName(schema, fts)))
+	return strings.Join(statements, ";\n")
+}
+
+// searchTarget resolves the table whose FTS5 table a SEARCH runs on and the
+// column it is limited to; posts.body names its table, a bare column uses table
+func searchTarget(table string, field *pb.Expression) (string, string) {
+	column := ""
+	if field != nil {
+		column = field.Value
+	}
+	if dot := strings.LastIndex(column, "."); dot > 0 {
+		return column[:dot], column[dot+1:]
+	}
+	return table, column
+}
+
+// buildSearchSQL renders SEARCH as a rowid lookup in the table's FTS5 table
+// body SEARCH 'x' = "posts".rowid IN (SELECT rowid FROM "posts_fts" WHERE "body" MATCH ?)
+// The search text is an FTS5 query: AND, OR, NOT, prefix* and "phrases" work.
+func buildSearchSQL(table string, cond *pb.QueryCondition) (string, []interface{}) {
+	table, column := searchTarget(table, cond.FieldExpr)
+	schema, fts := ftsTable(table)
+	valueSQL, args := buildValueSQL(cond.ValueExpr)
+	return fmt.Sprintf("%s.rowid IN (SELECT rowid FROM %s WHERE %s MATCH %s)",
+		QuoteIdentifier(table), qualifyName(schema, fts), QuoteIdentifier(column), valueSQL), args
+}
+
+// findSearchCondition returns the first SEARCH condition (recursive)
+func findSearchCondition(conditions []*pb.QueryCondition) *pb.QueryCondition {
+	for _, cond := range conditions {
+		if cond.Operator == "SEARCH" {
+			return cond
+		}
+		if found := findSearchCondition(cond.Nested); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// buildSearchRank builds the ORDER BY RELEVANCE expression
+// FTS5's rank is bm25, where better matches are lower, so it is negated to
+// sort like ts_rank and MATCH scores
+func buildSearchRank(table string, conditions []*pb.QueryCondition) string {
+	cond := findSearchCondition(conditions)
+	if cond == nil {
+		return ""
+	}
+	table, column := searchTarget(table, cond.FieldExpr)
+	schema, fts := ftsTable(table)
+	return fmt.Sprintf("(SELECT -rank FROM %s WHERE %s MATCH %s AND rowid = %s.rowid)",
+		qualifyName(schema, fts), QuoteIdentifier(column), QuoteString(getCondValue(cond)), QuoteIdentifier(table))
+}
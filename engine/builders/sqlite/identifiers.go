@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// IDENTIFIER QUOTING (injection-safe)
+// ============================================================================
+
+// valueKeywords are SQL value functions that appear as FIELD expressions
+// (SET updated_at = CURRENT_TIMESTAMP) and must not be quoted
+var valueKeywords = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"NULL": true, "TRUE": true, "FALSE": true, "ROWID": true,
+}
+
+// QuoteIdentifier returns name double-quoted with embedded quotes doubled
+// SQLite compares names case-insensitively, quoted or not, so every name is
+// quoted the same way. Qualified names (schema.table, table.column) are
+// quoted part by part.
+func QuoteIdentifier(name string) string {
+	if name == "" || name == "*" {
+		return name
+	}
+	if strings.Contains(name, ".") && !strings.Contains(name, `"`) {
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = quoteIdentifierPart(part)
+		}
+		return strings.Join(parts, ".")
+	}
+	return quoteIdentifierPart(name)
+}
+
+func quoteIdentifierPart(name string) string {
+	if name == "*" {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// splitSchema splits alias.name into the attached database alias and the bare name
+// Index, trigger and rename targets need them apart: SQLite qualifies the new
+// object's name, never the table it is built on.
+func splitSchema(name string) (string, string) {
+	if dot := strings.LastIndex(name, "."); dot > 0 && !strings.Contains(name, `"`) {
+		return name[:dot], name[dot+1:]
+	}
+	return "", name
+}
+
+// qualifyName quotes name, prefixed with the attached database alias when there is one
+func qualifyName(schema, name string) string {
+	if schema == "" {
+		return quoteIdentifierPart(name)
+	}
+	return quoteIdentifierPart(schema) + "." + quoteIdentifierPart(name)
+}
+
+// quoteIdentifierList quotes a comma-separated list of names (index columns)
+func quoteIdentifierList(names string) string {
+	parts := strings.Split(names, ",")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifier(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteColumnRef quotes a FIELD expression value, leaving SQL value keywords alone
+// EXCLUDED.col keeps the bare excluded qualifier of an upsert's proposed row.
+func quoteColumnRef(name string) string {
+	if valueKeywords[strings.ToUpper(name)] {
+		return name
+	}
+	if column, ok := excludedColumn(name); ok {
+		return "excluded." + QuoteIdentifier(column)
+	}
+	return QuoteIdentifier(name)
+}
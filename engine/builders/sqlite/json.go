@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/builders/internal/builderutil"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// JSON COLUMNS (JSON1: json_extract / json_set / json_tree)
+// ============================================================================
+
+// jsonPathTarget splits a JSON_PATH chain into its column and a JSON1 path
+// data->'address'->>'city' = data, $.address.city; data->0 = data, $[0]
+func jsonPathTarget(expr *pb.Expression) (*pb.Expression, string) {
+	var keys []*pb.Expression
+	for expr != nil && expr.Type == "JSON_PATH" {
+		keys = append([]*pb.Expression{expr.Right}, keys...)
+		expr = expr.Left
+	}
+	path := "$"
+	for _, key := range keys {
+		path += jsonPathStep(key)
+	}
+	return expr, path
+}
+
+// jsonPathStep renders one path leg: [n] for array indexes, .key or ."odd key" for members
+func jsonPathStep(key *pb.Expression) string {
+	if key == nil {
+		return ""
+	}
+	if key.Type == "NUMBER" {
+		return "[" + key.Value + "]"
+	}
+	if isJSONPathIdentifier(key.Value) {
+		return "." + key.Value
+	}
+	return `."` + key.Value + `"`
+}
+
+// isJSONPathIdentifier reports whether key can appear unquoted in a JSON1 path
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			continue
+		}
+		if i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// buildJSONPathSQL renders both -> and ->> as json_extract
+// json_extract returns SQL values for scalars (so comparisons with numbers and
+// strings work as on MySQL) and JSON text for objects and arrays.
+func buildJSONPathSQL(expr *pb.Expression) string {
+	column, path := jsonPathTarget(expr)
+	return fmt.Sprintf("json_extract(%s, %s)", BuildExpressionSQL(column), QuoteString(path))
+}
+
+// buildJSONCondition renders the JSONB containment and key operators
+// @> and <@ walk the contained document with json_tree; ?, ?| and ?& test json_type
+func buildJSONCondition(field string, cond *pb.QueryCondition) (string, []interface{}) {
+	switch cond.Operator {
+	case "@>":
+		valueSQL, args := buildJSONDocumentSQL(cond.ValueExpr)
+		return buildJSONContainsSQL(field, valueSQL), args
+	case "<@":
+		// The document is the container and is read twice
+		valueSQL, args := buildJSONDocumentSQL(cond.ValueExpr)
+		return buildJSONContainsSQL(valueSQL, field), append(args, args...)
+	case "?":
+		return fmt.Sprintf("json_type(%s, ?) IS NOT NULL", field),
+			[]interface{}{"$" + jsonPathStep(cond.ValueExpr)}
+	default:
+		// ?| = any key, ?& = all keys
+		if len(cond.ValuesExpr) == 0 {
+			if cond.Operator == "?|" {
+				return "1 = 0", nil
+			}
+			return "1 = 1", nil
+		}
+		logic := " OR "
+		if cond.Operator == "?&" {
+			logic = " AND "
+		}
+		parts := make([]string, len(cond.ValuesExpr))
+		args := make([]interface{}, len(cond.ValuesExpr))
+		for i, key := range cond.ValuesExpr {
+			parts[i] = fmt.Sprintf("json_type(%s, ?) IS NOT NULL", field)
+			args[i] = "$" + jsonPathStep(key)
+		}
+		return "(" + strings.Join(parts, logic) + ")", args
+	}
+}
+
+// buildJSONContainsSQL reports whether container holds every scalar of contained
+// (PostgreSQL's @>). Object members are matched by path and array elements by
+// membership, so '["a", "b"]' contains '["b"]'. contained is read once, container twice.
+func buildJSONContainsSQL(container, contained string) string {
+	return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM json_tree(%s) AS t WHERE t.type NOT IN ('object', 'array') AND "+
+		"CASE WHEN json_type(t.json, t.path) = 'array' "+
+		"THEN NOT EXISTS (SELECT 1 FROM json_each(%s, t.path) AS e WHERE e.value = t.value) "+
+		"ELSE json_extract(%s, t.fullkey) IS NOT t.value END)", contained, container, container)
+}
+
+// buildJSONDocumentSQL renders a JSON candidate: ARRAY('a', 'b') as json_array(?, ?),
+// computed expressions inline, anything else as a ? bound to the JSON text
+func buildJSONDocumentSQL(expr *pb.Expression) (string, []interface{}) {
+	if expr != nil && expr.Type == "FUNCTION" && strings.ToUpper(expr.FunctionName) == "ARRAY" {
+		var parts []string
+		var args []interface{}
+		for _, arg := range expr.FunctionArgs {
+			argSQL, argArgs := buildJSONValueSQL(arg)
+			parts = append(parts, argSQL)
+			args = append(args, argArgs...)
+		}
+		return "json_array(" + strings.Join(parts, ", ") + ")", args
+	}
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	value := ""
+	if expr != nil {
+		value = expr.Value
+	}
+	return "?", []interface{}{value}
+}
+
+// buildJSONValueSQL renders a value stored into a JSON document
+// Strings bind as JSON strings; numbers, booleans and NULL go through json()
+// so they keep their JSON type
+func buildJSONValueSQL(expr *pb.Expression) (string, []interface{}) {
+	if builderutil.IsComputed(expr) {
+		return BuildExpressionSQL(expr), nil
+	}
+	if expr == nil {
+		return "json('null')", nil
+	}
+	switch strings.ToUpper(expr.Value) {
+	case "NULL", "TRUE", "FALSE":
+		if expr.Type != "STRING" {
+			return fmt.Sprintf("json('%s')", strings.ToLower(expr.Value)), nil
+		}
+	}
+	if expr.Type == "FIELD" && valueKeywords[strings.ToUpper(expr.Value)] {
+		return expr.Value, nil
+	}
+	if expr.Type == "NUMBER" {
+		return "json(?)", []interface{}{expr.Value}
+	}
+	return "?", []interface{}{expr.Value}
+}
+
+// buildJSONSetSQL renders the JSON path assignments to one column as a single
+// col = json_set(col, '$.a', v1, '$.b', v2). SQLite evaluates every SET against
+// the old row and keeps only the last assignment to a column, so they are merged.
+func buildJSONSetSQL(fields []*pb.QueryField) (string, []interface{}) {
+	column, _ := jsonPathTarget(fields[0].NameExpr)
+	columnSQL := BuildExpressionSQL(column)
+	var parts []string
+	var args []interface{}
+	for _, field := range fields {
+		_, path := jsonPathTarget(field.NameExpr)
+		valueSQL, valueArgs := buildJSONValueSQL(field.ValueExpr)
+		parts = append(parts, QuoteString(path), valueSQL)
+		args = append(args, valueArgs...)
+	}
+	return fmt.Sprintf("%s = json_set(%s, %s)", columnSQL, columnSQL, strings.Join(parts, ", ")), args
+}
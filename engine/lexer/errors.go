@@ -1,7 +1,9 @@
 package lexer
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/omniql-engine/omniql/mapping"
@@ -10,14 +12,27 @@ import (
 // ParseError represents an error with position info
 type ParseError struct {
 	Message  string
-	Position int
-	Line     int
-	Column   int
-	Token    string
+	Position int    // Byte offset in input (0-indexed)
+	Line     int    // Line number (1-indexed)
+	Column   int    // Column number (1-indexed)
+	Token    string // Offending token text
 }
 
 func (e *ParseError) Error() string {
-	return fmt.Sprintf("parse error at line %d, column %d: %s", e.Line, e.Column, e.Message)
+	msg := fmt.Sprintf("parse error at line %d, column %d: %s", e.Line, e.Column, e.Message)
+	if e.Token != "" && !strings.Contains(e.Message, e.Token) {
+		msg += fmt.Sprintf(" (near '%s')", e.Token)
+	}
+	return msg
+}
+
+// AsParseError returns the ParseError wrapped anywhere in err's chain
+func AsParseError(err error) (*ParseError, bool) {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return pe, true
+	}
+	return nil, false
 }
 
 // NewParseError creates a new parse error
@@ -118,7 +133,67 @@ func SuggestSimilar(unknown string) string {
 	return bestMatch
 }
 
+// SuggestOperation finds the closest operation in mapping.OperationGroups
+// Used when the leading keyword of a query is not a known operation
+func SuggestOperation(unknown string) string {
+	candidates := make([]string, 0, len(mapping.OperationGroups))
+	for op := range mapping.OperationGroups {
+		candidates = append(candidates, op)
+	}
+	return closestMatch(strings.ToUpper(unknown), candidates)
+}
+
+// SuggestOperator finds the closest operator in mapping.OperatorMap (all databases)
+// Multi-word operators are returned in OQL form (NOT_LIKE -> NOT LIKE)
+func SuggestOperator(unknown string) string {
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, dbOps := range mapping.OperatorMap {
+		for op := range dbOps {
+			if !seen[op] {
+				seen[op] = true
+				candidates = append(candidates, op)
+			}
+		}
+	}
+	normalized := strings.ReplaceAll(strings.ToUpper(unknown), " ", "_")
+	return strings.ReplaceAll(closestMatch(normalized, candidates), "_", " ")
+}
+
+// closestMatch returns the candidate with the smallest edit distance to unknown
+// Ties prefer the candidate closest in length, then alphabetical order
+func closestMatch(unknown string, candidates []string) string {
+	maxDistance := 2
+	if len(unknown) <= 3 {
+		maxDistance = 1 // Short words: any 2 edits would match almost anything
+	}
+
+	sort.Strings(candidates)
+
+	var bestMatch string
+	bestDistance := maxDistance + 1
+	bestLenDiff := 0
+	for _, candidate := range candidates {
+		dist := levenshtein(unknown, candidate)
+		lenDiff := len(candidate) - len(unknown)
+		if lenDiff < 0 {
+			lenDiff = -lenDiff
+		}
+		if dist < bestDistance || (dist == bestDistance && lenDiff < bestLenDiff) {
+			bestDistance = dist
+			bestLenDiff = lenDiff
+			bestMatch = candidate
+		}
+	}
+	// Replacing every character is not a typo (B -> <)
+	if bestDistance > maxDistance || bestDistance >= len(unknown) {
+		return ""
+	}
+	return bestMatch
+}
+
 // levenshtein calculates edit distance between two strings
+// Adjacent transpositions count as one edit (CRAETE -> CREATE, => -> >=)
 func levenshtein(a, b string) int {
 	if len(a) == 0 {
 		return len(b)
@@ -149,6 +224,11 @@ func levenshtein(a, b string) int {
 				matrix[i][j-1]+1,      // insertion
 				matrix[i-1][j-1]+cost, // substitution
 			)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				if t := matrix[i-2][j-2] + 1; t < matrix[i][j] {
+					matrix[i][j] = t // transposition
+				}
+			}
 		}
 	}
 	
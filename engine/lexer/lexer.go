@@ -97,6 +97,7 @@ func (t *Tokenizer) tokenize() ([]Token, error) {
 				Position: t.pos,
 				Line:     t.line,
 				Column:   t.column,
+				Token:    string(ch),
 			}
 		case '\'', '"':
 			token, err := t.scanString(ch)
@@ -139,6 +140,7 @@ func (t *Tokenizer) tokenize() ([]Token, error) {
 			Position: t.pos,
 			Line:     t.line,
 			Column:   t.column,
+			Token:    string(ch),
 		}
 	}
 	
@@ -263,6 +265,7 @@ func (t *Tokenizer) scanString(quote byte) (Token, error) {
 		Position: startPos,
 		Line:     startLine,
 		Column:   startCol,
+		Token:    string(quote) + value.String(),
 	}
 }
 
@@ -301,6 +304,7 @@ func (t *Tokenizer) scanDollarQuote() (Token, error) {
 		Position: startPos,
 		Line:     startLine,
 		Column:   startCol,
+		Token:    "$$" + value.String(),
 	}
 }
 
@@ -351,6 +355,11 @@ func (t *Tokenizer) scanWord() (Token, error) {
 		if unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch)) || ch == '_' || ch == '.' || ch == ':' || ch == '@' {
 			value.WriteByte(ch)
 			t.advance()
+		} else if n := t.positionalSegment(startPos); n > 0 {
+			value.WriteString(t.input[t.pos : t.pos+n])
+			for i := 0; i < n; i++ {
+				t.advance()
+			}
 		} else {
 			break
 		}
@@ -387,10 +396,36 @@ func (t *Tokenizer) scanWord() (Token, error) {
 	}, nil
 }
 
+// positionalSegment returns the length of a MongoDB array update segment at
+// the current position ($, $[] or $[name]), 0 if there is none. It must follow
+// a dot inside a word: items.$.qty, items.$[].qty, items.$[i].qty
+func (t *Tokenizer) positionalSegment(wordStart int) int {
+	if t.pos == wordStart || t.input[t.pos] != '$' || t.input[t.pos-1] != '.' {
+		return 0
+	}
+	n := 1
+	if t.pos+n >= len(t.input) || t.input[t.pos+n] != '[' {
+		return n
+	}
+	n++
+	for t.pos+n < len(t.input) {
+		ch := t.input[t.pos+n]
+		if ch == ']' {
+			return n + 1
+		}
+		if !unicode.IsLetter(rune(ch)) && !unicode.IsDigit(rune(ch)) && ch != '_' {
+			return 0
+		}
+		n++
+	}
+	return 0
+}
+
 func (t *Tokenizer) tryMultiWord(firstWord string) string {
 	// Save position
 	savedPos := t.pos
 	savedCol := t.column
+	savedLine := t.line
 	
 	// Skip whitespace
 	t.skipWhitespace()
@@ -398,6 +433,7 @@ func (t *Tokenizer) tryMultiWord(firstWord string) string {
 	if t.pos >= len(t.input) {
 		t.pos = savedPos
 		t.column = savedCol
+		t.line = savedLine
 		return ""
 	}
 	
@@ -405,6 +441,7 @@ func (t *Tokenizer) tryMultiWord(firstWord string) string {
 	if !unicode.IsLetter(rune(t.input[t.pos])) {
 		t.pos = savedPos
 		t.column = savedCol
+		t.line = savedLine
 		return ""
 	}
 	
@@ -429,8 +466,8 @@ func (t *Tokenizer) tryMultiWord(firstWord string) string {
 		// Only consume if second word is actually uppercase (USER vs User)
 		// This distinguishes "CREATE USER" (DCL) from "CREATE User" (CRUD)
 		if nextWordStr == nextWordUpper {
+			t.column += tempPos - t.pos
 			t.pos = tempPos
-			t.column = savedCol + (tempPos - savedPos)
 			return combined
 		}
 	}
@@ -439,8 +476,8 @@ func (t *Tokenizer) tryMultiWord(firstWord string) string {
 	if _, exists := mapping.QueryClauses[combined]; exists {
 		// Clauses like "ORDER BY" should always match
 		if nextWordStr == nextWordUpper {
+			t.column += tempPos - t.pos
 			t.pos = tempPos
-			t.column = savedCol + (tempPos - savedPos)
 			return combined
 		}
 	}
@@ -448,21 +485,32 @@ func (t *Tokenizer) tryMultiWord(firstWord string) string {
 	// Not a multi-word keyword, restore position
 	t.pos = savedPos
 	t.column = savedCol
+	t.line = savedLine
 	return ""
 }
 
 func (t *Tokenizer) classifyWord(upper, original string) (TokenType, error) {
-	// Check mapping.OperationGroups
+	// Check mapping.OperationGroups (contextual operations like NOTIFY only
+	// start a statement; anywhere else they are names)
 	if _, exists := mapping.OperationGroups[upper]; exists {
+		if mapping.IsContextualOperation(upper) && len(t.tokens) > 0 {
+			return TOKEN_IDENTIFIER, nil
+		}
 		return TOKEN_OPERATION, nil
 	}
 	
-	// Check mapping.QueryClauses
-	if _, exists := mapping.QueryClauses[upper]; exists {
+	// Check mapping.QueryClauses (contextual clauses stay identifiers; the
+	// parser finds them in clause position by value)
+	if def, exists := mapping.QueryClauses[upper]; exists && !def.Contextual {
 		return TOKEN_CLAUSE, nil
 	}
 	
-	// Check mapping.OperatorMap (word operators like IN, LIKE, BETWEEN)
+	// Check mapping.OperatorMap (word operators like IN, LIKE, BETWEEN;
+	// contextual ones like SEARCH stay identifiers and the parser finds them
+	// in operator position by value)
+	if mapping.IsContextualOperator(upper) {
+		return TOKEN_IDENTIFIER, nil
+	}
 	for _, dbOps := range mapping.OperatorMap {
 		if _, exists := dbOps[upper]; exists {
 			return TOKEN_OPERATOR, nil
@@ -525,6 +573,17 @@ func (t *Tokenizer) scanOperator() (Token, error) {
 		}, nil
 	}
 	
+	// JSON path operators (->, ->>) - expression operators, not conditions
+	if mapping.IsJSONPathOperator(op) {
+		return Token{
+			Type:     TOKEN_OPERATOR,
+			Value:    op,
+			Position: startPos,
+			Line:     t.line,
+			Column:   startCol,
+		}, nil
+	}
+	
 	// Validate operator exists in mapping
 	normalized := strings.ReplaceAll(op, " ", "_")
 	for _, dbOps := range mapping.OperatorMap {
@@ -539,14 +598,20 @@ func (t *Tokenizer) scanOperator() (Token, error) {
 		}
 	}
 	
+	msg := fmt.Sprintf("unknown operator '%s'", op)
+	if suggestion := SuggestOperator(op); suggestion != "" {
+		msg += fmt.Sprintf(". Did you mean '%s'?", suggestion)
+	}
 	return Token{}, &ParseError{
-		Message:  fmt.Sprintf("unknown operator '%s'", op),
+		Message:  msg,
 		Position: startPos,
 		Line:     t.line,
 		Column:   startCol,
+		Token:    op,
 	}
 }
 
 func isOperatorChar(ch byte) bool {
-    return ch == '=' || ch == '!' || ch == '<' || ch == '>' || ch == '*' || ch == '%' || ch == '+' || ch == '-' || ch == '/'
+    return ch == '=' || ch == '!' || ch == '<' || ch == '>' || ch == '*' || ch == '%' || ch == '+' || ch == '-' || ch == '/' ||
+        ch == '@' || ch == '?' || ch == '|' || ch == '&'
 }
\ No newline at end of file
@@ -14,20 +14,36 @@ type Query struct {
 	SelectColumns []SelectColumn // SELECT with aliases
 
 	// ========== CRUD ==========
-	Conditions []Condition // WHERE conditions
-	Fields     []Field     // Field assignments or column definitions
-	Limit      int         // LIMIT clause
-	Offset     int         // OFFSET clause
+	Conditions []Condition   // WHERE conditions
+	Fields     []Field       // Field assignments or column definitions
+	Limit      int           // LIMIT clause
+	Offset     int           // OFFSET clause
+	Page       int           // PAGE clause (1-based, already desugared into Limit/Offset)
+	PageSize   int           // SIZE clause
+	After      []*Expression // AFTER cursor values (already desugared into Conditions)
 	Distinct   bool
+	Lock       string        // Row lock: UPDATE, SHARE (FOR UPDATE / FOR SHARE)
+	LockWait   string        // NOWAIT, SKIP LOCKED
+	Collation  string        // COLLATE locale: MongoDB collation, CI collations on SQL
+	CollationStrength int    // STRENGTH 1-5 (0 = locale default)
+	AsOf       string        // AS OF time (historical read): interval like "-10s" or a timestamp
+	Before     string        // BEFORE time (historical read): interval, timestamp or query ID
+	Final      bool          // FINAL: read merged rows (ClickHouse ReplacingMergeTree, ...)
+	Sample     string        // SAMPLE k: read a fraction (0.1) or about k rows (10000)
 
 	// ========== CRUD EXTENSIONS ==========
 	Upsert   *Upsert   // UPSERT operation
 	BulkData [][]Field // BULK INSERT data
 	Pattern  string    // LIKE pattern matching
 
+	Returning   []*Expression // RETURNING columns (INSERT/UPDATE/DELETE)
+	TTL         int64         // TTL in milliseconds (CREATE/UPSERT): Redis PEXPIRE, MongoDB TTL index, Cassandra USING TTL
+	IfNotExists bool          // CREATE ... IF NOT EXISTS: Cassandra lightweight transaction
+
 	// ========== DDL ==========
 	AlterAction  string // ADD_COLUMN, DROP_COLUMN, RENAME_COLUMN, MODIFY_COLUMN
 	DatabaseName string // Name identifier
+	DatabaseFile string // File to attach (SQLite ATTACH DATABASE)
 	ViewName     string // Name identifier
 	ViewQuery    *Query // 100% TrueAST - parsed subquery
 	NewName      string // Name identifier
@@ -85,9 +101,28 @@ type Query struct {
 
 	Cascade bool
 
+	// Partitioning (PostgreSQL; BigQuery partitions by a time unit)
+	PartitionStrategy  string        // RANGE, LIST, HASH; DAY, HOUR, MONTH, YEAR
+	PartitionKeys      []*Expression // 100% TrueAST
+	PartitionName      string        // CREATE PARTITION name OF Entity
+	PartitionFrom      []*Expression // FOR VALUES FROM (...) TO (...)
+	PartitionTo        []*Expression
+	PartitionIn        []*Expression // FOR VALUES IN (...)
+	PartitionModulus   int64         // FOR VALUES WITH (MODULUS m, REMAINDER r)
+	PartitionRemainder int64
+	PartitionDefault   bool
+
+	// Clustering columns (BigQuery CLUSTER BY)
+	ClusterKeys []*Expression // 100% TrueAST
+
+	// Dialect table options (MySQL: ENGINE, CHARSET, COLLATE, AUTO_INCREMENT; SQLite: STRICT, WITHOUT_ROWID;
+	// BigQuery: PARTITION_EXPIRATION_DAYS, REQUIRE_PARTITION_FILTER)
+	TableOptions map[string]string
+
 	// ========== DQL ==========
 	Joins           []Join           // JOIN clauses
 	Aggregate       *Aggregation     // Aggregate functions
+	Facets          []Facet          // FACET: more aggregates over the same rows
 	GroupBy         []*Expression    // 100% TrueAST
 	Having          []Condition      // HAVING conditions
 	OrderBy         []OrderBy        // ORDER BY clauses
@@ -102,6 +137,10 @@ type Query struct {
 
 	// ========== DCL ==========
 	Permission *Permission
+
+	// ========== PUBSUB ==========
+	Channel string // LISTEN/UNLISTEN/NOTIFY channel (* = all for UNLISTEN)
+	Payload string // NOTIFY payload
 }
 
 // ============================================================================
@@ -111,7 +150,7 @@ type Query struct {
 // Expression represents any expression in the AST
 // This is the core building block for 100% TrueAST
 type Expression struct {
-	Type     string // BINARY, FUNCTION, CASEWHEN, FIELD, LITERAL, WINDOW
+	Type     string // BINARY, FUNCTION, CASEWHEN, FIELD, LITERAL, WINDOW, JSON_PATH
 	Position int
 
 	// For leaf nodes (FIELD, LITERAL)
@@ -158,9 +197,10 @@ type Condition struct {
 
 // Field represents a field for CREATE/UPDATE or column definitions
 type Field struct {
-	NameExpr    *Expression // 100% TrueAST - field name
-	ValueExpr   *Expression // 100% TrueAST - field value or type
-	Constraints []string    // DDL constraints: UNIQUE, NOT_NULL, PRIMARY_KEY
+	NameExpr      *Expression // 100% TrueAST - field name
+	ValueExpr     *Expression // 100% TrueAST - field value or type
+	Constraints   []string    // DDL constraints: UNIQUE, NOT_NULL, PRIMARY_KEY
+	GeneratedExpr *Expression // DDL: GENERATED AS (expr) - computed column
 }
 
 // ============================================================================
@@ -189,8 +229,11 @@ type SelectColumn struct {
 
 // Upsert represents UPSERT operation
 type Upsert struct {
-	ConflictFields []*Expression // 100% TrueAST
-	UpdateFields   []Field
+	ConflictFields     []*Expression // 100% TrueAST
+	ConflictConstraint string        // ON CONSTRAINT name
+	ConflictWhere      []Condition   // Partial unique index predicate
+	UpdateFields       []Field       // nil ValueExpr = take the inserted value
+	DoNothing          bool          // DO NOTHING: a conflicting row is skipped, not updated
 }
 
 // ============================================================================
@@ -199,10 +242,11 @@ type Upsert struct {
 
 // Join represents a JOIN clause
 type Join struct {
-	Type      JoinType    // INNER, LEFT, RIGHT, FULL, CROSS
-	Table     string      // Table name
-	LeftExpr  *Expression // 100% TrueAST
-	RightExpr *Expression // 100% TrueAST
+	Type       JoinType    // INNER, LEFT, RIGHT, FULL, CROSS
+	Table      string      // Table name
+	LeftExpr   *Expression // 100% TrueAST
+	RightExpr  *Expression // 100% TrueAST
+	Conditions []Condition // Further ON conditions, AND-ed with LeftExpr = RightExpr
 }
 
 // JoinType for type safety
@@ -222,8 +266,17 @@ const (
 
 // Aggregation represents aggregate functions
 type Aggregation struct {
-	Function  AggregateFunc // COUNT, SUM, AVG, MIN, MAX
+	Function  AggregateFunc // COUNT, SUM, AVG, MIN, MAX, STRING AGG
 	FieldExpr *Expression   // 100% TrueAST
+	Separator string        // STRING AGG only
+	OrderBy   []OrderBy     // STRING AGG only: order inside the aggregate
+}
+
+// Facet is one named aggregate computed alongside the main one (FACET clause)
+type Facet struct {
+	Name      string        // Facet name
+	Aggregate *Aggregation  // 100% TrueAST
+	GroupBy   []*Expression // 100% TrueAST
 }
 
 // AggregateFunc for type safety
@@ -235,6 +288,8 @@ const (
 	Avg   AggregateFunc = "AVG"
 	Min   AggregateFunc = "MIN"
 	Max   AggregateFunc = "MAX"
+
+	StringAgg AggregateFunc = "STRING AGG"
 )
 
 // ============================================================================
@@ -261,13 +316,17 @@ const (
 
 // WindowFunction represents window function operations
 type WindowFunction struct {
-	Function    WindowFunc    // ROW NUMBER, RANK, DENSE RANK, LAG, LEAD, NTILE
+	Function    WindowFunc    // ROW NUMBER, RANK, DENSE RANK, LAG, LEAD, NTILE, COUNT, SUM, AVG, MIN, MAX
 	FieldExpr   *Expression   // 100% TrueAST
 	Alias       string        // Result column alias
 	PartitionBy []*Expression // 100% TrueAST
 	OrderBy     []OrderBy
-	Offset      int // For LAG/LEAD
-	Buckets     int // For NTILE
+	Offset      int         // For LAG/LEAD
+	Buckets     int         // For NTILE
+	Default     *Expression // For LAG/LEAD: value when no row is at the offset
+	FrameUnit   string      // ROWS, RANGE (empty = database default frame)
+	FrameStart  string      // UNBOUNDED PRECEDING, n PRECEDING, CURRENT ROW, n FOLLOWING
+	FrameEnd    string      // Same bounds as FrameStart, up to UNBOUNDED FOLLOWING
 }
 
 // WindowFunc for type safety
@@ -313,6 +372,7 @@ type CTE struct {
 	Query     *Query // CTE query definition
 	MainQuery *Query // Main query that uses the CTE
 	Recursive bool   // Is it recursive CTE?
+	MaxDepth  int    // DEPTH n: levels below the anchor, 0 = unlimited (MongoDB only)
 }
 
 // ============================================================================
@@ -348,6 +408,14 @@ type Transaction struct {
 	SavepointName  string
 	IsolationLevel string // SERIALIZABLE, REPEATABLE READ, etc.
 	ReadOnly       bool
+	Mode           string      // BEGIN mode: DEFERRED, IMMEDIATE, EXCLUSIVE (SQLite)
+	LockTables     []TableLock // LOCK TABLES entries
+}
+
+// TableLock is one table in LOCK TABLES
+type TableLock struct {
+	Entity string // Entity name as written
+	Mode   string // READ, WRITE
 }
 
 // ============================================================================
@@ -356,11 +424,16 @@ type Transaction struct {
 
 // Permission represents permission and user/role management
 type Permission struct {
-	Operation   string   // GRANT, REVOKE, CREATE USER, etc.
-	Permissions []string // READ, WRITE, DELETE
-	Target      string   // Target tenant_id or role_name
-	RoleName    string
-	UserName    string
-	Password    string
-	Roles       []string
+	Operation       string   // GRANT, REVOKE, CREATE USER, etc.
+	Permissions     []string // READ, WRITE, DELETE
+	ObjectType      string   // DATABASE, SCHEMA, SEQUENCE, FUNCTION, ALL TABLES IN SCHEMA (empty = table)
+	Target          string   // Target tenant_id or role_name
+	RoleName        string
+	UserName        string
+	Password        string
+	Roles           []string
+	RoleFlags       []string // LOGIN, NOLOGIN, SUPERUSER, CREATEDB, ...
+	ValidUntil      string   // VALID UNTIL timestamp
+	ConnectionLimit string   // CONNECTION LIMIT n
+	Host            string   // Account host of UserName/Target (user@host, MySQL)
 }
\ No newline at end of file
@@ -8,11 +8,23 @@ import (
 	"github.com/omniql-engine/omniql/mapping"
 	"github.com/omniql-engine/omniql/engine/ast"
 	"github.com/omniql-engine/omniql/engine/lexer"
+	"github.com/omniql-engine/omniql/engine/models"
 )
 
 // parseClauses parses optional clauses after main statement
 // Uses mapping.QueryClauses as SSOT for clause recognition
 func (p *Parser) parseClauses(node *ast.QueryNode) error {
+	if err := p.parseClauseList(node); err != nil {
+		return err
+	}
+	// PAGE n needs a page size: SIZE m or LIMIT m
+	if node.Page != nil && node.Limit == nil {
+		return p.error("PAGE requires SIZE or LIMIT")
+	}
+	return nil
+}
+
+func (p *Parser) parseClauseList(node *ast.QueryNode) error {
 	for !p.isAtEnd() {
 		clause := strings.ToUpper(p.current().Value)
 
@@ -62,10 +74,38 @@ func (p *Parser) parseClauses(node *ast.QueryNode) error {
 			if err := p.parseOffsetClause(node); err != nil {
 				return err
 			}
+		case "PAGE":
+			if err := p.parsePageClause(node); err != nil {
+				return err
+			}
+		case "SIZE":
+			if err := p.parseSizeClause(node); err != nil {
+				return err
+			}
+		case "AFTER":
+			if err := p.parseAfterClause(node); err != nil {
+				return err
+			}
+		case "FACET":
+			if err := p.parseFacetClause(node); err != nil {
+				return err
+			}
 		case "DISTINCT":
 			if err := p.parseDistinctClause(node); err != nil {
 				return err
 			}
+		case "RETURNING":
+			if err := p.parseReturningClause(node); err != nil {
+				return err
+			}
+		case "FOR UPDATE", "FOR SHARE":
+			if err := p.parseLockClause(node); err != nil {
+				return err
+			}
+		case "COLLATE":
+			if err := p.parseCollateClause(node); err != nil {
+				return err
+			}
 		case "WITH":
 			// WITH in GET context = SELECT expressions
 			if node.Operation == "GET" {
@@ -75,6 +115,8 @@ func (p *Parser) parseClauses(node *ast.QueryNode) error {
 			} else {
 				return nil
 			}
+		default:
+			return nil // A clause of another statement (AS, TO, ...) - stop here
 		}
 	}
 	return nil
@@ -109,8 +151,9 @@ func (p *Parser) parseOrderByClause(node *ast.QueryNode) error {
 		if p.current().Type == lexer.TOKEN_EOF {
 			break
 		}
+		// A key may be named like a contextual clause (ORDER BY size)
 		curUpper := strings.ToUpper(p.current().Value)
-		if mapping.IsClause(curUpper) && curUpper != "ASC" && curUpper != "DESC" {
+		if mapping.IsReservedClause(curUpper) && curUpper != "ASC" && curUpper != "DESC" {
 			break
 		}
 		// Stop at aggregate keywords
@@ -181,7 +224,7 @@ func (p *Parser) parseGroupByClause(node *ast.QueryNode) error {
 			break
 		}
 		curUpper := strings.ToUpper(p.current().Value)
-		if mapping.IsClause(curUpper) {
+		if mapping.IsReservedClause(curUpper) {
 			break
 		}
 		// Stop at aggregate keywords
@@ -190,6 +233,17 @@ func (p *Parser) parseGroupByClause(node *ast.QueryNode) error {
 		}
 
 		fieldTok := p.current()
+		if strings.ToUpper(fieldTok.Value) == "RANGE" && p.peek(1).Type == lexer.TOKEN_LPAREN {
+			bucket, err := p.parseRangeBucket()
+			if err != nil {
+				return err
+			}
+			node.GroupBy = append(node.GroupBy, bucket)
+			if !p.match(",") {
+				break
+			}
+			continue
+		}
 		field, err := p.expectIdentifier()
 		if err != nil {
 			return err
@@ -209,6 +263,250 @@ func (p *Parser) parseGroupByClause(node *ast.QueryNode) error {
 	return nil
 }
 
+// parseRangeBucket parses: RANGE(field, bound, bound, ...) (100% TrueAST)
+// Consecutive bounds make the buckets [b0, b1), [b1, b2), ... and each row is
+// grouped under the lower bound of its bucket. Bounds must be ascending numbers.
+func (p *Parser) parseRangeBucket() (*ast.ExpressionNode, error) {
+	rangeTok := p.advance() // consume RANGE
+	p.advance()             // consume (
+
+	fieldTok := p.current()
+	field, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	bucket := &ast.ExpressionNode{
+		Type:         "FUNCTION",
+		FunctionName: "RANGE",
+		FunctionArgs: []*ast.ExpressionNode{makeFieldExpr(field, fieldTok.Position)},
+		Position:     rangeTok.Position,
+	}
+
+	var last float64
+	for p.match(",") {
+		tok := p.current()
+		sign := ""
+		if tok.Value == "-" {
+			p.advance()
+			sign = "-"
+			tok = p.current()
+		}
+		if tok.Type != lexer.TOKEN_NUMBER {
+			return nil, p.errorAt(tok, "RANGE bounds must be numbers")
+		}
+		p.advance()
+		value, _ := strconv.ParseFloat(sign+tok.Value, 64)
+		if len(bucket.FunctionArgs) > 1 && value <= last {
+			return nil, p.errorAt(tok, "RANGE bounds must be in ascending order")
+		}
+		last = value
+		bucket.FunctionArgs = append(bucket.FunctionArgs, &ast.ExpressionNode{
+			Type:     "NUMBER",
+			Value:    sign + tok.Value,
+			Position: tok.Position,
+		})
+	}
+	if len(bucket.FunctionArgs) < 3 {
+		return nil, p.errorAt(rangeTok, "RANGE needs a field and at least two bounds")
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return bucket, nil
+}
+
+// parseReturningClause parses: RETURNING * | RETURNING field, ... (100% TrueAST)
+func (p *Parser) parseReturningClause(node *ast.QueryNode) error {
+	p.advance() // consume RETURNING
+
+	if p.current().Value == "*" {
+		tok := p.advance()
+		node.Returning = append(node.Returning, makeFieldExpr("*", tok.Position))
+		return nil
+	}
+
+	for {
+		fieldTok := p.current()
+		field, err := p.expectIdentifier()
+		if err != nil {
+			return err
+		}
+		node.Returning = append(node.Returning, makeFieldExpr(field, fieldTok.Position))
+
+		if !p.match(",") {
+			break
+		}
+	}
+	return nil
+}
+
+// ttlUnits are the TTL duration units in milliseconds; a bare number is seconds
+var ttlUnits = map[string]int64{
+	"MS": 1,
+	"S":  1000,
+	"M":  60 * 1000,
+	"H":  60 * 60 * 1000,
+	"D":  24 * 60 * 60 * 1000,
+}
+
+// parseTTLClause parses: TTL number [ms | s | m | h | d]
+// TTL 30 and TTL 30s are 30 seconds; TTL 500ms is half a second.
+func (p *Parser) parseTTLClause(node *ast.QueryNode) error {
+	p.advance() // consume TTL
+
+	amountTok := p.current()
+	if amountTok.Type != lexer.TOKEN_NUMBER || strings.ContainsAny(amountTok.Value, "-.") {
+		return p.errorAt(amountTok, "TTL expects a whole number followed by ms, s, m, h or d")
+	}
+	p.advance()
+	amount, err := strconv.ParseInt(amountTok.Value, 10, 64)
+	if err != nil || amount <= 0 {
+		return p.errorAt(amountTok, "TTL must be greater than zero")
+	}
+
+	unit := int64(1000)
+	if scale, ok := ttlUnits[strings.ToUpper(p.current().Value)]; ok && p.current().Type == lexer.TOKEN_IDENTIFIER {
+		p.advance()
+		unit = scale
+	}
+	node.TTL = amount * unit
+	return nil
+}
+
+// parseLockClause parses: FOR UPDATE | FOR SHARE [NOWAIT | SKIP LOCKED]
+func (p *Parser) parseLockClause(node *ast.QueryNode) error {
+	tok := p.advance() // consume FOR UPDATE / FOR SHARE (or just FOR)
+	clause := strings.ToUpper(tok.Value)
+	// If separate tokens, consume UPDATE/SHARE as well
+	if clause == "FOR" {
+		clause += " " + strings.ToUpper(p.advance().Value)
+	}
+	if node.Operation != "GET" {
+		return p.errorAt(tok, fmt.Sprintf("%s is only valid on GET", clause))
+	}
+	if node.Lock != "" {
+		return p.errorAt(tok, "duplicate row lock clause")
+	}
+	node.Lock = strings.TrimPrefix(clause, "FOR ")
+
+	if p.match("NOWAIT") {
+		node.LockWait = "NOWAIT"
+	} else if p.match("SKIP") {
+		if err := p.expect("LOCKED"); err != nil {
+			return err
+		}
+		node.LockWait = "SKIP LOCKED"
+	}
+	return nil
+}
+
+// parseAsOfClause parses: AS OF "time" after the entity - a relative interval
+// ("-10s") or a timestamp; the rows are read as they were at that time.
+// BEFORE "time" reads them as they were just before it, and also takes a
+// query ID (the rows before that statement changed them).
+func (p *Parser) parseAsOfClause(node *ast.QueryNode) error {
+	tok := p.advance() // consume AS OF (or just AS), or BEFORE
+	clause := "AS OF"
+	switch strings.ToUpper(tok.Value) {
+	case "AS":
+		p.advance() // consume OF
+	case "BEFORE":
+		clause = "BEFORE"
+	}
+	if node.Operation != "GET" {
+		return p.errorAt(tok, clause+" is only valid on GET")
+	}
+	if p.nested > 0 {
+		return p.errorAt(tok, clause+" applies to a whole statement, not a nested query")
+	}
+	if (clause == "AS OF" && node.AsOf != "") || (clause == "BEFORE" && node.Before != "") {
+		return p.errorAt(tok, "duplicate "+clause+" clause")
+	}
+	if node.AsOf != "" || node.Before != "" {
+		return p.errorAt(tok, "AS OF and BEFORE cannot be combined")
+	}
+	at := p.current()
+	if at.Type != lexer.TOKEN_STRING || strings.TrimSpace(at.Value) == "" {
+		return p.errorAt(at, clause+" requires a time string, e.g. "+clause+" \"-10s\"")
+	}
+	p.advance()
+	if clause == "BEFORE" {
+		node.Before = at.Value
+	} else {
+		node.AsOf = at.Value
+	}
+	return nil
+}
+
+// parseTableModifiers parses what may follow the entity of a GET or an
+// aggregate, in any order: FINAL (read rows merged by key), SAMPLE k, a
+// fraction of the rows (0.1) or about k rows (10000), and AS OF or BEFORE.
+// They are only keywords here, so before and final stay valid field names.
+func (p *Parser) parseTableModifiers(node *ast.QueryNode) error {
+	for !p.isAtEnd() {
+		tok := p.current()
+		switch strings.ToUpper(tok.Value) {
+		case "AS":
+			if !strings.EqualFold(p.peek(1).Value, "OF") {
+				return nil
+			}
+			if err := p.parseAsOfClause(node); err != nil {
+				return err
+			}
+		case "BEFORE":
+			if err := p.parseAsOfClause(node); err != nil {
+				return err
+			}
+		case "FINAL":
+			if node.Final {
+				return p.errorAt(tok, "duplicate FINAL")
+			}
+			p.advance()
+			node.Final = true
+		case "SAMPLE":
+			if node.Sample != "" {
+				return p.errorAt(tok, "duplicate SAMPLE")
+			}
+			p.advance()
+			k := p.current()
+			ratio, err := strconv.ParseFloat(k.Value, 64)
+			if k.Type != lexer.TOKEN_NUMBER || err != nil || ratio <= 0 || (ratio > 1 && ratio != float64(int64(ratio))) {
+				return p.errorAt(k, "SAMPLE requires a fraction up to 1 or a whole number of rows, e.g. SAMPLE 0.1")
+			}
+			p.advance()
+			node.Sample = k.Value
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseCollateClause parses: COLLATE locale [STRENGTH n]
+// Strength follows MongoDB: 1 ignores case and accents, 2 ignores case, 3 compares both
+func (p *Parser) parseCollateClause(node *ast.QueryNode) error {
+	tok := p.advance() // consume COLLATE
+	if node.Collation != "" {
+		return p.errorAt(tok, "duplicate COLLATE clause")
+	}
+	locale := p.current()
+	if locale.Type != lexer.TOKEN_IDENTIFIER && locale.Type != lexer.TOKEN_STRING {
+		return p.errorAt(locale, "COLLATE requires a locale")
+	}
+	p.advance()
+	node.Collation = locale.Value
+
+	if p.match("STRENGTH") {
+		strengthTok := p.advance()
+		strength, err := strconv.Atoi(strengthTok.Value)
+		if err != nil || strength < 1 || strength > 5 {
+			return p.errorAt(strengthTok, "STRENGTH requires integer from 1 to 5")
+		}
+		node.CollationStrength = strength
+	}
+	return nil
+}
+
 // parseHavingClause parses: HAVING condition [AND|OR condition]*
 func (p *Parser) parseHavingClause(node *ast.QueryNode) error {
 	p.advance() // consume HAVING
@@ -228,9 +526,12 @@ func (p *Parser) parseLimitClause(node *ast.QueryNode) error {
 	tok := p.advance()
 	val, err := strconv.Atoi(tok.Value)
 	if err != nil {
-		return p.error("LIMIT requires integer")
+		return p.errorAt(tok, "LIMIT requires integer")
 	}
 	node.Limit = &val
+	if node.Page != nil {
+		p.desugarPage(node)
+	}
 	return nil
 }
 
@@ -241,12 +542,112 @@ func (p *Parser) parseOffsetClause(node *ast.QueryNode) error {
 	tok := p.advance()
 	val, err := strconv.Atoi(tok.Value)
 	if err != nil {
-		return p.error("OFFSET requires integer")
+		return p.errorAt(tok, "OFFSET requires integer")
+	}
+	if node.Page != nil {
+		return p.errorAt(tok, "OFFSET cannot be combined with PAGE, which sets the offset")
 	}
 	node.Offset = &val
 	return nil
 }
 
+// parsePageClause parses: PAGE number (1-based)
+func (p *Parser) parsePageClause(node *ast.QueryNode) error {
+	p.advance() // consume PAGE
+
+	tok := p.advance()
+	val, err := strconv.Atoi(tok.Value)
+	if err != nil || val < 1 {
+		return p.errorAt(tok, "PAGE requires positive integer")
+	}
+	if node.Offset != nil {
+		return p.errorAt(tok, "PAGE cannot be combined with OFFSET, as it sets the offset")
+	}
+	node.Page = &val
+	p.desugarPage(node)
+	return nil
+}
+
+// parseSizeClause parses: SIZE number
+func (p *Parser) parseSizeClause(node *ast.QueryNode) error {
+	p.advance() // consume SIZE
+
+	tok := p.advance()
+	val, err := strconv.Atoi(tok.Value)
+	if err != nil || val < 1 {
+		return p.errorAt(tok, "SIZE requires positive integer")
+	}
+	node.PageSize = &val
+	p.desugarPage(node)
+	return nil
+}
+
+// parseAfterClause parses: AFTER value | AFTER cursor_token (keyset pagination)
+// Requires ORDER BY first; values line up with the ORDER BY fields
+func (p *Parser) parseAfterClause(node *ast.QueryNode) error {
+	p.advance() // consume AFTER
+
+	if len(node.OrderBy) == 0 {
+		return p.error("AFTER requires ORDER BY")
+	}
+	for _, ob := range node.OrderBy {
+		if ob.FieldExpr == nil || ob.FieldExpr.Type != "FIELD" {
+			return p.error("AFTER requires ORDER BY on plain fields")
+		}
+	}
+
+	// A plain value keeps the type it is written with (AFTER 42, AFTER "x")
+	tok := p.current()
+	values := []*models.Expression{{Type: "LITERAL", Value: tok.Value}}
+	switch tok.Type {
+	case lexer.TOKEN_NUMBER:
+		values[0].Type = "NUMBER"
+	case lexer.TOKEN_STRING:
+		values[0].Type = "STRING"
+	case lexer.TOKEN_BOOLEAN:
+		values[0].Type = "BOOLEAN"
+	case lexer.TOKEN_IDENTIFIER:
+	default:
+		return p.error(fmt.Sprintf("AFTER requires a cursor value, got '%s'", tok.Value))
+	}
+	p.advance()
+
+	if IsCursorToken(tok.Value) {
+		decoded, err := DecodeCursor(tok.Value)
+		if err != nil {
+			return p.errorAt(tok, err.Error())
+		}
+		values = decoded
+	}
+
+	if len(values) != len(node.OrderBy) {
+		return p.errorAt(tok, fmt.Sprintf("AFTER has %d cursor values but ORDER BY has %d fields", len(values), len(node.OrderBy)))
+	}
+
+	for _, v := range values {
+		node.After = append(node.After, &ast.ExpressionNode{Type: v.Type, Value: v.Value, Position: tok.Position})
+	}
+	return nil
+}
+
+// desugarPage rewrites PAGE n SIZE m into LIMIT m OFFSET (n-1)*m
+// PAGE without SIZE falls back to an explicit LIMIT as the page size
+func (p *Parser) desugarPage(node *ast.QueryNode) {
+	size := node.PageSize
+	if size == nil {
+		size = node.Limit
+	}
+	if size == nil {
+		return
+	}
+	limit := *size
+	node.Limit = &limit
+	if node.Page != nil {
+		offset := (*node.Page - 1) * limit
+		node.Offset = &offset
+	}
+}
+
 // parseDistinctClause parses: DISTINCT [column, ...] (100% TrueAST)
 func (p *Parser) parseDistinctClause(node *ast.QueryNode) error {
 	p.advance() // consume DISTINCT
@@ -420,15 +821,15 @@ func (p *Parser) parseComparisonOperator() string {
 }
 
 // validateOperator checks if operator is valid and returns error with suggestion if not
-func (p *Parser) validateOperator(op string) error {
+func (p *Parser) validateOperator(opTok lexer.Token, op string) error {
 	// Check if it's a valid comparison operator
 	if mapping.IsComparisonOperator(op) {
 		return nil
 	}
 	// Check for typo suggestion
-	suggestion := lexer.SuggestSimilar(op)
-	if suggestion != "" && mapping.IsComparisonOperator(suggestion) {
-		return p.error(fmt.Sprintf("unknown operator '%s'. Did you mean '%s'?", op, suggestion))
+	suggestion := lexer.SuggestOperator(op)
+	if suggestion != "" && mapping.IsComparisonOperator(strings.ReplaceAll(suggestion, " ", "_")) {
+		return p.errorAt(opTok, fmt.Sprintf("unknown operator '%s'. Did you mean '%s'?", opTok.Value, suggestion))
 	}
 	return nil // Let it pass - might be handled elsewhere
 }
@@ -467,10 +868,11 @@ func (p *Parser) parseCondition() (ast.ConditionNode, error) {
 	}
 
 	// Parse comparison operator
+	opTok := p.current()
 	cond.Operator = p.parseComparisonOperator()
 
 	// Validate operator (check for typos)
-	if err := p.validateOperator(cond.Operator); err != nil {
+	if err := p.validateOperator(opTok, cond.Operator); err != nil {
 		return cond, err
 	}
 
@@ -482,6 +884,10 @@ func (p *Parser) parseCondition() (ast.ConditionNode, error) {
 		cond.ValueExpr, cond.Value2Expr, err = p.parseBetweenValues()
 	case "NULLCHECK":
 		// No value needed
+	case "DISTANCE":
+		cond.ValueExpr, cond.Value2Expr, err = p.parseNearValues()
+	case "POLYGON":
+		cond.ValueExpr, err = p.parsePolygon()
 	default:
 		cond.ValueExpr, err = p.parseConditionSide()
 	}
@@ -540,6 +946,62 @@ func (p *Parser) parseBetweenSingleValue() (*ast.ExpressionNode, error) {
 	return p.parseConditionSide()
 }
 
+// parseNearValues parses: POINT(lng, lat) DISTANCE meters
+func (p *Parser) parseNearValues() (*ast.ExpressionNode, *ast.ExpressionNode, error) {
+	pointTok := p.current()
+	point, err := p.parseConditionSide()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isGeoPoint(point) {
+		return nil, nil, p.errorAt(pointTok, "NEAR expects POINT(longitude, latitude)")
+	}
+
+	if err := p.expect("DISTANCE"); err != nil {
+		return nil, nil, err
+	}
+	distTok := p.current()
+	if distTok.Type != lexer.TOKEN_NUMBER || strings.HasPrefix(distTok.Value, "-") {
+		return nil, nil, p.errorAt(distTok, "DISTANCE expects a number of meters")
+	}
+	p.advance()
+
+	return point, &ast.ExpressionNode{Type: "NUMBER", Value: distTok.Value, Position: distTok.Position}, nil
+}
+
+// parsePolygon parses: POLYGON(POINT(lng, lat), POINT(lng, lat), POINT(lng, lat), ...)
+// An open ring is closed by repeating its first point, as GeoJSON and WKT require.
+func (p *Parser) parsePolygon() (*ast.ExpressionNode, error) {
+	polygonTok := p.current()
+	polygon, err := p.parseConditionSide()
+	if err != nil {
+		return nil, err
+	}
+	if polygon.Type != "FUNCTION" || polygon.FunctionName != "POLYGON" || len(polygon.FunctionArgs) < 3 {
+		return nil, p.errorAt(polygonTok, "WITHIN expects POLYGON(POINT(lng, lat), ...) with at least 3 points")
+	}
+	for _, point := range polygon.FunctionArgs {
+		if !isGeoPoint(point) {
+			return nil, p.errorAt(p.tokenAt(point.Position), "POLYGON takes POINT(longitude, latitude) corners")
+		}
+	}
+
+	first, last := polygon.FunctionArgs[0], polygon.FunctionArgs[len(polygon.FunctionArgs)-1]
+	if first.FunctionArgs[0].Value != last.FunctionArgs[0].Value || first.FunctionArgs[1].Value != last.FunctionArgs[1].Value {
+		closing := *first
+		polygon.FunctionArgs = append(polygon.FunctionArgs, &closing)
+	}
+	return polygon, nil
+}
+
+// isGeoPoint checks for POINT(lng, lat) with two numeric coordinates
+func isGeoPoint(expr *ast.ExpressionNode) bool {
+	if expr == nil || expr.Type != "FUNCTION" || expr.FunctionName != "POINT" || len(expr.FunctionArgs) != 2 {
+		return false
+	}
+	return expr.FunctionArgs[0].Type == "NUMBER" && expr.FunctionArgs[1].Type == "NUMBER"
+}
+
 // parseSelectExpressions parses: WITH expr AS alias, expr2 AS alias2, ... (100% TrueAST)
 func (p *Parser) parseSelectExpressions(node *ast.QueryNode) error {
 	p.advance() // consume WITH
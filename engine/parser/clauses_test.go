@@ -0,0 +1,306 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+)
+
+// Contextual clause keywords are clauses only after a complete expression;
+// as an operand or an ORDER BY / GROUP BY key they are field names.
+func TestContextualClausesAsFieldNames(t *testing.T) {
+	tests := []struct {
+		input string
+		check func(q *models.Query) bool
+	}{
+		{`GET User WHERE size = 3`, func(q *models.Query) bool { return conditionField(q, 0) == "size" }},
+		{`GET User WHERE page = 1`, func(q *models.Query) bool { return conditionField(q, 0) == "page" }},
+		{`GET User WHERE status = "active" AND size > 2`, func(q *models.Query) bool { return conditionField(q, 1) == "size" }},
+		{`GET User WHERE a = size`, func(q *models.Query) bool {
+			return len(q.Conditions) == 1 && q.Conditions[0].ValueExpr.Value == "size"
+		}},
+		{`GET Product ORDER BY size DESC`, func(q *models.Query) bool {
+			return orderKey(q, 0) == "size" && q.OrderBy[0].Direction == models.Desc
+		}},
+		{`GET Product ORDER BY page ASC, size DESC`, func(q *models.Query) bool {
+			return orderKey(q, 0) == "page" && orderKey(q, 1) == "size"
+		}},
+		{`COUNT * FROM Product GROUP BY size`, func(q *models.Query) bool {
+			return len(q.GroupBy) == 1 && q.GroupBy[0].Value == "size"
+		}},
+		{`UPDATE Product SET size = 3 WHERE page = 2`, func(q *models.Query) bool { return conditionField(q, 0) == "page" }},
+		{`GET User WHERE after = 1`, func(q *models.Query) bool { return conditionField(q, 0) == "after" }},
+		{`GET Event ORDER BY after AFTER 5 LIMIT 10`, func(q *models.Query) bool {
+			return orderKey(q, 0) == "after" && len(q.After) == 1 && q.After[0].Value == "5"
+		}},
+		{`GET User WHERE facet = 1`, func(q *models.Query) bool { return conditionField(q, 0) == "facet" }},
+		{`COUNT * FROM Product GROUP BY facet`, func(q *models.Query) bool {
+			return len(q.GroupBy) == 1 && q.GroupBy[0].Value == "facet"
+		}},
+		{`COUNT * FROM Order WHERE facet = "a" GROUP BY status FACET total (SUM amount)`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "facet" && len(q.Facets) == 1 && q.Facets[0].Name == "total"
+		}},
+		{`GET User ORDER BY returning`, func(q *models.Query) bool { return orderKey(q, 0) == "returning" }},
+		{`DELETE User WHERE returning = 1 RETURNING id`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "returning" && len(q.Returning) == 1 && q.Returning[0].Value == "id"
+		}},
+		{`UPDATE User SET returning = 1 WHERE id = 2 RETURNING id, returning`, func(q *models.Query) bool {
+			return len(q.Returning) == 2 && q.Returning[1].Value == "returning"
+		}},
+		{`GET User WHERE collate = 1`, func(q *models.Query) bool { return conditionField(q, 0) == "collate" }},
+		{`GET User WHERE collate = "x" ORDER BY collate COLLATE en`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "collate" && orderKey(q, 0) == "collate" && q.Collation == "en"
+		}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !tt.check(q) {
+			t.Errorf("Parse(%q): unexpected query %+v", tt.input, q)
+		}
+	}
+}
+
+// SEARCH, NEAR and WITHIN are operators only after a condition's left side;
+// elsewhere they are field names
+func TestContextualOperatorsAsFieldNames(t *testing.T) {
+	tests := []struct {
+		input string
+		check func(q *models.Query) bool
+	}{
+		{`GET User WHERE search = 1`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "search" && q.Conditions[0].Operator == "="
+		}},
+		{`GET User WHERE status = "a" AND near > 2`, func(q *models.Query) bool { return conditionField(q, 1) == "near" }},
+		{`GET User WHERE a = within`, func(q *models.Query) bool {
+			return len(q.Conditions) == 1 && q.Conditions[0].ValueExpr.Value == "within"
+		}},
+		{`GET User ORDER BY near DESC, within`, func(q *models.Query) bool {
+			return orderKey(q, 0) == "near" && orderKey(q, 1) == "within"
+		}},
+		{`GET search, near FROM User`, func(q *models.Query) bool {
+			return len(q.Columns) == 2 && q.Columns[0].Value == "search" && q.Columns[1].Value == "near"
+		}},
+		{`CREATE Place WITH search = "a", near = 1, within = 2`, func(q *models.Query) bool {
+			return len(q.Fields) == 3 && q.Fields[2].NameExpr.Value == "within"
+		}},
+		{`GET Post WHERE search SEARCH "go"`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "search" && q.Conditions[0].Operator == "SEARCH"
+		}},
+		{`GET Store WHERE near NEAR POINT(1, 2) DISTANCE 10`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "near" && q.Conditions[0].Operator == "NEAR"
+		}},
+		{`GET Store WHERE within WITHIN POLYGON(POINT(0, 0), POINT(0, 1), POINT(1, 1))`, func(q *models.Query) bool {
+			return conditionField(q, 0) == "within" && q.Conditions[0].Operator == "WITHIN"
+		}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !tt.check(q) {
+			t.Errorf("Parse(%q): unexpected query %+v", tt.input, q)
+		}
+	}
+}
+
+// LISTEN, UNLISTEN and NOTIFY are operations only at the start of a statement;
+// elsewhere they are field and channel names
+func TestContextualOperationsAsNames(t *testing.T) {
+	tests := []struct {
+		input string
+		check func(q *models.Query) bool
+	}{
+		{`GET User WHERE notify = true`, func(q *models.Query) bool { return conditionField(q, 0) == "notify" }},
+		{`GET User WHERE a = 1 AND listen > 2 ORDER BY unlisten`, func(q *models.Query) bool {
+			return conditionField(q, 1) == "listen" && orderKey(q, 0) == "unlisten"
+		}},
+		{`GET listen, notify FROM User`, func(q *models.Query) bool {
+			return len(q.Columns) == 2 && q.Columns[1].Value == "notify"
+		}},
+		{`UPDATE User SET notify = false WHERE id = 1`, func(q *models.Query) bool {
+			return len(q.Fields) == 1 && q.Fields[0].NameExpr.Value == "notify"
+		}},
+		{`LISTEN notify`, func(q *models.Query) bool { return q.Operation == "LISTEN" && q.Channel == "notify" }},
+		{`NOTIFY listen, "x"`, func(q *models.Query) bool {
+			return q.Operation == "NOTIFY" && q.Channel == "listen" && q.Payload == "x"
+		}},
+		{`UNLISTEN unlisten`, func(q *models.Query) bool { return q.Operation == "UNLISTEN" && q.Channel == "unlisten" }},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !tt.check(q) {
+			t.Errorf("Parse(%q): unexpected query %+v", tt.input, q)
+		}
+	}
+}
+
+// Every contextual keyword (clauses such as PAGE, operators such as SEARCH
+// and operations such as NOTIFY) is a field name wherever a name can stand:
+// the query parses and renders back unchanged
+func TestContextualKeywordsAsFieldNames(t *testing.T) {
+	var keywords []string
+	for keyword, def := range mapping.QueryClauses {
+		if def.Contextual {
+			keywords = append(keywords, keyword)
+		}
+	}
+	for keyword := range mapping.ContextualOperators {
+		keywords = append(keywords, keyword)
+	}
+	for keyword := range mapping.ContextualOperations {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	templates := []string{
+		`GET User WHERE %s = 1`,
+		`GET User WHERE a = 1 AND %s > 2`,
+		`GET User WHERE a = %s`,
+		`GET User ORDER BY %s DESC`,
+		`COUNT * FROM User GROUP BY %s`,
+		`GET %s FROM User`,
+		`CREATE User WITH %s = 1`,
+		`UPDATE User SET %s = 1 WHERE id = 1`,
+	}
+	for _, keyword := range keywords {
+		for _, template := range templates {
+			input := fmt.Sprintf(template, strings.ToLower(keyword))
+			q, err := Parse(input)
+			if err != nil {
+				t.Errorf("Parse(%q): %v", input, err)
+				continue
+			}
+			if got, err := Render(q); err != nil || got != input {
+				t.Errorf("Render(Parse(%q)) = %q, %v", input, got, err)
+			}
+		}
+	}
+}
+
+func TestContextualClausesInClausePosition(t *testing.T) {
+	tests := []struct {
+		input          string
+		page, pageSize int
+	}{
+		{`GET User PAGE 2 SIZE 10`, 2, 10},
+		{`GET User WHERE size = 3 PAGE 2 SIZE 10`, 2, 10},
+		{`GET User WHERE active PAGE 3 SIZE 5`, 3, 5},
+		{`GET Product ORDER BY size PAGE 2 SIZE 10`, 2, 10},
+		{`GET Product ORDER BY page DESC, size PAGE 1 SIZE 20`, 1, 20},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if q.Page != tt.page || q.PageSize != tt.pageSize {
+			t.Errorf("Parse(%q): PAGE %d SIZE %d, want PAGE %d SIZE %d", tt.input, q.Page, q.PageSize, tt.page, tt.pageSize)
+		}
+	}
+}
+
+// PAGE desugars to LIMIT and OFFSET: it needs a page size and sets the offset
+func TestPageClause(t *testing.T) {
+	tests := []struct {
+		input         string
+		limit, offset int
+		wantErr       bool
+	}{
+		{`GET User PAGE 3 SIZE 10`, 10, 20, false},
+		{`GET User SIZE 10 PAGE 3`, 10, 20, false},
+		{`GET User PAGE 3 LIMIT 10`, 10, 20, false},
+		{`GET User PAGE 2`, 0, 0, true},
+		{`GET User WHERE active = true PAGE 2 ORDER BY id`, 0, 0, true},
+		{`GET User PAGE 2 SIZE 10 OFFSET 5`, 0, 0, true},
+		{`GET User OFFSET 5 PAGE 2 SIZE 10`, 0, 0, true},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q): expected an error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if q.Limit != tt.limit || q.Offset != tt.offset {
+			t.Errorf("Parse(%q): LIMIT %d OFFSET %d, want LIMIT %d OFFSET %d", tt.input, q.Limit, q.Offset, tt.limit, tt.offset)
+		}
+	}
+}
+
+// AS OF and BEFORE follow the entity, so before stays a field name
+func TestHistoricalReadFollowsEntity(t *testing.T) {
+	tests := []struct {
+		input        string
+		asOf, before string
+		field        string
+	}{
+		{`GET User WHERE before = 1`, "", "", "before"},
+		{`GET Order AS OF "-10s" WHERE status = "paid"`, "-10s", "", "status"},
+		{`GET Order BEFORE "01b2c3d4" WHERE before > 2`, "", "01b2c3d4", "before"},
+		{`GET id, before FROM Order AS OF "2026-10-01 12:00:00" WHERE id = 1`, "2026-10-01 12:00:00", "", "id"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if q.AsOf != tt.asOf || q.Before != tt.before || conditionField(q, 0) != tt.field {
+			t.Errorf("Parse(%q): AS OF %q BEFORE %q field %q", tt.input, q.AsOf, q.Before, conditionField(q, 0))
+			continue
+		}
+		rendered, err := Render(q)
+		if err != nil {
+			t.Errorf("Render(%q): %v", tt.input, err)
+			continue
+		}
+		again, err := Parse(rendered)
+		if err != nil || again.AsOf != q.AsOf || again.Before != q.Before {
+			t.Errorf("Render(%q) = %q does not parse back", tt.input, rendered)
+		}
+	}
+
+	for _, input := range []string{
+		`GET Order WHERE status = "paid" AS OF "-10s"`,
+		`COUNT * FROM Order AS OF "-10s"`,
+		`GET Order AS OF "-10s" BEFORE "-5s"`,
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): expected an error", input)
+		}
+	}
+}
+
+func conditionField(q *models.Query, i int) string {
+	if i >= len(q.Conditions) || q.Conditions[i].FieldExpr == nil {
+		return ""
+	}
+	return q.Conditions[i].FieldExpr.Value
+}
+
+func orderKey(q *models.Query, i int) string {
+	if i >= len(q.OrderBy) || q.OrderBy[i].FieldExpr == nil {
+		return ""
+	}
+	return q.OrderBy[i].FieldExpr.Value
+}
@@ -25,6 +25,8 @@ func (p *Parser) parseCRUD(op string) (*ast.QueryNode, error) {
 		return p.parseUpsert()
 	case "BULK INSERT":
 		return p.parseBulkInsert()
+	case "BULK UPSERT":
+		return p.parseBulkUpsert()
 	case "REPLACE":
 		return p.parseReplace()
 	default:
@@ -97,6 +99,11 @@ func (p *Parser) parseGet() (*ast.QueryNode, error) {
 		node.Columns = []*ast.ExpressionNode{makeFieldExpr("*", firstTok.Position)}
 	}
 
+	// ClickHouse: GET Event FINAL SAMPLE 0.1 WHERE ...
+	if err := p.parseTableModifiers(node); err != nil {
+		return nil, err
+	}
+
 	// // Check for aggregate BEFORE clauses (GET User COUNT WHERE id = 1)
 	// if !p.isAtEnd() {
 	// 	aggUpper := strings.ToUpper(p.current().Value)
@@ -140,7 +147,8 @@ func (p *Parser) parseGet() (*ast.QueryNode, error) {
 	return node, nil
 }
 
-// CREATE entity WITH field:value, ...
+// CREATE entity WITH field:value, ... [IF NOT EXISTS] [TTL duration] [RETURNING field, ...]
+// CREATE entity FROM GET ... (INSERT ... SELECT)
 func (p *Parser) parseCreate() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "CREATE",
@@ -155,6 +163,10 @@ func (p *Parser) parseCreate() (*ast.QueryNode, error) {
 	}
 	node.Entity = entity
 
+	if p.match("FROM") {
+		return p.parseSourceQuery(node)
+	}
+
 	// WITH
 	if err := p.expect("WITH"); err != nil {
 		return nil, err
@@ -167,6 +179,32 @@ func (p *Parser) parseCreate() (*ast.QueryNode, error) {
 	}
 	node.Fields = fields
 
+	// Optional IF NOT EXISTS: insert only if no row has the key
+	if strings.ToUpper(p.current().Value) == "IF" {
+		p.advance() // consume IF
+		if err := p.expect("NOT"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("EXISTS"); err != nil {
+			return nil, err
+		}
+		node.IfNotExists = true
+	}
+
+	// Optional TTL
+	if strings.ToUpper(p.current().Value) == "TTL" {
+		if err := p.parseTTLClause(node); err != nil {
+			return nil, err
+		}
+	}
+
+	// Optional RETURNING
+	if strings.ToUpper(p.current().Value) == "RETURNING" {
+		if err := p.parseReturningClause(node); err != nil {
+			return nil, err
+		}
+	}
+
 	return node, nil
 }
 
@@ -231,7 +269,10 @@ func (p *Parser) parseDelete() (*ast.QueryNode, error) {
 	return node, nil
 }
 
-// UPSERT entity WITH field:value ON conflict_field
+// UPSERT entity WITH field:value ON conflict_field [WHERE condition] [UPDATE SET field = expr, ... | DO NOTHING] [TTL duration] [RETURNING field, ...]
+// UPSERT entity WITH field:value ON CONSTRAINT name [UPDATE SET field = expr, ... | DO NOTHING] [TTL duration] [RETURNING field, ...]
+// UPSERT entity WITH field:value DO NOTHING (skip the row if it conflicts on any unique key)
+// UPSERT entity FROM GET ... (MongoDB: $merge into existing documents)
 func (p *Parser) parseUpsert() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "UPSERT",
@@ -245,6 +286,10 @@ func (p *Parser) parseUpsert() (*ast.QueryNode, error) {
 	}
 	node.Entity = entity
 
+	if p.match("FROM") {
+		return p.parseSourceQuery(node)
+	}
+
 	if err := p.expect("WITH"); err != nil {
 		return nil, err
 	}
@@ -256,29 +301,113 @@ func (p *Parser) parseUpsert() (*ast.QueryNode, error) {
 	node.Fields = fields
 
 	if p.match("ON") {
-		node.Upsert = &ast.UpsertNode{Position: p.current().Position}
+		if err := p.parseUpsertConflict(node, fields); err != nil {
+			return nil, err
+		}
+	} else if p.matchDoNothing() {
+		node.Upsert = &ast.UpsertNode{DoNothing: true, Position: p.current().Position}
+	}
 
+	// Optional TTL
+	if strings.ToUpper(p.current().Value) == "TTL" {
+		if err := p.parseTTLClause(node); err != nil {
+			return nil, err
+		}
+	}
+
+	// Optional RETURNING
+	if strings.ToUpper(p.current().Value) == "RETURNING" {
+		if err := p.parseReturningClause(node); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// parseUpsertConflict parses the conflict target after ON and an optional UPDATE SET
+// The inserted fields are updated on conflict unless UPDATE SET replaces them.
+func (p *Parser) parseUpsertConflict(node *ast.QueryNode, fields []ast.FieldNode) error {
+	node.Upsert = &ast.UpsertNode{Position: p.current().Position}
+
+	if strings.ToUpper(p.current().Value) == "CONSTRAINT" {
+		// Named constraint: every field is updated on conflict
+		p.advance() // consume CONSTRAINT
+		name, err := p.expectIdentifier()
+		if err != nil {
+			return err
+		}
+		node.Upsert.ConflictConstraint = name
+		node.Upsert.UpdateFields = upsertUpdateFields(fields, nil)
+	} else {
 		// Parse conflict fields as ExpressionNodes
 		conflicts, err := p.parseIdentifierListAsExpressions()
 		if err != nil {
-			return nil, err
+			return err
 		}
 		node.Upsert.ConflictFields = conflicts
 
+		// Optional predicate to infer a partial unique index
+		if p.current().Value == "WHERE" {
+			p.advance() // consume WHERE
+			where, err := p.parseConditions()
+			if err != nil {
+				return err
+			}
+			node.Upsert.ConflictWhere = where
+		}
+
 		// Copy non-conflict fields to UpdateFields
 		conflictSet := make(map[string]bool)
 		for _, c := range conflicts {
 			conflictSet[c.Value] = true
 		}
-		for _, f := range fields {
-			// Get field name from NameExpr
-			if f.NameExpr != nil && !conflictSet[f.NameExpr.Value] {
-				node.Upsert.UpdateFields = append(node.Upsert.UpdateFields, f)
-			}
+		node.Upsert.UpdateFields = upsertUpdateFields(fields, conflictSet)
+	}
+
+	// DO NOTHING: a conflicting row is skipped rather than updated
+	if p.matchDoNothing() {
+		node.Upsert.DoNothing = true
+		node.Upsert.UpdateFields = nil
+		return nil
+	}
+
+	// Optional explicit assignments: UPDATE SET visits = visits + 1, ...
+	if strings.ToUpper(p.current().Value) == "UPDATE" {
+		p.advance() // consume UPDATE
+		if err := p.expect("SET"); err != nil {
+			return err
+		}
+		updates, err := p.parseFieldAssignments()
+		if err != nil {
+			return err
 		}
+		node.Upsert.UpdateFields = updates
 	}
 
-	return node, nil
+	return nil
+}
+
+// matchDoNothing consumes DO NOTHING
+func (p *Parser) matchDoNothing() bool {
+	if !strings.EqualFold(p.current().Value, "DO") || !strings.EqualFold(p.peek(1).Value, "NOTHING") {
+		return false
+	}
+	p.advance() // consume DO
+	p.advance() // consume NOTHING
+	return true
+}
+
+// upsertUpdateFields names the fields to overwrite with their inserted value
+// The value is left out: builders render it as EXCLUDED.col / VALUES(col).
+func upsertUpdateFields(fields []ast.FieldNode, skip map[string]bool) []ast.FieldNode {
+	var updates []ast.FieldNode
+	for _, f := range fields {
+		if f.NameExpr != nil && !skip[f.NameExpr.Value] {
+			updates = append(updates, ast.FieldNode{NameExpr: f.NameExpr, Position: f.Position})
+		}
+	}
+	return updates
 }
 
 // BULK INSERT entity WITH [...] [...] ...
@@ -300,7 +429,70 @@ func (p *Parser) parseBulkInsert() (*ast.QueryNode, error) {
 		return nil, err
 	}
 
-	// Parse multiple [...] blocks, each is a row
+	if err := p.parseBulkRows(node); err != nil {
+		return nil, err
+	}
+
+	// Optional RETURNING
+	if strings.ToUpper(p.current().Value) == "RETURNING" {
+		if err := p.parseReturningClause(node); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// BULK UPSERT entity WITH [...] [...] ... ON conflict_field [UPDATE SET field = expr, ... | DO NOTHING]
+// BULK UPSERT entity WITH [...] [...] ... DO NOTHING
+// Format: BULK UPSERT User WITH [email = a@x.io, name = Alice] [email = b@x.io, name = Bob] ON email
+func (p *Parser) parseBulkUpsert() (*ast.QueryNode, error) {
+	node := &ast.QueryNode{
+		Operation: "BULK UPSERT",
+		Position:  p.current().Position,
+	}
+	p.advance() // consume BULK UPSERT
+
+	entity, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.Entity = entity
+
+	if err := p.expect("WITH"); err != nil {
+		return nil, err
+	}
+
+	if err := p.parseBulkRows(node); err != nil {
+		return nil, err
+	}
+	if len(node.BulkData) == 0 {
+		return nil, p.error("BULK UPSERT requires at least one [...] row")
+	}
+
+	if p.matchDoNothing() {
+		node.Upsert = &ast.UpsertNode{DoNothing: true, Position: p.current().Position}
+	} else {
+		if err := p.expect("ON"); err != nil {
+			return nil, err
+		}
+		if err := p.parseUpsertConflict(node, node.BulkData[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Optional RETURNING
+	if strings.ToUpper(p.current().Value) == "RETURNING" {
+		if err := p.parseReturningClause(node); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// parseBulkRows parses [field = value, ...] blocks into node.BulkData, one per row
+func (p *Parser) parseBulkRows(node *ast.QueryNode) error {
 	for !p.isAtEnd() && p.current().Value == "[" {
 		p.advance() // consume [
 
@@ -320,7 +512,7 @@ func (p *Parser) parseBulkInsert() (*ast.QueryNode, error) {
 			} else {
 				name := tok.Value
 				if !p.match(":", "=") {
-					return nil, p.error("expected ':' or '=' after field name")
+					return p.error("expected ':' or '=' after field name")
 				}
 
 				// Consume value until comma or ]
@@ -343,16 +535,17 @@ func (p *Parser) parseBulkInsert() (*ast.QueryNode, error) {
 		}
 
 		if err := p.expect("]"); err != nil {
-			return nil, err
+			return err
 		}
 
 		node.BulkData = append(node.BulkData, fields)
 	}
 
-	return node, nil
+	return nil
 }
 
 // REPLACE entity WITH field:value
+// REPLACE entity FROM GET ...
 func (p *Parser) parseReplace() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "REPLACE",
@@ -366,6 +559,10 @@ func (p *Parser) parseReplace() (*ast.QueryNode, error) {
 	}
 	node.Entity = entity
 
+	if p.match("FROM") {
+		return p.parseSourceQuery(node)
+	}
+
 	if err := p.expect("WITH"); err != nil {
 		return nil, err
 	}
@@ -377,4 +574,20 @@ func (p *Parser) parseReplace() (*ast.QueryNode, error) {
 	node.Fields = fields
 
 	return node, nil
-}
\ No newline at end of file
+}
+
+// parseSourceQuery parses the GET a write takes its rows from, after AS or FROM
+// The GET runs to the end of the query, so its clauses belong to it.
+func (p *Parser) parseSourceQuery(node *ast.QueryNode) (*ast.QueryNode, error) {
+	if strings.ToUpper(p.current().Value) != "GET" {
+		return nil, p.error("expected GET query after " + node.Operation + " " + node.Entity)
+	}
+	p.nested++
+	source, err := p.Parse()
+	p.nested--
+	if err != nil {
+		return nil, err
+	}
+	node.ViewQuery = source
+	return node, nil
+}
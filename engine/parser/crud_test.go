@@ -0,0 +1,41 @@
+package parser
+
+import "testing"
+
+// DO NOTHING skips a conflicting row, with or without a conflict target, and
+// survives a Render round trip
+func TestUpsertDoNothing(t *testing.T) {
+	tests := []struct {
+		input   string
+		targets int
+	}{
+		{`UPSERT User WITH email = "a", name = "b" ON email DO NOTHING`, 1},
+		{`UPSERT User WITH email = "a", name = "b" DO NOTHING RETURNING id`, 0},
+		{`UPSERT User WITH email = "a" ON email WHERE active = true DO NOTHING`, 1},
+		{`BULK UPSERT User WITH [email = "a"] [email = "b"] DO NOTHING`, 0},
+		{`BULK UPSERT User WITH [email = "a", org = 1] [email = "b", org = 1] ON email, org DO NOTHING`, 2},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if q.Upsert == nil || !q.Upsert.DoNothing || len(q.Upsert.UpdateFields) > 0 || len(q.Upsert.ConflictFields) != tt.targets {
+			t.Errorf("Parse(%q): Upsert = %+v", tt.input, q.Upsert)
+			continue
+		}
+		rendered, err := Render(q)
+		if err != nil {
+			t.Errorf("Render(%q): %v", tt.input, err)
+			continue
+		}
+		if rendered != tt.input {
+			t.Errorf("Render(%q) = %q", tt.input, rendered)
+		}
+	}
+
+	if _, err := Parse(`UPSERT User WITH email = "a" ON email DO UPDATE`); err == nil {
+		t.Error("expected an error for DO without NOTHING")
+	}
+}
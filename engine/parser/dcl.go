@@ -1,8 +1,11 @@
 package parser
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"github.com/omniql-engine/omniql/engine/ast"
+	"github.com/omniql-engine/omniql/engine/lexer"
 )
 
 // =============================================================================
@@ -31,6 +34,8 @@ func (p *Parser) parseDCL(op string) (*ast.QueryNode, error) {
 		return p.parseAlterUser(node)
 	case "CREATE ROLE":
 		return p.parseCreateRole(node)
+	case "ALTER ROLE":
+		return p.parseAlterRole(node)
 	case "DROP ROLE":
 		return p.parseDropRole(node)
 	case "ASSIGN ROLE":
@@ -46,7 +51,13 @@ func (p *Parser) parseDCL(op string) (*ast.QueryNode, error) {
 // DCL PARSERS
 // =============================================================================
 
-// GRANT|REVOKE permissions ON entity TO|FROM target
+// grantObjectTypes are the object kinds accepted after ON (a bare name is a table)
+var grantObjectTypes = map[string]bool{
+	"TABLE": true, "DATABASE": true, "SCHEMA": true, "SEQUENCE": true, "FUNCTION": true,
+}
+
+// GRANT|REVOKE permissions ON [object_type] entity TO|FROM target
+// object_type: TABLE, DATABASE, SCHEMA, SEQUENCE, FUNCTION, ALL TABLES IN SCHEMA
 func (p *Parser) parseGrantRevoke(node *ast.QueryNode, op string) (*ast.QueryNode, error) {
 	var perms []string
 	for !p.isAtEnd() {
@@ -63,6 +74,24 @@ func (p *Parser) parseGrantRevoke(node *ast.QueryNode, op string) (*ast.QueryNod
 		return nil, err
 	}
 
+	curUpper := strings.ToUpper(p.current().Value)
+	if curUpper == "ALL" && strings.ToUpper(p.peek(1).Value) == "TABLES" {
+		p.advance() // consume ALL
+		p.advance() // consume TABLES
+		if err := p.expect("IN"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("SCHEMA"); err != nil {
+			return nil, err
+		}
+		node.Permission.ObjectType = "ALL TABLES IN SCHEMA"
+	} else if grantObjectTypes[curUpper] && p.peek(1).Type == lexer.TOKEN_IDENTIFIER {
+		p.advance() // consume object type
+		if curUpper != "TABLE" {
+			node.Permission.ObjectType = curUpper
+		}
+	}
+
 	var entity string
 	if p.current().Value == "*" {
 		entity = "*"
@@ -86,63 +115,83 @@ func (p *Parser) parseGrantRevoke(node *ast.QueryNode, op string) (*ast.QueryNod
 		}
 	}
 
-	target, err := p.expectIdentifier()
+	target, host, err := p.parseAccountName()
 	if err != nil {
 		return nil, err
 	}
 	node.Permission.Target = target
+	node.Permission.Host = host
 
 	return node, nil
 }
 
-// CREATE USER name [WITH PASSWORD password]
-func (p *Parser) parseCreateUser(node *ast.QueryNode) (*ast.QueryNode, error) {
+// name | name@host | name@"host"
+// The host is MySQL's account host ('%' wildcards need the quoted form).
+func (p *Parser) parseAccountName() (string, string, error) {
 	name, err := p.expectIdentifier()
+	if err != nil {
+		return "", "", err
+	}
+	at := strings.LastIndex(name, "@")
+	if at == -1 {
+		return name, "", nil
+	}
+	host := name[at+1:]
+	name = name[:at]
+	if host == "" && p.current().Type == lexer.TOKEN_STRING {
+		host = p.advance().Value
+	}
+	if name == "" || host == "" {
+		return "", "", p.error("expected user@host")
+	}
+	return name, host, nil
+}
+
+// CREATE USER name[@host] [WITH PASSWORD password] [role options]
+func (p *Parser) parseCreateUser(node *ast.QueryNode) (*ast.QueryNode, error) {
+	name, host, err := p.parseAccountName()
 	if err != nil {
 		return nil, err
 	}
 	node.Permission.UserName = name
+	node.Permission.Host = host
 
-	if p.match("WITH") {
-		if p.match("PASSWORD") {
-			tok := p.advance()
-			node.Permission.Password = tok.Value
-		}
+	if err := p.parseRoleOptions(node.Permission); err != nil {
+		return nil, err
 	}
 
 	return node, nil
 }
 
-// DROP USER name
+// DROP USER name[@host]
 func (p *Parser) parseDropUser(node *ast.QueryNode) (*ast.QueryNode, error) {
-	name, err := p.expectIdentifier()
+	name, host, err := p.parseAccountName()
 	if err != nil {
 		return nil, err
 	}
 	node.Permission.UserName = name
+	node.Permission.Host = host
 
 	return node, nil
 }
 
-// ALTER USER name [WITH PASSWORD password]
+// ALTER USER name[@host] [WITH PASSWORD password] [role options]
 func (p *Parser) parseAlterUser(node *ast.QueryNode) (*ast.QueryNode, error) {
-	name, err := p.expectIdentifier()
+	name, host, err := p.parseAccountName()
 	if err != nil {
 		return nil, err
 	}
 	node.Permission.UserName = name
+	node.Permission.Host = host
 
-	if p.match("WITH") {
-		if p.match("PASSWORD") {
-			tok := p.advance()
-			node.Permission.Password = tok.Value
-		}
+	if err := p.parseRoleOptions(node.Permission); err != nil {
+		return nil, err
 	}
 
 	return node, nil
 }
 
-// CREATE ROLE name
+// CREATE ROLE name [WITH role options]
 func (p *Parser) parseCreateRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 	name, err := p.expectIdentifier()
 	if err != nil {
@@ -150,9 +199,84 @@ func (p *Parser) parseCreateRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 	}
 	node.Permission.RoleName = name
 
+	if err := p.parseRoleOptions(node.Permission); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// ALTER ROLE name [WITH] role options
+func (p *Parser) parseAlterRole(node *ast.QueryNode) (*ast.QueryNode, error) {
+	name, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.Permission.RoleName = name
+
+	if err := p.parseRoleOptions(node.Permission); err != nil {
+		return nil, err
+	}
+	if len(node.Permission.RoleFlags) == 0 && node.Permission.Password == "" &&
+		node.Permission.ValidUntil == "" && node.Permission.ConnectionLimit == "" {
+		return nil, p.error("ALTER ROLE requires at least one option")
+	}
+
 	return node, nil
 }
 
+// roleFlags are the boolean role attributes, each with its NO form
+var roleFlags = map[string]bool{
+	"LOGIN": true, "NOLOGIN": true, "SUPERUSER": true, "NOSUPERUSER": true,
+	"CREATEDB": true, "NOCREATEDB": true, "CREATEROLE": true, "NOCREATEROLE": true,
+	"INHERIT": true, "NOINHERIT": true, "REPLICATION": true, "NOREPLICATION": true,
+	"BYPASSRLS": true, "NOBYPASSRLS": true,
+}
+
+// [WITH] option [, option ...]
+// option: PASSWORD pw | VALID UNTIL 'timestamp' | CONNECTION LIMIT n | LOGIN | NOLOGIN | SUPERUSER | CREATEDB | ...
+func (p *Parser) parseRoleOptions(perm *ast.PermissionNode) error {
+	p.match("WITH")
+
+	for !p.isAtEnd() {
+		upper := strings.ToUpper(p.current().Value)
+		switch {
+		case upper == "PASSWORD":
+			p.advance() // consume PASSWORD
+			perm.Password = p.advance().Value
+		case upper == "VALID":
+			p.advance() // consume VALID
+			if err := p.expect("UNTIL"); err != nil {
+				return err
+			}
+			perm.ValidUntil = p.advance().Value
+		case upper == "CONNECTION":
+			p.advance() // consume CONNECTION
+			if err := p.expect("LIMIT"); err != nil {
+				return err
+			}
+			limit := ""
+			if p.match("-") {
+				limit = "-"
+			}
+			limit += p.current().Value
+			if _, err := strconv.Atoi(limit); err != nil {
+				return p.error(fmt.Sprintf("CONNECTION LIMIT must be an integer, got '%s'", limit))
+			}
+			p.advance()
+			perm.ConnectionLimit = limit
+		case roleFlags[upper]:
+			p.advance()
+			perm.RoleFlags = append(perm.RoleFlags, upper)
+		default:
+			return p.error(fmt.Sprintf("unknown role option '%s'", p.current().Value))
+		}
+		p.match(",")
+	}
+
+	return nil
+}
+
 // DROP ROLE name
 func (p *Parser) parseDropRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 	name, err := p.expectIdentifier()
@@ -164,7 +288,7 @@ func (p *Parser) parseDropRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 	return node, nil
 }
 
-// ASSIGN ROLE role TO user
+// ASSIGN ROLE role TO user[@host]
 func (p *Parser) parseAssignRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 	role, err := p.expectIdentifier()
 	if err != nil {
@@ -176,16 +300,17 @@ func (p *Parser) parseAssignRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 		return nil, err
 	}
 
-	user, err := p.expectIdentifier()
+	user, host, err := p.parseAccountName()
 	if err != nil {
 		return nil, err
 	}
 	node.Permission.UserName = user
+	node.Permission.Host = host
 
 	return node, nil
 }
 
-// REVOKE ROLE role FROM user
+// REVOKE ROLE role FROM user[@host]
 func (p *Parser) parseRevokeRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 	role, err := p.expectIdentifier()
 	if err != nil {
@@ -197,11 +322,12 @@ func (p *Parser) parseRevokeRole(node *ast.QueryNode) (*ast.QueryNode, error) {
 		return nil, err
 	}
 
-	user, err := p.expectIdentifier()
+	user, host, err := p.parseAccountName()
 	if err != nil {
 		return nil, err
 	}
 	node.Permission.UserName = user
+	node.Permission.Host = host
 
 	return node, nil
 }
\ No newline at end of file
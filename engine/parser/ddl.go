@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/omniql-engine/omniql/engine/ast"
+	"github.com/omniql-engine/omniql/engine/lexer"
 )
 
 
@@ -81,12 +82,16 @@ func (p *Parser) parseDDL(op string) (*ast.QueryNode, error) {
 		return p.parseCreatePolicy()
 	case "DROP POLICY":
 		return p.parseDropPolicy()
+	case "CREATE PARTITION":
+		return p.parseCreatePartition()
 	case "CREATE RULE":
 		return p.parseCreateRule()
 	case "DROP RULE":
 		return p.parseDropRule()
 	case "COMMENT ON":
 		return p.parseCommentOn()
+	case "SHOW TABLES", "SHOW COLUMNS", "SHOW INDEXES":
+		return p.parseShow(op)
 	default:
 		return nil, p.error("unimplemented DDL: " + op)
 	}
@@ -97,6 +102,7 @@ func (p *Parser) parseDDL(op string) (*ast.QueryNode, error) {
 // =============================================================================
 
 // CREATE TABLE name WITH columns
+// CREATE TABLE name AS GET ...
 func (p *Parser) parseCreateTable() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "CREATE TABLE",
@@ -110,6 +116,11 @@ func (p *Parser) parseCreateTable() (*ast.QueryNode, error) {
 	}
 	node.Entity = entity
 
+	// CREATE TABLE entity AS GET ...
+	if p.match("AS") {
+		return p.parseSourceQuery(node)
+	}
+
 	if err := p.expect("WITH"); err != nil {
 		return nil, err
 	}
@@ -120,9 +131,98 @@ func (p *Parser) parseCreateTable() (*ast.QueryNode, error) {
 	}
 	node.Fields = columns
 
+	// MySQL: ENGINE = InnoDB CHARSET = utf8mb4 ...; SQLite: STRICT, WITHOUT ROWID
+	if err := p.parseTableOptions(node); err != nil {
+		return nil, err
+	}
+
+	// PostgreSQL: PARTITION BY RANGE|LIST|HASH (key, ...); BigQuery: PARTITION BY DAY (key)
+	if p.matchPartitionBy() {
+		if err := p.parsePartitionBy(node); err != nil {
+			return nil, err
+		}
+	}
+
+	// BigQuery: CLUSTER BY key, ...
+	if p.matchClusterBy() {
+		keys, err := p.parseFieldList()
+		if err != nil {
+			return nil, err
+		}
+		node.ClusterKeys = keys
+	}
+
+	// BigQuery writes its partition options last: ... CLUSTER BY kind REQUIRE_PARTITION_FILTER = true
+	if err := p.parseTableOptions(node); err != nil {
+		return nil, err
+	}
+
 	return node, nil
 }
 
+// tableOptionNames are the dialect table options accepted after the column list
+var tableOptionNames = map[string]bool{
+	"ENGINE": true, "CHARSET": true, "COLLATE": true, "AUTO_INCREMENT": true,
+	"PARTITION_EXPIRATION_DAYS": true, "REQUIRE_PARTITION_FILTER": true,
+	"DATA_RETENTION_TIME_IN_DAYS": true, "DEFAULT_TIME_TO_LIVE": true,
+}
+
+// matchClusterBy consumes CLUSTER BY
+func (p *Parser) matchClusterBy() bool {
+	if strings.ToUpper(p.current().Value) == "CLUSTER" && strings.ToUpper(p.peek(1).Value) == "BY" {
+		p.advance()
+		p.advance()
+		return true
+	}
+	return false
+}
+
+// parseTableOptions parses: [DEFAULT] name [=] value [, ...]
+// CHARACTER SET is read as CHARSET; options are stored by upper-case name.
+// The SQLite flags STRICT and WITHOUT ROWID take no value and are stored as
+// STRICT and WITHOUT_ROWID = "true".
+func (p *Parser) parseTableOptions(node *ast.QueryNode) error {
+	for !p.isAtEnd() {
+		name := strings.ToUpper(p.current().Value)
+		next := strings.ToUpper(p.peek(1).Value)
+		if name == "STRICT" || (name == "WITHOUT" && next == "ROWID") {
+			if name == "WITHOUT" {
+				p.advance()
+				name = "WITHOUT_ROWID"
+			}
+			p.advance()
+			if node.TableOptions == nil {
+				node.TableOptions = map[string]string{}
+			}
+			node.TableOptions[name] = "true"
+			p.match(",")
+			continue
+		}
+		if name == "DEFAULT" && (tableOptionNames[next] || next == "CHARACTER") {
+			p.advance()
+			name, next = next, strings.ToUpper(p.peek(1).Value)
+		}
+		if name == "CHARACTER" && next == "SET" {
+			p.advance()
+			name = "CHARSET"
+		}
+		if !tableOptionNames[name] {
+			return nil
+		}
+		p.advance()
+		p.match("=")
+		if p.isAtEnd() {
+			return p.error("expected value for " + name)
+		}
+		if node.TableOptions == nil {
+			node.TableOptions = map[string]string{}
+		}
+		node.TableOptions[name] = p.advance().Value
+		p.match(",")
+	}
+	return nil
+}
+
 // DROP TABLE name [CASCADE]
 func (p *Parser) parseDropTable() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
@@ -195,11 +295,19 @@ func (p *Parser) parseAlterTable() (*ast.QueryNode, error) {
 			p.expect(")")
 			typ = typ + "(" + strings.Join(sizeParts, ",") + ")"
 		}
-		node.Fields = append(node.Fields, ast.FieldNode{
+		field := ast.FieldNode{
 			NameExpr:  makeFieldExpr(parts[0], pos),
 			ValueExpr: makeLiteralExpr(typ, pos),
 			Position:  pos,
-		})
+		}
+		if strings.ToUpper(p.current().Value) == "GENERATED" {
+			expr, err := p.parseGeneratedColumn()
+			if err != nil {
+				return nil, err
+			}
+			field.GeneratedExpr = expr
+		}
+		node.Fields = append(node.Fields, field)
 		case "DROP":
 			node.AlterAction = "DROP_COLUMN"
 			node.Fields = append(node.Fields, ast.FieldNode{
@@ -313,7 +421,7 @@ func (p *Parser) parseTruncate() (*ast.QueryNode, error) {
 	return node, nil
 }
 
-// CREATE INDEX table index_name:column [UNIQUE]
+// CREATE INDEX table index_name:column [UNIQUE | FULLTEXT] [SPARSE] [TTL seconds] [PARTIAL WHERE ...]
 func (p *Parser) parseCreateIndex() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "CREATE INDEX",
@@ -336,7 +444,7 @@ func (p *Parser) parseCreateIndex() (*ast.QueryNode, error) {
 	pos := indexTok.Position
 
 	if len(parts) < 2 {
-		return nil, p.error("expected index_name:column format")
+		return nil, p.errorAt(indexTok, "expected index_name:column format")
 	}
 
 	field := ast.FieldNode{
@@ -345,9 +453,36 @@ func (p *Parser) parseCreateIndex() (*ast.QueryNode, error) {
 		Position:  pos,
 	}
 
-	if !p.isAtEnd() && strings.ToUpper(p.current().Value) == "UNIQUE" {
-		p.advance()
-		field.Constraints = append(field.Constraints, "UNIQUE")
+	for !p.isAtEnd() {
+		kind := strings.ToUpper(p.current().Value)
+		switch kind {
+		case "UNIQUE", "FULLTEXT", "SPARSE":
+			p.advance()
+			field.Constraints = append(field.Constraints, kind)
+			continue
+		case "TTL":
+			// TTL seconds: stored as "TTL", "seconds" (like DEFAULT value)
+			p.advance()
+			secondsTok := p.current()
+			if secondsTok.Type != lexer.TOKEN_NUMBER || strings.ContainsAny(secondsTok.Value, "-.") {
+				return nil, p.errorAt(secondsTok, "TTL expects a whole number of seconds")
+			}
+			p.advance()
+			field.Constraints = append(field.Constraints, kind, secondsTok.Value)
+			continue
+		case "PARTIAL":
+			// PARTIAL WHERE conditions: only matching rows are indexed
+			p.advance()
+			if strings.ToUpper(p.current().Value) != "WHERE" {
+				return nil, p.error("expected WHERE after PARTIAL")
+			}
+			if err := p.parseWhereClause(node); err != nil {
+				return nil, err
+			}
+			field.Constraints = append(field.Constraints, kind)
+			continue
+		}
+		break
 	}
 
 	node.Fields = append(node.Fields, field)
@@ -355,7 +490,7 @@ func (p *Parser) parseCreateIndex() (*ast.QueryNode, error) {
 	return node, nil
 }
 
-// DROP INDEX table index_name
+// DROP INDEX table index_name [FULLTEXT]
 func (p *Parser) parseDropIndex() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "DROP INDEX",
@@ -375,16 +510,22 @@ func (p *Parser) parseDropIndex() (*ast.QueryNode, error) {
 		if err != nil {
 			return nil, err
 		}
-		node.Fields = append(node.Fields, ast.FieldNode{
+		field := ast.FieldNode{
 			NameExpr: makeFieldExpr(name, nameTok.Position),
 			Position: nameTok.Position,
-		})
+		}
+		if !p.isAtEnd() && strings.ToUpper(p.current().Value) == "FULLTEXT" {
+			p.advance()
+			field.Constraints = append(field.Constraints, "FULLTEXT")
+		}
+		node.Fields = append(node.Fields, field)
 	}
 
 	return node, nil
 }
 
-// CREATE DATABASE name
+// CREATE DATABASE name [FILE "path"]
+// FILE names the database file SQLite attaches under name
 func (p *Parser) parseCreateDatabase() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "CREATE DATABASE",
@@ -398,6 +539,16 @@ func (p *Parser) parseCreateDatabase() (*ast.QueryNode, error) {
 	}
 	node.DatabaseName = name
 
+	if !p.isAtEnd() && strings.ToUpper(p.current().Value) == "FILE" {
+		p.advance()
+		tok := p.current()
+		if tok.Type != lexer.TOKEN_STRING {
+			return nil, p.error("expected file path string after FILE")
+		}
+		p.advance()
+		node.DatabaseFile = tok.Value
+	}
+
 	return node, nil
 }
 
@@ -441,7 +592,9 @@ func (p *Parser) parseCreateView() (*ast.QueryNode, error) {
 		return nil, err
 	}
 
+	p.nested++
 	viewQuery, err := p.Parse()
+	p.nested--
 	if err != nil {
 		return nil, err
 	}
@@ -499,6 +652,32 @@ func (p *Parser) parseRenameTable() (*ast.QueryNode, error) {
 	return node, nil
 }
 
+// SHOW TABLES
+// SHOW COLUMNS FROM entity
+// SHOW INDEXES FROM entity
+func (p *Parser) parseShow(op string) (*ast.QueryNode, error) {
+	node := &ast.QueryNode{
+		Operation: op,
+		Position:  p.current().Position,
+	}
+	p.advance() // consume SHOW ...
+
+	if op == "SHOW TABLES" {
+		return node, nil
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	entity, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.Entity = entity
+
+	return node, nil
+}
+
 // ALTER VIEW name AS query
 func (p *Parser) parseAlterView() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
@@ -517,7 +696,9 @@ func (p *Parser) parseAlterView() (*ast.QueryNode, error) {
 		return nil, err
 	}
 
+	p.nested++
 	viewQuery, err := p.Parse()
+	p.nested--
 	if err != nil {
 		return nil, err
 	}
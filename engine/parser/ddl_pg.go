@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -780,4 +781,130 @@ func (p *Parser) parseCommentOn() (*ast.QueryNode, error) {
 	node.CommentTarget = strings.Join(targetParts, " ")
 
 	return node, nil
-}
\ No newline at end of file
+}
+// matchPartitionBy consumes PARTITION BY (one token when uppercase, two otherwise)
+func (p *Parser) matchPartitionBy() bool {
+	if strings.ToUpper(p.current().Value) == "PARTITION BY" {
+		p.advance()
+		return true
+	}
+	if strings.ToUpper(p.current().Value) == "PARTITION" && strings.ToUpper(p.peek(1).Value) == "BY" {
+		p.advance()
+		p.advance()
+		return true
+	}
+	return false
+}
+
+// PARTITION BY RANGE|LIST|HASH (key, ...) - PARTITION BY already consumed
+// BigQuery partitions by a time unit of one column: PARTITION BY DAY (created_at)
+func (p *Parser) parsePartitionBy(node *ast.QueryNode) error {
+	strategy := strings.ToUpper(p.current().Value)
+	switch strategy {
+	case "RANGE", "LIST", "HASH", "DAY", "HOUR", "MONTH", "YEAR":
+		p.advance()
+	default:
+		return p.error(fmt.Sprintf("expected RANGE, LIST, HASH or a time unit (DAY, HOUR, MONTH, YEAR) after PARTITION BY, got '%s'", p.current().Value))
+	}
+	node.PartitionStrategy = strategy
+
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	keys, err := p.parseFieldList()
+	if err != nil {
+		return err
+	}
+	node.PartitionKeys = keys
+	return p.expect(")")
+}
+
+// CREATE PARTITION name OF Entity FOR VALUES FROM (a) TO (b)
+// CREATE PARTITION name OF Entity FOR VALUES IN (a, b)
+// CREATE PARTITION name OF Entity FOR VALUES WITH (MODULUS m, REMAINDER r)
+// CREATE PARTITION name OF Entity DEFAULT
+func (p *Parser) parseCreatePartition() (*ast.QueryNode, error) {
+	node := &ast.QueryNode{
+		Operation: "CREATE PARTITION",
+		Position:  p.current().Position,
+	}
+	p.advance()
+
+	name, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.PartitionName = name
+
+	if err := p.expect("OF"); err != nil {
+		return nil, err
+	}
+	parent, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.Entity = parent
+
+	if p.match("DEFAULT") {
+		node.PartitionDefault = true
+		return node, nil
+	}
+
+	if err := p.expect("FOR"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("VALUES"); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToUpper(p.current().Value) {
+	case "FROM":
+		p.advance()
+		if node.PartitionFrom, err = p.parseInValues(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("TO"); err != nil {
+			return nil, err
+		}
+		if node.PartitionTo, err = p.parseInValues(); err != nil {
+			return nil, err
+		}
+	case "IN":
+		p.advance()
+		if node.PartitionIn, err = p.parseInValues(); err != nil {
+			return nil, err
+		}
+	case "WITH":
+		p.advance()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for !p.isAtEnd() && p.current().Value != ")" {
+			key := strings.ToUpper(p.advance().Value)
+			val, err := strconv.ParseInt(p.current().Value, 10, 64)
+			if err != nil {
+				return nil, p.error(fmt.Sprintf("expected number after %s, got '%s'", key, p.current().Value))
+			}
+			p.advance()
+			switch key {
+			case "MODULUS":
+				node.PartitionModulus = val
+			case "REMAINDER":
+				node.PartitionRemainder = val
+			default:
+				return nil, p.error(fmt.Sprintf("expected MODULUS or REMAINDER, got '%s'", key))
+			}
+			p.match(",")
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if node.PartitionModulus <= 0 {
+			return nil, p.error("hash partition requires MODULUS > 0")
+		}
+	default:
+		return nil, p.error(fmt.Sprintf("expected FROM, IN or WITH after FOR VALUES, got '%s'", p.current().Value))
+	}
+
+	return node, nil
+}
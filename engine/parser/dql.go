@@ -6,6 +6,7 @@ import (
 
 	"github.com/omniql-engine/omniql/engine/ast"
 	"github.com/omniql-engine/omniql/engine/lexer"
+	"github.com/omniql-engine/omniql/mapping"
 )
 
 // =============================================================================
@@ -15,7 +16,7 @@ import (
 func (p *Parser) parseDQL(op string) (*ast.QueryNode, error) {
 	switch op {
 	// Aggregates
-	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+	case "COUNT", "SUM", "AVG", "MIN", "MAX", "STRING AGG":
 		return p.parseAggregate(op)
 	// Joins
 	case "INNER JOIN", "LEFT JOIN", "RIGHT JOIN", "FULL JOIN", "CROSS JOIN":
@@ -57,7 +58,7 @@ func (p *Parser) parseAggregate(op string) (*ast.QueryNode, error) {
 	p.advance() // consume aggregate function
 
 	// Optional field (COUNT can be COUNT *)
-	if p.current().Value != "FROM" && p.current().Value != "*" {
+	if p.current().Value != "FROM" && p.current().Value != "*" && strings.ToUpper(p.current().Value) != "OVER" {
 		pos := p.current().Position
 		field, err := p.expectIdentifier()
 		if err != nil {
@@ -69,6 +70,24 @@ func (p *Parser) parseAggregate(op string) (*ast.QueryNode, error) {
 		node.Aggregate.FieldExpr = makeFieldExpr("*", p.current().Position)
 	}
 
+	// SUM amount OVER (...) is a window function: running totals, moving averages
+	if op != "STRING AGG" && strings.ToUpper(p.current().Value) == "OVER" {
+		window := ast.WindowNode{
+			Function:  op,
+			FieldExpr: node.Aggregate.FieldExpr,
+			Position:  node.Aggregate.Position,
+		}
+		node.Aggregate = nil
+		return p.parseWindowOver(node, window)
+	}
+
+	// STRING AGG name [ORDER BY field [DESC], ...] [SEPARATOR ", "] FROM ...
+	if op == "STRING AGG" {
+		if err := p.parseStringAggOptions(node.Aggregate); err != nil {
+			return nil, err
+		}
+	}
+
 	// FROM
 	if err := p.expect("FROM"); err != nil {
 		return nil, err
@@ -80,6 +99,10 @@ func (p *Parser) parseAggregate(op string) (*ast.QueryNode, error) {
 	}
 	node.Entity = entity
 
+	if err := p.parseTableModifiers(node); err != nil {
+		return nil, err
+	}
+
 	// Optional clauses
 	if err := p.parseClauses(node); err != nil {
 		return nil, err
@@ -88,6 +111,105 @@ func (p *Parser) parseAggregate(op string) (*ast.QueryNode, error) {
 	return node, nil
 }
 
+// parseStringAggOptions parses the ordering and separator inside STRING AGG
+// The separator defaults to "," when omitted.
+func (p *Parser) parseStringAggOptions(agg *ast.AggregateNode) error {
+	agg.Separator = ","
+
+	cur := strings.ToUpper(p.current().Value)
+	if cur == "ORDER" || cur == "ORDER BY" {
+		// Collect into a scratch node so the outer ORDER BY stays free
+		scratch := &ast.QueryNode{}
+		if err := p.parseOrderByClause(scratch); err != nil {
+			return err
+		}
+		agg.OrderBy = scratch.OrderBy
+	}
+
+	if strings.ToUpper(p.current().Value) == "SEPARATOR" {
+		p.advance() // consume SEPARATOR
+		tok := p.current()
+		if tok.Type != lexer.TOKEN_STRING {
+			return p.error("expected separator string after SEPARATOR")
+		}
+		p.advance()
+		agg.Separator = tok.Value
+	}
+	return nil
+}
+
+// parseFacetClause parses: FACET name (AGG [field|*] [GROUP BY field, ...]), ...
+// Each facet is aggregated over the same filtered rows as the main aggregate,
+// which keeps its own GROUP BY, HAVING and ORDER BY.
+func (p *Parser) parseFacetClause(node *ast.QueryNode) error {
+	if node.Aggregate == nil {
+		return p.error("FACET requires an aggregate (COUNT, SUM, AVG, MIN, MAX, STRING AGG)")
+	}
+	p.advance() // consume FACET
+
+	seen := map[string]bool{}
+	for {
+		facet := ast.FacetNode{Position: p.current().Position}
+		name, err := p.expectIdentifier()
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(name, "result") {
+			return p.error("facet name 'result' is reserved for the main aggregate")
+		}
+		if seen[strings.ToLower(name)] {
+			return p.error("duplicate facet name '" + name + "'")
+		}
+		seen[strings.ToLower(name)] = true
+		facet.Name = name
+
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		fn := strings.ToUpper(p.current().Value)
+		if !mapping.IsAggregate(fn) {
+			return p.error("expected aggregate function in FACET, got '" + p.current().Value + "'")
+		}
+		facet.Aggregate = &ast.AggregateNode{Function: fn, Position: p.current().Position}
+		p.advance() // consume aggregate function
+
+		// Optional field (COUNT can be COUNT *)
+		if p.match("*") {
+			facet.Aggregate.FieldExpr = makeFieldExpr("*", facet.Aggregate.Position)
+		} else if p.current().Value != ")" && p.current().Type != lexer.TOKEN_CLAUSE {
+			pos := p.current().Position
+			field, err := p.expectIdentifier()
+			if err != nil {
+				return err
+			}
+			facet.Aggregate.FieldExpr = makeFieldExpr(field, pos)
+		}
+		if fn == "STRING AGG" {
+			if err := p.parseStringAggOptions(facet.Aggregate); err != nil {
+				return err
+			}
+		}
+
+		cur := strings.ToUpper(p.current().Value)
+		if cur == "GROUP BY" || (cur == "GROUP" && strings.ToUpper(p.peek(1).Value) == "BY") {
+			scratch := &ast.QueryNode{}
+			if err := p.parseGroupByClause(scratch); err != nil {
+				return err
+			}
+			facet.GroupBy = scratch.GroupBy
+		}
+		if err := p.expect(")"); err != nil {
+			return err
+		}
+		node.Facets = append(node.Facets, facet)
+
+		if !p.match(",") {
+			break
+		}
+	}
+	return nil
+}
+
 // INNER JOIN|LEFT JOIN|... entity1 entity2 ON field1 = field2
 func (p *Parser) parseJoin(op string) (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
@@ -140,8 +262,9 @@ func (p *Parser) parseJoin(op string) (*ast.QueryNode, error) {
 	return node, nil
 }
 
-// UNION|UNION ALL|INTERSECT|EXCEPT (query1) (query2)
+// UNION|UNION ALL|INTERSECT|EXCEPT (query1) (query2) [ORDER BY ...] [LIMIT n] [OFFSET n]
 // Format: UNION (GET User WHERE age > 25) (GET User WHERE status = 'active')
+// Trailing ORDER BY/LIMIT/OFFSET apply to the combined result
 func (p *Parser) parseSetOperation(op string) (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: op,
@@ -183,6 +306,24 @@ func (p *Parser) parseSetOperation(op string) (*ast.QueryNode, error) {
 		return nil, err
 	}
 
+	// Outer ORDER BY / LIMIT / OFFSET
+	for !p.isAtEnd() {
+		var err error
+		switch strings.ToUpper(p.current().Value) {
+		case "ORDER", "ORDER BY":
+			err = p.parseOrderByClause(node)
+		case "LIMIT":
+			err = p.parseLimitClause(node)
+		case "OFFSET":
+			err = p.parseOffsetClause(node)
+		default:
+			return node, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return node, nil
 }
 
@@ -191,6 +332,8 @@ func (p *Parser) parseSetOperation(op string) (*ast.QueryNode, error) {
 // =============================================================================
 
 // ROW NUMBER|RANK|DENSE RANK|LAG|LEAD|NTILE OVER (PARTITION BY field ORDER BY field)
+// LAG|LEAD field [offset] [DEFAULT value] OVER (...)
+// NTILE buckets OVER (...)
 func (p *Parser) parseWindowFunction(op string) (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: op,
@@ -210,6 +353,21 @@ func (p *Parser) parseWindowFunction(op string) (*ast.QueryNode, error) {
 			field, _ := p.expectIdentifier()
 			window.FieldExpr = makeFieldExpr(field, pos)
 		}
+		if p.current().Type == lexer.TOKEN_NUMBER {
+			tok := p.advance()
+			offset, err := strconv.Atoi(tok.Value)
+			if err != nil || offset < 1 {
+				return nil, p.error(op + " offset must be a positive integer")
+			}
+			window.Offset = offset
+		}
+		if p.match("DEFAULT") {
+			value, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			window.Default = value
+		}
 	}
 
 	// NTILE requires bucket count
@@ -221,17 +379,22 @@ func (p *Parser) parseWindowFunction(op string) (*ast.QueryNode, error) {
 		}
 	}
 
+	return p.parseWindowOver(node, window)
+}
+
+// parseWindowOver parses the rest of a window function after its arguments:
+// [OVER ([PARTITION BY ...] [ORDER BY ...] [frame])] [AS alias] [FROM entity]
+func (p *Parser) parseWindowOver(node *ast.QueryNode, window ast.WindowNode) (*ast.QueryNode, error) {
 	// OVER clause
 	if p.match("OVER") {
 		if err := p.expect("("); err != nil {
 			return nil, err
 		}
 
-		// PARTITION BY (100% TrueAST)
-		if p.match("PARTITION") {
-			p.expect("BY")
+		// PARTITION BY (100% TrueAST); the lexer may join the keywords into one token
+		if p.match("PARTITION BY") || p.match("PARTITION") && p.expect("BY") == nil {
 			for !p.isAtEnd() {
-				if strings.ToUpper(p.current().Value) == "ORDER" || p.current().Value == ")" {
+				if upper := strings.ToUpper(p.current().Value); upper == "ORDER" || upper == "ORDER BY" || p.current().Value == ")" || isWindowFrameUnit(upper) {
 					break
 				}
 				pos := p.current().Position
@@ -245,9 +408,8 @@ func (p *Parser) parseWindowFunction(op string) (*ast.QueryNode, error) {
 		}
 
 		// ORDER BY (100% TrueAST)
-		if p.match("ORDER") {
-			p.expect("BY")
-			for !p.isAtEnd() && p.current().Value != ")" {
+		if p.match("ORDER BY") || p.match("ORDER") && p.expect("BY") == nil {
+			for !p.isAtEnd() && p.current().Value != ")" && !isWindowFrameUnit(p.current().Value) {
 				pos := p.current().Position
 				field, err := p.expectIdentifier()
 				if err != nil {
@@ -268,7 +430,25 @@ func (p *Parser) parseWindowFunction(op string) (*ast.QueryNode, error) {
 			}
 		}
 
-		p.expect(")")
+		// ROWS | RANGE frame
+		if isWindowFrameUnit(p.current().Value) {
+			if err := p.parseWindowFrame(&window); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	// AS alias
+	if p.match("AS") {
+		alias, err := p.expectIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		window.Alias = alias
 	}
 
 	node.WindowFunctions = append(node.WindowFunctions, window)
@@ -282,15 +462,84 @@ func (p *Parser) parseWindowFunction(op string) (*ast.QueryNode, error) {
 		node.Entity = entity
 	}
 
+	// Optional clauses (WHERE ...)
+	if err := p.parseClauses(node); err != nil {
+		return nil, err
+	}
+
 	return node, nil
 }
 
+// isWindowFrameUnit reports whether value starts a window frame
+func isWindowFrameUnit(value string) bool {
+	upper := strings.ToUpper(value)
+	return upper == "ROWS" || upper == "RANGE"
+}
+
+// parseWindowFrame parses ROWS|RANGE BETWEEN start AND end, or ROWS|RANGE start
+// (which ends at CURRENT ROW). Bounds are kept in their SQL spelling.
+func (p *Parser) parseWindowFrame(window *ast.WindowNode) error {
+	window.FrameUnit = strings.ToUpper(p.advance().Value)
+	if !p.match("BETWEEN") {
+		start, err := p.parseFrameBound()
+		if err != nil {
+			return err
+		}
+		window.FrameStart, window.FrameEnd = start, "CURRENT ROW"
+		return nil
+	}
+	start, err := p.parseFrameBound()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("AND"); err != nil {
+		return err
+	}
+	end, err := p.parseFrameBound()
+	if err != nil {
+		return err
+	}
+	window.FrameStart, window.FrameEnd = start, end
+	return nil
+}
+
+// parseFrameBound parses UNBOUNDED PRECEDING|FOLLOWING, n PRECEDING|FOLLOWING or CURRENT ROW
+func (p *Parser) parseFrameBound() (string, error) {
+	tok := p.current()
+	switch {
+	case strings.ToUpper(tok.Value) == "CURRENT":
+		p.advance()
+		if err := p.expect("ROW"); err != nil {
+			return "", err
+		}
+		return "CURRENT ROW", nil
+	case strings.ToUpper(tok.Value) == "UNBOUNDED":
+		p.advance()
+	case tok.Type == lexer.TOKEN_NUMBER:
+		if n, err := strconv.Atoi(tok.Value); err != nil || n < 0 {
+			return "", p.error("frame offset must be a non-negative integer")
+		}
+		p.advance()
+	default:
+		return "", p.error("expected UNBOUNDED, CURRENT ROW or an offset in window frame")
+	}
+	direction := strings.ToUpper(p.current().Value)
+	if direction != "PRECEDING" && direction != "FOLLOWING" {
+		return "", p.error("expected PRECEDING or FOLLOWING in window frame")
+	}
+	p.advance()
+	return strings.ToUpper(tok.Value) + " " + direction, nil
+}
+
 // =============================================================================
 // ADVANCED QUERIES
 // =============================================================================
 
-// CTE: WITH name AS (query)
+// CTE: WITH name AS (query) [main query]
 // Format: CTE temp_users AS (GET User WHERE active = true)
+// Format: CTE temp_users AS (GET User WHERE active = true) GET temp_users WHERE age > 18 LIMIT 10
+// Format: CTE RECURSIVE tree AS (UNION ALL (GET Category WHERE parent_id IS NULL) (INNER JOIN Category tree ON parent_id = id))
+// Without a main query the CTE is selected in full (SELECT * FROM name)
 func (p *Parser) parseCTE() (*ast.QueryNode, error) {
 	node := &ast.QueryNode{
 		Operation: "CTE",
@@ -298,6 +547,11 @@ func (p *Parser) parseCTE() (*ast.QueryNode, error) {
 	}
 	p.advance() // consume CTE/WITH
 
+	if strings.ToUpper(p.current().Value) == "RECURSIVE" {
+		p.advance()
+		node.Recursive = true
+	}
+
 	// CTE name
 	name, err := p.expectIdentifier()
 	if err != nil {
@@ -324,6 +578,26 @@ func (p *Parser) parseCTE() (*ast.QueryNode, error) {
 		return nil, err
 	}
 
+	// Optional DEPTH n: how many levels below the anchor a recursive CTE follows
+	if node.Recursive && strings.ToUpper(p.current().Value) == "DEPTH" {
+		p.advance() // consume DEPTH
+		tok := p.advance()
+		depth, err := strconv.Atoi(tok.Value)
+		if err != nil || depth < 1 {
+			return nil, p.errorAt(tok, "DEPTH requires a positive integer")
+		}
+		node.MaxDepth = depth
+	}
+
+	// Optional main query (100% TrueAST)
+	if !p.isAtEnd() && strings.ToUpper(p.current().Value) == "GET" {
+		mainQuery, err := p.parseNested()
+		if err != nil {
+			return nil, err
+		}
+		node.MainQuery = mainQuery
+	}
+
 	return node, nil
 }
 
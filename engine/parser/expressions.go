@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/omniql-engine/omniql/mapping"
@@ -12,7 +13,8 @@ import (
 // EXPRESSION PARSING (100% TrueAST)
 // Grammar: expression = term (('+' | '-') term)*
 //          term       = factor (('*' | '/') factor)*
-//          factor     = primary | '(' expression ')'
+//          factor     = path | '(' expression ')'
+//          path       = primary (('->' | '->>') primary)*
 //          primary    = identifier | number | string | function_call
 // =============================================================================
 
@@ -93,14 +95,14 @@ func (p *Parser) parseAdditive() (*ast.ExpressionNode, error) {
 
 // parseMultiplicative parses: factor (('*' | '/') factor)*
 func (p *Parser) parseMultiplicative() (*ast.ExpressionNode, error) {
-	left, err := p.parsePrimary()
+	left, err := p.parseJSONPath()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match("*", "/", "%") {
 		op := p.tokens[p.pos-1].Value
-		right, err := p.parsePrimary()
+		right, err := p.parseJSONPath()
 		if err != nil {
 			return nil, err
 		}
@@ -115,6 +117,39 @@ func (p *Parser) parseMultiplicative() (*ast.ExpressionNode, error) {
 	return left, nil
 }
 
+// parseJSONPath parses: primary (('->' | '->>') key)*
+// data->'address'->>'city' nests left to right
+func (p *Parser) parseJSONPath() (*ast.ExpressionNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseJSONPathKeys(left)
+}
+
+// parseJSONPathKeys applies any ('->' | '->>') key steps that follow left
+func (p *Parser) parseJSONPathKeys(left *ast.ExpressionNode) (*ast.ExpressionNode, error) {
+	for mapping.IsJSONPathOperator(p.current().Value) {
+		op := p.advance().Value
+		keyTok := p.current()
+		if keyTok.Type != lexer.TOKEN_STRING && keyTok.Type != lexer.TOKEN_NUMBER {
+			return nil, p.error(fmt.Sprintf("expected JSON key or array index after '%s'", op))
+		}
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.ExpressionNode{
+			Type:     "JSON_PATH",
+			Left:     left,
+			Operator: op,
+			Right:    right,
+			Position: left.Position,
+		}
+	}
+	return left, nil
+}
+
 // parsePrimary parses: identifier | number | string | function | '(' expr ')'
 func (p *Parser) parsePrimary() (*ast.ExpressionNode, error) {
 	tok := p.current()
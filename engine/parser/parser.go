@@ -14,6 +14,8 @@ import (
 type Parser struct {
 	tokens []lexer.Token
 	pos    int
+	schema SchemaProvider // Optional - enables column validation (see ParseWithSchema)
+	nested int            // Depth of the nested query being parsed (0 = top level)
 }
 
 // Parse is the package-level entry point for parsing OQL
@@ -75,6 +77,8 @@ func (p *Parser) Parse() (*ast.QueryNode, error) {
         node, err = p.parseTCL(op)
     case "DCL":
         node, err = p.parseDCL(op)
+    case "PUBSUB":
+        node, err = p.parsePubSub(op)
     default:
         return nil, p.error(fmt.Sprintf("unknown group '%s'", group))
     }
@@ -87,7 +91,7 @@ func (p *Parser) Parse() (*ast.QueryNode, error) {
     if !p.isAtEnd() {
         tok := p.current()
         suggestion := lexer.SuggestSimilar(tok.Value)
-        if suggestion != "" {
+        if suggestion != "" && !strings.EqualFold(suggestion, tok.Value) {
             return nil, p.error(fmt.Sprintf("unexpected '%s'. Did you mean '%s'?", tok.Value, suggestion))
         }
         return nil, p.error(fmt.Sprintf("unexpected '%s' after %s statement", tok.Value, op))
@@ -103,6 +107,9 @@ func (p *Parser) parseNested() (*ast.QueryNode, error) {
         return nil, p.error("empty nested query")
     }
 
+    p.nested++
+    defer func() { p.nested-- }()
+
     op := strings.ToUpper(p.current().Value)
 
     group, exists := mapping.OperationGroups[op]
@@ -121,6 +128,8 @@ func (p *Parser) parseNested() (*ast.QueryNode, error) {
         return p.parseTCL(op)
     case "DCL":
         return p.parseDCL(op)
+    case "PUBSUB":
+        return p.parsePubSub(op)
     default:
         return nil, p.error(fmt.Sprintf("unknown group '%s'", group))
     }
@@ -131,8 +140,13 @@ func (p *Parser) parseNested() (*ast.QueryNode, error) {
 // =============================================================================
 
 // current returns current token without advancing
+// Past the end it returns the trailing EOF token so errors keep a position
 func (p *Parser) current() lexer.Token {
 	if p.pos >= len(p.tokens) {
+		if len(p.tokens) > 0 {
+			last := p.tokens[len(p.tokens)-1]
+			return lexer.Token{Type: lexer.TOKEN_EOF, Position: last.Position, Line: last.Line, Column: last.Column}
+		}
 		return lexer.Token{Type: lexer.TOKEN_EOF}
 	}
 	return p.tokens[p.pos]
@@ -207,19 +221,17 @@ func (p *Parser) isClause() bool {
 
 // error creates parse error at current position
 func (p *Parser) error(message string) error {
-	tok := p.current()
-	return &lexer.ParseError{
-		Message:  message,
-		Position: tok.Position,
-		Line:     tok.Line,
-		Column:   tok.Column,
-		Token:    tok.Value,
-	}
+	return p.errorAt(p.current(), message)
+}
+
+// errorAt creates parse error at an already consumed token
+func (p *Parser) errorAt(tok lexer.Token, message string) error {
+	return lexer.NewParseError(tok, message)
 }
 
 // errorWithSuggestion adds "did you mean" suggestion
 func (p *Parser) errorWithSuggestion(unknown string) error {
-	suggestion := lexer.SuggestSimilar(unknown)
+	suggestion := lexer.SuggestOperation(unknown)
 	msg := fmt.Sprintf("unknown operation '%s'", unknown)
 	if suggestion != "" {
 		msg += fmt.Sprintf(". Did you mean '%s'?", suggestion)
@@ -320,7 +332,16 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 		Operation:    node.Operation,
 		Entity:       node.Entity,
 		Distinct:     node.Distinct,
+		Lock:         node.Lock,
+		LockWait:     node.LockWait,
+		Collation:    node.Collation,
+		CollationStrength: node.CollationStrength,
+		AsOf:         node.AsOf,
+		Before:       node.Before,
+		Final:        node.Final,
+		Sample:       node.Sample,
 		DatabaseName: node.DatabaseName,
+		DatabaseFile: node.DatabaseFile,
 		ViewName:     node.ViewName,
 		NewName:      node.NewName,
 		AlterAction:  node.AlterAction,
@@ -367,6 +388,35 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 		CommentTarget:     node.CommentTarget,
 		CommentText:       node.CommentText,
 		Cascade:           node.Cascade,
+
+		// PostgreSQL partitioning
+		PartitionStrategy:  node.PartitionStrategy,
+		PartitionName:      node.PartitionName,
+		PartitionModulus:   node.PartitionModulus,
+		PartitionRemainder: node.PartitionRemainder,
+		PartitionDefault:   node.PartitionDefault,
+
+		TableOptions: node.TableOptions,
+
+		Channel: node.Channel,
+		Payload: node.Payload,
+	}
+
+	// Partition keys and bounds (100% TrueAST)
+	for _, key := range node.PartitionKeys {
+		q.PartitionKeys = append(q.PartitionKeys, astExprToModelExpr(key))
+	}
+	for _, key := range node.ClusterKeys {
+		q.ClusterKeys = append(q.ClusterKeys, astExprToModelExpr(key))
+	}
+	for _, v := range node.PartitionFrom {
+		q.PartitionFrom = append(q.PartitionFrom, astExprToModelExpr(v))
+	}
+	for _, v := range node.PartitionTo {
+		q.PartitionTo = append(q.PartitionTo, astExprToModelExpr(v))
+	}
+	for _, v := range node.PartitionIn {
+		q.PartitionIn = append(q.PartitionIn, astExprToModelExpr(v))
 	}
 
 	// Columns (100% TrueAST)
@@ -387,8 +437,13 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 	// CTE (100% TrueAST) - convert ViewName/ViewQuery to CTE struct for CTE operations
 	if node.Operation == "CTE" && node.ViewName != "" && node.ViewQuery != nil {
 		q.CTE = &models.CTE{
-			Name:  node.ViewName,
-			Query: nodeToQuery(node.ViewQuery),
+			Name:      node.ViewName,
+			Query:     nodeToQuery(node.ViewQuery),
+			Recursive: node.Recursive,
+			MaxDepth:  node.MaxDepth,
+		}
+		if node.MainQuery != nil {
+			q.CTE.MainQuery = nodeToQuery(node.MainQuery)
 		}
 	}
 
@@ -434,12 +489,19 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 		q.Offset = *node.Offset
 	}
 
+	// Page/Size (already desugared into Limit/Offset, kept for executors)
+	if node.Page != nil && node.Limit != nil {
+		q.Page = *node.Page
+		q.PageSize = *node.Limit
+	}
+
 	// Fields (100% TrueAST)
 	for _, f := range node.Fields {
 		q.Fields = append(q.Fields, models.Field{
-			NameExpr:    astExprToModelExpr(f.NameExpr),
-			ValueExpr:   astExprToModelExpr(f.ValueExpr),
-			Constraints: f.Constraints,
+			NameExpr:      astExprToModelExpr(f.NameExpr),
+			ValueExpr:     astExprToModelExpr(f.ValueExpr),
+			Constraints:   f.Constraints,
+			GeneratedExpr: astExprToModelExpr(f.GeneratedExpr),
 		})
 	}
 
@@ -450,6 +512,25 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 		}
 	}
 
+	// AFTER cursor (keyset pagination) - desugared to WHERE seek predicate
+	if len(node.After) > 0 {
+		seek := keysetConditions(node.OrderBy, node.After)
+		if len(q.Conditions) > 0 {
+			// Group both sides so OR inside either one can't leak across
+			q.Conditions = []models.Condition{
+				*conditionNodeToModel(ast.ConditionNode{Operator: "GROUP", Nested: node.Conditions.Conditions}),
+				*conditionNodeToModel(ast.ConditionNode{Operator: "GROUP", Nested: seek, Logic: "AND"}),
+			}
+		} else {
+			for _, c := range seek {
+				q.Conditions = append(q.Conditions, *conditionNodeToModel(c))
+			}
+		}
+		for _, v := range node.After {
+			q.After = append(q.After, astExprToModelExpr(v))
+		}
+	}
+
 	// Having (100% TrueAST)
 	for _, c := range node.Having {
 		q.Having = append(q.Having, *conditionNodeToModel(c))
@@ -478,17 +559,50 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 		q.Aggregate = &models.Aggregation{
 			Function:  models.AggregateFunc(node.Aggregate.Function),
 			FieldExpr: astExprToModelExpr(node.Aggregate.FieldExpr),
+			Separator: node.Aggregate.Separator,
+		}
+		for _, ob := range node.Aggregate.OrderBy {
+			q.Aggregate.OrderBy = append(q.Aggregate.OrderBy, models.OrderBy{
+				FieldExpr: astExprToModelExpr(ob.FieldExpr),
+				Direction: models.SortDirection(ob.Direction),
+			})
+		}
+	}
+
+	// Facets (100% TrueAST)
+	for _, f := range node.Facets {
+		facet := models.Facet{
+			Name: f.Name,
+			Aggregate: &models.Aggregation{
+				Function:  models.AggregateFunc(f.Aggregate.Function),
+				FieldExpr: astExprToModelExpr(f.Aggregate.FieldExpr),
+				Separator: f.Aggregate.Separator,
+			},
 		}
+		for _, ob := range f.Aggregate.OrderBy {
+			facet.Aggregate.OrderBy = append(facet.Aggregate.OrderBy, models.OrderBy{
+				FieldExpr: astExprToModelExpr(ob.FieldExpr),
+				Direction: models.SortDirection(ob.Direction),
+			})
+		}
+		for _, g := range f.GroupBy {
+			facet.GroupBy = append(facet.GroupBy, astExprToModelExpr(g))
+		}
+		q.Facets = append(q.Facets, facet)
 	}
 
 	// WindowFunctions (100% TrueAST) - cast string to WindowFunc
 	for _, wf := range node.WindowFunctions {
 		mwf := models.WindowFunction{
-			Function:  models.WindowFunc(wf.Function),
-			FieldExpr: astExprToModelExpr(wf.FieldExpr),
-			Alias:     wf.Alias,
-			Offset:    wf.Offset,
-			Buckets:   wf.Buckets,
+			Function:   models.WindowFunc(wf.Function),
+			FieldExpr:  astExprToModelExpr(wf.FieldExpr),
+			Alias:      wf.Alias,
+			Offset:     wf.Offset,
+			Buckets:    wf.Buckets,
+			Default:    astExprToModelExpr(wf.Default),
+			FrameUnit:  wf.FrameUnit,
+			FrameStart: wf.FrameStart,
+			FrameEnd:   wf.FrameEnd,
 		}
 		for _, pb := range wf.PartitionBy {
 			mwf.PartitionBy = append(mwf.PartitionBy, astExprToModelExpr(pb))
@@ -504,10 +618,13 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 
 	// Upsert (100% TrueAST)
 	if node.Upsert != nil {
-		q.Upsert = &models.Upsert{}
+		q.Upsert = &models.Upsert{ConflictConstraint: node.Upsert.ConflictConstraint, DoNothing: node.Upsert.DoNothing}
 		for _, cf := range node.Upsert.ConflictFields {
 			q.Upsert.ConflictFields = append(q.Upsert.ConflictFields, astExprToModelExpr(cf))
 		}
+		for _, c := range node.Upsert.ConflictWhere {
+			q.Upsert.ConflictWhere = append(q.Upsert.ConflictWhere, *conditionNodeToModel(c))
+		}
 		for _, f := range node.Upsert.UpdateFields {
 			q.Upsert.UpdateFields = append(q.Upsert.UpdateFields, models.Field{
 				NameExpr:    astExprToModelExpr(f.NameExpr),
@@ -517,6 +634,13 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 		}
 	}
 
+	// Returning (100% TrueAST)
+	for _, r := range node.Returning {
+		q.Returning = append(q.Returning, astExprToModelExpr(r))
+	}
+	q.TTL = node.TTL
+	q.IfNotExists = node.IfNotExists
+
 	// BulkData (100% TrueAST)
 	for _, row := range node.BulkData {
 		var fields []models.Field
@@ -537,19 +661,31 @@ func nodeToQuery(node *ast.QueryNode) *models.Query {
 			SavepointName:  node.Transaction.SavepointName,
 			IsolationLevel: node.Transaction.IsolationLevel,
 			ReadOnly:       node.Transaction.ReadOnly,
+			Mode:           node.Transaction.Mode,
+		}
+		for _, tl := range node.Transaction.LockTables {
+			q.Transaction.LockTables = append(q.Transaction.LockTables, models.TableLock{
+				Entity: tl.Entity,
+				Mode:   tl.Mode,
+			})
 		}
 	}
 
 	// Permission (unchanged - no expressions)
 	if node.Permission != nil {
 		q.Permission = &models.Permission{
-			Operation:   node.Permission.Operation,
-			Permissions: node.Permission.Permissions,
-			Target:      node.Permission.Target,
-			RoleName:    node.Permission.RoleName,
-			UserName:    node.Permission.UserName,
-			Password:    node.Permission.Password,
-			Roles:       node.Permission.Roles,
+			Operation:       node.Permission.Operation,
+			Permissions:     node.Permission.Permissions,
+			ObjectType:      node.Permission.ObjectType,
+			Target:          node.Permission.Target,
+			RoleName:        node.Permission.RoleName,
+			UserName:        node.Permission.UserName,
+			Password:        node.Permission.Password,
+			Roles:           node.Permission.Roles,
+			RoleFlags:       node.Permission.RoleFlags,
+			ValidUntil:      node.Permission.ValidUntil,
+			ConnectionLimit: node.Permission.ConnectionLimit,
+			Host:            node.Permission.Host,
 		}
 	}
 
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"github.com/omniql-engine/omniql/engine/ast"
+	"github.com/omniql-engine/omniql/engine/lexer"
+)
+
+// =============================================================================
+// PUBSUB DISPATCHER & PARSER
+// =============================================================================
+
+// LISTEN channel
+// UNLISTEN channel | *
+// NOTIFY channel [, 'payload']
+func (p *Parser) parsePubSub(op string) (*ast.QueryNode, error) {
+	node := &ast.QueryNode{
+		Operation: op,
+		Position:  p.current().Position,
+	}
+	p.advance() // consume operation
+
+	// UNLISTEN * stops every channel of the session
+	if op == "UNLISTEN" && p.current().Value == "*" {
+		p.advance()
+		node.Channel = "*"
+		return node, nil
+	}
+
+	channel, err := p.expectIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	node.Channel = channel
+
+	if op == "NOTIFY" && p.match(",") {
+		tok := p.current()
+		if tok.Type != lexer.TOKEN_STRING {
+			return nil, p.error("expected payload string after ','")
+		}
+		p.advance()
+		node.Payload = tok.Value
+	}
+
+	return node, nil
+}
@@ -0,0 +1,1528 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+)
+
+// =============================================================================
+// RENDER (models.Query -> OQL text)
+// =============================================================================
+
+// ErrNotRenderable is returned (wrapped) for a Query that no OQL statement
+// parses into, e.g. a GET with two joins
+var ErrNotRenderable = errors.New("no OQL syntax")
+
+// Render converts a Query back into canonical OQL text, without the : prefix.
+// Parse reads the text back into the same Query, up to what OQL only spells
+// one way: keywords are upper case, strings double quoted, ASC and default
+// options are left out, and the PAGE and AFTER clauses the parser desugared
+// are written again.
+func Render(q *models.Query) (string, error) {
+	if q == nil {
+		return "", fmt.Errorf("%w: nil query", ErrNotRenderable)
+	}
+
+	op := renderOperation(q)
+	switch mapping.OperationGroups[op] {
+	case "CRUD":
+		return renderCRUD(op, q)
+	case "DDL":
+		return renderDDL(op, q)
+	case "DQL":
+		return renderDQL(op, q)
+	case "TCL":
+		return renderTCL(op, q)
+	case "DCL":
+		return renderDCL(op, q)
+	case "PUBSUB":
+		return renderPubSub(op, q)
+	}
+	return "", fmt.Errorf("%w: operation '%s'", ErrNotRenderable, q.Operation)
+}
+
+// renderOperation returns the operation whose syntax spells q. A GET that
+// joins, aggregates, windows or combines queries is written as that operation.
+func renderOperation(q *models.Query) string {
+	op := strings.ToUpper(q.Operation)
+	if op == "GET" {
+		switch {
+		case len(q.Joins) > 0:
+			op = string(q.Joins[0].Type) + " JOIN"
+		case q.Aggregate != nil:
+			op = string(q.Aggregate.Function)
+		case len(q.WindowFunctions) > 0:
+			op = string(q.WindowFunctions[0].Function)
+		case q.SetOperation != nil:
+			op = string(q.SetOperation.Type)
+		}
+	}
+	if _, ok := mapping.OperationGroups[op]; !ok {
+		op = strings.ReplaceAll(op, "_", " ") // UNION_ALL, ROW_NUMBER
+	}
+	return op
+}
+
+// =============================================================================
+// CRUD
+// =============================================================================
+
+func renderCRUD(op string, q *models.Query) (string, error) {
+	switch op {
+	case "GET":
+		return renderGet(q)
+	case "CREATE", "UPSERT", "REPLACE":
+		return renderInsert(op, q)
+	case "UPDATE":
+		fields, err := renderAssignments(q.Fields)
+		if err != nil {
+			return "", err
+		}
+		return renderClauses(q, "UPDATE", q.Entity, "SET", fields)
+	case "DELETE":
+		return renderClauses(q, "DELETE", q.Entity)
+	case "BULK INSERT", "BULK UPSERT":
+		return renderBulk(op, q)
+	}
+	return "", fmt.Errorf("%w: operation '%s'", ErrNotRenderable, op)
+}
+
+// GET [column, ... FROM] entity [FINAL] [SAMPLE k] [WITH expr AS alias, ...] [clauses]
+func renderGet(q *models.Query) (string, error) {
+	words := []string{"GET"}
+	columns, err := renderColumnList(q.Columns)
+	if err != nil {
+		return "", err
+	}
+	if columns != "" {
+		words = append(words, columns, "FROM")
+	}
+	words = append(words, q.Entity)
+	words = append(words, renderTableModifiers(q)...)
+
+	if len(q.SelectColumns) > 0 {
+		selects := make([]string, len(q.SelectColumns))
+		for i, col := range q.SelectColumns {
+			expr, err := renderExpression(col.ExpressionObj)
+			if err != nil {
+				return "", err
+			}
+			if col.Alias != "" {
+				expr += " AS " + col.Alias
+			}
+			selects[i] = expr
+		}
+		words = append(words, "WITH", strings.Join(selects, ", "))
+	}
+	return renderClauses(q, words...)
+}
+
+// renderColumnList renders a column selection; * and none are empty
+func renderColumnList(columns []*models.Expression) (string, error) {
+	if len(columns) == 1 && columns[0] != nil && columns[0].Type == "FIELD" && columns[0].Value == "*" {
+		return "", nil
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		if col == nil || col.Type != "FIELD" {
+			return "", fmt.Errorf("%w: column expression outside WITH", ErrNotRenderable)
+		}
+		names[i] = col.Value
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// CREATE|UPSERT|REPLACE entity WITH field = value, ... [ON ...] [IF NOT EXISTS] [TTL] [RETURNING ...]
+// CREATE|UPSERT|REPLACE entity FROM GET ...
+func renderInsert(op string, q *models.Query) (string, error) {
+	if q.ViewQuery != nil {
+		source, err := Render(q.ViewQuery)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join([]string{op, q.Entity, "FROM", source}, " "), nil
+	}
+
+	fields, err := renderAssignments(q.Fields)
+	if err != nil {
+		return "", err
+	}
+	words := []string{op, q.Entity, "WITH", fields}
+	if op == "UPSERT" && q.Upsert != nil {
+		conflict, err := renderUpsertConflict(q.Upsert, q.Fields)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, conflict...)
+	}
+	if q.IfNotExists {
+		words = append(words, "IF NOT EXISTS")
+	}
+	if q.TTL > 0 {
+		words = append(words, "TTL", renderTTL(q.TTL))
+	}
+	if len(q.Returning) > 0 {
+		words = append(words, "RETURNING", renderFieldNames(q.Returning))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// BULK INSERT|UPSERT entity WITH [field = value, ...] [...] [ON ...] [RETURNING ...]
+func renderBulk(op string, q *models.Query) (string, error) {
+	words := []string{op, q.Entity, "WITH"}
+	for _, row := range q.BulkData {
+		fields, err := renderAssignments(row)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "["+fields+"]")
+	}
+	if op == "BULK UPSERT" {
+		if q.Upsert == nil || len(q.BulkData) == 0 {
+			return "", fmt.Errorf("%w: BULK UPSERT without rows and conflict target", ErrNotRenderable)
+		}
+		conflict, err := renderUpsertConflict(q.Upsert, q.BulkData[0])
+		if err != nil {
+			return "", err
+		}
+		if len(conflict) == 0 {
+			return "", fmt.Errorf("%w: BULK UPSERT without a conflict target", ErrNotRenderable)
+		}
+		words = append(words, conflict...)
+	}
+	if len(q.Returning) > 0 {
+		words = append(words, "RETURNING", renderFieldNames(q.Returning))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// renderAssignments renders field = value, ...
+func renderAssignments(fields []models.Field) (string, error) {
+	assignments := make([]string, len(fields))
+	for i, f := range fields {
+		name, err := renderExpression(f.NameExpr)
+		if err != nil {
+			return "", err
+		}
+		if f.ValueExpr == nil {
+			return "", fmt.Errorf("%w: field %s without a value", ErrNotRenderable, name)
+		}
+		if f.ValueExpr.Type == "LITERAL" && strings.EqualFold(f.ValueExpr.Value, "NULL") && f.NameExpr.Type == "FIELD" {
+			// A bare null parses as a field; field:null is the literal
+			assignments[i] = name + ":" + f.ValueExpr.Value
+			continue
+		}
+		value, err := renderExpression(f.ValueExpr)
+		if err != nil {
+			return "", err
+		}
+		assignments[i] = name + " = " + value
+	}
+	return strings.Join(assignments, ", "), nil
+}
+
+// renderUpsertConflict renders ON fields [WHERE ...] or ON CONSTRAINT name,
+// then DO NOTHING, or UPDATE SET unless the update is the one the parser
+// infers: every inserted field but the conflict fields, taking its inserted value
+func renderUpsertConflict(upsert *models.Upsert, inserted []models.Field) ([]string, error) {
+	var words []string
+	skip := map[string]bool{}
+	switch {
+	case upsert.ConflictConstraint != "":
+		words = append(words, "ON", "CONSTRAINT", upsert.ConflictConstraint)
+	case len(upsert.ConflictFields) > 0:
+		words = append(words, "ON", renderFieldNames(upsert.ConflictFields))
+		for _, f := range upsert.ConflictFields {
+			skip[f.Value] = true
+		}
+		if len(upsert.ConflictWhere) > 0 {
+			where, err := renderConditions(upsert.ConflictWhere)
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, "WHERE", where)
+		}
+	case upsert.DoNothing:
+		return []string{"DO", "NOTHING"}, nil
+	case len(upsert.UpdateFields) > 0:
+		return nil, fmt.Errorf("%w: UPSERT update without a conflict target", ErrNotRenderable)
+	default:
+		return nil, nil
+	}
+	if upsert.DoNothing {
+		return append(words, "DO", "NOTHING"), nil
+	}
+
+	var inferred []string
+	for _, f := range inserted {
+		if f.NameExpr != nil && !skip[f.NameExpr.Value] {
+			inferred = append(inferred, f.NameExpr.Value)
+		}
+	}
+	if isInferredUpdate(upsert.UpdateFields, inferred) {
+		return words, nil
+	}
+	if len(upsert.UpdateFields) == 0 {
+		return nil, fmt.Errorf("%w: UPSERT that updates nothing on conflict", ErrNotRenderable)
+	}
+	updates, err := renderAssignments(upsert.UpdateFields)
+	if err != nil {
+		return nil, err
+	}
+	return append(words, "UPDATE", "SET", updates), nil
+}
+
+// isInferredUpdate reports whether updates take the inserted value of exactly names
+func isInferredUpdate(updates []models.Field, names []string) bool {
+	if len(updates) != len(names) {
+		return false
+	}
+	for i, f := range updates {
+		if f.ValueExpr != nil || f.NameExpr == nil || f.NameExpr.Value != names[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// renderTTL writes milliseconds in the largest unit that divides them
+func renderTTL(ms int64) string {
+	for _, unit := range []struct {
+		name string
+		size int64
+	}{{"d", ttlUnits["D"]}, {"h", ttlUnits["H"]}, {"m", ttlUnits["M"]}, {"s", ttlUnits["S"]}} {
+		if ms%unit.size == 0 {
+			return strconv.FormatInt(ms/unit.size, 10) + unit.name
+		}
+	}
+	return strconv.FormatInt(ms, 10) + "ms"
+}
+
+// =============================================================================
+// CLAUSES
+// =============================================================================
+
+// renderClauses appends the optional clauses of q to the statement words
+func renderClauses(q *models.Query, words ...string) (string, error) {
+	conditions, after := whereConditions(q)
+	if len(conditions) > 0 {
+		where, err := renderConditions(conditions)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "WHERE", where)
+	}
+	if len(q.GroupBy) > 0 {
+		groupBy, err := renderExpressionList(q.GroupBy)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "GROUP BY", groupBy)
+	}
+	if len(q.Having) > 0 {
+		having, err := renderConditions(q.Having)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "HAVING", having)
+	}
+	if len(q.Facets) > 0 {
+		facets, err := renderFacets(q.Facets)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "FACET", facets)
+	}
+
+	limits, err := renderOrderAndLimits(q)
+	if err != nil {
+		return "", err
+	}
+	words = append(words, limits...)
+	if len(after) > 0 {
+		words = append(words, "AFTER", renderCursor(after))
+	}
+
+	if q.Distinct {
+		words = append(words, "DISTINCT")
+		if q.Aggregate != nil {
+			// Aggregates take their DISTINCT columns here, GET before FROM
+			if columns, err := renderColumnList(q.Columns); err == nil && columns != "" {
+				words = append(words, columns)
+			}
+		}
+	}
+	if q.Collation != "" {
+		words = append(words, "COLLATE", renderWord(q.Collation))
+		if q.CollationStrength > 0 {
+			words = append(words, "STRENGTH", strconv.Itoa(q.CollationStrength))
+		}
+	}
+	if q.Lock != "" {
+		words = append(words, "FOR", q.Lock)
+		if q.LockWait != "" {
+			words = append(words, q.LockWait)
+		}
+	}
+	if len(q.Returning) > 0 {
+		words = append(words, "RETURNING", renderFieldNames(q.Returning))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// renderOrderAndLimits renders ORDER BY, then PAGE/SIZE when Limit and Offset
+// are the page they were desugared from, else LIMIT and OFFSET
+func renderOrderAndLimits(q *models.Query) ([]string, error) {
+	var words []string
+	if len(q.OrderBy) > 0 {
+		orderBy, err := renderOrderBy(q.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, "ORDER BY", orderBy)
+	}
+	if q.Page > 0 && q.PageSize > 0 && q.Limit == q.PageSize && q.Offset == (q.Page-1)*q.PageSize {
+		return append(words, "PAGE", strconv.Itoa(q.Page), "SIZE", strconv.Itoa(q.PageSize)), nil
+	}
+	if q.Limit > 0 {
+		words = append(words, "LIMIT", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		words = append(words, "OFFSET", strconv.Itoa(q.Offset))
+	}
+	return words, nil
+}
+
+// whereConditions takes back out of the conditions the seek predicate an
+// AFTER cursor was desugared into, returning the cursor values to write
+// instead. Conditions that do not hold the predicate are kept whole.
+func whereConditions(q *models.Query) ([]models.Condition, []*models.Expression) {
+	if len(q.After) == 0 || len(q.After) != len(q.OrderBy) {
+		return q.Conditions, nil
+	}
+	seek := seekConditions(q.OrderBy, q.After)
+	if reflect.DeepEqual(q.Conditions, seek) {
+		return nil, q.After
+	}
+	if len(q.Conditions) == 2 && q.Conditions[0].Operator == "GROUP" && q.Conditions[1].Operator == "GROUP" &&
+		reflect.DeepEqual(q.Conditions[1].Nested, seek) {
+		return q.Conditions[0].Nested, q.After
+	}
+	return q.Conditions, nil
+}
+
+// seekConditions rebuilds the predicate keysetConditions desugars AFTER into
+func seekConditions(orderBy []models.OrderBy, after []*models.Expression) []models.Condition {
+	var groups []models.Condition
+	for i := range orderBy {
+		var group []models.Condition
+		for j := 0; j < i; j++ {
+			group = append(group, models.Condition{
+				FieldExpr: orderBy[j].FieldExpr,
+				Operator:  "=",
+				ValueExpr: after[j],
+				Logic:     logicFor(j, "AND"),
+			})
+		}
+		op := ">"
+		if orderBy[i].Direction == models.Desc {
+			op = "<"
+		}
+		group = append(group, models.Condition{
+			FieldExpr: orderBy[i].FieldExpr,
+			Operator:  op,
+			ValueExpr: after[i],
+			Logic:     logicFor(i, "AND"),
+		})
+
+		if len(group) == 1 {
+			group[0].Logic = logicFor(i, "OR")
+			groups = append(groups, group[0])
+			continue
+		}
+		groups = append(groups, models.Condition{Operator: "GROUP", Nested: group, Logic: logicFor(i, "OR")})
+	}
+	return groups
+}
+
+// renderCursor writes AFTER values: one plain value, or several as a cursor token
+func renderCursor(after []*models.Expression) string {
+	if len(after) == 1 && !IsCursorToken(after[0].Value) {
+		switch after[0].Type {
+		case "STRING":
+			return quote(after[0].Value)
+		case "NUMBER", "BOOLEAN":
+			return after[0].Value
+		}
+		return renderLiteral(after[0].Value)
+	}
+	return EncodeCursor(after)
+}
+
+// renderOrderBy renders expr [DESC], ...
+func renderOrderBy(orderBy []models.OrderBy) (string, error) {
+	items := make([]string, len(orderBy))
+	for i, o := range orderBy {
+		expr, err := renderSide(o.FieldExpr)
+		if err != nil {
+			return "", err
+		}
+		if strings.ToUpper(string(o.Direction)) == "DESC" {
+			expr += " DESC"
+		}
+		items[i] = expr
+	}
+	return strings.Join(items, ", "), nil
+}
+
+// renderFacets renders name (AGG [field] [GROUP BY ...]), ...
+func renderFacets(facets []models.Facet) (string, error) {
+	items := make([]string, len(facets))
+	for i, f := range facets {
+		if f.Aggregate == nil {
+			return "", fmt.Errorf("%w: facet %s without an aggregate", ErrNotRenderable, f.Name)
+		}
+		words, err := renderAggregate(f.Aggregate)
+		if err != nil {
+			return "", err
+		}
+		if len(f.GroupBy) > 0 {
+			groupBy, err := renderExpressionList(f.GroupBy)
+			if err != nil {
+				return "", err
+			}
+			words = append(words, "GROUP BY", groupBy)
+		}
+		items[i] = f.Name + " (" + strings.Join(words, " ") + ")"
+	}
+	return strings.Join(items, ", "), nil
+}
+
+// renderFieldNames renders a RETURNING or conflict list of fields
+func renderFieldNames(fields []*models.Expression) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Value
+	}
+	return strings.Join(names, ", ")
+}
+
+// =============================================================================
+// CONDITIONS
+// =============================================================================
+
+// renderConditions renders a condition list; a missing logic joins with AND
+func renderConditions(conditions []models.Condition) (string, error) {
+	var b strings.Builder
+	for i, c := range conditions {
+		if i > 0 {
+			logic := strings.ToUpper(c.Logic)
+			if logic == "" {
+				logic = "AND"
+			}
+			b.WriteString(" " + logic + " ")
+		}
+		cond, err := renderCondition(c)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(cond)
+	}
+	return b.String(), nil
+}
+
+// renderCondition renders one condition by its operator category (SSOT)
+func renderCondition(c models.Condition) (string, error) {
+	if c.Operator == "GROUP" {
+		nested, err := renderConditions(c.Nested)
+		if err != nil {
+			return "", err
+		}
+		return "(" + nested + ")", nil
+	}
+
+	field, err := renderSide(c.FieldExpr)
+	if err != nil {
+		return "", err
+	}
+	if c.Operator == "" {
+		return field, nil
+	}
+	op := strings.ReplaceAll(strings.ToUpper(c.Operator), "_", " ")
+
+	switch mapping.GetOperatorCategory(c.Operator) {
+	case "MULTI_VALUE":
+		values := make([]string, len(c.ValuesExpr))
+		for i, v := range c.ValuesExpr {
+			if values[i], err = renderSide(v); err != nil {
+				return "", err
+			}
+		}
+		return field + " " + op + " (" + strings.Join(values, ", ") + ")", nil
+	case "RANGE":
+		low, err := renderSide(c.ValueExpr)
+		if err != nil {
+			return "", err
+		}
+		high, err := renderSide(c.Value2Expr)
+		if err != nil {
+			return "", err
+		}
+		return field + " " + op + " " + low + " AND " + high, nil
+	case "NULLCHECK":
+		return field + " " + op, nil
+	case "DISTANCE":
+		point, err := renderSide(c.ValueExpr)
+		if err != nil {
+			return "", err
+		}
+		distance, err := renderSide(c.Value2Expr)
+		if err != nil {
+			return "", err
+		}
+		return field + " " + op + " " + point + " DISTANCE " + distance, nil
+	}
+
+	value, err := renderSide(c.ValueExpr)
+	if err != nil {
+		return "", err
+	}
+	return field + " " + op + " " + value, nil
+}
+
+// =============================================================================
+// EXPRESSIONS
+// =============================================================================
+
+// expressionPrecedence ranks the binary operators as parseExpression binds
+// them, loosest first
+var expressionPrecedence = map[string]int{
+	"OR":  1,
+	"AND": 2,
+	"+":   3,
+	"-":   3,
+	"*":   4,
+	"/":   4,
+	"%":   4,
+	"->":  5,
+	"->>": 5,
+}
+
+// numberLiteral matches the numbers the lexer reads as one token
+var numberLiteral = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// plainWord matches values that lex as a single identifier
+var plainWord = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// renderExpression renders an expression tree, adding the parentheses its
+// shape needs
+func renderExpression(e *models.Expression) (string, error) {
+	if e == nil {
+		return "", fmt.Errorf("%w: missing expression", ErrNotRenderable)
+	}
+
+	switch e.Type {
+	case "FIELD", "NUMBER", "BOOLEAN":
+		return e.Value, nil
+	case "STRING":
+		return quote(e.Value), nil
+	case "LITERAL":
+		return renderLiteral(e.Value), nil
+
+	case "BINARY", "JSON_PATH":
+		precedence := expressionPrecedence[strings.ToUpper(e.Operator)]
+		left, err := renderOperand(e.Left, precedence, false)
+		if err != nil {
+			return "", err
+		}
+		right, err := renderOperand(e.Right, precedence, true)
+		if err != nil {
+			return "", err
+		}
+		if e.Type == "JSON_PATH" {
+			return left + e.Operator + right, nil
+		}
+		return left + " " + strings.ToUpper(e.Operator) + " " + right, nil
+
+	case "FUNCTION":
+		args, err := renderExpressionList(e.FunctionArgs)
+		if err != nil {
+			return "", err
+		}
+		return e.FunctionName + "(" + args + ")", nil
+
+	case "CASEWHEN":
+		words := []string{"CASE"}
+		for _, cc := range e.CaseConditions {
+			if cc.Condition == nil {
+				return "", fmt.Errorf("%w: CASE WHEN without a condition", ErrNotRenderable)
+			}
+			cond, err := renderCondition(*cc.Condition)
+			if err != nil {
+				return "", err
+			}
+			then, err := renderExpression(cc.ThenExpr)
+			if err != nil {
+				return "", err
+			}
+			words = append(words, "WHEN", cond, "THEN", then)
+		}
+		if e.CaseElse != nil {
+			elseExpr, err := renderExpression(e.CaseElse)
+			if err != nil {
+				return "", err
+			}
+			words = append(words, "ELSE", elseExpr)
+		}
+		return strings.Join(append(words, "END"), " "), nil
+
+	case "WINDOW":
+		words := []string{e.FunctionName}
+		for _, arg := range e.FunctionArgs {
+			words = append(words, arg.Value)
+		}
+		over, err := renderOver(e.PartitionBy, e.WindowOrderBy, "", "", "")
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(append(words, over), " "), nil
+	}
+	return "", fmt.Errorf("%w: %s expression", ErrNotRenderable, e.Type)
+}
+
+// renderOperand renders the child of a binary expression, in parentheses when
+// it binds looser than its parent (or as loose, on the right)
+func renderOperand(e *models.Expression, parent int, right bool) (string, error) {
+	s, err := renderExpression(e)
+	if err != nil {
+		return "", err
+	}
+	if e.Type == "BINARY" || e.Type == "JSON_PATH" {
+		precedence := expressionPrecedence[strings.ToUpper(e.Operator)]
+		if precedence < parent || right && precedence == parent {
+			return "(" + s + ")", nil
+		}
+	}
+	return s, nil
+}
+
+// renderSide renders one side of a condition, which is parsed without AND and OR
+func renderSide(e *models.Expression) (string, error) {
+	return renderOperand(e, expressionPrecedence["+"], false)
+}
+
+// renderExpressionList renders expr, expr, ...
+func renderExpressionList(exprs []*models.Expression) (string, error) {
+	items := make([]string, len(exprs))
+	for i, e := range exprs {
+		s, err := renderExpression(e)
+		if err != nil {
+			return "", err
+		}
+		items[i] = s
+	}
+	return strings.Join(items, ", "), nil
+}
+
+// renderLiteral writes an untyped value: numbers, booleans and NULL bare,
+// anything else as a string
+func renderLiteral(value string) string {
+	switch strings.ToUpper(value) {
+	case "TRUE", "FALSE", "NULL":
+		return value
+	}
+	if numberLiteral.MatchString(value) {
+		return value
+	}
+	return quote(value)
+}
+
+// renderWord writes a name bare when it lexes as one identifier, else quoted
+func renderWord(value string) string {
+	if plainWord.MatchString(value) {
+		return value
+	}
+	return quote(value)
+}
+
+// quote writes a double-quoted string with the escapes the lexer reads
+func quote(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// =============================================================================
+// DQL
+// =============================================================================
+
+func renderDQL(op string, q *models.Query) (string, error) {
+	switch {
+	case mapping.IsAggregate(op):
+		return renderAggregateQuery(op, q)
+	case strings.HasSuffix(op, " JOIN"):
+		return renderJoin(op, q)
+	case op == "UNION" || op == "UNION ALL" || op == "INTERSECT" || op == "EXCEPT":
+		return renderSetOperation(op, q)
+	case mapping.IsWindowFunction(op):
+		if len(q.WindowFunctions) != 1 {
+			return "", fmt.Errorf("%w: %s with %d window functions", ErrNotRenderable, op, len(q.WindowFunctions))
+		}
+		return renderWindow(q, q.WindowFunctions[0])
+	case op == "CTE":
+		return renderCTE(q)
+	case op == "SUBQUERY":
+		return renderSubquery(q)
+	case op == "EXISTS":
+		inner := q.ViewQuery
+		if q.Subquery != nil && q.Subquery.Query != nil {
+			inner = q.Subquery.Query
+		}
+		if inner == nil {
+			return "", fmt.Errorf("%w: EXISTS without a query", ErrNotRenderable)
+		}
+		nested, err := Render(inner)
+		if err != nil {
+			return "", err
+		}
+		return "EXISTS (" + nested + ")", nil
+	}
+	return "", fmt.Errorf("%w: operation '%s'", ErrNotRenderable, op)
+}
+
+// AGG [field|*] [ORDER BY ...] [SEPARATOR "s"] FROM entity [FINAL] [SAMPLE k] [clauses]
+// AGG field OVER (...) [AS alias] FROM entity [clauses]
+func renderAggregateQuery(op string, q *models.Query) (string, error) {
+	if q.Aggregate == nil {
+		if len(q.WindowFunctions) != 1 {
+			return "", fmt.Errorf("%w: %s without an aggregate", ErrNotRenderable, op)
+		}
+		return renderWindow(q, q.WindowFunctions[0])
+	}
+	words, err := renderAggregate(q.Aggregate)
+	if err != nil {
+		return "", err
+	}
+	words = append(words, "FROM", q.Entity)
+	return renderClauses(q, append(words, renderTableModifiers(q)...)...)
+}
+
+// renderTableModifiers renders FINAL, SAMPLE k, AS OF and BEFORE, which
+// follow the entity
+func renderTableModifiers(q *models.Query) []string {
+	var words []string
+	if q.AsOf != "" {
+		words = append(words, "AS OF", quote(q.AsOf))
+	}
+	if q.Before != "" {
+		words = append(words, "BEFORE", quote(q.Before))
+	}
+	if q.Final {
+		words = append(words, "FINAL")
+	}
+	if q.Sample != "" {
+		words = append(words, "SAMPLE", q.Sample)
+	}
+	return words
+}
+
+// renderAggregate renders AGG [field|*] and the STRING AGG options
+func renderAggregate(agg *models.Aggregation) ([]string, error) {
+	words := []string{strings.ReplaceAll(string(agg.Function), "_", " ")}
+	if agg.FieldExpr != nil {
+		if agg.FieldExpr.Type != "FIELD" {
+			return nil, fmt.Errorf("%w: %s of an expression", ErrNotRenderable, agg.Function)
+		}
+		words = append(words, agg.FieldExpr.Value)
+	}
+	if len(agg.OrderBy) > 0 {
+		orderBy, err := renderOrderBy(agg.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, "ORDER BY", orderBy)
+	}
+	if agg.Separator != "" && agg.Separator != "," {
+		words = append(words, "SEPARATOR", quote(agg.Separator))
+	}
+	return words, nil
+}
+
+// TYPE JOIN entity table [ON left = right] [clauses]
+func renderJoin(op string, q *models.Query) (string, error) {
+	if len(q.Joins) != 1 {
+		return "", fmt.Errorf("%w: %d joins in one query", ErrNotRenderable, len(q.Joins))
+	}
+	join := q.Joins[0]
+	if len(join.Conditions) > 0 {
+		return "", fmt.Errorf("%w: JOIN with more than one ON condition", ErrNotRenderable)
+	}
+	if columns, err := renderColumnList(q.Columns); err != nil || columns != "" {
+		return "", fmt.Errorf("%w: column list on a JOIN", ErrNotRenderable)
+	}
+
+	words := []string{op, q.Entity, join.Table}
+	if join.Type != models.CrossJoin {
+		left, err := renderSide(join.LeftExpr)
+		if err != nil {
+			return "", err
+		}
+		right, err := renderSide(join.RightExpr)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "ON", left, "=", right)
+	}
+	return renderClauses(q, words...)
+}
+
+// UNION|UNION ALL|INTERSECT|EXCEPT (query) (query) [ORDER BY ...] [LIMIT n] [OFFSET n]
+func renderSetOperation(op string, q *models.Query) (string, error) {
+	set := q.SetOperation
+	if set == nil || set.LeftQuery == nil || set.RightQuery == nil {
+		return "", fmt.Errorf("%w: %s without two queries", ErrNotRenderable, op)
+	}
+	left, err := Render(set.LeftQuery)
+	if err != nil {
+		return "", err
+	}
+	right, err := Render(set.RightQuery)
+	if err != nil {
+		return "", err
+	}
+
+	words := []string{op, "(" + left + ")", "(" + right + ")"}
+	limits, err := renderOrderAndLimits(q)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append(words, limits...), " "), nil
+}
+
+// FN [field] [offset] [DEFAULT value] OVER (...) [AS alias] [FROM entity] [clauses]
+func renderWindow(q *models.Query, wf models.WindowFunction) (string, error) {
+	fn := strings.ReplaceAll(string(wf.Function), "_", " ")
+	words := []string{fn}
+	if wf.FieldExpr != nil {
+		words = append(words, wf.FieldExpr.Value)
+	}
+	if wf.Offset > 0 && (fn == "LAG" || fn == "LEAD") {
+		words = append(words, strconv.Itoa(wf.Offset))
+	}
+	if wf.Buckets > 0 && fn == "NTILE" {
+		words = append(words, strconv.Itoa(wf.Buckets))
+	}
+	if wf.Default != nil {
+		value, err := renderOperand(wf.Default, len(expressionPrecedence), false)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "DEFAULT", value)
+	}
+
+	over, err := renderOver(wf.PartitionBy, wf.OrderBy, wf.FrameUnit, wf.FrameStart, wf.FrameEnd)
+	if err != nil {
+		return "", err
+	}
+	words = append(words, over)
+	if wf.Alias != "" {
+		words = append(words, "AS", wf.Alias)
+	}
+	if q.Entity != "" {
+		words = append(words, "FROM", q.Entity)
+	}
+	return renderClauses(q, words...)
+}
+
+// renderOver renders OVER ([PARTITION BY ...] [ORDER BY ...] [frame])
+func renderOver(partitionBy []*models.Expression, orderBy []models.OrderBy, unit, start, end string) (string, error) {
+	var words []string
+	if len(partitionBy) > 0 {
+		partitions, err := renderExpressionList(partitionBy)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "PARTITION BY", partitions)
+	}
+	if len(orderBy) > 0 {
+		order, err := renderOrderBy(orderBy)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, "ORDER BY", order)
+	}
+	if unit != "" {
+		words = append(words, unit, "BETWEEN", start, "AND", end)
+	}
+	return "OVER (" + strings.Join(words, " ") + ")", nil
+}
+
+// CTE [RECURSIVE] name AS (query) [DEPTH n] [GET ...]
+func renderCTE(q *models.Query) (string, error) {
+	cte := q.CTE
+	if cte == nil {
+		cte = &models.CTE{Name: q.ViewName, Query: q.ViewQuery}
+	}
+	if cte.Name == "" || cte.Query == nil {
+		return "", fmt.Errorf("%w: CTE without a name and query", ErrNotRenderable)
+	}
+	inner, err := Render(cte.Query)
+	if err != nil {
+		return "", err
+	}
+
+	words := []string{"CTE"}
+	if cte.Recursive {
+		words = append(words, "RECURSIVE")
+	}
+	words = append(words, cte.Name, "AS", "("+inner+")")
+	if cte.Recursive && cte.MaxDepth > 0 {
+		words = append(words, "DEPTH", strconv.Itoa(cte.MaxDepth))
+	}
+	if cte.MainQuery != nil {
+		main, err := Render(cte.MainQuery)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, main)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// SUBQUERY field IN (query)
+// The query is written as parsed (ViewQuery); Subquery.Query selects only field.
+func renderSubquery(q *models.Query) (string, error) {
+	var field *models.Expression
+	inner := q.ViewQuery
+	if q.Subquery != nil {
+		field = q.Subquery.FieldExpr
+		if inner == nil {
+			inner = q.Subquery.Query
+		}
+	}
+	if field == nil && len(q.Columns) > 0 {
+		field = q.Columns[0]
+	}
+	if field == nil || inner == nil {
+		return "", fmt.Errorf("%w: SUBQUERY without a field and query", ErrNotRenderable)
+	}
+	nested, err := Render(inner)
+	if err != nil {
+		return "", err
+	}
+	return "SUBQUERY " + field.Value + " IN (" + nested + ")", nil
+}
+
+// =============================================================================
+// DDL
+// =============================================================================
+
+func renderDDL(op string, q *models.Query) (string, error) {
+	words := []string{op}
+	switch op {
+	case "CREATE TABLE":
+		if q.ViewQuery != nil {
+			source, err := Render(q.ViewQuery)
+			if err != nil {
+				return "", err
+			}
+			return strings.Join([]string{op, q.Entity, "AS", source}, " "), nil
+		}
+		columns, err := renderColumnDefinitions(q.Fields)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, q.Entity, "WITH", columns)
+		words = append(words, renderTableOptions(q.TableOptions)...)
+		if q.PartitionStrategy != "" {
+			words = append(words, "PARTITION BY", q.PartitionStrategy, "("+renderFieldNames(q.PartitionKeys)+")")
+		}
+		if len(q.ClusterKeys) > 0 {
+			words = append(words, "CLUSTER BY", renderFieldNames(q.ClusterKeys))
+		}
+	case "ALTER TABLE":
+		action, err := renderAlterAction(q)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, q.Entity)
+		if action != "" {
+			words = append(words, action)
+		}
+	case "TRUNCATE", "TRUNCATE TABLE", "CREATE COLLECTION", "DROP COLLECTION":
+		words = append(words, q.Entity)
+	case "DROP TABLE":
+		words = append(words, q.Entity)
+	case "RENAME TABLE":
+		words = append(words, q.Entity, "TO", q.NewName)
+	case "SHOW TABLES":
+	case "SHOW COLUMNS", "SHOW INDEXES":
+		words = append(words, "FROM", q.Entity)
+	case "CREATE INDEX":
+		return renderCreateIndex(q)
+	case "DROP INDEX":
+		words = append(words, q.Entity)
+		if len(q.Fields) > 0 && q.Fields[0].NameExpr != nil {
+			words = append(words, q.Fields[0].NameExpr.Value)
+			if hasConstraint(q.Fields[0].Constraints, "FULLTEXT") {
+				words = append(words, "FULLTEXT")
+			}
+		}
+	case "CREATE DATABASE":
+		words = append(words, q.DatabaseName)
+		if q.DatabaseFile != "" {
+			words = append(words, "FILE", quote(q.DatabaseFile))
+		}
+	case "DROP DATABASE":
+		words = append(words, q.DatabaseName)
+	case "CREATE VIEW", "ALTER VIEW":
+		if q.ViewQuery == nil {
+			return "", fmt.Errorf("%w: %s without a query", ErrNotRenderable, op)
+		}
+		view, err := Render(q.ViewQuery)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, q.ViewName, "AS", view)
+	case "DROP VIEW":
+		words = append(words, q.ViewName)
+
+	// PostgreSQL-specific DDL
+	case "CREATE SEQUENCE":
+		words = append(words, q.SequenceName)
+		words = appendOption(words, "START", q.SequenceStart)
+		words = appendOption(words, "INCREMENT", q.SequenceIncrement)
+		words = appendOption(words, "MIN", q.SequenceMin)
+		words = appendOption(words, "MAX", q.SequenceMax)
+		words = appendOption(words, "CACHE", q.SequenceCache)
+		if q.SequenceCycle {
+			words = append(words, "CYCLE")
+		}
+	case "ALTER SEQUENCE":
+		words = append(words, q.SequenceName)
+		words = appendOption(words, "RESTART", q.SequenceRestart)
+		words = appendOption(words, "INCREMENT", q.SequenceIncrement)
+	case "DROP SEQUENCE":
+		words = append(words, q.SequenceName)
+	case "CREATE EXTENSION":
+		words = append(words, q.ExtensionName)
+		if q.SchemaName != "" {
+			words = append(words, "SCHEMA:"+q.SchemaName)
+		}
+	case "DROP EXTENSION":
+		words = append(words, q.ExtensionName)
+	case "CREATE SCHEMA":
+		words = append(words, q.SchemaName)
+		if q.SchemaOwner != "" {
+			words = append(words, "OWNER:"+q.SchemaOwner)
+		}
+	case "DROP SCHEMA":
+		words = append(words, q.SchemaName)
+	case "CREATE TYPE":
+		words = append(words, q.TypeName, "AS", q.TypeKind)
+		switch q.TypeKind {
+		case "ENUM":
+			words = append(words, "VALUES:"+strings.Join(q.EnumValues, ", "))
+		case "COMPOSITE":
+			columns, err := renderColumnDefinitions(q.Fields)
+			if err != nil {
+				return "", err
+			}
+			words = append(words, "WITH", columns)
+		default:
+			return "", fmt.Errorf("%w: CREATE TYPE AS %s", ErrNotRenderable, q.TypeKind)
+		}
+	case "ALTER TYPE":
+		words = append(words, q.TypeName)
+		switch q.AlterAction {
+		case "ADD_VALUE":
+			words = append(words, "ADD_VALUE:"+q.EnumValue)
+		case "RENAME_VALUE":
+			words = append(words, "RENAME_VALUE:"+q.EnumValue+":"+q.NewEnumValue)
+		default:
+			return "", fmt.Errorf("%w: ALTER TYPE %s", ErrNotRenderable, q.AlterAction)
+		}
+	case "DROP TYPE":
+		words = append(words, q.TypeName)
+	case "CREATE DOMAIN":
+		words = append(words, q.DomainName, "AS", q.DomainType)
+		if q.DomainDefault != "" {
+			words = append(words, "DEFAULT:"+q.DomainDefault)
+		}
+		if q.DomainConstraint != "" {
+			words = append(words, "CHECK:"+q.DomainConstraint)
+		}
+	case "DROP DOMAIN":
+		words = append(words, q.DomainName)
+	case "CREATE FUNCTION":
+		words = append(words, q.FuncName)
+		if len(q.FuncArgs) > 0 {
+			words = append(words, "ARGS:"+strings.Join(q.FuncArgs, ","))
+		}
+		if q.FuncReturns != "" {
+			words = append(words, "RETURNS:"+q.FuncReturns)
+		}
+		if q.FuncLanguage != "" {
+			words = append(words, "LANGUAGE:"+q.FuncLanguage)
+		}
+		if q.FuncBody != "" {
+			words = append(words, "BODY:$$"+q.FuncBody+"$$")
+		}
+	case "ALTER FUNCTION":
+		words = append(words, q.FuncName)
+		switch {
+		case q.FuncOwner != "":
+			words = append(words, "OWNER:"+q.FuncOwner)
+		case q.SchemaName != "":
+			words = append(words, "SCHEMA:"+q.SchemaName)
+		}
+	case "DROP FUNCTION":
+		words = append(words, q.FuncName)
+	case "CREATE TRIGGER":
+		words = append(words, q.TriggerName, "ON", q.Entity)
+		words = appendText(words, "TIMING", q.TriggerTiming)
+		words = appendText(words, "EVENTS", q.TriggerEvents)
+		words = appendText(words, "FOREACH", q.TriggerForEach)
+		words = appendText(words, "FUNCTION", q.FuncName)
+	case "DROP TRIGGER":
+		words = append(words, q.TriggerName, "ON", q.Entity)
+	case "CREATE POLICY":
+		words = append(words, q.PolicyName, "ON", q.Entity)
+		words = appendText(words, "FOR", q.PolicyFor)
+		words = appendText(words, "TO", q.PolicyTo)
+		words = appendText(words, "USING", q.PolicyUsing)
+		words = appendText(words, "CHECK", q.PolicyCheck)
+	case "DROP POLICY":
+		words = append(words, q.PolicyName, "ON", q.Entity)
+	case "CREATE PARTITION":
+		bounds, err := renderPartitionBounds(q)
+		if err != nil {
+			return "", err
+		}
+		words = append(words, q.PartitionName, "OF", q.Entity, bounds)
+	case "CREATE RULE":
+		words = append(words, q.RuleName, "ON", q.Entity)
+		words = appendText(words, "EVENT", q.RuleEvent)
+		words = appendText(words, "ACTION", q.RuleAction)
+	case "DROP RULE":
+		words = append(words, q.RuleName, "ON", q.Entity)
+	case "COMMENT ON":
+		words = append(words, q.CommentTarget, "TEXT:"+q.CommentText)
+	default:
+		return "", fmt.Errorf("%w: operation '%s'", ErrNotRenderable, op)
+	}
+
+	if q.Cascade && strings.HasPrefix(op, "DROP ") || q.Cascade && op == "CREATE SEQUENCE" {
+		words = append(words, "CASCADE")
+	}
+	return strings.Join(words, " "), nil
+}
+
+// renderColumnDefinitions renders name:TYPE[(size)][:CONSTRAINT...] [GENERATED AS (expr)], ...
+func renderColumnDefinitions(fields []models.Field) (string, error) {
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		if f.NameExpr == nil || f.ValueExpr == nil {
+			return "", fmt.Errorf("%w: column definition without a name and type", ErrNotRenderable)
+		}
+		column := f.NameExpr.Value + ":" + f.ValueExpr.Value
+		for _, constraint := range f.Constraints {
+			column += ":" + constraint
+		}
+		if f.GeneratedExpr != nil {
+			expr, err := renderExpression(f.GeneratedExpr)
+			if err != nil {
+				return "", err
+			}
+			column += " GENERATED AS (" + expr + ")"
+		}
+		columns[i] = column
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// renderTableOptions renders the dialect table options in name order
+func renderTableOptions(options map[string]string) []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var words []string
+	for _, name := range names {
+		switch name {
+		case "STRICT":
+			words = append(words, "STRICT")
+		case "WITHOUT_ROWID":
+			words = append(words, "WITHOUT ROWID")
+		default:
+			words = append(words, name, "=", renderWord(options[name]))
+		}
+	}
+	return words
+}
+
+// renderAlterAction renders ADD col:TYPE, DROP col, RENAME old:new or MODIFY col:TYPE
+func renderAlterAction(q *models.Query) (string, error) {
+	if q.AlterAction == "" {
+		return "", nil
+	}
+	if len(q.Fields) == 0 || q.Fields[0].NameExpr == nil {
+		return "", fmt.Errorf("%w: %s without a column", ErrNotRenderable, q.AlterAction)
+	}
+	f := q.Fields[0]
+	value := ""
+	if f.ValueExpr != nil {
+		value = f.ValueExpr.Value
+	}
+
+	switch q.AlterAction {
+	case "ADD_COLUMN":
+		action := "ADD " + f.NameExpr.Value + ":" + value
+		if f.GeneratedExpr != nil {
+			expr, err := renderExpression(f.GeneratedExpr)
+			if err != nil {
+				return "", err
+			}
+			action += " GENERATED AS (" + expr + ")"
+		}
+		return action, nil
+	case "DROP_COLUMN":
+		return "DROP " + f.NameExpr.Value, nil
+	case "RENAME_COLUMN":
+		return "RENAME " + f.NameExpr.Value + ":" + value, nil
+	case "MODIFY_COLUMN":
+		return "MODIFY " + f.NameExpr.Value + ":" + value, nil
+	}
+	return "", fmt.Errorf("%w: ALTER TABLE %s", ErrNotRenderable, q.AlterAction)
+}
+
+// CREATE INDEX entity name:column [UNIQUE] [FULLTEXT] [SPARSE] [TTL seconds] [PARTIAL WHERE ...]
+func renderCreateIndex(q *models.Query) (string, error) {
+	if len(q.Fields) == 0 || q.Fields[0].NameExpr == nil || q.Fields[0].ValueExpr == nil {
+		return "", fmt.Errorf("%w: CREATE INDEX without a name and column", ErrNotRenderable)
+	}
+	f := q.Fields[0]
+	if !plainWord.MatchString(f.ValueExpr.Value) {
+		return "", fmt.Errorf("%w: index on %s (one ascending column only)", ErrNotRenderable, f.ValueExpr.Value)
+	}
+
+	words := []string{"CREATE INDEX", q.Entity, f.NameExpr.Value + ":" + f.ValueExpr.Value}
+	for i := 0; i < len(f.Constraints); i++ {
+		switch constraint := f.Constraints[i]; constraint {
+		case "TTL":
+			if i+1 == len(f.Constraints) {
+				return "", fmt.Errorf("%w: index TTL without seconds", ErrNotRenderable)
+			}
+			i++
+			words = append(words, "TTL", f.Constraints[i])
+		case "PARTIAL":
+			where, err := renderConditions(q.Conditions)
+			if err != nil {
+				return "", err
+			}
+			words = append(words, "PARTIAL WHERE", where)
+		default:
+			words = append(words, constraint)
+		}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// renderPartitionBounds renders DEFAULT or FOR VALUES FROM (...) TO (...) | IN (...) | WITH (...)
+func renderPartitionBounds(q *models.Query) (string, error) {
+	switch {
+	case q.PartitionDefault:
+		return "DEFAULT", nil
+	case len(q.PartitionIn) > 0:
+		in, err := renderExpressionList(q.PartitionIn)
+		if err != nil {
+			return "", err
+		}
+		return "FOR VALUES IN (" + in + ")", nil
+	case len(q.PartitionFrom) > 0:
+		from, err := renderExpressionList(q.PartitionFrom)
+		if err != nil {
+			return "", err
+		}
+		to, err := renderExpressionList(q.PartitionTo)
+		if err != nil {
+			return "", err
+		}
+		return "FOR VALUES FROM (" + from + ") TO (" + to + ")", nil
+	case q.PartitionModulus > 0:
+		return fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", q.PartitionModulus, q.PartitionRemainder), nil
+	}
+	return "", fmt.Errorf("%w: CREATE PARTITION without bounds", ErrNotRenderable)
+}
+
+// appendOption appends KEY:n when n is set
+func appendOption(words []string, key string, n int64) []string {
+	if n == 0 {
+		return words
+	}
+	return append(words, key+":"+strconv.FormatInt(n, 10))
+}
+
+// appendText appends KEY:value when value is set
+func appendText(words []string, key, value string) []string {
+	if value == "" {
+		return words
+	}
+	return append(words, key+":"+value)
+}
+
+func hasConstraint(constraints []string, name string) bool {
+	for _, c := range constraints {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// TCL
+// =============================================================================
+
+func renderTCL(op string, q *models.Query) (string, error) {
+	words := []string{op}
+	tx := q.Transaction
+	if tx == nil {
+		tx = &models.Transaction{}
+	}
+
+	switch op {
+	case "BEGIN", "START":
+		if tx.Mode != "" {
+			words = append(words, tx.Mode)
+		}
+	case "SAVEPOINT", "ROLLBACK TO", "RELEASE SAVEPOINT":
+		words = append(words, tx.SavepointName)
+	case "LOCK TABLES":
+		locks := make([]string, len(tx.LockTables))
+		for i, lock := range tx.LockTables {
+			locks[i] = lock.Entity + " " + lock.Mode
+		}
+		words = append(words, strings.Join(locks, ", "))
+	case "SET TRANSACTION":
+		if tx.IsolationLevel != "" {
+			level := "ISOLATION LEVEL " + tx.IsolationLevel
+			if tx.ReadOnly {
+				level += ","
+			}
+			words = append(words, level)
+		}
+		if tx.ReadOnly {
+			words = append(words, "READ ONLY")
+		}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// =============================================================================
+// DCL
+// =============================================================================
+
+func renderDCL(op string, q *models.Query) (string, error) {
+	perm := q.Permission
+	if perm == nil {
+		return "", fmt.Errorf("%w: %s without a permission", ErrNotRenderable, op)
+	}
+	words := []string{op}
+
+	switch op {
+	case "GRANT", "REVOKE":
+		words = append(words, strings.Join(perm.Permissions, ", "), "ON")
+		if perm.ObjectType != "" {
+			words = append(words, perm.ObjectType)
+		}
+		direction := "TO"
+		if op == "REVOKE" {
+			direction = "FROM"
+		}
+		words = append(words, q.Entity, direction, renderAccount(perm.Target, perm.Host))
+	case "CREATE USER", "ALTER USER":
+		words = append(words, renderAccount(perm.UserName, perm.Host))
+		words = append(words, renderRoleOptions(perm)...)
+	case "DROP USER":
+		words = append(words, renderAccount(perm.UserName, perm.Host))
+	case "CREATE ROLE", "ALTER ROLE":
+		words = append(words, perm.RoleName)
+		words = append(words, renderRoleOptions(perm)...)
+	case "DROP ROLE":
+		words = append(words, perm.RoleName)
+	case "ASSIGN ROLE":
+		words = append(words, perm.RoleName, "TO", renderAccount(perm.UserName, perm.Host))
+	case "REVOKE ROLE":
+		words = append(words, perm.RoleName, "FROM", renderAccount(perm.UserName, perm.Host))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// renderAccount renders name or name@host, quoting a host that is not one word
+func renderAccount(name, host string) string {
+	if host == "" {
+		return name
+	}
+	if plainWord.MatchString("h" + host) {
+		return name + "@" + host
+	}
+	return name + "@" + quote(host)
+}
+
+// renderRoleOptions renders WITH PASSWORD "pw", VALID UNTIL "ts", CONNECTION LIMIT n, FLAG, ...
+func renderRoleOptions(perm *models.Permission) []string {
+	var options []string
+	if perm.Password != "" {
+		options = append(options, "PASSWORD "+quote(perm.Password))
+	}
+	if perm.ValidUntil != "" {
+		options = append(options, "VALID UNTIL "+quote(perm.ValidUntil))
+	}
+	if perm.ConnectionLimit != "" {
+		options = append(options, "CONNECTION LIMIT "+perm.ConnectionLimit)
+	}
+	options = append(options, perm.RoleFlags...)
+	if len(options) == 0 {
+		return nil
+	}
+	return []string{"WITH", strings.Join(options, ", ")}
+}
+
+// =============================================================================
+// PUBSUB
+// =============================================================================
+
+func renderPubSub(op string, q *models.Query) (string, error) {
+	text := op + " " + q.Channel
+	if op == "NOTIFY" && q.Payload != "" {
+		text += ", " + quote(q.Payload)
+	}
+	return text, nil
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/models"
+)
+
+// Every renderable operation renders as written and parses back into the
+// same Query
+func TestRenderParseRoundTrip(t *testing.T) {
+	inputs := []string{
+		`GET User`,
+		`GET User WHERE age > 18 AND name LIKE "A%" ORDER BY age DESC LIMIT 10 OFFSET 20`,
+		`GET email FROM User DISTINCT`,
+		`GET name, email FROM User WHERE active = true`,
+		`GET User WITH UPPER(name) AS upper_name`,
+		`GET User PAGE 2 SIZE 20`,
+		`GET User ORDER BY id LIMIT 10 AFTER 42`,
+		`GET User WHERE id = 1 FOR UPDATE NOWAIT`,
+		`GET User WHERE name = "a" COLLATE en`,
+		`GET Order WHERE status IN ("a", "b") AND total BETWEEN 1 AND 10`,
+		`GET User WHERE deleted_at IS NULL`,
+		`GET Post WHERE body SEARCH "hello world"`,
+		`GET Store WHERE location NEAR POINT(-73.97, 40.77) DISTANCE 5000`,
+		`GET Store WHERE location WITHIN POLYGON(POINT(0, 0), POINT(0, 10), POINT(10, 10), POINT(0, 0))`,
+		`GET Event FINAL SAMPLE 0.1`,
+		`GET Account AS OF "2024-01-01"`,
+		`COUNT * FROM Order GROUP BY status HAVING COUNT(*) > 5`,
+		`COUNT * FROM Order WHERE year = 2024 GROUP BY status FACET total (SUM amount), by_region (COUNT * GROUP BY region)`,
+		`SUM amount FROM Order`,
+		`ROW NUMBER OVER (ORDER BY id) FROM Entity`,
+		`LAG amount 2 DEFAULT 0 OVER (PARTITION BY user_id ORDER BY created_at) FROM Order`,
+		`INNER JOIN User Order ON id = user_id`,
+		`UNION (GET User WHERE age > 50) (GET User WHERE role = "premium")`,
+		`CTE active AS (GET User WHERE active = true) GET active`,
+		`SUBQUERY id IN (GET User WHERE active = true)`,
+		`EXISTS (GET User WHERE email = "a")`,
+		`CREATE User WITH name = "Alice", age = 25 RETURNING id`,
+		`CREATE Session WITH id = "abc" TTL 30m`,
+		`CREATE User WITH email = "a" IF NOT EXISTS`,
+		`UPSERT User WITH email = "a", name = "b" ON email`,
+		`UPSERT User WITH email = "a", name = "b" ON email UPDATE SET hits = hits + 1`,
+		`UPSERT User WITH email = "a", name = "b" ON CONSTRAINT users_email_key`,
+		`UPSERT User WITH email = "a", name = "b"`,
+		`UPSERT User WITH email = "a", name = "b" ON email DO NOTHING`,
+		`REPLACE User WITH id = 1, name = "John"`,
+		`UPDATE Account SET balance = balance + 100 WHERE id = 1`,
+		`DELETE User WHERE id = 1 RETURNING id`,
+		`BULK INSERT User WITH [name = "a"] [name = "b"]`,
+		`BULK UPSERT User WITH [email = "a", name = "x"] [email = "b", name = "y"] ON email`,
+		`CREATE Archive FROM GET id, total FROM Order WHERE status = "done"`,
+		`CREATE TABLE User WITH id:AUTO, name:STRING`,
+		`ALTER TABLE User ADD name:STRING`,
+		`ALTER TABLE User DROP name`,
+		`DROP TABLE User`,
+		`TRUNCATE Log`,
+		`RENAME TABLE User TO Customer`,
+		`CREATE INDEX User idx_email:email UNIQUE`,
+		`DROP INDEX User idx_email`,
+		`CREATE DATABASE analytics`,
+		`DROP DATABASE analytics`,
+		`CREATE VIEW ActiveUser AS GET User WHERE active = true`,
+		`DROP VIEW ActiveUser`,
+		`CREATE COLLECTION User`,
+		`DROP COLLECTION User`,
+		`CREATE SEQUENCE order_seq START:1000 INCREMENT:1 MIN:1 MAX:999999 CACHE:10 CYCLE`,
+		`ALTER SEQUENCE order_seq RESTART:5000`,
+		`DROP SEQUENCE order_seq`,
+		`CREATE EXTENSION postgis SCHEMA:public`,
+		`DROP EXTENSION postgis`,
+		`CREATE SCHEMA reporting OWNER:admin_user`,
+		`DROP SCHEMA reporting`,
+		`CREATE TYPE order_status AS ENUM VALUES:pending, processing, shipped`,
+		`CREATE TYPE address AS COMPOSITE WITH street:STRING, city:STRING`,
+		`ALTER TYPE order_status ADD_VALUE:cancelled`,
+		`ALTER TYPE order_status RENAME_VALUE:pending:waiting`,
+		`DROP TYPE order_status`,
+		`CREATE DOMAIN positive_int AS INT DEFAULT:0`,
+		`DROP DOMAIN positive_int`,
+		`CREATE FUNCTION add_numbers RETURNS:INT LANGUAGE:sql`,
+		`ALTER FUNCTION add_numbers OWNER:admin`,
+		`DROP FUNCTION add_numbers`,
+		`CREATE TRIGGER audit_log ON User TIMING:AFTER EVENTS:INSERT FOREACH:ROW FUNCTION:log_changes`,
+		`DROP TRIGGER audit_log ON User`,
+		`CREATE POLICY user_isolation ON User FOR:SELECT TO:app_user`,
+		`DROP POLICY user_isolation ON User`,
+		`CREATE PARTITION events_2024 OF Event FOR VALUES FROM ("2024-01-01") TO ("2025-01-01")`,
+		`CREATE PARTITION logs_h0 OF Log FOR VALUES WITH (MODULUS 4, REMAINDER 0)`,
+		`CREATE RULE protect_delete ON User EVENT:DELETE ACTION:NOTHING`,
+		`DROP RULE protect_delete ON User`,
+		`BEGIN`,
+		`COMMIT`,
+		`ROLLBACK`,
+		`SAVEPOINT s1`,
+		`ROLLBACK TO s1`,
+		`RELEASE SAVEPOINT s1`,
+		`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`,
+		`LOCK TABLES Account WRITE`,
+		`UNLOCK TABLES`,
+		`GRANT READ, WRITE ON Order TO app`,
+		`GRANT USAGE, CREATE ON SCHEMA reporting TO analyst`,
+		`REVOKE DELETE ON Order FROM intern`,
+		`CREATE USER analyst WITH PASSWORD "secret123"`,
+		`ALTER USER john WITH PASSWORD "x"`,
+		`DROP USER john`,
+		`CREATE ROLE admin`,
+		`ALTER ROLE app WITH NOLOGIN`,
+		`DROP ROLE analyst`,
+		`ASSIGN ROLE admin TO bob`,
+		`REVOKE ROLE analyst FROM john`,
+		`LISTEN orders`,
+		`NOTIFY orders, "created 42"`,
+		`UNLISTEN orders`,
+	}
+	for _, input := range inputs {
+		q, err := Parse(input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", input, err)
+			continue
+		}
+		rendered, err := Render(q)
+		if err != nil {
+			t.Errorf("Render(%q): %v", input, err)
+			continue
+		}
+		if rendered != input {
+			t.Errorf("Render(%q) = %q", input, rendered)
+		}
+		reparsed, err := Parse(rendered)
+		if err != nil {
+			t.Errorf("Parse(Render(%q)): %v", input, err)
+			continue
+		}
+		if !reflect.DeepEqual(q, reparsed) {
+			t.Errorf("Parse(Render(%q)) differs from the parsed query", input)
+		}
+	}
+}
+
+// A Query that no OQL statement spells fails with ErrNotRenderable rather
+// than losing data or rendering text Parse rejects
+func TestRenderNotRenderable(t *testing.T) {
+	email := &models.Expression{Type: "FIELD", Value: "email"}
+	name := models.Field{
+		NameExpr:  &models.Expression{Type: "FIELD", Value: "name"},
+		ValueExpr: &models.Expression{Type: "STRING", Value: "b"},
+	}
+	row := []models.Field{{NameExpr: email, ValueExpr: &models.Expression{Type: "STRING", Value: "a"}}, name}
+
+	tests := []struct {
+		name  string
+		query *models.Query
+	}{
+		{"nil query", nil},
+		{"UPSERT update without a conflict target", &models.Query{
+			Operation: "UPSERT", Entity: "User", Fields: row,
+			Upsert: &models.Upsert{UpdateFields: []models.Field{name}},
+		}},
+		{"UPSERT that updates nothing", &models.Query{
+			Operation: "UPSERT", Entity: "User", Fields: row,
+			Upsert: &models.Upsert{ConflictFields: []*models.Expression{email}, UpdateFields: []models.Field{}},
+		}},
+		{"BULK UPSERT without a conflict target", &models.Query{
+			Operation: "BULK UPSERT", Entity: "User", BulkData: [][]models.Field{row},
+			Upsert: &models.Upsert{},
+		}},
+		{"BULK UPSERT update without a conflict target", &models.Query{
+			Operation: "BULK UPSERT", Entity: "User", BulkData: [][]models.Field{row},
+			Upsert: &models.Upsert{UpdateFields: []models.Field{name}},
+		}},
+		{"BULK UPSERT without an Upsert", &models.Query{
+			Operation: "BULK UPSERT", Entity: "User", BulkData: [][]models.Field{row},
+		}},
+	}
+	for _, tt := range tests {
+		rendered, err := Render(tt.query)
+		if !errors.Is(err, ErrNotRenderable) {
+			t.Errorf("%s: Render = %q, %v; want ErrNotRenderable", tt.name, rendered, err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+
+	"github.com/omniql-engine/omniql/mapping"
+	"github.com/omniql-engine/omniql/engine/ast"
+	"github.com/omniql-engine/omniql/engine/lexer"
+	"github.com/omniql-engine/omniql/engine/models"
+)
+
+// =============================================================================
+// SCHEMA-AWARE VALIDATION (optional)
+// =============================================================================
+
+// SchemaProvider exposes the live schema to the parser
+// When set, entity and column references are checked at parse time
+type SchemaProvider interface {
+	// Columns returns the column names of a table (ok = false if table is unknown)
+	Columns(table string) (columns []string, ok bool)
+}
+
+// StaticSchema is a map-backed SchemaProvider: table name -> column names
+type StaticSchema map[string][]string
+
+// Columns implements SchemaProvider
+func (s StaticSchema) Columns(table string) ([]string, bool) {
+	columns, ok := s[table]
+	return columns, ok
+}
+
+// ParseWithSchema parses OQL and validates entities and columns against schema
+// A nil schema behaves exactly like Parse
+func ParseWithSchema(input string, schema SchemaProvider) (*models.Query, error) {
+	p, err := New(input)
+	if err != nil {
+		return nil, err
+	}
+	p.schema = schema
+
+	node, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.validateSchema(node); err != nil {
+		return nil, err
+	}
+
+	return nodeToQuery(node), nil
+}
+
+// schemaScope holds the tables a query may reference, keyed by table name
+type schemaScope struct {
+	tables  map[string]map[string]bool
+	order   []string        // Table names in FROM/JOIN order
+	aliases map[string]bool // SELECT/window aliases usable in ORDER BY and HAVING
+}
+
+// validateSchema checks a parsed query against p.schema (no-op when unset)
+func (p *Parser) validateSchema(node *ast.QueryNode) error {
+	if p.schema == nil || node == nil {
+		return nil
+	}
+
+	// Set operations: validate each side on its own
+	if node.SetOperation != nil {
+		if err := p.validateSchema(node.SetOperation.LeftQuery); err != nil {
+			return err
+		}
+		return p.validateSchema(node.SetOperation.RightQuery)
+	}
+
+	// Only data queries reference columns - DDL/TCL/DCL are left to the database
+	switch mapping.OperationGroups[node.Operation] {
+	case "CRUD", "DQL":
+	default:
+		return nil
+	}
+	// CTE and SUBQUERY entities are query names, not tables
+	if node.Entity == "" || node.Operation == "CTE" || node.Operation == "SUBQUERY" {
+		return nil
+	}
+
+	scope := &schemaScope{
+		tables:  make(map[string]map[string]bool),
+		aliases: make(map[string]bool),
+	}
+	if err := p.addSchemaTable(scope, node.Entity, node.Operation); err != nil {
+		return err
+	}
+	for _, join := range node.Joins {
+		if err := p.addSchemaTable(scope, join.Table, node.Operation); err != nil {
+			return err
+		}
+	}
+	for _, sc := range node.SelectColumns {
+		if sc.Alias != "" {
+			scope.aliases[strings.ToLower(sc.Alias)] = true
+		}
+	}
+	for _, wf := range node.WindowFunctions {
+		if wf.Alias != "" {
+			scope.aliases[strings.ToLower(wf.Alias)] = true
+		}
+	}
+
+	// Column references (values on the right-hand side are not checked)
+	var exprs []*ast.ExpressionNode
+	exprs = append(exprs, node.Columns...)
+	exprs = append(exprs, node.GroupBy...)
+	exprs = append(exprs, node.Returning...)
+	for _, sc := range node.SelectColumns {
+		exprs = append(exprs, sc.ExpressionObj)
+	}
+	for _, f := range node.Fields {
+		exprs = append(exprs, f.NameExpr)
+	}
+	for _, row := range node.BulkData {
+		for _, f := range row {
+			exprs = append(exprs, f.NameExpr)
+		}
+	}
+	if node.Upsert != nil {
+		exprs = append(exprs, node.Upsert.ConflictFields...)
+		exprs = append(exprs, conditionFieldExprs(node.Upsert.ConflictWhere)...)
+		for _, f := range node.Upsert.UpdateFields {
+			exprs = append(exprs, f.NameExpr)
+		}
+	}
+	if node.Conditions != nil {
+		exprs = append(exprs, conditionFieldExprs(node.Conditions.Conditions)...)
+	}
+	for _, join := range node.Joins {
+		exprs = append(exprs, join.LeftExpr, join.RightExpr)
+	}
+	if node.Aggregate != nil {
+		exprs = append(exprs, node.Aggregate.FieldExpr)
+		for _, ob := range node.Aggregate.OrderBy {
+			exprs = append(exprs, ob.FieldExpr)
+		}
+	}
+	for _, facet := range node.Facets {
+		exprs = append(exprs, facet.Aggregate.FieldExpr)
+		exprs = append(exprs, facet.GroupBy...)
+		for _, ob := range facet.Aggregate.OrderBy {
+			exprs = append(exprs, ob.FieldExpr)
+		}
+	}
+	for _, wf := range node.WindowFunctions {
+		exprs = append(exprs, wf.FieldExpr)
+		exprs = append(exprs, wf.PartitionBy...)
+		for _, ob := range wf.OrderBy {
+			exprs = append(exprs, ob.FieldExpr)
+		}
+	}
+	for _, expr := range exprs {
+		if err := p.validateSchemaExpr(scope, expr, false); err != nil {
+			return err
+		}
+	}
+
+	// ORDER BY and HAVING may also reference aliases
+	for _, ob := range node.OrderBy {
+		if ob.FieldExpr != nil && mapping.IsSearchRankField(ob.FieldExpr.Value) {
+			continue
+		}
+		if err := p.validateSchemaExpr(scope, ob.FieldExpr, true); err != nil {
+			return err
+		}
+	}
+	for _, expr := range conditionFieldExprs(node.Having) {
+		if err := p.validateSchemaExpr(scope, expr, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addSchemaTable resolves entity to its table name and loads its columns
+func (p *Parser) addSchemaTable(scope *schemaScope, entity string, operation string) error {
+	table := schemaTableName(entity, operation)
+	if _, loaded := scope.tables[table]; loaded {
+		return nil
+	}
+	columns, ok := p.schema.Columns(table)
+	if !ok {
+		return p.errorAt(p.tokenAtValue(entity), fmt.Sprintf("unknown entity '%s' (no table %s)", entity, table))
+	}
+	set := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		set[strings.ToLower(col)] = true
+	}
+	scope.tables[table] = set
+	scope.order = append(scope.order, table)
+	return nil
+}
+
+// validateSchemaExpr walks an expression and checks every FIELD reference
+func (p *Parser) validateSchemaExpr(scope *schemaScope, expr *ast.ExpressionNode, allowAliases bool) error {
+	if expr == nil {
+		return nil
+	}
+
+	if expr.Type == "FIELD" && expr.Value != "*" {
+		name := strings.ToLower(expr.Value)
+		if allowAliases && scope.aliases[name] {
+			return nil
+		}
+
+		// Qualified reference: entity.column or table.column
+		if dot := strings.LastIndex(name, "."); dot > 0 {
+			qualifier, column := name[:dot], name[dot+1:]
+			for _, table := range scope.order {
+				if table == qualifier || table == schemaTableName(qualifier, "GET") {
+					if column == "*" || scope.tables[table][column] {
+						return nil
+					}
+					return p.errorAt(p.tokenAt(expr.Position), fmt.Sprintf("unknown column %s on %s", column, table))
+				}
+			}
+			return p.errorAt(p.tokenAt(expr.Position), fmt.Sprintf("unknown table %s", qualifier))
+		}
+
+		for _, table := range scope.order {
+			if scope.tables[table][name] {
+				return nil
+			}
+		}
+		return p.errorAt(p.tokenAt(expr.Position), fmt.Sprintf("unknown column %s on %s", expr.Value, strings.Join(scope.order, ", ")))
+	}
+
+	if err := p.validateSchemaExpr(scope, expr.Left, allowAliases); err != nil {
+		return err
+	}
+	if err := p.validateSchemaExpr(scope, expr.Right, allowAliases); err != nil {
+		return err
+	}
+	for _, arg := range expr.FunctionArgs {
+		if err := p.validateSchemaExpr(scope, arg, allowAliases); err != nil {
+			return err
+		}
+	}
+	for _, cc := range expr.CaseConditions {
+		if cc.Condition != nil {
+			if err := p.validateSchemaExpr(scope, cc.Condition.FieldExpr, allowAliases); err != nil {
+				return err
+			}
+		}
+	}
+	for _, part := range expr.PartitionBy {
+		if err := p.validateSchemaExpr(scope, part, allowAliases); err != nil {
+			return err
+		}
+	}
+	for _, ob := range expr.WindowOrderBy {
+		if err := p.validateSchemaExpr(scope, ob.FieldExpr, allowAliases); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conditionFieldExprs collects the left-hand side of every condition (recursive)
+func conditionFieldExprs(conds []ast.ConditionNode) []*ast.ExpressionNode {
+	var exprs []*ast.ExpressionNode
+	for _, c := range conds {
+		if c.Operator == "GROUP" {
+			exprs = append(exprs, conditionFieldExprs(c.Nested)...)
+			continue
+		}
+		exprs = append(exprs, c.FieldExpr)
+	}
+	return exprs
+}
+
+// schemaTableName applies mapping.TableNamingRules (same as the SQL translators)
+func schemaTableName(entity string, operation string) string {
+	if mapping.TableNamingRules[operation] == "plural" {
+		return mapping.EntityTable(entity)
+	}
+	return strings.ToLower(entity)
+}
+
+// tokenAt finds the token starting at a byte offset (for error positions)
+func (p *Parser) tokenAt(position int) lexer.Token {
+	for _, tok := range p.tokens {
+		if tok.Position == position {
+			return tok
+		}
+	}
+	return p.current()
+}
+
+// tokenAtValue finds the first token with the given text
+func (p *Parser) tokenAtValue(value string) lexer.Token {
+	for _, tok := range p.tokens {
+		if tok.Value == value {
+			return tok
+		}
+	}
+	return p.current()
+}
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/omniql-engine/omniql/engine/ast"
 )
 
@@ -20,8 +23,15 @@ func (p *Parser) parseTCL(op string) (*ast.QueryNode, error) {
 	p.advance() // consume operation
 
 	// BEGIN and START are aliases
+	// BEGIN DEFERRED | IMMEDIATE | EXCLUSIVE picks SQLite's locking mode
 	if op == "BEGIN" || op == "START" {
-		// No additional parsing needed
+		if !p.isAtEnd() {
+			switch mode := strings.ToUpper(p.current().Value); mode {
+			case "DEFERRED", "IMMEDIATE", "EXCLUSIVE":
+				p.advance()
+				node.Transaction.Mode = mode
+			}
+		}
 		return node, nil
 	}
 
@@ -52,7 +62,31 @@ func (p *Parser) parseTCL(op string) (*ast.QueryNode, error) {
 		node.Transaction.SavepointName = name
 	}
 
-	// SET TRANSACTION ISOLATION LEVEL ...
+	// LOCK TABLES Entity READ|WRITE [, Entity READ|WRITE]*
+	if op == "LOCK TABLES" {
+		for {
+			entityTok := p.current()
+			entity, err := p.expectIdentifier()
+			if err != nil {
+				return nil, err
+			}
+			mode := strings.ToUpper(p.current().Value)
+			if mode != "READ" && mode != "WRITE" {
+				return nil, p.error(fmt.Sprintf("expected READ or WRITE after %s, got '%s'", entity, p.current().Value))
+			}
+			p.advance()
+			node.Transaction.LockTables = append(node.Transaction.LockTables, ast.TableLockNode{
+				Entity:   entity,
+				Mode:     mode,
+				Position: entityTok.Position,
+			})
+			if !p.match(",") {
+				break
+			}
+		}
+	}
+
+	// SET TRANSACTION [ISOLATION LEVEL level] [,] [READ ONLY | READ WRITE]
 	if op == "SET TRANSACTION" {
 		if p.match("ISOLATION") {
 			p.expect("LEVEL")
@@ -60,7 +94,30 @@ func (p *Parser) parseTCL(op string) (*ast.QueryNode, error) {
 			if err != nil {
 				return nil, err
 			}
+			// READ COMMITTED, READ UNCOMMITTED, REPEATABLE READ
+			next := strings.ToUpper(p.current().Value)
+			switch strings.ToUpper(level) {
+			case "READ":
+				if next == "COMMITTED" || next == "UNCOMMITTED" {
+					level += " " + p.advance().Value
+				}
+			case "REPEATABLE":
+				if next == "READ" {
+					level += " " + p.advance().Value
+				}
+			}
 			node.Transaction.IsolationLevel = level
+			p.match(",")
+		}
+		if !p.isAtEnd() && strings.ToUpper(p.current().Value) == "READ" {
+			p.advance()
+			switch access := strings.ToUpper(p.current().Value); access {
+			case "ONLY", "WRITE":
+				p.advance()
+				node.Transaction.ReadOnly = access == "ONLY"
+			default:
+				return nil, p.error(fmt.Sprintf("expected ONLY or WRITE after READ, got '%s'", p.current().Value))
+			}
 		}
 	}
 
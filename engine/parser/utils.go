@@ -1,11 +1,15 @@
 package parser
 
 import (
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/omniql-engine/omniql/mapping"
 	"github.com/omniql-engine/omniql/engine/ast"
 	"github.com/omniql-engine/omniql/engine/lexer"
+	"github.com/omniql-engine/omniql/engine/models"
 )
 
 // =============================================================================
@@ -83,6 +87,7 @@ func (p *Parser) parseIdentifierListAsExpressions() ([]*ast.ExpressionNode, erro
 
 // parseFieldAssignments parses: field:value, field2:value2, ... (100% TrueAST)
 // Also handles expressions: field = value + 10, field = UPPER(name)
+// and JSON path targets: metadata->'plan' = "pro"
 func (p *Parser) parseFieldAssignments() ([]ast.FieldNode, error) {
 	var fields []ast.FieldNode
 
@@ -100,6 +105,10 @@ func (p *Parser) parseFieldAssignments() ([]ast.FieldNode, error) {
 		} else {
 			// Separate tokens: field : value or field = value
 			name := tok.Value
+			target, err := p.parseJSONPathKeys(makeFieldExpr(name, tok.Position))
+			if err != nil {
+				return nil, err
+			}
 			if !p.match(":", "=") {
 				return nil, p.error("expected ':' or '=' after field name")
 			}
@@ -109,6 +118,7 @@ func (p *Parser) parseFieldAssignments() ([]ast.FieldNode, error) {
 			if err != nil {
 				return nil, err
 			}
+			field.NameExpr = target
 			fields = append(fields, field)
 		}
 
@@ -147,8 +157,10 @@ func (p *Parser) parseFieldValue(name string, pos int) (ast.FieldNode, error) {
 		for !p.isAtEnd() && p.current().Value != ")" {
 			argTok := p.current()
 			argVal := p.advance().Value
-			// Determine if arg is field or literal
-			if isFieldName(argVal) {
+			// Determine if arg is string, field or literal
+			if argTok.Type == lexer.TOKEN_STRING {
+				args = append(args, &ast.ExpressionNode{Type: "STRING", Value: argVal, Position: argTok.Position})
+			} else if isFieldName(argVal) {
 				args = append(args, makeFieldExpr(argVal, argTok.Position))
 			} else {
 				args = append(args, makeLiteralExpr(argVal, argTok.Position))
@@ -198,7 +210,7 @@ func isFieldName(s string) bool {
 	return true
 }
 
-// parseColumnDefinitions parses: col:TYPE(size), col2:TYPE2:constraint, ... (100% TrueAST)
+// parseColumnDefinitions parses: col:TYPE(size), col2:TYPE2:constraint, col3:TYPE[], ... (100% TrueAST)
 func (p *Parser) parseColumnDefinitions() ([]ast.FieldNode, error) {
 	var columns []ast.FieldNode
 
@@ -239,7 +251,31 @@ func (p *Parser) parseColumnDefinitions() ([]ast.FieldNode, error) {
 				p.match(",")
 			}
 			p.expect(")")
-			col.ValueExpr = makeLiteralExpr(typ+"("+strings.Join(sizeParts, ",")+")", tok.Position)
+			typ += "(" + strings.Join(sizeParts, ",") + ")"
+		}
+
+		// Array type: TEXT[]
+		if p.current().Value == "[" && p.peek(1).Value == "]" {
+			p.advance()
+			p.advance()
+			typ += "[]"
+		}
+
+		// Constraints after the size or array: STRING(100):NOT_NULL, TEXT[]:NOT_NULL
+		for p.match(":") {
+			for _, constraint := range strings.Split(p.advance().Value, ":") {
+				col.Constraints = append(col.Constraints, strings.ToUpper(constraint))
+			}
+		}
+		col.ValueExpr = makeLiteralExpr(typ, tok.Position)
+
+		// Computed column: GENERATED [ALWAYS] AS (expr) [STORED]
+		if strings.ToUpper(p.current().Value) == "GENERATED" {
+			expr, err := p.parseGeneratedColumn()
+			if err != nil {
+				return nil, err
+			}
+			col.GeneratedExpr = expr
 		}
 
 		columns = append(columns, col)
@@ -250,4 +286,155 @@ func (p *Parser) parseColumnDefinitions() ([]ast.FieldNode, error) {
 	}
 
 	return columns, nil
-}
\ No newline at end of file
+}
+
+// parseGeneratedColumn parses: GENERATED [ALWAYS] AS (expr) [STORED]
+// Only stored columns are supported, so STORED is optional
+func (p *Parser) parseGeneratedColumn() (*ast.ExpressionNode, error) {
+	p.advance() // consume GENERATED
+	if strings.ToUpper(p.current().Value) == "ALWAYS" {
+		p.advance()
+	}
+	if err := p.expect("AS"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if strings.ToUpper(p.current().Value) == "STORED" {
+		p.advance()
+	}
+	return expr, nil
+}
+
+// =============================================================================
+// CURSOR TOKENS (keyset pagination)
+// =============================================================================
+
+// cursorPrefix marks opaque AFTER tokens so they lex as a single identifier
+const cursorPrefix = "cur_"
+
+var cursorEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// cursorValue is one value of a cursor token with the expression type it is
+// compared as (NUMBER, STRING, BOOLEAN or NULL), so a numeric key is not
+// compared as text on the next page
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// EncodeCursor packs ORDER BY values of the last row into an opaque token
+func EncodeCursor(values []*models.Expression) string {
+	items := make([]cursorValue, len(values))
+	for i, v := range values {
+		switch {
+		case v == nil || (v.Type == "FIELD" && strings.EqualFold(v.Value, "NULL")):
+			items[i] = cursorValue{Type: "NULL"}
+		case v.Type == "NUMBER" || v.Type == "BOOLEAN":
+			items[i] = cursorValue{Type: v.Type, Value: v.Value}
+		default:
+			items[i] = cursorValue{Type: "STRING", Value: v.Value}
+		}
+	}
+	data, _ := json.Marshal(items)
+	return cursorPrefix + cursorEncoding.EncodeToString(data)
+}
+
+// DecodeCursor unpacks a token produced by EncodeCursor. Tokens of earlier
+// versions, which hold the values as strings, decode to untyped literals.
+func DecodeCursor(token string) ([]*models.Expression, error) {
+	if !IsCursorToken(token) {
+		return nil, fmt.Errorf("invalid cursor '%s'", token)
+	}
+	data, err := cursorEncoding.DecodeString(strings.TrimPrefix(token, cursorPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor '%s'", token)
+	}
+	var items []cursorValue
+	if err := json.Unmarshal(data, &items); err != nil {
+		var legacy []string
+		if json.Unmarshal(data, &legacy) != nil {
+			return nil, fmt.Errorf("invalid cursor '%s'", token)
+		}
+		values := make([]*models.Expression, len(legacy))
+		for i, v := range legacy {
+			values[i] = &models.Expression{Type: "LITERAL", Value: v}
+		}
+		return values, nil
+	}
+	values := make([]*models.Expression, len(items))
+	for i, item := range items {
+		switch item.Type {
+		case "NULL":
+			values[i] = &models.Expression{Type: "FIELD", Value: "NULL"}
+		case "NUMBER", "STRING", "BOOLEAN":
+			values[i] = &models.Expression{Type: item.Type, Value: item.Value}
+		default:
+			return nil, fmt.Errorf("invalid cursor '%s'", token)
+		}
+	}
+	return values, nil
+}
+
+// IsCursorToken checks if value looks like a token produced by EncodeCursor
+func IsCursorToken(value string) bool {
+	return strings.HasPrefix(value, cursorPrefix)
+}
+
+// keysetConditions builds the seek predicate for ORDER BY a, b AFTER (x, y):
+// a > x OR (a = x AND b > y), flipping > to < for DESC fields
+func keysetConditions(orderBy []ast.OrderByNode, after []*ast.ExpressionNode) []ast.ConditionNode {
+	var groups []ast.ConditionNode
+	for i := range orderBy {
+		var group []ast.ConditionNode
+		for j := 0; j < i; j++ {
+			group = append(group, ast.ConditionNode{
+				FieldExpr: orderBy[j].FieldExpr,
+				Operator:  "=",
+				ValueExpr: after[j],
+				Logic:     logicFor(j, "AND"),
+				Position:  after[j].Position,
+			})
+		}
+		op := ">"
+		if orderBy[i].Direction == "DESC" {
+			op = "<"
+		}
+		group = append(group, ast.ConditionNode{
+			FieldExpr: orderBy[i].FieldExpr,
+			Operator:  op,
+			ValueExpr: after[i],
+			Logic:     logicFor(i, "AND"),
+			Position:  after[i].Position,
+		})
+
+		if len(group) == 1 {
+			group[0].Logic = logicFor(i, "OR")
+			groups = append(groups, group[0])
+			continue
+		}
+		groups = append(groups, ast.ConditionNode{
+			Operator: "GROUP",
+			Nested:   group,
+			Logic:    logicFor(i, "OR"),
+			Position: after[i].Position,
+		})
+	}
+	return groups
+}
+
+// logicFor returns logic for the i-th condition in a list (first has none)
+func logicFor(i int, logic string) string {
+	if i == 0 {
+		return ""
+	}
+	return logic
+}
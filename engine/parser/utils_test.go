@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/models"
+)
+
+// Cursor values keep their type, so a numeric key is not compared as text
+func TestCursorKeepsValueTypes(t *testing.T) {
+	values := []*models.Expression{
+		{Type: "NUMBER", Value: "42"},
+		{Type: "STRING", Value: "2026-10-15T10:00:00Z"},
+		{Type: "BOOLEAN", Value: "true"},
+		{Type: "STRING", Value: "007"},
+		{Type: "FIELD", Value: "NULL"},
+	}
+	q, err := Parse("GET Event ORDER BY a, b, c, d, e AFTER " + EncodeCursor(values) + " LIMIT 10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.After) != len(values) {
+		t.Fatalf("got %d cursor values, want %d", len(q.After), len(values))
+	}
+	for i, want := range values {
+		if got := q.After[i]; got.Type != want.Type || got.Value != want.Value {
+			t.Errorf("cursor value %d = %s %q, want %s %q", i, got.Type, got.Value, want.Type, want.Value)
+		}
+	}
+}
+
+func TestPlainAfterValueKeepsType(t *testing.T) {
+	tests := []struct {
+		input, typ string
+	}{
+		{`GET Event ORDER BY id AFTER 42 LIMIT 10`, "NUMBER"},
+		{`GET Event ORDER BY code AFTER "42" LIMIT 10`, "STRING"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if len(q.After) != 1 || q.After[0].Type != tt.typ {
+			t.Errorf("Parse(%q): AFTER %+v, want one %s", tt.input, q.After, tt.typ)
+		}
+	}
+}
+
+// Tokens that hold the values as strings still decode
+func TestDecodeLegacyCursor(t *testing.T) {
+	values, err := DecodeCursor(cursorPrefix + cursorEncoding.EncodeToString([]byte(`["7","x"]`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || values[0].Value != "7" || values[1].Value != "x" {
+		t.Errorf("DecodeCursor: %+v", values)
+	}
+}
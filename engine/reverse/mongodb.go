@@ -1,10 +1,13 @@
 package reverse
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/omniql-engine/omniql/engine/models"
 )
@@ -13,10 +16,12 @@ import (
 // ENTRY POINT
 // ============================================================================
 
+// MongoDBToQuery converts a MongoDB command document, or a mongosh statement
+// such as db.users.find({age: {$gt: 30}}).limit(5), to an OQL Query
 func MongoDBToQuery(jsonStr string) (*models.Query, error) {
-	var doc map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &doc); err != nil {
-		return nil, fmt.Errorf("%w: invalid JSON: %v", ErrParseError, err)
+	doc, err := parseMongoCommand(jsonStr)
+	if err != nil {
+		return nil, err
 	}
 
 	// ==================== CRUD ====================
@@ -265,18 +270,67 @@ func convertMongoUpdateOne(collection string, doc map[string]interface{}) (*mode
 		query.Conditions = conditions
 	}
 
-	if update, ok := doc["update"].(map[string]interface{}); ok {
-		query.Fields = convertMongoUpdate(update)
-	}
+	query.Fields = convertMongoUpdateDoc(doc["update"])
 
 	if upsert, ok := doc["upsert"].(bool); ok && upsert {
 		query.Operation = "UPSERT"
 		query.Upsert = &models.Upsert{}
+		filter, _ := doc["filter"].(map[string]interface{})
+		update, _ := doc["update"].(map[string]interface{})
+		if keyed := convertMongoKeyedUpsert(query.Entity, filter, update); keyed != nil {
+			return keyed, nil
+		}
 	}
 
 	return query, nil
 }
 
+// convertMongoKeyedUpsert reads an upsert whose filter only matches fields by
+// value as UPSERT ... ON those fields: the matched values are inserted along
+// with $set, which updates a match, or $setOnInsert, which leaves it (DO NOTHING).
+// It returns nil for any other upsert.
+func convertMongoKeyedUpsert(entity string, filter, update map[string]interface{}) *models.Query {
+	if len(filter) == 0 {
+		return nil
+	}
+	for name := range update {
+		if name != "$set" && name != "$setOnInsert" {
+			return nil
+		}
+	}
+
+	query := &models.Query{Operation: "UPSERT", Entity: entity, Upsert: &models.Upsert{}}
+	inserted := map[string]bool{}
+	for _, name := range sortedKeys(filter) {
+		if strings.HasPrefix(name, "$") {
+			return nil
+		}
+		if _, isOperator := filter[name].(map[string]interface{}); isOperator {
+			return nil
+		}
+		query.Upsert.ConflictFields = append(query.Upsert.ConflictFields, FieldExpr(name))
+		query.Fields = append(query.Fields, models.Field{NameExpr: FieldExpr(name), ValueExpr: mongoLiteral(filter[name])})
+		inserted[name] = true
+	}
+
+	if set, ok := update["$set"].(map[string]interface{}); ok {
+		for _, field := range convertMongoDocument(set) {
+			query.Fields = append(query.Fields, field)
+			query.Upsert.UpdateFields = append(query.Upsert.UpdateFields, models.Field{NameExpr: field.NameExpr})
+		}
+		return query
+	}
+	if onInsert, ok := update["$setOnInsert"].(map[string]interface{}); ok {
+		for _, field := range convertMongoDocument(onInsert) {
+			if !inserted[field.NameExpr.Value] {
+				query.Fields = append(query.Fields, field)
+				query.Upsert.DoNothing = true
+			}
+		}
+	}
+	return query
+}
+
 // ============================================================================
 // CRUD: UPDATE MANY → UPDATE
 // ============================================================================
@@ -295,9 +349,7 @@ func convertMongoUpdateMany(collection string, doc map[string]interface{}) (*mod
 		query.Conditions = conditions
 	}
 
-	if update, ok := doc["update"].(map[string]interface{}); ok {
-		query.Fields = convertMongoUpdate(update)
-	}
+	query.Fields = convertMongoUpdateDoc(doc["update"])
 
 	return query, nil
 }
@@ -403,6 +455,52 @@ func convertMongoDistinct(collection string, doc map[string]interface{}) (*model
 	return query, nil
 }
 
+// MongoDBToQueries converts a MongoDB command that may stand for several OQL
+// queries: createIndexes gives one CREATE INDEX per index it creates. Any
+// other command gives the one query of MongoDBToQuery.
+func MongoDBToQueries(jsonStr string) ([]*models.Query, error) {
+	doc, err := parseMongoCommand(jsonStr)
+	if err != nil {
+		return nil, err
+	}
+	if collection, ok := doc["createIndexes"].(string); ok {
+		return convertMongoCreateIndexes(collection, doc)
+	}
+	query, err := MongoDBToQuery(jsonStr)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Query{query}, nil
+}
+
+// parseMongoCommand reads a command document, or the mongosh statement for one
+func parseMongoCommand(jsonStr string) (map[string]interface{}, error) {
+	if strings.HasPrefix(strings.TrimSpace(jsonStr), "db.") {
+		return parseMongoShell(jsonStr)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON: %v", ErrParseError, err)
+	}
+
+	// The field order of an index key is part of the index
+	if indexes, ok := doc["indexes"].([]interface{}); ok && doc["createIndexes"] != nil {
+		var ordered struct {
+			Indexes []struct {
+				Key mongoIndexKeys `json:"key"`
+			} `json:"indexes"`
+		}
+		if err := json.Unmarshal([]byte(jsonStr), &ordered); err == nil {
+			for i, index := range ordered.Indexes {
+				if spec, ok := indexes[i].(map[string]interface{}); ok && index.Key != nil {
+					spec["key"] = index.Key
+				}
+			}
+		}
+	}
+	return doc, nil
+}
+
 // ============================================================================
 // AGGREGATE → GET with aggregation features
 // ============================================================================
@@ -419,13 +517,31 @@ func convertMongoAggregate(collection string, doc map[string]interface{}) (*mode
 	}
 
 	hasGroup := false
+	joinedAs := ""
 
-	for _, stage := range pipeline {
+	for i, stage := range pipeline {
 		stageMap, ok := stage.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
+		// $graphLookup → recursive CTE; the stages after it are the main query's
+		if graphLookup, ok := stageMap["$graphLookup"].(map[string]interface{}); ok {
+			return convertMongoGraphLookup(query, graphLookup, pipeline[i+1:])
+		}
+
+		// $unionWith → set operation of the stages before it and its pipeline
+		if stageMap["$unionWith"] != nil {
+			return convertMongoUnionWith(collection, pipeline, lastUnionWith(pipeline))
+		}
+
+		// $facet → main aggregate plus one FACET per other branch
+		if facet, ok := stageMap["$facet"].(map[string]interface{}); ok {
+			if err := convertMongoFacet(collection, query, facet); err != nil {
+				return nil, err
+			}
+		}
+
 		// $match → Conditions (before $group) or Having (after $group)
 		if match, ok := stageMap["$match"].(map[string]interface{}); ok {
 			conditions, err := convertMongoFilter(match)
@@ -467,8 +583,26 @@ func convertMongoAggregate(collection string, doc map[string]interface{}) (*mode
 
 		// $lookup → Join
 		if lookup, ok := stageMap["$lookup"].(map[string]interface{}); ok {
-			if join := convertMongoLookup(lookup); join != nil {
+			join, err := convertMongoLookup(query.Entity, lookup)
+			if err != nil {
+				return nil, err
+			}
+			if join != nil {
 				query.Joins = append(query.Joins, *join)
+				joinedAs, _ = lookup["as"].(string)
+			}
+		}
+
+		// $unwind of the joined documents that drops the unmatched rows → INNER JOIN
+		if unwind, ok := stageMap["$unwind"]; ok && joinedAs != "" {
+			path, _ := unwind.(string)
+			preserve := false
+			if unwindMap, ok := unwind.(map[string]interface{}); ok {
+				path, _ = unwindMap["path"].(string)
+				preserve, _ = unwindMap["preserveNullAndEmptyArrays"].(bool)
+			}
+			if path == "$"+joinedAs && !preserve {
+				query.Joins[len(query.Joins)-1].Type = models.InnerJoin
 			}
 		}
 
@@ -485,12 +619,18 @@ func convertMongoAggregate(collection string, doc map[string]interface{}) (*mode
 	// Process advanced stages (window functions, set operations, etc.)
 	ProcessAdvancedPipelineStages(query, pipeline)
 
+	// A join is the operation, as OQL writes it: INNER JOIN Order User ON ...
+	if query.Operation == "GET" && len(query.Joins) > 0 {
+		query.Operation = string(query.Joins[0].Type) + " JOIN"
+	}
+
 	return query, nil
 }
 
 // convertMongoProject handles $project stage with expressions
 func convertMongoProject(query *models.Query, project map[string]interface{}) {
-	for field, val := range project {
+	for _, field := range sortedKeys(project) {
+		val := project[field]
 		// Exclusion: {field: 0}
 		if v, ok := val.(float64); ok && v == 0 {
 			continue
@@ -610,30 +750,144 @@ func convertMongoCollMod(collection string, doc map[string]interface{}) (*models
 	}, nil
 }
 
+// convertMongoCreateIndex converts a createIndexes command that creates one
+// index; MongoDBToQueries reads one with several
 func convertMongoCreateIndex(collection string, doc map[string]interface{}) (*models.Query, error) {
-	query := &models.Query{
-		Operation: "CREATE INDEX",
-		Entity:    TableToEntity(collection),
+	queries, err := convertMongoCreateIndexes(collection, doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) != 1 {
+		return nil, fmt.Errorf("%w: createIndexes with %d indexes in one query (MongoDBToQueries reads each)", ErrNotSupported, len(queries))
+	}
+	return queries[0], nil
+}
+
+// convertMongoCreateIndexes converts each index of a createIndexes command to
+// CREATE INDEX Entity name:columns, as BuildMongoIndexModel reads it: the
+// key fields in order, descending ones marked DESC, and the UNIQUE, SPARSE,
+// TTL seconds and PARTIAL (its filter as Conditions) options. An index
+// without a name gets MongoDB's default, the fields and directions joined
+// by _ (email_1_created_at_-1).
+func convertMongoCreateIndexes(collection string, doc map[string]interface{}) ([]*models.Query, error) {
+	indexes, _ := doc["indexes"].([]interface{})
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("%w: createIndexes without indexes", ErrParseError)
+	}
+
+	queries := make([]*models.Query, 0, len(indexes))
+	for _, index := range indexes {
+		spec, ok := index.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: createIndexes index must be a document", ErrParseError)
+		}
+		query, err := convertMongoIndex(TableToEntity(collection), spec)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+func convertMongoIndex(entity string, spec map[string]interface{}) (*models.Query, error) {
+	keys, ok := spec["key"].(mongoIndexKeys)
+	if keyDoc, isDoc := spec["key"].(map[string]interface{}); isDoc {
+		// Read without its order; sorted to be deterministic
+		keys, ok = nil, true
+		for _, field := range sortedKeys(keyDoc) {
+			keys = append(keys, mongoIndexKey{Field: field, Direction: keyDoc[field]})
+		}
+	}
+	if !ok || len(keys) == 0 {
+		return nil, fmt.Errorf("%w: index without a key", ErrParseError)
 	}
 
-	if indexes, ok := doc["indexes"].([]interface{}); ok && len(indexes) > 0 {
-		if idx, ok := indexes[0].(map[string]interface{}); ok {
-			if name, ok := idx["name"].(string); ok {
-				query.NewName = name
+	var columns, defaultName []string
+	var constraints []string
+	for _, key := range keys {
+		column := key.Field
+		switch direction := key.Direction.(type) {
+		case float64:
+			if direction < 0 {
+				column += " DESC"
 			}
-			if key, ok := idx["key"].(map[string]interface{}); ok {
-				for fieldName := range key {
-					query.Fields = append(query.Fields, models.Field{
-						NameExpr: FieldExpr(fieldName),
-					})
-				}
+		case string:
+			if direction != "text" {
+				return nil, fmt.Errorf("%w: %s index", ErrNotSupported, direction)
+			}
+			if len(constraints) == 0 {
+				constraints = append(constraints, "FULLTEXT")
 			}
+		default:
+			return nil, fmt.Errorf("%w: index direction of %s", ErrParseError, key.Field)
 		}
+		columns = append(columns, column)
+		defaultName = append(defaultName, key.Field, valueToString(key.Direction))
+	}
+
+	name, _ := spec["name"].(string)
+	if name == "" {
+		name = strings.Join(defaultName, "_")
+	}
+	if unique, _ := spec["unique"].(bool); unique {
+		constraints = append(constraints, "UNIQUE")
+	}
+	if sparse, _ := spec["sparse"].(bool); sparse {
+		constraints = append(constraints, "SPARSE")
+	}
+	if seconds, ok := spec["expireAfterSeconds"].(float64); ok {
+		constraints = append(constraints, "TTL", strconv.FormatFloat(seconds, 'f', -1, 64))
 	}
 
+	query := &models.Query{
+		Operation: "CREATE INDEX",
+		Entity:    entity,
+	}
+	if filter, ok := spec["partialFilterExpression"].(map[string]interface{}); ok {
+		conditions, err := convertMongoFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		query.Conditions = conditions
+		constraints = append(constraints, "PARTIAL")
+	}
+	query.Fields = []models.Field{{
+		NameExpr:    FieldExpr(name),
+		ValueExpr:   FieldExpr(strings.Join(columns, ",")),
+		Constraints: constraints,
+	}}
 	return query, nil
 }
 
+// mongoIndexKey is one field of an index key and its direction: 1, -1 or "text"
+type mongoIndexKey struct {
+	Field     string
+	Direction interface{}
+}
+
+// mongoIndexKeys is an index key read in order, which a map would lose
+type mongoIndexKeys []mongoIndexKey
+
+func (k *mongoIndexKeys) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key := mongoIndexKey{Field: token.(string)}
+		if err := decoder.Decode(&key.Direction); err != nil {
+			return err
+		}
+		*k = append(*k, key)
+	}
+	return nil
+}
+
 func convertMongoDropIndex(collection string, doc map[string]interface{}) (*models.Query, error) {
 	query := &models.Query{
 		Operation: "DROP INDEX",
@@ -836,7 +1090,7 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 	isFirst := true
 
 	for field, value := range filter {
-		// Handle $and
+		// Handle $and; a branch joined by OR is a group
 		if field == "$and" {
 			if arr, ok := value.([]interface{}); ok {
 				for _, item := range arr {
@@ -845,12 +1099,14 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 						if err != nil {
 							return nil, err
 						}
-						for i := range subConds {
-							if !isFirst {
-								subConds[i].Logic = "AND"
-							}
-							isFirst = false
+						if len(subConds) == 0 {
+							continue
+						}
+						subConds = groupConditions(subConds, "AND")
+						if !isFirst {
+							subConds[0].Logic = "AND"
 						}
+						isFirst = false
 						conditions = append(conditions, subConds...)
 					}
 				}
@@ -858,8 +1114,10 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 			continue
 		}
 
-		// Handle $or
+		// Handle $or; a branch of several fields is a group, and so is the
+		// whole $or beside other fields
 		if field == "$or" {
+			var orConds []models.Condition
 			if arr, ok := value.([]interface{}); ok {
 				for _, item := range arr {
 					if m, ok := item.(map[string]interface{}); ok {
@@ -867,20 +1125,33 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 						if err != nil {
 							return nil, err
 						}
-						for i := range subConds {
-							if !isFirst {
-								subConds[i].Logic = "OR"
-							}
-							isFirst = false
+						if len(subConds) == 0 {
+							continue
 						}
-						conditions = append(conditions, subConds...)
+						subConds = groupConditions(subConds, "OR")
+						if len(orConds) > 0 {
+							subConds[0].Logic = "OR"
+						}
+						orConds = append(orConds, subConds...)
 					}
 				}
 			}
+			if len(orConds) == 0 {
+				continue
+			}
+			if len(filter) > 1 {
+				orConds = groupConditions(orConds, "AND")
+			}
+			if !isFirst {
+				orConds[0].Logic = "AND"
+			}
+			isFirst = false
+			conditions = append(conditions, orConds...)
 			continue
 		}
 
-		// Handle $nor
+		// Handle $nor: NOT (a OR b) is NOT a AND NOT b, and a branch of
+		// several fields, NOT (x AND y), is the group (NOT x OR NOT y)
 		if field == "$nor" {
 			if arr, ok := value.([]interface{}); ok {
 				for _, item := range arr {
@@ -889,13 +1160,17 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 						if err != nil {
 							return nil, err
 						}
-						for i := range subConds {
-							subConds[i].Operator = negateOperatorOQL(subConds[i].Operator)
-							if !isFirst {
-								subConds[i].Logic = "AND"
-							}
-							isFirst = false
+						if len(subConds) == 0 {
+							continue
+						}
+						subConds = negateConditions(subConds)
+						if len(arr) > 1 || len(filter) > 1 {
+							subConds = groupConditions(subConds, "AND")
+						}
+						if !isFirst {
+							subConds[0].Logic = "AND"
 						}
+						isFirst = false
 						conditions = append(conditions, subConds...)
 					}
 				}
@@ -903,6 +1178,20 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 			continue
 		}
 
+		// Handle $expr - comparisons of computed values
+		if field == "$expr" {
+			subConds, err := convertMongoExprCondition(value)
+			if err != nil {
+				return nil, err
+			}
+			if len(subConds) > 0 && !isFirst {
+				subConds[0].Logic = "AND"
+			}
+			isFirst = isFirst && len(subConds) == 0
+			conditions = append(conditions, subConds...)
+			continue
+		}
+
 		cond, err := convertFieldCondition(field, value, isFirst)
 		if err != nil {
 			return nil, err
@@ -916,6 +1205,159 @@ func convertMongoFilter(filter map[string]interface{}) ([]models.Condition, erro
 	return conditions, nil
 }
 
+// mongoExprComparisons maps $expr comparison operators to OQL operators
+var mongoExprComparisons = map[string]string{
+	"$eq":  "=",
+	"$ne":  "!=",
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+// convertMongoExprCondition converts an $expr tree to conditions whose sides
+// are expressions: {$gt: ["$spent", {$multiply: ["$limit", 0.9]}]} is
+// spent > limit * 0.9. $and and $or join their conditions, grouping any that
+// mix both.
+func convertMongoExprCondition(expr interface{}) ([]models.Condition, error) {
+	exprMap, ok := expr.(map[string]interface{})
+	if !ok || len(exprMap) != 1 {
+		return nil, fmt.Errorf("%w: $expr must be a single operator", ErrParseError)
+	}
+
+	for op, arg := range exprMap {
+		args, _ := arg.([]interface{})
+
+		switch op {
+		case "$and", "$or":
+			logic := strings.ToUpper(strings.TrimPrefix(op, "$"))
+			var conditions []models.Condition
+			for _, item := range args {
+				subConds, err := convertMongoExprCondition(item)
+				if err != nil {
+					return nil, err
+				}
+				subConds = groupConditions(subConds, logic)
+				if len(conditions) > 0 {
+					subConds[0].Logic = logic
+				}
+				conditions = append(conditions, subConds...)
+			}
+			return conditions, nil
+
+		case "$not":
+			if len(args) == 0 {
+				args = []interface{}{arg}
+			}
+			subConds, err := convertMongoExprCondition(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return negateConditions(subConds), nil
+
+		case "$in":
+			values, ok := argAt(args, 1).([]interface{})
+			if len(args) != 2 || !ok {
+				return nil, fmt.Errorf("%w: $in needs an expression and an array", ErrParseError)
+			}
+			cond := models.Condition{FieldExpr: convertMongoAggExpression(args[0]), Operator: "IN"}
+			for _, value := range values {
+				cond.ValuesExpr = append(cond.ValuesExpr, convertMongoAggExpression(value))
+			}
+			return []models.Condition{cond}, nil
+		}
+
+		operator, ok := mongoExprComparisons[op]
+		if !ok {
+			return nil, fmt.Errorf("%w: $expr operator %s", ErrNotSupported, op)
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%w: %s needs two expressions", ErrParseError, op)
+		}
+		right := args[1]
+		if right == nil && (op == "$eq" || op == "$ne") {
+			operator := "IS_NULL"
+			if op == "$ne" {
+				operator = "IS_NOT_NULL"
+			}
+			return []models.Condition{{FieldExpr: convertMongoAggExpression(args[0]), Operator: operator}}, nil
+		}
+		return []models.Condition{{
+			FieldExpr: convertMongoAggExpression(args[0]),
+			Operator:  operator,
+			ValueExpr: convertMongoAggExpression(right),
+		}}, nil
+	}
+	return nil, nil
+}
+
+// negateConditions applies De Morgan's laws to a condition list: each
+// condition is negated, groups in turn, and AND and OR swap. AND binds
+// tighter than OR, so a AND b OR c is negated as (NOT a OR NOT b) AND NOT c.
+func negateConditions(conditions []models.Condition) []models.Condition {
+	if len(conditions) == 0 {
+		return conditions
+	}
+	// The terms joined by OR, each a run joined by AND
+	var terms [][]models.Condition
+	for i, cond := range conditions {
+		if i == 0 || cond.Logic == "OR" {
+			terms = append(terms, nil)
+		}
+		terms[len(terms)-1] = append(terms[len(terms)-1], cond)
+	}
+
+	var negated []models.Condition
+	for _, term := range terms {
+		var negatedTerm []models.Condition
+		for _, cond := range term {
+			if cond.Operator == "GROUP" {
+				cond.Nested = negateConditions(cond.Nested)
+				if len(cond.Nested) == 1 {
+					cond = cond.Nested[0]
+				}
+			} else {
+				cond.Operator = negateOperatorOQL(cond.Operator)
+			}
+			cond.Logic = "OR"
+			negatedTerm = append(negatedTerm, cond)
+		}
+		if len(terms) > 1 {
+			negatedTerm = groupConditions(negatedTerm, "AND")
+		}
+		negatedTerm[0].Logic = "AND"
+		negated = append(negated, negatedTerm...)
+	}
+	negated[0].Logic = ""
+	return negated
+}
+
+// groupConditions nests conditions in a GROUP unless they are one condition
+// or all joined by logic, so they can be joined to others by logic
+func groupConditions(conditions []models.Condition, logic string) []models.Condition {
+	if len(conditions) > 1 && hasOtherLogic(conditions[1:], logic) {
+		return []models.Condition{{Operator: "GROUP", Nested: conditions}}
+	}
+	return conditions
+}
+
+// hasOtherLogic reports whether any condition is joined by other than logic
+func hasOtherLogic(conditions []models.Condition, logic string) bool {
+	for _, cond := range conditions {
+		if cond.Logic != logic {
+			return true
+		}
+	}
+	return false
+}
+
+func argAt(args []interface{}, i int) interface{} {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
 func convertFieldCondition(field string, value interface{}, isFirst bool) (*models.Condition, error) {
 	cond := &models.Condition{
 		FieldExpr: FieldExpr(field),
@@ -926,12 +1368,18 @@ func convertFieldCondition(field string, value interface{}, isFirst bool) (*mode
 
 	switch v := value.(type) {
 	case map[string]interface{}:
+		// {field: {$oid: ...}} is an equality, not an operator
+		if _, _, ok := extendedJSONValue(v); ok {
+			cond.Operator = "="
+			cond.ValueExpr = mongoLiteral(v)
+			return cond, nil
+		}
 		return convertOperatorCondition(cond, v)
 	case nil:
 		cond.Operator = "IS_NULL"
 	default:
 		cond.Operator = "="
-		cond.ValueExpr = LiteralExpr(valueToString(v))
+		cond.ValueExpr = mongoLiteral(v)
 	}
 
 	return cond, nil
@@ -943,8 +1391,8 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 	lteVal, hasLte := ops["$lte"]
 	if hasGte && hasLte {
 		cond.Operator = "BETWEEN"
-		cond.ValueExpr = LiteralExpr(valueToString(gteVal))
-		cond.Value2Expr = LiteralExpr(valueToString(lteVal))
+		cond.ValueExpr = mongoLiteral(gteVal)
+		cond.Value2Expr = mongoLiteral(lteVal)
 		return cond, nil
 	}
 
@@ -953,8 +1401,8 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 	gtVal, hasGt := ops["$gt"]
 	if hasLt && hasGt {
 		cond.Operator = "NOT_BETWEEN"
-		cond.ValueExpr = LiteralExpr(valueToString(gtVal))
-		cond.Value2Expr = LiteralExpr(valueToString(ltVal))
+		cond.ValueExpr = mongoLiteral(gtVal)
+		cond.Value2Expr = mongoLiteral(ltVal)
 		return cond, nil
 	}
 
@@ -963,33 +1411,33 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 		switch mongoOp {
 		case "$eq":
 			cond.Operator = "="
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$ne":
 			cond.Operator = "!="
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$gt":
 			cond.Operator = ">"
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$gte":
 			cond.Operator = ">="
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$lt":
 			cond.Operator = "<"
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$lte":
 			cond.Operator = "<="
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$in":
 			cond.Operator = "IN"
 			if arr, ok := val.([]interface{}); ok {
 				for _, item := range arr {
-					cond.ValuesExpr = append(cond.ValuesExpr, LiteralExpr(valueToString(item)))
+					cond.ValuesExpr = append(cond.ValuesExpr, mongoLiteral(item))
 				}
 			}
 
@@ -997,7 +1445,7 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 			cond.Operator = "NOT_IN"
 			if arr, ok := val.([]interface{}); ok {
 				for _, item := range arr {
-					cond.ValuesExpr = append(cond.ValuesExpr, LiteralExpr(valueToString(item)))
+					cond.ValuesExpr = append(cond.ValuesExpr, mongoLiteral(item))
 				}
 			}
 
@@ -1036,14 +1484,14 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 		case "$type":
 			// Type checking - map to special handling
 			cond.Operator = "TYPE"
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$all":
 			// Array contains all elements
 			cond.Operator = "CONTAINS_ALL"
 			if arr, ok := val.([]interface{}); ok {
 				for _, item := range arr {
-					cond.ValuesExpr = append(cond.ValuesExpr, LiteralExpr(valueToString(item)))
+					cond.ValuesExpr = append(cond.ValuesExpr, mongoLiteral(item))
 				}
 			}
 
@@ -1058,7 +1506,7 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 		case "$size":
 			// Array size
 			cond.Operator = "ARRAY_SIZE"
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 
 		case "$options":
 			// Already handled with $regex
@@ -1067,7 +1515,7 @@ func convertOperatorCondition(cond *models.Condition, ops map[string]interface{}
 		default:
 			// Unknown operator - use as-is
 			cond.Operator = GetOQLOperator(mongoOp, "MongoDB")
-			cond.ValueExpr = LiteralExpr(valueToString(val))
+			cond.ValueExpr = mongoLiteral(val)
 		}
 
 		return cond, nil
@@ -1088,7 +1536,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(set) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: LiteralExpr(valueToString(set[name])),
+				ValueExpr: mongoLiteral(set[name]),
 			})
 		}
 	}
@@ -1108,7 +1556,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(inc) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: BinaryExpr(FieldExpr(name), "+", LiteralExpr(valueToString(inc[name]))),
+				ValueExpr: BinaryExpr(FieldExpr(name), "+", mongoLiteral(inc[name])),
 			})
 		}
 	}
@@ -1118,7 +1566,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(mul) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: BinaryExpr(FieldExpr(name), "*", LiteralExpr(valueToString(mul[name]))),
+				ValueExpr: BinaryExpr(FieldExpr(name), "*", mongoLiteral(mul[name])),
 			})
 		}
 	}
@@ -1128,7 +1576,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(min) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: FunctionExpr("MIN", FieldExpr(name), LiteralExpr(valueToString(min[name]))),
+				ValueExpr: FunctionExpr("MIN", FieldExpr(name), mongoLiteral(min[name])),
 			})
 		}
 	}
@@ -1138,7 +1586,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(max) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: FunctionExpr("MAX", FieldExpr(name), LiteralExpr(valueToString(max[name]))),
+				ValueExpr: FunctionExpr("MAX", FieldExpr(name), mongoLiteral(max[name])),
 			})
 		}
 	}
@@ -1159,7 +1607,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(push) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: FunctionExpr("ARRAY_APPEND", FieldExpr(name), LiteralExpr(valueToString(push[name]))),
+				ValueExpr: FunctionExpr("ARRAY_APPEND", FieldExpr(name), mongoLiteral(push[name])),
 			})
 		}
 	}
@@ -1169,7 +1617,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(pull) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: FunctionExpr("ARRAY_REMOVE", FieldExpr(name), LiteralExpr(valueToString(pull[name]))),
+				ValueExpr: FunctionExpr("ARRAY_REMOVE", FieldExpr(name), mongoLiteral(pull[name])),
 			})
 		}
 	}
@@ -1179,7 +1627,7 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 		for _, name := range sortedKeys(addToSet) {
 			fields = append(fields, models.Field{
 				NameExpr:  FieldExpr(name),
-				ValueExpr: FunctionExpr("ARRAY_ADD_UNIQUE", FieldExpr(name), LiteralExpr(valueToString(addToSet[name]))),
+				ValueExpr: FunctionExpr("ARRAY_ADD_UNIQUE", FieldExpr(name), mongoLiteral(addToSet[name])),
 			})
 		}
 	}
@@ -1202,6 +1650,119 @@ func convertMongoUpdate(update map[string]interface{}) []models.Field {
 	return fields
 }
 
+// ============================================================================
+// UPDATE PIPELINE
+// ============================================================================
+
+// convertMongoUpdateDoc converts an update document of operators, or the
+// pipeline form: an array of $set stages computing fields from expressions
+func convertMongoUpdateDoc(update interface{}) []models.Field {
+	switch u := update.(type) {
+	case map[string]interface{}:
+		return convertMongoUpdate(u)
+	case []interface{}:
+		return convertMongoPipelineUpdate(u)
+	}
+	return nil
+}
+
+func convertMongoPipelineUpdate(pipeline []interface{}) []models.Field {
+	var fields []models.Field
+	for _, stage := range pipeline {
+		stageMap, ok := stage.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// $set / $addFields - computed field assignment
+		for _, op := range []string{"$set", "$addFields"} {
+			if set, ok := stageMap[op].(map[string]interface{}); ok {
+				for _, name := range sortedKeys(set) {
+					fields = append(fields, models.Field{
+						NameExpr:  FieldExpr(name),
+						ValueExpr: convertMongoAggExpression(set[name]),
+					})
+				}
+			}
+		}
+
+		// $unset - one field or a list of them
+		var unset []interface{}
+		switch u := stageMap["$unset"].(type) {
+		case string:
+			unset = []interface{}{u}
+		case []interface{}:
+			unset = u
+		}
+		for _, name := range unset {
+			fields = append(fields, models.Field{
+				NameExpr:  FieldExpr(valueToString(name)),
+				ValueExpr: LiteralExpr("NULL"),
+			})
+		}
+	}
+	return fields
+}
+
+// mongoBinaryOperators maps arithmetic expression operators to OQL operators
+var mongoBinaryOperators = map[string]string{
+	"$add":      "+",
+	"$subtract": "-",
+	"$multiply": "*",
+	"$divide":   "/",
+	"$mod":      "%",
+}
+
+// mongoFunctions maps string and math expression operators to OQL functions
+var mongoFunctions = map[string]string{
+	"$concat":   "CONCAT",
+	"$toUpper":  "UPPER",
+	"$toLower":  "LOWER",
+	"$strLenCP": "LENGTH",
+	"$abs":      "ABS",
+	"$round":    "ROUND",
+}
+
+// convertMongoAggExpression converts an aggregation expression back into a
+// TrueAST expression: "$field" is a field, $add and the other arithmetic
+// operators are BINARY (folded left when given more than two operands), and
+// $concat, $toUpper and the like are FUNCTION
+func convertMongoAggExpression(val interface{}) *models.Expression {
+	exprMap, ok := val.(map[string]interface{})
+	if !ok || len(exprMap) != 1 {
+		return convertExpressionValue(val)
+	}
+	for op, arg := range exprMap {
+		args, isList := arg.([]interface{})
+		if !isList {
+			args = []interface{}{arg}
+		}
+
+		if oqlOp, ok := mongoBinaryOperators[op]; ok && len(args) >= 2 {
+			expr := convertMongoAggExpression(args[0])
+			for _, operand := range args[1:] {
+				expr = BinaryExpr(expr, oqlOp, convertMongoAggExpression(operand))
+			}
+			return expr
+		}
+		if name, ok := mongoFunctions[op]; ok {
+			// $round's second argument is the place, which ROUND leaves at 0
+			if op == "$round" {
+				args = args[:1]
+			}
+			var exprs []*models.Expression
+			for _, a := range args {
+				exprs = append(exprs, convertMongoAggExpression(a))
+			}
+			return FunctionExpr(name, exprs...)
+		}
+		if op == "$literal" {
+			return mongoLiteral(arg)
+		}
+	}
+	return convertExpressionValue(val)
+}
+
 // ============================================================================
 // DOCUMENT / PROJECTION / SORT CONVERSION
 // ============================================================================
@@ -1218,7 +1779,7 @@ func convertMongoDocument(doc map[string]interface{}) []models.Field {
 	for _, name := range keys {
 		fields = append(fields, models.Field{
 			NameExpr:  FieldExpr(name),
-			ValueExpr: LiteralExpr(valueToString(doc[name])),
+			ValueExpr: mongoLiteral(doc[name]),
 		})
 	}
 	return fields
@@ -1264,21 +1825,130 @@ func sortedKeys(m map[string]interface{}) []string {
 // LOOKUP → JOIN
 // ============================================================================
 
-func convertMongoLookup(lookup map[string]interface{}) *models.Join {
+// convertMongoLookup converts $lookup to a join of entity with the from
+// collection, fields qualified by their entity. localField and foreignField
+// are the ON equality. The pipeline form correlates through let variables:
+// its $match conditions are the ON conditions, the first equality of a let
+// variable with a joined field becomes the join's equality and the others
+// its Conditions.
+func convertMongoLookup(entity string, lookup map[string]interface{}) (*models.Join, error) {
 	from, _ := lookup["from"].(string)
-	localField, _ := lookup["localField"].(string)
-	foreignField, _ := lookup["foreignField"].(string)
-
 	if from == "" {
-		return nil
+		return nil, nil
+	}
+	join := &models.Join{Type: models.LeftJoin, Table: TableToEntity(from)}
+
+	pipeline, isPipeline := lookup["pipeline"].([]interface{})
+	if !isPipeline {
+		localField, _ := lookup["localField"].(string)
+		foreignField, _ := lookup["foreignField"].(string)
+		join.LeftExpr = FieldExpr(entity + "." + localField)
+		join.RightExpr = FieldExpr(join.Table + "." + foreignField)
+		return join, nil
+	}
+
+	vars, _ := lookup["let"].(map[string]interface{})
+	var conditions []models.Condition
+	for _, stage := range pipeline {
+		stageMap, _ := stage.(map[string]interface{})
+		match, ok := stageMap["$match"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: $lookup pipeline stages other than $match", ErrNotSupported)
+		}
+		matchConds, err := convertLookupMatch(match, vars, entity, join.Table)
+		if err != nil {
+			return nil, err
+		}
+		if len(matchConds) > 0 && len(conditions) > 0 {
+			matchConds[0].Logic = "AND"
+		}
+		conditions = append(conditions, matchConds...)
 	}
 
-	return &models.Join{
-		Type:      models.LeftJoin,
-		Table:     TableToEntity(from),
-		LeftExpr:  FieldExpr(localField),
-		RightExpr: FieldExpr(foreignField),
+	// The ON equality has to hold whatever the other conditions are, so only
+	// conditions joined by AND can give it up
+	for i, cond := range conditions {
+		if i > 0 && cond.Logic != "AND" {
+			break
+		}
+		if cond.Operator != "=" || !isEntityField(cond.FieldExpr, join.Table) && !isEntityField(cond.ValueExpr, join.Table) {
+			continue
+		}
+		local, foreign := cond.ValueExpr, cond.FieldExpr
+		if !isEntityField(foreign, join.Table) {
+			local, foreign = foreign, local
+		}
+		if !isEntityField(local, entity) {
+			continue
+		}
+		join.LeftExpr, join.RightExpr = local, foreign
+		join.Conditions = append(conditions[:i:i], conditions[i+1:]...)
+		if len(join.Conditions) > 0 {
+			join.Conditions[0].Logic = ""
+		}
+		return join, nil
 	}
+	return nil, fmt.Errorf("%w: $lookup pipeline without an equality between a let variable and a field of %s", ErrNotSupported, from)
+}
+
+// convertLookupMatch converts a $match of a $lookup pipeline. Its fields are
+// the joined collection's and its let variables ($$name) the local
+// collection's, so each field is qualified with the entity it belongs to.
+func convertLookupMatch(match, vars map[string]interface{}, entity, joined string) ([]models.Condition, error) {
+	filter := make(map[string]interface{}, len(match))
+	for field, value := range match {
+		if field == "$expr" {
+			value = qualifyLookupExpr(value, vars, entity, joined)
+		} else if !strings.HasPrefix(field, "$") {
+			field = joined + "." + field
+		}
+		filter[field] = value
+	}
+	return convertMongoFilter(filter)
+}
+
+// qualifyLookupExpr rewrites the field paths of an $expr in a $lookup
+// pipeline: $field becomes $Joined.field and $$name the let variable's value,
+// $Entity.field for a path. $literal values are kept as written.
+func qualifyLookupExpr(expr interface{}, vars map[string]interface{}, entity, joined string) interface{} {
+	switch v := expr.(type) {
+	case string:
+		if name, ok := strings.CutPrefix(v, "$$"); ok {
+			value, ok := vars[name]
+			if !ok {
+				return v
+			}
+			if path, ok := value.(string); ok && strings.HasPrefix(path, "$") && !strings.HasPrefix(path, "$$") {
+				return "$" + entity + "." + strings.TrimPrefix(path, "$")
+			}
+			return value
+		}
+		if strings.HasPrefix(v, "$") {
+			return "$" + joined + "." + strings.TrimPrefix(v, "$")
+		}
+		return v
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = qualifyLookupExpr(item, vars, entity, joined)
+		}
+		return items
+	case map[string]interface{}:
+		rewritten := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if key != "$literal" {
+				item = qualifyLookupExpr(item, vars, entity, joined)
+			}
+			rewritten[key] = item
+		}
+		return rewritten
+	}
+	return expr
+}
+
+// isEntityField reports whether expr is a field qualified with entity
+func isEntityField(expr *models.Expression, entity string) bool {
+	return expr != nil && expr.Type == "FIELD" && strings.HasPrefix(expr.Value, entity+".")
 }
 
 // ============================================================================
@@ -1412,7 +2082,60 @@ func valueToString(v interface{}) string {
 		return "false"
 	case nil:
 		return "NULL"
+	case map[string]interface{}:
+		if _, value, ok := extendedJSONValue(val); ok {
+			return value
+		}
+		return fmt.Sprintf("%v", val)
 	default:
 		return fmt.Sprintf("%v", val)
 	}
+}
+
+// mongoLiteral converts a filter or document value to a literal. Extended
+// JSON wrappers keep their type: $numberLong, $numberInt, $numberDouble and
+// $numberDecimal are NUMBER, $oid and $date (as ISO 8601) are STRING.
+func mongoLiteral(v interface{}) *models.Expression {
+	if wrapper, ok := v.(map[string]interface{}); ok {
+		if exprType, value, ok := extendedJSONValue(wrapper); ok {
+			return &models.Expression{Type: exprType, Value: value}
+		}
+	}
+	return LiteralExpr(valueToString(v))
+}
+
+// extendedJSONValue decodes a canonical or relaxed Extended JSON wrapper to
+// its expression type and value
+func extendedJSONValue(wrapper map[string]interface{}) (string, string, bool) {
+	if len(wrapper) != 1 {
+		return "", "", false
+	}
+	for key, inner := range wrapper {
+		switch key {
+		case "$numberLong", "$numberInt", "$numberDouble", "$numberDecimal":
+			if s, ok := inner.(string); ok {
+				return "NUMBER", s, true
+			}
+		case "$oid":
+			if s, ok := inner.(string); ok {
+				return "STRING", s, true
+			}
+		case "$date":
+			switch d := inner.(type) {
+			case string:
+				// relaxed: ISO 8601
+				return "STRING", d, true
+			case float64:
+				return "STRING", time.UnixMilli(int64(d)).UTC().Format(time.RFC3339Nano), true
+			case map[string]interface{}:
+				// canonical: {"$numberLong": "<milliseconds>"}
+				if _, ms, ok := extendedJSONValue(d); ok {
+					if n, err := strconv.ParseInt(ms, 10, 64); err == nil {
+						return "STRING", time.UnixMilli(n).UTC().Format(time.RFC3339Nano), true
+					}
+				}
+			}
+		}
+	}
+	return "", "", false
 }
\ No newline at end of file
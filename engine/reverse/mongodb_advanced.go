@@ -1,6 +1,7 @@
 package reverse
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/omniql-engine/omniql/engine/models"
@@ -9,7 +10,8 @@ import (
 // ============================================================================
 // ADVANCED AGGREGATION PIPELINE PROCESSING
 // Called from convertMongoAggregate in mongodb.go for advanced stages
-// Only covers features in OQL mapping: Window Functions, Set Operations, CASE
+// Only covers features in OQL mapping: Window Functions, Set Operations, CASE,
+// FACET and recursive CTEs
 // ============================================================================
 
 // ProcessAdvancedPipelineStages handles advanced aggregation stages
@@ -27,20 +29,6 @@ func ProcessAdvancedPipelineStages(query *models.Query, pipeline []interface{})
 			convertWindowFields(query, windowFields)
 			hasAdvanced = true
 		}
-
-		// $unionWith → UNION
-		if unionWith, ok := stageMap["$unionWith"].(map[string]interface{}); ok {
-			convertUnionWith(query, unionWith)
-			hasAdvanced = true
-		}
-		// $unionWith can also be a string (simple form)
-		if unionColl, ok := stageMap["$unionWith"].(string); ok {
-			query.SetOperation = &models.SetOperation{
-				Type:       models.Union,
-				RightQuery: &models.Query{Operation: "GET", Entity: TableToEntity(unionColl)},
-			}
-			hasAdvanced = true
-		}
 	}
 
 	return hasAdvanced
@@ -77,8 +65,8 @@ func convertWindowFields(query *models.Query, windowFields map[string]interface{
 
 	// output → Window function definitions
 	if output, ok := windowFields["output"].(map[string]interface{}); ok {
-		for alias, def := range output {
-			if defMap, ok := def.(map[string]interface{}); ok {
+		for _, alias := range sortedKeys(output) {
+			if defMap, ok := output[alias].(map[string]interface{}); ok {
 				wf := convertWindowFunctionDef(alias, defMap, partitionBy, windowOrderBy)
 				if wf != nil {
 					query.WindowFunctions = append(query.WindowFunctions, *wf)
@@ -86,6 +74,12 @@ func convertWindowFields(query *models.Query, windowFields map[string]interface{
 			}
 		}
 	}
+
+	// A pipeline of window functions alone is the window query itself:
+	// LAG amount OVER (...) FROM Order
+	if query.Operation == "GET" && len(query.WindowFunctions) > 0 {
+		query.Operation = string(query.WindowFunctions[0].Function)
+	}
 }
 
 func convertWindowFunctionDef(alias string, def map[string]interface{}, partitionBy []*models.Expression, orderBy []models.OrderBy) *models.WindowFunction {
@@ -136,6 +130,23 @@ func convertWindowFunctionDef(alias string, def map[string]interface{}, partitio
 				} else {
 					wf.Function = models.WindowFunc("LEAD")
 				}
+				if def, ok := shiftDef["default"]; ok {
+					wf.Default = mongoLiteral(def)
+				}
+			}
+			return wf
+
+		case "$sum", "$avg", "$min", "$max", "$count":
+			wf.Function = models.WindowFunc(strings.ToUpper(strings.TrimPrefix(op, "$")))
+			wf.FieldExpr = windowAggregateField(op, val)
+			if wf.FieldExpr == nil {
+				continue
+			}
+			if op == "$sum" && wf.FieldExpr.Value != "*" && isCountCond(val) {
+				wf.Function = models.WindowFunc("COUNT")
+			}
+			if window, ok := def["window"].(map[string]interface{}); ok {
+				convertWindowFrame(wf, window)
 			}
 			return wf
 		}
@@ -144,36 +155,203 @@ func convertWindowFunctionDef(alias string, def map[string]interface{}, partitio
 	return nil
 }
 
+// windowAggregateField reads the field of a window aggregate: "$amount" for
+// $sum and the others, * for $count, and for COUNT field the $sum of
+// {$cond: [{$gt: ["$field", null]}, 1, 0]} that counts where field is set
+func windowAggregateField(op string, val interface{}) *models.Expression {
+	switch v := val.(type) {
+	case string:
+		if strings.HasPrefix(v, "$") {
+			return FieldExpr(strings.TrimPrefix(v, "$"))
+		}
+	case map[string]interface{}:
+		if op == "$count" {
+			return FieldExpr("*")
+		}
+		if cond, ok := v["$cond"].([]interface{}); ok && isCountCond(v) {
+			gt, _ := cond[0].(map[string]interface{})["$gt"].([]interface{})
+			field, _ := gt[0].(string)
+			return FieldExpr(strings.TrimPrefix(field, "$"))
+		}
+	}
+	return nil
+}
+
+// isCountCond reports whether a $sum adds 1 for each document where a field is set
+func isCountCond(val interface{}) bool {
+	v, ok := val.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	cond, ok := v["$cond"].([]interface{})
+	if !ok || len(cond) != 3 || valueToString(cond[1]) != "1" || valueToString(cond[2]) != "0" {
+		return false
+	}
+	test, _ := cond[0].(map[string]interface{})
+	gt, ok := test["$gt"].([]interface{})
+	if !ok || len(gt) != 2 || gt[1] != nil {
+		return false
+	}
+	field, ok := gt[0].(string)
+	return ok && strings.HasPrefix(field, "$")
+}
+
+// convertWindowFrame reads window: {documents: [start, end]} as a ROWS frame
+// and window: {range: [start, end]} as a RANGE frame. Bounds are "unbounded",
+// "current", or a number of documents before (negative) or after the current one.
+func convertWindowFrame(wf *models.WindowFunction, window map[string]interface{}) {
+	bounds, ok := window["documents"].([]interface{})
+	wf.FrameUnit = "ROWS"
+	if !ok {
+		if bounds, ok = window["range"].([]interface{}); !ok {
+			wf.FrameUnit = ""
+			return
+		}
+		wf.FrameUnit = "RANGE"
+	}
+	if len(bounds) != 2 {
+		wf.FrameUnit = ""
+		return
+	}
+	wf.FrameStart = windowFrameBound(bounds[0], "PRECEDING")
+	wf.FrameEnd = windowFrameBound(bounds[1], "FOLLOWING")
+}
+
+// windowFrameBound converts one frame bound; unbounded is UNBOUNDED
+// PRECEDING as a start and UNBOUNDED FOLLOWING as an end
+func windowFrameBound(bound interface{}, unbounded string) string {
+	switch b := bound.(type) {
+	case string:
+		if b == "current" {
+			return "CURRENT ROW"
+		}
+		return "UNBOUNDED " + unbounded
+	case float64:
+		switch {
+		case b < 0:
+			return valueToString(-b) + " PRECEDING"
+		case b > 0:
+			return valueToString(b) + " FOLLOWING"
+		}
+		return "CURRENT ROW"
+	}
+	return "UNBOUNDED " + unbounded
+}
+
 // ============================================================================
 // SET OPERATIONS ($unionWith, $setIntersection, $setDifference)
 // Mapping: UNION, UNION ALL, INTERSECT, EXCEPT
 // ============================================================================
 
-func convertUnionWith(query *models.Query, unionWith map[string]interface{}) {
-	coll, _ := unionWith["coll"].(string)
+// convertMongoUnionWith reads the pipeline BuildSetOperationPipeline writes
+// back into a set operation. The stages before the last top-level $unionWith
+// are the first query and its pipeline the second; a string $unionWith is a
+// whole collection. Without more stages it is UNION ALL. UNION, INTERSECT and
+// EXCEPT tag each side's rows with $replaceRoot and group them on $row, and a
+// $match on the sides keeps those in both (INTERSECT) or only in the first
+// (EXCEPT). $sort, $skip and $limit after that order and page the result.
+func convertMongoUnionWith(collection string, pipeline []interface{}, at int) (*models.Query, error) {
+	stage := pipeline[at].(map[string]interface{})["$unionWith"]
+	coll, _ := stage.(string)
+	var rightPipeline []interface{}
+	if unionWith, ok := stage.(map[string]interface{}); ok {
+		coll, _ = unionWith["coll"].(string)
+		rightPipeline, _ = unionWith["pipeline"].([]interface{})
+	}
+	if coll == "" {
+		return nil, fmt.Errorf("%w: $unionWith needs a collection", ErrParseError)
+	}
 
-	rightQuery := &models.Query{
-		Operation: "GET",
-		Entity:    TableToEntity(coll),
+	left, err := convertMongoAggregate(collection, map[string]interface{}{"pipeline": withoutSideTag(pipeline[:at])})
+	if err != nil {
+		return nil, err
+	}
+	right, err := convertMongoAggregate(coll, map[string]interface{}{"pipeline": withoutSideTag(rightPipeline)})
+	if err != nil {
+		return nil, err
 	}
 
-	// pipeline in $unionWith
-	if pipeline, ok := unionWith["pipeline"].([]interface{}); ok {
-		for _, stage := range pipeline {
-			if stageMap, ok := stage.(map[string]interface{}); ok {
-				if match, ok := stageMap["$match"].(map[string]interface{}); ok {
-					conditions, _ := convertMongoFilter(match)
-					rightQuery.Conditions = conditions
-				}
+	opType, rest := setOperationTail(pipeline[at+1:])
+	query := &models.Query{
+		Operation:    string(opType),
+		SetOperation: &models.SetOperation{Type: opType, LeftQuery: left, RightQuery: right},
+	}
+	for _, stage := range rest {
+		stageMap, _ := stage.(map[string]interface{})
+		for name, value := range stageMap {
+			switch name {
+			case "$sort":
+				sort, _ := value.(map[string]interface{})
+				query.OrderBy = convertMongoSort(sort)
+			case "$skip":
+				skip, _ := value.(float64)
+				query.Offset = int(skip)
+			case "$limit":
+				limit, _ := value.(float64)
+				query.Limit = int(limit)
+			default:
+				return nil, fmt.Errorf("%w: %s after $unionWith", ErrNotSupported, name)
 			}
 		}
 	}
+	return query, nil
+}
 
-	query.SetOperation = &models.SetOperation{
-		Type:       models.Union,
-		LeftQuery:  &models.Query{Operation: query.Operation, Entity: query.Entity, Conditions: query.Conditions},
-		RightQuery: rightQuery,
+// lastUnionWith returns the index of the last top-level $unionWith, or -1;
+// the ones before it belong to the first query of a nested set operation
+func lastUnionWith(pipeline []interface{}) int {
+	for i := len(pipeline) - 1; i >= 0; i-- {
+		if stageMap, ok := pipeline[i].(map[string]interface{}); ok && stageMap["$unionWith"] != nil {
+			return i
+		}
 	}
+	return -1
+}
+
+// withoutSideTag drops the $replaceRoot that tags a side's rows, {row, side}
+func withoutSideTag(pipeline []interface{}) []interface{} {
+	if len(pipeline) == 0 {
+		return pipeline
+	}
+	stageMap, _ := pipeline[len(pipeline)-1].(map[string]interface{})
+	replaceRoot, _ := stageMap["$replaceRoot"].(map[string]interface{})
+	newRoot, _ := replaceRoot["newRoot"].(map[string]interface{})
+	if _, ok := newRoot["side"]; ok && newRoot["row"] != nil {
+		return pipeline[:len(pipeline)-1]
+	}
+	return pipeline
+}
+
+// setOperationTail reads the set operation from the stages after $unionWith
+// and returns it with the stages that follow it
+func setOperationTail(stages []interface{}) (models.SetOperationType, []interface{}) {
+	if len(stages) == 0 {
+		return "UNION ALL", stages
+	}
+	stageMap, _ := stages[0].(map[string]interface{})
+	group, _ := stageMap["$group"].(map[string]interface{})
+	if group["_id"] != "$row" || group["sides"] == nil {
+		return "UNION ALL", stages
+	}
+	opType, rest := models.Union, stages[1:]
+	if len(rest) > 0 {
+		stageMap, _ = rest[0].(map[string]interface{})
+		match, _ := stageMap["$match"].(map[string]interface{})
+		sides, _ := match["sides"].(map[string]interface{})
+		switch {
+		case sides["$all"] != nil:
+			opType, rest = models.Intersect, rest[1:]
+		case sides["$ne"] != nil:
+			opType, rest = models.Except, rest[1:]
+		}
+	}
+	// The $replaceRoot that restores the rows from the group key
+	if len(rest) > 0 {
+		if stageMap, _ = rest[0].(map[string]interface{}); stageMap["$replaceRoot"] != nil {
+			rest = rest[1:]
+		}
+	}
+	return opType, rest
 }
 
 // ConvertSetExpression handles $setIntersection, $setDifference in $project
@@ -319,12 +497,15 @@ func convertExpressionValue(val interface{}) *models.Expression {
 		}
 		return LiteralExpr(v)
 	case float64:
-		return LiteralExpr(valueToString(v))
+		return mongoLiteral(v)
 	case int:
-		return LiteralExpr(valueToString(v))
+		return mongoLiteral(v)
 	case bool:
-		return LiteralExpr(valueToString(v))
+		return mongoLiteral(v)
 	case map[string]interface{}:
+		if _, _, ok := extendedJSONValue(v); ok {
+			return mongoLiteral(v)
+		}
 		// Check for CASE expression
 		if caseExpr := ConvertCaseExpression(v); caseExpr != nil {
 			return caseExpr
@@ -332,7 +513,7 @@ func convertExpressionValue(val interface{}) *models.Expression {
 		// Check for comparison operators in expression context
 		return convertComparisonInExpr(v)
 	default:
-		return LiteralExpr(valueToString(v))
+		return mongoLiteral(v)
 	}
 }
 
@@ -396,4 +577,128 @@ func isComparisonOperator(op string) bool {
 		return true
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// ============================================================================
+// FACETS ($facet)
+// Mapping: the "result" branch is the main aggregate, every other a FACET
+// ============================================================================
+
+// convertMongoFacet reads each branch as its own aggregate pipeline. Without
+// a "result" branch, the first branch by name is the main aggregate.
+func convertMongoFacet(collection string, query *models.Query, facet map[string]interface{}) error {
+	names := sortedKeys(facet)
+	mainName := "result"
+	if _, ok := facet[mainName]; !ok && len(names) > 0 {
+		mainName = names[0]
+	}
+
+	for _, name := range names {
+		stages, ok := facet[name].([]interface{})
+		if !ok {
+			return fmt.Errorf("%w: $facet branch %s is not a pipeline", ErrParseError, name)
+		}
+		branch, err := convertMongoAggregate(collection, map[string]interface{}{"pipeline": stages})
+		if err != nil {
+			return err
+		}
+		if branch.Aggregate == nil {
+			return fmt.Errorf("%w: $facet branch %s is not an aggregate", ErrNotSupported, name)
+		}
+
+		if name == mainName {
+			query.Operation = branch.Operation
+			query.Aggregate = branch.Aggregate
+			query.GroupBy = branch.GroupBy
+			query.Having = branch.Having
+			query.OrderBy = branch.OrderBy
+			query.Limit = branch.Limit
+			query.Offset = branch.Offset
+			continue
+		}
+		// A sort inside a facet only orders a STRING AGG
+		if len(branch.OrderBy) > 0 {
+			branch.Aggregate.OrderBy = branch.OrderBy
+		}
+		query.Facets = append(query.Facets, models.Facet{
+			Name:      name,
+			Aggregate: branch.Aggregate,
+			GroupBy:   branch.GroupBy,
+		})
+	}
+	return nil
+}
+
+// ============================================================================
+// RECURSIVE CTE ($graphLookup)
+// Mapping: CTE RECURSIVE name AS (UNION ALL (anchor) (INNER JOIN ...))
+// ============================================================================
+
+// convertMongoGraphLookup reads the pipeline BuildGraphLookupPipeline writes
+// back into a recursive CTE. The stages before $graphLookup select the anchor,
+// $graphLookup is the join on the CTE, and after the stages that unwind the
+// rows come the main query's $match, $sort, $skip and $limit.
+func convertMongoGraphLookup(anchor *models.Query, lookup map[string]interface{}, rest []interface{}) (*models.Query, error) {
+	name, _ := lookup["as"].(string)
+	from, _ := lookup["from"].(string)
+	connectFrom, _ := lookup["connectFromField"].(string)
+	connectTo, _ := lookup["connectToField"].(string)
+	if name == "" || from == "" || connectFrom == "" || connectTo == "" {
+		return nil, fmt.Errorf("%w: $graphLookup needs from, connectFromField, connectToField and as", ErrParseError)
+	}
+	// The recursive join starts from the field it follows
+	if startWith, _ := lookup["startWith"].(string); startWith != "$"+connectFrom {
+		return nil, fmt.Errorf("%w: $graphLookup startWith other than $%s", ErrNotSupported, connectFrom)
+	}
+
+	anchor.Columns = []*models.Expression{FieldExpr("*")}
+	member := &models.Query{
+		Operation: "INNER JOIN",
+		Entity:    TableToEntity(from),
+		Joins: []models.Join{{
+			Type:      models.InnerJoin,
+			Table:     name,
+			LeftExpr:  FieldExpr(connectTo),
+			RightExpr: FieldExpr(connectFrom),
+		}},
+	}
+	body := &models.Query{
+		Operation:    "UNION ALL",
+		SetOperation: &models.SetOperation{Type: "UNION ALL", LeftQuery: anchor, RightQuery: member},
+	}
+	cte := &models.CTE{Name: name, Query: body, Recursive: true}
+	// maxDepth counts levels below the first join, DEPTH levels below the anchor
+	if maxDepth, ok := lookup["maxDepth"].(float64); ok {
+		cte.MaxDepth = int(maxDepth) + 1
+	}
+
+	main := &models.Query{Operation: "GET", Entity: name, Columns: []*models.Expression{FieldExpr("*")}}
+	hasMain := false
+	for _, stage := range rest {
+		stageMap, ok := stage.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if match, ok := stageMap["$match"].(map[string]interface{}); ok {
+			conditions, err := convertMongoFilter(match)
+			if err != nil {
+				return nil, err
+			}
+			main.Conditions, hasMain = conditions, true
+		}
+		if sort, ok := stageMap["$sort"].(map[string]interface{}); ok {
+			main.OrderBy, hasMain = convertMongoSort(sort), true
+		}
+		if skip, ok := stageMap["$skip"].(float64); ok {
+			main.Offset, hasMain = int(skip), true
+		}
+		if limit, ok := stageMap["$limit"].(float64); ok {
+			main.Limit, hasMain = int(limit), true
+		}
+	}
+	if hasMain {
+		cte.MainQuery = main
+	}
+
+	return &models.Query{Operation: "CTE", ViewName: name, ViewQuery: body, CTE: cte}, nil
+}
@@ -0,0 +1,539 @@
+package reverse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// MONGOSH SHELL SYNTAX
+// ============================================================================
+
+// parseMongoShell reads a mongosh statement, as copied from mongosh or Compass,
+// into the command document MongoDBToQuery converts:
+//
+//	db.users.find({age: {$gt: 30}}).sort({name: 1}).limit(5)
+//
+// is {"find": "users", "filter": {...}, "sort": {...}, "limit": 5}. Arguments
+// are JavaScript literals: keys may be unquoted, strings single-quoted, and
+// ObjectId(), ISODate(), NumberLong() and /regex/ values are read as their
+// plain values.
+func parseMongoShell(shell string) (map[string]interface{}, error) {
+	p := &shellParser{src: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(shell), ";"))}
+	if !strings.HasPrefix(p.src, "db.") {
+		return nil, fmt.Errorf("%w: shell statement must start with db.", ErrParseError)
+	}
+	p.pos = len("db.")
+
+	// db.<collection>.<method>(...), db.getCollection("name").<method>(...)
+	// or db.<method>(...)
+	var names []string
+	for {
+		name := p.identifier()
+		if name == "" {
+			return nil, p.errorf("expected a collection or method name")
+		}
+		names = append(names, name)
+		if p.peek() != '.' {
+			break
+		}
+		p.pos++
+	}
+
+	collection := strings.Join(names[:len(names)-1], ".")
+	method := names[len(names)-1]
+	argsAt := p.pos
+	args, err := p.arguments()
+	if err != nil {
+		return nil, err
+	}
+
+	if method == "getCollection" {
+		if collection != "" || len(args) != 1 {
+			return nil, p.errorf("getCollection takes one name")
+		}
+		name, ok := args[0].(string)
+		if !ok || p.peek() != '.' {
+			return nil, p.errorf("getCollection must be followed by a method")
+		}
+		p.pos++
+		collection = name
+		if method = p.identifier(); method == "" {
+			return nil, p.errorf("expected a method name")
+		}
+		argsAt = p.pos
+		if args, err = p.arguments(); err != nil {
+			return nil, err
+		}
+	}
+
+	var doc map[string]interface{}
+	if collection == "" {
+		doc, err = mongoShellDatabaseCommand(method, args)
+	} else {
+		doc, err = mongoShellCollectionCommand(collection, method, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The field order of an index key is part of the index
+	if method == "createIndex" || method == "createIndexes" {
+		if err := p.orderIndexKeys(doc, argsAt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Cursor methods: .sort(), .limit(), .skip(), .count(), ...
+	for p.skipSpace(); p.peek() == '.'; p.skipSpace() {
+		p.pos++
+		name := p.identifier()
+		chainArgs, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = applyMongoShellCursor(doc, name, chainArgs); err != nil {
+			return nil, err
+		}
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return doc, nil
+}
+
+// mongoShellCollectionCommand builds the command of db.<collection>.<method>(args)
+func mongoShellCollectionCommand(collection, method string, args []interface{}) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	arg := func(i int) interface{} { return argAt(args, i) }
+
+	switch method {
+	case "find", "findOne":
+		doc["find"] = collection
+		setIfPresent(doc, "filter", arg(0))
+		setIfPresent(doc, "projection", arg(1))
+		if method == "findOne" {
+			doc["limit"] = float64(1)
+		}
+	case "countDocuments", "count":
+		return mongoShellCount(collection, arg(0)), nil
+	case "insertOne":
+		doc["insertOne"] = collection
+		doc["document"] = arg(0)
+	case "insertMany":
+		doc["insertMany"] = collection
+		doc["documents"] = arg(0)
+	case "insert":
+		if documents, ok := arg(0).([]interface{}); ok {
+			doc["insertMany"] = collection
+			doc["documents"] = documents
+		} else {
+			doc["insertOne"] = collection
+			doc["document"] = arg(0)
+		}
+	case "updateOne", "updateMany":
+		doc[method] = collection
+		doc["filter"] = arg(0)
+		doc["update"] = arg(1)
+		copyShellOption(doc, arg(2), "upsert")
+	case "replaceOne":
+		doc["replaceOne"] = collection
+		doc["filter"] = arg(0)
+		doc["replacement"] = arg(1)
+		copyShellOption(doc, arg(2), "upsert")
+	case "deleteOne", "deleteMany":
+		doc[method] = collection
+		setIfPresent(doc, "filter", arg(0))
+	case "remove":
+		doc["deleteMany"] = collection
+		setIfPresent(doc, "filter", arg(0))
+	case "distinct":
+		doc["distinct"] = collection
+		doc["key"] = arg(0)
+		setIfPresent(doc, "query", arg(1))
+	case "aggregate":
+		pipeline, ok := arg(0).([]interface{})
+		if !ok {
+			pipeline = args // stages passed as separate arguments
+		}
+		doc["aggregate"] = collection
+		doc["pipeline"] = pipeline
+	case "drop":
+		doc["drop"] = collection
+	case "createIndex":
+		doc["createIndexes"] = collection
+		doc["indexes"] = []interface{}{shellIndex(arg(0), arg(1))}
+	case "createIndexes":
+		keys, _ := arg(0).([]interface{})
+		indexes := make([]interface{}, len(keys))
+		for i, key := range keys {
+			indexes[i] = shellIndex(key, arg(1))
+		}
+		doc["createIndexes"] = collection
+		doc["indexes"] = indexes
+	case "dropIndex":
+		doc["dropIndexes"] = collection
+		doc["index"] = arg(0)
+	case "renameCollection":
+		doc["renameCollection"] = collection
+		doc["to"] = arg(0)
+	default:
+		return nil, fmt.Errorf("%w: shell method %s", ErrNotSupported, method)
+	}
+	return doc, nil
+}
+
+// mongoShellDatabaseCommand builds the command of db.<method>(args)
+func mongoShellDatabaseCommand(method string, args []interface{}) (map[string]interface{}, error) {
+	switch method {
+	case "createCollection":
+		return map[string]interface{}{"create": argAt(args, 0)}, nil
+	case "createView":
+		return map[string]interface{}{"createView": argAt(args, 0), "viewOn": argAt(args, 1), "pipeline": argAt(args, 2)}, nil
+	case "dropDatabase":
+		return map[string]interface{}{"dropDatabase": float64(1)}, nil
+	}
+	return nil, fmt.Errorf("%w: shell method db.%s", ErrNotSupported, method)
+}
+
+// applyMongoShellCursor applies a cursor method chained after find or aggregate
+func applyMongoShellCursor(doc map[string]interface{}, method string, args []interface{}) (map[string]interface{}, error) {
+	collection, isFind := doc["find"].(string)
+	_, isAggregate := doc["aggregate"].(string)
+
+	switch {
+	case method == "pretty" || method == "toArray":
+		return doc, nil
+	case isFind && (method == "sort" || method == "limit" || method == "skip" || method == "projection"):
+		doc[method] = argAt(args, 0)
+		return doc, nil
+	case isFind && (method == "count" || method == "countDocuments"):
+		return mongoShellCount(collection, doc["filter"]), nil
+	case isAggregate && method == "allowDiskUse":
+		return doc, nil
+	}
+	return nil, fmt.Errorf("%w: shell method .%s here", ErrNotSupported, method)
+}
+
+// mongoShellCount is the aggregate COUNT translates to
+func mongoShellCount(collection string, filter interface{}) map[string]interface{} {
+	var pipeline []interface{}
+	if filter != nil {
+		pipeline = append(pipeline, map[string]interface{}{"$match": filter})
+	}
+	pipeline = append(pipeline, map[string]interface{}{
+		"$group": map[string]interface{}{"_id": nil, "result": map[string]interface{}{"$sum": float64(1)}},
+	})
+	return map[string]interface{}{"aggregate": collection, "pipeline": pipeline}
+}
+
+func setIfPresent(doc map[string]interface{}, key string, value interface{}) {
+	if value != nil {
+		doc[key] = value
+	}
+}
+
+// shellIndex builds the createIndexes entry of an index key and its options
+func shellIndex(key, options interface{}) map[string]interface{} {
+	index := map[string]interface{}{"key": key}
+	if opts, ok := options.(map[string]interface{}); ok {
+		for name, value := range opts {
+			index[name] = value
+		}
+	}
+	return index
+}
+
+// copyShellOption copies one option of a method's options document
+func copyShellOption(doc map[string]interface{}, options interface{}, name string) {
+	if opts, ok := options.(map[string]interface{}); ok {
+		setIfPresent(doc, name, opts[name])
+	}
+}
+
+// ============================================================================
+// JAVASCRIPT LITERALS
+// ============================================================================
+
+// shellParser reads the JavaScript literals of a shell statement into the
+// values encoding/json would give for the same document
+type shellParser struct {
+	src string
+	pos int
+}
+
+func (p *shellParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrParseError, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *shellParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *shellParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// identifier reads a name: letters, digits, _ and $
+func (p *shellParser) identifier() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c != '_' && c != '$' && !unicode.IsLetter(rune(c)) && !unicode.IsDigit(rune(c)) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// arguments reads a parenthesized argument list
+func (p *shellParser) arguments() ([]interface{}, error) {
+	if p.peek() != '(' {
+		return nil, p.errorf("expected (")
+	}
+	p.pos++
+	var args []interface{}
+	for p.peek() != ')' {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != ')' {
+			return nil, p.errorf("expected , or )")
+		}
+	}
+	p.pos++
+	return args, nil
+}
+
+func (p *shellParser) value() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '/':
+		return p.regex()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case c == 0:
+		return nil, p.errorf("unexpected end of statement")
+	}
+	return p.constructor()
+}
+
+func (p *shellParser) object() (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	if err := p.members(func(key string, value interface{}) { obj[key] = value }); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// orderIndexKeys rereads the key documents of createIndex({...}) or
+// createIndexes([{...}, ...]), whose arguments start at pos, in order
+func (p *shellParser) orderIndexKeys(doc map[string]interface{}, pos int) error {
+	indexes, _ := doc["indexes"].([]interface{})
+	q := &shellParser{src: p.src, pos: pos}
+	q.peek()
+	q.pos++ // (
+	list := q.peek() == '['
+	if list {
+		q.pos++
+	}
+	for i := 0; i < len(indexes) && q.peek() == '{'; i++ {
+		var keys mongoIndexKeys
+		err := q.members(func(key string, value interface{}) {
+			keys = append(keys, mongoIndexKey{Field: key, Direction: value})
+		})
+		if err != nil {
+			return err
+		}
+		indexes[i].(map[string]interface{})["key"] = keys
+		if !list {
+			break
+		}
+		if q.peek() == ',' {
+			q.pos++
+		}
+	}
+	return nil
+}
+
+// members reads the key: value members of an object in order
+func (p *shellParser) members(member func(key string, value interface{})) error {
+	p.pos++ // {
+	for p.peek() != '}' {
+		var key string
+		if c := p.peek(); c == '"' || c == '\'' {
+			s, err := p.str()
+			if err != nil {
+				return err
+			}
+			key = s
+		} else if key = p.identifier(); key == "" {
+			return p.errorf("expected a key")
+		}
+		if p.peek() != ':' {
+			return p.errorf("expected : after %s", key)
+		}
+		p.pos++
+		value, err := p.value()
+		if err != nil {
+			return err
+		}
+		member(key, value)
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != '}' {
+			return p.errorf("expected , or }")
+		}
+	}
+	p.pos++
+	return nil
+}
+
+func (p *shellParser) array() ([]interface{}, error) {
+	p.pos++ // [
+	arr := []interface{}{}
+	for p.peek() != ']' {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, value)
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != ']' {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+	p.pos++
+	return arr, nil
+}
+
+func (p *shellParser) str() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.src):
+			escaped := p.src[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u':
+				if p.pos+4 <= len(p.src) {
+					if r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						p.pos += 4
+						continue
+					}
+				}
+				b.WriteByte(escaped)
+			default:
+				b.WriteByte(escaped)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// regex reads /pattern/flags as {$regex: pattern, $options: flags}
+func (p *shellParser) regex() (map[string]interface{}, error) {
+	p.pos++ // /
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != '/' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unterminated regular expression")
+	}
+	pattern := p.src[start:p.pos]
+	p.pos++
+	regex := map[string]interface{}{"$regex": pattern}
+	if flags := p.identifier(); flags != "" {
+		regex["$options"] = flags
+	}
+	return regex, nil
+}
+
+func (p *shellParser) number() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte("+-.0123456789eE", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %s", p.src[start:p.pos])
+	}
+	return n, nil
+}
+
+// constructor reads true, false, null and the BSON helpers: ObjectId("..."),
+// ISODate("...") and new Date("...") give their string, NumberInt(),
+// NumberLong() and NumberDecimal() their number
+func (p *shellParser) constructor() (interface{}, error) {
+	name := p.identifier()
+	switch name {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null", "undefined":
+		return nil, nil
+	case "new":
+		name = p.identifier()
+	case "":
+		return nil, p.errorf("unexpected %q", p.src[p.pos:p.pos+1])
+	}
+
+	args, err := p.arguments()
+	if err != nil {
+		return nil, err
+	}
+	value := argAt(args, 0)
+	switch name {
+	case "ObjectId", "ISODate", "Date", "UUID":
+		return value, nil
+	case "NumberInt", "NumberLong", "NumberDecimal", "Int32", "Long", "Decimal128", "Double":
+		if s, ok := value.(string); ok {
+			n, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, p.errorf("invalid number %s", s)
+			}
+			return n, nil
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w: shell helper %s()", ErrNotSupported, name)
+}
@@ -2,12 +2,14 @@ package reverse
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/omniql-engine/omniql/engine/models"
 
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/parser/test_driver"
 )
@@ -17,6 +19,7 @@ import (
 // ============================================================================
 
 func MySQLToQuery(sql string) (*models.Query, error) {
+	sql, alias := stripMySQLRowAlias(sql)
 	p := parser.New()
 	stmts, _, err := p.Parse(sql, "", "")
 	if err != nil {
@@ -33,7 +36,7 @@ func MySQLToQuery(sql string) (*models.Query, error) {
 	case *ast.SetOprStmt:
 		return convertMySQLSetOpr(stmt)
 	case *ast.InsertStmt:
-		return convertMySQLInsert(stmt)
+		return convertMySQLInsert(stmt, alias)
 	case *ast.UpdateStmt:
 		return convertMySQLUpdate(stmt)
 	case *ast.DeleteStmt:
@@ -77,6 +80,10 @@ func MySQLToQuery(sql string) (*models.Query, error) {
 		return &models.Query{Operation: "SAVEPOINT", Transaction: &models.Transaction{Operation: "SAVEPOINT", SavepointName: stmt.Name}}, nil
 	case *ast.ReleaseSavepointStmt:
 		return &models.Query{Operation: "RELEASE SAVEPOINT", Transaction: &models.Transaction{Operation: "RELEASE SAVEPOINT", SavepointName: stmt.Name}}, nil
+	case *ast.LockTablesStmt:
+		return convertMySQLLockTables(stmt), nil
+	case *ast.UnlockTablesStmt:
+		return convertMySQLUnlockTables(), nil
 
 	// ==================== DCL ====================
 	case *ast.GrantStmt:
@@ -191,6 +198,24 @@ func convertMySQLSelect(stmt *ast.SelectStmt) (*models.Query, error) {
 		query.Distinct = true
 	}
 
+	// FOR UPDATE / FOR SHARE [NOWAIT | SKIP LOCKED]
+	if stmt.LockInfo != nil {
+		switch stmt.LockInfo.LockType {
+		case ast.SelectLockForUpdate, ast.SelectLockForUpdateWaitN:
+			query.Lock = "UPDATE"
+		case ast.SelectLockForUpdateNoWait:
+			query.Lock, query.LockWait = "UPDATE", "NOWAIT"
+		case ast.SelectLockForUpdateSkipLocked:
+			query.Lock, query.LockWait = "UPDATE", "SKIP LOCKED"
+		case ast.SelectLockForShare:
+			query.Lock = "SHARE"
+		case ast.SelectLockForShareNoWait:
+			query.Lock, query.LockWait = "SHARE", "NOWAIT"
+		case ast.SelectLockForShareSkipLocked:
+			query.Lock, query.LockWait = "SHARE", "SKIP LOCKED"
+		}
+	}
+
 	// GROUP BY
 	if stmt.GroupBy != nil {
 		for _, item := range stmt.GroupBy.Items {
@@ -214,7 +239,35 @@ func convertMySQLSelect(stmt *ast.SelectStmt) (*models.Query, error) {
 // CRUD: INSERT → CREATE / BULK INSERT / UPSERT / REPLACE
 // ============================================================================
 
-func convertMySQLInsert(stmt *ast.InsertStmt) (*models.Query, error) {
+// mysqlRowAlias is the row alias of INSERT ... VALUES (...) AS new[(a, b)]
+// ON DUPLICATE KEY UPDATE, which names the proposed row (MySQL 8.0.19+)
+type mysqlRowAlias struct {
+	name    string
+	columns []string // optional column aliases, in insert column order
+}
+
+var mysqlRowAliasRegex = regexp.MustCompile(`(?is)\)\s*AS\s+\x60?(\w+)\x60?(?:\s*\(([^)]*)\))?\s+(ON\s+DUPLICATE\s+KEY\s+UPDATE\b)`)
+
+// stripMySQLRowAlias removes the row alias, which the SQL parser predates,
+// and returns it so references to it can be read as inserted values
+func stripMySQLRowAlias(sql string) (string, *mysqlRowAlias) {
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "INSERT") {
+		return sql, nil
+	}
+	m := mysqlRowAliasRegex.FindStringSubmatchIndex(sql)
+	if m == nil {
+		return sql, nil
+	}
+	alias := &mysqlRowAlias{name: sql[m[2]:m[3]]}
+	if m[4] >= 0 {
+		for _, column := range strings.Split(sql[m[4]:m[5]], ",") {
+			alias.columns = append(alias.columns, strings.Trim(strings.TrimSpace(column), "`"))
+		}
+	}
+	return sql[:m[0]+1] + " " + sql[m[6]:], alias
+}
+
+func convertMySQLInsert(stmt *ast.InsertStmt, alias *mysqlRowAlias) (*models.Query, error) {
 	entity := extractMySQLTableName(stmt.Table.TableRefs)
 
 	// Extract column names
@@ -236,7 +289,12 @@ func convertMySQLInsert(stmt *ast.InsertStmt) (*models.Query, error) {
 
 	// Check for ON DUPLICATE KEY UPDATE (UPSERT)
 	if len(stmt.OnDuplicate) > 0 {
-		return convertMySQLUpsert(entity, columns, lists, stmt.OnDuplicate)
+		return convertMySQLUpsert(entity, columns, lists, stmt.OnDuplicate, alias)
+	}
+
+	// INSERT IGNORE skips rows hitting any unique key: UPSERT ... DO NOTHING
+	if stmt.IgnoreErr {
+		return mysqlUpsertQuery(entity, columns, lists, &models.Upsert{DoNothing: true})
 	}
 
 	// Single row INSERT → CREATE
@@ -273,43 +331,118 @@ func convertMySQLReplace(entity string, columns []string, lists [][]ast.ExprNode
 	}, nil
 }
 
-func convertMySQLUpsert(entity string, columns []string, lists [][]ast.ExprNode, onDup []*ast.Assignment) (*models.Query, error) {
-	if len(lists) == 0 {
-		return nil, fmt.Errorf("%w: UPSERT without values", ErrParseError)
-	}
-
-	fields := buildMySQLFieldsFromLists(columns, lists[0])
+// insertedValueVisitor rewrites the references ON DUPLICATE KEY UPDATE makes
+// to the proposed row - VALUES(col), alias.col or a column alias - into the
+// portable EXCLUDED.col
+type insertedValueVisitor struct {
+	columns []string
+	alias   *mysqlRowAlias
+	sources map[string]bool // inserted columns referenced
+}
 
-	var updateFields []models.Field
-	for _, assign := range onDup {
-		valueExpr := mysqlExprToExpression(assign.Expr)
+func (v *insertedValueVisitor) Enter(n ast.Node) (ast.Node, bool) {
+	return n, false
+}
 
-		// Handle VALUES(column) function - extract just the column name
-		if funcExpr, ok := assign.Expr.(*ast.FuncCallExpr); ok {
-			if strings.ToUpper(funcExpr.FnName.O) == "VALUES" && len(funcExpr.Args) > 0 {
-				if colExpr, ok := funcExpr.Args[0].(*ast.ColumnNameExpr); ok {
-					valueExpr = LiteralExpr(colExpr.Name.Name.O)
-				}
-			}
+func (v *insertedValueVisitor) Leave(n ast.Node) (ast.Node, bool) {
+	column := ""
+	switch e := n.(type) {
+	case *ast.ValuesExpr:
+		if e.Column != nil {
+			column = e.Column.Name.Name.O
 		}
-		// Also handle ValuesExpr (pingcap specific for VALUES())
-		if valuesExpr, ok := assign.Expr.(*ast.ValuesExpr); ok {
-			if valuesExpr.Column != nil {
-				valueExpr = LiteralExpr(valuesExpr.Column.Name.Name.O)
+	case *ast.FuncCallExpr:
+		if strings.EqualFold(e.FnName.O, "VALUES") && len(e.Args) == 1 {
+			if col, ok := e.Args[0].(*ast.ColumnNameExpr); ok {
+				column = col.Name.Name.O
 			}
 		}
+	case *ast.ColumnNameExpr:
+		column = v.aliasedColumn(e.Name)
+	}
+	if column == "" {
+		return n, true
+	}
+	v.sources[strings.ToLower(column)] = true
+	return &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: model.NewCIStr("EXCLUDED." + column)}}, true
+}
+
+// aliasedColumn returns the inserted column that name references through
+// the row alias: alias.col, or a column alias with or without the row alias
+func (v *insertedValueVisitor) aliasedColumn(name *ast.ColumnName) string {
+	if v.alias == nil || name.Table.O != "" && !strings.EqualFold(name.Table.O, v.alias.name) {
+		return ""
+	}
+	for i, column := range v.alias.columns {
+		if strings.EqualFold(column, name.Name.O) && i < len(v.columns) {
+			return v.columns[i]
+		}
+	}
+	if name.Table.O != "" && len(v.alias.columns) == 0 {
+		return name.Name.O
+	}
+	return ""
+}
 
+// convertMySQLUpsert reads ON DUPLICATE KEY UPDATE. MySQL names no conflict
+// target, so it is inferred: the inserted columns the update neither sets nor
+// reads the inserted value of.
+func convertMySQLUpsert(entity string, columns []string, lists [][]ast.ExprNode, onDup []*ast.Assignment, alias *mysqlRowAlias) (*models.Query, error) {
+	visitor := &insertedValueVisitor{columns: columns, alias: alias, sources: map[string]bool{}}
+	updated := map[string]bool{}
+	var updateFields []models.Field
+	for _, assign := range onDup {
+		column := assign.Column.Name.O
+		updated[strings.ToLower(column)] = true
+
+		rewritten, _ := assign.Expr.Accept(visitor)
+		valueExpr := mysqlExprToExpression(rewritten.(ast.ExprNode))
+		// A column taking its own inserted value needs no value
+		if valueExpr != nil && valueExpr.Type == "FIELD" && strings.EqualFold(valueExpr.Value, "EXCLUDED."+column) {
+			valueExpr = nil
+		}
 		updateFields = append(updateFields, models.Field{
-			NameExpr:  FieldExpr(assign.Column.Name.O),
+			NameExpr:  FieldExpr(column),
 			ValueExpr: valueExpr,
 		})
 	}
 
+	var conflictFields []*models.Expression
+	for _, column := range columns {
+		if !updated[strings.ToLower(column)] && !visitor.sources[strings.ToLower(column)] {
+			conflictFields = append(conflictFields, FieldExpr(column))
+		}
+	}
+	if len(conflictFields) == 0 {
+		return nil, fmt.Errorf("%w: cannot infer the conflict key of ON DUPLICATE KEY UPDATE: every inserted column is updated or read", ErrNotSupported)
+	}
+
+	return mysqlUpsertQuery(entity, columns, lists, &models.Upsert{ConflictFields: conflictFields, UpdateFields: updateFields})
+}
+
+// mysqlUpsertQuery builds UPSERT from one row and BULK UPSERT from several
+func mysqlUpsertQuery(entity string, columns []string, lists [][]ast.ExprNode, upsert *models.Upsert) (*models.Query, error) {
+	if len(lists) == 0 {
+		return nil, fmt.Errorf("%w: UPSERT without values", ErrParseError)
+	}
+	if len(lists) == 1 {
+		return &models.Query{
+			Operation: "UPSERT",
+			Entity:    entity,
+			Fields:    buildMySQLFieldsFromLists(columns, lists[0]),
+			Upsert:    upsert,
+		}, nil
+	}
+
+	var bulkData [][]models.Field
+	for _, list := range lists {
+		bulkData = append(bulkData, buildMySQLFieldsFromLists(columns, list))
+	}
 	return &models.Query{
-		Operation: "UPSERT",
+		Operation: "BULK UPSERT",
 		Entity:    entity,
-		Fields:    fields,
-		Upsert:    &models.Upsert{UpdateFields: updateFields},
+		BulkData:  bulkData,
+		Upsert:    upsert,
 	}, nil
 }
 
@@ -677,6 +810,9 @@ func mysqlExprToExpression(expr ast.ExprNode) *models.Expression {
 	case *ast.ColumnNameExpr:
 		return FieldExpr(e.Name.Name.O)
 
+	case *test_driver.ParamMarkerExpr:
+		return LiteralExpr("?")
+
 	case *test_driver.ValueExpr:
 		return LiteralExpr(formatMySQLValue(e))
 
@@ -218,11 +218,21 @@ func mysqlSubqueryToExpression(subq *ast.SubqueryExpr) *models.Expression {
 // MySQL-specific: LOCK TABLES / UNLOCK TABLES
 // ============================================================================
 
-func convertMySQLLockTables(tables []string, lockType string) *models.Query {
+func convertMySQLLockTables(stmt *ast.LockTablesStmt) *models.Query {
+	var locks []models.TableLock
+	for _, tl := range stmt.TableLocks {
+		// READ LOCAL / WRITE LOCAL keep their base mode
+		mode := "WRITE"
+		if strings.HasPrefix(tl.Type.String(), "READ") {
+			mode = "READ"
+		}
+		locks = append(locks, models.TableLock{Entity: TableToEntity(tl.Table.Name.O), Mode: mode})
+	}
 	return &models.Query{
 		Operation: "LOCK TABLES",
 		Transaction: &models.Transaction{
-			Operation: "LOCK TABLES",
+			Operation:  "LOCK TABLES",
+			LockTables: locks,
 		},
 	}
 }
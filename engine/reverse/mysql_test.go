@@ -0,0 +1,53 @@
+package reverse
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/parser"
+)
+
+// ON DUPLICATE KEY UPDATE reverses to an UPSERT on the inferred conflict key,
+// reading VALUES(col) and the row alias as the inserted value
+func TestMySQLUpsert(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"INSERT INTO users (email, name) VALUES ('a', 'b') ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			`UPSERT User WITH email = "a", name = "b" ON email`},
+		{"INSERT INTO pages (id, hits) VALUES (1, 1) ON DUPLICATE KEY UPDATE hits = hits + 1",
+			`UPSERT Page WITH id = 1, hits = 1 ON id UPDATE SET hits = hits + 1`},
+		{"INSERT INTO users (email, name, nick) VALUES ('a', 'b', 'c') ON DUPLICATE KEY UPDATE name = VALUES(nick)",
+			`UPSERT User WITH email = "a", name = "b", nick = "c" ON email UPDATE SET name = EXCLUDED.nick`},
+		{"INSERT INTO users (email, name) VALUES ('a', 'b') AS new ON DUPLICATE KEY UPDATE name = new.name",
+			`UPSERT User WITH email = "a", name = "b" ON email`},
+		{"INSERT INTO pages (id, hits) VALUES (1, 1) AS `new` ON DUPLICATE KEY UPDATE hits = hits + new.hits",
+			`UPSERT Page WITH id = 1, hits = 1 ON id UPDATE SET hits = hits + EXCLUDED.hits`},
+		{"INSERT INTO users (email, name, nick) VALUES ('a', 'b', 'c') AS new(e, n, k) ON DUPLICATE KEY UPDATE name = k",
+			`UPSERT User WITH email = "a", name = "b", nick = "c" ON email UPDATE SET name = EXCLUDED.nick`},
+		{"INSERT INTO pages (id, hits) VALUES (1, 1), (2, 1) AS new ON DUPLICATE KEY UPDATE hits = new.hits",
+			`BULK UPSERT Page WITH [id = 1, hits = 1] [id = 2, hits = 1] ON id`},
+	}
+	for _, tt := range tests {
+		q, err := MySQLToQuery(tt.sql)
+		if err != nil {
+			t.Errorf("MySQLToQuery(%q): %v", tt.sql, err)
+			continue
+		}
+		got, err := parser.Render(q)
+		if err != nil {
+			t.Errorf("Render(%q): %v", tt.sql, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("MySQLToQuery(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+
+	// Every inserted column is updated: no column is left to be the key
+	_, err := MySQLToQuery("INSERT INTO pages (id, hits) VALUES (1, 1) ON DUPLICATE KEY UPDATE id = 2, hits = 3")
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported for an uninferable conflict key, got %v", err)
+	}
+}
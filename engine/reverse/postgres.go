@@ -278,7 +278,11 @@ func convertUpsert(entity string, columns []string, values *pg_query.Node, confl
 		Operation: "UPSERT",
 		Entity:    entity,
 		Fields:    fields,
-		Upsert:    &models.Upsert{ConflictFields: conflictFields, UpdateFields: updateFields},
+		Upsert: &models.Upsert{
+			ConflictFields: conflictFields,
+			UpdateFields:   updateFields,
+			DoNothing:      conflict.Action == pg_query.OnConflictAction_ONCONFLICT_NOTHING,
+		},
 	}, nil
 }
 
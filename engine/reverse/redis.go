@@ -115,15 +115,22 @@ func RedisToQuery(commandStr string) (*models.Query, error) {
 // ============================================================================
 
 // parseRedisCommand splits a Redis command string into parts
-// Handles quoted strings and preserves field-value pairs
+// Handles quoted strings and preserves field-value pairs; inside double
+// quotes a backslash escapes the next character, as in redis-cli
 func parseRedisCommand(commandStr string) []string {
 	var parts []string
 	var current strings.Builder
 	inQuotes := false
 	quoteChar := rune(0)
+	escaped := false
 
 	for _, r := range commandStr {
 		switch {
+		case escaped:
+			escaped = false
+			current.WriteRune(r)
+		case r == '\\' && inQuotes && quoteChar == '"':
+			escaped = true
 		case (r == '"' || r == '\'') && !inQuotes:
 			inQuotes = true
 			quoteChar = r
@@ -162,7 +169,7 @@ type RedisKeyInfo struct {
 }
 
 // parseRedisKey parses a Redis key into its components
-// Formats:
+// Formats (the first three parts may be wrapped in a {hash tag}):
 //   - tenant:tenantId:entity:id
 //   - tenant:tenantId:entity:*
 //   - tenant:tenantId:entity:field:value
@@ -170,6 +177,9 @@ type RedisKeyInfo struct {
 func parseRedisKey(key string) *RedisKeyInfo {
 	info := &RedisKeyInfo{}
 
+	// Redis Cluster hash tags: {tenant:tenantId:entity}:id
+	key = strings.NewReplacer("{", "", "}", "").Replace(key)
+
 	// Check for pattern
 	if strings.HasSuffix(key, ":*") || strings.HasSuffix(key, "*") {
 		info.IsPattern = true
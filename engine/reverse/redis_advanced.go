@@ -13,7 +13,7 @@ import (
 // ============================================================================
 // REDIS ADVANCED REVERSE TRANSLATION
 // Covers: UPSERT, REPLACE, List/Set/SortedSet operations, Complex expressions,
-//         Extended key patterns, Tenant isolation
+//         Extended key patterns, Tenant isolation, Index ranges, RediSearch
 // ============================================================================
 
 // ============================================================================
@@ -890,6 +890,14 @@ func ConvertExtendedRedisCommand(parts []string) (*models.Query, error) {
 		return convertRedisZRem(parts)
 	case "ZSCORE":
 		return convertRedisZScore(parts)
+	case "ZRANGEBYSCORE":
+		return convertRedisZRangeByScore(parts, false)
+	case "ZREVRANGEBYSCORE":
+		return convertRedisZRangeByScore(parts, true)
+
+	// RediSearch
+	case "FT.SEARCH":
+		return convertRedisFTSearch(parts)
 
 	// Hash operations
 	case "HGET":
@@ -1161,4 +1169,485 @@ func convertRedisExpire(parts []string) (*models.Query, error) {
 			},
 		},
 	}, nil
-}
\ No newline at end of file
+}
+
+// ============================================================================
+// INDEX RANGES (ZRANGEBYSCORE)
+// ============================================================================
+
+// parseRedisIndexKey reads a secondary index key, tenant:{tenant}:_idx:{entity}:{rest}
+// or, with hash tags, {tenant:{tenant}:{entity}}_idx:{rest}
+func parseRedisIndexKey(key string) (entity, rest string, ok bool) {
+	if strings.HasPrefix(key, "{") {
+		end := strings.Index(key, "}_idx:")
+		if end < 0 {
+			return "", "", false
+		}
+		tag := strings.Split(key[1:end], ":")
+		if len(tag) != 3 {
+			return "", "", false
+		}
+		return tag[2], key[end+len("}_idx:"):], true
+	}
+	parts := strings.SplitN(key, ":", 5)
+	if len(parts) != 5 || parts[0] != "tenant" || parts[2] != "_idx" {
+		return "", "", false
+	}
+	return parts[3], parts[4], true
+}
+
+// convertRedisZRangeByScore converts ZRANGEBYSCORE (and ZREVRANGEBYSCORE,
+// whose bounds come max first) to GET ordered by score. On a score index it
+// is a range over the indexed field; on any other sorted set it ranges over
+// _score like ZRANGE.
+func convertRedisZRangeByScore(parts []string, reverse bool) (*models.Query, error) {
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("%w: %s requires key, min, max", ErrParseError, strings.ToUpper(parts[0]))
+	}
+
+	min, max := parts[2], parts[3]
+	if reverse {
+		min, max = max, min
+	}
+
+	query := &models.Query{Operation: "GET"}
+	field := "_score"
+	entity, rest, isIndex := parseRedisIndexKey(parts[1])
+	if isIndex {
+		if !strings.HasPrefix(rest, "score:") {
+			return nil, fmt.Errorf("%w: ZRANGEBYSCORE over %s", ErrNotSupported, parts[1])
+		}
+		field = strings.TrimPrefix(rest, "score:")
+		query.Entity = TableToEntity(entity)
+	} else {
+		query.Entity = entityFromKey(parts[1])
+	}
+
+	direction := models.Asc
+	if reverse {
+		direction = models.Desc
+	}
+	query.OrderBy = []models.OrderBy{{FieldExpr: FieldExpr(field), Direction: direction}}
+
+	conditions, err := scoreRangeConditions(field, min, max)
+	if err != nil {
+		return nil, err
+	}
+	query.Conditions = conditions
+
+	for i := 4; i < len(parts); i++ {
+		if strings.ToUpper(parts[i]) == "LIMIT" && i+2 < len(parts) {
+			query.Offset, _ = strconv.Atoi(parts[i+1])
+			query.Limit, _ = strconv.Atoi(parts[i+2])
+			i += 2
+		}
+	}
+	return query, nil
+}
+
+// scoreRangeConditions converts score bounds to conditions on a field; "("
+// marks an exclusive bound and -inf / +inf an open one
+func scoreRangeConditions(field, min, max string) ([]models.Condition, error) {
+	lower, lowerOpen := strings.CutPrefix(min, "(")
+	upper, upperOpen := strings.CutPrefix(max, "(")
+	for _, bound := range []string{lower, upper} {
+		if _, err := strconv.ParseFloat(bound, 64); err != nil {
+			return nil, fmt.Errorf("%w: score bound %s", ErrParseError, bound)
+		}
+	}
+	lowerInf := strings.EqualFold(lower, "-inf")
+	upperInf := strings.EqualFold(upper, "+inf") || strings.EqualFold(upper, "inf")
+
+	if !lowerInf && !upperInf && !lowerOpen && !upperOpen {
+		if lower == upper {
+			return []models.Condition{NewCondition(FieldExpr(field), "=", LiteralExpr(lower))}, nil
+		}
+		return []models.Condition{{
+			FieldExpr:  FieldExpr(field),
+			Operator:   "BETWEEN",
+			ValueExpr:  LiteralExpr(lower),
+			Value2Expr: LiteralExpr(upper),
+		}}, nil
+	}
+
+	var conditions []models.Condition
+	if !lowerInf {
+		op := ">="
+		if lowerOpen {
+			op = ">"
+		}
+		conditions = append(conditions, NewCondition(FieldExpr(field), op, LiteralExpr(lower)))
+	}
+	if !upperInf {
+		op := "<="
+		if upperOpen {
+			op = "<"
+		}
+		logic := ""
+		if len(conditions) > 0 {
+			logic = "AND"
+		}
+		conditions = append(conditions, NewConditionWithLogic(FieldExpr(field), op, LiteralExpr(upper), logic))
+	}
+	return conditions, nil
+}
+
+// ============================================================================
+// REDISEARCH (FT.SEARCH)
+// ============================================================================
+
+// convertRedisFTSearch converts FT.SEARCH index query [SORTBY field [ASC|DESC]]
+// [LIMIT offset count] to GET. The index names the entity: the last part of
+// tenant:{tenant}:_ft:{entity}, or of any other index name.
+func convertRedisFTSearch(parts []string) (*models.Query, error) {
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("%w: FT.SEARCH requires index and query", ErrParseError)
+	}
+
+	index := parts[1]
+	query := &models.Query{
+		Operation: "GET",
+		Entity:    TableToEntity(index[strings.LastIndex(index, ":")+1:]),
+	}
+
+	conditions, err := parseSearchQuery(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	query.Conditions = conditions
+
+	for i := 3; i < len(parts); i++ {
+		switch strings.ToUpper(parts[i]) {
+		case "SORTBY":
+			if i+1 >= len(parts) {
+				return nil, fmt.Errorf("%w: SORTBY requires field", ErrParseError)
+			}
+			orderBy := models.OrderBy{FieldExpr: FieldExpr(parts[i+1]), Direction: models.Asc}
+			i++
+			if i+1 < len(parts) && strings.EqualFold(parts[i+1], "DESC") {
+				orderBy.Direction = models.Desc
+				i++
+			} else if i+1 < len(parts) && strings.EqualFold(parts[i+1], "ASC") {
+				i++
+			}
+			query.OrderBy = []models.OrderBy{orderBy}
+		case "LIMIT":
+			if i+2 >= len(parts) {
+				return nil, fmt.Errorf("%w: LIMIT requires offset and count", ErrParseError)
+			}
+			query.Offset, _ = strconv.Atoi(parts[i+1])
+			query.Limit, _ = strconv.Atoi(parts[i+2])
+			i += 2
+		case "RETURN":
+			// RETURN n field ... selects the returned fields
+			if i+1 < len(parts) {
+				n, _ := strconv.Atoi(parts[i+1])
+				for j := i + 2; j < i+2+n && j < len(parts); j++ {
+					query.Columns = append(query.Columns, FieldExpr(parts[j]))
+				}
+				i += 1 + n
+			}
+		}
+	}
+	return query, nil
+}
+
+// searchQueryParser reads the RediSearch query language BuildSearchQuery
+// writes: terms separated by spaces are AND-ed, | joins them with OR, and a
+// term is @field:[min max], @field:{tag | tag}, @field:(text) or a
+// parenthesized query, optionally negated with -
+type searchQueryParser struct {
+	query string
+	pos   int
+}
+
+func parseSearchQuery(query string) ([]models.Condition, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || query == "*" {
+		return nil, nil
+	}
+	p := &searchQueryParser{query: query}
+	conditions, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.query) {
+		return nil, fmt.Errorf("%w: unexpected %q in search query", ErrParseError, p.query[p.pos:])
+	}
+	return conditions, nil
+}
+
+func (p *searchQueryParser) skipSpace() {
+	for p.pos < len(p.query) && p.query[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *searchQueryParser) peek() byte {
+	if p.pos < len(p.query) {
+		return p.query[p.pos]
+	}
+	return 0
+}
+
+// parseUnion reads runs of AND-ed terms joined by |. The runs are kept flat,
+// the first term of each after the first joined with OR, since AND binds
+// tighter than OR in OQL as well.
+func (p *searchQueryParser) parseUnion() ([]models.Condition, error) {
+	var conditions []models.Condition
+	for {
+		run, err := p.parseIntersect()
+		if err != nil {
+			return nil, err
+		}
+		for i, cond := range run {
+			switch {
+			case len(conditions) == 0:
+				cond.Logic = ""
+			case i == 0:
+				cond.Logic = "OR"
+			default:
+				cond.Logic = "AND"
+			}
+			conditions = append(conditions, cond)
+		}
+		if p.skipSpace(); p.peek() != '|' {
+			return conditions, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *searchQueryParser) parseIntersect() ([]models.Condition, error) {
+	var conditions []models.Condition
+	for {
+		p.skipSpace()
+		if c := p.peek(); c == 0 || c == '|' || c == ')' {
+			if len(conditions) == 0 {
+				return nil, fmt.Errorf("%w: empty term in search query", ErrParseError)
+			}
+			return conditions, nil
+		}
+		terms, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, terms...)
+	}
+}
+
+// parseTerm reads one term; a parenthesized query with no OR is returned as
+// its own terms
+func (p *searchQueryParser) parseTerm() ([]models.Condition, error) {
+	negate := p.peek() == '-'
+	if negate {
+		p.pos++
+	}
+
+	switch p.peek() {
+	case '*':
+		p.pos++
+		return nil, nil
+	case '(':
+		p.pos++
+		nested, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpace(); p.peek() != ')' {
+			return nil, fmt.Errorf("%w: unclosed ( in search query", ErrParseError)
+		}
+		p.pos++
+		return searchGroup(nested, negate)
+	case '@':
+		p.pos++
+	default:
+		return nil, fmt.Errorf("%w: full-text search over every field", ErrNotSupported)
+	}
+
+	field := unescapeSearch(p.readUntil(':'))
+	if p.peek() != ':' || field == "" {
+		return nil, fmt.Errorf("%w: field without value in search query", ErrParseError)
+	}
+	p.pos++
+
+	var cond models.Condition
+	var err error
+	switch p.peek() {
+	case '[':
+		p.pos++
+		cond, err = searchRange(field, p.readUntil(']'))
+		p.pos++
+	case '{':
+		p.pos++
+		cond, err = searchTags(field, p.readUntil('}'))
+		p.pos++
+	case '(':
+		p.pos++
+		text := unescapeSearch(p.readUntil(')'))
+		p.pos++
+		if negate {
+			return nil, fmt.Errorf("%w: negated full-text search", ErrNotSupported)
+		}
+		return []models.Condition{NewCondition(FieldExpr(field), "SEARCH", LiteralExpr(text))}, nil
+	default:
+		return nil, fmt.Errorf("%w: full-text search on @%s", ErrNotSupported, field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.pos > len(p.query) {
+		return nil, fmt.Errorf("%w: unclosed term in search query", ErrParseError)
+	}
+	if negate {
+		if cond, err = negateSearchCondition(cond); err != nil {
+			return nil, err
+		}
+	}
+	return []models.Condition{cond}, nil
+}
+
+// readUntil reads up to an unescaped end byte, keeping the escapes
+func (p *searchQueryParser) readUntil(end byte) string {
+	start := p.pos
+	for p.pos < len(p.query) && p.query[p.pos] != end {
+		if p.query[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos > len(p.query) {
+		p.pos = len(p.query)
+	}
+	return p.query[start:p.pos]
+}
+
+// splitSearchTags splits a tag list at the | not escaped
+func splitSearchTags(tags string) []string {
+	var list []string
+	start := 0
+	for i := 0; i < len(tags); i++ {
+		switch tags[i] {
+		case '\\':
+			i++
+		case '|':
+			list = append(list, tags[start:i])
+			start = i + 1
+		}
+	}
+	return append(list, tags[start:])
+}
+
+// unescapeSearch drops the backslashes escapeSearch adds
+func unescapeSearch(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// searchRange converts @field:[min max]
+func searchRange(field, bounds string) (models.Condition, error) {
+	values := strings.Fields(bounds)
+	if len(values) != 2 {
+		return models.Condition{}, fmt.Errorf("%w: numeric range [%s]", ErrParseError, bounds)
+	}
+	conditions, err := scoreRangeConditions(field, values[0], values[1])
+	if err != nil {
+		return models.Condition{}, err
+	}
+	if len(conditions) != 1 {
+		return models.Condition{Operator: "GROUP", Nested: conditions}, nil
+	}
+	return conditions[0], nil
+}
+
+// searchTags converts @field:{tag | tag}; a lone tag ending in an unescaped *
+// is a prefix
+func searchTags(field, tags string) (models.Condition, error) {
+	var raw []string
+	for _, tag := range splitSearchTags(tags) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			raw = append(raw, tag)
+		}
+	}
+	switch {
+	case len(raw) == 0:
+		return models.Condition{}, fmt.Errorf("%w: empty tag list", ErrParseError)
+	case len(raw) > 1:
+		values := make([]*models.Expression, len(raw))
+		for i, tag := range raw {
+			values[i] = LiteralExpr(unescapeSearch(tag))
+		}
+		return models.Condition{FieldExpr: FieldExpr(field), Operator: "IN", ValuesExpr: values}, nil
+	}
+	tag := raw[0]
+	if strings.HasSuffix(tag, "*") && !strings.HasSuffix(tag, "\\*") {
+		return NewCondition(FieldExpr(field), "LIKE", LiteralExpr(unescapeSearch(strings.TrimSuffix(tag, "*"))+"%")), nil
+	}
+	return NewCondition(FieldExpr(field), "=", LiteralExpr(unescapeSearch(tag))), nil
+}
+
+// searchGroup converts a parenthesized query. BuildSearchQuery writes numeric
+// IN lists as equality ranges joined by |, which are read back as IN.
+func searchGroup(nested []models.Condition, negate bool) ([]models.Condition, error) {
+	if in, ok := searchInList(nested); ok {
+		if negate {
+			in.Operator = "NOT_IN"
+		}
+		return []models.Condition{in}, nil
+	}
+	if negate {
+		return nil, fmt.Errorf("%w: negated search group", ErrNotSupported)
+	}
+	for _, cond := range nested[1:] {
+		if cond.Logic == "OR" {
+			return []models.Condition{{Operator: "GROUP", Nested: nested}}, nil
+		}
+	}
+	return nested, nil
+}
+
+// searchInList reports whether conditions are equalities on one field joined by OR
+func searchInList(conditions []models.Condition) (models.Condition, bool) {
+	if len(conditions) < 2 {
+		return models.Condition{}, false
+	}
+	in := models.Condition{FieldExpr: conditions[0].FieldExpr, Operator: "IN"}
+	for i, cond := range conditions {
+		if cond.Operator != "=" || cond.FieldExpr == nil || cond.FieldExpr.Value != in.FieldExpr.Value ||
+			(i > 0 && cond.Logic != "OR") {
+			return models.Condition{}, false
+		}
+		in.ValuesExpr = append(in.ValuesExpr, cond.ValueExpr)
+	}
+	return in, true
+}
+
+// searchNegations are the operators of negated terms
+var searchNegations = map[string]string{
+	"=":       "!=",
+	"IN":      "NOT_IN",
+	"BETWEEN": "NOT_BETWEEN",
+	"LIKE":    "NOT_LIKE",
+	">":       "<=",
+	">=":      "<",
+	"<":       ">=",
+	"<=":      ">",
+}
+
+func negateSearchCondition(cond models.Condition) (models.Condition, error) {
+	negated, ok := searchNegations[cond.Operator]
+	if !ok {
+		return models.Condition{}, fmt.Errorf("%w: negated search range", ErrNotSupported)
+	}
+	cond.Operator = negated
+	return cond, nil
+}
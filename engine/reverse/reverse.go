@@ -32,6 +32,8 @@ func ToQuery(query string, dbType string) (*models.Query, error) {
 		return PostgreSQLToQuery(query)
 	case "MySQL":
 		return MySQLToQuery(query)
+	case "SQLite":
+		return SQLiteToQuery(query)
 	case "MongoDB":
 		return MongoDBToQuery(query)
 	case "Redis":
@@ -0,0 +1,70 @@
+package reverse
+
+import (
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/parser"
+)
+
+// Native queries reverse to the OQL that translates back to them
+func TestToQuery(t *testing.T) {
+	tests := []struct {
+		db     string
+		native string
+		want   string
+	}{
+		{"PostgreSQL", "SELECT * FROM users WHERE age > 21 ORDER BY name DESC LIMIT 10 OFFSET 5",
+			`GET User WHERE age > 21 ORDER BY name DESC LIMIT 10 OFFSET 5`},
+		{"PostgreSQL", "SELECT name, email FROM users WHERE status IN ('a', 'b')",
+			`GET name, email FROM User WHERE status IN ("a", "b")`},
+		{"PostgreSQL", "INSERT INTO users (name, age) VALUES ('Alice', 30)",
+			`CREATE User WITH name = "Alice", age = 30`},
+		{"PostgreSQL", "UPDATE users SET age = 31 WHERE id = 1",
+			`UPDATE User SET age = 31 WHERE id = 1`},
+		{"PostgreSQL", "DELETE FROM users WHERE id = 1",
+			`DELETE User WHERE id = 1`},
+		{"PostgreSQL", "INSERT INTO users (email, name) VALUES ('a', 'b') ON CONFLICT (email) DO NOTHING",
+			`UPSERT User WITH email = "a", name = "b" ON email DO NOTHING`},
+		{"PostgreSQL", "TRUNCATE TABLE logs",
+			`TRUNCATE TABLE Log`},
+		{"PostgreSQL", "DROP TABLE users",
+			`DROP TABLE User`},
+		{"SQLite", "SELECT * FROM users WHERE age > 21 LIMIT 10",
+			`GET User WHERE age > 21 LIMIT 10`},
+		{"SQLite", "DELETE FROM users WHERE age < 18",
+			`DELETE User WHERE age < 18`},
+		{"MongoDB", `{"find":"users","filter":{"age":{"$gt":21}},"sort":{"name":-1},"limit":10}`,
+			`GET User WHERE age > 21 ORDER BY name DESC LIMIT 10`},
+		{"MongoDB", `{"insertOne":"users","document":{"name":"Alice","age":30}}`,
+			`CREATE User WITH age = 30, name = "Alice"`},
+		{"MongoDB", `{"updateOne":"users","filter":{"email":"a"},"update":{"$set":{"name":"b"}},"upsert":true}`,
+			`UPSERT User WITH email = "a", name = "b" ON email`},
+		{"MongoDB", `{"updateMany":"users","filter":{"id":1},"update":{"$inc":{"balance":100}}}`,
+			`UPDATE User SET balance = balance + 100 WHERE id = 1`},
+		{"MongoDB", `{"deleteMany":"users","filter":{"status":"banned"}}`,
+			`DELETE User WHERE status = "banned"`},
+		{"Redis", "HGETALL tenant:t1:user:1",
+			`GET User WHERE id = 1`},
+		{"Redis", "HMSET tenant:t1:user:1 name Alice age 30",
+			`CREATE User WITH id = 1, name = "Alice", age = 30`},
+		{"Redis", "HSET tenant:t1:user:1 age 31",
+			`UPDATE User SET age = 31 WHERE id = 1`},
+		{"Redis", "DEL tenant:t1:user:1",
+			`DELETE User WHERE id = 1`},
+	}
+	for _, tt := range tests {
+		q, err := ToQuery(tt.native, tt.db)
+		if err != nil {
+			t.Errorf("%s: ToQuery(%q): %v", tt.db, tt.native, err)
+			continue
+		}
+		got, err := parser.Render(q)
+		if err != nil {
+			t.Errorf("%s: Render(%q): %v", tt.db, tt.native, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: ToQuery(%q):\n got %s\nwant %s", tt.db, tt.native, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package reverse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/models"
+)
+
+// ============================================================================
+// ENTRY POINT
+// ============================================================================
+
+// SQLite shares PostgreSQL's syntax for almost every statement, ON CONFLICT
+// upserts included, so only the SQLite-only forms are read here and the rest
+// goes through PostgreSQLToQuery.
+var (
+	sqliteInsertOr = regexp.MustCompile(`(?i)^INSERT\s+OR\s+(REPLACE|IGNORE|ABORT|FAIL|ROLLBACK)\s+INTO\b`)
+	sqliteReplace  = regexp.MustCompile(`(?i)^REPLACE\s+INTO\b`)
+	sqliteAttach   = regexp.MustCompile(`(?i)^ATTACH\s+(?:DATABASE\s+)?'((?:[^']|'')*)'\s+AS\s+"?(\w+)"?$`)
+	sqliteDetach   = regexp.MustCompile(`(?i)^DETACH\s+(?:DATABASE\s+)?"?(\w+)"?$`)
+	sqlitePragma   = regexp.MustCompile(`(?i)^PRAGMA\s+(?:"?(\w+)"?\.)?(\w+)\s*(?:=\s*(\S+)|\(\s*([^)]*?)\s*\))?$`)
+)
+
+func SQLiteToQuery(sql string) (*models.Query, error) {
+	sql = sqliteParameters(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sql), ";")))
+
+	switch {
+	case sqliteInsertOr.MatchString(sql):
+		action := strings.ToUpper(sqliteInsertOr.FindStringSubmatch(sql)[1])
+		return convertSQLiteInsertOr(action, sqliteInsertOr.ReplaceAllString(sql, "INSERT INTO"))
+	case sqliteReplace.MatchString(sql):
+		return convertSQLiteInsertOr("REPLACE", sqliteReplace.ReplaceAllString(sql, "INSERT INTO"))
+	case sqliteAttach.MatchString(sql):
+		m := sqliteAttach.FindStringSubmatch(sql)
+		return &models.Query{
+			Operation:    "CREATE DATABASE",
+			DatabaseName: m[2],
+			DatabaseFile: strings.ReplaceAll(m[1], "''", "'"),
+		}, nil
+	case sqliteDetach.MatchString(sql):
+		return &models.Query{Operation: "DROP DATABASE", DatabaseName: sqliteDetach.FindStringSubmatch(sql)[1]}, nil
+	case sqlitePragma.MatchString(sql):
+		m := sqlitePragma.FindStringSubmatch(sql)
+		return convertSQLitePragma(m[1], strings.ToLower(m[2]), m[3]+m[4])
+	case len(sql) >= 6 && strings.EqualFold(sql[:6], "PRAGMA"):
+		return nil, fmt.Errorf("%w: malformed PRAGMA", ErrParseError)
+	}
+
+	return PostgreSQLToQuery(sql)
+}
+
+// sqliteParameters numbers the ? parameters outside quotes as $1, $2, ... so
+// the PostgreSQL parser reads them
+func sqliteParameters(sql string) string {
+	if !strings.Contains(sql, "?") {
+		return sql
+	}
+	var b strings.Builder
+	quote := byte(0)
+	n := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// ============================================================================
+// CRUD: INSERT OR REPLACE / INSERT OR IGNORE
+// ============================================================================
+
+// convertSQLiteInsertOr reads INSERT OR <action> as a plain INSERT and applies
+// its conflict action: REPLACE deletes the conflicting row first, IGNORE
+// skips the row like ON CONFLICT DO NOTHING, and ABORT, FAIL and ROLLBACK only
+// choose how much of the failed statement is undone.
+func convertSQLiteInsertOr(action, insert string) (*models.Query, error) {
+	query, err := PostgreSQLToQuery(insert)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case "REPLACE":
+		if query.Operation == "BULK INSERT" {
+			return nil, fmt.Errorf("%w: REPLACE of several rows", ErrNotSupported)
+		}
+		if query.Operation == "CREATE" {
+			query.Operation = "REPLACE"
+		}
+	case "IGNORE":
+		switch query.Operation {
+		case "CREATE":
+			query.Operation = "UPSERT"
+			query.Upsert = &models.Upsert{DoNothing: true}
+		case "BULK INSERT":
+			query.Operation = "BULK UPSERT"
+			query.Upsert = &models.Upsert{DoNothing: true}
+		}
+	}
+	return query, nil
+}
+
+// ============================================================================
+// PRAGMA
+// ============================================================================
+
+// convertSQLitePragma maps the schema PRAGMAs BuildSchemaPragmaSQL writes to
+// SHOW TABLES, SHOW COLUMNS and SHOW INDEXES (of schema.Entity when the PRAGMA
+// names an attached database), and the PRAGMAs BuildSetTransactionSQL writes
+// back to SET TRANSACTION. Other PRAGMAs tune the engine or read schema
+// details OQL has no operation for.
+func convertSQLitePragma(schema, name, value string) (*models.Query, error) {
+	switch name {
+	case "table_list":
+		if value != "" {
+			return nil, fmt.Errorf("%w: PRAGMA table_list of one table", ErrNotSupported)
+		}
+		return &models.Query{Operation: "SHOW TABLES"}, nil
+	case "table_info", "index_list":
+		table := strings.Trim(value, `'"`+"`[]")
+		if table == "" {
+			return nil, fmt.Errorf("%w: PRAGMA %s without a table", ErrParseError, name)
+		}
+		operation := "SHOW COLUMNS"
+		if name == "index_list" {
+			operation = "SHOW INDEXES"
+		}
+		entity := TableToEntity(table)
+		if schema != "" && !strings.EqualFold(schema, "main") {
+			entity = schema + "." + entity
+		}
+		return &models.Query{Operation: operation, Entity: entity}, nil
+	case "read_uncommitted", "query_only":
+	default:
+		return nil, fmt.Errorf("%w: PRAGMA %s (OQL has no operation for it)", ErrNotSupported, name)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("%w: reading PRAGMA %s", ErrNotSupported, name)
+	}
+
+	on := false
+	switch strings.ToUpper(strings.Trim(value, `'"`)) {
+	case "1", "ON", "TRUE", "YES":
+		on = true
+	case "0", "OFF", "FALSE", "NO":
+	default:
+		return nil, fmt.Errorf("%w: PRAGMA %s = %s", ErrParseError, name, value)
+	}
+
+	query := &models.Query{
+		Operation:   "SET TRANSACTION",
+		Transaction: &models.Transaction{Operation: "SET TRANSACTION"},
+	}
+	if name == "read_uncommitted" {
+		query.Transaction.IsolationLevel = "SERIALIZABLE"
+		if on {
+			query.Transaction.IsolationLevel = "READ UNCOMMITTED"
+		}
+	} else {
+		query.Transaction.ReadOnly = on
+	}
+	return query, nil
+}
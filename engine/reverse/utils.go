@@ -79,6 +79,12 @@ func GetOQLType(sqlType, dbType string) string {
 			return oql
 		}
 	}
+	// Types registered after init (mapping.RegisterType)
+	for oqlType, native := range mapping.TypeMap[dbType] {
+		if strings.EqualFold(native, sqlType) {
+			return oqlType
+		}
+	}
 	return sqlType // Return as-is if not mapped
 }
 
@@ -86,14 +92,18 @@ func GetOQLType(sqlType, dbType string) string {
 // TABLE ↔ ENTITY CONVERSION
 // ============================================================================
 
-// TableToEntity: users → User, order_items → OrderItem
+// TableToEntity: users → User, order_items → OrderItem, people → Person
+// A table named in mapping.TableNameOverrides maps back to its entity.
 func TableToEntity(table string) string {
-	return toPascalCase(toSingular(strings.ToLower(table)))
+	if entity, ok := mapping.TableEntity(table); ok {
+		return entity
+	}
+	return toPascalCase(mapping.Singularize(strings.ToLower(table)))
 }
 
-// EntityToTable: User → users, OrderItem → order_items
+// EntityToTable: User → users, Person → people (the translators' table name)
 func EntityToTable(entity string) string {
-	return toPlural(toSnakeCase(entity))
+	return mapping.EntityTable(entity)
 }
 
 // ============================================================================
@@ -129,57 +139,6 @@ func FunctionExpr(name string, args ...*models.Expression) *models.Expression {
 // STRING TRANSFORMATIONS
 // ============================================================================
 
-func toSingular(word string) string {
-	irregulars := map[string]string{
-		"people": "person", "children": "child", "men": "man", "women": "woman",
-		"teeth": "tooth", "feet": "foot", "geese": "goose", "mice": "mouse",
-	}
-	if s, ok := irregulars[word]; ok {
-		return s
-	}
-	if strings.HasSuffix(word, "ies") && len(word) > 3 {
-		return word[:len(word)-3] + "y"
-	}
-	if strings.HasSuffix(word, "ves") && len(word) > 3 {
-		return word[:len(word)-3] + "f"
-	}
-	if strings.HasSuffix(word, "oes") && len(word) > 3 {
-		return word[:len(word)-2]
-	}
-	if strings.HasSuffix(word, "ses") || strings.HasSuffix(word, "xes") ||
-		strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes") {
-		return word[:len(word)-2]
-	}
-	if strings.HasSuffix(word, "s") && len(word) > 1 {
-		return word[:len(word)-1]
-	}
-	return word
-}
-
-func toPlural(word string) string {
-	irregulars := map[string]string{
-		"person": "people", "child": "children", "man": "men", "woman": "women",
-		"tooth": "teeth", "foot": "feet", "goose": "geese", "mouse": "mice",
-	}
-	if p, ok := irregulars[word]; ok {
-		return p
-	}
-	if strings.HasSuffix(word, "y") && len(word) > 1 && !isVowel(rune(word[len(word)-2])) {
-		return word[:len(word)-1] + "ies"
-	}
-	if strings.HasSuffix(word, "f") {
-		return word[:len(word)-1] + "ves"
-	}
-	if strings.HasSuffix(word, "fe") {
-		return word[:len(word)-2] + "ves"
-	}
-	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") ||
-		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh") {
-		return word + "es"
-	}
-	return word + "s"
-}
-
 func toPascalCase(snake string) string {
 	var b strings.Builder
 	cap := true
@@ -198,25 +157,6 @@ func toPascalCase(snake string) string {
 	return b.String()
 }
 
-func toSnakeCase(pascal string) string {
-	var b strings.Builder
-	for i, r := range pascal {
-		if unicode.IsUpper(r) {
-			if i > 0 {
-				b.WriteRune('_')
-			}
-			b.WriteRune(unicode.ToLower(r))
-		} else {
-			b.WriteRune(r)
-		}
-	}
-	return b.String()
-}
-
-func isVowel(r rune) bool {
-	return r == 'a' || r == 'e' || r == 'i' || r == 'o' || r == 'u'
-}
-
 // ============================================================================
 // ADDITIONAL TRUEAST BUILDERS (for DQL/Advanced)
 // ============================================================================
@@ -0,0 +1,491 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	bqbuilders "github.com/omniql-engine/omniql/engine/builders/bigquery"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	return &pb.Expression{
+		Type:           expr.Type,
+		Value:          expr.Value,
+		Left:           mapBigQueryExpression(expr.Left),
+		Operator:       expr.Operator,
+		Right:          mapBigQueryExpression(expr.Right),
+		FunctionName:   expr.FunctionName,
+		FunctionArgs:   mapBigQueryExpressions(expr.FunctionArgs),
+		CaseConditions: mapBigQueryCaseConditions(expr.CaseConditions),
+		CaseElse:       mapBigQueryExpression(expr.CaseElse),
+	}
+}
+
+func mapBigQueryExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapBigQueryExpression(expr))
+	}
+	return result
+}
+
+func mapBigQueryCaseConditions(conditions []*models.CaseCondition) []*pb.CaseCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.CaseCondition
+	for _, cc := range conditions {
+		result = append(result, &pb.CaseCondition{
+			Condition: mapBigQueryCondition(cc.Condition),
+			ThenExpr:  mapBigQueryExpression(cc.ThenExpr),
+		})
+	}
+	return result
+}
+
+func mapBigQueryCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapBigQueryExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapBigQueryExpression(cond.ValueExpr),
+		Value2Expr: mapBigQueryExpression(cond.Value2Expr),
+		ValuesExpr: mapBigQueryExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapBigQueryConditions(cond.Nested),
+	}
+}
+
+func mapBigQueryConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapBigQueryCondition(&cond))
+	}
+	return result
+}
+
+func mapBigQueryOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapBigQueryExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+// TranslateBigQuery converts OQL Query to BigQuery RelationalQuery.
+// Writes that BigQuery cannot express are rejected here rather than built:
+// DML returns no rows and takes no row locks, and tables are partitioned by
+// time unit only.
+func TranslateBigQuery(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	operation := mapping.OperationMap["BigQuery"][query.Operation]
+	if len(query.Returning) > 0 {
+		return nil, &mapping.ErrNotSupported{Database: "BigQuery", Feature: "RETURNING"}
+	}
+	if query.Lock != "" {
+		return nil, &mapping.ErrNotSupported{Database: "BigQuery", Feature: "FOR " + query.Lock}
+	}
+	switch strings.ToUpper(query.PartitionStrategy) {
+	case "RANGE", "LIST", "HASH":
+		return nil, &mapping.ErrNotSupported{Database: "BigQuery", Feature: "PARTITION BY " + query.PartitionStrategy}
+	}
+
+	table := TableName(query.Entity, query.Operation)
+	conditions := mapBigQueryConditions(query.Conditions)
+	fields := mapBigQueryFields(query.Fields)
+
+	// DQL: Map fields
+	joins := mapBigQueryJoins(query.Joins)
+	aggregate := mapBigQueryAggregate(query.Aggregate)
+	orderBy := mapBigQueryOrderByClauses(query.OrderBy)
+	having := mapBigQueryConditions(query.Having)
+
+	// DQL: Advanced fields
+	windowFunctions := mapBigQueryWindowFunctions(query.WindowFunctions)
+	cte, err := mapBigQueryCTE(query.CTE, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	subquery, err := mapBigQuerySubquery(query.Subquery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	setOperation, err := mapBigQuerySetOperation(query.SetOperation, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// DDL
+	viewQuery, err := mapBigQueryViewQuery(query.ViewQuery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	newName := query.NewName
+	if query.NewName != "" && query.Operation == "RENAME TABLE" {
+		newName = TableName(query.NewName, query.Operation)
+	}
+
+	result := &pb.RelationalQuery{
+		Operation:  operation,
+		Table:      table,
+		Conditions: conditions,
+		Fields:     fields,
+		Limit:      int32(query.Limit),
+		Offset:     int32(query.Offset),
+		Distinct:   query.Distinct,
+
+		// DQL
+		Joins:           joins,
+		Columns:         mapBigQueryExpressions(query.Columns),
+		SelectColumns:   mapBigQuerySelectColumns(query.SelectColumns),
+		Aggregate:       aggregate,
+		OrderBy:         orderBy,
+		GroupBy:         mapBigQueryExpressions(query.GroupBy),
+		Having:          having,
+		WindowFunctions: windowFunctions,
+		Cte:             cte,
+		Subquery:        subquery,
+		Pattern:         query.Pattern,
+		SetOperation:    setOperation,
+
+		// CRUD Extensions
+		BulkData: mapBigQueryBulkData(query.BulkData),
+
+		// DDL
+		ViewName:          query.ViewName,
+		ViewQuery:         viewQuery,
+		NewName:           newName,
+		AlterAction:       query.AlterAction,
+		Cascade:           query.Cascade,
+		PartitionStrategy: query.PartitionStrategy,
+		PartitionKeys:     mapBigQueryExpressions(query.PartitionKeys),
+		ClusterKeys:       mapBigQueryExpressions(query.ClusterKeys),
+		TableOptions:      query.TableOptions,
+		SchemaName:        query.SchemaName,
+		DatabaseName:      query.DatabaseName,
+	}
+
+	sql, err := buildBigQueryString(result)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = bqbuilders.NumberParams(sql)
+	return result, nil
+}
+
+// ============================================================================
+// FIELD MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryFields(fields []models.Field) []*pb.QueryField {
+	if len(fields) == 0 {
+		return nil
+	}
+	var result []*pb.QueryField
+	for _, field := range fields {
+		result = append(result, &pb.QueryField{
+			NameExpr:      mapBigQueryExpression(field.NameExpr),
+			ValueExpr:     mapBigQueryExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapBigQueryExpression(field.GeneratedExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CRUD EXTENSIONS (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
+	if len(bulkData) == 0 {
+		return nil
+	}
+	var result []*pb.BulkInsertRow
+	for _, row := range bulkData {
+		result = append(result, &pb.BulkInsertRow{
+			Fields: mapBigQueryFields(row),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// JOIN MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryJoins(joins []models.Join) []*pb.JoinClause {
+	if len(joins) == 0 {
+		return nil
+	}
+	var result []*pb.JoinClause
+	for _, join := range joins {
+		result = append(result, &pb.JoinClause{
+			JoinType:  string(join.Type),
+			Table:     TableName(join.Table, "GET"),
+			LeftExpr:  mapBigQueryExpression(join.LeftExpr),
+			RightExpr: mapBigQueryExpression(join.RightExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// AGGREGATE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapBigQueryExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapBigQueryOrderByClauses(agg.OrderBy),
+	}
+}
+
+// ============================================================================
+// WINDOW FUNCTIONS (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause {
+	if len(windowFuncs) == 0 {
+		return nil
+	}
+	var result []*pb.WindowClause
+	for _, wf := range windowFuncs {
+		result = append(result, &pb.WindowClause{
+			Function:     string(wf.Function),
+			FieldExpr:    mapBigQueryExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapBigQueryExpressions(wf.PartitionBy),
+			OrderBy:      mapBigQueryOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapBigQueryExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CTE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryCTE(cte *models.CTE, tenantID string) (*pb.CTEClause, error) {
+	if cte == nil {
+		return nil, nil
+	}
+	var cteQuery *pb.RelationalQuery
+	if cte.Query != nil {
+		var err error
+		if cteQuery, err = TranslateBigQuery(cte.Query, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		var err error
+		if mainQuery, err = TranslateBigQuery(main, tenantID); err != nil {
+			return nil, err
+		}
+		pointAtCTE(main, mainQuery, cte.Name)
+	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
+	}
+
+	return &pb.CTEClause{
+		CteName:   cte.Name,
+		CteQuery:  cteQuery,
+		Recursive: cte.Recursive,
+		MainQuery: mainQuery,
+	}, nil
+}
+
+// ============================================================================
+// SUBQUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQuerySubquery(subquery *models.Subquery, tenantID string) (*pb.SubqueryClause, error) {
+	if subquery == nil {
+		return nil, nil
+	}
+	var subqueryQuery *pb.RelationalQuery
+	if subquery.Query != nil {
+		var err error
+		if subqueryQuery, err = TranslateBigQuery(subquery.Query, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.SubqueryClause{
+		SubqueryType: subquery.Type,
+		FieldExpr:    mapBigQueryExpression(subquery.FieldExpr),
+		Subquery:     subqueryQuery,
+		Alias:        subquery.Alias,
+	}, nil
+}
+
+// ============================================================================
+// SET OPERATION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQuerySetOperation(setOp *models.SetOperation, tenantID string) (*pb.SetOperationClause, error) {
+	if setOp == nil {
+		return nil, nil
+	}
+	leftQuery, err := TranslateBigQuery(setOp.LeftQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate left query: %w", err)
+	}
+	rightQuery, err := TranslateBigQuery(setOp.RightQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate right query: %w", err)
+	}
+	return &pb.SetOperationClause{
+		OperationType: string(setOp.Type),
+		LeftQuery:     leftQuery,
+		RightQuery:    rightQuery,
+	}, nil
+}
+
+// ============================================================================
+// SELECT COLUMNS MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQuerySelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapBigQueryExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// VIEW QUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapBigQueryViewQuery(viewQuery *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	if viewQuery == nil {
+		return nil, nil
+	}
+	return TranslateBigQuery(viewQuery, tenantID)
+}
+
+// ============================================================================
+// SQL STRING BUILDER
+// ============================================================================
+
+func buildBigQueryString(query *pb.RelationalQuery) (string, error) {
+	operation := strings.ToLower(query.Operation)
+
+	switch operation {
+	case "select":
+		sql, _, err := bqbuilders.BuildSelectSQL(query)
+		return sql, err
+	case "insert":
+		sql, _, err := bqbuilders.BuildInsertSQL(query)
+		return sql, err
+	case "update":
+		sql, _ := bqbuilders.BuildUpdateSQL(query)
+		return sql, nil
+	case "delete":
+		sql, _ := bqbuilders.BuildDeleteSQL(query)
+		return sql, nil
+	case "bulk_insert":
+		sql, _, err := bqbuilders.BuildBulkInsertSQL(query)
+		return sql, err
+	case "create_table":
+		return bqbuilders.BuildCreateTableSQL(query, mapping.TypeMap)
+	case "alter_table":
+		return bqbuilders.BuildAlterTableSQL(query, mapping.TypeMap)
+	case "drop_table":
+		return bqbuilders.BuildDropTableSQL(query)
+	case "truncate_table":
+		return bqbuilders.BuildTruncateTableSQL(query)
+	case "rename_table":
+		return bqbuilders.BuildRenameTableSQL(query)
+	case "create_schema":
+		return bqbuilders.BuildCreateSchemaSQL(query)
+	case "drop_schema":
+		return bqbuilders.BuildDropSchemaSQL(query)
+	case "create_view":
+		return bqbuilders.BuildCreateViewSQL(query)
+	case "create_or_replace_view":
+		return bqbuilders.BuildAlterViewSQL(query)
+	case "drop_view":
+		return bqbuilders.BuildDropViewSQL(query)
+	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
+		sql, _ := bqbuilders.BuildJoinSQL(query)
+		return sql, nil
+	case "count", "sum", "avg", "min", "max", "string_agg":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _ := bqbuilders.BuildWindowSQL(query)
+			return sql, nil
+		}
+		sql, _, err := bqbuilders.BuildAggregateSQL(query)
+		return sql, err
+	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
+		sql, _ := bqbuilders.BuildWindowSQL(query)
+		return sql, nil
+	case "union", "union_all", "intersect", "except":
+		sql, _, err := bqbuilders.BuildSetOperationSQL(query)
+		return sql, err
+	case "with":
+		sql, _, err := bqbuilders.BuildCTESQL(query)
+		return sql, err
+	case "subquery", "exists":
+		sql, _, err := bqbuilders.BuildSubquerySQL(query)
+		return sql, err
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,309 @@
+package translator
+
+import (
+	"strings"
+
+	cqlbuilders "github.com/omniql-engine/omniql/engine/builders/cassandra"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapCassandraExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	return &pb.Expression{
+		Type:           expr.Type,
+		Value:          expr.Value,
+		Left:           mapCassandraExpression(expr.Left),
+		Operator:       expr.Operator,
+		Right:          mapCassandraExpression(expr.Right),
+		FunctionName:   expr.FunctionName,
+		FunctionArgs:   mapCassandraExpressions(expr.FunctionArgs),
+		CaseConditions: mapCassandraCaseConditions(expr.CaseConditions),
+		CaseElse:       mapCassandraExpression(expr.CaseElse),
+	}
+}
+
+func mapCassandraExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapCassandraExpression(expr))
+	}
+	return result
+}
+
+func mapCassandraCaseConditions(conditions []*models.CaseCondition) []*pb.CaseCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.CaseCondition
+	for _, cc := range conditions {
+		result = append(result, &pb.CaseCondition{
+			Condition: mapCassandraCondition(cc.Condition),
+			ThenExpr:  mapCassandraExpression(cc.ThenExpr),
+		})
+	}
+	return result
+}
+
+func mapCassandraCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapCassandraExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapCassandraExpression(cond.ValueExpr),
+		Value2Expr: mapCassandraExpression(cond.Value2Expr),
+		ValuesExpr: mapCassandraExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapCassandraConditions(cond.Nested),
+	}
+}
+
+func mapCassandraConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapCassandraCondition(&cond))
+	}
+	return result
+}
+
+func mapCassandraOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapCassandraExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+// TranslateCassandra converts OQL Query to a Cassandra RelationalQuery in CQL.
+// A CQL query reads one table by key, so what needs more than that is a
+// capability error here: joins, subqueries, set operations, window
+// functions, HAVING and OFFSET. Writes return no rows and take no locks.
+// It uses the default Cassandra options.
+func TranslateCassandra(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	return translateCassandra(query, tenantID, cqlbuilders.Options{})
+}
+
+func translateCassandra(query *models.Query, tenantID string, opts cqlbuilders.Options) (*pb.RelationalQuery, error) {
+	operation := mapping.OperationMap["Cassandra"][query.Operation]
+	if err := checkCassandraQuery(query); err != nil {
+		return nil, err
+	}
+
+	table := TableName(query.Entity, query.Operation)
+
+	result := &pb.RelationalQuery{
+		Operation:  operation,
+		Table:      table,
+		Conditions: mapCassandraConditions(query.Conditions),
+		Fields:     mapCassandraFields(query.Fields),
+		Limit:      int32(query.Limit),
+		Distinct:   query.Distinct,
+
+		// DQL
+		Columns:       mapCassandraExpressions(query.Columns),
+		SelectColumns: mapCassandraSelectColumns(query.SelectColumns),
+		Aggregate:     mapCassandraAggregate(query.Aggregate),
+		OrderBy:       mapCassandraOrderByClauses(query.OrderBy),
+		GroupBy:       mapCassandraExpressions(query.GroupBy),
+		Having:        mapCassandraConditions(query.Having),
+
+		// CRUD Extensions
+		Upsert:      mapCassandraUpsert(query.Upsert),
+		BulkData:    mapCassandraBulkData(query.BulkData),
+		TtlMs:       query.TTL,
+		IfNotExists: query.IfNotExists,
+
+		// DDL
+		AlterAction:  query.AlterAction,
+		Cascade:      query.Cascade,
+		ClusterKeys:  mapCassandraExpressions(query.ClusterKeys),
+		TableOptions: query.TableOptions,
+	}
+
+	sql, err := buildCassandraString(result, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = sql
+	return result, nil
+}
+
+// checkCassandraQuery rejects the parts of a query CQL has no form for
+func checkCassandraQuery(query *models.Query) error {
+	switch {
+	case len(query.Returning) > 0:
+		return &mapping.ErrNotSupported{Database: "Cassandra", Feature: "RETURNING"}
+	case query.Lock != "":
+		return &mapping.ErrNotSupported{Database: "Cassandra", Feature: "FOR " + query.Lock}
+	case query.PartitionStrategy != "":
+		// Rows are partitioned by the partition key, always
+		return &mapping.ErrNotSupported{Database: "Cassandra", Feature: "PARTITION BY " + query.PartitionStrategy}
+	case len(query.Joins) > 0:
+		return mapping.NotSupported("Cassandra", string(query.Joins[0].Type)+" JOIN", mapping.SupportsOperation)
+	case len(query.WindowFunctions) > 0:
+		return &mapping.ErrNotSupported{Database: "Cassandra", Feature: "OVER"}
+	case query.CTE != nil:
+		return mapping.NotSupported("Cassandra", "CTE", mapping.SupportsOperation)
+	case query.Subquery != nil:
+		return mapping.NotSupported("Cassandra", "SUBQUERY", mapping.SupportsOperation)
+	case query.SetOperation != nil:
+		return mapping.NotSupported("Cassandra", strings.ReplaceAll(string(query.SetOperation.Type), "_", " "), mapping.SupportsOperation)
+	case len(query.Having) > 0:
+		return mapping.NotSupported("Cassandra", "HAVING", mapping.SupportsOperation)
+	case query.Offset > 0:
+		return mapping.NotSupported("Cassandra", "OFFSET", mapping.SupportsOperation)
+	}
+	return nil
+}
+
+// ============================================================================
+// FIELD MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapCassandraFields(fields []models.Field) []*pb.QueryField {
+	if len(fields) == 0 {
+		return nil
+	}
+	var result []*pb.QueryField
+	for _, field := range fields {
+		result = append(result, &pb.QueryField{
+			NameExpr:      mapCassandraExpression(field.NameExpr),
+			ValueExpr:     mapCassandraExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapCassandraExpression(field.GeneratedExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CRUD EXTENSIONS (100% TrueAST)
+// ============================================================================
+
+func mapCassandraUpsert(upsert *models.Upsert) *pb.UpsertClause {
+	if upsert == nil {
+		return nil
+	}
+	return &pb.UpsertClause{
+		ConflictFields:     mapCassandraExpressions(upsert.ConflictFields),
+		UpdateFields:       mapCassandraFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
+		ConflictWhere:      mapCassandraConditions(upsert.ConflictWhere),
+	}
+}
+
+func mapCassandraBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
+	if len(bulkData) == 0 {
+		return nil
+	}
+	var result []*pb.BulkInsertRow
+	for _, row := range bulkData {
+		result = append(result, &pb.BulkInsertRow{
+			Fields: mapCassandraFields(row),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// AGGREGATE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapCassandraAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapCassandraExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapCassandraOrderByClauses(agg.OrderBy),
+	}
+}
+
+// ============================================================================
+// SELECT COLUMNS MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapCassandraSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapCassandraExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CQL STRING BUILDER
+// ============================================================================
+
+func buildCassandraString(query *pb.RelationalQuery, opts cqlbuilders.Options) (string, error) {
+	operation := strings.ToLower(query.Operation)
+
+	switch operation {
+	case "select":
+		sql, _, err := cqlbuilders.BuildSelectSQL(query, opts)
+		return sql, err
+	case "insert":
+		sql, _, err := cqlbuilders.BuildInsertSQL(query)
+		return sql, err
+	case "update":
+		sql, _, err := cqlbuilders.BuildUpdateSQL(query, opts)
+		return sql, err
+	case "delete":
+		sql, _, err := cqlbuilders.BuildDeleteSQL(query, opts)
+		return sql, err
+	case "batch":
+		sql, _, err := cqlbuilders.BuildBatchSQL(query)
+		return sql, err
+	case "create_table":
+		return cqlbuilders.BuildCreateTableSQL(query, mapping.TypeMap)
+	case "alter_table":
+		return cqlbuilders.BuildAlterTableSQL(query, mapping.TypeMap)
+	case "drop_table":
+		return cqlbuilders.BuildDropTableSQL(query)
+	case "truncate_table":
+		return cqlbuilders.BuildTruncateTableSQL(query)
+	case "create_index":
+		return cqlbuilders.BuildCreateIndexSQL(query)
+	case "drop_index":
+		return cqlbuilders.BuildDropIndexSQL(query)
+	case "count", "sum", "avg", "min", "max":
+		sql, _, err := cqlbuilders.BuildAggregateSQL(query, opts)
+		return sql, err
+	default:
+		return "", nil
+	}
+}
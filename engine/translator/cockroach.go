@@ -0,0 +1,60 @@
+package translator
+
+import (
+	"fmt"
+
+	crdbbuilders "github.com/omniql-engine/omniql/engine/builders/cockroach"
+	pgbuilders "github.com/omniql-engine/omniql/engine/builders/postgres"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+// TranslateCockroachDB converts OQL Query to CockroachDB RelationalQuery.
+// CockroachDB is translated as PostgreSQL, then the statements it writes
+// differently are rebuilt: UPSERT INTO, AS OF SYSTEM TIME and its column types.
+func TranslateCockroachDB(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	return translateCockroachDB(query, tenantID, pgbuilders.Options{})
+}
+
+func translateCockroachDB(query *models.Query, tenantID string, opts pgbuilders.Options) (*pb.RelationalQuery, error) {
+	operation := mapping.OperationMap["CockroachDB"][query.Operation]
+
+	if query.AsOf != "" && query.Lock != "" {
+		return nil, fmt.Errorf("CockroachDB cannot lock rows (FOR %s) in a historical read", query.Lock)
+	}
+	if query.PartitionStrategy != "" {
+		return nil, &mapping.ErrNotSupported{Database: "CockroachDB", Feature: "PARTITION BY " + query.PartitionStrategy}
+	}
+
+	result, err := translatePostgreSQL(query, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if function := crdbbuilders.FindAdvisoryLock(result); function != "" {
+		return nil, &mapping.ErrNotSupported{Database: "CockroachDB", Feature: "advisory lock " + function}
+	}
+
+	result.Operation = operation
+	result.AsOf = query.AsOf
+	crdbbuilders.MapColumnTypes(result)
+	result.Sql = buildCockroachDBString(result, opts)
+
+	return result, nil
+}
+
+// buildCockroachDBString builds the statements CockroachDB writes its own
+// way and leaves the rest to the PostgreSQL builders
+func buildCockroachDBString(query *pb.RelationalQuery, opts pgbuilders.Options) string {
+	switch query.Operation {
+	case "upsert":
+		sql, _ := crdbbuilders.BuildUpsertSQL(query)
+		return sql
+	default:
+		return buildPostgreSQLString(query, opts)
+	}
+}
@@ -0,0 +1,127 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// DIALECTS (SQL backends registered from other modules)
+// ============================================================================
+
+// Dialect is a SQL database backend shipped outside this module. Once
+// registered, its Name is a database type like "PostgreSQL": Translate
+// builds its queries and oql.WrapSQL runs them through database/sql.
+type Dialect interface {
+	// Name is the database type, e.g. "Db2"
+	Name() string
+	// OperationMap maps OQL operations (mapping.OperationGroups keys) to the
+	// dialect's operation names; operations left out are rejected
+	OperationMap() map[string]string
+	// TypeMap maps universal column types (AUTO, STRING, ...) to native
+	// types; it must cover mapping.CoreTypes
+	TypeMap() map[string]string
+	// NewBuilder returns the builder for one translation
+	NewBuilder() Builder
+}
+
+// Builder writes the native statement for a query of a registered dialect.
+// operation is the query's OperationMap entry and table its table name
+// (see TableName); mapping.TypeMap[dialect] holds the dialect's types.
+type Builder interface {
+	Build(query *models.Query, operation string, table string) (string, error)
+}
+
+// dialects are the registered dialects by name
+var dialects = map[string]Dialect{}
+
+// RegisterDialect makes dialect a database type. It is meant to be called
+// from the dialect package's init function, before any translation.
+func RegisterDialect(dialect Dialect) error {
+	if dialect == nil {
+		return fmt.Errorf("dialect is nil")
+	}
+	name := dialect.Name()
+	if name == "" {
+		return fmt.Errorf("dialect has no name")
+	}
+	if _, builtIn := mapping.OperationMap[name]; builtIn && dialects[name] == nil {
+		return fmt.Errorf("dialect %s is built in and cannot be replaced", name)
+	}
+	if _, exists := dialects[name]; exists {
+		return fmt.Errorf("dialect %s is already registered", name)
+	}
+
+	operations := dialect.OperationMap()
+	if len(operations) == 0 {
+		return fmt.Errorf("dialect %s maps no operations", name)
+	}
+	for op := range operations {
+		if _, ok := mapping.OperationGroups[op]; !ok {
+			return fmt.Errorf("dialect %s maps unknown OQL operation %q", name, op)
+		}
+	}
+
+	types := dialect.TypeMap()
+	if err := mapping.ValidateTypeMap(name, types); err != nil {
+		return err
+	}
+
+	mapping.RegisterDatabase(name, operations, types)
+	dialects[name] = dialect
+	return nil
+}
+
+// IsDialect reports whether dbType is a registered dialect
+func IsDialect(dbType string) bool {
+	_, ok := dialects[dbType]
+	return ok
+}
+
+// translateDialect translates a query for a registered dialect: the operation
+// and table come from the mappings, the statement from the dialect's builder
+func translateDialect(dialect Dialect) func(*models.Query, string) (*pb.RelationalQuery, error) {
+	return func(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+		name := dialect.Name()
+		operation, ok := mapping.OperationMap[name][query.Operation]
+		if !ok {
+			return nil, mapping.NotSupported(name, query.Operation, mapping.SupportsOperation)
+		}
+		table := TableName(query.Entity, query.Operation)
+
+		sql, err := dialect.NewBuilder().Build(query, operation, table)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.RelationalQuery{
+			Operation: operation,
+			Table:     table,
+			Sql:       sql,
+		}, nil
+	}
+}
+
+// TableName is the table an operation on entity reads or writes, by
+// mapping.TableNamingRules: "users" for GET User, "" for operations on no table
+func TableName(entity string, operation string) string {
+	if dot := strings.LastIndex(entity, "."); dot > 0 {
+		name := TableName(entity[dot+1:], operation)
+		if name == "" {
+			return ""
+		}
+		return entity[:dot] + "." + name
+	}
+
+	rule := mapping.TableNamingRules[strings.ToUpper(strings.ReplaceAll(operation, "_", " "))]
+	if rule == "plural" {
+		return mapping.EntityTable(entity)
+	}
+	if rule == "none" {
+		return ""
+	}
+	return strings.ToLower(entity)
+}
@@ -0,0 +1,144 @@
+package translator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/parser"
+	"github.com/omniql-engine/omniql/mapping"
+)
+
+// Each dialect translates its own clauses: ClickHouse FINAL and SAMPLE,
+// Snowflake time travel, BigQuery UNNEST, Cassandra TTL and collections,
+// T-SQL TOP and OUTPUT, Oracle MERGE and Elasticsearch searches
+func TestDialectFeatures(t *testing.T) {
+	tests := []struct {
+		db    string
+		input string
+		want  string
+	}{
+		{"Oracle", `GET User WHERE age > 21 ORDER BY name LIMIT 10 OFFSET 5`,
+			"SELECT * FROM users WHERE age > :1 ORDER BY name ASC OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{"Oracle", `UPSERT User WITH email = "a", name = "b" ON email`,
+			"MERGE INTO users t USING (SELECT :1 AS email, :2 AS name FROM DUAL) s ON (t.email = s.email) WHEN MATCHED THEN UPDATE SET t.name = s.name WHEN NOT MATCHED THEN INSERT (email, name) VALUES (s.email, s.name)"},
+		{"Oracle", `GET User WHERE metadata->>"plan" = "pro"`,
+			"SELECT * FROM users WHERE JSON_VALUE(metadata, '$.plan') = :1"},
+		{"SQLServer", `GET User LIMIT 10`,
+			"SELECT TOP (10) * FROM [users]"},
+		{"SQLServer", `CREATE User WITH name = "John", age = 30 RETURNING id, created_at`,
+			"INSERT INTO [users] ([name], [age]) OUTPUT INSERTED.[id], INSERTED.[created_at] VALUES (@p1, @p2)"},
+		{"SQLServer", `UPSERT User WITH email = "a", name = "b" ON email`,
+			"MERGE INTO [users] WITH (HOLDLOCK) AS t USING (VALUES (@p1, @p2)) AS s ([email], [name]) ON (t.[email] = s.[email]) WHEN MATCHED THEN UPDATE SET [name] = s.[name] WHEN NOT MATCHED THEN INSERT ([email], [name]) VALUES (s.[email], s.[name]);"},
+		{"SQLServer", `RENAME TABLE User TO Customer`,
+			"EXEC sp_rename N'[users]', N'customers'"},
+		{"ClickHouse", `GET Event FINAL WHERE user_id = 42`,
+			"SELECT * FROM `events` FINAL WHERE `user_id` = ?"},
+		{"ClickHouse", `COUNT * FROM Event SAMPLE 0.1 WHERE kind = "click"`,
+			"SELECT COUNT(*) FROM `events` SAMPLE 0.1 WHERE `kind` = ?"},
+		{"ClickHouse", `UPDATE User SET age = 31 WHERE id = 1`,
+			"ALTER TABLE `users` UPDATE `age` = ? WHERE `id` = ?"},
+		{"ClickHouse", `GET Post WHERE "go" = ANY(tags)`,
+			"SELECT * FROM `posts` WHERE has(`tags`, ?)"},
+		{"Snowflake", `GET Order AS OF "-10m" WHERE status = "paid"`,
+			"SELECT * FROM orders AT(OFFSET => -600) WHERE status = ?"},
+		{"Snowflake", `GET Order BEFORE "01b2c3d4-0000-5e6f-0000-000000000001"`,
+			"SELECT * FROM orders BEFORE(STATEMENT => '01b2c3d4-0000-5e6f-0000-000000000001')"},
+		{"Snowflake", `GET User WHERE data->"address"->>"city" = "Paris"`,
+			"SELECT * FROM users WHERE data['address']['city']::STRING = ?"},
+		{"BigQuery", `GET analytics.Event WHERE kind = "signup"`,
+			"SELECT * FROM `analytics`.`events` WHERE `kind` = @p1"},
+		{"BigQuery", `GET Post WHERE UNNEST(tags) LIKE "go%"`,
+			"SELECT * FROM `posts` WHERE EXISTS(SELECT 1 FROM UNNEST(`tags`) AS elem WHERE elem LIKE @p1)"},
+		{"BigQuery", `UPDATE User SET active = false`,
+			"UPDATE `users` SET `active` = @p1 WHERE TRUE"},
+		{"Cassandra", `CREATE User WITH id = "u1", email = "john@example.com" IF NOT EXISTS TTL 30s`,
+			"INSERT INTO users (id, email) VALUES (?, ?) IF NOT EXISTS USING TTL 30"},
+		{"Cassandra", `UPDATE User SET visits = visits + 1 WHERE id = 5`,
+			"UPDATE users SET visits = visits + ? WHERE id = ?"},
+		{"Cassandra", `GET Post WHERE id = 1 AND tags @> ARRAY("go", "sql")`,
+			"SELECT * FROM posts WHERE id = ? AND tags CONTAINS ? AND tags CONTAINS ?"},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := Translate(query, tt.db, "")
+		if err != nil {
+			t.Errorf("%s: Translate(%q): %v", tt.db, tt.input, err)
+			continue
+		}
+		if got := result.GetRelational().GetSql(); got != tt.want {
+			t.Errorf("%s: Translate(%q):\n got %s\nwant %s", tt.db, tt.input, got, tt.want)
+		}
+	}
+}
+
+// SQL Server RANGE frames take only UNBOUNDED and CURRENT ROW bounds, so a
+// numeric offset is not supported; ROWS frames take any bound
+func TestSQLServerWindowFrames(t *testing.T) {
+	tests := []struct {
+		frame string
+		want  string
+	}{
+		{"RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW",
+			"SELECT *, SUM([total]) OVER (ORDER BY [day] ASC RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS [s] FROM [orders]"},
+		{"ROWS BETWEEN 3 PRECEDING AND 2 FOLLOWING",
+			"SELECT *, SUM([total]) OVER (ORDER BY [day] ASC ROWS BETWEEN 3 PRECEDING AND 2 FOLLOWING) AS [s] FROM [orders]"},
+		{"RANGE BETWEEN 3 PRECEDING AND CURRENT ROW", ""},
+		{"RANGE BETWEEN CURRENT ROW AND 1 FOLLOWING", ""},
+	}
+	for _, tt := range tests {
+		input := "SUM total OVER (ORDER BY day " + tt.frame + ") AS s FROM Order"
+		query, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		result, err := Translate(query, "SQLServer", "")
+		if tt.want == "" {
+			var notSupported *mapping.ErrNotSupported
+			if !errors.As(err, &notSupported) {
+				t.Errorf("Translate(%q): expected ErrNotSupported, got %v", input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Translate(%q): %v", input, err)
+			continue
+		}
+		if got := result.GetRelational().GetSql(); got != tt.want {
+			t.Errorf("Translate(%q):\n got %s\nwant %s", input, got, tt.want)
+		}
+	}
+}
+
+// Elasticsearch translates reads to search requests
+func TestElasticsearchSearches(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`GET User WHERE age > 21 ORDER BY name LIMIT 10 OFFSET 5`,
+			`GET /users/_search {"from":5,"query":{"bool":{"filter":[{"range":{"age":{"gt":21}}}]}},"size":10,"sort":[{"name":"asc"}]}`},
+		{`GET User WHERE name ILIKE "john%"`,
+			`GET /users/_search {"query":{"bool":{"filter":[{"wildcard":{"name":{"case_insensitive":true,"value":"john*"}}}]}},"size":10000}`},
+		{`GET Article WHERE body SEARCH "distributed databases" ORDER BY RELEVANCE DESC LIMIT 20`,
+			`GET /articles/_search {"query":{"bool":{"must":[{"match":{"body":{"query":"distributed databases"}}}]}},"size":20,"sort":[{"_score":"desc"}]}`},
+		{`COUNT * FROM Order GROUP BY country ORDER BY country DESC LIMIT 5`,
+			`GET /orders/_search {"aggs":{"country":{"terms":{"field":"country","order":{"_key":"desc"},"size":5}}},"size":0}`},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := Translate(query, "Elasticsearch", "")
+		if err != nil {
+			t.Errorf("Translate(%q): %v", tt.input, err)
+			continue
+		}
+		if got := result.GetDocument().GetQuery(); got != tt.want {
+			t.Errorf("Translate(%q):\n got %s\nwant %s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	esbuilders "github.com/omniql-engine/omniql/engine/builders/elasticsearch"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapElasticsearchExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	// data->'address'->>'city' is the object field data.address.city
+	if expr.Type == "JSON_PATH" {
+		return &pb.Expression{Type: "FIELD", Value: elasticsearchFieldPath(expr)}
+	}
+	return &pb.Expression{
+		Type:         expr.Type,
+		Value:        expr.Value,
+		Left:         mapElasticsearchExpression(expr.Left),
+		Operator:     expr.Operator,
+		Right:        mapElasticsearchExpression(expr.Right),
+		FunctionName: expr.FunctionName,
+		FunctionArgs: mapElasticsearchExpressions(expr.FunctionArgs),
+	}
+}
+
+// elasticsearchFieldPath flattens a JSON_PATH chain into a dotted field name
+func elasticsearchFieldPath(expr *models.Expression) string {
+	if expr.Type != "JSON_PATH" {
+		return expr.Value
+	}
+	path := elasticsearchFieldPath(expr.Left)
+	if expr.Right != nil {
+		path += "." + expr.Right.Value
+	}
+	return path
+}
+
+func mapElasticsearchExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapElasticsearchExpression(expr))
+	}
+	return result
+}
+
+func mapElasticsearchCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapElasticsearchExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapElasticsearchExpression(cond.ValueExpr),
+		Value2Expr: mapElasticsearchExpression(cond.Value2Expr),
+		ValuesExpr: mapElasticsearchExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapElasticsearchConditions(cond.Nested),
+	}
+}
+
+func mapElasticsearchConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapElasticsearchCondition(&cond))
+	}
+	return result
+}
+
+func mapElasticsearchOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapElasticsearchExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+func mapElasticsearchSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapElasticsearchExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+func mapElasticsearchAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapElasticsearchExpression(agg.FieldExpr),
+	}
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+// TranslateElasticsearch converts OQL Query to an Elasticsearch DocumentQuery
+// whose Query is the request in console form: GET /users/_search {...}.
+// Elasticsearch is read through search requests on one index, so only GET
+// and the aggregates translate; what needs another index or a second pass
+// over the hits is a capability error here. It uses the default
+// Elasticsearch options.
+func TranslateElasticsearch(query *models.Query, tenantID string) (*pb.DocumentQuery, error) {
+	return translateElasticsearch(query, tenantID, esbuilders.Options{})
+}
+
+func translateElasticsearch(query *models.Query, tenantID string, opts esbuilders.Options) (*pb.DocumentQuery, error) {
+	operation := mapping.OperationMap["Elasticsearch"][query.Operation]
+	if err := checkElasticsearchQuery(query); err != nil {
+		return nil, err
+	}
+
+	result := &pb.DocumentQuery{
+		Operation:     operation,
+		Collection:    TableName(query.Entity, query.Operation),
+		Conditions:    mapElasticsearchConditions(query.Conditions),
+		Limit:         int32(query.Limit),
+		Skip:          int32(query.Offset),
+		Columns:       mapElasticsearchExpressions(query.Columns),
+		SelectColumns: mapElasticsearchSelectColumns(query.SelectColumns),
+		Aggregate:     mapElasticsearchAggregate(query.Aggregate),
+		OrderBy:       mapElasticsearchOrderByClauses(query.OrderBy),
+		GroupBy:       mapElasticsearchExpressions(query.GroupBy),
+		Having:        mapElasticsearchConditions(query.Having),
+		Distinct:      query.Distinct,
+	}
+
+	request, err := buildElasticsearchString(result, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Query = request
+	return result, nil
+}
+
+// checkElasticsearchQuery rejects the parts of a query a search request has
+// no form for
+func checkElasticsearchQuery(query *models.Query) error {
+	switch {
+	case len(query.Joins) > 0:
+		return mapping.NotSupported("Elasticsearch", string(query.Joins[0].Type)+" JOIN", mapping.SupportsOperation)
+	case len(query.WindowFunctions) > 0:
+		return &mapping.ErrNotSupported{Database: "Elasticsearch", Feature: "OVER"}
+	case query.CTE != nil:
+		return mapping.NotSupported("Elasticsearch", "CTE", mapping.SupportsOperation)
+	case query.Subquery != nil:
+		return mapping.NotSupported("Elasticsearch", "SUBQUERY", mapping.SupportsOperation)
+	case query.SetOperation != nil:
+		return mapping.NotSupported("Elasticsearch", strings.ReplaceAll(string(query.SetOperation.Type), "_", " "), mapping.SupportsOperation)
+	case len(query.Having) > 0:
+		return mapping.NotSupported("Elasticsearch", "HAVING", mapping.SupportsOperation)
+	case query.Lock != "":
+		return &mapping.ErrNotSupported{Database: "Elasticsearch", Feature: "FOR " + query.Lock}
+	}
+	return nil
+}
+
+// ============================================================================
+// REQUEST STRING BUILDER
+// ============================================================================
+
+// buildElasticsearchString writes the request as the Kibana console does:
+// method, path and the JSON body on one line
+func buildElasticsearchString(query *pb.DocumentQuery, opts esbuilders.Options) (string, error) {
+	endpoint, body, err := esbuilders.BuildRequest(query, opts)
+	if err != nil {
+		return "", err
+	}
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("GET /%s/%s %s", query.Collection, endpoint, strings.TrimSpace(data.String())), nil
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/omniql-engine/omniql/engine/models"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 	
-	"github.com/jinzhu/inflection"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -22,6 +21,10 @@ func mapMongoDBExpression(expr *models.Expression) *pb.Expression {
 	if expr == nil {
 		return nil
 	}
+	// metadata->'address'->>'city' is the dotted path metadata.address.city
+	if expr.Type == "JSON_PATH" {
+		return &pb.Expression{Type: "FIELD", Value: mongoDBFieldPath(expr)}
+	}
 	return &pb.Expression{
 		Type:           expr.Type,
 		Value:          expr.Value,
@@ -35,6 +38,18 @@ func mapMongoDBExpression(expr *models.Expression) *pb.Expression {
 	}
 }
 
+// mongoDBFieldPath flattens a JSON_PATH chain into a dot-notation field path
+func mongoDBFieldPath(expr *models.Expression) string {
+	if expr.Type != "JSON_PATH" {
+		return expr.Value
+	}
+	path := mongoDBFieldPath(expr.Left)
+	if expr.Right != nil {
+		path += "." + expr.Right.Value
+	}
+	return path
+}
+
 func mapMongoDBExpressions(exprs []*models.Expression) []*pb.Expression {
 	if len(exprs) == 0 {
 		return nil
@@ -112,7 +127,12 @@ func mapMongoDBOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
 // MAIN TRANSLATOR
 // ============================================================================
 
+// TranslateMongoDB translates query with the default MongoDB options
 func TranslateMongoDB(query *models.Query, tenantID string) (*pb.DocumentQuery, error) {
+	return translateMongoDB(query, tenantID, mongobuilders.Options{})
+}
+
+func translateMongoDB(query *models.Query, tenantID string, opts mongobuilders.Options) (*pb.DocumentQuery, error) {
 	operation := mapping.OperationMap["MongoDB"][query.Operation]
 	collection := getMongoDBCollectionName(query.Entity, query.Operation)
 	conditions := mapMongoDBConditions(query.Conditions)
@@ -148,34 +168,37 @@ func TranslateMongoDB(query *models.Query, tenantID string) (*pb.DocumentQuery,
 	
 	// CRUD extensions
 	upsert := mapMongoDBUpsert(query.Upsert)
+	// updateOne matches on the conflict fields: with none it would match any document
+	if query.Upsert != nil && query.Upsert.DoNothing && len(query.Upsert.ConflictFields) == 0 {
+		return nil, fmt.Errorf("MongoDB DO NOTHING needs conflict fields: UPSERT ... ON field DO NOTHING")
+	}
 	bulkData := mapMongoDBBulkData(query.BulkData)
 	viewName := query.ViewName
-	viewQuery := mapMongoDBViewQuery(query.ViewQuery, tenantID)
+	viewQuery := mapMongoDBViewQuery(query.ViewQuery, tenantID, opts)
 	databaseName := query.DatabaseName
 
-	// SET OPERATIONS
+	// SET OPERATIONS: the pipeline runs on the left query's collection and
+	// brings the right query in with $unionWith
+	var setOperation *pb.DocumentSetOperationClause
 	if query.SetOperation != nil {
+		var err error
+		if setOperation, err = mapMongoDBSetOperation(query.SetOperation, tenantID, opts); err != nil {
+			return nil, err
+		}
 		operation = mapping.OperationMap["MongoDB"][string(query.SetOperation.Type)]
-		collection = getMongoDBCollectionName(query.SetOperation.LeftQuery.Entity, query.SetOperation.LeftQuery.Operation)
-		
-		if query.SetOperation.Type == models.Intersect {
-			leftConditions := mapMongoDBConditions(query.SetOperation.LeftQuery.Conditions)
-			rightConditions := mapMongoDBConditions(query.SetOperation.RightQuery.Conditions)
-			conditions = append(leftConditions, rightConditions...)
-		} else if query.SetOperation.Type == models.Except {
-			leftConditions := mapMongoDBConditions(query.SetOperation.LeftQuery.Conditions)
-			rightConditions := mapMongoDBConditions(query.SetOperation.RightQuery.Conditions)
-			
-			conditions = leftConditions
-			for _, rightCond := range rightConditions {
-				negatedCond := &pb.QueryCondition{
-					FieldExpr: rightCond.FieldExpr,
-					Operator:  negateOperator(rightCond.Operator),
-					ValueExpr: rightCond.ValueExpr,
-				}
-				conditions = append(conditions, negatedCond)
-			}
+		collection = setOperation.LeftQuery.Collection
+	}
+
+	// RECURSIVE CTE: $graphLookup from the anchor documents
+	var graphLookup *pb.GraphLookupClause
+	if query.CTE != nil {
+		anchor, lookup, err := mapMongoDBGraphLookup(query.CTE, tenantID, opts)
+		if err != nil {
+			return nil, err
 		}
+		collection = getMongoDBCollectionName(anchor.Entity, anchor.Operation)
+		conditions = mapMongoDBConditions(anchor.Conditions)
+		graphLookup = lookup
 	}
 	
 	result := &pb.DocumentQuery{
@@ -190,6 +213,7 @@ func TranslateMongoDB(query *models.Query, tenantID string) (*pb.DocumentQuery,
 		Columns:         mapMongoDBExpressions(query.Columns),
 		SelectColumns:   mapMongoDBSelectColumns(query.SelectColumns),
 		Aggregate:       aggregate,
+		Facets:          mapMongoDBFacets(query.Facets),
 		OrderBy:         orderBy,
 		GroupBy:         mapMongoDBExpressions(query.GroupBy),
 		Having:          mapMongoDBConditions(query.Having),
@@ -215,9 +239,39 @@ func TranslateMongoDB(query *models.Query, tenantID string) (*pb.DocumentQuery,
 		ViewQuery:    viewQuery,
 		DatabaseName: databaseName,
 		NewName:      getMongoDBCollectionName(query.NewName, query.Operation),
+		SetOperation: setOperation,
+		GraphLookup:  graphLookup,
+		ArrayFilters: mapMongoDBArrayFilters(fields, conditions),
+		Collation:    mapMongoDBCollation(query),
+		Returning:    mapMongoDBExpressions(query.Returning),
+		TtlMs:        query.TTL,
 	}
 
-	result.Query = buildMongoDBString(result)
+	mongobuilders.ResolveFieldPaths(result)
+
+	if mongobuilders.WritesFromQuery(result) {
+		if _, err := mongobuilders.BuildMongoWriteFromPipeline(result, opts); err != nil {
+			return nil, err
+		}
+	}
+	if result.SetOperation != nil {
+		if _, err := mongobuilders.BuildSetOperationPipeline(result, opts); err != nil {
+			return nil, err
+		}
+	}
+	if strings.ToLower(operation) == "find" {
+		if mongobuilders.IsGroupedSelect(result) {
+			if _, err := mongobuilders.BuildMongoDBGroupedPipeline(result, opts); err != nil {
+				return nil, err
+			}
+		} else if mongobuilders.NeedsSelectPipeline(result) {
+			if _, err := mongobuilders.BuildMongoDBSelectPipeline(result, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Query = buildMongoDBString(result, opts)
 	return result, nil
 }
 
@@ -243,7 +297,7 @@ func mapMongoDBFields(fields []models.Field) []*pb.QueryField {
 func getMongoDBCollectionName(entity string, operation string) string {
 	rule := mapping.TableNamingRules[operation]
 	if rule == "plural" {
-		return inflection.Plural(strings.ToLower(entity))
+		return mapping.EntityTable(entity)
 	}
 	if rule == "none" {
 		return ""
@@ -283,34 +337,95 @@ func convertMongoDBOperator(operator string) string {
 		return "IS_NOT_NULL"
 	case "LIKE":
 		return "$regex"
+	case "SEARCH":
+		return "$text"
+	case "NEAR":
+		return "$near"
+	case "WITHIN":
+		return "$geoWithin"
 	default:
 		return operator
 	}
 }
 
-func negateOperator(operator string) string {
-	switch operator {
-	case "$gt":
-		return "$lte"
-	case "$gte":
-		return "$lt"
-	case "$lt":
-		return "$gte"
-	case "$lte":
-		return "$gt"
-	case "$eq":
-		return "$ne"
-	case "$ne":
-		return "$eq"
-	default:
-		return operator
+// mapMongoDBSetOperation translates both queries of a set operation; either
+// may itself be a set operation
+func mapMongoDBSetOperation(setOp *models.SetOperation, tenantID string, opts mongobuilders.Options) (*pb.DocumentSetOperationClause, error) {
+	if setOp.LeftQuery == nil || setOp.RightQuery == nil {
+		return nil, fmt.Errorf("%s requires two queries", setOp.Type)
+	}
+	left, err := translateMongoDB(setOp.LeftQuery, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+	right, err := translateMongoDB(setOp.RightQuery, tenantID, opts)
+	if err != nil {
+		return nil, err
 	}
+	return &pb.DocumentSetOperationClause{
+		OperationType: string(setOp.Type),
+		LeftQuery:     left,
+		RightQuery:    right,
+	}, nil
 }
 
 // ============================================================================
 // CRUD EXTENSIONS (100% TrueAST)
 // ============================================================================
 
+// mapMongoDBArrayFilters splits the conditions on $[name] elements off an update
+// With SET items.$[i].qty = 0, items.i.sku = 'x' becomes the array filter
+// {i.sku: 'x'} and stays in the filter as items.sku = 'x', so only documents
+// holding such an element are matched. Only top-level AND conditions qualify.
+func mapMongoDBArrayFilters(fields []*pb.QueryField, conditions []*pb.QueryCondition) []*pb.QueryCondition {
+	arrays := map[string]string{} // identifier -> array path
+	for _, field := range fields {
+		if field.NameExpr == nil {
+			continue
+		}
+		path := field.NameExpr.Value
+		for offset := 0; ; {
+			start := strings.Index(path[offset:], ".$[")
+			if start < 0 {
+				break
+			}
+			start += offset
+			end := start + strings.IndexByte(path[start:], ']')
+			if name := path[start+3 : end]; name != "" {
+				arrays[name] = path[:start]
+			}
+			offset = end + 1
+		}
+	}
+	if len(arrays) == 0 {
+		return nil
+	}
+
+	var filters []*pb.QueryCondition
+	for _, cond := range conditions {
+		if cond.FieldExpr == nil || cond.FieldExpr.Type != "FIELD" || strings.ToUpper(cond.Logic) == "OR" {
+			continue
+		}
+		for name, array := range arrays {
+			element := array + "." + name
+			rest, ok := strings.CutPrefix(cond.FieldExpr.Value, element)
+			if !ok || (rest != "" && rest[0] != '.') {
+				continue
+			}
+			filters = append(filters, &pb.QueryCondition{
+				FieldExpr:  &pb.Expression{Type: "FIELD", Value: name + rest},
+				Operator:   cond.Operator,
+				ValueExpr:  cond.ValueExpr,
+				Value2Expr: cond.Value2Expr,
+				ValuesExpr: cond.ValuesExpr,
+			})
+			cond.FieldExpr = &pb.Expression{Type: "FIELD", Value: array + rest}
+			break
+		}
+	}
+	return filters
+}
+
 func mapMongoDBUpsert(upsert *models.Upsert) *pb.UpsertClause {
 	if upsert == nil {
 		return nil
@@ -318,7 +433,7 @@ func mapMongoDBUpsert(upsert *models.Upsert) *pb.UpsertClause {
 	return &pb.UpsertClause{
 		ConflictFields: mapMongoDBExpressions(upsert.ConflictFields),
 		UpdateFields:   mapMongoDBFields(upsert.UpdateFields),
-		ConflictAction: "UPSERT",
+		ConflictAction: conflictAction(upsert, "UPSERT"),
 	}
 }
 
@@ -351,7 +466,7 @@ func mapMongoDBJoins(joins []models.Join) []*pb.JoinClause {
 		}
 		result = append(result, &pb.JoinClause{
 			JoinType:  joinType,
-			Table:     strings.ToLower(join.Table) + "s",
+			Table:     mapping.EntityTable(join.Table),
 			LeftExpr:  mapMongoDBExpression(join.LeftExpr),
 			RightExpr: mapMongoDBExpression(join.RightExpr),
 		})
@@ -370,9 +485,26 @@ func mapMongoDBAggregate(agg *models.Aggregation) *pb.AggregateClause {
 	return &pb.AggregateClause{
 		Function:  convertMongoDBAggregateFunction(string(agg.Function)),
 		FieldExpr: mapMongoDBExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapMongoDBOrderByClauses(agg.OrderBy),
 	}
 }
 
+func mapMongoDBFacets(facets []models.Facet) []*pb.FacetClause {
+	if len(facets) == 0 {
+		return nil
+	}
+	var result []*pb.FacetClause
+	for _, facet := range facets {
+		result = append(result, &pb.FacetClause{
+			Name:      facet.Name,
+			Aggregate: mapMongoDBAggregate(facet.Aggregate),
+			GroupBy:   mapMongoDBExpressions(facet.GroupBy),
+		})
+	}
+	return result
+}
+
 func convertMongoDBAggregateFunction(function string) string {
 	switch strings.ToUpper(function) {
 	case "COUNT":
@@ -385,11 +517,81 @@ func convertMongoDBAggregateFunction(function string) string {
 		return "min"
 	case "MAX":
 		return "max"
+	case "STRING AGG":
+		return "string_agg"
 	default:
 		return strings.ToLower(function)
 	}
 }
 
+// ============================================================================
+// RECURSIVE CTE MAPPING (100% TrueAST)
+// ============================================================================
+
+// mapMongoDBGraphLookup maps a recursive CTE onto $graphLookup
+// The body must be a UNION ALL of an anchor query and a join of one collection
+// with the CTE. ON parent_id = id walks from each document's id to the
+// documents whose parent_id matches it, as the recursive member does on SQL.
+func mapMongoDBGraphLookup(cte *models.CTE, tenantID string, opts mongobuilders.Options) (*models.Query, *pb.GraphLookupClause, error) {
+	if !cte.Recursive {
+		return nil, nil, fmt.Errorf("MongoDB supports only recursive CTEs (as $graphLookup)")
+	}
+	shapeErr := fmt.Errorf("recursive CTE %s must be a UNION ALL of an anchor query and a join on %s", cte.Name, cte.Name)
+	body := cte.Query
+	if body == nil || body.SetOperation == nil || !strings.HasPrefix(string(body.SetOperation.Type), "UNION") {
+		return nil, nil, shapeErr
+	}
+	anchor, member := body.SetOperation.LeftQuery, body.SetOperation.RightQuery
+	if anchor == nil || member == nil || len(member.Joins) != 1 || !strings.EqualFold(member.Joins[0].Table, cte.Name) {
+		return nil, nil, shapeErr
+	}
+
+	from := getMongoDBCollectionName(member.Entity, "GET")
+	cteField, memberField := graphLookupFields(member.Joins[0], cte.Name, member.Entity, from)
+	if cteField == "" || memberField == "" {
+		return nil, nil, shapeErr
+	}
+
+	lookup := &pb.GraphLookupClause{
+		CteName:          cte.Name,
+		From:             from,
+		StartWith:        cteField,
+		ConnectFromField: cteField,
+		ConnectToField:   memberField,
+		MaxDepth:         int32(cte.MaxDepth),
+	}
+	if cte.MainQuery != nil {
+		lookup.MainQuery, _ = translateMongoDB(cte.MainQuery, tenantID, opts)
+	}
+	return anchor, lookup, nil
+}
+
+// graphLookupFields returns the CTE-side and member-side fields of the recursive join
+// A side qualified with the CTE name belongs to the CTE; unqualified, the left
+// side is the member's (INNER JOIN Category tree ON parent_id = id).
+func graphLookupFields(join models.Join, cteName string, memberNames ...string) (string, string) {
+	if join.LeftExpr == nil || join.RightExpr == nil {
+		return "", ""
+	}
+	memberField, leftIsCTE := unqualifyField(join.LeftExpr.Value, cteName)
+	cteField, rightIsCTE := unqualifyField(join.RightExpr.Value, cteName)
+	if leftIsCTE && !rightIsCTE {
+		memberField, cteField = cteField, memberField
+	}
+	for _, name := range memberNames {
+		memberField, _ = unqualifyField(memberField, name)
+	}
+	return cteField, memberField
+}
+
+// unqualifyField strips a leading qualifier naming table, reporting whether it did
+func unqualifyField(field, table string) (string, bool) {
+	if prefix, rest, ok := strings.Cut(field, "."); ok && strings.EqualFold(prefix, table) {
+		return rest, true
+	}
+	return field, false
+}
+
 // ============================================================================
 // WINDOW FUNCTIONS (100% TrueAST)
 // ============================================================================
@@ -405,18 +607,38 @@ func mapMongoDBWindowFunctions(windowFuncs []models.WindowFunction) []*pb.Window
 			alias = strings.ToLower(string(wf.Function)) + "_result"
 		}
 		result = append(result, &pb.WindowClause{
-			Function:    convertMongoDBWindowFunction(string(wf.Function)),
-			FieldExpr:   mapMongoDBExpression(wf.FieldExpr),
-			Alias:       alias,
-			PartitionBy: mapMongoDBExpressions(wf.PartitionBy),
-			OrderBy:     mapMongoDBOrderByClauses(wf.OrderBy),
-			Offset:      int32(wf.Offset),
-			Buckets:     int32(wf.Buckets),
+			Function:     convertMongoDBWindowFunction(string(wf.Function)),
+			FieldExpr:    mapMongoDBExpression(wf.FieldExpr),
+			Alias:        alias,
+			PartitionBy:  mapMongoDBExpressions(wf.PartitionBy),
+			OrderBy:      mapMongoDBOrderByClauses(wf.OrderBy),
+			Offset:       mongoDBShiftOffset(wf),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapMongoDBExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
 		})
 	}
 	return result
 }
 
+// mongoDBShiftOffset returns the $shift of LAG and LEAD: LAG looks back
+// (negative), LEAD ahead, one row unless an offset is given
+func mongoDBShiftOffset(wf models.WindowFunction) int32 {
+	offset := int32(wf.Offset)
+	if offset == 0 {
+		offset = 1
+	}
+	switch wf.Function {
+	case "LAG":
+		return -offset
+	case "LEAD":
+		return offset
+	}
+	return int32(wf.Offset)
+}
+
 func convertMongoDBWindowFunction(function string) string {
 	switch function {
 	case "ROW NUMBER", "ROW_NUMBER":
@@ -452,15 +674,29 @@ func mapMongoDBSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColum
 	return result
 }
 
+// ============================================================================
+// COLLATION MAPPING
+// ============================================================================
+
+func mapMongoDBCollation(query *models.Query) *pb.CollationClause {
+	if query.Collation == "" {
+		return nil
+	}
+	return &pb.CollationClause{
+		Locale:   query.Collation,
+		Strength: int32(query.CollationStrength),
+	}
+}
+
 // ============================================================================
 // VIEW QUERY MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapMongoDBViewQuery(viewQuery *models.Query, tenantID string) *pb.DocumentQuery {
+func mapMongoDBViewQuery(viewQuery *models.Query, tenantID string, opts mongobuilders.Options) *pb.DocumentQuery {
 	if viewQuery == nil {
 		return nil
 	}
-	result, _ := TranslateMongoDB(viewQuery, tenantID)
+	result, _ := translateMongoDB(viewQuery, tenantID, opts)
 	return result
 }
 
@@ -468,12 +704,51 @@ func mapMongoDBViewQuery(viewQuery *models.Query, tenantID string) *pb.DocumentQ
 // QUERY STRING BUILDER
 // ============================================================================
 
-func buildMongoDBString(query *pb.DocumentQuery) string {
+// addMongoDBCollation adds the COLLATE clause to a find, update, delete,
+// distinct or aggregate command
+func addMongoDBCollation(cmd bson.M, query *pb.DocumentQuery) {
+	if collation := mongobuilders.BuildMongoCollationDocument(query.Collation); collation != nil {
+		cmd["collation"] = collation
+	}
+}
+
+// addMongoDBReturning limits a findAndModify to the RETURNING fields
+func addMongoDBReturning(cmd bson.M, query *pb.DocumentQuery) {
+	if projection := mongobuilders.BuildMongoReturningProjection(query.Returning); projection != nil {
+		cmd["fields"] = projection
+	}
+}
+
+func buildMongoDBString(query *pb.DocumentQuery, opts mongobuilders.Options) string {
 	operation := strings.ToLower(query.Operation)
+
+	// CREATE TABLE ... AS GET and CREATE / REPLACE / UPSERT ... FROM GET run
+	// the GET on its collection, ending in $out or $merge
+	if mongobuilders.WritesFromQuery(query) {
+		pipeline, _ := mongobuilders.BuildMongoWriteFromPipeline(query, opts)
+		cmd := bson.M{"aggregate": query.ViewQuery.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query.ViewQuery)
+		jsonBytes, _ := json.Marshal(cmd)
+		return string(jsonBytes)
+	}
 	
 	switch operation {
 	case "find":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
+		// GET ... GROUP BY groups in an aggregate pipeline, and computed or
+		// aliased columns need one: a find projection can only keep fields
+		if mongobuilders.IsGroupedSelect(query) || mongobuilders.NeedsSelectPipeline(query) {
+			var pipeline []bson.M
+			if mongobuilders.IsGroupedSelect(query) {
+				pipeline, _ = mongobuilders.BuildMongoDBGroupedPipeline(query, opts)
+			} else {
+				pipeline, _ = mongobuilders.BuildMongoDBSelectPipeline(query, opts)
+			}
+			cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+			addMongoDBCollation(cmd, query)
+			jsonBytes, _ := json.Marshal(cmd)
+			return string(jsonBytes)
+		}
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
 		cmd := bson.M{"find": query.Collection, "filter": filter}
 		
 		if query.Limit > 0 {
@@ -490,43 +765,74 @@ func buildMongoDBString(query *pb.DocumentQuery) string {
 				cmd["sort"] = sortStage
 			}
 		}
+		if projection := mongobuilders.BuildMongoFindProjection(query); projection != nil {
+			cmd["projection"] = projection
+		}
+		addMongoDBCollation(cmd, query)
     
     jsonBytes, _ := json.Marshal(cmd)
     return string(jsonBytes)
 		
 	case "insertone":
-		doc := mongobuilders.BuildMongoDocument(query.Fields)
+		doc := mongobuilders.BuildMongoInsertDocument(query, opts)
 		jsonBytes, _ := json.Marshal(bson.M{"insertOne": query.Collection, "document": doc})
 		return string(jsonBytes)
 		
 	case "updateone":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
-		update := mongobuilders.BuildMongoSimpleUpdate(query.Fields)
-		jsonBytes, _ := json.Marshal(bson.M{"updateOne": query.Collection, "filter": filter, "update": update})
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
+		update := mongobuilders.BuildMongoSimpleUpdate(query.Fields, opts)
+		if query.Upsert != nil {
+			filter, update = mongobuilders.BuildMongoUpsert(query, opts)
+		}
+		cmd := bson.M{"updateOne": query.Collection, "filter": filter, "update": update}
+		// RETURNING reads the updated document back, as SQL does
+		if len(query.Returning) > 0 {
+			cmd = bson.M{"findAndModify": query.Collection, "query": filter, "update": update, "new": true}
+			addMongoDBReturning(cmd, query)
+		}
+		if query.Upsert != nil {
+			cmd["upsert"] = true
+		}
+		if len(query.ArrayFilters) > 0 {
+			cmd["arrayFilters"] = mongobuilders.BuildMongoArrayFilters(query.ArrayFilters, opts)
+		}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "deleteone":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
-		jsonBytes, _ := json.Marshal(bson.M{"deleteOne": query.Collection, "filter": filter})
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
+		cmd := bson.M{"deleteOne": query.Collection, "filter": filter}
+		// RETURNING reads the deleted document back, as SQL does
+		if len(query.Returning) > 0 {
+			cmd = bson.M{"findAndModify": query.Collection, "query": filter, "remove": true}
+			addMongoDBReturning(cmd, query)
+		}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "insertmany":
 		docs := []bson.M{}
 		for _, row := range query.BulkData {
-			doc := mongobuilders.BuildMongoDocument(row.Fields)
+			doc := mongobuilders.BuildMongoDocument(row.Fields, opts)
 			docs = append(docs, doc)
 		}
 		jsonBytes, _ := json.Marshal(bson.M{"insertMany": query.Collection, "documents": docs})
 		return string(jsonBytes)
 		
 	case "replaceone":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
-		doc := mongobuilders.BuildMongoDocument(query.Fields)
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
+		doc := mongobuilders.BuildMongoDocument(query.Fields, opts)
 		jsonBytes, _ := json.Marshal(bson.M{"replaceOne": query.Collection, "filter": filter, "replacement": doc})
 		return string(jsonBytes)
 		
 	case "createcollection":
-		jsonBytes, _ := json.Marshal(bson.M{"create": query.Collection})
+		cmd := bson.M{"create": query.Collection}
+		if validator := mongobuilders.BuildMongoJSONSchema(query.Fields); validator != nil {
+			cmd["validator"] = validator
+		}
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "dropcollection":
@@ -538,12 +844,20 @@ func buildMongoDBString(query *pb.DocumentQuery) string {
 		return string(jsonBytes)
 		
 	case "deletemany":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
-		jsonBytes, _ := json.Marshal(bson.M{"deleteMany": query.Collection, "filter": filter})
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
+		cmd := bson.M{"deleteMany": query.Collection, "filter": filter}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "create_index":
-		jsonBytes, _ := json.Marshal(bson.M{"createIndexes": query.Collection})
+		model, err := mongobuilders.BuildMongoIndexModel(query, opts)
+		if err != nil {
+			jsonBytes, _ := json.Marshal(bson.M{"createIndexes": query.Collection})
+			return string(jsonBytes)
+		}
+		// Extended JSON keeps the order of the index key's fields
+		jsonBytes, _ := bson.MarshalExtJSON(mongobuilders.BuildCreateIndexCommand(query.Collection, model), false, false)
 		return string(jsonBytes)
 		
 	case "drop_index":
@@ -571,38 +885,65 @@ func buildMongoDBString(query *pb.DocumentQuery) string {
 		return string(jsonBytes)
 		
 	case "lookup":
-		pipeline := mongobuilders.BuildMongoDBJoinPipeline(query)
-		jsonBytes, _ := json.Marshal(bson.M{"aggregate": query.Collection, "pipeline": pipeline})
+		pipeline := mongobuilders.BuildMongoDBJoinPipeline(query, opts)
+		cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
-	case "count", "sum", "avg", "min", "max":
-		pipeline := mongobuilders.BuildMongoDBAggregatePipeline(query)
-		jsonBytes, _ := json.Marshal(bson.M{"aggregate": query.Collection, "pipeline": pipeline})
+	case "count", "sum", "avg", "min", "max", "string_agg":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			pipeline, _ := mongobuilders.BuildWindowFunctionPipeline(query, opts)
+			cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+			addMongoDBCollation(cmd, query)
+			jsonBytes, _ := json.Marshal(cmd)
+			return string(jsonBytes)
+		}
+		pipeline := mongobuilders.BuildMongoDBAggregatePipeline(query, opts)
+		cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "row_number", "rank", "dense_rank", "shift", "ntile":
-		pipeline, _ := mongobuilders.BuildWindowFunctionPipeline(query)
-		jsonBytes, _ := json.Marshal(bson.M{"aggregate": query.Collection, "pipeline": pipeline})
+		pipeline, _ := mongobuilders.BuildWindowFunctionPipeline(query, opts)
+		cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "unionwith", "intersect", "setdifference":
-		pipeline, _ := mongobuilders.BuildSetOperationPipeline(query)
-		jsonBytes, _ := json.Marshal(bson.M{"aggregate": query.Collection, "pipeline": pipeline})
+		pipeline, _ := mongobuilders.BuildSetOperationPipeline(query, opts)
+		cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
+		return string(jsonBytes)
+		
+	case "graphlookup":
+		pipeline := mongobuilders.BuildGraphLookupPipeline(query, opts)
+		cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "group":
-		pipeline := mongobuilders.BuildMongoDBAggregatePipeline(query)
-		jsonBytes, _ := json.Marshal(bson.M{"aggregate": query.Collection, "pipeline": pipeline})
+		pipeline := mongobuilders.BuildMongoDBAggregatePipeline(query, opts)
+		cmd := bson.M{"aggregate": query.Collection, "pipeline": pipeline}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "sort":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
 		sort := mongobuilders.BuildMongoDBSortStage(query.OrderBy)
-		jsonBytes, _ := json.Marshal(bson.M{"find": query.Collection, "filter": filter, "sort": sort})
+		cmd := bson.M{"find": query.Collection, "filter": filter, "sort": sort}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "match":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
 		jsonBytes, _ := json.Marshal(bson.M{"$match": filter})
 		return string(jsonBytes)
 		
@@ -611,8 +952,10 @@ func buildMongoDBString(query *pb.DocumentQuery) string {
 		if len(query.Columns) > 0 {
 			field = query.Columns[0].Value
 		}
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
-		jsonBytes, _ := json.Marshal(bson.M{"distinct": query.Collection, "key": field, "query": filter})
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
+		cmd := bson.M{"distinct": query.Collection, "key": field, "query": filter}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "limit":
@@ -622,8 +965,10 @@ func buildMongoDBString(query *pb.DocumentQuery) string {
 		return fmt.Sprintf(`{"skip": %d}`, query.Skip)
 		
 	case "regex":
-		filter := mongobuilders.BuildMongoFilter(query.Conditions)
-		jsonBytes, _ := json.Marshal(bson.M{"find": query.Collection, "filter": filter})
+		filter := mongobuilders.BuildMongoFilter(query.Conditions, opts)
+		cmd := bson.M{"find": query.Collection, "filter": filter}
+		addMongoDBCollation(cmd, query)
+		jsonBytes, _ := json.Marshal(cmd)
 		return string(jsonBytes)
 		
 	case "cond":
@@ -0,0 +1,51 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/parser"
+)
+
+// OQL translates to MongoDB commands: filters, updates and the geo and text
+// operators
+func TestMongoDBCommands(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`GET User WHERE age > 21 AND status IN ("a", "b") ORDER BY name DESC LIMIT 10 OFFSET 5`,
+			`{"filter":{"age":{"$gt":21},"status":{"$in":["a","b"]}},"find":"users","limit":10,"skip":5,"sort":{"name":-1}}`},
+		{`CREATE User WITH name = "Alice", age = 30`,
+			`{"document":{"age":30,"name":"Alice"},"insertOne":"users"}`},
+		{`UPDATE User SET balance = balance + 100 WHERE id = 1`,
+			`{"filter":{"id":1},"update":{"$inc":{"balance":100}},"updateOne":"users"}`},
+		{`UPSERT User WITH email = "a", name = "b" ON email`,
+			`{"filter":{"email":"a"},"update":{"$set":{"name":"b"}},"updateOne":"users","upsert":true}`},
+		{`UPSERT User WITH email = "a", name = "b" ON email DO NOTHING`,
+			`{"filter":{"email":"a"},"update":{"$setOnInsert":{"email":"a","name":"b"}},"updateOne":"users","upsert":true}`},
+		{`GET User COLLATE "en"`,
+			`{"collation":{"locale":"en"},"filter":{},"find":"users"}`},
+		{`GET Order ORDER BY id AFTER 120 LIMIT 50`,
+			`{"filter":{"id":{"$gt":120}},"find":"orders","limit":50,"sort":{"id":1}}`},
+		{`GET Post WHERE body SEARCH "postgres tips" ORDER BY RELEVANCE DESC`,
+			`{"filter":{"$text":{"$search":"postgres tips"}},"find":"posts","projection":{"score":{"$meta":"textScore"}},"sort":{"score":{"$meta":"textScore"}}}`},
+		{`GET Store WHERE location NEAR POINT(-73.97, 40.77) DISTANCE 5000`,
+			`{"filter":{"location":{"$near":{"$geometry":{"coordinates":[-73.97,40.77],"type":"Point"},"$maxDistance":5000}}},"find":"stores"}`},
+		{`GET Store WHERE location WITHIN POLYGON(POINT(0, 0), POINT(0, 10), POINT(10, 10))`,
+			`{"filter":{"location":{"$geoWithin":{"$geometry":{"coordinates":[[[0,0],[0,10],[10,10],[0,0]]],"type":"Polygon"}}}},"find":"stores"}`},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := Translate(query, "MongoDB", "")
+		if err != nil {
+			t.Errorf("Translate(%q): %v", tt.input, err)
+			continue
+		}
+		if got := result.GetDocument().GetQuery(); got != tt.want {
+			t.Errorf("Translate(%q):\n got %s\nwant %s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,526 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	mssqlbuilders "github.com/omniql-engine/omniql/engine/builders/mssql"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	return &pb.Expression{
+		Type:           expr.Type,
+		Value:          expr.Value,
+		Left:           mapSQLServerExpression(expr.Left),
+		Operator:       expr.Operator,
+		Right:          mapSQLServerExpression(expr.Right),
+		FunctionName:   expr.FunctionName,
+		FunctionArgs:   mapSQLServerExpressions(expr.FunctionArgs),
+		CaseConditions: mapSQLServerCaseConditions(expr.CaseConditions),
+		CaseElse:       mapSQLServerExpression(expr.CaseElse),
+	}
+}
+
+func mapSQLServerExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapSQLServerExpression(expr))
+	}
+	return result
+}
+
+func mapSQLServerCaseConditions(conditions []*models.CaseCondition) []*pb.CaseCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.CaseCondition
+	for _, cc := range conditions {
+		result = append(result, &pb.CaseCondition{
+			Condition: mapSQLServerCondition(cc.Condition),
+			ThenExpr:  mapSQLServerExpression(cc.ThenExpr),
+		})
+	}
+	return result
+}
+
+func mapSQLServerCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapSQLServerExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapSQLServerExpression(cond.ValueExpr),
+		Value2Expr: mapSQLServerExpression(cond.Value2Expr),
+		ValuesExpr: mapSQLServerExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapSQLServerConditions(cond.Nested),
+	}
+}
+
+func mapSQLServerConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapSQLServerCondition(&cond))
+	}
+	return result
+}
+
+func mapSQLServerOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapSQLServerExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+func TranslateSQLServer(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	operation := mapping.OperationMap["SQLServer"][query.Operation]
+	table := TableName(query.Entity, query.Operation)
+	conditions := mapSQLServerConditions(query.Conditions)
+	fields := mapSQLServerFields(query.Fields)
+
+	// DQL: Map fields
+	joins := mapSQLServerJoins(query.Joins)
+	aggregate := mapSQLServerAggregate(query.Aggregate)
+	orderBy := mapSQLServerOrderByClauses(query.OrderBy)
+	having := mapSQLServerConditions(query.Having)
+
+	// DQL: Advanced fields
+	windowFunctions := mapSQLServerWindowFunctions(query.WindowFunctions)
+	cte, err := mapSQLServerCTE(query.CTE, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	subquery, err := mapSQLServerSubquery(query.Subquery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	setOperation, err := mapSQLServerSetOperation(query.SetOperation, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// TCL (no DCL: logins and users are managed by the DBA)
+	var savepointName, isolationLevel string
+	var readOnly bool
+	if query.Transaction != nil {
+		savepointName = query.Transaction.SavepointName
+		isolationLevel = query.Transaction.IsolationLevel
+		readOnly = query.Transaction.ReadOnly
+	}
+
+	// DDL
+	viewQuery, err := mapSQLServerViewQuery(query.ViewQuery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	newName := query.NewName
+	if query.NewName != "" && query.Operation == "RENAME TABLE" {
+		newName = TableName(query.NewName, query.Operation)
+	}
+
+	result := &pb.RelationalQuery{
+		Operation:  operation,
+		Table:      table,
+		Conditions: conditions,
+		Fields:     fields,
+		Limit:      int32(query.Limit),
+		Offset:     int32(query.Offset),
+		Distinct:   query.Distinct,
+		Lock:       query.Lock,
+		LockWait:   query.LockWait,
+
+		// DQL
+		Joins:           joins,
+		Columns:         mapSQLServerExpressions(query.Columns),
+		SelectColumns:   mapSQLServerSelectColumns(query.SelectColumns),
+		Aggregate:       aggregate,
+		OrderBy:         orderBy,
+		GroupBy:         mapSQLServerExpressions(query.GroupBy),
+		Having:          having,
+		WindowFunctions: windowFunctions,
+		Cte:             cte,
+		Subquery:        subquery,
+		Pattern:         query.Pattern,
+		SetOperation:    setOperation,
+
+		// TCL
+		SavepointName:  savepointName,
+		IsolationLevel: isolationLevel,
+		ReadOnly:       readOnly,
+
+		// CRUD Extensions
+		Upsert:   mapSQLServerUpsert(query.Upsert),
+		BulkData: mapSQLServerBulkData(query.BulkData),
+
+		// DDL
+		ViewName:    query.ViewName,
+		ViewQuery:   viewQuery,
+		NewName:     newName,
+		AlterAction: query.AlterAction,
+		Cascade:     query.Cascade,
+
+		// RETURNING is written as OUTPUT INSERTED.col / DELETED.col
+		Returning:    mapSQLServerExpressions(query.Returning),
+		DatabaseName: query.DatabaseName,
+	}
+
+	sql, err := buildSQLServerString(result)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = mssqlbuilders.NumberParams(sql)
+	return result, nil
+}
+
+// ============================================================================
+// FIELD MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerFields(fields []models.Field) []*pb.QueryField {
+	if len(fields) == 0 {
+		return nil
+	}
+	var result []*pb.QueryField
+	for _, field := range fields {
+		result = append(result, &pb.QueryField{
+			NameExpr:      mapSQLServerExpression(field.NameExpr),
+			ValueExpr:     mapSQLServerExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapSQLServerExpression(field.GeneratedExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CRUD EXTENSIONS (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerUpsert(upsert *models.Upsert) *pb.UpsertClause {
+	if upsert == nil {
+		return nil
+	}
+	return &pb.UpsertClause{
+		ConflictFields:     mapSQLServerExpressions(upsert.ConflictFields),
+		UpdateFields:       mapSQLServerFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
+		ConflictWhere:      mapSQLServerConditions(upsert.ConflictWhere),
+	}
+}
+
+func mapSQLServerBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
+	if len(bulkData) == 0 {
+		return nil
+	}
+	var result []*pb.BulkInsertRow
+	for _, row := range bulkData {
+		result = append(result, &pb.BulkInsertRow{
+			Fields: mapSQLServerFields(row),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// JOIN MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerJoins(joins []models.Join) []*pb.JoinClause {
+	if len(joins) == 0 {
+		return nil
+	}
+	var result []*pb.JoinClause
+	for _, join := range joins {
+		result = append(result, &pb.JoinClause{
+			JoinType:  string(join.Type),
+			Table:     TableName(join.Table, "GET"),
+			LeftExpr:  mapSQLServerExpression(join.LeftExpr),
+			RightExpr: mapSQLServerExpression(join.RightExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// AGGREGATE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapSQLServerExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapSQLServerOrderByClauses(agg.OrderBy),
+	}
+}
+
+// ============================================================================
+// WINDOW FUNCTIONS (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause {
+	if len(windowFuncs) == 0 {
+		return nil
+	}
+	var result []*pb.WindowClause
+	for _, wf := range windowFuncs {
+		result = append(result, &pb.WindowClause{
+			Function:     string(wf.Function),
+			FieldExpr:    mapSQLServerExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapSQLServerExpressions(wf.PartitionBy),
+			OrderBy:      mapSQLServerOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapSQLServerExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CTE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerCTE(cte *models.CTE, tenantID string) (*pb.CTEClause, error) {
+	if cte == nil {
+		return nil, nil
+	}
+	var cteQuery *pb.RelationalQuery
+	if cte.Query != nil {
+		var err error
+		if cteQuery, err = TranslateSQLServer(cte.Query, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		var err error
+		if mainQuery, err = TranslateSQLServer(main, tenantID); err != nil {
+			return nil, err
+		}
+		pointAtCTE(main, mainQuery, cte.Name)
+	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
+	}
+
+	return &pb.CTEClause{
+		CteName:   cte.Name,
+		CteQuery:  cteQuery,
+		Recursive: cte.Recursive,
+		MainQuery: mainQuery,
+	}, nil
+}
+
+// ============================================================================
+// SUBQUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerSubquery(subquery *models.Subquery, tenantID string) (*pb.SubqueryClause, error) {
+	if subquery == nil {
+		return nil, nil
+	}
+	var subqueryQuery *pb.RelationalQuery
+	if subquery.Query != nil {
+		var err error
+		if subqueryQuery, err = TranslateSQLServer(subquery.Query, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.SubqueryClause{
+		SubqueryType: subquery.Type,
+		FieldExpr:    mapSQLServerExpression(subquery.FieldExpr),
+		Subquery:     subqueryQuery,
+		Alias:        subquery.Alias,
+	}, nil
+}
+
+// ============================================================================
+// SET OPERATION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerSetOperation(setOp *models.SetOperation, tenantID string) (*pb.SetOperationClause, error) {
+	if setOp == nil {
+		return nil, nil
+	}
+	leftQuery, err := TranslateSQLServer(setOp.LeftQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate left query: %w", err)
+	}
+	rightQuery, err := TranslateSQLServer(setOp.RightQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate right query: %w", err)
+	}
+	return &pb.SetOperationClause{
+		OperationType: string(setOp.Type),
+		LeftQuery:     leftQuery,
+		RightQuery:    rightQuery,
+	}, nil
+}
+
+// ============================================================================
+// SELECT COLUMNS MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapSQLServerExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// VIEW QUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLServerViewQuery(viewQuery *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	if viewQuery == nil {
+		return nil, nil
+	}
+	return TranslateSQLServer(viewQuery, tenantID)
+}
+
+// ============================================================================
+// SQL STRING BUILDER
+// ============================================================================
+
+func buildSQLServerString(query *pb.RelationalQuery) (string, error) {
+	operation := strings.ToLower(query.Operation)
+
+	switch operation {
+	case "select":
+		sql, _, err := mssqlbuilders.BuildSelectSQL(query)
+		return sql, err
+	case "insert":
+		sql, _, err := mssqlbuilders.BuildInsertSQL(query)
+		return sql, err
+	case "update":
+		sql, _ := mssqlbuilders.BuildUpdateSQL(query)
+		return sql, nil
+	case "delete":
+		sql, _ := mssqlbuilders.BuildDeleteSQL(query)
+		return sql, nil
+	case "merge":
+		sql, _, err := mssqlbuilders.BuildUpsertSQL(query)
+		return sql, err
+	case "bulk_insert":
+		sql, _, err := mssqlbuilders.BuildBulkInsertSQL(query)
+		return sql, err
+	case "bulk_merge":
+		sql, _, err := mssqlbuilders.BuildBulkUpsertSQL(query)
+		return sql, err
+	case "create_table":
+		return mssqlbuilders.BuildCreateTableSQL(query, mapping.TypeMap)
+	case "alter_table":
+		return mssqlbuilders.BuildAlterTableSQL(query, mapping.TypeMap)
+	case "drop_table":
+		return mssqlbuilders.BuildDropTableSQL(query)
+	case "truncate_table":
+		return mssqlbuilders.BuildTruncateTableSQL(query)
+	case "sp_rename":
+		return mssqlbuilders.BuildRenameTableSQL(query)
+	case "create_index":
+		return mssqlbuilders.BuildCreateIndexSQL(query)
+	case "drop_index":
+		return mssqlbuilders.BuildDropIndexSQL(query)
+	case "create_database":
+		return mssqlbuilders.BuildCreateDatabaseSQL(query)
+	case "drop_database":
+		return mssqlbuilders.BuildDropDatabaseSQL(query)
+	case "create_view":
+		return mssqlbuilders.BuildCreateViewSQL(query)
+	case "alter_view":
+		return mssqlbuilders.BuildAlterViewSQL(query)
+	case "drop_view":
+		return mssqlbuilders.BuildDropViewSQL(query)
+	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
+		sql, _ := mssqlbuilders.BuildJoinSQL(query)
+		return sql, nil
+	case "count", "sum", "avg", "min", "max", "string_agg":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _, err := mssqlbuilders.BuildWindowSQL(query)
+			return sql, err
+		}
+		sql, _, err := mssqlbuilders.BuildAggregateSQL(query)
+		return sql, err
+	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
+		sql, _, err := mssqlbuilders.BuildWindowSQL(query)
+		return sql, err
+	case "union", "union_all", "intersect", "except":
+		sql, _, err := mssqlbuilders.BuildSetOperationSQL(query)
+		return sql, err
+	case "begin_transaction":
+		return "BEGIN TRANSACTION", nil
+	case "commit":
+		return "COMMIT", nil
+	case "rollback":
+		return "ROLLBACK", nil
+	case "save_transaction":
+		return mssqlbuilders.BuildSavepointSQL(query.SavepointName)
+	case "rollback_transaction":
+		return mssqlbuilders.BuildRollbackToSavepointSQL(query.SavepointName)
+	case "set_transaction":
+		return mssqlbuilders.BuildSetTransactionSQL(query)
+	case "with":
+		sql, _, err := mssqlbuilders.BuildCTESQL(query)
+		return sql, err
+	case "subquery", "exists":
+		sql, _, err := mssqlbuilders.BuildSubquerySQL(query)
+		return sql, err
+	default:
+		return "", nil
+	}
+}
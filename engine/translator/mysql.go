@@ -7,7 +7,6 @@ import (
 	mysqlbuilders "github.com/omniql-engine/omniql/engine/builders/mysql"
 	"github.com/omniql-engine/omniql/engine/models"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
-	"github.com/jinzhu/inflection"
 )
 
 // ============================================================================
@@ -100,7 +99,12 @@ func mapMySQLOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
 // MAIN TRANSLATOR
 // ============================================================================
 
+// TranslateMySQL translates query with the default MySQL options
 func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	return translateMySQL(query, tenantID, mysqlbuilders.Options{})
+}
+
+func translateMySQL(query *models.Query, tenantID string, opts mysqlbuilders.Options) (*pb.RelationalQuery, error) {
 	operation := mapping.OperationMap["MySQL"][query.Operation]
 	table := getMySQLTableName(query.Entity, query.Operation)
 	conditions := mapMySQLConditions(query.Conditions)
@@ -114,10 +118,16 @@ func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery,
 	
 	// DQL: Advanced fields
 	windowFunctions := mapMySQLWindowFunctions(query.WindowFunctions)
-	cte := mapMySQLCTE(query.CTE, tenantID)
-	subquery := mapMySQLSubquery(query.Subquery, tenantID)
+	cte, err := mapMySQLCTE(query.CTE, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+	subquery, err := mapMySQLSubquery(query.Subquery, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
 	pattern := query.Pattern
-	setOperation, err := mapMySQLSetOperation(query.SetOperation, tenantID)
+	setOperation, err := mapMySQLSetOperation(query.SetOperation, tenantID, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -125,23 +135,43 @@ func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery,
 	// TCL
 	var savepointName, isolationLevel string
 	var readOnly bool
+	var lockTables []*pb.TableLock
 	if query.Transaction != nil {
 		savepointName = query.Transaction.SavepointName
 		isolationLevel = query.Transaction.IsolationLevel
 		readOnly = query.Transaction.ReadOnly
+		for _, tl := range query.Transaction.LockTables {
+			lockTables = append(lockTables, &pb.TableLock{
+				Table: getMySQLTableName(tl.Entity, "GET"),
+				Mode:  tl.Mode,
+			})
+		}
 	}
 	
 	// DCL
 	var permissions []string
 	var permissionTarget, roleName, userName, password string
 	var userRoles []string
+	var permissionObject string
+	var roleFlags []string
+	var validUntil, connectionLimit, userHost string
 	if query.Permission != nil {
 		permissions = query.Permission.Permissions
+		permissionObject = query.Permission.ObjectType
 		permissionTarget = query.Permission.Target
 		roleName = query.Permission.RoleName
 		userName = query.Permission.UserName
 		password = query.Permission.Password
 		userRoles = query.Permission.Roles
+		roleFlags = query.Permission.RoleFlags
+		validUntil = query.Permission.ValidUntil
+		connectionLimit = query.Permission.ConnectionLimit
+		userHost = query.Permission.Host
+
+		// Non-table objects are named as written, not pluralized
+		if permissionObject != "" {
+			table = query.Entity
+		}
 	}
 	
 	// CRUD extensions
@@ -150,14 +180,17 @@ func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery,
 
 	// DDL
 	viewName := query.ViewName
-	viewQuery := mapMySQLViewQuery(query.ViewQuery, tenantID)
+	viewQuery, err := mapMySQLViewQuery(query.ViewQuery, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
 	databaseName := query.DatabaseName
 	newName := query.NewName
 	if query.NewName != "" && query.Operation == "RENAME TABLE" {
 		lookupOp := strings.ToUpper(strings.ReplaceAll(query.Operation, "_", " "))
 		rule := mapping.TableNamingRules[lookupOp]
 		if rule == "plural" {
-			newName = inflection.Plural(strings.ToLower(query.NewName))
+			newName = mapping.EntityTable(query.NewName)
 		} else {
 			newName = strings.ToLower(query.NewName)
 		}
@@ -171,6 +204,8 @@ func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery,
 		Limit:      int32(query.Limit),
 		Offset:     int32(query.Offset),
 		Distinct:   query.Distinct,
+		Lock:       query.Lock,
+		LockWait:   query.LockWait,
 		
 		// DQL
 		Joins:           joins,
@@ -190,14 +225,20 @@ func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery,
 		SavepointName:  savepointName,
 		IsolationLevel: isolationLevel,
 		ReadOnly:       readOnly,
+		LockTables:     lockTables,
 		
 		// DCL
 		Permissions:      permissions,
 		PermissionTarget: permissionTarget,
+		PermissionObject: permissionObject,
 		RoleName:         roleName,
 		UserName:         userName,
 		Password:         password,
 		UserRoles:        userRoles,
+		RoleFlags:        roleFlags,
+		ValidUntil:       validUntil,
+		ConnectionLimit:  connectionLimit,
+		UserHost:         userHost,
 		
 		// CRUD Extensions
 		Upsert:   upsert,
@@ -209,9 +250,17 @@ func TranslateMySQL(query *models.Query, tenantID string) (*pb.RelationalQuery,
 		DatabaseName: databaseName,
 		NewName:      newName,
 		AlterAction:  query.AlterAction,
+		TableOptions: query.TableOptions,
 	}
 	
-	result.Sql = buildMySQLString(result)
+	if query.Collation != "" {
+		applyCollation(result, mysqlbuilders.CollationName(query.Collation, query.CollationStrength))
+	}
+	sql, err := buildMySQLString(result, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = sql
 	return result, nil
 }
 
@@ -226,9 +275,10 @@ func mapMySQLFields(fields []models.Field) []*pb.QueryField {
 	var result []*pb.QueryField
 	for _, field := range fields {
 		result = append(result, &pb.QueryField{
-			NameExpr:    mapMySQLExpression(field.NameExpr),
-			ValueExpr:   mapMySQLExpression(field.ValueExpr),
-			Constraints: field.Constraints,
+			NameExpr:      mapMySQLExpression(field.NameExpr),
+			ValueExpr:     mapMySQLExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapMySQLExpression(field.GeneratedExpr),
 		})
 	}
 	return result
@@ -239,7 +289,7 @@ func getMySQLTableName(entity string, operation string) string {
 	rule := mapping.TableNamingRules[lookupOp]
 	
 	if rule == "plural" {
-		return inflection.Plural(strings.ToLower(entity))
+		return mapping.EntityTable(entity)
 	}
 	if rule == "none" {
 		return ""
@@ -256,9 +306,10 @@ func mapMySQLUpsert(upsert *models.Upsert) *pb.UpsertClause {
 		return nil
 	}
 	return &pb.UpsertClause{
-		ConflictFields: mapMySQLExpressions(upsert.ConflictFields),
-		UpdateFields:   mapMySQLFields(upsert.UpdateFields),
-		ConflictAction: "UPDATE",
+		ConflictFields:     mapMySQLExpressions(upsert.ConflictFields),
+		UpdateFields:       mapMySQLFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
 	}
 }
 
@@ -306,6 +357,8 @@ func mapMySQLAggregate(agg *models.Aggregation) *pb.AggregateClause {
 	return &pb.AggregateClause{
 		Function:  string(agg.Function),
 		FieldExpr: mapMySQLExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapMySQLOrderByClauses(agg.OrderBy),
 	}
 }
 
@@ -320,13 +373,17 @@ func mapMySQLWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowCl
 	var result []*pb.WindowClause
 	for _, wf := range windowFuncs {
 		result = append(result, &pb.WindowClause{
-			Function:    string(wf.Function),
-			FieldExpr:   mapMySQLExpression(wf.FieldExpr),
-			Alias:       wf.Alias,
-			PartitionBy: mapMySQLExpressions(wf.PartitionBy),
-			OrderBy:     mapMySQLOrderByClauses(wf.OrderBy),
-			Offset:      int32(wf.Offset),
-			Buckets:     int32(wf.Buckets),
+			Function:     string(wf.Function),
+			FieldExpr:    mapMySQLExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapMySQLExpressions(wf.PartitionBy),
+			OrderBy:      mapMySQLOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapMySQLExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
 		})
 	}
 	return result
@@ -336,54 +393,82 @@ func mapMySQLWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowCl
 // CTE MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapMySQLCTE(cte *models.CTE, tenantID string) *pb.CTEClause {
+func mapMySQLCTE(cte *models.CTE, tenantID string, opts mysqlbuilders.Options) (*pb.CTEClause, error) {
 	if cte == nil {
-		return nil
+		return nil, nil
 	}
 	var cteQuery *pb.RelationalQuery
 	if cte.Query != nil {
-		cteQuery, _ = TranslateMySQL(cte.Query, tenantID)
+		var err error
+		if cteQuery, err = translateMySQL(cte.Query, tenantID, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		var err error
+		if mainQuery, err = translateMySQL(main, tenantID, opts); err != nil {
+			return nil, err
+		}
+		pointAtCTE(main, mainQuery, cte.Name)
 	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
+	}
+
 	return &pb.CTEClause{
 		CteName:   cte.Name,
 		CteQuery:  cteQuery,
 		Recursive: cte.Recursive,
-	}
+		MainQuery: mainQuery,
+	}, nil
 }
 
 // ============================================================================
 // SUBQUERY MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapMySQLSubquery(subquery *models.Subquery, tenantID string) *pb.SubqueryClause {
+func mapMySQLSubquery(subquery *models.Subquery, tenantID string, opts mysqlbuilders.Options) (*pb.SubqueryClause, error) {
 	if subquery == nil {
-		return nil
+		return nil, nil
 	}
 	var subqueryQuery *pb.RelationalQuery
 	if subquery.Query != nil {
-		subqueryQuery, _ = TranslateMySQL(subquery.Query, tenantID)
+		var err error
+		if subqueryQuery, err = translateMySQL(subquery.Query, tenantID, opts); err != nil {
+			return nil, err
+		}
 	}
 	return &pb.SubqueryClause{
 		SubqueryType: subquery.Type,
 		FieldExpr:    mapMySQLExpression(subquery.FieldExpr),
 		Subquery:     subqueryQuery,
 		Alias:        subquery.Alias,
-	}
+	}, nil
 }
 
 // ============================================================================
 // SET OPERATION MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapMySQLSetOperation(setOp *models.SetOperation, tenantID string) (*pb.SetOperationClause, error) {
+func mapMySQLSetOperation(setOp *models.SetOperation, tenantID string, opts mysqlbuilders.Options) (*pb.SetOperationClause, error) {
 	if setOp == nil {
 		return nil, nil
 	}
-	leftQuery, err := TranslateMySQL(setOp.LeftQuery, tenantID)
+	leftQuery, err := translateMySQL(setOp.LeftQuery, tenantID, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to translate left query: %w", err)
 	}
-	rightQuery, err := TranslateMySQL(setOp.RightQuery, tenantID)
+	rightQuery, err := translateMySQL(setOp.RightQuery, tenantID, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to translate right query: %w", err)
 	}
@@ -416,139 +501,134 @@ func mapMySQLSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn
 // VIEW QUERY MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapMySQLViewQuery(viewQuery *models.Query, tenantID string) *pb.RelationalQuery {
+func mapMySQLViewQuery(viewQuery *models.Query, tenantID string, opts mysqlbuilders.Options) (*pb.RelationalQuery, error) {
 	if viewQuery == nil {
-		return nil
+		return nil, nil
 	}
-	result, _ := TranslateMySQL(viewQuery, tenantID)
-	return result
+	return translateMySQL(viewQuery, tenantID, opts)
 }
 
 // ============================================================================
 // SQL STRING BUILDER
 // ============================================================================
 
-func buildMySQLString(query *pb.RelationalQuery) string {
+func buildMySQLString(query *pb.RelationalQuery, opts mysqlbuilders.Options) (string, error) {
 	operation := strings.ToLower(query.Operation)
 	
 	switch operation {
 	case "select":
 		sql, _ := mysqlbuilders.BuildSelectSQL(query)
-		return sql
+		return sql, nil
 	case "insert":
 		sql, _ := mysqlbuilders.BuildInsertSQL(query)
-		return sql
+		return sql, nil
 	case "update":
 		sql, _ := mysqlbuilders.BuildUpdateSQL(query)
-		return sql
+		return sql, nil
 	case "delete":
 		sql, _ := mysqlbuilders.BuildDeleteSQL(query)
-		return sql
+		return sql, nil
 	case "upsert":
-		sql, _, _ := mysqlbuilders.BuildUpsertSQL(query)
-		return sql
+		sql, _, err := mysqlbuilders.BuildUpsertSQL(query, opts)
+		return sql, err
 	case "replace":
 		sql, _ := mysqlbuilders.BuildInsertSQL(query)
-		return strings.Replace(sql, "INSERT", "REPLACE", 1)
+		return strings.Replace(sql, "INSERT", "REPLACE", 1), nil
 	case "bulk_insert":
-		sql, _, _ := mysqlbuilders.BuildBulkInsertSQL(query)
-		return sql
+		sql, _, err := mysqlbuilders.BuildBulkInsertSQL(query)
+		return sql, err
+	case "bulk_upsert":
+		statements, _, err := mysqlbuilders.BuildBulkUpsertSQL(query, opts)
+		return strings.Join(statements, ";\n"), err
 	case "create_table":
-		sql, _ := mysqlbuilders.BuildCreateTableSQL(query, mapping.TypeMap)
-		return sql
+		return mysqlbuilders.BuildCreateTableSQL(query, mapping.TypeMap)
 	case "alter_table":
-		sql, _ := mysqlbuilders.BuildAlterTableSQL(query, mapping.TypeMap)
-		return sql
+		return mysqlbuilders.BuildAlterTableSQL(query, mapping.TypeMap)
 	case "drop_table":
-		sql, _ := mysqlbuilders.BuildDropTableSQL(query)
-		return sql
+		return mysqlbuilders.BuildDropTableSQL(query)
 	case "truncate_table":
-		sql, _ := mysqlbuilders.BuildTruncateTableSQL(query)
-		return sql
+		return mysqlbuilders.BuildTruncateTableSQL(query)
 	case "alter_table_rename":
-		sql, _ := mysqlbuilders.BuildRenameTableSQL(query)
-		return sql
+		return mysqlbuilders.BuildRenameTableSQL(query)
+	case "show_tables":
+		return mysqlbuilders.BuildShowTablesSQL(), nil
+	case "show_columns":
+		return mysqlbuilders.BuildShowColumnsSQL(query)
+	case "show_index":
+		return mysqlbuilders.BuildShowIndexSQL(query)
 	case "create_index":
-		sql, _ := mysqlbuilders.BuildCreateIndexSQL(query)
-		return sql
+		return mysqlbuilders.BuildCreateIndexSQL(query)
 	case "drop_index":
-		sql, _ := mysqlbuilders.BuildDropIndexSQL(query)
-		return sql
+		return mysqlbuilders.BuildDropIndexSQL(query)
 	case "create_database":
-		sql, _ := mysqlbuilders.BuildCreateDatabaseSQL(query)
-		return sql
+		return mysqlbuilders.BuildCreateDatabaseSQL(query)
 	case "drop_database":
-		sql, _ := mysqlbuilders.BuildDropDatabaseSQL(query)
-		return sql
+		return mysqlbuilders.BuildDropDatabaseSQL(query)
 	case "create_view":
-		sql, _ := mysqlbuilders.BuildCreateViewSQL(query)
-		return sql
+		return mysqlbuilders.BuildCreateViewSQL(query)
 	case "drop_view":
-		sql, _ := mysqlbuilders.BuildDropViewSQL(query)
-		return sql
+		return mysqlbuilders.BuildDropViewSQL(query)
 	case "alter_view":
-		sql, _ := mysqlbuilders.BuildAlterViewSQL(query)
-		return sql
+		return mysqlbuilders.BuildAlterViewSQL(query)
 	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
 		sql, _ := mysqlbuilders.BuildJoinSQL(query)
-		return sql
-	case "count", "sum", "avg", "min", "max":
+		return sql, nil
+	case "count", "sum", "avg", "min", "max", "group_concat":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _ := mysqlbuilders.BuildWindowSQL(query)
+			return sql, nil
+		}
 		sql, _ := mysqlbuilders.BuildAggregateSQL(query)
-		return sql
+		return sql, nil
 	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
 		sql, _ := mysqlbuilders.BuildWindowSQL(query)
-		return sql
+		return sql, nil
 	case "union", "union_all", "intersect", "except":
-		sql, _ := mysqlbuilders.BuildSetOperationSQL(query)
-		return sql
+		sql, _, err := mysqlbuilders.BuildSetOperationSQL(query, opts)
+		return sql, err
 	case "grant":
-		sql, _ := mysqlbuilders.BuildGrantSQL(query, false)
-		return sql
+		return mysqlbuilders.BuildGrantSQL(query, false)
 	case "revoke":
-		sql, _ := mysqlbuilders.BuildRevokeSQL(query, false)
-		return sql
+		return mysqlbuilders.BuildRevokeSQL(query, false)
 	case "create_user":
-		sql, _ := mysqlbuilders.BuildCreateUserSQL(query)
-		return sql
+		return mysqlbuilders.BuildCreateUserSQL(query)
 	case "drop_user":
-		sql, _ := mysqlbuilders.BuildDropUserSQL(query)
-		return sql
+		return mysqlbuilders.BuildDropUserSQL(query)
 	case "alter_user":
-		sql, _ := mysqlbuilders.BuildAlterUserSQL(query)
-		return sql
+		return mysqlbuilders.BuildAlterUserSQL(query)
 	case "create_role":
-		sql, _ := mysqlbuilders.BuildCreateRoleSQL(query)
-		return sql
+		return mysqlbuilders.BuildCreateRoleSQL(query)
+	case "alter_role":
+		return mysqlbuilders.BuildAlterRoleSQL(query)
 	case "drop_role":
-		sql, _ := mysqlbuilders.BuildDropRoleSQL(query)
-		return sql
+		return mysqlbuilders.BuildDropRoleSQL(query)
 	case "assign_role":
-		sql, _ := mysqlbuilders.BuildAssignRoleSQL(query)
-		return sql
+		return mysqlbuilders.BuildAssignRoleSQL(query)
 	case "revoke_role":
-		sql, _ := mysqlbuilders.BuildRevokeRoleSQL(query)
-		return sql
+		return mysqlbuilders.BuildRevokeRoleSQL(query)
 	case "begin", "start", "start_transaction":
-    	return "START TRANSACTION"
+		return "START TRANSACTION", nil
 	case "commit":
-		return "COMMIT"
+		return "COMMIT", nil
 	case "rollback":
-		return "ROLLBACK"
+		return "ROLLBACK", nil
 	case "savepoint":
-		sql, _ := mysqlbuilders.BuildSavepointSQL(query.SavepointName)
-		return sql
+		return mysqlbuilders.BuildSavepointSQL(query.SavepointName)
 	case "rollback_to":
-		sql, _ := mysqlbuilders.BuildRollbackToSavepointSQL(query.SavepointName)
-		return sql
+		return mysqlbuilders.BuildRollbackToSavepointSQL(query.SavepointName)
 	case "release_savepoint":
-		sql, _ := mysqlbuilders.BuildReleaseSavepointSQL(query.SavepointName)
-		return sql
+		return mysqlbuilders.BuildReleaseSavepointSQL(query.SavepointName)
 	case "set_transaction":
-		return mysqlbuilders.BuildSetTransactionSQL(query.IsolationLevel)
+		return mysqlbuilders.BuildSetTransactionOptionsSQL(query), nil
+	case "lock_tables":
+		return mysqlbuilders.BuildLockTablesSQL(query.LockTables)
+	case "unlock_tables":
+		return "UNLOCK TABLES", nil
 	case "with":
-		sql, _ := mysqlbuilders.BuildCTESQL(query)
-		return sql
+		sql, _, err := mysqlbuilders.BuildCTESQL(query, opts)
+		return sql, err
 	default:
-		return ""
+		return "", nil
 	}
 }
\ No newline at end of file
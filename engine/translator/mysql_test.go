@@ -0,0 +1,90 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	mysqlbuilders "github.com/omniql-engine/omniql/engine/builders/mysql"
+	"github.com/omniql-engine/omniql/engine/parser"
+)
+
+// Account options MySQL cannot express fail instead of translating to ""
+func TestMySQLAccountErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`CREATE ROLE app WITH LOGIN, CONNECTION LIMIT 10`, "takes no options"},
+		{`ALTER ROLE app WITH VALID UNTIL '2027-01-01'`, "VALID UNTIL is not supported"},
+		{`CREATE USER john WITH PASSWORD secret123 VALID UNTIL '2027-01-01'`, "VALID UNTIL is not supported"},
+		{`ALTER USER john WITH VALID UNTIL '2027-01-01'`, "VALID UNTIL is not supported"},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := TranslateMySQL(query, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("TranslateMySQL(%q) = %v, %v; want error containing %q", tt.input, result, err, tt.want)
+		}
+	}
+}
+
+// INTERSECT / EXCEPT are rewritten for MySQL before 8.0.31, or rejected when
+// the rewrite cannot keep the query's meaning
+func TestMySQLSetOperationEmulation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`INTERSECT (GET User WITH email) (GET Customer WITH email)`,
+			"SELECT DISTINCT `l`.* FROM (SELECT `email` FROM `users`) AS `l` WHERE EXISTS (SELECT 1 FROM (SELECT `email` FROM `customers`) AS `r` WHERE `l`.`email` <=> `r`.`email`)"},
+		{`EXCEPT (GET User WITH email WHERE age > 3) (GET User WITH email WHERE age < 9)`,
+			"SELECT DISTINCT `l`.* FROM (SELECT `email` FROM `users` WHERE `age` > ?) AS `l` WHERE NOT EXISTS (SELECT 1 FROM (SELECT `email` FROM `users` WHERE `age` < ?) AS `r` WHERE `l`.`email` <=> `r`.`email`)"},
+		{`INTERSECT (GET User WITH email AS contact) (GET Customer WITH mail)`,
+			"SELECT DISTINCT `l`.* FROM (SELECT `email` AS `contact` FROM `users`) AS `l` WHERE EXISTS (SELECT 1 FROM (SELECT `mail` FROM `customers`) AS `r` WHERE `l`.`contact` <=> `r`.`mail`)"},
+		{`EXCEPT (GET User WHERE age > 3) (GET User WHERE age < 9)`,
+			"SELECT DISTINCT * FROM `users` WHERE (`age` > ?) AND NOT COALESCE((`age` < ?), FALSE)"},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := TranslateMySQL(query, "")
+		if err != nil {
+			t.Errorf("TranslateMySQL(%q): %v", tt.input, err)
+			continue
+		}
+		if result.Sql != tt.want {
+			t.Errorf("TranslateMySQL(%q):\n got %s\nwant %s", tt.input, result.Sql, tt.want)
+		}
+	}
+
+	for _, input := range []string{
+		`INTERSECT (GET User) (GET Customer)`,
+		`EXCEPT (GET User WITH email) (GET Customer WITH email, name)`,
+	} {
+		query, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		if result, err := TranslateMySQL(query, ""); err == nil {
+			t.Errorf("TranslateMySQL(%q) = %q; want an error", input, result.Sql)
+		}
+	}
+
+	query, err := parser.Parse(`INTERSECT (GET User) (GET Customer)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := TranslateWithOptions(query, "MySQL", "", Options{MySQL: mysqlbuilders.Options{NativeSetOperations: true}})
+	if err != nil {
+		t.Fatalf("native INTERSECT: %v", err)
+	}
+	const want = "(SELECT * FROM `users`) INTERSECT (SELECT * FROM `customers`)"
+	if got := result.GetRelational().GetSql(); got != want {
+		t.Errorf("native INTERSECT:\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,272 @@
+package translator
+
+import (
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+)
+
+// ============================================================================
+// COLUMN NAMING (mapping.ColumnNaming)
+// ============================================================================
+
+// applyColumnNaming returns query with its field names in dbType's column
+// naming convention. The query is copied where names change, so the
+// caller's query can still be translated for another database.
+func applyColumnNaming(query *models.Query, dbType string) *models.Query {
+	if mapping.ColumnNaming[dbType] == mapping.AsWritten {
+		return query
+	}
+	r := columnRenamer{dbType: dbType}
+	return r.query(query)
+}
+
+// columnRenamer renames the fields of one query. A bare word in a value
+// position (name = active, status IN (a, b)) is a string, not a column,
+// so only FIELDs nested in an expression there are renamed.
+type columnRenamer struct {
+	dbType  string
+	aliases map[string]bool // SELECT, window and CASE aliases of the query
+}
+
+func (r columnRenamer) query(q *models.Query) *models.Query {
+	if q == nil {
+		return nil
+	}
+	out := *q
+	r.aliases = queryAliases(q)
+
+	out.Columns = r.exprs(q.Columns)
+	if q.SelectColumns != nil {
+		out.SelectColumns = make([]models.SelectColumn, len(q.SelectColumns))
+		for i, col := range q.SelectColumns {
+			col.ExpressionObj = r.expr(col.ExpressionObj)
+			out.SelectColumns[i] = col
+		}
+	}
+	out.Conditions = r.conditions(q.Conditions)
+	out.Fields = r.fields(q.Fields, q)
+	if q.BulkData != nil {
+		out.BulkData = make([][]models.Field, len(q.BulkData))
+		for i, row := range q.BulkData {
+			out.BulkData[i] = r.fields(row, q)
+		}
+	}
+	if q.Upsert != nil {
+		upsert := *q.Upsert
+		upsert.ConflictFields = r.exprs(q.Upsert.ConflictFields)
+		upsert.ConflictWhere = r.conditions(q.Upsert.ConflictWhere)
+		upsert.UpdateFields = r.fields(q.Upsert.UpdateFields, q)
+		out.Upsert = &upsert
+	}
+	out.Returning = r.exprs(q.Returning)
+	out.PartitionKeys = r.exprs(q.PartitionKeys)
+	out.ClusterKeys = r.exprs(q.ClusterKeys)
+	out.ViewQuery = r.query(q.ViewQuery)
+
+	if q.Joins != nil {
+		out.Joins = make([]models.Join, len(q.Joins))
+		for i, join := range q.Joins {
+			join.LeftExpr = r.expr(join.LeftExpr)
+			join.RightExpr = r.expr(join.RightExpr)
+			join.Conditions = r.conditions(join.Conditions)
+			out.Joins[i] = join
+		}
+	}
+	out.Aggregate = r.aggregate(q.Aggregate)
+	if q.Facets != nil {
+		out.Facets = make([]models.Facet, len(q.Facets))
+		for i, facet := range q.Facets {
+			facet.Aggregate = r.aggregate(facet.Aggregate)
+			facet.GroupBy = r.exprs(facet.GroupBy)
+			out.Facets[i] = facet
+		}
+	}
+	out.GroupBy = r.exprs(q.GroupBy)
+	out.Having = r.conditions(q.Having)
+	out.OrderBy = r.orderBy(q.OrderBy)
+	if q.WindowFunctions != nil {
+		out.WindowFunctions = make([]models.WindowFunction, len(q.WindowFunctions))
+		for i, wf := range q.WindowFunctions {
+			wf.FieldExpr = r.expr(wf.FieldExpr)
+			wf.PartitionBy = r.exprs(wf.PartitionBy)
+			wf.OrderBy = r.orderBy(wf.OrderBy)
+			wf.Default = r.value(wf.Default)
+			out.WindowFunctions[i] = wf
+		}
+	}
+
+	if q.SetOperation != nil {
+		setOp := *q.SetOperation
+		setOp.LeftQuery = r.query(q.SetOperation.LeftQuery)
+		setOp.RightQuery = r.query(q.SetOperation.RightQuery)
+		out.SetOperation = &setOp
+	}
+	if q.CTE != nil {
+		cte := *q.CTE
+		cte.Query = r.query(q.CTE.Query)
+		cte.MainQuery = r.query(q.CTE.MainQuery)
+		out.CTE = &cte
+	}
+	if q.Subquery != nil {
+		sub := *q.Subquery
+		sub.FieldExpr = r.expr(q.Subquery.FieldExpr)
+		sub.Query = r.query(q.Subquery.Query)
+		out.Subquery = &sub
+	}
+	if q.CaseStatement != nil {
+		cs := *q.CaseStatement
+		cs.WhenClauses = r.caseConditions(q.CaseStatement.WhenClauses)
+		cs.ElseExpr = r.value(q.CaseStatement.ElseExpr)
+		out.CaseStatement = &cs
+	}
+	return &out
+}
+
+// fields renames column names and the columns used in values. An index
+// name is not a column; the new name of RENAME COLUMN is.
+func (r columnRenamer) fields(fields []models.Field, q *models.Query) []models.Field {
+	if fields == nil {
+		return nil
+	}
+	out := make([]models.Field, len(fields))
+	for i, field := range fields {
+		switch {
+		case q.Operation == "CREATE INDEX" || q.Operation == "DROP INDEX":
+			field.ValueExpr = r.expr(field.ValueExpr)
+		case q.AlterAction == "RENAME_COLUMN":
+			field.NameExpr = r.expr(field.NameExpr)
+			field.ValueExpr = r.expr(field.ValueExpr)
+		default:
+			field.NameExpr = r.expr(field.NameExpr)
+			field.ValueExpr = r.value(field.ValueExpr)
+		}
+		field.GeneratedExpr = r.expr(field.GeneratedExpr)
+		out[i] = field
+	}
+	return out
+}
+
+func (r columnRenamer) conditions(conditions []models.Condition) []models.Condition {
+	if conditions == nil {
+		return nil
+	}
+	out := make([]models.Condition, len(conditions))
+	for i, cond := range conditions {
+		cond.FieldExpr = r.expr(cond.FieldExpr)
+		cond.ValueExpr = r.value(cond.ValueExpr)
+		cond.Value2Expr = r.value(cond.Value2Expr)
+		if cond.ValuesExpr != nil {
+			values := make([]*models.Expression, len(cond.ValuesExpr))
+			for j, value := range cond.ValuesExpr {
+				values[j] = r.value(value)
+			}
+			cond.ValuesExpr = values
+		}
+		cond.Nested = r.conditions(cond.Nested)
+		out[i] = cond
+	}
+	return out
+}
+
+func (r columnRenamer) caseConditions(clauses []*models.CaseCondition) []*models.CaseCondition {
+	if clauses == nil {
+		return nil
+	}
+	out := make([]*models.CaseCondition, len(clauses))
+	for i, clause := range clauses {
+		if clause == nil {
+			continue
+		}
+		c := *clause
+		if clause.Condition != nil {
+			c.Condition = &r.conditions([]models.Condition{*clause.Condition})[0]
+		}
+		c.ThenExpr = r.value(clause.ThenExpr)
+		out[i] = &c
+	}
+	return out
+}
+
+func (r columnRenamer) aggregate(agg *models.Aggregation) *models.Aggregation {
+	if agg == nil {
+		return nil
+	}
+	out := *agg
+	out.FieldExpr = r.expr(agg.FieldExpr)
+	out.OrderBy = r.orderBy(agg.OrderBy)
+	return &out
+}
+
+func (r columnRenamer) orderBy(orderBy []models.OrderBy) []models.OrderBy {
+	if orderBy == nil {
+		return nil
+	}
+	out := make([]models.OrderBy, len(orderBy))
+	for i, ob := range orderBy {
+		ob.FieldExpr = r.expr(ob.FieldExpr)
+		out[i] = ob
+	}
+	return out
+}
+
+func (r columnRenamer) exprs(exprs []*models.Expression) []*models.Expression {
+	if exprs == nil {
+		return nil
+	}
+	out := make([]*models.Expression, len(exprs))
+	for i, expr := range exprs {
+		out[i] = r.expr(expr)
+	}
+	return out
+}
+
+// value renames the columns in an expression in a value position: a bare
+// FIELD there is a string and is kept
+func (r columnRenamer) value(expr *models.Expression) *models.Expression {
+	if expr == nil || expr.Type == "FIELD" {
+		return expr
+	}
+	return r.expr(expr)
+}
+
+// expr renames the FIELDs of an expression tree; aliases, * and search
+// rank fields (RELEVANCE) are not columns and are kept
+func (r columnRenamer) expr(expr *models.Expression) *models.Expression {
+	if expr == nil {
+		return nil
+	}
+	out := *expr
+	if expr.Type == "FIELD" && !r.aliases[expr.Value] && !mapping.IsSearchRankField(expr.Value) {
+		out.Value = mapping.ColumnName(r.dbType, expr.Value)
+	}
+	out.Left = r.expr(expr.Left)
+	out.Right = r.expr(expr.Right)
+	out.FunctionArgs = r.exprs(expr.FunctionArgs)
+	out.CaseConditions = r.caseConditions(expr.CaseConditions)
+	out.CaseElse = r.value(expr.CaseElse)
+	out.PartitionBy = r.exprs(expr.PartitionBy)
+	out.WindowOrderBy = r.orderBy(expr.WindowOrderBy)
+	return &out
+}
+
+// queryAliases are the names a query gives its result columns
+func queryAliases(q *models.Query) map[string]bool {
+	aliases := map[string]bool{}
+	for _, col := range q.SelectColumns {
+		if col.Alias != "" {
+			aliases[col.Alias] = true
+		}
+	}
+	for _, wf := range q.WindowFunctions {
+		if wf.Alias != "" {
+			aliases[wf.Alias] = true
+		}
+	}
+	if q.CaseStatement != nil && q.CaseStatement.Alias != "" {
+		aliases[q.CaseStatement.Alias] = true
+	}
+	if q.Subquery != nil && q.Subquery.Alias != "" {
+		aliases[q.Subquery.Alias] = true
+	}
+	return aliases
+}
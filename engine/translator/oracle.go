@@ -0,0 +1,528 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	oraclebuilders "github.com/omniql-engine/omniql/engine/builders/oracle"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	return &pb.Expression{
+		Type:           expr.Type,
+		Value:          expr.Value,
+		Left:           mapOracleExpression(expr.Left),
+		Operator:       expr.Operator,
+		Right:          mapOracleExpression(expr.Right),
+		FunctionName:   expr.FunctionName,
+		FunctionArgs:   mapOracleExpressions(expr.FunctionArgs),
+		CaseConditions: mapOracleCaseConditions(expr.CaseConditions),
+		CaseElse:       mapOracleExpression(expr.CaseElse),
+	}
+}
+
+func mapOracleExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapOracleExpression(expr))
+	}
+	return result
+}
+
+func mapOracleCaseConditions(conditions []*models.CaseCondition) []*pb.CaseCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.CaseCondition
+	for _, cc := range conditions {
+		result = append(result, &pb.CaseCondition{
+			Condition: mapOracleCondition(cc.Condition),
+			ThenExpr:  mapOracleExpression(cc.ThenExpr),
+		})
+	}
+	return result
+}
+
+func mapOracleCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapOracleExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapOracleExpression(cond.ValueExpr),
+		Value2Expr: mapOracleExpression(cond.Value2Expr),
+		ValuesExpr: mapOracleExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapOracleConditions(cond.Nested),
+	}
+}
+
+func mapOracleConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapOracleCondition(&cond))
+	}
+	return result
+}
+
+func mapOracleOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapOracleExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+// TranslateOracle translates query with the default Oracle options
+func TranslateOracle(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	return translateOracle(query, tenantID, oraclebuilders.Options{})
+}
+
+func translateOracle(query *models.Query, tenantID string, opts oraclebuilders.Options) (*pb.RelationalQuery, error) {
+	// RETURNING ... INTO needs out binds, which a single statement string cannot carry
+	if len(query.Returning) > 0 {
+		return nil, &mapping.ErrNotSupported{Database: "Oracle", Feature: "RETURNING"}
+	}
+
+	operation := mapping.OperationMap["Oracle"][query.Operation]
+	table := TableName(query.Entity, query.Operation)
+	conditions := mapOracleConditions(query.Conditions)
+	fields := mapOracleFields(query.Fields)
+
+	// DQL: Map fields
+	joins := mapOracleJoins(query.Joins)
+	aggregate := mapOracleAggregate(query.Aggregate)
+	orderBy := mapOracleOrderByClauses(query.OrderBy)
+	having := mapOracleConditions(query.Having)
+
+	// DQL: Advanced fields
+	windowFunctions := mapOracleWindowFunctions(query.WindowFunctions)
+	cte, err := mapOracleCTE(query.CTE, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+	subquery, err := mapOracleSubquery(query.Subquery, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+	setOperation, err := mapOracleSetOperation(query.SetOperation, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// TCL (accounts and permissions are left to the DBA, so no DCL)
+	var savepointName, isolationLevel string
+	var readOnly bool
+	if query.Transaction != nil {
+		savepointName = query.Transaction.SavepointName
+		isolationLevel = query.Transaction.IsolationLevel
+		readOnly = query.Transaction.ReadOnly
+	}
+
+	// DDL
+	viewQuery, err := mapOracleViewQuery(query.ViewQuery, tenantID, opts)
+	if err != nil {
+		return nil, err
+	}
+	newName := query.NewName
+	if query.NewName != "" && query.Operation == "RENAME TABLE" {
+		newName = TableName(query.NewName, query.Operation)
+	}
+
+	result := &pb.RelationalQuery{
+		Operation:  operation,
+		Table:      table,
+		Conditions: conditions,
+		Fields:     fields,
+		Limit:      int32(query.Limit),
+		Offset:     int32(query.Offset),
+		Distinct:   query.Distinct,
+		Lock:       query.Lock,
+		LockWait:   query.LockWait,
+
+		// DQL
+		Joins:           joins,
+		Columns:         mapOracleExpressions(query.Columns),
+		SelectColumns:   mapOracleSelectColumns(query.SelectColumns),
+		Aggregate:       aggregate,
+		OrderBy:         orderBy,
+		GroupBy:         mapOracleExpressions(query.GroupBy),
+		Having:          having,
+		WindowFunctions: windowFunctions,
+		Cte:             cte,
+		Subquery:        subquery,
+		Pattern:         query.Pattern,
+		SetOperation:    setOperation,
+
+		// TCL
+		SavepointName:  savepointName,
+		IsolationLevel: isolationLevel,
+		ReadOnly:       readOnly,
+
+		// CRUD Extensions
+		Upsert:   mapOracleUpsert(query.Upsert),
+		BulkData: mapOracleBulkData(query.BulkData),
+
+		// DDL
+		ViewName:    query.ViewName,
+		ViewQuery:   viewQuery,
+		NewName:     newName,
+		AlterAction: query.AlterAction,
+		Cascade:     query.Cascade,
+	}
+
+	sql, err := buildOracleString(result, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = oraclebuilders.NumberBinds(sql)
+	return result, nil
+}
+
+// ============================================================================
+// FIELD MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleFields(fields []models.Field) []*pb.QueryField {
+	if len(fields) == 0 {
+		return nil
+	}
+	var result []*pb.QueryField
+	for _, field := range fields {
+		result = append(result, &pb.QueryField{
+			NameExpr:      mapOracleExpression(field.NameExpr),
+			ValueExpr:     mapOracleExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapOracleExpression(field.GeneratedExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CRUD EXTENSIONS (100% TrueAST)
+// ============================================================================
+
+func mapOracleUpsert(upsert *models.Upsert) *pb.UpsertClause {
+	if upsert == nil {
+		return nil
+	}
+	return &pb.UpsertClause{
+		ConflictFields:     mapOracleExpressions(upsert.ConflictFields),
+		UpdateFields:       mapOracleFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
+		ConflictWhere:      mapOracleConditions(upsert.ConflictWhere),
+	}
+}
+
+func mapOracleBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
+	if len(bulkData) == 0 {
+		return nil
+	}
+	var result []*pb.BulkInsertRow
+	for _, row := range bulkData {
+		result = append(result, &pb.BulkInsertRow{
+			Fields: mapOracleFields(row),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// JOIN MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleJoins(joins []models.Join) []*pb.JoinClause {
+	if len(joins) == 0 {
+		return nil
+	}
+	var result []*pb.JoinClause
+	for _, join := range joins {
+		result = append(result, &pb.JoinClause{
+			JoinType:  string(join.Type),
+			Table:     TableName(join.Table, "GET"),
+			LeftExpr:  mapOracleExpression(join.LeftExpr),
+			RightExpr: mapOracleExpression(join.RightExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// AGGREGATE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapOracleExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapOracleOrderByClauses(agg.OrderBy),
+	}
+}
+
+// ============================================================================
+// WINDOW FUNCTIONS (100% TrueAST)
+// ============================================================================
+
+func mapOracleWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause {
+	if len(windowFuncs) == 0 {
+		return nil
+	}
+	var result []*pb.WindowClause
+	for _, wf := range windowFuncs {
+		result = append(result, &pb.WindowClause{
+			Function:     string(wf.Function),
+			FieldExpr:    mapOracleExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapOracleExpressions(wf.PartitionBy),
+			OrderBy:      mapOracleOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapOracleExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CTE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleCTE(cte *models.CTE, tenantID string, opts oraclebuilders.Options) (*pb.CTEClause, error) {
+	if cte == nil {
+		return nil, nil
+	}
+	var cteQuery *pb.RelationalQuery
+	if cte.Query != nil {
+		var err error
+		if cteQuery, err = translateOracle(cte.Query, tenantID, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		var err error
+		if mainQuery, err = translateOracle(main, tenantID, opts); err != nil {
+			return nil, err
+		}
+		pointAtCTE(main, mainQuery, cte.Name)
+	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
+	}
+
+	return &pb.CTEClause{
+		CteName:   cte.Name,
+		CteQuery:  cteQuery,
+		Recursive: cte.Recursive,
+		MainQuery: mainQuery,
+	}, nil
+}
+
+// ============================================================================
+// SUBQUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleSubquery(subquery *models.Subquery, tenantID string, opts oraclebuilders.Options) (*pb.SubqueryClause, error) {
+	if subquery == nil {
+		return nil, nil
+	}
+	var subqueryQuery *pb.RelationalQuery
+	if subquery.Query != nil {
+		var err error
+		if subqueryQuery, err = translateOracle(subquery.Query, tenantID, opts); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.SubqueryClause{
+		SubqueryType: subquery.Type,
+		FieldExpr:    mapOracleExpression(subquery.FieldExpr),
+		Subquery:     subqueryQuery,
+		Alias:        subquery.Alias,
+	}, nil
+}
+
+// ============================================================================
+// SET OPERATION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleSetOperation(setOp *models.SetOperation, tenantID string, opts oraclebuilders.Options) (*pb.SetOperationClause, error) {
+	if setOp == nil {
+		return nil, nil
+	}
+	leftQuery, err := translateOracle(setOp.LeftQuery, tenantID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate left query: %w", err)
+	}
+	rightQuery, err := translateOracle(setOp.RightQuery, tenantID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate right query: %w", err)
+	}
+	return &pb.SetOperationClause{
+		OperationType: string(setOp.Type),
+		LeftQuery:     leftQuery,
+		RightQuery:    rightQuery,
+	}, nil
+}
+
+// ============================================================================
+// SELECT COLUMNS MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapOracleExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// VIEW QUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapOracleViewQuery(viewQuery *models.Query, tenantID string, opts oraclebuilders.Options) (*pb.RelationalQuery, error) {
+	if viewQuery == nil {
+		return nil, nil
+	}
+	return translateOracle(viewQuery, tenantID, opts)
+}
+
+// ============================================================================
+// SQL STRING BUILDER
+// ============================================================================
+
+func buildOracleString(query *pb.RelationalQuery, opts oraclebuilders.Options) (string, error) {
+	operation := strings.ToLower(query.Operation)
+
+	switch operation {
+	case "select":
+		sql, _, err := oraclebuilders.BuildSelectSQL(query, opts)
+		return sql, err
+	case "insert":
+		sql, _, err := oraclebuilders.BuildInsertSQL(query, opts)
+		return sql, err
+	case "update":
+		sql, _ := oraclebuilders.BuildUpdateSQL(query)
+		return sql, nil
+	case "delete":
+		sql, _ := oraclebuilders.BuildDeleteSQL(query)
+		return sql, nil
+	case "merge":
+		sql, _, err := oraclebuilders.BuildUpsertSQL(query)
+		return sql, err
+	case "insert_all":
+		sql, _, err := oraclebuilders.BuildBulkInsertSQL(query)
+		return sql, err
+	case "bulk_merge":
+		sql, _, err := oraclebuilders.BuildBulkUpsertSQL(query)
+		return sql, err
+	case "create_table":
+		return oraclebuilders.BuildCreateTableSQL(query, mapping.TypeMap, opts)
+	case "alter_table":
+		return oraclebuilders.BuildAlterTableSQL(query, mapping.TypeMap)
+	case "drop_table":
+		return oraclebuilders.BuildDropTableSQL(query)
+	case "truncate_table":
+		return oraclebuilders.BuildTruncateTableSQL(query)
+	case "rename_table":
+		return oraclebuilders.BuildRenameTableSQL(query)
+	case "create_index":
+		return oraclebuilders.BuildCreateIndexSQL(query)
+	case "drop_index":
+		return oraclebuilders.BuildDropIndexSQL(query)
+	case "create_view":
+		return oraclebuilders.BuildCreateViewSQL(query, opts)
+	case "create_or_replace_view":
+		return oraclebuilders.BuildAlterViewSQL(query, opts)
+	case "drop_view":
+		return oraclebuilders.BuildDropViewSQL(query)
+	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
+		sql, _ := oraclebuilders.BuildJoinSQL(query, opts)
+		return sql, nil
+	case "count", "sum", "avg", "min", "max", "listagg":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _ := oraclebuilders.BuildWindowSQL(query)
+			return sql, nil
+		}
+		sql, _ := oraclebuilders.BuildAggregateSQL(query, opts)
+		return sql, nil
+	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
+		sql, _ := oraclebuilders.BuildWindowSQL(query)
+		return sql, nil
+	case "union", "union_all", "intersect", "minus":
+		sql, _, err := oraclebuilders.BuildSetOperationSQL(query, opts)
+		return sql, err
+	case "begin":
+		return oraclebuilders.BuildBeginSQL(), nil
+	case "commit":
+		return "COMMIT", nil
+	case "rollback":
+		return "ROLLBACK", nil
+	case "savepoint":
+		return oraclebuilders.BuildSavepointSQL(query.SavepointName)
+	case "rollback_to":
+		return oraclebuilders.BuildRollbackToSavepointSQL(query.SavepointName)
+	case "set_transaction":
+		return oraclebuilders.BuildSetTransactionSQL(query), nil
+	case "with":
+		sql, _, err := oraclebuilders.BuildCTESQL(query, opts)
+		return sql, err
+	case "subquery", "exists":
+		sql, _, err := oraclebuilders.BuildSubquerySQL(query, opts)
+		return sql, err
+	default:
+		return "", nil
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/omniql-engine/omniql/engine/models"
 	pgbuilders "github.com/omniql-engine/omniql/engine/builders/postgres"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
-	"github.com/jinzhu/inflection"
 )
 
 // ============================================================================
@@ -108,7 +107,12 @@ func mapOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
 // ============================================================================
 
 // TranslatePostgreSQL converts OQL Query to PostgreSQL RelationalQuery (100% TrueAST)
+// with the default PostgreSQL options
 func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	return translatePostgreSQL(query, tenantID, pgbuilders.Options{})
+}
+
+func translatePostgreSQL(query *models.Query, tenantID string, opts pgbuilders.Options) (*pb.RelationalQuery, error) {
 	operation := mapping.OperationMap["PostgreSQL"][query.Operation]
 	table := getPostgreSQLTableName(query.Entity, query.Operation)
 	conditions := mapConditions(query.Conditions)
@@ -122,10 +126,10 @@ func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQu
 	
 	// DQL: Map new advanced fields
 	windowFunctions := mapWindowFunctions(query.WindowFunctions)
-	cte := mapCTE(query.CTE, tenantID)
-	subquery := mapSubquery(query.Subquery, tenantID)
+	cte := mapCTE(query.CTE, tenantID, opts)
+	subquery := mapSubquery(query.Subquery, tenantID, opts)
 	pattern := query.Pattern
-	setOperation, err := mapSetOperation(query.SetOperation, tenantID)
+	setOperation, err := mapSetOperation(query.SetOperation, tenantID, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -147,13 +151,26 @@ func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQu
 	var userName string
 	var password string
 	var userRoles []string
+	var permissionObject string
+	var roleFlags []string
+	var validUntil string
+	var connectionLimit string
 	if query.Permission != nil {
 		permissions = query.Permission.Permissions
+		permissionObject = query.Permission.ObjectType
 		permissionTarget = query.Permission.Target
 		roleName = query.Permission.RoleName
 		userName = query.Permission.UserName
 		password = query.Permission.Password
 		userRoles = query.Permission.Roles
+		roleFlags = query.Permission.RoleFlags
+		validUntil = query.Permission.ValidUntil
+		connectionLimit = query.Permission.ConnectionLimit
+
+		// Non-table objects are named as written, not pluralized
+		if permissionObject != "" {
+			table = query.Entity
+		}
 	}
 	
 	// CRUD: Map UPSERT and BULK INSERT
@@ -162,14 +179,14 @@ func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQu
 	
 	// DDL: Map view and database fields
 	viewName := query.ViewName
-	viewQuery := mapViewQuery(query.ViewQuery, tenantID)
+	viewQuery := mapViewQuery(query.ViewQuery, tenantID, opts)
 	databaseName := query.DatabaseName
 	newName := query.NewName
 	if query.NewName != "" && query.Operation == "RENAME TABLE" {
 		lookupOp := strings.ToUpper(strings.ReplaceAll(query.Operation, "_", " "))
 		rule := mapping.TableNamingRules[lookupOp]
 		if rule == "plural" {
-			newName = inflection.Plural(strings.ToLower(query.NewName))
+			newName = mapping.EntityTable(query.NewName)
 		} else {
 			newName = strings.ToLower(query.NewName)
 		}
@@ -183,6 +200,8 @@ func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQu
 		Limit:      int32(query.Limit),
 		Offset:     int32(query.Offset),
 		Distinct:   query.Distinct,
+		Lock:       query.Lock,
+		LockWait:   query.LockWait,
 		
 		// GROUP 3: DQL
 		Joins:         joins,
@@ -208,14 +227,19 @@ func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQu
 		// GROUP 5: DCL
 		Permissions:      permissions,
 		PermissionTarget: permissionTarget,
+		PermissionObject: permissionObject,
 		RoleName:         roleName,
 		UserName:         userName,
 		Password:         password,
 		UserRoles:        userRoles,
+		RoleFlags:        roleFlags,
+		ValidUntil:       validUntil,
+		ConnectionLimit:  connectionLimit,
 		
 		// CRUD Extensions
-		Upsert:   upsert,
-		BulkData: bulkData,
+		Upsert:    upsert,
+		BulkData:  bulkData,
+		Returning: mapExpressions(query.Returning),
 		
 		// DDL Extensions
 		ViewName:     viewName,
@@ -276,9 +300,39 @@ func TranslatePostgreSQL(query *models.Query, tenantID string) (*pb.RelationalQu
 		CommentText:   query.CommentText,
 
 		Cascade: query.Cascade,
+
+		PartitionStrategy:  query.PartitionStrategy,
+		PartitionKeys:      mapExpressions(query.PartitionKeys),
+		PartitionName:      query.PartitionName,
+		PartitionFrom:      mapExpressions(query.PartitionFrom),
+		PartitionTo:        mapExpressions(query.PartitionTo),
+		PartitionIn:        mapExpressions(query.PartitionIn),
+		PartitionModulus:   query.PartitionModulus,
+		PartitionRemainder: query.PartitionRemainder,
+		PartitionDefault:   query.PartitionDefault,
+
+		Channel: query.Channel,
+		Payload: query.Payload,
+	}
+	
+	// Reject identifiers that cannot be quoted safely (embedded quotes)
+	if err := pgbuilders.ValidateIdentifiers(result); err != nil {
+		return nil, err
+	}
+	if err := pgbuilders.ValidateGrantPrivileges(result); err != nil {
+		return nil, err
+	}
+	// COPY reads values by position, so every row must set the same columns
+	if operation == "bulk_insert" && pgbuilders.UseCopy(result, opts) {
+		if _, _, _, err := pgbuilders.BuildCopyRows(result); err != nil {
+			return nil, err
+		}
 	}
 	
-	result.Sql = buildPostgreSQLString(result)
+	if query.Collation != "" {
+		applyCollation(result, pgbuilders.CollationName(query.Collation, query.CollationStrength))
+	}
+	result.Sql = buildPostgreSQLString(result, opts)
 	
 	return result, nil
 }
@@ -294,9 +348,10 @@ func mapFields(fields []models.Field) []*pb.QueryField {
 	var result []*pb.QueryField
 	for _, field := range fields {
 		result = append(result, &pb.QueryField{
-			NameExpr:    mapExpression(field.NameExpr),
-			ValueExpr:   mapExpression(field.ValueExpr),
-			Constraints: field.Constraints,
+			NameExpr:      mapExpression(field.NameExpr),
+			ValueExpr:     mapExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapExpression(field.GeneratedExpr),
 		})
 	}
 	return result
@@ -307,7 +362,7 @@ func getPostgreSQLTableName(entity string, operation string) string {
 	rule := mapping.TableNamingRules[lookupOp]
 	
 	if rule == "plural" {
-		return inflection.Plural(strings.ToLower(entity))
+		return mapping.EntityTable(entity)
 	}
 	if rule == "none" {
 		return ""
@@ -324,10 +379,21 @@ func mapUpsert(upsert *models.Upsert) *pb.UpsertClause {
 		return nil
 	}
 	return &pb.UpsertClause{
-		ConflictFields: mapExpressions(upsert.ConflictFields),
-		UpdateFields:   mapFields(upsert.UpdateFields),
-		ConflictAction: "UPDATE",
+		ConflictFields:     mapExpressions(upsert.ConflictFields),
+		UpdateFields:       mapFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
+		ConflictWhere:      mapConditions(upsert.ConflictWhere),
+	}
+}
+
+// conflictAction is what an UPSERT does with a conflicting row: NOTHING for
+// DO NOTHING, otherwise the database's update action
+func conflictAction(upsert *models.Upsert, update string) string {
+	if upsert.DoNothing {
+		return "NOTHING"
 	}
+	return update
 }
 
 func mapBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
@@ -374,6 +440,8 @@ func mapAggregate(agg *models.Aggregation) *pb.AggregateClause {
 	return &pb.AggregateClause{
 		Function:  string(agg.Function),
 		FieldExpr: mapExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapOrderByClauses(agg.OrderBy),
 	}
 }
 
@@ -388,13 +456,17 @@ func mapWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause
 	var result []*pb.WindowClause
 	for _, wf := range windowFuncs {
 		result = append(result, &pb.WindowClause{
-			Function:    string(wf.Function),
-			FieldExpr:   mapExpression(wf.FieldExpr),
-			Alias:       wf.Alias,
-			PartitionBy: mapExpressions(wf.PartitionBy),
-			OrderBy:     mapOrderByClauses(wf.OrderBy),
-			Offset:      int32(wf.Offset),
-			Buckets:     int32(wf.Buckets),
+			Function:     string(wf.Function),
+			FieldExpr:    mapExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapExpressions(wf.PartitionBy),
+			OrderBy:      mapOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
 		})
 	}
 	return result
@@ -404,20 +476,38 @@ func mapWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause
 // CTE MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapCTE(cte *models.CTE, tenantID string) *pb.CTEClause {
+func mapCTE(cte *models.CTE, tenantID string, opts pgbuilders.Options) *pb.CTEClause {
 	if cte == nil {
 		return nil
 	}
 	
 	var cteQuery *pb.RelationalQuery
 	if cte.Query != nil {
-		cteQuery, _ = TranslatePostgreSQL(cte.Query, tenantID)
+		cteQuery, _ = translatePostgreSQL(cte.Query, tenantID, opts)
+	}
+	
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		mainQuery, _ = translatePostgreSQL(main, tenantID, opts)
+		pointAtCTE(main, mainQuery, cte.Name)
+	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
 	}
 	
 	return &pb.CTEClause{
 		CteName:   cte.Name,
 		CteQuery:  cteQuery,
 		Recursive: cte.Recursive,
+		MainQuery: mainQuery,
 	}
 }
 
@@ -425,14 +515,14 @@ func mapCTE(cte *models.CTE, tenantID string) *pb.CTEClause {
 // SUBQUERY MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapSubquery(subquery *models.Subquery, tenantID string) *pb.SubqueryClause {
+func mapSubquery(subquery *models.Subquery, tenantID string, opts pgbuilders.Options) *pb.SubqueryClause {
 	if subquery == nil {
 		return nil
 	}
 	
 	var subqueryQuery *pb.RelationalQuery
 	if subquery.Query != nil {
-		subqueryQuery, _ = TranslatePostgreSQL(subquery.Query, tenantID)
+		subqueryQuery, _ = translatePostgreSQL(subquery.Query, tenantID, opts)
 	}
 	
 	return &pb.SubqueryClause{
@@ -447,17 +537,17 @@ func mapSubquery(subquery *models.Subquery, tenantID string) *pb.SubqueryClause
 // SET OPERATION MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapSetOperation(setOp *models.SetOperation, tenantID string) (*pb.SetOperationClause, error) {
+func mapSetOperation(setOp *models.SetOperation, tenantID string, opts pgbuilders.Options) (*pb.SetOperationClause, error) {
 	if setOp == nil {
 		return nil, nil
 	}
 	
-	leftQuery, err := TranslatePostgreSQL(setOp.LeftQuery, tenantID)
+	leftQuery, err := translatePostgreSQL(setOp.LeftQuery, tenantID, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to translate left query: %w", err)
 	}
 	
-	rightQuery, err := TranslatePostgreSQL(setOp.RightQuery, tenantID)
+	rightQuery, err := translatePostgreSQL(setOp.RightQuery, tenantID, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to translate right query: %w", err)
 	}
@@ -491,11 +581,11 @@ func mapSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
 // VIEW QUERY MAPPING (100% TrueAST)
 // ============================================================================
 
-func mapViewQuery(viewQuery *models.Query, tenantID string) *pb.RelationalQuery {
+func mapViewQuery(viewQuery *models.Query, tenantID string, opts pgbuilders.Options) *pb.RelationalQuery {
 	if viewQuery == nil {
 		return nil
 	}
-	result, _ := TranslatePostgreSQL(viewQuery, tenantID)
+	result, _ := translatePostgreSQL(viewQuery, tenantID, opts)
 	return result
 }
 
@@ -503,7 +593,7 @@ func mapViewQuery(viewQuery *models.Query, tenantID string) *pb.RelationalQuery
 // SQL STRING BUILDER (unchanged)
 // ============================================================================
 
-func buildPostgreSQLString(query *pb.RelationalQuery) string {
+func buildPostgreSQLString(query *pb.RelationalQuery, opts pgbuilders.Options) string {
 	operation := strings.ToLower(query.Operation)
 	
 	switch operation {
@@ -523,8 +613,14 @@ func buildPostgreSQLString(query *pb.RelationalQuery) string {
 		sql, _ := pgbuilders.BuildUpsertSQL(query)
 		return sql
 	case "bulk_insert":
+		if pgbuilders.UseCopy(query, opts) {
+			return pgbuilders.BuildCopySQL(query)
+		}
 		sql, _ := pgbuilders.BuildBulkInsertSQL(query)
 		return sql
+	case "bulk_upsert":
+		sql, _ := pgbuilders.BuildBulkUpsertSQL(query)
+		return sql
 	case "create_table":
 		return pgbuilders.BuildCreateTableSQL(query)
 	case "alter_table":
@@ -537,6 +633,14 @@ func buildPostgreSQLString(query *pb.RelationalQuery) string {
 	case "alter_table_rename":
 		sql, _ := pgbuilders.BuildRenameTableSQL(query)
 		return sql
+	case "show_tables":
+		return pgbuilders.BuildShowTablesSQL()
+	case "show_columns":
+		sql, _ := pgbuilders.BuildShowColumnsSQL(query)
+		return sql
+	case "show_indexes":
+		sql, _ := pgbuilders.BuildShowIndexesSQL(query)
+		return sql
 	case "create_index":
 		sql, _ := pgbuilders.BuildCreateIndexSQL(query)
 		return sql
@@ -561,7 +665,12 @@ func buildPostgreSQLString(query *pb.RelationalQuery) string {
 	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
 		sql, _ := pgbuilders.BuildJoinSQL(query)
 		return sql
-	case "count", "sum", "avg", "min", "max":
+	case "count", "sum", "avg", "min", "max", "string_agg":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _ := pgbuilders.BuildWindowFunctionSQL(query)
+			return sql
+		}
 		sql, _ := pgbuilders.BuildAggregateSQL(query)
 		return sql
 	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
@@ -599,6 +708,9 @@ func buildPostgreSQLString(query *pb.RelationalQuery) string {
 	case "create_role":
 		sql, _ := pgbuilders.BuildCreateRoleSQL(query)
 		return sql
+	case "alter_role":
+		sql, _ := pgbuilders.BuildAlterRoleSQL(query)
+		return sql
 	case "drop_role":
 		sql, _ := pgbuilders.BuildDropRoleSQL(query)
 		return sql
@@ -627,10 +739,24 @@ func buildPostgreSQLString(query *pb.RelationalQuery) string {
 		sql, _ := pgbuilders.BuildSetTransactionSQL(query)
 		return sql
 
+	// Pub/sub
+	case "listen":
+		sql, _ := pgbuilders.BuildListenSQL(query)
+		return sql
+	case "unlisten":
+		sql, _ := pgbuilders.BuildUnlistenSQL(query)
+		return sql
+	case "notify":
+		sql, _ := pgbuilders.BuildNotifySQL(query)
+		return sql
+
 	// PostgreSQL-specific DDL
 	case "create_sequence":
 		sql, _ := pgbuilders.BuildCreateSequenceSQL(query)
 		return sql
+	case "create_partition":
+		sql, _ := pgbuilders.BuildCreatePartitionSQL(query)
+		return sql
 	case "alter_sequence":
 		sql, _ := pgbuilders.BuildAlterSequenceSQL(query)
 		return sql
@@ -1,10 +1,13 @@
 package translator
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"github.com/omniql-engine/omniql/mapping"
 	"github.com/omniql-engine/omniql/engine/models"
+	redisbuilders "github.com/omniql-engine/omniql/engine/builders/redis"
 	pb "github.com/omniql-engine/omniql/utilities/proto"
 )
 
@@ -47,11 +50,16 @@ func buildExpressionValue(expr *models.Expression) string {
 // MAIN TRANSLATOR
 // ============================================================================
 
-// TranslateRedis converts OQL Query to Redis KeyValueQuery
+// TranslateRedis converts OQL Query to Redis KeyValueQuery with the default
+// Redis options
 func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, error) {
+	return translateRedis(query, tenantID, redisbuilders.Options{})
+}
+
+func translateRedis(query *models.Query, tenantID string, opts redisbuilders.Options) (*pb.KeyValueQuery, error) {
 	// Check for unsupported operations first
 	switch query.Operation {
-	case "CREATE ROLE", "DROP ROLE", "ASSIGN ROLE", "REVOKE ROLE":
+	case "CREATE ROLE", "ALTER ROLE", "DROP ROLE", "ASSIGN ROLE", "REVOKE ROLE":
 		return nil, fmt.Errorf("Redis does not support role operations. Use CREATE USER with permissions instead")
 	}
 
@@ -94,7 +102,7 @@ func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, er
 		
 		result := &pb.KeyValueQuery{
 			Command:    command,
-			Key:        buildRedisKeyPattern(tenantID, query.Entity),
+			Key:        buildRedisKeyPattern(tenantID, query.Entity, opts),
 			Args:       args,
 			Entity:     strings.ToLower(query.Entity),
 			Conditions: convertConditionsToProto(query.Conditions),
@@ -110,7 +118,7 @@ func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, er
 	if query.Operation == "DROP TABLE" {
 		result := &pb.KeyValueQuery{
 			Command: "DROP_TABLE",
-			Key:     buildRedisKeyPattern(tenantID, query.Entity),
+			Key:     buildRedisKeyPattern(tenantID, query.Entity, opts),
 			Args:    []string{},
 			Entity:  strings.ToLower(query.Entity),
 		}
@@ -120,7 +128,7 @@ func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, er
 
 	// Special handling for BULK INSERT
 	if query.Operation == "BULK INSERT" {
-		result, err := buildBulkInsert(query, tenantID)
+		result, err := buildBulkInsert(query, tenantID, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -128,6 +136,14 @@ func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, er
 		return result, nil
 	}
 
+	// RediSearch: CREATE TABLE creates the index and GET searches it
+	if opts.RediSearch {
+		if result, ok := buildRediSearch(query, tenantID, opts); ok {
+			result.CommandString = buildRedisString(result)
+			return result, nil
+		}
+	}
+
 	command := mapping.OperationMap["Redis"][query.Operation]
 	if command == "" {
 		return nil, fmt.Errorf("operation %s not supported in Redis", query.Operation)
@@ -138,11 +154,11 @@ func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, er
 	var key string
 	
 	if command == "ACL" {
-		args = buildACLArgs(query, tenantID)
+		args = buildACLArgs(query, tenantID, opts)
 		key = ""
 	} else {
-		key = buildRedisKey(tenantID, query.Entity, query.Conditions, query.Fields, query.Operation)
-		args = buildRedisArgs(query, command, tenantID)
+		key = buildRedisKey(tenantID, query.Entity, query.Conditions, query.Fields, query.Operation, opts)
+		args = buildRedisArgs(query, command, tenantID, opts)
 	}
 	
 	result := &pb.KeyValueQuery{
@@ -154,12 +170,36 @@ func TranslateRedis(query *models.Query, tenantID string) (*pb.KeyValueQuery, er
 		Limit:      int32(query.Limit),
 		Offset:     int32(query.Offset),
 		OrderBy:    convertOrderByToProto(query.OrderBy),
+		TtlMs:      query.TTL,
+	}
+	if query.Operation == "UPSERT" && query.Upsert != nil {
+		if query.Upsert.ConflictConstraint != "" || len(query.Upsert.ConflictWhere) > 0 {
+			return nil, fmt.Errorf("Redis UPSERT supports ON field list only, not ON CONSTRAINT or a conflict WHERE")
+		}
+		result.Upsert = mapRedisUpsert(query.Upsert)
 	}
 	result.CommandString = buildRedisString(result)
 	return result, nil
 }
 
-func buildBulkInsert(query *models.Query, tenantID string) (*pb.KeyValueQuery, error) {
+// mapRedisUpsert converts the conflict fields and the fields updated on
+// conflict; UPDATE SET values become strings like UPDATE's HSET arguments
+func mapRedisUpsert(upsert *models.Upsert) *pb.UpsertClause {
+	clause := &pb.UpsertClause{ConflictAction: "UPSERT"}
+	for _, field := range upsert.ConflictFields {
+		clause.ConflictFields = append(clause.ConflictFields, convertExprToProto(field))
+	}
+	for _, field := range upsert.UpdateFields {
+		update := &pb.QueryField{NameExpr: convertExprToProto(field.NameExpr)}
+		if field.ValueExpr != nil {
+			update.ValueExpr = &pb.Expression{Type: field.ValueExpr.Type, Value: buildExpressionValue(field.ValueExpr)}
+		}
+		clause.UpdateFields = append(clause.UpdateFields, update)
+	}
+	return clause
+}
+
+func buildBulkInsert(query *models.Query, tenantID string, opts redisbuilders.Options) (*pb.KeyValueQuery, error) {
 	entityLower := strings.ToLower(query.Entity)
 	var pairs []*pb.KeyValuePair
 	
@@ -169,7 +209,7 @@ func buildBulkInsert(query *models.Query, tenantID string) (*pb.KeyValueQuery, e
 			keyId = idField
 		}
 		
-		fullKey := fmt.Sprintf("tenant:%s:%s:%s", tenantID, entityLower, keyId)
+		fullKey := redisbuilders.RecordKey(tenantID, entityLower, keyId, opts)
 		jsonValue := fieldsToJSON(row)
 		
 		pairs = append(pairs, &pb.KeyValuePair{
@@ -181,14 +221,74 @@ func buildBulkInsert(query *models.Query, tenantID string) (*pb.KeyValueQuery, e
 	return &pb.KeyValueQuery{
 		Command:   "BULK INSERT",
 		BulkPairs: pairs,
+		Entity:    entityLower,
 	}, nil
 }
 
+// redisSearchOperation reports whether RediSearch adds operation to Redis:
+// CREATE TABLE creates the entity's index
+func redisSearchOperation(dbType, operation string, opts redisbuilders.Options) bool {
+	return dbType == "Redis" && opts.RediSearch && operation == "CREATE TABLE"
+}
+
+// redisSearchOperator reports whether RediSearch adds operator to Redis:
+// SEARCH is a full-text match in FT.SEARCH
+func redisSearchOperator(dbType, operator string, opts redisbuilders.Options) bool {
+	return dbType == "Redis" && opts.RediSearch && operator == "SEARCH"
+}
+
+// buildRediSearch builds FT.CREATE for CREATE TABLE and FT.SEARCH for a GET
+// the index can answer; ok is false for anything else
+func buildRediSearch(query *models.Query, tenantID string, opts redisbuilders.Options) (*pb.KeyValueQuery, bool) {
+	entityLower := strings.ToLower(query.Entity)
+	index := redisbuilders.SearchIndexName(tenantID, query.Entity)
+
+	switch query.Operation {
+	case "CREATE TABLE":
+		var fields []redisbuilders.SearchField
+		for _, field := range query.Fields {
+			fields = append(fields, redisbuilders.SearchField{
+				Name: getExprValue(field.NameExpr),
+				Type: getExprValue(field.ValueExpr),
+			})
+		}
+		prefix := redisbuilders.EntityKeyPrefix(tenantID, entityLower, opts)
+		return &pb.KeyValueQuery{
+			Command: "FT.CREATE",
+			Key:     index,
+			Args:    redisbuilders.BuildSearchCreateArgs(prefix, fields),
+			Entity:  entityLower,
+		}, true
+
+	case "GET":
+		if isDirectIdLookup(query.Conditions) {
+			return nil, false
+		}
+		conditions := convertConditionsToProto(query.Conditions)
+		orderBy := convertOrderByToProto(query.OrderBy)
+		args, ok := redisbuilders.BuildSearchArgs(conditions, orderBy, query.Limit, query.Offset)
+		if !ok {
+			return nil, false
+		}
+		return &pb.KeyValueQuery{
+			Command:    "FT.SEARCH",
+			Key:        index,
+			Args:       args,
+			Entity:     entityLower,
+			Conditions: conditions,
+			Limit:      int32(query.Limit),
+			Offset:     int32(query.Offset),
+			OrderBy:    orderBy,
+		}, true
+	}
+	return nil, false
+}
+
 // ============================================================================
 // KEY BUILDING (100% TrueAST)
 // ============================================================================
 
-func buildRedisKey(tenantID, entity string, conditions []models.Condition, fields []models.Field, operation string) string {
+func buildRedisKey(tenantID, entity string, conditions []models.Condition, fields []models.Field, operation string, opts redisbuilders.Options) string {
 	if operation == "DELETE" && len(conditions) == 0 && len(fields) == 0 {
 		return ""
 	}
@@ -202,12 +302,12 @@ func buildRedisKey(tenantID, entity string, conditions []models.Condition, field
 	// Case 1: Direct ID lookup (WHERE id = X)
 	if isDirectIdLookup(conditions) {
 		condValue := getExprValue(conditions[0].ValueExpr)
-		return fmt.Sprintf("tenant:%s:%s:%s", tenantID, entityLower, condValue)
+		return redisbuilders.RecordKey(tenantID, entityLower, condValue, opts)
 	}
 	
 	// Case 2: Other conditions → return pattern for scanning
 	if len(conditions) > 0 {
-		return fmt.Sprintf("tenant:%s:%s:*", tenantID, entityLower)
+		return redisbuilders.KeyPattern(tenantID, entityLower, opts)
 	}
 	
 	// Check if there's an id field (for CREATE)
@@ -215,16 +315,16 @@ func buildRedisKey(tenantID, entity string, conditions []models.Condition, field
 		nameValue := getExprValue(field.NameExpr)
 		if strings.ToLower(nameValue) == "id" {
 			fieldValue := getExprValue(field.ValueExpr)
-			return fmt.Sprintf("tenant:%s:%s:%s", tenantID, entityLower, fieldValue)
+			return redisbuilders.RecordKey(tenantID, entityLower, fieldValue, opts)
 		}
 	}
 	
 	// For CREATE without ID, generate a key
 	if operation == "CREATE" || operation == "UPDATE" || operation == "UPSERT" || operation == "REPLACE" {
-		return fmt.Sprintf("tenant:%s:%s:generated_%d", tenantID, entityLower, generateID())
+		return redisbuilders.RecordKey(tenantID, entityLower, fmt.Sprintf("generated_%d", generateID()), opts)
 	}
 	
-	return fmt.Sprintf("tenant:%s:%s:*", tenantID, entityLower)
+	return redisbuilders.KeyPattern(tenantID, entityLower, opts)
 }
 
 var idCounter int
@@ -237,13 +337,13 @@ func generateID() int {
 // ACL ARGUMENT BUILDING (100% TrueAST)
 // ============================================================================
 
-func buildACLArgs(query *models.Query, tenantID string) []string {
+func buildACLArgs(query *models.Query, tenantID string, opts redisbuilders.Options) []string {
 	var args []string
 	
 	switch query.Operation {
 	case "CREATE USER":
 		args = append(args, "SETUSER")
-		args = append(args, buildACLSetUserArgs(query, tenantID)...)
+		args = append(args, buildACLSetUserArgs(query, tenantID, opts)...)
 		
 	case "DROP USER":
 		args = append(args, "DELUSER")
@@ -264,7 +364,7 @@ func buildACLArgs(query *models.Query, tenantID string) []string {
 		
 	case "ALTER USER":
 		args = append(args, "SETUSER")
-		args = append(args, buildACLSetUserArgs(query, tenantID)...)
+		args = append(args, buildACLSetUserArgs(query, tenantID, opts)...)
 		
 	case "GRANT":
 		args = append(args, "SETUSER")
@@ -277,7 +377,7 @@ func buildACLArgs(query *models.Query, tenantID string) []string {
 				args = append(args, target)
 				args = append(args, "resetkeys")
 				args = append(args, "on")
-				args = append(args, fmt.Sprintf("~tenant:%s:*", tenantID))
+				args = append(args, "~"+redisbuilders.TenantKeyPattern(tenantID, opts))
 				
 				for _, perm := range query.Permission.Permissions {
 					switch strings.ToUpper(perm) {
@@ -328,7 +428,7 @@ func buildACLArgs(query *models.Query, tenantID string) []string {
 	return args
 }
 
-func buildACLSetUserArgs(query *models.Query, tenantID string) []string {
+func buildACLSetUserArgs(query *models.Query, tenantID string, opts redisbuilders.Options) []string {
 	var args []string
 	
 	if query.Permission == nil {
@@ -343,7 +443,7 @@ func buildACLSetUserArgs(query *models.Query, tenantID string) []string {
 		args = append(args, ">"+query.Permission.Password)
 	}
 	
-	args = append(args, fmt.Sprintf("~tenant:%s:*", tenantID))
+	args = append(args, "~"+redisbuilders.TenantKeyPattern(tenantID, opts))
 	args = append(args, "+get", "+set", "+del", "+exists", "+ttl", "+expire")
 	args = append(args, "+hget", "+hset", "+hgetall", "+hdel", "+hmset")
 	args = append(args, "+lpush", "+lpop", "+lrange", "+llen")
@@ -357,7 +457,7 @@ func buildACLSetUserArgs(query *models.Query, tenantID string) []string {
 // REGULAR ARGUMENT BUILDING (100% TrueAST)
 // ============================================================================
 
-func buildRedisArgs(query *models.Query, command string, tenantID string) []string {
+func buildRedisArgs(query *models.Query, command string, tenantID string, opts redisbuilders.Options) []string {
 	var args []string
 	
 	switch command {
@@ -379,7 +479,7 @@ func buildRedisArgs(query *models.Query, command string, tenantID string) []stri
 		}
 		
 	case "MSET":
-		args = buildBulkSetArgs(query, tenantID)
+		args = buildBulkSetArgs(query, tenantID, opts)
 		
 	case "MULTI", "EXEC", "DISCARD":
 		// Transaction commands (no args)
@@ -437,7 +537,7 @@ func buildSetArgs(query *models.Query) []string {
 	return args
 }
 
-func buildBulkSetArgs(query *models.Query, tenantID string) []string {
+func buildBulkSetArgs(query *models.Query, tenantID string, opts redisbuilders.Options) []string {
 	var args []string
 	entityLower := strings.ToLower(query.Entity)
 	
@@ -447,7 +547,7 @@ func buildBulkSetArgs(query *models.Query, tenantID string) []string {
 			keyId = idField
 		}
 		
-		fullKey := fmt.Sprintf("tenant:%s:%s:%s", tenantID, entityLower, keyId)
+		fullKey := redisbuilders.RecordKey(tenantID, entityLower, keyId, opts)
 		args = append(args, fullKey)
 		
 		jsonValue := fieldsToJSON(row)
@@ -478,11 +578,7 @@ func buildGenericArgs(query *models.Query) []string {
 // ============================================================================
 
 func fieldsToJSON(fields []models.Field) string {
-	if len(fields) == 0 {
-		return "{}"
-	}
-	
-	var pairs []string
+	values := make(map[string]string, len(fields))
 	for _, field := range fields {
 		nameValue := getExprValue(field.NameExpr)
 		var valueValue string
@@ -491,10 +587,12 @@ func fieldsToJSON(fields []models.Field) string {
 		} else {
 			valueValue = getExprValue(field.ValueExpr)
 		}
-		pairs = append(pairs, fmt.Sprintf(`"%s":"%s"`, nameValue, valueValue))
+		values[nameValue] = valueValue
 	}
 	
-	return "{" + strings.Join(pairs, ",") + "}"
+	// Marshal escapes quotes and backslashes in the values
+	jsonBytes, _ := json.Marshal(values)
+	return string(jsonBytes)
 }
 
 func getFieldValue(fields []models.Field, name string) string {
@@ -507,9 +605,9 @@ func getFieldValue(fields []models.Field, name string) string {
 	return ""
 }
 
-func buildRedisKeyPattern(tenantID, entity string) string {
+func buildRedisKeyPattern(tenantID, entity string, opts redisbuilders.Options) string {
 	entityLower := strings.ToLower(entity)
-	return fmt.Sprintf("tenant:%s:%s:*", tenantID, entityLower)
+	return redisbuilders.KeyPattern(tenantID, entityLower, opts)
 }
 
 func buildRedisString(query *pb.KeyValueQuery) string {
@@ -529,6 +627,11 @@ func buildRedisString(query *pb.KeyValueQuery) string {
 		
 	case "DROP_TABLE":
 		return fmt.Sprintf("DEL %s", query.Key)
+
+	case "FT.SEARCH":
+		// The query is one argument and may hold spaces
+		return strings.TrimSpace(fmt.Sprintf("FT.SEARCH %s %s %s",
+			query.Key, strconv.Quote(query.Args[0]), strings.Join(query.Args[1:], " ")))
 		
 	case "COUNT", "SUM", "AVG", "MIN", "MAX":
 		if len(query.Args) > 0 {
@@ -546,6 +649,11 @@ func buildRedisString(query *pb.KeyValueQuery) string {
 	if len(query.Args) > 0 {
 		parts = append(parts, query.Args...)
 	}
+
+	// TTL expires the written key in the same MULTI/EXEC
+	if query.TtlMs > 0 {
+		return strings.Join(parts, " ") + "\n" + strings.Join(redisbuilders.ExpireArgs(query.Key, query.TtlMs), " ")
+	}
 	
 	return strings.Join(parts, " ")
 }
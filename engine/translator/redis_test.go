@@ -0,0 +1,37 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/parser"
+)
+
+// OQL translates to Redis commands on the tenant's record keys
+func TestRedisCommands(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`GET User WHERE id = 1`, "HGETALL tenant:t1:user:1"},
+		{`GET User WHERE age > 21`, "HGETALL tenant:t1:user:*"},
+		{`CREATE User WITH id = 1, name = "Alice"`, "HMSET tenant:t1:user:1 name Alice"},
+		{`CREATE Session WITH id = "abc", user_id = 42 TTL 30m`,
+			"HMSET tenant:t1:session:abc user_id 42\nEXPIRE tenant:t1:session:abc 1800"},
+		{`UPDATE User SET age = 31 WHERE id = 1`, "HSET tenant:t1:user:1 age 31"},
+		{`DELETE User WHERE id = 1`, "DEL tenant:t1:user:1"},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := Translate(query, "Redis", "t1")
+		if err != nil {
+			t.Errorf("Translate(%q): %v", tt.input, err)
+			continue
+		}
+		if got := result.GetKeyValue().GetCommandString(); got != tt.want {
+			t.Errorf("Translate(%q):\n got %q\nwant %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,525 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	sfbuilders "github.com/omniql-engine/omniql/engine/builders/snowflake"
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	return &pb.Expression{
+		Type:           expr.Type,
+		Value:          expr.Value,
+		Left:           mapSnowflakeExpression(expr.Left),
+		Operator:       expr.Operator,
+		Right:          mapSnowflakeExpression(expr.Right),
+		FunctionName:   expr.FunctionName,
+		FunctionArgs:   mapSnowflakeExpressions(expr.FunctionArgs),
+		CaseConditions: mapSnowflakeCaseConditions(expr.CaseConditions),
+		CaseElse:       mapSnowflakeExpression(expr.CaseElse),
+	}
+}
+
+func mapSnowflakeExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapSnowflakeExpression(expr))
+	}
+	return result
+}
+
+func mapSnowflakeCaseConditions(conditions []*models.CaseCondition) []*pb.CaseCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.CaseCondition
+	for _, cc := range conditions {
+		result = append(result, &pb.CaseCondition{
+			Condition: mapSnowflakeCondition(cc.Condition),
+			ThenExpr:  mapSnowflakeExpression(cc.ThenExpr),
+		})
+	}
+	return result
+}
+
+func mapSnowflakeCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapSnowflakeExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapSnowflakeExpression(cond.ValueExpr),
+		Value2Expr: mapSnowflakeExpression(cond.Value2Expr),
+		ValuesExpr: mapSnowflakeExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapSnowflakeConditions(cond.Nested),
+	}
+}
+
+func mapSnowflakeConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapSnowflakeCondition(&cond))
+	}
+	return result
+}
+
+func mapSnowflakeOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapSnowflakeExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+// TranslateSnowflake converts OQL Query to Snowflake RelationalQuery.
+// Writes that Snowflake cannot express are rejected here rather than built:
+// DML returns no rows and takes no row locks, and tables are divided into
+// micro-partitions by Snowflake itself (CLUSTER BY orders them).
+func TranslateSnowflake(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	operation := mapping.OperationMap["Snowflake"][query.Operation]
+	if len(query.Returning) > 0 {
+		return nil, &mapping.ErrNotSupported{Database: "Snowflake", Feature: "RETURNING"}
+	}
+	if query.Lock != "" {
+		return nil, &mapping.ErrNotSupported{Database: "Snowflake", Feature: "FOR " + query.Lock}
+	}
+	if query.PartitionStrategy != "" {
+		return nil, &mapping.ErrNotSupported{Database: "Snowflake", Feature: "PARTITION BY " + query.PartitionStrategy}
+	}
+	for _, point := range []string{query.AsOf, query.Before} {
+		if err := sfbuilders.CheckTimeTravel(point); err != nil {
+			return nil, err
+		}
+	}
+
+	table := TableName(query.Entity, query.Operation)
+	conditions := mapSnowflakeConditions(query.Conditions)
+	fields := mapSnowflakeFields(query.Fields)
+
+	// DQL: Map fields
+	joins := mapSnowflakeJoins(query.Joins)
+	aggregate := mapSnowflakeAggregate(query.Aggregate)
+	orderBy := mapSnowflakeOrderByClauses(query.OrderBy)
+	having := mapSnowflakeConditions(query.Having)
+
+	// DQL: Advanced fields
+	windowFunctions := mapSnowflakeWindowFunctions(query.WindowFunctions)
+	cte, err := mapSnowflakeCTE(query.CTE, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	subquery, err := mapSnowflakeSubquery(query.Subquery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	setOperation, err := mapSnowflakeSetOperation(query.SetOperation, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// DDL
+	viewQuery, err := mapSnowflakeViewQuery(query.ViewQuery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	newName := query.NewName
+	if query.NewName != "" && query.Operation == "RENAME TABLE" {
+		newName = TableName(query.NewName, query.Operation)
+	}
+
+	result := &pb.RelationalQuery{
+		Operation:  operation,
+		Table:      table,
+		Conditions: conditions,
+		Fields:     fields,
+		Limit:      int32(query.Limit),
+		Offset:     int32(query.Offset),
+		Distinct:   query.Distinct,
+		AsOf:       query.AsOf,
+		Before:     query.Before,
+
+		// DQL
+		Joins:           joins,
+		Columns:         mapSnowflakeExpressions(query.Columns),
+		SelectColumns:   mapSnowflakeSelectColumns(query.SelectColumns),
+		Aggregate:       aggregate,
+		OrderBy:         orderBy,
+		GroupBy:         mapSnowflakeExpressions(query.GroupBy),
+		Having:          having,
+		WindowFunctions: windowFunctions,
+		Cte:             cte,
+		Subquery:        subquery,
+		Pattern:         query.Pattern,
+		SetOperation:    setOperation,
+
+		// CRUD Extensions
+		Upsert:   mapSnowflakeUpsert(query.Upsert),
+		BulkData: mapSnowflakeBulkData(query.BulkData),
+
+		// DDL
+		ViewName:     query.ViewName,
+		ViewQuery:    viewQuery,
+		NewName:      newName,
+		AlterAction:  query.AlterAction,
+		Cascade:      query.Cascade,
+		ClusterKeys:  mapSnowflakeExpressions(query.ClusterKeys),
+		TableOptions: query.TableOptions,
+		SchemaName:   query.SchemaName,
+		DatabaseName: query.DatabaseName,
+	}
+
+	sql, err := buildSnowflakeString(result)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = sql
+	return result, nil
+}
+
+// ============================================================================
+// FIELD MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeFields(fields []models.Field) []*pb.QueryField {
+	if len(fields) == 0 {
+		return nil
+	}
+	var result []*pb.QueryField
+	for _, field := range fields {
+		result = append(result, &pb.QueryField{
+			NameExpr:      mapSnowflakeExpression(field.NameExpr),
+			ValueExpr:     mapSnowflakeExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapSnowflakeExpression(field.GeneratedExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CRUD EXTENSIONS (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeUpsert(upsert *models.Upsert) *pb.UpsertClause {
+	if upsert == nil {
+		return nil
+	}
+	return &pb.UpsertClause{
+		ConflictFields:     mapSnowflakeExpressions(upsert.ConflictFields),
+		UpdateFields:       mapSnowflakeFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
+		ConflictWhere:      mapSnowflakeConditions(upsert.ConflictWhere),
+	}
+}
+
+func mapSnowflakeBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
+	if len(bulkData) == 0 {
+		return nil
+	}
+	var result []*pb.BulkInsertRow
+	for _, row := range bulkData {
+		result = append(result, &pb.BulkInsertRow{
+			Fields: mapSnowflakeFields(row),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// JOIN MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeJoins(joins []models.Join) []*pb.JoinClause {
+	if len(joins) == 0 {
+		return nil
+	}
+	var result []*pb.JoinClause
+	for _, join := range joins {
+		result = append(result, &pb.JoinClause{
+			JoinType:  string(join.Type),
+			Table:     TableName(join.Table, "GET"),
+			LeftExpr:  mapSnowflakeExpression(join.LeftExpr),
+			RightExpr: mapSnowflakeExpression(join.RightExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// AGGREGATE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapSnowflakeExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapSnowflakeOrderByClauses(agg.OrderBy),
+	}
+}
+
+// ============================================================================
+// WINDOW FUNCTIONS (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause {
+	if len(windowFuncs) == 0 {
+		return nil
+	}
+	var result []*pb.WindowClause
+	for _, wf := range windowFuncs {
+		result = append(result, &pb.WindowClause{
+			Function:     string(wf.Function),
+			FieldExpr:    mapSnowflakeExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapSnowflakeExpressions(wf.PartitionBy),
+			OrderBy:      mapSnowflakeOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapSnowflakeExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CTE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeCTE(cte *models.CTE, tenantID string) (*pb.CTEClause, error) {
+	if cte == nil {
+		return nil, nil
+	}
+	var cteQuery *pb.RelationalQuery
+	if cte.Query != nil {
+		var err error
+		if cteQuery, err = TranslateSnowflake(cte.Query, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		var err error
+		if mainQuery, err = TranslateSnowflake(main, tenantID); err != nil {
+			return nil, err
+		}
+		pointAtCTE(main, mainQuery, cte.Name)
+	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
+	}
+
+	return &pb.CTEClause{
+		CteName:   cte.Name,
+		CteQuery:  cteQuery,
+		Recursive: cte.Recursive,
+		MainQuery: mainQuery,
+	}, nil
+}
+
+// ============================================================================
+// SUBQUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeSubquery(subquery *models.Subquery, tenantID string) (*pb.SubqueryClause, error) {
+	if subquery == nil {
+		return nil, nil
+	}
+	var subqueryQuery *pb.RelationalQuery
+	if subquery.Query != nil {
+		var err error
+		if subqueryQuery, err = TranslateSnowflake(subquery.Query, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.SubqueryClause{
+		SubqueryType: subquery.Type,
+		FieldExpr:    mapSnowflakeExpression(subquery.FieldExpr),
+		Subquery:     subqueryQuery,
+		Alias:        subquery.Alias,
+	}, nil
+}
+
+// ============================================================================
+// SET OPERATION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeSetOperation(setOp *models.SetOperation, tenantID string) (*pb.SetOperationClause, error) {
+	if setOp == nil {
+		return nil, nil
+	}
+	leftQuery, err := TranslateSnowflake(setOp.LeftQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate left query: %w", err)
+	}
+	rightQuery, err := TranslateSnowflake(setOp.RightQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate right query: %w", err)
+	}
+	return &pb.SetOperationClause{
+		OperationType: string(setOp.Type),
+		LeftQuery:     leftQuery,
+		RightQuery:    rightQuery,
+	}, nil
+}
+
+// ============================================================================
+// SELECT COLUMNS MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapSnowflakeExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// VIEW QUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSnowflakeViewQuery(viewQuery *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	if viewQuery == nil {
+		return nil, nil
+	}
+	return TranslateSnowflake(viewQuery, tenantID)
+}
+
+// ============================================================================
+// SQL STRING BUILDER
+// ============================================================================
+
+func buildSnowflakeString(query *pb.RelationalQuery) (string, error) {
+	operation := strings.ToLower(query.Operation)
+
+	switch operation {
+	case "select":
+		sql, _, err := sfbuilders.BuildSelectSQL(query)
+		return sql, err
+	case "insert":
+		sql, _, err := sfbuilders.BuildInsertSQL(query)
+		return sql, err
+	case "update":
+		sql, _, err := sfbuilders.BuildUpdateSQL(query)
+		return sql, err
+	case "delete":
+		sql, _ := sfbuilders.BuildDeleteSQL(query)
+		return sql, nil
+	case "merge":
+		sql, _, err := sfbuilders.BuildUpsertSQL(query)
+		return sql, err
+	case "bulk_insert":
+		sql, _, err := sfbuilders.BuildBulkInsertSQL(query)
+		return sql, err
+	case "bulk_merge":
+		sql, _, err := sfbuilders.BuildBulkUpsertSQL(query)
+		return sql, err
+	case "create_table":
+		return sfbuilders.BuildCreateTableSQL(query, mapping.TypeMap)
+	case "alter_table":
+		return sfbuilders.BuildAlterTableSQL(query, mapping.TypeMap)
+	case "drop_table":
+		return sfbuilders.BuildDropTableSQL(query)
+	case "truncate_table":
+		return sfbuilders.BuildTruncateTableSQL(query)
+	case "rename_table":
+		return sfbuilders.BuildRenameTableSQL(query)
+	case "create_database":
+		return sfbuilders.BuildCreateDatabaseSQL(query)
+	case "drop_database":
+		return sfbuilders.BuildDropDatabaseSQL(query)
+	case "create_schema":
+		return sfbuilders.BuildCreateSchemaSQL(query)
+	case "drop_schema":
+		return sfbuilders.BuildDropSchemaSQL(query)
+	case "create_view":
+		return sfbuilders.BuildCreateViewSQL(query)
+	case "create_or_replace_view":
+		return sfbuilders.BuildAlterViewSQL(query)
+	case "drop_view":
+		return sfbuilders.BuildDropViewSQL(query)
+	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
+		sql, _ := sfbuilders.BuildJoinSQL(query)
+		return sql, nil
+	case "count", "sum", "avg", "min", "max", "listagg":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _ := sfbuilders.BuildWindowSQL(query)
+			return sql, nil
+		}
+		sql, _, err := sfbuilders.BuildAggregateSQL(query)
+		return sql, err
+	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
+		sql, _ := sfbuilders.BuildWindowSQL(query)
+		return sql, nil
+	case "union", "union_all", "intersect", "except":
+		sql, _, err := sfbuilders.BuildSetOperationSQL(query)
+		return sql, err
+	case "begin":
+		return "BEGIN", nil
+	case "commit":
+		return "COMMIT", nil
+	case "rollback":
+		return "ROLLBACK", nil
+	case "with":
+		sql, _, err := sfbuilders.BuildCTESQL(query)
+		return sql, err
+	case "subquery", "exists":
+		sql, _, err := sfbuilders.BuildSubquerySQL(query)
+		return sql, err
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,552 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+	"github.com/omniql-engine/omniql/mapping"
+	sqlitebuilders "github.com/omniql-engine/omniql/engine/builders/sqlite"
+	"github.com/omniql-engine/omniql/engine/models"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// ============================================================================
+// EXPRESSION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteExpression(expr *models.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	return &pb.Expression{
+		Type:           expr.Type,
+		Value:          expr.Value,
+		Left:           mapSQLiteExpression(expr.Left),
+		Operator:       expr.Operator,
+		Right:          mapSQLiteExpression(expr.Right),
+		FunctionName:   expr.FunctionName,
+		FunctionArgs:   mapSQLiteExpressions(expr.FunctionArgs),
+		CaseConditions: mapSQLiteCaseConditions(expr.CaseConditions),
+		CaseElse:       mapSQLiteExpression(expr.CaseElse),
+	}
+}
+
+func mapSQLiteExpressions(exprs []*models.Expression) []*pb.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	var result []*pb.Expression
+	for _, expr := range exprs {
+		result = append(result, mapSQLiteExpression(expr))
+	}
+	return result
+}
+
+func mapSQLiteCaseConditions(conditions []*models.CaseCondition) []*pb.CaseCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.CaseCondition
+	for _, cc := range conditions {
+		result = append(result, &pb.CaseCondition{
+			Condition: mapSQLiteCondition(cc.Condition),
+			ThenExpr:  mapSQLiteExpression(cc.ThenExpr),
+		})
+	}
+	return result
+}
+
+func mapSQLiteCondition(cond *models.Condition) *pb.QueryCondition {
+	if cond == nil {
+		return nil
+	}
+	return &pb.QueryCondition{
+		FieldExpr:  mapSQLiteExpression(cond.FieldExpr),
+		Operator:   cond.Operator,
+		ValueExpr:  mapSQLiteExpression(cond.ValueExpr),
+		Value2Expr: mapSQLiteExpression(cond.Value2Expr),
+		ValuesExpr: mapSQLiteExpressions(cond.ValuesExpr),
+		Logic:      cond.Logic,
+		Nested:     mapSQLiteConditions(cond.Nested),
+	}
+}
+
+func mapSQLiteConditions(conditions []models.Condition) []*pb.QueryCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	var result []*pb.QueryCondition
+	for _, cond := range conditions {
+		result = append(result, mapSQLiteCondition(&cond))
+	}
+	return result
+}
+
+func mapSQLiteOrderByClauses(orderBy []models.OrderBy) []*pb.OrderByClause {
+	if len(orderBy) == 0 {
+		return nil
+	}
+	var result []*pb.OrderByClause
+	for _, ob := range orderBy {
+		result = append(result, &pb.OrderByClause{
+			FieldExpr: mapSQLiteExpression(ob.FieldExpr),
+			Direction: string(ob.Direction),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// MAIN TRANSLATOR
+// ============================================================================
+
+func TranslateSQLite(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	operation := mapping.OperationMap["SQLite"][query.Operation]
+	table := getSQLiteTableName(query.Entity, query.Operation)
+	conditions := mapSQLiteConditions(query.Conditions)
+	fields := mapSQLiteFields(query.Fields)
+	
+	// DQL: Map fields
+	joins := mapSQLiteJoins(query.Joins)
+	aggregate := mapSQLiteAggregate(query.Aggregate)
+	orderBy := mapSQLiteOrderByClauses(query.OrderBy)
+	having := mapSQLiteConditions(query.Having)
+	
+	// DQL: Advanced fields
+	windowFunctions := mapSQLiteWindowFunctions(query.WindowFunctions)
+	cte := mapSQLiteCTE(query.CTE, tenantID)
+	subquery := mapSQLiteSubquery(query.Subquery, tenantID)
+	pattern := query.Pattern
+	setOperation, err := mapSQLiteSetOperation(query.SetOperation, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	
+	// TCL (SQLite has no accounts or permissions, so no DCL)
+	var savepointName, isolationLevel, beginMode string
+	var readOnly bool
+	if query.Transaction != nil {
+		savepointName = query.Transaction.SavepointName
+		isolationLevel = query.Transaction.IsolationLevel
+		readOnly = query.Transaction.ReadOnly
+		beginMode = query.Transaction.Mode
+	}
+	
+	// CRUD extensions
+	upsert := mapSQLiteUpsert(query.Upsert)
+	bulkData := mapSQLiteBulkData(query.BulkData)
+
+	// DDL
+	viewName := query.ViewName
+	viewQuery, err := mapSQLiteViewQuery(query.ViewQuery, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	databaseName := query.DatabaseName
+	databaseFile := query.DatabaseFile
+	newName := query.NewName
+	if query.NewName != "" && query.Operation == "RENAME TABLE" {
+		lookupOp := strings.ToUpper(strings.ReplaceAll(query.Operation, "_", " "))
+		rule := mapping.TableNamingRules[lookupOp]
+		if rule == "plural" {
+			newName = mapping.EntityTable(query.NewName)
+		} else {
+			newName = strings.ToLower(query.NewName)
+		}
+	}
+	
+	result := &pb.RelationalQuery{
+		Operation:  operation,
+		Table:      table,
+		Conditions: conditions,
+		Fields:     fields,
+		Limit:      int32(query.Limit),
+		Offset:     int32(query.Offset),
+		Distinct:   query.Distinct,
+		Returning:  mapSQLiteExpressions(query.Returning),
+		
+		// DQL
+		Joins:           joins,
+		Columns:         mapSQLiteExpressions(query.Columns),
+		SelectColumns:   mapSQLiteSelectColumns(query.SelectColumns),
+		Aggregate:       aggregate,
+		OrderBy:         orderBy,
+		GroupBy:         mapSQLiteExpressions(query.GroupBy),
+		Having:          having,
+		WindowFunctions: windowFunctions,
+		Cte:             cte,
+		Subquery:        subquery,
+		Pattern:         pattern,
+		SetOperation:    setOperation,
+		
+		// TCL
+		SavepointName:  savepointName,
+		IsolationLevel: isolationLevel,
+		ReadOnly:       readOnly,
+		BeginMode:      beginMode,
+		
+		// CRUD Extensions
+		Upsert:   upsert,
+		BulkData: bulkData,
+		
+		// DDL
+		ViewName:     viewName,
+		ViewQuery:    viewQuery,
+		DatabaseName: databaseName,
+		DatabaseFile: databaseFile,
+		NewName:      newName,
+		AlterAction:  query.AlterAction,
+		TableOptions: query.TableOptions,
+	}
+	
+	if query.Collation != "" {
+		applyCollation(result, sqlitebuilders.CollationName(query.Collation, query.CollationStrength))
+	}
+	sql, err := buildSQLiteString(result)
+	if err != nil {
+		return nil, err
+	}
+	result.Sql = sql
+	return result, nil
+}
+
+// ============================================================================
+// FIELD MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteFields(fields []models.Field) []*pb.QueryField {
+	if len(fields) == 0 {
+		return nil
+	}
+	var result []*pb.QueryField
+	for _, field := range fields {
+		result = append(result, &pb.QueryField{
+			NameExpr:      mapSQLiteExpression(field.NameExpr),
+			ValueExpr:     mapSQLiteExpression(field.ValueExpr),
+			Constraints:   field.Constraints,
+			GeneratedExpr: mapSQLiteExpression(field.GeneratedExpr),
+		})
+	}
+	return result
+}
+
+// getSQLiteTableName names the table for entity; alias.Entity addresses a table
+// in an attached database, and only the entity part follows the naming rule
+func getSQLiteTableName(entity string, operation string) string {
+	if dot := strings.LastIndex(entity, "."); dot > 0 {
+		name := getSQLiteTableName(entity[dot+1:], operation)
+		if name == "" {
+			return ""
+		}
+		return entity[:dot] + "." + name
+	}
+
+	lookupOp := strings.ToUpper(strings.ReplaceAll(operation, "_", " "))
+	rule := mapping.TableNamingRules[lookupOp]
+	
+	if rule == "plural" {
+		return mapping.EntityTable(entity)
+	}
+	if rule == "none" {
+		return ""
+	}
+	return strings.ToLower(entity)
+}
+
+// ============================================================================
+// CRUD EXTENSIONS (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteUpsert(upsert *models.Upsert) *pb.UpsertClause {
+	if upsert == nil {
+		return nil
+	}
+	return &pb.UpsertClause{
+		ConflictFields:     mapSQLiteExpressions(upsert.ConflictFields),
+		UpdateFields:       mapSQLiteFields(upsert.UpdateFields),
+		ConflictAction:     conflictAction(upsert, "UPDATE"),
+		ConflictConstraint: upsert.ConflictConstraint,
+		ConflictWhere:      mapSQLiteConditions(upsert.ConflictWhere),
+	}
+}
+
+func mapSQLiteBulkData(bulkData [][]models.Field) []*pb.BulkInsertRow {
+	if len(bulkData) == 0 {
+		return nil
+	}
+	var result []*pb.BulkInsertRow
+	for _, row := range bulkData {
+		result = append(result, &pb.BulkInsertRow{
+			Fields: mapSQLiteFields(row),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// JOIN MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteJoins(joins []models.Join) []*pb.JoinClause {
+	if len(joins) == 0 {
+		return nil
+	}
+	var result []*pb.JoinClause
+	for _, join := range joins {
+		result = append(result, &pb.JoinClause{
+			JoinType:  string(join.Type),
+			Table:     getSQLiteTableName(join.Table, "GET"),
+			LeftExpr:  mapSQLiteExpression(join.LeftExpr),
+			RightExpr: mapSQLiteExpression(join.RightExpr),
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// AGGREGATE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteAggregate(agg *models.Aggregation) *pb.AggregateClause {
+	if agg == nil {
+		return nil
+	}
+	return &pb.AggregateClause{
+		Function:  string(agg.Function),
+		FieldExpr: mapSQLiteExpression(agg.FieldExpr),
+		Separator: agg.Separator,
+		OrderBy:   mapSQLiteOrderByClauses(agg.OrderBy),
+	}
+}
+
+// ============================================================================
+// WINDOW FUNCTIONS (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteWindowFunctions(windowFuncs []models.WindowFunction) []*pb.WindowClause {
+	if len(windowFuncs) == 0 {
+		return nil
+	}
+	var result []*pb.WindowClause
+	for _, wf := range windowFuncs {
+		result = append(result, &pb.WindowClause{
+			Function:     string(wf.Function),
+			FieldExpr:    mapSQLiteExpression(wf.FieldExpr),
+			Alias:        wf.Alias,
+			PartitionBy:  mapSQLiteExpressions(wf.PartitionBy),
+			OrderBy:      mapSQLiteOrderByClauses(wf.OrderBy),
+			Offset:       int32(wf.Offset),
+			Buckets:      int32(wf.Buckets),
+			DefaultValue: mapSQLiteExpression(wf.Default),
+			FrameUnit:    wf.FrameUnit,
+			FrameStart:   wf.FrameStart,
+			FrameEnd:     wf.FrameEnd,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// CTE MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteCTE(cte *models.CTE, tenantID string) *pb.CTEClause {
+	if cte == nil {
+		return nil
+	}
+	var cteQuery *pb.RelationalQuery
+	if cte.Query != nil {
+		cteQuery, _ = TranslateSQLite(cte.Query, tenantID)
+	}
+
+	var mainQuery *pb.RelationalQuery
+	if cte.MainQuery != nil {
+		main := cte.MainQuery
+		// Reverse translation points MainQuery back at the query holding the CTE
+		if main.CTE == cte {
+			copied := *main
+			copied.CTE = nil
+			main = &copied
+		}
+		mainQuery, _ = TranslateSQLite(main, tenantID)
+		pointAtCTE(main, mainQuery, cte.Name)
+	}
+	if cte.Recursive {
+		// The recursive member reads from the CTE itself
+		pointAtCTE(cte.Query, cteQuery, cte.Name)
+	}
+
+	return &pb.CTEClause{
+		CteName:   cte.Name,
+		CteQuery:  cteQuery,
+		Recursive: cte.Recursive,
+		MainQuery: mainQuery,
+	}
+}
+
+// ============================================================================
+// SUBQUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteSubquery(subquery *models.Subquery, tenantID string) *pb.SubqueryClause {
+	if subquery == nil {
+		return nil
+	}
+	var subqueryQuery *pb.RelationalQuery
+	if subquery.Query != nil {
+		subqueryQuery, _ = TranslateSQLite(subquery.Query, tenantID)
+	}
+	return &pb.SubqueryClause{
+		SubqueryType: subquery.Type,
+		FieldExpr:    mapSQLiteExpression(subquery.FieldExpr),
+		Subquery:     subqueryQuery,
+		Alias:        subquery.Alias,
+	}
+}
+
+// ============================================================================
+// SET OPERATION MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteSetOperation(setOp *models.SetOperation, tenantID string) (*pb.SetOperationClause, error) {
+	if setOp == nil {
+		return nil, nil
+	}
+	leftQuery, err := TranslateSQLite(setOp.LeftQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate left query: %w", err)
+	}
+	rightQuery, err := TranslateSQLite(setOp.RightQuery, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate right query: %w", err)
+	}
+	return &pb.SetOperationClause{
+		OperationType: string(setOp.Type),
+		LeftQuery:     leftQuery,
+		RightQuery:    rightQuery,
+	}, nil
+}
+
+// ============================================================================
+// SELECT COLUMNS MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteSelectColumns(selectCols []models.SelectColumn) []*pb.SelectColumn {
+	if len(selectCols) == 0 {
+		return nil
+	}
+	var result []*pb.SelectColumn
+	for _, col := range selectCols {
+		result = append(result, &pb.SelectColumn{
+			ExpressionObj: mapSQLiteExpression(col.ExpressionObj),
+			Alias:         col.Alias,
+		})
+	}
+	return result
+}
+
+// ============================================================================
+// VIEW QUERY MAPPING (100% TrueAST)
+// ============================================================================
+
+func mapSQLiteViewQuery(viewQuery *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+	if viewQuery == nil {
+		return nil, nil
+	}
+	return TranslateSQLite(viewQuery, tenantID)
+}
+
+// ============================================================================
+// SQL STRING BUILDER
+// ============================================================================
+
+func buildSQLiteString(query *pb.RelationalQuery) (string, error) {
+	operation := strings.ToLower(query.Operation)
+	
+	switch operation {
+	case "select":
+		sql, _ := sqlitebuilders.BuildSelectSQL(query)
+		return sql, nil
+	case "insert":
+		sql, _ := sqlitebuilders.BuildInsertSQL(query)
+		return sql, nil
+	case "update":
+		sql, _ := sqlitebuilders.BuildUpdateSQL(query)
+		return sql, nil
+	case "delete":
+		// TRUNCATE TABLE maps here too: an unqualified DELETE empties the table
+		sql, _ := sqlitebuilders.BuildDeleteSQL(query)
+		return sql, nil
+	case "upsert":
+		sql, _, err := sqlitebuilders.BuildUpsertSQL(query)
+		return sql, err
+	case "replace":
+		sql, _ := sqlitebuilders.BuildReplaceSQL(query)
+		return sql, nil
+	case "bulk_insert":
+		sql, _, err := sqlitebuilders.BuildBulkInsertSQL(query)
+		return sql, err
+	case "bulk_upsert":
+		sql, _, err := sqlitebuilders.BuildBulkUpsertSQL(query)
+		return sql, err
+	case "create_table":
+		return sqlitebuilders.BuildCreateTableSQL(query, mapping.TypeMap)
+	case "alter_table":
+		return sqlitebuilders.BuildAlterTableSQL(query, mapping.TypeMap)
+	case "drop_table":
+		return sqlitebuilders.BuildDropTableSQL(query)
+	case "alter_table_rename":
+		return sqlitebuilders.BuildRenameTableSQL(query)
+	case "table_list", "table_info", "index_list":
+		return sqlitebuilders.BuildSchemaPragmaSQL(query)
+	case "create_index":
+		return sqlitebuilders.BuildCreateIndexSQL(query)
+	case "drop_index":
+		return sqlitebuilders.BuildDropIndexSQL(query)
+	case "create_view":
+		return sqlitebuilders.BuildCreateViewSQL(query)
+	case "attach":
+		return sqlitebuilders.BuildAttachDatabaseSQL(query)
+	case "detach":
+		return sqlitebuilders.BuildDetachDatabaseSQL(query)
+	case "drop_view":
+		return sqlitebuilders.BuildDropViewSQL(query)
+	case "drop_create_view":
+		return sqlitebuilders.BuildAlterViewSQL(query)
+	case "inner_join", "left_join", "right_join", "full_join", "cross_join":
+		sql, _ := sqlitebuilders.BuildJoinSQL(query)
+		return sql, nil
+	case "count", "sum", "avg", "min", "max", "group_concat":
+		// SUM amount OVER (...) is a window function, not a grouped aggregate
+		if len(query.WindowFunctions) > 0 {
+			sql, _ := sqlitebuilders.BuildWindowSQL(query)
+			return sql, nil
+		}
+		sql, _ := sqlitebuilders.BuildAggregateSQL(query)
+		return sql, nil
+	case "row_number", "rank", "dense_rank", "lag", "lead", "ntile":
+		sql, _ := sqlitebuilders.BuildWindowSQL(query)
+		return sql, nil
+	case "union", "union_all", "intersect", "except":
+		sql, _ := sqlitebuilders.BuildSetOperationSQL(query)
+		return sql, nil
+	case "begin":
+		return sqlitebuilders.BuildBeginSQL(query.BeginMode), nil
+	case "commit":
+		return "COMMIT", nil
+	case "rollback":
+		return "ROLLBACK", nil
+	case "savepoint":
+		return sqlitebuilders.BuildSavepointSQL(query.SavepointName)
+	case "rollback_to":
+		return sqlitebuilders.BuildRollbackToSavepointSQL(query.SavepointName)
+	case "release":
+		return sqlitebuilders.BuildReleaseSavepointSQL(query.SavepointName)
+	case "set_transaction":
+		return sqlitebuilders.BuildSetTransactionSQL(query), nil
+	case "with":
+		sql, _ := sqlitebuilders.BuildCTESQL(query)
+		return sql, nil
+	default:
+		return "", nil
+	}
+}
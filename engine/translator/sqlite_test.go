@@ -0,0 +1,31 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/parser"
+)
+
+// A statement the SQLite builders reject fails instead of translating to ""
+func TestSQLiteBuilderErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`UPSERT User WITH email = "john@example.com", name = "John" ON CONSTRAINT users_email_key`, "cannot target constraint"},
+		{`CREATE TABLE Setting WITH key:STRING, value:TEXT WITHOUT ROWID`, "needs a PRIMARY KEY"},
+		{`ALTER TABLE User MODIFY name:TEXT`, "cannot change a column's type"},
+		{`CREATE VIEW Recent AS UPSERT User WITH email = "a" ON CONSTRAINT users_email_key`, "cannot target constraint"},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := TranslateSQLite(query, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("TranslateSQLite(%q) = %v, %v; want error containing %q", tt.input, result, err, tt.want)
+		}
+	}
+}
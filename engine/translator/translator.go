@@ -1,34 +1,107 @@
 package translator
 
 import (
-	"fmt"                        
+	"fmt"
+	"strings"
                       
+	cqlbuilders "github.com/omniql-engine/omniql/engine/builders/cassandra"
+	esbuilders "github.com/omniql-engine/omniql/engine/builders/elasticsearch"
+	mongobuilders "github.com/omniql-engine/omniql/engine/builders/mongodb"
+	mysqlbuilders "github.com/omniql-engine/omniql/engine/builders/mysql"
+	oraclebuilders "github.com/omniql-engine/omniql/engine/builders/oracle"
+	pgbuilders "github.com/omniql-engine/omniql/engine/builders/postgres"
+	redisbuilders "github.com/omniql-engine/omniql/engine/builders/redis"
 	"github.com/omniql-engine/omniql/mapping"          
 	"github.com/omniql-engine/omniql/engine/models"        
 	pb "github.com/omniql-engine/omniql/utilities/proto" 
 )
 
+// Options are per-client settings that change what a query translates to and
+// how the client runs it, one set per database. The zero value is the default
+// of every database.
+type Options struct {
+	MySQL         mysqlbuilders.Options
+	PostgreSQL    pgbuilders.Options // also CockroachDB
+	Oracle        oraclebuilders.Options
+	Cassandra     cqlbuilders.Options
+	MongoDB       mongobuilders.Options
+	Elasticsearch esbuilders.Options
+	Redis         redisbuilders.Options
+}
+
 // Translate routes query to appropriate database translator and wraps in UniversalQuery
 func Translate(query *models.Query, dbType string, tenantID string) (*pb.UniversalQuery, error) {
+	return TranslateWithOptions(query, dbType, tenantID, Options{})
+}
+
+// TranslateWithOptions is Translate with a client's options
+func TranslateWithOptions(query *models.Query, dbType string, tenantID string, opts Options) (*pb.UniversalQuery, error) {
 	// Validate database type using mapping
 	if !mapping.IsSupportedDatabase(dbType) {
-		return nil, fmt.Errorf("unsupported database type: %s (supported: PostgreSQL, MySQL, MongoDB, Redis)", dbType)
+		return nil, fmt.Errorf("unsupported database type: %s (supported: PostgreSQL, MySQL, SQLite, Oracle, SQLServer, CockroachDB, ClickHouse, BigQuery, Snowflake, Cassandra, MongoDB, Elasticsearch, Redis)", dbType)
 	}
+	if err := checkSupport(query, dbType, opts); err != nil {
+		return nil, err
+	}
+	query = applyColumnNaming(query, dbType)
 
 	switch dbType {
 	case "PostgreSQL":
-		return translateRelational(query, tenantID, TranslatePostgreSQL, "PostgreSQL")
+		return translateRelational(query, tenantID, func(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+			return translatePostgreSQL(query, tenantID, opts.PostgreSQL)
+		}, "PostgreSQL")
 	
 	case "MySQL":
-		return translateRelational(query, tenantID, TranslateMySQL, "MySQL")
+		return translateRelational(query, tenantID, func(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+			return translateMySQL(query, tenantID, opts.MySQL)
+		}, "MySQL")
+	
+	case "SQLite":
+		return translateRelational(query, tenantID, TranslateSQLite, "SQLite")
+	
+	case "Oracle":
+		return translateRelational(query, tenantID, func(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+			return translateOracle(query, tenantID, opts.Oracle)
+		}, "Oracle")
+	
+	case "SQLServer":
+		return translateRelational(query, tenantID, TranslateSQLServer, "SQLServer")
+	
+	case "CockroachDB":
+		return translateRelational(query, tenantID, func(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+			return translateCockroachDB(query, tenantID, opts.PostgreSQL)
+		}, "CockroachDB")
+	
+	case "ClickHouse":
+		return translateRelational(query, tenantID, TranslateClickHouse, "ClickHouse")
+	case "BigQuery":
+		return translateRelational(query, tenantID, TranslateBigQuery, "BigQuery")
+	case "Snowflake":
+		return translateRelational(query, tenantID, TranslateSnowflake, "Snowflake")
+	case "Cassandra":
+		return translateRelational(query, tenantID, func(query *models.Query, tenantID string) (*pb.RelationalQuery, error) {
+			return translateCassandra(query, tenantID, opts.Cassandra)
+		}, "Cassandra")
 	
 	case "MongoDB":
-		return translateDocument(query, tenantID, TranslateMongoDB, "MongoDB")
+		return translateDocument(query, tenantID, func(query *models.Query, tenantID string) (*pb.DocumentQuery, error) {
+			return translateMongoDB(query, tenantID, opts.MongoDB)
+		}, "MongoDB")
+	
+	case "Elasticsearch":
+		return translateDocument(query, tenantID, func(query *models.Query, tenantID string) (*pb.DocumentQuery, error) {
+			return translateElasticsearch(query, tenantID, opts.Elasticsearch)
+		}, "Elasticsearch")
 	
 	case "Redis":
-		return translateKeyValue(query, tenantID, TranslateRedis, "Redis")
+		return translateKeyValue(query, tenantID, func(query *models.Query, tenantID string) (*pb.KeyValueQuery, error) {
+			return translateRedis(query, tenantID, opts.Redis)
+		}, "Redis")
 		
 	default:
+		if dialect, ok := dialects[dbType]; ok {
+			return translateRelational(query, tenantID, translateDialect(dialect), dbType)
+		}
 		return nil, fmt.Errorf("unsupported database type: %s", dbType)
 	}
 }
@@ -52,7 +125,7 @@ func translateRelational(
 	}, nil
 }
 
-// translateDocument - Helper for MongoDB
+// translateDocument - Helper for MongoDB and Elasticsearch
 func translateDocument(
 	query *models.Query,
 	tenantID string,
@@ -90,4 +163,190 @@ func translateKeyValue(
 			KeyValue: kvQuery,
 		},
 	}, nil
-}
\ No newline at end of file
+}
+
+// isWriteFromQuery reports whether an operation can take its rows from a GET
+// (CREATE TABLE ... AS GET, CREATE / REPLACE / UPSERT ... FROM GET)
+func isWriteFromQuery(operation string) bool {
+	switch operation {
+	case "CREATE TABLE", "CREATE", "REPLACE", "UPSERT":
+		return true
+	}
+	return false
+}
+
+// checkSupport rejects a query dbType cannot run before it is translated:
+// its operation, condition operators and features are looked up in the
+// mapping capabilities (mapping.SupportsOperation, ...)
+func checkSupport(query *models.Query, dbType string, opts Options) error {
+	if _, ok := mapping.OperationMap[dbType]; !ok {
+		return nil // no mappings: Translate rejects the database itself
+	}
+	// Reverse translation reads joins on several conditions that OQL cannot write
+	for _, join := range query.Joins {
+		if len(join.Conditions) > 0 {
+			return &mapping.ErrNotSupported{Database: dbType, Feature: "JOIN with more than one ON condition"}
+		}
+	}
+
+	if !mapping.SupportsOperation(dbType, query.Operation) && !redisSearchOperation(dbType, query.Operation, opts.Redis) {
+		return mapping.NotSupported(dbType, query.Operation, mapping.SupportsOperation)
+	}
+	if op := findUnsupportedOperator(query.Conditions, dbType, opts); op != "" {
+		return mapping.NotSupported(dbType, op, mapping.SupportsOperator)
+	}
+
+	var features []string
+	if len(query.Facets) > 0 {
+		features = append(features, "FACET")
+	}
+	if query.CTE != nil && query.CTE.MaxDepth > 0 {
+		features = append(features, "CTE DEPTH")
+	}
+	if query.TTL > 0 {
+		features = append(features, "TTL")
+	}
+	if query.IfNotExists {
+		features = append(features, "IF NOT EXISTS")
+	}
+	if query.Upsert != nil && query.Upsert.DoNothing {
+		features = append(features, "DO NOTHING")
+	}
+	if query.Collation != "" {
+		features = append(features, "COLLATE")
+	}
+	if query.AsOf != "" {
+		features = append(features, "AS OF")
+	}
+	if query.Before != "" {
+		features = append(features, "BEFORE")
+	}
+	features = append(features, tableModifiers(query)...)
+	switch query.PartitionStrategy {
+	case "DAY", "HOUR", "MONTH", "YEAR":
+		features = append(features, "PARTITION BY "+query.PartitionStrategy)
+	}
+	if len(query.ClusterKeys) > 0 {
+		features = append(features, "CLUSTER BY")
+	}
+	if query.ViewQuery != nil && isWriteFromQuery(query.Operation) {
+		// CREATE TABLE name AS GET ..., UPSERT User FROM GET ... ($merge)
+		if query.Operation == "CREATE TABLE" {
+			features = append(features, "CREATE TABLE AS")
+		} else {
+			features = append(features, query.Operation+" FROM")
+		}
+	}
+	for _, feature := range features {
+		if !mapping.SupportsFeature(dbType, feature) {
+			return mapping.NotSupported(dbType, feature, mapping.SupportsFeature)
+		}
+	}
+	return nil
+}
+
+// tableModifiers returns FINAL and SAMPLE if query or a query nested in it (a
+// view, CTE, subquery or set operand) reads its table with them
+func tableModifiers(query *models.Query) []string {
+	var final, sample bool
+	// Reverse translation points a CTE's main query back at the query holding it
+	seen := map[*models.Query]bool{}
+	var walk func(q *models.Query)
+	walk = func(q *models.Query) {
+		if q == nil || seen[q] {
+			return
+		}
+		seen[q] = true
+		final = final || q.Final
+		sample = sample || q.Sample != ""
+		walk(q.ViewQuery)
+		if q.CTE != nil {
+			walk(q.CTE.Query)
+			walk(q.CTE.MainQuery)
+		}
+		if q.Subquery != nil {
+			walk(q.Subquery.Query)
+		}
+		if q.SetOperation != nil {
+			walk(q.SetOperation.LeftQuery)
+			walk(q.SetOperation.RightQuery)
+		}
+	}
+	walk(query)
+
+	var modifiers []string
+	if final {
+		modifiers = append(modifiers, "FINAL")
+	}
+	if sample {
+		modifiers = append(modifiers, "SAMPLE")
+	}
+	return modifiers
+}
+
+// findUnsupportedOperator returns the first operator in conditions (recursive)
+// that dbType does not evaluate
+func findUnsupportedOperator(conditions []models.Condition, dbType string, opts Options) string {
+	for _, cond := range conditions {
+		if !mapping.SupportsOperator(dbType, cond.Operator) && !redisSearchOperator(dbType, cond.Operator, opts.Redis) {
+			return cond.Operator
+		}
+		if op := findUnsupportedOperator(cond.Nested, dbType, opts); op != "" {
+			return op
+		}
+	}
+	return ""
+}
+
+// applyCollation puts an explicit COLLATE on the string comparisons and the
+// ORDER BY fields of a translated query, as MongoDB applies its collation to
+// both. "" (SQLite's default BINARY) leaves the query as it is.
+func applyCollation(relQuery *pb.RelationalQuery, name string) {
+	if name == "" {
+		return
+	}
+	collateConditions(relQuery.Conditions, name)
+	for _, ob := range relQuery.OrderBy {
+		if ob.FieldExpr != nil && ob.FieldExpr.Type == "FIELD" && !mapping.IsSearchRankField(ob.FieldExpr.Value) {
+			ob.FieldExpr = &pb.Expression{Type: "COLLATE", Left: ob.FieldExpr, Value: name}
+		}
+	}
+}
+
+// collateConditions wraps the field of each comparison with a string value (recursive)
+// Comparisons with numbers are left alone: COLLATE on a non-text column is an error.
+func collateConditions(conditions []*pb.QueryCondition, name string) {
+	for _, cond := range conditions {
+		collateConditions(cond.Nested, name)
+		if cond.FieldExpr == nil || cond.FieldExpr.Type != "FIELD" || !mapping.CollationOperators[cond.Operator] {
+			continue
+		}
+		for _, value := range append([]*pb.Expression{cond.ValueExpr, cond.Value2Expr}, cond.ValuesExpr...) {
+			if value != nil && value.Type == "STRING" {
+				cond.FieldExpr = &pb.Expression{Type: "COLLATE", Left: cond.FieldExpr, Value: name}
+				break
+			}
+		}
+	}
+}
+
+// pointAtCTE restores CTE references that translation pluralized like entities
+// translated must be the translation of query; set operation sides and join
+// tables are followed so a recursive member can read from the CTE.
+func pointAtCTE(query *models.Query, translated *pb.RelationalQuery, name string) {
+	if query == nil || translated == nil {
+		return
+	}
+	if strings.EqualFold(query.Entity, name) {
+		translated.Table = name
+	}
+	for i, join := range query.Joins {
+		if i < len(translated.Joins) && strings.EqualFold(join.Table, name) {
+			translated.Joins[i].Table = name
+		}
+	}
+	if query.SetOperation != nil && translated.SetOperation != nil {
+		pointAtCTE(query.SetOperation.LeftQuery, translated.SetOperation.LeftQuery, name)
+		pointAtCTE(query.SetOperation.RightQuery, translated.SetOperation.RightQuery, name)
+	}
+}
@@ -0,0 +1,343 @@
+package translator
+
+import (
+	"fmt"
+	"testing"
+
+	cqlbuilders "github.com/omniql-engine/omniql/engine/builders/cassandra"
+	esbuilders "github.com/omniql-engine/omniql/engine/builders/elasticsearch"
+	mongobuilders "github.com/omniql-engine/omniql/engine/builders/mongodb"
+	mysqlbuilders "github.com/omniql-engine/omniql/engine/builders/mysql"
+	oraclebuilders "github.com/omniql-engine/omniql/engine/builders/oracle"
+	pgbuilders "github.com/omniql-engine/omniql/engine/builders/postgres"
+	redisbuilders "github.com/omniql-engine/omniql/engine/builders/redis"
+	"github.com/omniql-engine/omniql/engine/parser"
+	pb "github.com/omniql-engine/omniql/utilities/proto"
+)
+
+// Each query of a set operation keeps its own ORDER BY / LIMIT, and the
+// clauses after the last one apply to the combined result
+func TestSetOperationOrderingAndLimits(t *testing.T) {
+	const input = `UNION (GET User WHERE age > 50 ORDER BY age DESC LIMIT 5) (GET User WHERE role = "premium" LIMIT 5) ORDER BY name LIMIT 8 OFFSET 2`
+	tests := []struct {
+		db   string
+		want string
+	}{
+		{"MySQL", "(SELECT * FROM `users` WHERE `age` > ? ORDER BY `age` DESC LIMIT 5) UNION (SELECT * FROM `users` WHERE `role` = ? LIMIT 5) ORDER BY `name` ASC LIMIT 8 OFFSET 2"},
+		{"SQLite", `SELECT * FROM (SELECT * FROM "users" WHERE "age" > ? ORDER BY "age" DESC LIMIT 5) UNION SELECT * FROM (SELECT * FROM "users" WHERE "role" = ? LIMIT 5) ORDER BY "name" ASC LIMIT 8 OFFSET 2`},
+		{"Oracle", "SELECT * FROM (SELECT * FROM users WHERE age > :1 ORDER BY age DESC FETCH NEXT 5 ROWS ONLY) UNION SELECT * FROM (SELECT * FROM users WHERE role = :2 FETCH NEXT 5 ROWS ONLY) ORDER BY name ASC OFFSET 2 ROWS FETCH NEXT 8 ROWS ONLY"},
+		{"SQLServer", "SELECT * FROM (SELECT * FROM (SELECT TOP (5) * FROM [users] WHERE [age] > @p1 ORDER BY [age] DESC) AS [q] UNION SELECT * FROM (SELECT TOP (5) * FROM [users] WHERE [role] = @p2) AS [q]) AS [u] ORDER BY [name] ASC OFFSET 2 ROWS FETCH NEXT 8 ROWS ONLY"},
+		{"Snowflake", "(SELECT * FROM users WHERE age > ? ORDER BY age DESC LIMIT 5) UNION (SELECT * FROM users WHERE role = ? LIMIT 5) ORDER BY name ASC LIMIT 8 OFFSET 2"},
+		{"BigQuery", "(SELECT * FROM `users` WHERE `age` > @p1 ORDER BY `age` DESC LIMIT 5) UNION DISTINCT (SELECT * FROM `users` WHERE `role` = @p2 LIMIT 5) ORDER BY `name` ASC LIMIT 8 OFFSET 2"},
+		{"ClickHouse", "SELECT * FROM ((SELECT * FROM `users` WHERE `age` > ? ORDER BY `age` DESC LIMIT 5) UNION DISTINCT (SELECT * FROM `users` WHERE `role` = ? LIMIT 5)) ORDER BY `name` ASC LIMIT 8 OFFSET 2"},
+	}
+	query, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	for _, tt := range tests {
+		result, err := Translate(query, tt.db, "")
+		if err != nil {
+			t.Errorf("%s: %v", tt.db, err)
+			continue
+		}
+		if got := result.GetRelational().GetSql(); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.db, got, tt.want)
+		}
+	}
+}
+
+// T-SQL cannot order a query inside a set operation unless it is paged
+func TestSQLServerSetOperationRejectsUnpagedOrder(t *testing.T) {
+	query, err := parser.Parse(`UNION (GET User ORDER BY age) (GET User WHERE role = "premium")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Translate(query, "SQLServer", ""); err == nil {
+		t.Error("expected an error for ORDER BY without LIMIT inside a set operation")
+	}
+}
+
+// UPSERT ... DO NOTHING inserts a row or leaves the conflicting one untouched
+func TestUpsertDoNothing(t *testing.T) {
+	const input = `UPSERT User WITH email = "a", name = "b" ON email DO NOTHING`
+	tests := []struct {
+		db   string
+		want string
+	}{
+		{"PostgreSQL", "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO NOTHING"},
+		{"CockroachDB", "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO NOTHING"},
+		{"MySQL", "INSERT IGNORE INTO `users` (`email`, `name`) VALUES (?, ?)"},
+		{"SQLite", `INSERT INTO "users" ("email", "name") VALUES (?, ?) ON CONFLICT ("email") DO NOTHING`},
+		{"Oracle", "MERGE INTO users t USING (SELECT :1 AS email, :2 AS name FROM DUAL) s ON (t.email = s.email) WHEN NOT MATCHED THEN INSERT (email, name) VALUES (s.email, s.name)"},
+		{"SQLServer", "MERGE INTO [users] WITH (HOLDLOCK) AS t USING (VALUES (@p1, @p2)) AS s ([email], [name]) ON (t.[email] = s.[email]) WHEN NOT MATCHED THEN INSERT ([email], [name]) VALUES (s.[email], s.[name]);"},
+		{"Snowflake", "MERGE INTO users t USING (SELECT ? AS email, ? AS name) s ON t.email = s.email WHEN NOT MATCHED THEN INSERT (email, name) VALUES (s.email, s.name)"},
+		{"Cassandra", "INSERT INTO users (email, name) VALUES (?, ?) IF NOT EXISTS"},
+	}
+	query, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	for _, tt := range tests {
+		result, err := Translate(query, tt.db, "")
+		if err != nil {
+			t.Errorf("%s: %v", tt.db, err)
+			continue
+		}
+		if got := result.GetRelational().GetSql(); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.db, got, tt.want)
+		}
+	}
+
+	result, err := Translate(query, "MongoDB", "")
+	if err != nil {
+		t.Fatalf("MongoDB: %v", err)
+	}
+	const wantMongo = `{"filter":{"email":"a"},"update":{"$setOnInsert":{"email":"a","name":"b"}},"updateOne":"users","upsert":true}`
+	if got := result.GetDocument().GetQuery(); got != wantMongo {
+		t.Errorf("MongoDB:\n got %s\nwant %s", got, wantMongo)
+	}
+
+	if _, err := Translate(query, "Redis", ""); err == nil {
+		t.Error("Redis: expected DO NOTHING to be rejected")
+	}
+}
+
+// SHOW reads the schema: PRAGMAs on SQLite, the catalogs or SHOW elsewhere
+func TestShowSchema(t *testing.T) {
+	tests := []struct {
+		input string
+		db    string
+		want  string
+	}{
+		{`SHOW TABLES`, "SQLite", `PRAGMA table_list`},
+		{`SHOW COLUMNS FROM User`, "SQLite", `PRAGMA table_info("users")`},
+		{`SHOW INDEXES FROM aux.OrderItem`, "SQLite", `PRAGMA "aux".index_list("orderitems")`},
+		{`SHOW COLUMNS FROM User`, "MySQL", "SHOW COLUMNS FROM `users`"},
+		{`SHOW INDEXES FROM User`, "MySQL", "SHOW INDEX FROM `users`"},
+		{`SHOW INDEXES FROM billing.User`, "PostgreSQL", `SELECT indexname, indexdef FROM pg_indexes WHERE schemaname = 'billing' AND tablename = 'users' ORDER BY indexname`},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := Translate(query, tt.db, "")
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.db, tt.input, err)
+			continue
+		}
+		if got := result.GetRelational().GetSql(); got != tt.want {
+			t.Errorf("%s %q:\n got %s\nwant %s", tt.db, tt.input, got, tt.want)
+		}
+	}
+
+	query, err := parser.Parse(`SHOW TABLES`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Translate(query, "Oracle", ""); err == nil {
+		t.Error("Oracle: expected SHOW TABLES to be rejected")
+	}
+}
+
+// TRUNCATE without TABLE empties the same table as TRUNCATE TABLE
+func TestTruncateWithoutTable(t *testing.T) {
+	tests := []struct {
+		db   string
+		want string
+	}{
+		{"PostgreSQL", "TRUNCATE TABLE logs"},
+		{"MySQL", "TRUNCATE TABLE `logs`"},
+		{"SQLite", `DELETE FROM "logs"`},
+		{"SQLServer", "TRUNCATE TABLE [logs]"},
+		{"Cassandra", "TRUNCATE TABLE logs"},
+		{"MongoDB", `{"deleteMany":"logs","filter":{}}`},
+	}
+	for _, input := range []string{"TRUNCATE Log", "TRUNCATE TABLE Log"} {
+		query, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		for _, tt := range tests {
+			result, err := Translate(query, tt.db, "")
+			if err != nil {
+				t.Errorf("%s: Translate(%q): %v", tt.db, input, err)
+				continue
+			}
+			got := result.GetRelational().GetSql()
+			if doc := result.GetDocument(); doc != nil {
+				got = doc.GetQuery()
+			}
+			if got != tt.want {
+				t.Errorf("%s: Translate(%q) = %s, want %s", tt.db, input, got, tt.want)
+			}
+		}
+	}
+}
+
+// A PostgreSQL GRANT or REVOKE on a database, schema, sequence or function
+// takes only the privileges that object has
+func TestPostgreSQLGrantObjectPrivileges(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`GRANT CONNECT, TEMP ON DATABASE shop TO analyst`, "GRANT CONNECT, TEMP ON DATABASE shop TO analyst"},
+		{`GRANT USAGE, CREATE ON SCHEMA reporting TO analyst`, "GRANT USAGE, CREATE ON SCHEMA reporting TO analyst"},
+		{`GRANT ALL ON DATABASE shop TO analyst`, "GRANT ALL PRIVILEGES ON DATABASE shop TO analyst"},
+		{`GRANT EXECUTE ON FUNCTION calc_total TO app`, "GRANT EXECUTE ON FUNCTION calc_total TO app"},
+		{`GRANT READ ON ALL TABLES IN SCHEMA public TO analyst`, "GRANT SELECT ON ALL TABLES IN SCHEMA public TO analyst"},
+		{`GRANT SELECT ON DATABASE shop TO analyst`, ""},
+		{`GRANT READ ON SCHEMA reporting TO analyst`, ""},
+		{`REVOKE CONNECT, INSERT ON DATABASE shop FROM analyst`, ""},
+		{`GRANT WRITE ON SEQUENCE users_id_seq TO app`, ""},
+		{`GRANT USAGE ON FUNCTION calc_total TO app`, ""},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := Translate(query, "PostgreSQL", "")
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Translate(%q) = %s, want an error", tt.input, result.GetRelational().GetSql())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Translate(%q): %v", tt.input, err)
+			continue
+		}
+		if got := result.GetRelational().GetSql(); got != tt.want {
+			t.Errorf("Translate(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+// queryText is the native query of a translation, whatever its database kind
+func queryText(result *pb.UniversalQuery) string {
+	switch {
+	case result.GetRelational() != nil:
+		return result.GetRelational().GetSql()
+	case result.GetDocument() != nil:
+		return result.GetDocument().GetQuery()
+	}
+	return result.GetKeyValue().GetCommandString()
+}
+
+// Options change the translation of the client they are given to; Translate
+// and other clients keep the defaults
+func TestTranslateWithOptions(t *testing.T) {
+	tests := []struct {
+		db    string
+		input string
+		opts  Options
+		want  string
+	}{
+		{"MySQL", `UPSERT User WITH email = "a", name = "b" ON email`,
+			Options{MySQL: mysqlbuilders.Options{UseUpsertRowAlias: true}},
+			"INSERT INTO `users` (`email`, `name`) VALUES (?, ?) AS `new` ON DUPLICATE KEY UPDATE `name` = `new`.`name`"},
+		{"MySQL", `BULK UPSERT User WITH [email = "a", name = "b"] [email = "c", name = "d"] [email = "e", name = "f"] ON email`,
+			Options{MySQL: mysqlbuilders.Options{BulkBatchRows: 2}},
+			"INSERT INTO `users` (`email`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`);\n" +
+				"INSERT INTO `users` (`email`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)"},
+		{"Oracle", `GET User LIMIT 10 OFFSET 5`,
+			Options{Oracle: oraclebuilders.Options{LegacyPagination: true}},
+			"SELECT * FROM (SELECT q.*, ROWNUM oql_rn FROM (SELECT * FROM users) q WHERE ROWNUM <= 15) WHERE oql_rn > 5"},
+		{"PostgreSQL", `BULK INSERT User WITH [name = "a", age = 1] [name = "b", age = 2]`,
+			Options{PostgreSQL: pgbuilders.Options{CopyThreshold: 3}},
+			"COPY users (name, age) FROM STDIN"},
+		{"CockroachDB", `BULK INSERT User WITH [name = "a", age = 1] [name = "b", age = 2]`,
+			Options{PostgreSQL: pgbuilders.Options{CopyThreshold: 3}},
+			"COPY users (name, age) FROM STDIN"},
+		{"Elasticsearch", `GET User`,
+			Options{Elasticsearch: esbuilders.Options{MaxResultWindow: 500}},
+			`GET /users/_search {"size":500}`},
+		{"Elasticsearch", `COUNT * FROM User GROUP BY role`,
+			Options{Elasticsearch: esbuilders.Options{TermsSize: 50}},
+			`GET /users/_search {"aggs":{"role":{"terms":{"field":"role","size":50}}},"size":0}`},
+		{"MongoDB", `GET User WHERE active = true AND day = "2024-01-02"`,
+			Options{MongoDB: mongobuilders.Options{UntypedValues: true}},
+			`{"filter":{"active":"true","day":"2024-01-02"},"find":"users"}`},
+		{"Redis", `GET User WHERE id = 1`,
+			Options{Redis: redisbuilders.Options{HashTags: true}},
+			"HGETALL {tenant:t1:user}:1"},
+		{"Redis", `GET User WHERE body SEARCH "x"`,
+			Options{Redis: redisbuilders.Options{RediSearch: true}},
+			`FT.SEARCH tenant:t1:_ft:user "@body:(x)" LIMIT 0 10000`},
+	}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := TranslateWithOptions(query, tt.db, "t1", tt.opts)
+		if err != nil {
+			t.Errorf("%s: TranslateWithOptions(%q): %v", tt.db, tt.input, err)
+			continue
+		}
+		if got := queryText(result); got != tt.want {
+			t.Errorf("%s: TranslateWithOptions(%q):\n got %s\nwant %s", tt.db, tt.input, got, tt.want)
+		}
+		defaults, err := Translate(query, tt.db, "t1")
+		if err == nil && queryText(defaults) == tt.want {
+			t.Errorf("%s: Translate(%q) used the options of an earlier TranslateWithOptions", tt.db, tt.input)
+		}
+	}
+
+	// A Cassandra read must name the partition of a table listed in the options
+	query, err := parser.Parse(`GET Event WHERE day = "x"`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	opts := Options{Cassandra: cqlbuilders.Options{PartitionKeys: map[string][]string{"events": {"user_id"}}}}
+	if _, err := TranslateWithOptions(query, "Cassandra", "t1", opts); err == nil {
+		t.Error("Cassandra: TranslateWithOptions without the partition key: expected an error")
+	}
+	if _, err := Translate(query, "Cassandra", "t1"); err != nil {
+		t.Errorf("Cassandra: Translate: %v", err)
+	}
+}
+
+// A BULK INSERT loaded with COPY lines each row's values up with the first
+// row's columns, and rejects rows that set other columns
+func TestPostgreSQLCopyRows(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`BULK INSERT User WITH [name = "a", age = 1] [name = "b", age = 2]`, "[[a 1] [b 2]]"},
+		{`BULK INSERT User WITH [name = "a", age = 1] [age = 2, name = "b"]`, "[[a 1] [b 2]]"},
+		{`BULK INSERT User WITH [name = "a", age = 1] [name = "b"]`, ""},
+		{`BULK INSERT User WITH [name = "a", age = 1] [name = "b", email = "c"]`, ""},
+		{`BULK INSERT User WITH [name = "a"] [name = "b", age = 2]`, ""},
+	}
+	opts := Options{PostgreSQL: pgbuilders.Options{CopyThreshold: 1}}
+	for _, tt := range tests {
+		query, err := parser.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		result, err := TranslateWithOptions(query, "PostgreSQL", "", opts)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("TranslateWithOptions(%q) = %s, want an error", tt.input, queryText(result))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("TranslateWithOptions(%q): %v", tt.input, err)
+			continue
+		}
+		_, _, rows, err := pgbuilders.BuildCopyRows(result.GetRelational())
+		if err != nil {
+			t.Errorf("BuildCopyRows(%q): %v", tt.input, err)
+			continue
+		}
+		if got := fmt.Sprint(rows); got != tt.want {
+			t.Errorf("BuildCopyRows(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package oql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/translator"
+)
+
+// ============================================
+// EXPLAIN (normalized query plans)
+// ============================================
+
+// PlanNode is one step of a query plan, normalized across databases.
+// Node types use PostgreSQL's vocabulary (Seq Scan, Index Scan, Nested Loop,
+// Sort, Aggregate, ...) so plans from different dialects can be compared.
+// Rows is the estimated number of rows the step produces; Cost is the
+// optimizer's estimate in the database's own units, children included.
+type PlanNode struct {
+	NodeType string
+	Table    string
+	Index    string
+	Rows     float64
+	Cost     float64
+	Children []*PlanNode
+}
+
+// Explain returns the optimizer's plan for a query without running it
+func (c *Client) Explain(input string) (*PlanNode, error) {
+	query, isOQL, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if !isOQL {
+		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
+	}
+
+	if c.dbType != "MySQL" {
+		return nil, fmt.Errorf("EXPLAIN is not supported on %s", c.dbType)
+	}
+
+	result, err := translator.TranslateWithOptions(query, c.dbType, c.tenantID, c.options)
+	if err != nil {
+		return nil, fmt.Errorf("translation error: %w", err)
+	}
+
+	var raw string
+	explainSQL := "EXPLAIN FORMAT=JSON " + result.GetRelational().Sql
+	if err := c.sqlDB.QueryRowContext(c.ctx, explainSQL).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("explain error: %w", err)
+	}
+	return parseMySQLPlan([]byte(raw))
+}
+
+// ============================================
+// MYSQL ADAPTER (EXPLAIN FORMAT=JSON)
+// ============================================
+
+// parseMySQLPlan normalizes the query_block tree of EXPLAIN FORMAT=JSON
+func parseMySQLPlan(data []byte) (*PlanNode, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid MySQL plan: %w", err)
+	}
+	block, ok := doc["query_block"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid MySQL plan: missing query_block")
+	}
+	return mysqlQueryBlock(block), nil
+}
+
+// mysqlQueryBlock converts a query_block; its query_cost is the total for the block
+func mysqlQueryBlock(block map[string]any) *PlanNode {
+	node := mysqlPlanOperation(block)
+	if cost, ok := jsonObject(block, "cost_info")["query_cost"]; ok {
+		node.Cost = jsonNumber(cost)
+	}
+	return node
+}
+
+// mysqlPlanOperation converts the single operation nested in a block:
+// ordering, grouping, duplicate removal, union, join or table access
+func mysqlPlanOperation(block map[string]any) *PlanNode {
+	if op := jsonObject(block, "ordering_operation"); op != nil {
+		child := mysqlPlanOperation(op)
+		// Without a filesort MySQL reads rows in index order - nothing to sort
+		if using, _ := op["using_filesort"].(bool); !using {
+			return child
+		}
+		sortCost := jsonNumber(jsonObject(op, "cost_info")["sort_cost"])
+		return &PlanNode{NodeType: "Sort", Rows: child.Rows, Cost: child.Cost + sortCost, Children: []*PlanNode{child}}
+	}
+	if op := jsonObject(block, "grouping_operation"); op != nil {
+		child := mysqlPlanOperation(op)
+		return &PlanNode{NodeType: "Aggregate", Rows: child.Rows, Cost: child.Cost, Children: []*PlanNode{child}}
+	}
+	if op := jsonObject(block, "duplicates_removal"); op != nil {
+		child := mysqlPlanOperation(op)
+		return &PlanNode{NodeType: "Unique", Rows: child.Rows, Cost: child.Cost, Children: []*PlanNode{child}}
+	}
+	if op := jsonObject(block, "union_result"); op != nil {
+		node := &PlanNode{NodeType: "Append"}
+		specs, _ := op["query_specifications"].([]any)
+		for _, spec := range specs {
+			if qb := jsonObject(spec, "query_block"); qb != nil {
+				child := mysqlQueryBlock(qb)
+				node.Rows += child.Rows
+				node.Cost += child.Cost
+				node.Children = append(node.Children, child)
+			}
+		}
+		return node
+	}
+	if loop, ok := block["nested_loop"].([]any); ok {
+		return mysqlNestedLoop(loop)
+	}
+	if table := jsonObject(block, "table"); table != nil {
+		return mysqlTablePlan(table)
+	}
+	// "message": "No tables used" and friends
+	return &PlanNode{NodeType: "Result"}
+}
+
+// mysqlNestedLoop turns MySQL's flat join order into a left-deep tree of
+// Nested Loop nodes; each table's prefix cost and rows cover the join so far
+func mysqlNestedLoop(loop []any) *PlanNode {
+	var plan *PlanNode
+	for _, step := range loop {
+		table := jsonObject(step, "table")
+		if table == nil {
+			continue
+		}
+		inner := mysqlTablePlan(table)
+		if plan == nil {
+			plan = inner
+			continue
+		}
+		costInfo := jsonObject(table, "cost_info")
+		plan = &PlanNode{
+			NodeType: "Nested Loop",
+			Rows:     jsonNumber(table["rows_produced_per_join"]),
+			Cost:     jsonNumber(costInfo["prefix_cost"]),
+			Children: []*PlanNode{plan, inner},
+		}
+	}
+	if plan == nil {
+		return &PlanNode{NodeType: "Result"}
+	}
+	return plan
+}
+
+// mysqlTablePlan converts one table access, including derived tables and
+// subqueries attached to its condition
+func mysqlTablePlan(table map[string]any) *PlanNode {
+	usingIndex, _ := table["using_index"].(bool)
+	costInfo := jsonObject(table, "cost_info")
+	node := &PlanNode{
+		NodeType: mysqlAccessNodeType(jsonString(table["access_type"]), usingIndex),
+		Table:    jsonString(table["table_name"]),
+		Index:    jsonString(table["key"]),
+		Rows:     jsonNumber(table["rows_examined_per_scan"]),
+		Cost:     jsonNumber(costInfo["read_cost"]) + jsonNumber(costInfo["eval_cost"]),
+	}
+	// filtered is the percentage of examined rows that survive the condition
+	if filtered, ok := table["filtered"]; ok {
+		node.Rows = node.Rows * jsonNumber(filtered) / 100
+	}
+
+	if derived := jsonObject(table, "materialized_from_subquery"); derived != nil {
+		node.NodeType = "Subquery Scan"
+		if qb := jsonObject(derived, "query_block"); qb != nil {
+			node.Children = append(node.Children, mysqlQueryBlock(qb))
+		}
+	}
+	subqueries, _ := table["attached_subqueries"].([]any)
+	for _, sub := range subqueries {
+		if qb := jsonObject(sub, "query_block"); qb != nil {
+			node.Children = append(node.Children, mysqlQueryBlock(qb))
+		}
+	}
+	return node
+}
+
+// mysqlAccessNodeType maps MySQL's access_type to a common node type
+func mysqlAccessNodeType(accessType string, usingIndex bool) string {
+	switch strings.ToLower(accessType) {
+	case "all":
+		return "Seq Scan"
+	case "index", "range", "ref", "eq_ref", "ref_or_null", "const", "system",
+		"fulltext", "index_merge", "unique_subquery", "index_subquery":
+		if usingIndex {
+			return "Index Only Scan"
+		}
+		return "Index Scan"
+	default:
+		return "Scan"
+	}
+}
+
+// jsonObject returns v[key] as an object, or nil
+func jsonObject(v any, key string) map[string]any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	child, _ := obj[key].(map[string]any)
+	return child
+}
+
+// jsonNumber reads a plan number; MySQL writes costs as strings ("1.20")
+func jsonNumber(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// jsonString reads a plan string, or ""
+func jsonString(v any) string {
+	s, _ := v.(string)
+	return s
+}
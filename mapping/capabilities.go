@@ -0,0 +1,181 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FeatureMap - query features beyond an operation and its operators, per database
+// Usage: FeatureMap["MongoDB"]["FACET"] returns true
+// A feature a database does not list is rejected before translation
+var FeatureMap = map[string]map[string]bool{
+	"PostgreSQL": {
+		"COLLATE":         true,
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // ON CONFLICT DO NOTHING
+		"WATCH":           true, // LISTEN/NOTIFY triggers
+	},
+	"MySQL": {
+		"COLLATE":         true,
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // INSERT IGNORE
+		"REPLACE FROM":    true,
+	},
+	"SQLite": {
+		"COLLATE":         true,
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // ON CONFLICT DO NOTHING
+		"REPLACE FROM":    true,
+	},
+	"Oracle": {
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // MERGE without WHEN MATCHED
+	},
+	"SQLServer": {
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true, // SELECT ... INTO
+		"DO NOTHING":      true, // MERGE without WHEN MATCHED
+	},
+	"CockroachDB": {
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // ON CONFLICT DO NOTHING
+		"REPLACE FROM":    true, // UPSERT INTO ... SELECT
+		"AS OF":           true, // AS OF SYSTEM TIME
+	},
+	"ClickHouse": {
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"FINAL":           true, // merge rows of the same sorting key on read
+		"SAMPLE":          true, // needs a SAMPLE BY key on the table
+	},
+	"BigQuery": {
+		"CREATE FROM":        true,
+		"CREATE TABLE AS":    true,
+		"PARTITION BY DAY":   true, // time-unit column partitioning
+		"PARTITION BY HOUR":  true,
+		"PARTITION BY MONTH": true,
+		"PARTITION BY YEAR":  true,
+		"CLUSTER BY":         true,
+	},
+	"Snowflake": {
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // MERGE without WHEN MATCHED
+		"AS OF":           true, // Time Travel: FROM t AT(...)
+		"BEFORE":          true, // FROM t BEFORE(...)
+		"CLUSTER BY":      true, // clustering key
+	},
+	"Cassandra": {
+		"TTL":           true, // USING TTL
+		"IF NOT EXISTS": true, // lightweight transaction
+		"DO NOTHING":    true, // INSERT ... IF NOT EXISTS
+		"CLUSTER BY":    true, // clustering columns of the primary key
+	},
+	"MongoDB": {
+		"FACET":           true,
+		"CTE DEPTH":       true,
+		"TTL":             true, // TTL index on expireAt
+		"COLLATE":         true,
+		"CREATE FROM":     true,
+		"CREATE TABLE AS": true,
+		"DO NOTHING":      true, // $setOnInsert only
+		"REPLACE FROM":    true,
+		"UPSERT FROM":     true, // $merge
+		"WATCH":           true, // change streams
+		"BULK WRITE":      true,
+	},
+	"Redis": {
+		"TTL": true, // EXPIRE
+	},
+}
+
+// StandardOperators - operators every registered dialect is expected to
+// build; RegisterDatabase gives a new database these as its OperatorMap
+var StandardOperators = map[string]string{
+	"=":           "=",
+	"!=":          "!=",
+	">":           ">",
+	"<":           "<",
+	">=":          ">=",
+	"<=":          "<=",
+	"IN":          "IN",
+	"NOT_IN":      "NOT IN",
+	"BETWEEN":     "BETWEEN",
+	"NOT_BETWEEN": "NOT BETWEEN",
+	"LIKE":        "LIKE",
+	"NOT_LIKE":    "NOT LIKE",
+	"IS_NULL":     "IS NULL",
+	"IS_NOT_NULL": "IS NOT NULL",
+	"AND":         "AND",
+	"OR":          "OR",
+	"NOT":         "NOT",
+}
+
+// ErrNotSupported is returned (possibly wrapped) for an operation, operator
+// or feature a database does not have. Use errors.As to extract it.
+type ErrNotSupported struct {
+	Database    string   // PostgreSQL, MySQL, ... or a registered dialect
+	Feature     string   // GRANT, NEAR, FACET, ...
+	SupportedBy []string // databases that do support it
+}
+
+func (e *ErrNotSupported) Error() string {
+	msg := fmt.Sprintf("%s is not supported in %s", e.Feature, e.Database)
+	if len(e.SupportedBy) > 0 {
+		msg += " (supported in " + joinNames(e.SupportedBy) + ")"
+	}
+	return msg
+}
+
+// NotSupported returns the ErrNotSupported for feature in dbType, listing
+// the databases that supports (SupportsOperation, ...) reports do have it
+func NotSupported(dbType, feature string, supports func(dbType, feature string) bool) error {
+	var supportedBy []string
+	for _, db := range SupportedDatabases {
+		if _, mapped := OperationMap[db]; mapped && db != dbType && supports(db, feature) {
+			supportedBy = append(supportedBy, db)
+		}
+	}
+	return &ErrNotSupported{Database: dbType, Feature: feature, SupportedBy: supportedBy}
+}
+
+// SupportsOperation reports whether dbType runs an OQL operation: it has an
+// OperationMap entry that is not "" or "unsupported"
+func SupportsOperation(dbType, operation string) bool {
+	native := OperationMap[dbType][strings.ToUpper(operation)]
+	return native != "" && native != "unsupported"
+}
+
+// SupportsOperator reports whether dbType evaluates a condition operator:
+// it has an OperatorMap entry. Operators no database lists (GROUP, bare
+// expressions) are not tracked and always supported.
+func SupportsOperator(dbType, operator string) bool {
+	operator = strings.ToUpper(operator)
+	if _, ok := OperatorMap[dbType][operator]; ok {
+		return true
+	}
+	for _, operators := range OperatorMap {
+		if _, ok := operators[operator]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SupportsFeature reports whether dbType has a FeatureMap feature
+func SupportsFeature(dbType, feature string) bool {
+	return FeatureMap[dbType][strings.ToUpper(feature)]
+}
+
+// joinNames writes a list for a message: "A", "A and B", "A, B and C"
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -6,6 +6,7 @@ type ClauseDefinition struct {
 	Parsers    []string // Which parsers can use this: CRUD, DQL, DDL, TCL, DCL
 	ValueType  string   // NUMERIC, STRING, BOOLEAN, FIELD_LIST, CONDITION, NONE
 	Terminates bool     // true = ends expression parsing (WHERE, LIMIT), false = part of expression (OVER)
+	Contextual bool     // true = a clause only after a complete expression; elsewhere a name (size, page)
 }
 
 // QueryClauses defines all available OQL clauses
@@ -24,6 +25,27 @@ var QueryClauses = map[string]ClauseDefinition{
 		ValueType:  "NUMERIC",
 		Terminates: true,
 	},
+	"PAGE": {
+		Keyword:    "PAGE",
+		Parsers:    []string{"CRUD", "DQL"},
+		ValueType:  "NUMERIC",
+		Terminates: true,
+		Contextual: true,
+	},
+	"SIZE": {
+		Keyword:    "SIZE",
+		Parsers:    []string{"CRUD", "DQL"},
+		ValueType:  "NUMERIC",
+		Terminates: true,
+		Contextual: true,
+	},
+	"AFTER": {
+		Keyword:    "AFTER",
+		Parsers:    []string{"CRUD", "DQL"},
+		ValueType:  "STRING",
+		Terminates: true,
+		Contextual: true,
+	},
 
 	// ========== FILTERING ==========
 	"WHERE": {
@@ -52,6 +74,13 @@ var QueryClauses = map[string]ClauseDefinition{
 		ValueType:  "FIELD_LIST",
 		Terminates: true,
 	},
+	"FACET": {
+		Keyword:    "FACET",
+		Parsers:    []string{"DQL"},
+		ValueType:  "FIELD_LIST",
+		Terminates: true,
+		Contextual: true,
+	},
 	"DISTINCT": {
 		Keyword:    "DISTINCT",
 		Parsers:    []string{"CRUD", "DQL"},
@@ -59,6 +88,38 @@ var QueryClauses = map[string]ClauseDefinition{
 		Terminates: false,
 	},
 
+	// ========== RESULT ROWS ==========
+	"RETURNING": {
+		Keyword:    "RETURNING",
+		Parsers:    []string{"CRUD"},
+		ValueType:  "FIELD_LIST",
+		Terminates: true,
+		Contextual: true,
+	},
+
+	// ========== ROW LOCKING ==========
+	"FOR UPDATE": {
+		Keyword:    "FOR UPDATE",
+		Parsers:    []string{"CRUD"},
+		ValueType:  "NONE",
+		Terminates: true,
+	},
+	"FOR SHARE": {
+		Keyword:    "FOR SHARE",
+		Parsers:    []string{"CRUD"},
+		ValueType:  "NONE",
+		Terminates: true,
+	},
+
+	// ========== COLLATION ==========
+	"COLLATE": {
+		Keyword:    "COLLATE",
+		Parsers:    []string{"CRUD", "DQL"},
+		ValueType:  "STRING",
+		Terminates: true,
+		Contextual: true,
+	},
+
 	// ========== FIELD ASSIGNMENTS ==========
 	"WITH": {
 		Keyword:    "WITH",
@@ -162,6 +223,22 @@ func IsTerminatingClause(keyword string) bool {
 	return false
 }
 
+// IsContextualClause checks if a clause keyword is also a valid name
+// Contextual clauses (PAGE, SIZE, ...) are common column names, so they are
+// clauses only where a clause can follow: after a complete expression, never
+// as an operand or an ORDER BY key
+func IsContextualClause(keyword string) bool {
+	if def, exists := QueryClauses[keyword]; exists {
+		return def.Contextual
+	}
+	return false
+}
+
+// IsReservedClause checks if a keyword is a clause wherever it appears
+func IsReservedClause(keyword string) bool {
+	return IsClause(keyword) && !IsContextualClause(keyword)
+}
+
 // GetClauseValueType returns the expected value type for a clause
 func GetClauseValueType(clause string) string {
 	if def, exists := QueryClauses[clause]; exists {
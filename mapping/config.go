@@ -0,0 +1,276 @@
+package mapping
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config - the mapping tables that can be exported to and loaded from a
+// YAML or JSON file, keyed by database like the maps they fill
+//
+//	operations:
+//	  MySQL: {UPSERT: "insert_on_duplicate"}
+//	types:
+//	  PostgreSQL: {TEXT: CITEXT}
+//	column_naming:
+//	  PostgreSQL: snake_case
+//	table_names:
+//	  Person: persons
+type Config struct {
+	Operations   map[string]map[string]string          `json:"operations,omitempty" yaml:"operations,omitempty"`
+	Types        map[string]map[string]string          `json:"types,omitempty" yaml:"types,omitempty"`
+	Operators    map[string]map[string]string          `json:"operators,omitempty" yaml:"operators,omitempty"`
+	Functions    map[string]map[string]FunctionMapping `json:"functions,omitempty" yaml:"functions,omitempty"`
+	Features     map[string]map[string]bool            `json:"features,omitempty" yaml:"features,omitempty"`
+	ColumnNaming map[string]string                     `json:"column_naming,omitempty" yaml:"column_naming,omitempty"`
+	FieldNaming  string                                `json:"field_naming,omitempty" yaml:"field_naming,omitempty"`
+	TableNames   map[string]string                     `json:"table_names,omitempty" yaml:"table_names,omitempty"`
+	Irregular    map[string]string                     `json:"irregular,omitempty" yaml:"irregular,omitempty"`
+	Uncountable  []string                              `json:"uncountable,omitempty" yaml:"uncountable,omitempty"`
+}
+
+// ExportConfig returns a copy of the current mapping tables
+func ExportConfig() *Config {
+	cfg := &Config{
+		Operations:   copyTables(OperationMap),
+		Types:        copyTables(TypeMap),
+		Operators:    copyTables(OperatorMap),
+		Functions:    copyTables(FunctionMap),
+		Features:     copyTables(FeatureMap),
+		ColumnNaming: copyTable(ColumnNaming),
+		FieldNaming:  FieldNaming,
+		TableNames:   copyTable(TableNameOverrides),
+		Irregular:    copyTable(IrregularPlurals),
+		Uncountable:  append([]string(nil), UncountableWords...),
+	}
+	return cfg
+}
+
+// Marshal writes the config as "yaml" or "json"
+func (c *Config) Marshal(format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return yaml.Marshal(c)
+	case "json":
+		return json.MarshalIndent(c, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown config format %q (use yaml or json)", format)
+	}
+}
+
+// ParseConfig reads a YAML or JSON config (JSON is read as YAML)
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// LoadConfig reads a config file and merges it into the mapping tables
+func LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := MergeConfig(cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveConfig writes the current mapping tables to a file, as JSON for a
+// .json path and YAML otherwise
+func SaveConfig(path string) error {
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+	data, err := ExportConfig().Marshal(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// MergeConfig overlays cfg on the mapping tables: each entry replaces the
+// one it names and entries it leaves out are kept. The whole config is
+// checked first, so an invalid one changes nothing. Databases must already
+// be supported (a new one needs its dialect registered). Like the maps it
+// changes, it is not safe for use concurrently with translation.
+func MergeConfig(cfg *Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	for db, ops := range cfg.Operations {
+		if OperationMap[db] == nil {
+			OperationMap[db] = map[string]string{}
+		}
+		if TranslatedToGroup[db] == nil {
+			TranslatedToGroup[db] = map[string]string{}
+		}
+		for op, native := range ops {
+			op = strings.ToUpper(op)
+			OperationMap[db][op] = native
+			if group, exists := OperationGroups[op]; exists {
+				TranslatedToGroup[db][native] = group
+			}
+		}
+	}
+	for db, types := range cfg.Types {
+		for oqlType, native := range types {
+			if err := RegisterType(db, oqlType, native); err != nil {
+				return err
+			}
+		}
+	}
+	for db, operators := range cfg.Operators {
+		if OperatorMap[db] == nil {
+			OperatorMap[db] = map[string]string{}
+		}
+		for operator, native := range operators {
+			OperatorMap[db][strings.ToUpper(operator)] = native
+		}
+	}
+	for db, functions := range cfg.Functions {
+		for function, spelling := range functions {
+			RegisterFunction(db, function, spelling)
+		}
+	}
+	for db, features := range cfg.Features {
+		if FeatureMap[db] == nil {
+			FeatureMap[db] = map[string]bool{}
+		}
+		for feature, on := range features {
+			FeatureMap[db][strings.ToUpper(feature)] = on
+		}
+	}
+	for db, convention := range cfg.ColumnNaming {
+		if err := SetColumnNaming(db, convention); err != nil {
+			return err
+		}
+	}
+	if cfg.FieldNaming != "" {
+		FieldNaming = cfg.FieldNaming
+	}
+	for entity, table := range cfg.TableNames {
+		RegisterTableName(entity, table)
+	}
+	for singular, plural := range cfg.Irregular {
+		RegisterIrregular(singular, plural)
+	}
+	for _, word := range cfg.Uncountable {
+		if !isUncountable(word) {
+			RegisterUncountable(word)
+		}
+	}
+	return nil
+}
+
+// validate checks a config before it is merged
+func (c *Config) validate() error {
+	var problems []string
+	checkDB := func(section, db string) {
+		if !IsSupportedDatabase(db) {
+			problems = append(problems, fmt.Sprintf("%s: unknown database type: %s", section, db))
+		}
+	}
+
+	for _, db := range sortedKeys(c.Operations) {
+		checkDB("operations", db)
+		for _, op := range sortedKeys(c.Operations[db]) {
+			if !isOperation(op) {
+				problems = append(problems, fmt.Sprintf("operations: %s: unknown OQL operation %s", db, op))
+			}
+		}
+	}
+	for _, db := range sortedKeys(c.Types) {
+		checkDB("types", db)
+		if _, ok := TypeMap[db]; !ok && IsSupportedDatabase(db) {
+			problems = append(problems, fmt.Sprintf("types: %s has no type map", db))
+		}
+		for _, oqlType := range sortedKeys(c.Types[db]) {
+			name := strings.TrimSpace(oqlType)
+			if name == "" || strings.ContainsAny(name, "()[]: ") {
+				problems = append(problems, fmt.Sprintf("types: %s: type name %q must be a bare name", db, oqlType))
+			}
+			if strings.TrimSpace(c.Types[db][oqlType]) == "" {
+				problems = append(problems, fmt.Sprintf("types: %s: native type for %s is empty", db, oqlType))
+			}
+		}
+	}
+	for _, db := range sortedKeys(c.Operators) {
+		checkDB("operators", db)
+	}
+	for _, db := range sortedKeys(c.Functions) {
+		checkDB("functions", db)
+	}
+	for _, db := range sortedKeys(c.Features) {
+		checkDB("features", db)
+	}
+	for _, db := range sortedKeys(c.ColumnNaming) {
+		checkDB("column_naming", db)
+		if !isNamingConvention(c.ColumnNaming[db]) {
+			problems = append(problems, fmt.Sprintf("column_naming: %s: unknown naming convention %q", db, c.ColumnNaming[db]))
+		}
+	}
+	if c.FieldNaming != "" && !isNamingConvention(c.FieldNaming) {
+		problems = append(problems, fmt.Sprintf("field_naming: unknown naming convention %q", c.FieldNaming))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid mapping config:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// isOperation reports whether op is an OQL operation or clause some
+// database maps
+func isOperation(op string) bool {
+	op = strings.ToUpper(op)
+	if _, ok := OperationGroups[op]; ok {
+		return true
+	}
+	for _, ops := range OperationMap {
+		if _, ok := ops[op]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func copyTables[V any](tables map[string]map[string]V) map[string]map[string]V {
+	out := make(map[string]map[string]V, len(tables))
+	for db, table := range tables {
+		out[db] = copyTable(table)
+	}
+	return out
+}
+
+func copyTable[V any](table map[string]V) map[string]V {
+	out := make(map[string]V, len(table))
+	for k, v := range table {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,8 +5,17 @@ package mapping
 var SupportedDatabases = []string{
 	"PostgreSQL",
 	"MySQL",
+	"SQLite",
+	"Oracle",
+	"SQLServer",
+	"CockroachDB",
+	"ClickHouse",
+	"BigQuery",
+	"Snowflake",
+	"Cassandra",
 	"QuestDB",
 	"MongoDB",
+	"Elasticsearch",
 	"Redis",
 }
 
@@ -18,4 +27,28 @@ func IsSupportedDatabase(dbType string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// RegisterDatabase adds a database type with its OQL operation -> native
+// operation and universal type -> native type mappings; its operators are
+// the StandardOperators and it has no FeatureMap features. It backs
+// translator.RegisterDialect and, like the maps it fills, is not safe for
+// use concurrently with translation: call it from an init function.
+func RegisterDatabase(dbType string, operations, types map[string]string) {
+	if !IsSupportedDatabase(dbType) {
+		SupportedDatabases = append(SupportedDatabases, dbType)
+	}
+	OperationMap[dbType] = operations
+	TypeMap[dbType] = types
+	OperatorMap[dbType] = make(map[string]string)
+	for operator, native := range StandardOperators {
+		OperatorMap[dbType][operator] = native
+	}
+
+	TranslatedToGroup[dbType] = make(map[string]string)
+	for oqlOp, translated := range operations {
+		if group, exists := OperationGroups[oqlOp]; exists {
+			TranslatedToGroup[dbType][translated] = group
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package mapping
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FunctionMapping is how one database spells an OQL scalar function
+type FunctionMapping struct {
+	Name     string   `json:"name,omitempty" yaml:"name,omitempty"`         // native function or operator: date_trunc, $dateTrunc
+	Template string   `json:"template,omitempty" yaml:"template,omitempty"` // SQL call with $1, $2, ... for the arguments, when it is not Name(args)
+	Args     []string `json:"args,omitempty" yaml:"args,omitempty"`         // MongoDB: argument names of an operator that takes a document
+}
+
+// DATE_TRUNC for databases without date_trunc: format the date down to the
+// unit ($1), in MySQL DATE_FORMAT and SQLite strftime format codes, pick the
+// Oracle TRUNC format model, or count whole units from SQL Server's day 0
+// (1900-01-01, a Monday) and add them back
+const (
+	mysqlTruncFormat = "CASE LOWER($1) WHEN 'year' THEN '%Y-01-01 00:00:00' WHEN 'month' THEN '%Y-%m-01 00:00:00' " +
+		"WHEN 'day' THEN '%Y-%m-%d 00:00:00' WHEN 'hour' THEN '%Y-%m-%d %H:00:00' " +
+		"WHEN 'minute' THEN '%Y-%m-%d %H:%i:00' ELSE '%Y-%m-%d %H:%i:%S' END"
+	sqliteTruncFormat = "CASE LOWER($1) WHEN 'year' THEN '%Y-01-01 00:00:00' WHEN 'month' THEN '%Y-%m-01 00:00:00' " +
+		"WHEN 'day' THEN '%Y-%m-%d 00:00:00' WHEN 'hour' THEN '%Y-%m-%d %H:00:00' " +
+		"WHEN 'minute' THEN '%Y-%m-%d %H:%M:00' ELSE '%Y-%m-%d %H:%M:%S' END"
+	oracleTruncFormat = "CASE LOWER($1) WHEN 'year' THEN 'YYYY' WHEN 'month' THEN 'MM' WHEN 'week' THEN 'IW' " +
+		"WHEN 'day' THEN 'DD' WHEN 'hour' THEN 'HH24' ELSE 'MI' END"
+	mssqlTruncExpr = "CASE LOWER($1) WHEN 'year' THEN DATEADD(year, DATEDIFF(year, 0, $2), 0) " +
+		"WHEN 'month' THEN DATEADD(month, DATEDIFF(month, 0, $2), 0) WHEN 'week' THEN DATEADD(week, DATEDIFF(week, 0, $2), 0) " +
+		"WHEN 'day' THEN DATEADD(day, DATEDIFF(day, 0, $2), 0) WHEN 'hour' THEN DATEADD(hour, DATEDIFF(hour, 0, $2), 0) " +
+		"ELSE DATEADD(minute, DATEDIFF(minute, 0, $2), 0) END"
+	bigqueryTruncExpr = "CASE LOWER($1) WHEN 'year' THEN TIMESTAMP_TRUNC($2, YEAR) WHEN 'month' THEN TIMESTAMP_TRUNC($2, MONTH) " +
+		"WHEN 'week' THEN TIMESTAMP_TRUNC($2, ISOWEEK) WHEN 'day' THEN TIMESTAMP_TRUNC($2, DAY) " +
+		"WHEN 'hour' THEN TIMESTAMP_TRUNC($2, HOUR) ELSE TIMESTAMP_TRUNC($2, MINUTE) END"
+)
+
+// FunctionMap - OQL scalar function -> per-database spelling
+// Usage: FunctionMap["MySQL"]["DATE_TRUNC"]
+// Functions a database does not list are written as called: UPPER(name)
+var FunctionMap = map[string]map[string]FunctionMapping{
+	"PostgreSQL": {
+		"DATE_TRUNC": {Name: "date_trunc"},
+		"IFNULL":     {Name: "COALESCE"},
+	},
+
+	"MySQL": {
+		"DATE_TRUNC": {Name: "DATE_FORMAT", Template: "DATE_FORMAT($2, " + mysqlTruncFormat + ")"},
+		"STRPOS":     {Name: "LOCATE", Template: "LOCATE($2, $1)"},
+	},
+
+	"SQLite": {
+		"DATE_TRUNC": {Name: "strftime", Template: "strftime(" + sqliteTruncFormat + ", $2)"},
+		"NOW":        {Name: "datetime", Template: "datetime('now')"},
+		"STRPOS":     {Name: "instr"},
+	},
+
+	"Oracle": {
+		"DATE_TRUNC": {Name: "TRUNC", Template: "TRUNC($2, " + oracleTruncFormat + ")"},
+		"IFNULL":     {Name: "NVL"},
+		"NOW":        {Name: "SYSTIMESTAMP", Template: "SYSTIMESTAMP"},
+		"STRPOS":     {Name: "INSTR"},
+		"SUBSTRING":  {Name: "SUBSTR"},
+	},
+
+	"SQLServer": {
+		"DATE_TRUNC": {Name: "DATEADD", Template: mssqlTruncExpr},
+		"IFNULL":     {Name: "ISNULL"},
+		"NOW":        {Name: "SYSDATETIME", Template: "SYSDATETIME()"},
+		"STRPOS":     {Name: "CHARINDEX", Template: "CHARINDEX($2, $1)"},
+		"LENGTH":     {Name: "LEN"},
+		"CEIL":       {Name: "CEILING"},
+	},
+
+	"ClickHouse": {
+		"DATE_TRUNC": {Name: "dateTrunc"},
+		"IFNULL":     {Name: "ifNull"},
+		"NOW":        {Name: "now"},
+		"STRPOS":     {Name: "positionUTF8"},
+		"LENGTH":     {Name: "lengthUTF8"},
+		"UPPER":      {Name: "upperUTF8"},
+		"LOWER":      {Name: "lowerUTF8"},
+		"SUBSTRING":  {Name: "substringUTF8"},
+		"STDDEV":     {Name: "stddevSamp"},
+		"VARIANCE":   {Name: "varSamp"},
+
+		// Arrays are 1-based, as in PostgreSQL
+		"ARRAY_LENGTH":    {Name: "length", Template: "length($1)"},
+		"CARDINALITY":     {Name: "length"},
+		"ARRAY_APPEND":    {Name: "arrayPushBack"},
+		"ARRAY_PREPEND":   {Name: "arrayPushFront", Template: "arrayPushFront($2, $1)"},
+		"ARRAY_REMOVE":    {Name: "arrayFilter", Template: "arrayFilter(x -> x != $2, $1)"},
+		"ARRAY_POSITION":  {Name: "indexOf"},
+		"ARRAY_TO_STRING": {Name: "arrayStringConcat"},
+		"ARRAY_AGG":       {Name: "groupArray"},
+		"STRING_AGG":      {Name: "arrayStringConcat", Template: "arrayStringConcat(groupArray($1), $2)"},
+		"UNNEST":          {Name: "arrayJoin"},
+	},
+
+	"BigQuery": {
+		"DATE_TRUNC": {Name: "TIMESTAMP_TRUNC", Template: bigqueryTruncExpr},
+		"NOW":        {Name: "CURRENT_TIMESTAMP", Template: "CURRENT_TIMESTAMP()"},
+		"SUBSTRING":  {Name: "SUBSTR"},
+
+		// Arrays are 0-based (arr[OFFSET(0)]); positions are returned 1-based, as in PostgreSQL
+		"CARDINALITY":    {Name: "ARRAY_LENGTH"},
+		"ARRAY_APPEND":   {Name: "ARRAY_CONCAT", Template: "ARRAY_CONCAT($1, [$2])"},
+		"ARRAY_PREPEND":  {Name: "ARRAY_CONCAT", Template: "ARRAY_CONCAT([$1], $2)"},
+		"ARRAY_REMOVE":   {Name: "ARRAY", Template: "ARRAY(SELECT x FROM UNNEST($1) AS x WHERE x != $2)"},
+		"ARRAY_POSITION": {Name: "UNNEST", Template: "(SELECT MIN(o) + 1 FROM UNNEST($1) AS x WITH OFFSET o WHERE x = $2)"},
+	},
+
+	"Snowflake": {
+		"NOW":    {Name: "CURRENT_TIMESTAMP", Template: "CURRENT_TIMESTAMP()"},
+		"STRPOS": {Name: "POSITION", Template: "POSITION($2, $1)"},
+
+		// Arrays of VARIANTs, 0-based; positions are returned 1-based, as in PostgreSQL
+		"CARDINALITY":    {Name: "ARRAY_SIZE"},
+		"ARRAY_PREPEND":  {Name: "ARRAY_PREPEND", Template: "ARRAY_PREPEND($2, $1)"},
+		"ARRAY_POSITION": {Name: "ARRAY_POSITION", Template: "ARRAY_POSITION($2::VARIANT, $1) + 1"},
+	},
+
+	"Cassandra": {
+		"NOW": {Name: "toTimestamp", Template: "toTimestamp(now())"},
+	},
+
+	"MongoDB": {
+		"UPPER":      {Name: "$toUpper"},
+		"LOWER":      {Name: "$toLower"},
+		"CONCAT":     {Name: "$concat"},
+		"LENGTH":     {Name: "$strLenCP"},
+		"ABS":        {Name: "$abs"},
+		"ROUND":      {Name: "$round"},
+		"FLOOR":      {Name: "$floor"},
+		"CEIL":       {Name: "$ceil"},
+		"SQRT":       {Name: "$sqrt"},
+		"COALESCE":   {Name: "$ifNull"},
+		"IFNULL":     {Name: "$ifNull"},
+		"TRIM":       {Name: "$trim", Args: []string{"input"}},
+		"DATE_TRUNC": {Name: "$dateTrunc", Args: []string{"unit", "date"}},
+	},
+}
+
+// RegisterFunction sets how dbType spells an OQL function, e.g.
+// RegisterFunction("MySQL", "MONTH_START", FunctionMapping{Template: "DATE_FORMAT($1, '%Y-%m-01')"})
+func RegisterFunction(dbType, function string, spelling FunctionMapping) {
+	if FunctionMap[dbType] == nil {
+		FunctionMap[dbType] = map[string]FunctionMapping{}
+	}
+	FunctionMap[dbType][strings.ToUpper(function)] = spelling
+}
+
+// GetFunctionMapping returns how dbType spells an OQL function
+func GetFunctionMapping(dbType, function string) (FunctionMapping, bool) {
+	spelling, ok := FunctionMap[dbType][strings.ToUpper(function)]
+	return spelling, ok
+}
+
+// templateArg matches a $n argument placeholder
+var templateArg = regexp.MustCompile(`\$(\d+)`)
+
+// FunctionSQL writes a call of an OQL function in dbType's SQL, with args
+// already built: the template filled in, else Name(args), else as called
+func FunctionSQL(dbType, function string, args []string) string {
+	spelling, ok := GetFunctionMapping(dbType, function)
+	if !ok {
+		return function + "(" + strings.Join(args, ", ") + ")"
+	}
+	if spelling.Template != "" {
+		return templateArg.ReplaceAllStringFunc(spelling.Template, func(placeholder string) string {
+			n, _ := strconv.Atoi(placeholder[1:])
+			if n < 1 || n > len(args) {
+				return "NULL"
+			}
+			return args[n-1]
+		})
+	}
+	return spelling.Name + "(" + strings.Join(args, ", ") + ")"
+}
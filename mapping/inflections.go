@@ -0,0 +1,125 @@
+package mapping
+
+import (
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// IrregularPlurals - singular -> plural for nouns the suffix rules get wrong
+// Matched as word endings (salesperson -> salespeople, the longest ending
+// first) and used both ways: people -> Person when reverse translating a table
+var IrregularPlurals = map[string]string{
+	"person":     "people",
+	"child":      "children",
+	"woman":      "women",
+	"human":      "humans", // not humen
+	"tooth":      "teeth",
+	"foot":       "feet",
+	"goose":      "geese",
+	"mouse":      "mice",
+	"criterion":  "criteria",
+	"phenomenon": "phenomena",
+	"cactus":     "cacti",
+	"leaf":       "leaves",
+	"thief":      "thieves",
+}
+
+// UncountableWords - nouns whose table name is the entity name itself
+// (Data -> data, not datas). Matched as word endings: user_data, metadata.
+var UncountableWords = []string{
+	"data", "media", "news", "feedback", "staff", "software", "hardware",
+	"firmware", "equipment", "information", "series", "species", "sheep",
+	"fish", "money", "audio", "traffic", "analytics",
+}
+
+// TableNameOverrides - entity -> table name for tables the inflector must not
+// name, e.g. {"Person": "persons"}. Matched case-insensitively both ways.
+var TableNameOverrides = map[string]string{}
+
+// RegisterIrregular adds a noun with an irregular plural (e.g. "alumnus", "alumni")
+func RegisterIrregular(singular, plural string) {
+	IrregularPlurals[strings.ToLower(singular)] = strings.ToLower(plural)
+}
+
+// RegisterUncountable adds nouns that are their own plural
+func RegisterUncountable(words ...string) {
+	for _, word := range words {
+		UncountableWords = append(UncountableWords, strings.ToLower(word))
+	}
+}
+
+// RegisterTableName names the table of entity, overriding inflection
+func RegisterTableName(entity, table string) {
+	TableNameOverrides[entity] = table
+}
+
+// Pluralize returns the plural of a noun: category -> categories, status -> statuses
+func Pluralize(word string) string {
+	if isUncountable(word) {
+		return word
+	}
+	if from, to, ok := irregularEnding(word, false); ok {
+		return word[:len(word)-len(from)] + to
+	}
+	return inflection.Plural(word)
+}
+
+// Singularize returns the singular of a noun: categories -> category, people -> person
+func Singularize(word string) string {
+	if isUncountable(word) {
+		return word
+	}
+	if from, to, ok := irregularEnding(word, true); ok {
+		return word[:len(word)-len(from)] + to
+	}
+	return inflection.Singular(word)
+}
+
+// EntityTable is the table name of an entity: its override, else the
+// lower-cased entity pluralized (User -> users, Person -> people)
+func EntityTable(entity string) string {
+	for name, table := range TableNameOverrides {
+		if strings.EqualFold(name, entity) {
+			return table
+		}
+	}
+	return Pluralize(strings.ToLower(entity))
+}
+
+// TableEntity returns the entity whose overridden table name is table
+func TableEntity(table string) (string, bool) {
+	for entity, name := range TableNameOverrides {
+		if strings.EqualFold(name, table) {
+			return entity, true
+		}
+	}
+	return "", false
+}
+
+func isUncountable(word string) bool {
+	lower := strings.ToLower(word)
+	for _, u := range UncountableWords {
+		if strings.HasSuffix(lower, u) {
+			return true
+		}
+	}
+	return false
+}
+
+// irregularEnding finds the longest irregular form word ends with: a singular,
+// or a plural when plural is set. It returns that ending and the other form.
+func irregularEnding(word string, plural bool) (string, string, bool) {
+	lower := strings.ToLower(word)
+	var from, to string
+	for singularForm, pluralForm := range IrregularPlurals {
+		ending, other := singularForm, pluralForm
+		if plural {
+			ending, other = pluralForm, singularForm
+		}
+		if strings.HasSuffix(lower, ending) && len(ending) > len(from) {
+			from, to = ending, other
+		}
+	}
+	return from, to, from != ""
+}
@@ -0,0 +1,139 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Naming conventions for column names
+const (
+	AsWritten  = ""           // names are used as written in OQL
+	SnakeCase  = "snake_case" // first_name
+	CamelCase  = "camelCase"  // firstName
+	PascalCase = "PascalCase" // FirstName
+)
+
+// ColumnNaming - the naming convention of each database's columns
+// Usage: ColumnNaming["PostgreSQL"] = SnakeCase makes OQL firstName hit first_name
+// A database left out uses field names as written
+var ColumnNaming = map[string]string{}
+
+// FieldNaming - the convention OQL field names are written in; result rows
+// of a database with a ColumnNaming convention come back in it
+var FieldNaming = CamelCase
+
+// SetColumnNaming sets the naming convention of dbType's columns
+// (AsWritten turns conversion off again)
+func SetColumnNaming(dbType, convention string) error {
+	if !IsSupportedDatabase(dbType) {
+		return fmt.Errorf("unknown database type: %s", dbType)
+	}
+	if !isNamingConvention(convention) {
+		return fmt.Errorf("unknown naming convention %q (use snake_case, camelCase or PascalCase)", convention)
+	}
+	if convention == AsWritten {
+		delete(ColumnNaming, dbType)
+		return nil
+	}
+	ColumnNaming[dbType] = convention
+	return nil
+}
+
+// ColumnName is the column dbType stores an OQL field in: firstName ->
+// first_name with SnakeCase. A qualified name (users.firstName) converts
+// its last part.
+func ColumnName(dbType, field string) string {
+	return convertQualified(field, ColumnNaming[dbType])
+}
+
+// ResultField is the field name a result column of dbType is returned as:
+// first_name -> firstName with FieldNaming CamelCase. Columns of databases
+// without a ColumnNaming convention are returned as they are.
+func ResultField(dbType, column string) string {
+	if ColumnNaming[dbType] == AsWritten {
+		return column
+	}
+	return convertQualified(column, FieldNaming)
+}
+
+// ConvertName writes name in a naming convention: ConvertName("userID",
+// SnakeCase) is "user_id". Names that are not plain identifiers (*, _id,
+// $meta) are returned as they are.
+func ConvertName(name, convention string) string {
+	if convention == AsWritten || !isPlainIdentifier(name) {
+		return name
+	}
+	words := splitWords(name)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if convention == PascalCase || (convention == CamelCase && i > 0) {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		words[i] = word
+	}
+	if convention == SnakeCase {
+		return strings.Join(words, "_")
+	}
+	return strings.Join(words, "")
+}
+
+func convertQualified(name, convention string) string {
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		return name[:dot+1] + ConvertName(name[dot+1:], convention)
+	}
+	return ConvertName(name, convention)
+}
+
+func isNamingConvention(convention string) bool {
+	switch convention {
+	case AsWritten, SnakeCase, CamelCase, PascalCase:
+		return true
+	}
+	return false
+}
+
+// isPlainIdentifier reports whether name is letters, digits and inner
+// underscores, starting with a letter
+func isPlainIdentifier(name string) bool {
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitWords splits an identifier at underscores and case changes:
+// first_name, firstName and FirstName are [first name]; an acronym stays
+// one word (userID, HTTPServer)
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := runes[i-1]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
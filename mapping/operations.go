@@ -2,16 +2,17 @@ package mapping
 
 import "strings"
 
-// OperationGroups maps each operation to its group (CRUD, DDL, DQL, TCL, DCL)
+// OperationGroups maps each operation to its group (CRUD, DDL, DQL, TCL, DCL, PUBSUB)
 // Used by parser to route operations dynamically - no hardcoded lists!
 var OperationGroups = map[string]string{
-	// ========== GROUP 1: CRUD (7 operations) ==========
+	// ========== GROUP 1: CRUD (8 operations) ==========
 	"GET":         "CRUD",
 	"CREATE":      "CRUD",
 	"UPDATE":      "CRUD",
 	"DELETE":      "CRUD",
 	"UPSERT":      "CRUD", // Insert or Update
 	"BULK INSERT": "CRUD", // Insert multiple rows
+	"BULK UPSERT": "CRUD", // Insert or update multiple rows
 	"REPLACE":     "CRUD", // Delete + Insert (MySQL)
 	
 	// ========== GROUP 2: DDL (14 operations) ==========
@@ -56,6 +57,12 @@ var OperationGroups = map[string]string{
 	"CREATE RULE":       "DDL",
 	"DROP RULE":         "DDL",
 	"COMMENT ON":        "DDL",
+	"CREATE PARTITION":  "DDL",
+
+	// Schema introspection
+	"SHOW TABLES":  "DDL",
+	"SHOW COLUMNS": "DDL",
+	"SHOW INDEXES": "DDL",
 	
 	// ========== GROUP 3: DQL (31 operations) ==========
 	// JOIN operations
@@ -71,6 +78,7 @@ var OperationGroups = map[string]string{
 	"AVG":   "DQL",
 	"MIN":   "DQL",
 	"MAX":   "DQL",
+	"STRING AGG": "DQL",
 	
 	// Query modifiers
 	// "GROUP BY": "DQL",
@@ -102,7 +110,7 @@ var OperationGroups = map[string]string{
 	// "LIKE":     "DQL", // Pattern matching
 	"CASE":     "DQL", // Conditional logic
 	
-	// ========== GROUP 4: TCL (8 operations) ==========
+	// ========== GROUP 4: TCL (10 operations) ==========
 	"BEGIN":              "TCL",
 	"COMMIT":             "TCL",
 	"ROLLBACK":           "TCL",
@@ -111,17 +119,39 @@ var OperationGroups = map[string]string{
 	"START":              "TCL", // Alias for BEGIN
 	"RELEASE SAVEPOINT":  "TCL",
 	"SET TRANSACTION":    "TCL", // Isolation levels
+	"LOCK TABLES":        "TCL", // Explicit table locks (MySQL)
+	"UNLOCK TABLES":      "TCL",
 	
-	// ========== GROUP 5: DCL (9 operations) ==========
+	// ========== GROUP 5: DCL (10 operations) ==========
 	"GRANT":       "DCL",
 	"REVOKE":      "DCL",
 	"CREATE ROLE": "DCL",
+	"ALTER ROLE":  "DCL",
 	"DROP ROLE":   "DCL",
 	"ASSIGN ROLE": "DCL",
 	"REVOKE ROLE": "DCL",
 	"CREATE USER": "DCL",
 	"DROP USER":   "DCL",
 	"ALTER USER":  "DCL",
+
+	// ========== GROUP 6: PUBSUB (3 operations) ==========
+	"LISTEN":   "PUBSUB",
+	"UNLISTEN": "PUBSUB",
+	"NOTIFY":   "PUBSUB",
+}
+
+// ContextualOperations - operations that are also common column names (a
+// notify flag, a listen count), so they are operations only at the start of
+// a statement and names everywhere else
+var ContextualOperations = map[string]bool{
+	"LISTEN":   true,
+	"UNLISTEN": true,
+	"NOTIFY":   true,
+}
+
+// IsContextualOperation checks if an operation keyword is also a valid name
+func IsContextualOperation(op string) bool {
+	return ContextualOperations[strings.ToUpper(op)]
 }
 
 // OperationSubTypes provides finer classification within groups
@@ -133,6 +163,7 @@ var OperationSubTypes = map[string]string{
 	"DELETE":      "WRITE",
 	"UPSERT":      "WRITE",
 	"BULK INSERT": "WRITE",
+	"BULK UPSERT": "WRITE",
 	"REPLACE":     "WRITE",
 	
 	// DDL Sub-types
@@ -151,6 +182,9 @@ var OperationSubTypes = map[string]string{
 	"DROP VIEW":         "VIEW DROP",
 	"ALTER VIEW":        "VIEW MODIFY",
 	"RENAME TABLE":      "SCHEMA RENAME",
+	"SHOW TABLES":       "SCHEMA READ",
+	"SHOW COLUMNS":      "SCHEMA READ",
+	"SHOW INDEXES":      "INDEX READ",
 	
 	// DQL Sub-types
 	"INNER JOIN": "JOIN",
@@ -164,6 +198,7 @@ var OperationSubTypes = map[string]string{
 	"AVG":   "AGGREGATE",
 	"MIN":   "AGGREGATE",
 	"MAX":   "AGGREGATE",
+	"STRING AGG": "AGGREGATE",
 	
 	"UNION":     "SET",
 	"UNION ALL": "SET",
@@ -193,22 +228,559 @@ var OperationSubTypes = map[string]string{
 	"ROLLBACK TO":       "TRANSACTION PARTIAL",
 	"RELEASE SAVEPOINT": "TRANSACTION RELEASE",
 	"SET TRANSACTION":   "TRANSACTION CONFIG",
+	"LOCK TABLES":       "TABLE LOCK",
+	"UNLOCK TABLES":     "TABLE UNLOCK",
 	
 	// DCL Sub-types
 	"GRANT":       "PERMISSION GRANT",
 	"REVOKE":      "PERMISSION REVOKE",
 	"CREATE ROLE": "ROLE CREATE",
+	"ALTER ROLE":  "ROLE MODIFY",
 	"DROP ROLE":   "ROLE DROP",
 	"ASSIGN ROLE": "ROLE ASSIGN",
 	"REVOKE ROLE": "ROLE REVOKE",
 	"CREATE USER": "USER CREATE",
 	"DROP USER":   "USER DROP",
 	"ALTER USER":  "USER MODIFY",
+
+	// PUBSUB Sub-types
+	"LISTEN":   "CHANNEL SUBSCRIBE",
+	"UNLISTEN": "CHANNEL UNSUBSCRIBE",
+	"NOTIFY":   "CHANNEL PUBLISH",
 }
 
-// OperationMap - Runtime mapping for translators
-var OperationMap = map[string]map[string]string{
-	"PostgreSQL": {
+// OperationMap - Runtime mapping for translators
+var OperationMap = map[string]map[string]string{
+	"PostgreSQL": {
+		// ========== GROUP 1: CRUD Operations ==========
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",
+		"DELETE":      "delete",
+		"UPSERT":      "upsert",
+		"BULK INSERT": "bulk_insert",
+		"BULK UPSERT": "bulk_upsert",
+		"REPLACE":     "insert", // PostgreSQL uses INSERT ... ON CONFLICT
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",
+		"ALTER TABLE":    "alter_table",
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "create_index",
+		"DROP INDEX":     "drop_index",
+		"CREATE DATABASE": "create_database",
+		"DROP DATABASE":   "drop_database",
+		"CREATE VIEW":     "create_view",
+		"DROP VIEW":       "drop_view",
+		"ALTER VIEW": "alter_view",
+		"RENAME TABLE":    "alter_table_rename",
+		"SHOW TABLES":     "show_tables",  // information_schema.tables
+		"SHOW COLUMNS":    "show_columns", // information_schema.columns
+		"SHOW INDEXES":    "show_indexes", // pg_indexes
+
+		// PostgreSQL-specific DDL
+		"CREATE SEQUENCE":  "create_sequence",
+		"ALTER SEQUENCE":   "alter_sequence",
+		"DROP SEQUENCE":    "drop_sequence",
+		"CREATE EXTENSION": "create_extension",
+		"DROP EXTENSION":   "drop_extension",
+		"CREATE SCHEMA":    "create_schema",
+		"DROP SCHEMA":      "drop_schema",
+		"CREATE TYPE":      "create_type",
+		"ALTER TYPE":       "alter_type",
+		"DROP TYPE":        "drop_type",
+		"CREATE DOMAIN":    "create_domain",
+		"DROP DOMAIN":      "drop_domain",
+		"CREATE FUNCTION":  "create_function",
+		"ALTER FUNCTION":   "alter_function",
+		"DROP FUNCTION":    "drop_function",
+		"CREATE TRIGGER":   "create_trigger",
+		"DROP TRIGGER":     "drop_trigger",
+		"CREATE POLICY":    "create_policy",
+		"DROP POLICY":      "drop_policy",
+		"CREATE RULE":      "create_rule",
+		"DROP RULE":        "drop_rule",
+		"COMMENT ON":       "comment_on",
+		"CREATE PARTITION": "create_partition",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		// JOIN operations
+		"INNER JOIN": "inner_join",
+		"LEFT JOIN":  "left_join",
+		"RIGHT JOIN": "right_join",
+		"FULL JOIN":  "full_join",
+		"CROSS JOIN": "cross_join",
+		
+		// Aggregate functions
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "string_agg",
+		
+		// Query modifiers
+		"GROUP BY": "group_by",
+		"ORDER BY": "order_by",
+		"HAVING":   "having",
+		"DISTINCT": "distinct",
+		"LIMIT":    "limit",
+		"OFFSET":   "offset",
+		
+		// Set operations
+		"UNION":     "union",
+		"UNION ALL": "union_all",
+		"INTERSECT": "intersect",
+		"EXCEPT":    "except",
+		
+		// Window functions
+		"ROW NUMBER":   "row_number",
+		"RANK":         "rank",
+		"DENSE RANK":   "dense_rank",
+		"LAG":          "lag",
+		"LEAD":         "lead",
+		"NTILE":        "ntile",
+		"PARTITION BY": "partition_by",
+		
+		// Advanced query features
+		"CTE":      "with",
+		"SUBQUERY": "subquery",
+		"EXISTS":   "exists",
+		"LIKE":     "like",
+		"CASE":     "case",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		"BEGIN":             "begin",
+		"START":             "begin",
+		"COMMIT":            "commit",
+		"ROLLBACK":          "rollback",
+		"SAVEPOINT":         "savepoint",
+		"ROLLBACK TO":       "rollback_to",
+		"RELEASE SAVEPOINT": "release_savepoint",
+		"SET TRANSACTION":   "set_transaction",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		"GRANT":       "grant",
+		"REVOKE":      "revoke",
+		"CREATE ROLE": "create_role",
+		"ALTER ROLE":  "alter_role",
+		"DROP ROLE":   "drop_role",
+		"ASSIGN ROLE": "assign_role",  
+		"REVOKE ROLE": "revoke_role",
+		"CREATE USER": "create_user",
+		"DROP USER":   "drop_user",
+		"ALTER USER":  "alter_user",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "listen",
+		"UNLISTEN": "unlisten",
+		"NOTIFY":   "notify",
+	},
+	"MySQL": {
+		// ========== GROUP 1: CRUD Operations ==========
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",
+		"DELETE":      "delete",
+		"UPSERT":      "upsert",
+		"BULK INSERT": "bulk_insert",
+		"BULK UPSERT": "bulk_upsert",
+		"REPLACE":     "replace", // MySQL has native REPLACE
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",
+		"ALTER TABLE":    "alter_table",
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "create_index",
+		"DROP INDEX":     "drop_index",
+		"CREATE DATABASE": "create_database",
+		"DROP DATABASE":   "drop_database",
+		"CREATE VIEW":     "create_view",
+		"DROP VIEW":       "drop_view",
+		"ALTER VIEW":      "alter_view",
+		"RENAME TABLE":    "rename_table",
+		"SHOW TABLES":     "show_tables",
+		"SHOW COLUMNS":    "show_columns",
+		"SHOW INDEXES":    "show_index",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		"INNER JOIN": "inner_join",
+		"LEFT JOIN":  "left_join",
+		"RIGHT JOIN": "right_join",
+		"FULL JOIN":  "full_join",
+		"CROSS JOIN": "cross_join",
+		
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "group_concat",
+		
+		"GROUP BY": "group_by",
+		"ORDER BY": "order_by",
+		"HAVING":   "having",
+		"DISTINCT": "distinct",
+		"LIMIT":    "limit",
+		"OFFSET":   "offset",
+		
+		"UNION":     "union",
+		"UNION ALL": "union_all",
+		"INTERSECT": "intersect",
+		"EXCEPT":    "except",
+		
+		// Window functions (MySQL 8.0+)
+		"ROW NUMBER":   "row_number",
+		"RANK":         "rank",
+		"DENSE RANK":   "dense_rank",
+		"LAG":          "lag",
+		"LEAD":         "lead",
+		"NTILE":        "ntile",
+		"PARTITION BY": "partition_by",
+		
+		// Advanced query features
+		"CTE":      "with",
+		"SUBQUERY": "subquery",
+		"EXISTS":   "exists",
+		"LIKE":     "like",
+		"CASE":     "case",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		"BEGIN":             "start_transaction",
+		"START":             "start_transaction",
+		"COMMIT":            "commit",
+		"ROLLBACK":          "rollback",
+		"SAVEPOINT":         "savepoint",
+		"ROLLBACK TO":       "rollback_to",
+		"RELEASE SAVEPOINT": "release_savepoint",
+		"SET TRANSACTION":   "set_transaction",
+		"LOCK TABLES":       "lock_tables",
+		"UNLOCK TABLES":     "unlock_tables",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		"GRANT":       "grant",
+		"REVOKE":      "revoke",
+		"CREATE ROLE": "create_role",
+		"ALTER ROLE":  "alter_role",
+		"DROP ROLE":   "drop_role",
+		"ASSIGN ROLE": "assign_role",  
+		"REVOKE ROLE": "revoke_role",
+		"CREATE USER": "create_user",
+		"DROP USER":   "drop_user",
+		"ALTER USER":  "alter_user",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},
+	"SQLite": {
+		// ========== GROUP 1: CRUD Operations ==========
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",
+		"DELETE":      "delete",
+		"UPSERT":      "upsert",
+		"BULK INSERT": "bulk_insert",
+		"BULK UPSERT": "bulk_upsert",
+		"REPLACE":     "replace", // SQLite has INSERT OR REPLACE
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",
+		"ALTER TABLE":    "alter_table",
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "delete", // SQLite doesn't have TRUNCATE
+		"TRUNCATE":       "delete",
+		"CREATE INDEX":   "create_index",
+		"DROP INDEX":     "drop_index",
+		"CREATE DATABASE": "attach",    // SQLite uses ATTACH DATABASE
+		"DROP DATABASE":   "detach",    // SQLite uses DETACH DATABASE
+		"CREATE VIEW":     "create_view",
+		"DROP VIEW":       "drop_view",
+		"ALTER VIEW":      "drop_create_view", // SQLite: drop then create
+		"RENAME TABLE":    "alter_table_rename",
+		"SHOW TABLES":     "table_list",  // PRAGMA table_list
+		"SHOW COLUMNS":    "table_info",  // PRAGMA table_info(t)
+		"SHOW INDEXES":    "index_list",  // PRAGMA index_list(t)
+		
+		// ========== GROUP 3: DQL Operations ==========
+		"INNER JOIN": "inner_join",
+		"LEFT JOIN":  "left_join",
+		"RIGHT JOIN": "right_join",
+		"FULL JOIN":  "full_join",
+		"CROSS JOIN": "cross_join",
+		
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "group_concat",
+		
+		"GROUP BY": "group_by",
+		"ORDER BY": "order_by",
+		"HAVING":   "having",
+		"DISTINCT": "distinct",
+		"LIMIT":    "limit",
+		"OFFSET":   "offset",
+		
+		"UNION":     "union",
+		"UNION ALL": "union_all",
+		"INTERSECT": "intersect",
+		"EXCEPT":    "except",
+		
+		// Window functions (SQLite 3.25+)
+		"ROW NUMBER":   "row_number",
+		"RANK":         "rank",
+		"DENSE RANK":   "dense_rank",
+		"LAG":          "lag",
+		"LEAD":         "lead",
+		"NTILE":        "ntile",
+		"PARTITION BY": "partition_by",
+		
+		// Advanced query features
+		"CTE":      "with",
+		"SUBQUERY": "subquery",
+		"EXISTS":   "exists",
+		"LIKE":     "like",
+		"CASE":     "case",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		"BEGIN":             "begin",
+		"START":             "begin",
+		"COMMIT":            "commit",
+		"ROLLBACK":          "rollback",
+		"SAVEPOINT":         "savepoint",
+		"ROLLBACK TO":       "rollback_to",
+		"RELEASE SAVEPOINT": "release",
+		"SET TRANSACTION":   "set_transaction", // PRAGMA read_uncommitted / query_only
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},
+	"Oracle": {
+		// ========== GROUP 1: CRUD Operations ==========
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",
+		"DELETE":      "delete",
+		"UPSERT":      "merge",       // MERGE INTO ... USING (SELECT ... FROM DUAL)
+		"BULK INSERT": "insert_all",  // INSERT ALL INTO ... SELECT 1 FROM DUAL
+		"BULK UPSERT": "bulk_merge",
+		"REPLACE":     "unsupported",
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",
+		"ALTER TABLE":    "alter_table",
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "create_index",
+		"DROP INDEX":     "drop_index",
+		"CREATE DATABASE": "unsupported",  // A database is created by the DBA, not by SQL
+		"DROP DATABASE":   "unsupported",
+		"CREATE VIEW":     "create_view",
+		"DROP VIEW":       "drop_view",
+		"ALTER VIEW":      "create_or_replace_view",
+		"RENAME TABLE":    "rename_table",
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		"INNER JOIN": "inner_join",
+		"LEFT JOIN":  "left_join",
+		"RIGHT JOIN": "right_join",
+		"FULL JOIN":  "full_join",
+		"CROSS JOIN": "cross_join",
+		
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "listagg",
+		
+		"GROUP BY": "group_by",
+		"ORDER BY": "order_by",
+		"HAVING":   "having",
+		"DISTINCT": "distinct",
+		"LIMIT":    "fetch",   // FETCH NEXT n ROWS ONLY (12c+), ROWNUM before
+		"OFFSET":   "offset",
+		
+		"UNION":     "union",
+		"UNION ALL": "union_all",
+		"INTERSECT": "intersect",
+		"EXCEPT":    "minus",
+		
+		// Window functions
+		"ROW NUMBER":   "row_number",
+		"RANK":         "rank",
+		"DENSE RANK":   "dense_rank",
+		"LAG":          "lag",
+		"LEAD":         "lead",
+		"NTILE":        "ntile",
+		"PARTITION BY": "partition_by",
+		
+		// Advanced query features
+		"CTE":      "with",
+		"SUBQUERY": "subquery",
+		"EXISTS":   "exists",
+		"LIKE":     "like",
+		"CASE":     "case",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		"BEGIN":             "begin",  // SET TRANSACTION READ WRITE
+		"START":             "begin",
+		"COMMIT":            "commit",
+		"ROLLBACK":          "rollback",
+		"SAVEPOINT":         "savepoint",
+		"ROLLBACK TO":       "rollback_to",
+		"RELEASE SAVEPOINT": "unsupported",  // Savepoints end with the transaction
+		"SET TRANSACTION":   "set_transaction",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},
+	"SQLServer": {
+		// ========== GROUP 1: CRUD Operations ==========
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",
+		"DELETE":      "delete",
+		"UPSERT":      "merge",        // MERGE INTO ... WITH (HOLDLOCK) USING (VALUES ...)
+		"BULK INSERT": "bulk_insert",  // Multi-row VALUES, up to 1000 rows
+		"BULK UPSERT": "bulk_merge",
+		"REPLACE":     "unsupported",
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",
+		"ALTER TABLE":    "alter_table",  // Renames go through sp_rename
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "create_index",
+		"DROP INDEX":     "drop_index",
+		"CREATE DATABASE": "create_database",
+		"DROP DATABASE":   "drop_database",
+		"CREATE VIEW":     "create_view",
+		"DROP VIEW":       "drop_view",
+		"ALTER VIEW":      "alter_view",
+		"RENAME TABLE":    "sp_rename",  // EXEC sp_rename N'[old]', N'new'
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		"INNER JOIN": "inner_join",
+		"LEFT JOIN":  "left_join",
+		"RIGHT JOIN": "right_join",
+		"FULL JOIN":  "full_join",
+		"CROSS JOIN": "cross_join",
+		
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "string_agg",  // SQL Server 2017+
+		
+		"GROUP BY": "group_by",
+		"ORDER BY": "order_by",
+		"HAVING":   "having",
+		"DISTINCT": "distinct",
+		"LIMIT":    "top",     // TOP (n), or FETCH NEXT n ROWS ONLY after an OFFSET
+		"OFFSET":   "offset",  // OFFSET n ROWS (needs ORDER BY)
+		
+		"UNION":     "union",
+		"UNION ALL": "union_all",
+		"INTERSECT": "intersect",
+		"EXCEPT":    "except",
+		
+		// Window functions
+		"ROW NUMBER":   "row_number",
+		"RANK":         "rank",
+		"DENSE RANK":   "dense_rank",
+		"LAG":          "lag",
+		"LEAD":         "lead",
+		"NTILE":        "ntile",
+		"PARTITION BY": "partition_by",
+		
+		// Advanced query features
+		"CTE":      "with",
+		"SUBQUERY": "subquery",
+		"EXISTS":   "exists",
+		"LIKE":     "like",
+		"CASE":     "case",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		"BEGIN":             "begin_transaction",
+		"START":             "begin_transaction",
+		"COMMIT":            "commit",
+		"ROLLBACK":          "rollback",
+		"SAVEPOINT":         "save_transaction",      // SAVE TRANSACTION name
+		"ROLLBACK TO":       "rollback_transaction",  // ROLLBACK TRANSACTION name
+		"RELEASE SAVEPOINT": "unsupported",           // Savepoints end with the transaction
+		"SET TRANSACTION":   "set_transaction",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},
+	"CockroachDB": {
 		// ========== GROUP 1: CRUD Operations ==========
 		"GET":         "select",
 		"CREATE":      "insert",
@@ -216,13 +788,15 @@ var OperationMap = map[string]map[string]string{
 		"DELETE":      "delete",
 		"UPSERT":      "upsert",
 		"BULK INSERT": "bulk_insert",
-		"REPLACE":     "insert", // PostgreSQL uses INSERT ... ON CONFLICT
+		"BULK UPSERT": "bulk_upsert",
+		"REPLACE":     "upsert", // UPSERT INTO: insert or replace by primary key
 		
 		// ========== GROUP 2: DDL Operations ==========
 		"CREATE TABLE":   "create_table",
 		"ALTER TABLE":    "alter_table",
 		"DROP TABLE":     "drop_table",
 		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
 		"CREATE INDEX":   "create_index",
 		"DROP INDEX":     "drop_index",
 		"CREATE DATABASE": "create_database",
@@ -231,30 +805,34 @@ var OperationMap = map[string]map[string]string{
 		"DROP VIEW":       "drop_view",
 		"ALTER VIEW": "alter_view",
 		"RENAME TABLE":    "alter_table_rename",
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
 
-		// PostgreSQL-specific DDL
+		// PostgreSQL DDL that CockroachDB shares
 		"CREATE SEQUENCE":  "create_sequence",
 		"ALTER SEQUENCE":   "alter_sequence",
 		"DROP SEQUENCE":    "drop_sequence",
-		"CREATE EXTENSION": "create_extension",
-		"DROP EXTENSION":   "drop_extension",
+		"CREATE EXTENSION": "unsupported",  // Extensions such as PostGIS are built in
+		"DROP EXTENSION":   "unsupported",
 		"CREATE SCHEMA":    "create_schema",
 		"DROP SCHEMA":      "drop_schema",
 		"CREATE TYPE":      "create_type",
 		"ALTER TYPE":       "alter_type",
 		"DROP TYPE":        "drop_type",
-		"CREATE DOMAIN":    "create_domain",
-		"DROP DOMAIN":      "drop_domain",
+		"CREATE DOMAIN":    "unsupported",
+		"DROP DOMAIN":      "unsupported",
 		"CREATE FUNCTION":  "create_function",
 		"ALTER FUNCTION":   "alter_function",
 		"DROP FUNCTION":    "drop_function",
-		"CREATE TRIGGER":   "create_trigger",
-		"DROP TRIGGER":     "drop_trigger",
-		"CREATE POLICY":    "create_policy",
-		"DROP POLICY":      "drop_policy",
-		"CREATE RULE":      "create_rule",
-		"DROP RULE":        "drop_rule",
+		"CREATE TRIGGER":   "unsupported",
+		"DROP TRIGGER":     "unsupported",
+		"CREATE POLICY":    "unsupported",
+		"DROP POLICY":      "unsupported",
+		"CREATE RULE":      "unsupported",
+		"DROP RULE":        "unsupported",
 		"COMMENT ON":       "comment_on",
+		"CREATE PARTITION": "unsupported",  // No PARTITION OF tables
 		
 		// ========== GROUP 3: DQL Operations ==========
 		// JOIN operations
@@ -270,6 +848,7 @@ var OperationMap = map[string]map[string]string{
 		"AVG":   "avg",
 		"MIN":   "min",
 		"MAX":   "max",
+		"STRING AGG": "string_agg",
 		
 		// Query modifiers
 		"GROUP BY": "group_by",
@@ -310,41 +889,54 @@ var OperationMap = map[string]map[string]string{
 		"ROLLBACK TO":       "rollback_to",
 		"RELEASE SAVEPOINT": "release_savepoint",
 		"SET TRANSACTION":   "set_transaction",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
 		
 		// ========== GROUP 5: DCL Operations ==========
 		"GRANT":       "grant",
 		"REVOKE":      "revoke",
 		"CREATE ROLE": "create_role",
+		"ALTER ROLE":  "alter_role",
 		"DROP ROLE":   "drop_role",
-		"ASSIGN ROLE": "assign_role",  
+		"ASSIGN ROLE": "assign_role",
 		"REVOKE ROLE": "revoke_role",
 		"CREATE USER": "create_user",
 		"DROP USER":   "drop_user",
 		"ALTER USER":  "alter_user",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",  // Use changefeeds
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
 	},
-	"MySQL": {
+	"ClickHouse": {
 		// ========== GROUP 1: CRUD Operations ==========
 		"GET":         "select",
 		"CREATE":      "insert",
-		"UPDATE":      "update",
-		"DELETE":      "delete",
-		"UPSERT":      "upsert",
+		"UPDATE":      "alter_update",  // ALTER TABLE ... UPDATE mutation
+		"DELETE":      "alter_delete",  // ALTER TABLE ... DELETE mutation
+		"UPSERT":      "unsupported",   // Use a ReplacingMergeTree table
 		"BULK INSERT": "bulk_insert",
-		"REPLACE":     "replace", // MySQL has native REPLACE
+		"BULK UPSERT": "unsupported",
+		"REPLACE":     "unsupported",
 		
 		// ========== GROUP 2: DDL Operations ==========
-		"CREATE TABLE":   "create_table",
+		"CREATE TABLE":   "create_table",  // ENGINE = MergeTree ORDER BY key
 		"ALTER TABLE":    "alter_table",
 		"DROP TABLE":     "drop_table",
 		"TRUNCATE TABLE": "truncate_table",
-		"CREATE INDEX":   "create_index",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "add_index",  // Data skipping index: ALTER TABLE ... ADD INDEX
 		"DROP INDEX":     "drop_index",
 		"CREATE DATABASE": "create_database",
 		"DROP DATABASE":   "drop_database",
 		"CREATE VIEW":     "create_view",
 		"DROP VIEW":       "drop_view",
-		"ALTER VIEW":      "alter_view",
+		"ALTER VIEW":      "create_or_replace_view",
 		"RENAME TABLE":    "rename_table",
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
 		
 		// ========== GROUP 3: DQL Operations ==========
 		"INNER JOIN": "inner_join",
@@ -358,6 +950,7 @@ var OperationMap = map[string]map[string]string{
 		"AVG":   "avg",
 		"MIN":   "min",
 		"MAX":   "max",
+		"STRING AGG": "group_array",  // arrayStringConcat(groupArray(field), 'sep')
 		
 		"GROUP BY": "group_by",
 		"ORDER BY": "order_by",
@@ -366,12 +959,114 @@ var OperationMap = map[string]map[string]string{
 		"LIMIT":    "limit",
 		"OFFSET":   "offset",
 		
-		"UNION":     "union",
+		"UNION":     "union",  // UNION DISTINCT
 		"UNION ALL": "union_all",
 		"INTERSECT": "intersect",
 		"EXCEPT":    "except",
 		
-		// Window functions (MySQL 8.0+)
+		// Window functions
+		"ROW NUMBER":   "row_number",
+		"RANK":         "rank",
+		"DENSE RANK":   "dense_rank",
+		"LAG":          "lag",   // lagInFrame over the whole partition
+		"LEAD":         "lead",  // leadInFrame
+		"NTILE":        "ntile",
+		"PARTITION BY": "partition_by",
+		
+		// Advanced query features
+		"CTE":      "with",
+		"SUBQUERY": "subquery",
+		"EXISTS":   "exists",
+		"LIKE":     "like",
+		"CASE":     "case",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		// Inserts are atomic per block; there are no multi-statement transactions
+		"BEGIN":             "unsupported",
+		"START":             "unsupported",
+		"COMMIT":            "unsupported",
+		"ROLLBACK":          "unsupported",
+		"SAVEPOINT":         "unsupported",
+		"ROLLBACK TO":       "unsupported",
+		"RELEASE SAVEPOINT": "unsupported",
+		"SET TRANSACTION":   "unsupported",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},	"BigQuery": {
+		// ========== GROUP 1: CRUD Operations ==========
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",  // WHERE true when unconditional
+		"DELETE":      "delete",
+		"UPSERT":      "unsupported",
+		"BULK INSERT": "bulk_insert",
+		"BULK UPSERT": "unsupported",
+		"REPLACE":     "unsupported",
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",  // PARTITION BY / CLUSTER BY / OPTIONS
+		"ALTER TABLE":    "alter_table",
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "unsupported",  // No secondary indexes: cluster the table instead
+		"DROP INDEX":     "unsupported",
+		"CREATE DATABASE": "create_schema",  // A dataset
+		"DROP DATABASE":   "drop_schema",
+		"CREATE SCHEMA":   "create_schema",
+		"DROP SCHEMA":     "drop_schema",
+		"CREATE VIEW":     "create_view",
+		"DROP VIEW":       "drop_view",
+		"ALTER VIEW":      "create_or_replace_view",
+		"RENAME TABLE":    "rename_table",  // ALTER TABLE ... RENAME TO
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		"INNER JOIN": "inner_join",
+		"LEFT JOIN":  "left_join",
+		"RIGHT JOIN": "right_join",
+		"FULL JOIN":  "full_join",
+		"CROSS JOIN": "cross_join",
+		
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "string_agg",
+		
+		"GROUP BY": "group_by",
+		"ORDER BY": "order_by",
+		"HAVING":   "having",
+		"DISTINCT": "distinct",
+		"LIMIT":    "limit",
+		"OFFSET":   "offset",
+		
+		"UNION":     "union",  // UNION DISTINCT
+		"UNION ALL": "union_all",
+		"INTERSECT": "intersect",  // INTERSECT DISTINCT
+		"EXCEPT":    "except",  // EXCEPT DISTINCT
+		
+		// Window functions
 		"ROW NUMBER":   "row_number",
 		"RANK":         "rank",
 		"DENSE RANK":   "dense_rank",
@@ -388,49 +1083,67 @@ var OperationMap = map[string]map[string]string{
 		"CASE":     "case",
 		
 		// ========== GROUP 4: TCL Operations ==========
-		"BEGIN":             "start_transaction",
-		"START":             "start_transaction",
-		"COMMIT":            "commit",
-		"ROLLBACK":          "rollback",
-		"SAVEPOINT":         "savepoint",
-		"ROLLBACK TO":       "rollback_to",
-		"RELEASE SAVEPOINT": "release_savepoint",
-		"SET TRANSACTION":   "set_transaction",
+		// Each statement is atomic; transactions exist only inside multi-statement scripts
+		"BEGIN":             "unsupported",
+		"START":             "unsupported",
+		"COMMIT":            "unsupported",
+		"ROLLBACK":          "unsupported",
+		"SAVEPOINT":         "unsupported",
+		"ROLLBACK TO":       "unsupported",
+		"RELEASE SAVEPOINT": "unsupported",
+		"SET TRANSACTION":   "unsupported",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
 		
 		// ========== GROUP 5: DCL Operations ==========
-		"GRANT":       "grant",
-		"REVOKE":      "revoke",
-		"CREATE ROLE": "create_role",
-		"DROP ROLE":   "drop_role",
-		"ASSIGN ROLE": "assign_role",  
-		"REVOKE ROLE": "revoke_role",
-		"CREATE USER": "create_user",
-		"DROP USER":   "drop_user",
-		"ALTER USER":  "alter_user",
+		// Access is granted with Cloud IAM
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
 	},
-	"SQLite": {
+
+	"Snowflake": {
 		// ========== GROUP 1: CRUD Operations ==========
 		"GET":         "select",
 		"CREATE":      "insert",
 		"UPDATE":      "update",
 		"DELETE":      "delete",
-		"UPSERT":      "upsert",
+		"UPSERT":      "merge",  // MERGE INTO ... USING (SELECT ...)
 		"BULK INSERT": "bulk_insert",
-		"REPLACE":     "replace", // SQLite has INSERT OR REPLACE
+		"BULK UPSERT": "bulk_merge",
+		"REPLACE":     "unsupported",
 		
 		// ========== GROUP 2: DDL Operations ==========
-		"CREATE TABLE":   "create_table",
+		"CREATE TABLE":   "create_table",  // CLUSTER BY / DATA_RETENTION_TIME_IN_DAYS
 		"ALTER TABLE":    "alter_table",
 		"DROP TABLE":     "drop_table",
-		"TRUNCATE TABLE": "delete", // SQLite doesn't have TRUNCATE
-		"CREATE INDEX":   "create_index",
-		"DROP INDEX":     "drop_index",
-		"CREATE DATABASE": "attach",    // SQLite uses ATTACH DATABASE
-		"DROP DATABASE":   "detach",    // SQLite uses DETACH DATABASE
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "unsupported",  // No secondary indexes: cluster the table instead
+		"DROP INDEX":     "unsupported",
+		"CREATE DATABASE": "create_database",
+		"DROP DATABASE":   "drop_database",
+		"CREATE SCHEMA":   "create_schema",
+		"DROP SCHEMA":     "drop_schema",
 		"CREATE VIEW":     "create_view",
 		"DROP VIEW":       "drop_view",
-		"ALTER VIEW":      "drop_create_view", // SQLite: drop then create
-		"RENAME TABLE":    "alter_table_rename",
+		"ALTER VIEW":      "create_or_replace_view",
+		"RENAME TABLE":    "rename_table",  // ALTER TABLE ... RENAME TO
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
 		
 		// ========== GROUP 3: DQL Operations ==========
 		"INNER JOIN": "inner_join",
@@ -444,6 +1157,7 @@ var OperationMap = map[string]map[string]string{
 		"AVG":   "avg",
 		"MIN":   "min",
 		"MAX":   "max",
+		"STRING AGG": "listagg",
 		
 		"GROUP BY": "group_by",
 		"ORDER BY": "order_by",
@@ -455,9 +1169,9 @@ var OperationMap = map[string]map[string]string{
 		"UNION":     "union",
 		"UNION ALL": "union_all",
 		"INTERSECT": "intersect",
-		"EXCEPT":    "except",
+		"EXCEPT":    "except",  // Also spelled MINUS
 		
-		// Window functions (SQLite 3.25+)
+		// Window functions
 		"ROW NUMBER":   "row_number",
 		"RANK":         "rank",
 		"DENSE RANK":   "dense_rank",
@@ -474,26 +1188,145 @@ var OperationMap = map[string]map[string]string{
 		"CASE":     "case",
 		
 		// ========== GROUP 4: TCL Operations ==========
+		// No savepoints; a transaction is BEGIN ... COMMIT | ROLLBACK
 		"BEGIN":             "begin",
 		"START":             "begin",
 		"COMMIT":            "commit",
 		"ROLLBACK":          "rollback",
-		"SAVEPOINT":         "savepoint",
-		"ROLLBACK TO":       "rollback_to",
-		"RELEASE SAVEPOINT": "release",
-		"SET TRANSACTION":   "unsupported", // SQLite has limited transaction config
+		"SAVEPOINT":         "unsupported",
+		"ROLLBACK TO":       "unsupported",
+		"RELEASE SAVEPOINT": "unsupported",
+		"SET TRANSACTION":   "unsupported",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		// Privileges are granted to roles with Snowflake's own GRANT syntax
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},
+
+	"Cassandra": {
+		// ========== GROUP 1: CRUD Operations ==========
+		// Every INSERT overwrites a row with the same primary key
+		"GET":         "select",
+		"CREATE":      "insert",
+		"UPDATE":      "update",
+		"DELETE":      "delete",
+		"UPSERT":      "insert",
+		"BULK INSERT": "batch",  // BEGIN BATCH ... APPLY BATCH
+		"BULK UPSERT": "batch",
+		"REPLACE":     "unsupported",
+		
+		// ========== GROUP 2: DDL Operations ==========
+		"CREATE TABLE":   "create_table",  // PRIMARY KEY ((partition key), clustering columns)
+		"ALTER TABLE":    "alter_table",
+		"DROP TABLE":     "drop_table",
+		"TRUNCATE TABLE": "truncate_table",
+		"TRUNCATE":       "truncate_table",
+		"CREATE INDEX":   "create_index",  // Secondary index
+		"DROP INDEX":     "drop_index",
+		"CREATE DATABASE": "unsupported",  // Keyspaces need a replication strategy
+		"DROP DATABASE":   "unsupported",
+		"CREATE SCHEMA":   "unsupported",
+		"DROP SCHEMA":     "unsupported",
+		"CREATE VIEW":     "unsupported",  // Materialized views are kept by Cassandra, not defined by a query
+		"DROP VIEW":       "unsupported",
+		"ALTER VIEW":      "unsupported",
+		"RENAME TABLE":    "unsupported",
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		// No joins: tables are modeled per query
+		"INNER JOIN": "unsupported",
+		"LEFT JOIN":  "unsupported",
+		"RIGHT JOIN": "unsupported",
+		"FULL JOIN":  "unsupported",
+		"CROSS JOIN": "unsupported",
+		
+		// Aggregates run within one partition
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "unsupported",
+		
+		"GROUP BY": "group_by",  // Partition key and clustering columns only
+		"ORDER BY": "order_by",  // Clustering columns only
+		"HAVING":   "unsupported",
+		"DISTINCT": "distinct",  // Partition key columns only
+		"LIMIT":    "limit",
+		"OFFSET":   "unsupported",  // Page with the driver's paging state
+		
+		"UNION":     "unsupported",
+		"UNION ALL": "unsupported",
+		"INTERSECT": "unsupported",
+		"EXCEPT":    "unsupported",
+		
+		// Window functions
+		"ROW NUMBER":   "unsupported",
+		"RANK":         "unsupported",
+		"DENSE RANK":   "unsupported",
+		"LAG":          "unsupported",
+		"LEAD":         "unsupported",
+		"NTILE":        "unsupported",
+		"PARTITION BY": "unsupported",
+		
+		// Advanced query features
+		"CTE":      "unsupported",
+		"SUBQUERY": "unsupported",
+		"EXISTS":   "unsupported",
+		"LIKE":     "unsupported",
+		"CASE":     "unsupported",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		// No transactions: a BATCH is atomic, IF NOT EXISTS is a lightweight transaction
+		"BEGIN":             "unsupported",
+		"START":             "unsupported",
+		"COMMIT":            "unsupported",
+		"ROLLBACK":          "unsupported",
+		"SAVEPOINT":         "unsupported",
+		"ROLLBACK TO":       "unsupported",
+		"RELEASE SAVEPOINT": "unsupported",
+		"SET TRANSACTION":   "unsupported",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
 		
 		// ========== GROUP 5: DCL Operations ==========
+		// Roles and permissions are managed with CQL's own GRANT syntax
 		"GRANT":       "unsupported",
 		"REVOKE":      "unsupported",
 		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
 		"DROP ROLE":   "unsupported",
 		"ASSIGN ROLE": "unsupported",
 		"REVOKE ROLE": "unsupported",
 		"CREATE USER": "unsupported",
 		"DROP USER":   "unsupported",
 		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
 	},
+
 	"MongoDB": {
 		// ========== GROUP 1: CRUD Operations ==========
 		"GET":         "find",
@@ -519,6 +1352,9 @@ var OperationMap = map[string]map[string]string{
 		"DROP VIEW":       "drop_view",
 		"ALTER VIEW":      "alter_view",
 		"RENAME TABLE":    "renameCollection",
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
 		"TRUNCATE": "deleteMany",
 		
 		// ========== GROUP 3: DQL Operations ==========
@@ -533,6 +1369,7 @@ var OperationMap = map[string]map[string]string{
 		"AVG":   "avg",
 		"MIN":   "min",
 		"MAX":   "max",
+		"STRING AGG": "string_agg",
 		
 		"GROUP BY": "group",
 		"ORDER BY": "sort",
@@ -556,7 +1393,7 @@ var OperationMap = map[string]map[string]string{
 		"PARTITION BY": "partition_by",
 		
 		// Advanced query features
-		"CTE":      "unsupported", // MongoDB doesn't have CTEs
+		"CTE":      "graphlookup", // Recursive CTEs only, as $graphLookup
 		"SUBQUERY": "pipeline",    // MongoDB uses aggregation pipeline
 		"EXISTS":   "exists",
 		"LIKE":     "regex",
@@ -571,17 +1408,133 @@ var OperationMap = map[string]map[string]string{
 		"ROLLBACK TO":       "unsupported",
 		"RELEASE SAVEPOINT": "unsupported",
 		"SET TRANSACTION":   "set_transaction",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
 		
 		// ========== GROUP 5: DCL Operations ==========
 		"GRANT":       "grant",
 		"REVOKE":      "revoke",
 		"CREATE ROLE": "create_role",
+		"ALTER ROLE":  "unsupported", // Login attributes are user options (updateUser)
 		"DROP ROLE":   "drop_role",
 		"ASSIGN ROLE": "grant_role",
 		"REVOKE ROLE": "revoke_role",
 		"CREATE USER": "create_user",
 		"DROP USER":   "drop_user",
 		"ALTER USER":  "alter_user",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
+	},
+
+	"Elasticsearch": {
+		// ========== GROUP 1: CRUD Operations ==========
+		// Reads only: documents are indexed with the Elasticsearch client
+		"GET":         "search",  // GET /index/_search
+		"CREATE":      "unsupported",
+		"UPDATE":      "unsupported",
+		"DELETE":      "unsupported",
+		"UPSERT":      "unsupported",
+		"BULK INSERT": "unsupported",
+		"BULK UPSERT": "unsupported",
+		"REPLACE":     "unsupported",
+		
+		// ========== GROUP 2: DDL Operations ==========
+		// Indexes and their mappings are managed with index templates
+		"CREATE TABLE":    "unsupported",
+		"ALTER TABLE":     "unsupported",
+		"DROP TABLE":      "unsupported",
+		"TRUNCATE TABLE":  "unsupported",
+		"TRUNCATE":        "unsupported",
+		"CREATE INDEX":    "unsupported",
+		"DROP INDEX":      "unsupported",
+		"CREATE DATABASE": "unsupported",
+		"DROP DATABASE":   "unsupported",
+		"CREATE SCHEMA":   "unsupported",
+		"DROP SCHEMA":     "unsupported",
+		"CREATE VIEW":     "unsupported",
+		"DROP VIEW":       "unsupported",
+		"ALTER VIEW":      "unsupported",
+		"RENAME TABLE":    "unsupported",
+		"SHOW TABLES":     "unsupported",
+		"SHOW COLUMNS":    "unsupported",
+		"SHOW INDEXES":    "unsupported",
+		
+		// ========== GROUP 3: DQL Operations ==========
+		"INNER JOIN": "unsupported",
+		"LEFT JOIN":  "unsupported",
+		"RIGHT JOIN": "unsupported",
+		"FULL JOIN":  "unsupported",
+		"CROSS JOIN": "unsupported",
+		
+		// Metric aggregations, or _count for COUNT *
+		"COUNT": "count",
+		"SUM":   "sum",
+		"AVG":   "avg",
+		"MIN":   "min",
+		"MAX":   "max",
+		"STRING AGG": "unsupported",
+		
+		"GROUP BY": "terms",     // Nested terms aggregations
+		"ORDER BY": "sort",
+		"HAVING":   "unsupported",
+		"DISTINCT": "collapse",  // GET: collapse on one field; COUNT: cardinality
+		"LIMIT":    "size",
+		"OFFSET":   "from",
+		
+		"UNION":     "unsupported",
+		"UNION ALL": "unsupported",
+		"INTERSECT": "unsupported",
+		"EXCEPT":    "unsupported",
+		
+		// Window functions
+		"ROW NUMBER":   "unsupported",
+		"RANK":         "unsupported",
+		"DENSE RANK":   "unsupported",
+		"LAG":          "unsupported",
+		"LEAD":         "unsupported",
+		"NTILE":        "unsupported",
+		"PARTITION BY": "unsupported",
+		
+		// Advanced query features
+		"CTE":      "unsupported",
+		"SUBQUERY": "unsupported",
+		"EXISTS":   "unsupported",
+		"LIKE":     "wildcard",
+		"CASE":     "unsupported",
+		
+		// ========== GROUP 4: TCL Operations ==========
+		// No transactions: each document is written on its own
+		"BEGIN":             "unsupported",
+		"START":             "unsupported",
+		"COMMIT":            "unsupported",
+		"ROLLBACK":          "unsupported",
+		"SAVEPOINT":         "unsupported",
+		"ROLLBACK TO":       "unsupported",
+		"RELEASE SAVEPOINT": "unsupported",
+		"SET TRANSACTION":   "unsupported",
+		"LOCK TABLES":       "unsupported",
+		"UNLOCK TABLES":     "unsupported",
+		
+		// ========== GROUP 5: DCL Operations ==========
+		// Users and roles are managed with the security API
+		"GRANT":       "unsupported",
+		"REVOKE":      "unsupported",
+		"CREATE ROLE": "unsupported",
+		"ALTER ROLE":  "unsupported",
+		"DROP ROLE":   "unsupported",
+		"ASSIGN ROLE": "unsupported",
+		"REVOKE ROLE": "unsupported",
+		"CREATE USER": "unsupported",
+		"DROP USER":   "unsupported",
+		"ALTER USER":  "unsupported",
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "unsupported",
+		"UNLISTEN": "unsupported",
+		"NOTIFY":   "unsupported",
 	},
 		"Redis": {
 		// ========== GROUP 1: CRUD Operations ==========
@@ -610,6 +1563,8 @@ var OperationMap = map[string]map[string]string{
 		"ROLLBACK TO": "",            // ← NO CHANGE
 		"RELEASE SAVEPOINT": "",      // ← NO CHANGE
 		"SET TRANSACTION": "",        // ← NO CHANGE
+		"LOCK TABLES": "",            // Redis has no table locks
+		"UNLOCK TABLES": "",
 		
 		// ========== GROUP 5: DCL Operations ==========
 		"GRANT":       "ACL",  // Translator must add "SETUSER" as first arg
@@ -618,9 +1573,15 @@ var OperationMap = map[string]map[string]string{
 		"DROP USER":   "ACL",  // Translator must add "DELUSER" as first arg
 		"ALTER USER":  "ACL",  // Translator must add "SETUSER" as first arg
 		"CREATE ROLE": "",     // Redis doesn't have roles, only users with permissions
+		"ALTER ROLE":  "",     // Redis doesn't have roles
 		"DROP ROLE":   "",     // Redis doesn't have roles
 		"ASSIGN ROLE": "",     // Redis doesn't have roles
 		"REVOKE ROLE": "",     // Redis doesn't have roles
+
+		// ========== GROUP 6: PUBSUB Operations ==========
+		"LISTEN":   "",        // Not wired to SUBSCRIBE yet
+		"UNLISTEN": "",
+		"NOTIFY":   "",
 	},
 }
 
@@ -633,6 +1594,7 @@ var TableNamingRules = map[string]string{
 		"DELETE":      "plural",
 		"UPSERT":      "plural",
 		"BULK INSERT": "plural",
+		"BULK UPSERT": "plural",
 		"REPLACE":     "plural",
 
 		// ========== GROUP 2: DDL - table operations use plural, others use exact ==========
@@ -640,9 +1602,13 @@ var TableNamingRules = map[string]string{
 		"ALTER TABLE":       "plural",  // References tables created by CREATE TABLE
 		"DROP TABLE":        "plural",  // References tables created by CREATE TABLE
 		"TRUNCATE TABLE":    "plural",  // References tables created by CREATE TABLE
+		"TRUNCATE":          "plural",  // TRUNCATE TABLE without TABLE
 		"CREATE INDEX":      "plural",  // Index on plural table names
 		"DROP INDEX":        "plural",  // Index on plural table names
 		"RENAME TABLE":      "plural",  // Renames plural tables
+		"SHOW TABLES":       "none",    // Lists every table
+		"SHOW COLUMNS":      "plural",  // Reads tables created by CREATE TABLE
+		"SHOW INDEXES":      "plural",  // Reads tables created by CREATE TABLE
 		"CREATE COLLECTION": "exact",   // MongoDB - explicit names
 		"DROP COLLECTION":   "exact",   // MongoDB - explicit names
 		"CREATE DATABASE":   "exact",   // Database names are explicit
@@ -663,6 +1629,7 @@ var TableNamingRules = map[string]string{
 		"AVG":   "plural",
 		"MIN":   "plural",
 		"MAX":   "plural",
+		"STRING AGG": "plural",
 		
 		"GROUP BY": "plural",
 		"ORDER BY": "plural",
@@ -699,11 +1666,14 @@ var TableNamingRules = map[string]string{
 		"ROLLBACK TO":       "none",
 		"RELEASE SAVEPOINT": "none",
 		"SET TRANSACTION":   "none",
+		"LOCK TABLES":       "none", // Each locked table is named by the translator
+		"UNLOCK TABLES":     "none",
 		
 		// ========== GROUP 5: DCL ==========
 		"GRANT":       "plural",
 		"REVOKE":      "plural",
 		"CREATE ROLE": "none",
+		"ALTER ROLE":  "none",
 		"DROP ROLE":   "none",
 		"ASSIGN ROLE": "none",
 		"REVOKE ROLE": "none",
@@ -711,6 +1681,11 @@ var TableNamingRules = map[string]string{
 		"DROP USER":   "none",
 		"ALTER USER":  "none",
 
+		// ========== GROUP 6: PUBSUB - channels, no table ==========
+		"LISTEN":   "none",
+		"UNLISTEN": "none",
+		"NOTIFY":   "none",
+
 		// ========== PG SPECIFIC DDL - table references use plural ==========
 		"CREATE TRIGGER":   "plural",  // ON {table}
 		"DROP TRIGGER":     "plural",  // ON {table}
@@ -719,6 +1694,7 @@ var TableNamingRules = map[string]string{
 		"CREATE RULE":      "plural",  // ON {table}
 		"DROP RULE":        "plural",  // ON {table}
 		"COMMENT ON":       "plural",  // TABLE/COLUMN references
+		"CREATE PARTITION": "plural",  // PARTITION OF {table}
 
 		// ========== PG SPECIFIC DDL - standalone objects use exact ==========
 		"CREATE SEQUENCE":  "exact",
@@ -806,6 +1782,12 @@ var OperationTemplates = map[string]OperationMapping{
 		MongoDB:    "db.{table}.insertMany([{documents}])",
 		Redis:      "MSET {key1} {value1} {key2} {value2}",
 	},
+	"BULK UPSERT": {
+		OQL:        "BULK UPSERT {Entity} WITH [{rows}] ON {conflict_fields}",
+		PostgreSQL: "INSERT INTO {table} ({fields}) VALUES {multiple_rows} ON CONFLICT ({conflict}) DO UPDATE SET {updates}",
+		MySQL:      "INSERT INTO {table} ({fields}) VALUES {multiple_rows} ON DUPLICATE KEY UPDATE {updates}",
+		SQLite:     "INSERT OR REPLACE INTO {table} ({fields}) VALUES {multiple_rows}",
+	},
 	
 	// ========== GROUP 2: DDL Operations ==========
 	"CREATE TABLE": {
@@ -815,6 +1797,13 @@ var OperationTemplates = map[string]OperationMapping{
 		SQLite:     "CREATE TABLE {table} ({columns})",
 		MongoDB:    "db.createCollection('{collection}')",
 	},
+	"GENERATED COLUMN": {
+		OQL:        "CREATE TABLE {table} WITH {column}:{TYPE} GENERATED AS ({expr})",
+		PostgreSQL: "{column} {type} GENERATED ALWAYS AS ({expr}) STORED",
+		MySQL:      "{column} {type} GENERATED ALWAYS AS ({expr}) STORED",
+		// Fallback: plain column kept in sync by triggers
+		SQLite:     "{column} {type}; CREATE TRIGGER {table}_{column}_insert AFTER INSERT ON {table} BEGIN UPDATE {table} SET {column} = ({expr}) WHERE rowid = NEW.rowid; END; CREATE TRIGGER {table}_{column}_update AFTER UPDATE ON {table} BEGIN UPDATE {table} SET {column} = ({expr}) WHERE rowid = NEW.rowid; END",
+	},
 	"DROP TABLE": {
 		OQL:        "DROP TABLE {table}",
 		PostgreSQL: "DROP TABLE IF EXISTS {table}",
@@ -919,6 +1908,22 @@ var OperationTemplates = map[string]OperationMapping{
 		SQLite:     "PRAGMA read_uncommitted = {value}",
 		MongoDB:    "session.startTransaction({readConcern: {level}})",
 	},
+	"LOCK TABLES": {
+		OQL:        "LOCK TABLES {table} READ|WRITE[, {table} READ|WRITE]",
+		PostgreSQL: "N/A (use GET ... FOR UPDATE inside a transaction)",
+		MySQL:      "LOCK TABLES {table} READ|WRITE[, {table} READ|WRITE]",
+		SQLite:     "N/A",
+		MongoDB:    "N/A",
+		Redis:      "N/A",
+	},
+	"UNLOCK TABLES": {
+		OQL:        "UNLOCK TABLES",
+		PostgreSQL: "N/A",
+		MySQL:      "UNLOCK TABLES",
+		SQLite:     "N/A",
+		MongoDB:    "N/A",
+		Redis:      "N/A",
+	},
 	
 	// ========== GROUP 5: DCL Operations ==========
 	"GRANT": {
@@ -962,13 +1967,21 @@ var OperationTemplates = map[string]OperationMapping{
 		Redis:      "ACL SETUSER {username} >{password}",  // ✅ ADDED
 	},
 	"CREATE ROLE": {
-		OQL:        "CREATE ROLE {role_name}",
-		PostgreSQL: "CREATE ROLE {role_name}",
+		OQL:        "CREATE ROLE {role_name} [WITH {options}]",
+		PostgreSQL: "CREATE ROLE {role_name} [WITH {options}]",
 		MySQL:      "CREATE ROLE {role_name}",
 		SQLite:     "N/A",
 		MongoDB:    "db.createRole({role: '{role_name}', privileges: [], roles: []})",
 		Redis:      "N/A (Redis has users with permissions, not roles)",  // ✅ ADDED
 	},
+	"ALTER ROLE": {
+		OQL:        "ALTER ROLE {role_name} WITH {options}",
+		PostgreSQL: "ALTER ROLE {role_name} WITH {options}",
+		MySQL:      "ALTER USER {role_name} {account_options}",
+		SQLite:     "N/A",
+		MongoDB:    "N/A",
+		Redis:      "N/A (Redis has users with permissions, not roles)",
+	},
 	"DROP ROLE": {
 		OQL:        "DROP ROLE {role_name}",
 		PostgreSQL: "DROP ROLE {role_name}",
@@ -993,6 +2006,32 @@ var OperationTemplates = map[string]OperationMapping{
 		MongoDB:    "db.revokeRolesFromUser('{username}', ['{role_name}'])",
 		Redis:      "N/A (Redis has users with permissions, not roles)",  // ✅ ADDED
 	},
+
+	// ========== GROUP 6: PUBSUB Operations ==========
+	"LISTEN": {
+		OQL:        "LISTEN {channel}",
+		PostgreSQL: "LISTEN {channel}",
+		MySQL:      "N/A",
+		SQLite:     "N/A",
+		MongoDB:    "N/A (use change streams)",
+		Redis:      "SUBSCRIBE {channel}",
+	},
+	"UNLISTEN": {
+		OQL:        "UNLISTEN {channel|*}",
+		PostgreSQL: "UNLISTEN {channel|*}",
+		MySQL:      "N/A",
+		SQLite:     "N/A",
+		MongoDB:    "N/A",
+		Redis:      "UNSUBSCRIBE {channel}",
+	},
+	"NOTIFY": {
+		OQL:        "NOTIFY {channel}[, '{payload}']",
+		PostgreSQL: "NOTIFY {channel}[, '{payload}']",
+		MySQL:      "N/A",
+		SQLite:     "N/A",
+		MongoDB:    "N/A",
+		Redis:      "PUBLISH {channel} {payload}",
+	},
 }
 
 // TranslatedToGroup - reverse mapping built from above
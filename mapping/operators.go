@@ -26,6 +26,20 @@ var OperatorMap = map[string]map[string]string{
 		"IS_NULL":     "IS NULL",
 		"IS_NOT_NULL": "IS NOT NULL",
 		
+		// JSONB operators (PostgreSQL specific)
+		"@>": "@>",  // Contains
+		"<@": "<@",  // Contained by
+		"?":  "?",   // Key exists
+		"?|": "?|",  // Any key exists
+		"?&": "?&",  // All keys exist
+		
+		// Full-text search
+		"SEARCH": "@@",  // to_tsvector(col) @@ plainto_tsquery(value)
+		
+		// Geospatial (PostGIS)
+		"NEAR":   "ST_DWithin",   // ST_DWithin(col::geography, point, meters)
+		"WITHIN": "ST_Contains",  // ST_Contains(polygon, col::geometry)
+		
 		// Logical operators
 		"AND": "AND",
 		"OR":  "OR",
@@ -51,6 +65,14 @@ var OperatorMap = map[string]map[string]string{
 		"NOT_ILIKE":   "NOT LIKE",
 		"IS_NULL":     "IS NULL",
 		"IS_NOT_NULL": "IS NOT NULL",
+		"SEARCH":      "MATCH AGAINST",  // Requires a FULLTEXT index
+		
+		// JSON operators (JSON column functions)
+		"@>": "JSON_CONTAINS",       // JSON_CONTAINS(col, doc)
+		"<@": "JSON_CONTAINS",       // JSON_CONTAINS(doc, col)
+		"?":  "JSON_CONTAINS_PATH",  // JSON_CONTAINS_PATH(col, 'one', '$.key')
+		"?|": "JSON_CONTAINS_PATH",  // 'one' mode
+		"?&": "JSON_CONTAINS_PATH",  // 'all' mode
 		
 		// Logical operators
 		"AND": "AND",
@@ -77,12 +99,251 @@ var OperatorMap = map[string]map[string]string{
 		"NOT_ILIKE":   "NOT LIKE",
 		"IS_NULL":     "IS NULL",
 		"IS_NOT_NULL": "IS NOT NULL",
+		"SEARCH":      "MATCH",  // Requires a FULLTEXT index (FTS5 table)
+		
+		// JSON operators (JSON1 functions over JSON text)
+		"@>": "json_each",  // Every value of the document is in col
+		"<@": "json_each",  // Every value of col is in the document
+		"?":  "json_type",  // json_type(col, '$.key') IS NOT NULL
+		"?|": "json_type",
+		"?&": "json_type",
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+		"NOT": "NOT",
+	},
+	"Oracle": {
+		// Basic comparison operators
+		"=":  "=",
+		"!=": "!=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "LIKE",  // Oracle doesn't have ILIKE, use LIKE with UPPER()
+		"NOT_ILIKE":   "NOT LIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// JSON key operators (@> and <@ have no Oracle equivalent)
+		"?":  "JSON_EXISTS",  // JSON_EXISTS(col, '$.key')
+		"?|": "JSON_EXISTS",
+		"?&": "JSON_EXISTS",
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+		"NOT": "NOT",
+	},
+	"SQLServer": {
+		// Basic comparison operators
+		"=":  "=",
+		"!=": "!=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "LIKE",  // No ILIKE: LOWER() on both sides, whatever the collation
+		"NOT_ILIKE":   "NOT LIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// JSON key operators (SQL Server 2022; no containment operators)
+		"?":  "JSON_PATH_EXISTS",  // JSON_PATH_EXISTS(col, '$.key') = 1
+		"?|": "JSON_PATH_EXISTS",
+		"?&": "JSON_PATH_EXISTS",
 		
 		// Logical operators
 		"AND": "AND",
 		"OR":  "OR",
 		"NOT": "NOT",
 	},
+	"CockroachDB": {
+		// Basic comparison operators
+		"=":  "=",
+		"!=": "!=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "ILIKE",        // Case-insensitive LIKE
+		"NOT_ILIKE":   "NOT ILIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// JSONB operators
+		"@>": "@>",  // Contains
+		"<@": "<@",  // Contained by
+		"?":  "?",   // Key exists
+		"?|": "?|",  // Any key exists
+		"?&": "?&",  // All keys exist
+		
+		// Full-text search (CockroachDB 23.1+)
+		"SEARCH": "@@",  // to_tsvector(col) @@ plainto_tsquery(value)
+		
+		// Geospatial (built in)
+		"NEAR":   "ST_DWithin",   // ST_DWithin(col::geography, point, meters)
+		"WITHIN": "ST_Contains",  // ST_Contains(polygon, col::geometry)
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+		"NOT": "NOT",
+	},
+	"ClickHouse": {
+		// Basic comparison operators
+		"=":  "=",
+		"!=": "!=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "ILIKE",        // Case-insensitive LIKE
+		"NOT_ILIKE":   "NOT ILIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// Array containment
+		"@>": "hasAll",  // hasAll(col, [values])
+		"<@": "hasAll",  // hasAll([values], col)
+		
+		// JSON key operators (JSON stored as String)
+		"?":  "JSONHas",  // JSONHas(col, 'key')
+		"?|": "JSONHas",
+		"?&": "JSONHas",
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+		"NOT": "NOT",
+	},	"BigQuery": {
+		// Basic comparison operators
+		"=":  "=",
+		"!=": "!=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "LIKE",  // No ILIKE: LOWER() on both sides
+		"NOT_ILIKE":   "NOT LIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// Array containment (every element found IN UNNEST(array))
+		"@>": "UNNEST",
+		"<@": "UNNEST",
+		
+		// JSON key operators
+		"?":  "JSON_QUERY",  // JSON_QUERY(col, '$.key') IS NOT NULL
+		"?|": "JSON_QUERY",
+		"?&": "JSON_QUERY",
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+		"NOT": "NOT",
+	},
+	
+	"Snowflake": {
+		// Basic comparison operators
+		"=":  "=",
+		"!=": "!=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "ILIKE",
+		"NOT_ILIKE":   "NOT ILIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// Array containment (nothing left after ARRAY_EXCEPT)
+		"@>": "ARRAY_EXCEPT",
+		"<@": "ARRAY_EXCEPT",
+		
+		// JSON key operators
+		"?":  "IS NOT NULL",  // col['key'] IS NOT NULL
+		"?|": "IS NOT NULL",
+		"?&": "IS NOT NULL",
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+		"NOT": "NOT",
+	},
+	
+	"Cassandra": {
+		// Basic comparison operators (no !=: rows are found by key, not scanned)
+		"=":  "=",
+		">":  ">",
+		"<":  "<",
+		">=": ">=",
+		"<=": "<=",
+		
+		// Advanced operators
+		"IN":      "IN",
+		"BETWEEN": "BETWEEN",  // col >= ? AND col <= ?
+		
+		// Collection containment (one CONTAINS per element)
+		"@>": "CONTAINS",
+		
+		// Map key operators
+		"?":  "CONTAINS KEY",
+		"?&": "CONTAINS KEY",
+		
+		// Logical operators (no OR or NOT)
+		"AND": "AND",
+	},
+
 	"MongoDB": {
 		// Basic comparison operators
 		"=":  "$eq",
@@ -103,12 +364,73 @@ var OperatorMap = map[string]map[string]string{
 		"NOT_ILIKE":   "$not/$regex",
 		"IS_NULL":     "null",
 		"IS_NOT_NULL": "$ne:null",
+		"SEARCH":      "$text",  // Requires a text index
+		"NEAR":        "$near",       // Requires a 2dsphere index
+		"WITHIN":      "$geoWithin",
 		
 		// Logical operators
 		"AND": "implicit",  // MongoDB uses implicit AND in queries
 		"OR":  "$or",
 		"NOT": "$not",
 	},
+	
+	"Elasticsearch": {
+		// Basic comparison operators
+		"=":  "term",
+		"!=": "must_not/term",
+		">":  "range/gt",
+		"<":  "range/lt",
+		">=": "range/gte",
+		"<=": "range/lte",
+		
+		// Advanced operators
+		"IN":          "terms",
+		"NOT_IN":      "must_not/terms",
+		"BETWEEN":     "range/gte/lte",
+		"NOT_BETWEEN": "must_not/range",
+		"LIKE":        "wildcard",  // % and _ become * and ?
+		"NOT_LIKE":    "must_not/wildcard",
+		"ILIKE":       "wildcard",  // case_insensitive: true
+		"NOT_ILIKE":   "must_not/wildcard",
+		"IS_NULL":     "must_not/exists",
+		"IS_NOT_NULL": "exists",
+		"SEARCH":      "match",  // Analyzed full-text query, scored
+		
+		// Arrays are multi-valued fields (one term per element)
+		"@>": "term",
+		
+		// Object key operators (exists on field.key)
+		"?":  "exists",
+		"?|": "exists",
+		"?&": "exists",
+		
+		// Logical operators
+		"AND": "bool/filter",
+		"OR":  "bool/should",
+	},
+	"Redis": {
+		// Evaluated client side on each hash (redis.MatchesConditions)
+		"=":           "=",
+		"!=":          "!=",
+		">":           ">",
+		"<":           "<",
+		">=":          ">=",
+		"<=":          "<=",
+		"IN":          "IN",
+		"NOT_IN":      "NOT IN",
+		"BETWEEN":     "BETWEEN",
+		"NOT_BETWEEN": "NOT BETWEEN",
+		"LIKE":        "LIKE",
+		"NOT_LIKE":    "NOT LIKE",
+		"ILIKE":       "ILIKE",
+		"NOT_ILIKE":   "NOT ILIKE",
+		"IS_NULL":     "IS NULL",
+		"IS_NOT_NULL": "IS NOT NULL",
+		
+		// Logical operators
+		"AND": "AND",
+		"OR":  "OR",
+	},
 }
 
 // OperatorDefinition defines operators for documentation
@@ -259,6 +581,12 @@ var OperatorExamples = map[string]map[string]string{
 		"ILIKE":       "email ILIKE '%@gmail.com'",
 		"IS_NULL":     "deleted_at IS NULL",
 		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "metadata->>'plan' = 'pro'",
+		"@>":          "metadata @> '{\"plan\": \"pro\"}'",
+		"?|":          "tags ?| ARRAY['new', 'sale']",
+		"SEARCH":      "to_tsvector(body) @@ plainto_tsquery('postgres tips')",
+		"NEAR":        "ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint(-73.97, 40.77), 4326)::geography, 5000)",
+		"WITHIN":      "ST_Contains(ST_GeomFromText('POLYGON((0 0, 0 10, 10 10, 0 0))', 4326), location::geometry)",
 	},
 	"MySQL": {
 		"=":           "age = 25",
@@ -270,6 +598,10 @@ var OperatorExamples = map[string]map[string]string{
 		"ILIKE":       "LOWER(email) LIKE LOWER('%@gmail.com')",
 		"IS_NULL":     "deleted_at IS NULL",
 		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"SEARCH":      "MATCH(body) AGAINST('postgres tips' IN BOOLEAN MODE)",
+		"->>":         "JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.plan')) = 'pro'",
+		"@>":          "JSON_CONTAINS(metadata, '{\"plan\": \"pro\"}')",
+		"?|":          "JSON_CONTAINS_PATH(metadata, 'one', '$.plan', '$.tier')",
 	},
 	"SQLite": {
 		"=":           "age = 25",
@@ -281,7 +613,103 @@ var OperatorExamples = map[string]map[string]string{
 		"ILIKE":       "name LIKE 'john%'  -- case-insensitive by default",
 		"IS_NULL":     "deleted_at IS NULL",
 		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"SEARCH":      "posts.rowid IN (SELECT rowid FROM posts_fts WHERE body MATCH 'postgres tips')",
+	},
+	"Oracle": {
+		"=":           "age = 25",
+		"!=":          "status != 'inactive'",
+		">":           "price > 100",
+		"IN":          "status IN ('active', 'pending')",
+		"BETWEEN":     "age BETWEEN 18 AND 65",
+		"LIKE":        "name LIKE 'John%'",
+		"ILIKE":       "UPPER(email) LIKE UPPER('%@gmail.com')",
+		"IS_NULL":     "deleted_at IS NULL",
+		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "JSON_VALUE(metadata, '$.plan') = 'pro'",
+		"?|":          "(JSON_EXISTS(metadata, '$.plan') OR JSON_EXISTS(metadata, '$.tier'))",
+	},
+	"SQLServer": {
+		"=":           "age = 25",
+		"!=":          "status != N'inactive'",
+		">":           "price > 100",
+		"IN":          "status IN (N'active', N'pending')",
+		"BETWEEN":     "age BETWEEN 18 AND 65",
+		"LIKE":        "name LIKE N'John%'",
+		"ILIKE":       "LOWER(email) LIKE LOWER(N'%@gmail.com')",
+		"IS_NULL":     "deleted_at IS NULL",
+		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "JSON_VALUE(metadata, N'$.plan') = N'pro'",
+		"?":           "JSON_PATH_EXISTS(metadata, N'$.trial') = 1",
+	},
+	"CockroachDB": {
+		"=":           "age = 25",
+		"!=":          "status != 'inactive'",
+		">":           "price > 100",
+		"IN":          "status IN ('active', 'pending')",
+		"BETWEEN":     "age BETWEEN 18 AND 65",
+		"LIKE":        "name LIKE 'John%'",
+		"ILIKE":       "email ILIKE '%@gmail.com'",
+		"IS_NULL":     "deleted_at IS NULL",
+		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "metadata->>'plan' = 'pro'",
+		"@>":          "metadata @> '{\"plan\": \"pro\"}'",
+		"?|":          "tags ?| ARRAY['new', 'sale']",
+		"SEARCH":      "to_tsvector(body) @@ plainto_tsquery('cockroach tips')",
+		"NEAR":        "ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint(-73.97, 40.77), 4326)::geography, 5000)",
+		"WITHIN":      "ST_Contains(ST_GeomFromText('POLYGON((0 0, 0 10, 10 10, 0 0))', 4326), location::geometry)",
 	},
+	"ClickHouse": {
+		"=":           "age = 25",
+		"!=":          "status != 'inactive'",
+		">":           "price > 100",
+		"IN":          "status IN ('active', 'pending')",
+		"BETWEEN":     "age BETWEEN 18 AND 65",
+		"LIKE":        "name LIKE 'John%'",
+		"ILIKE":       "email ILIKE '%@gmail.com'",
+		"IS_NULL":     "deleted_at IS NULL",
+		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "JSONExtractString(metadata, 'plan') = 'pro'",
+		"@>":          "hasAll(tags, ['new', 'sale'])",
+		"?|":          "(JSONHas(metadata, 'plan') OR JSONHas(metadata, 'tier'))",
+	},	"BigQuery": {
+		"=":           "age = 25",
+		"!=":          "status != 'inactive'",
+		">":           "price > 100",
+		"IN":          "status IN ('active', 'pending')",
+		"BETWEEN":     "age BETWEEN 18 AND 65",
+		"LIKE":        "name LIKE 'John%'",
+		"ILIKE":       "LOWER(email) LIKE LOWER('%@gmail.com')",
+		"IS_NULL":     "deleted_at IS NULL",
+		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "JSON_VALUE(metadata, '$.plan') = 'pro'",
+		"@>":          "NOT EXISTS(SELECT 1 FROM UNNEST(['new', 'sale']) AS elem WHERE elem NOT IN UNNEST(tags))",
+		"?":           "JSON_QUERY(metadata, '$.trial') IS NOT NULL",
+	},
+	
+	"Snowflake": {
+		"=":           "age = 25",
+		"!=":          "status != 'inactive'",
+		">":           "price > 100",
+		"IN":          "status IN ('active', 'pending')",
+		"BETWEEN":     "age BETWEEN 18 AND 65",
+		"LIKE":        "name LIKE 'John%'",
+		"ILIKE":       "email ILIKE '%@gmail.com'",
+		"IS_NULL":     "deleted_at IS NULL",
+		"IS_NOT_NULL": "updated_at IS NOT NULL",
+		"->>":         "metadata['plan']::STRING = 'pro'",
+		"@>":          "ARRAY_SIZE(ARRAY_EXCEPT(ARRAY_DISTINCT(ARRAY_CONSTRUCT('new', 'sale')), tags)) = 0",
+		"?":           "metadata['trial'] IS NOT NULL",
+	},
+	
+	"Cassandra": {
+		"=":       "user_id = 25",
+		">":       "created_at > '2026-01-01'",
+		"IN":      "status IN ('active', 'pending')",
+		"BETWEEN": "created_at >= '2026-01-01' AND created_at <= '2026-02-01'",
+		"@>":      "tags CONTAINS 'new' AND tags CONTAINS 'sale'",
+		"?":       "metadata CONTAINS KEY 'trial'",
+	},
+
 	"MongoDB": {
 		"$eq":  "{age: {$eq: 25}}",
 		"$ne":  "{status: {$ne: 'inactive'}}",
@@ -292,6 +720,20 @@ var OperatorExamples = map[string]map[string]string{
 		"$regex_i": "{email: {$regex: /@gmail.com$/i}}",
 		"IS_NULL": "{deleted_at: null}",
 		"IS_NOT_NULL": "{updated_at: {$ne: null}}",
+		"$text":       "{$text: {$search: 'postgres tips'}}",
+		"$near":       "{location: {$near: {$geometry: {type: 'Point', coordinates: [-73.97, 40.77]}, $maxDistance: 5000}}}",
+		"$geoWithin":  "{location: {$geoWithin: {$geometry: {type: 'Polygon', coordinates: [[[0, 0], [0, 10], [10, 10], [0, 0]]]}}}}",
+	},
+	
+	"Elasticsearch": {
+		"=":       `{"term": {"status": "active"}}`,
+		">":       `{"range": {"price": {"gt": 100}}}`,
+		"IN":      `{"terms": {"status": ["active", "pending"]}}`,
+		"BETWEEN": `{"range": {"age": {"gte": 18, "lte": 65}}}`,
+		"LIKE":    `{"wildcard": {"name": {"value": "John*"}}}`,
+		"ILIKE":   `{"wildcard": {"email": {"value": "*@gmail.com", "case_insensitive": true}}}`,
+		"IS_NULL": `{"bool": {"must_not": [{"exists": {"field": "deleted_at"}}]}}`,
+		"SEARCH":  `{"match": {"body": {"query": "postgres tips"}}}`,
 	},
 }
 
@@ -304,6 +746,22 @@ var ArithmeticOperators = map[string]bool{
     "%": true,
 }
 
+// JSONPathOperators - JSON field access (expressions, not conditions)
+// data->'key' returns JSON, data->>'key' returns text
+var JSONPathOperators = map[string]bool{
+	"->":  true,
+	"->>": true,
+}
+
+// SearchRankField - ORDER BY pseudo-field that sorts SEARCH matches by relevance
+// ORDER BY RELEVANCE -> ts_rank (PostgreSQL), MATCH score (MySQL), textScore (MongoDB)
+const SearchRankField = "RELEVANCE"
+
+// IsSearchRankField checks if an ORDER BY field is the relevance pseudo-field
+func IsSearchRankField(field string) bool {
+	return strings.ToUpper(field) == SearchRankField
+}
+
 // OperatorCategories - SSOT for operator types
 var OperatorCategories = map[string]string{
 	// Multi-value operators (IN)
@@ -329,6 +787,52 @@ var OperatorCategories = map[string]string{
 	"NOT_LIKE":    "COMPARISON",
 	"ILIKE":       "COMPARISON",
 	"NOT_ILIKE":   "COMPARISON",
+	
+	// JSONB containment / key existence
+	"@>":          "COMPARISON",
+	"<@":          "COMPARISON",
+	"?":           "COMPARISON",
+	"?|":          "MULTI_VALUE",
+	"?&":          "MULTI_VALUE",
+	
+	// Full-text search (single value)
+	"SEARCH":      "COMPARISON",
+	
+	// Geospatial: NEAR POINT(lng, lat) DISTANCE meters, WITHIN POLYGON(...)
+	"NEAR":        "DISTANCE",
+	"WITHIN":      "POLYGON",
+}
+
+// CollationOperators - string comparisons a COLLATE clause applies to on SQL databases
+// LIKE keeps each database's own case rules
+var CollationOperators = map[string]bool{
+	"=": true, "!=": true, "<>": true, ">": true, "<": true, ">=": true, "<=": true,
+	"IN": true, "NOT_IN": true, "BETWEEN": true, "NOT_BETWEEN": true,
+}
+
+// GeoOperators - operators that need geospatial support (MongoDB, PostgreSQL with PostGIS)
+var GeoOperators = map[string]bool{
+	"NEAR":   true,
+	"WITHIN": true,
+}
+
+// IsGeoOperator checks if operator is NEAR or WITHIN
+func IsGeoOperator(op string) bool {
+	return GeoOperators[strings.ToUpper(op)]
+}
+
+// ContextualOperators - word operators that are also common column names, so
+// they are operators only after the left side of a condition, never as an
+// operand, an ORDER BY key or a field name
+var ContextualOperators = map[string]bool{
+	"SEARCH": true,
+	"NEAR":   true,
+	"WITHIN": true,
+}
+
+// IsContextualOperator checks if a word operator is also a valid name
+func IsContextualOperator(op string) bool {
+	return ContextualOperators[strings.ToUpper(op)]
 }
 
 // WindowFunctions - SSOT for window function names
@@ -379,6 +883,11 @@ func IsArithmeticOperator(op string) bool {
     return ArithmeticOperators[op]
 }
 
+// IsJSONPathOperator checks if token is a JSON access operator (->, ->>)
+func IsJSONPathOperator(op string) bool {
+	return JSONPathOperators[op]
+}
+
 // IsComparisonOperator checks if token is comparison operator
 func IsComparisonOperator(op string) bool {
     upper := strings.ToUpper(op)
@@ -1,5 +1,10 @@
 package mapping
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TypeMap - Runtime mapping for schema translators
 // Usage: TypeMap["PostgreSQL"]["AUTO"] returns "SERIAL"
 // Maps universal type names to database-specific type names
@@ -127,6 +132,292 @@ var TypeMap = map[string]map[string]string{
 		"UUID":      "TEXT",
 	},
 	
+	"Oracle": {
+		// Primary Key Types (identity columns, 12c+)
+		"AUTO":      "NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY",
+		"BIGAUTO":   "NUMBER(19) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY",
+		
+		// Numeric Types (all NUMBER)
+		"INT":       "NUMBER(10)",
+		"BIGINT":    "NUMBER(19)",
+		"SMALLINT":  "NUMBER(5)",
+		"DECIMAL":   "NUMBER",
+		"NUMERIC":   "NUMBER",
+		"REAL":      "BINARY_FLOAT",
+		"FLOAT":     "BINARY_DOUBLE",
+		
+		// String Types
+		"STRING":    "VARCHAR2(255)",
+		"TEXT":      "CLOB",
+		"CHAR":      "CHAR",
+		
+		// Boolean (stored as NUMBER(1) 1 / 0)
+		"BOOLEAN":   "NUMBER(1)",
+		"BOOL":      "NUMBER(1)",
+		
+		// Date/Time Types
+		"TIMESTAMP": "TIMESTAMP",
+		"DATETIME":  "TIMESTAMP",
+		"DATE":      "DATE",             // Oracle DATE also holds the time of day
+		"TIME":      "INTERVAL DAY(0) TO SECOND",
+		
+		// Binary Types
+		"BINARY":    "BLOB",
+		"BLOB":      "BLOB",
+		
+		// JSON Types (native JSON is 21c+)
+		"JSON":      "JSON",
+		"JSONB":     "JSON",
+		
+		// UUID
+		"UUID":      "CHAR(36)",
+	},
+	
+	"SQLServer": {
+		// Primary Key Types (IDENTITY columns)
+		"AUTO":      "INT IDENTITY(1,1) PRIMARY KEY",
+		"BIGAUTO":   "BIGINT IDENTITY(1,1) PRIMARY KEY",
+		
+		// Numeric Types
+		"INT":       "INT",
+		"BIGINT":    "BIGINT",
+		"SMALLINT":  "SMALLINT",
+		"DECIMAL":   "DECIMAL",
+		"NUMERIC":   "DECIMAL",
+		"REAL":      "REAL",
+		"FLOAT":     "FLOAT",
+		
+		// String Types (Unicode)
+		"STRING":    "NVARCHAR(255)",
+		"TEXT":      "NVARCHAR(MAX)",
+		"CHAR":      "NCHAR",
+		
+		// Boolean (stored as BIT 1 / 0)
+		"BOOLEAN":   "BIT",
+		"BOOL":      "BIT",
+		
+		// Date/Time Types (TIMESTAMP is a row version in SQL Server)
+		"TIMESTAMP": "DATETIME2",
+		"DATETIME":  "DATETIME2",
+		"DATE":      "DATE",
+		"TIME":      "TIME",
+		
+		// Binary Types
+		"BINARY":    "VARBINARY(MAX)",
+		"BLOB":      "VARBINARY(MAX)",
+		
+		// JSON Types (stored as text, read with JSON_VALUE)
+		"JSON":      "NVARCHAR(MAX)",
+		"JSONB":     "NVARCHAR(MAX)",
+		
+		// UUID
+		"UUID":      "UNIQUEIDENTIFIER",
+	},
+	
+	"CockroachDB": {
+		// Primary Key Types (SERIAL is INT8 DEFAULT unique_rowid(): unique, not sequential)
+		"AUTO":      "SERIAL",
+		"BIGAUTO":   "BIGSERIAL",
+		
+		// Numeric Types (a bare INT is 64-bit on CockroachDB, so sizes are explicit)
+		"INT":       "INT4",
+		"BIGINT":    "INT8",
+		"SMALLINT":  "INT2",
+		"DECIMAL":   "DECIMAL",
+		"NUMERIC":   "DECIMAL",
+		"REAL":      "FLOAT4",
+		"FLOAT":     "FLOAT8",
+		
+		// String Types (VARCHAR and TEXT are aliases of STRING)
+		"STRING":    "VARCHAR",
+		"TEXT":      "TEXT",
+		"CHAR":      "CHAR",
+		
+		// Boolean
+		"BOOLEAN":   "BOOLEAN",
+		"BOOL":      "BOOLEAN",
+		
+		// Date/Time Types
+		"TIMESTAMP": "TIMESTAMP",
+		"DATETIME":  "TIMESTAMP",
+		"DATE":      "DATE",
+		"TIME":      "TIME",
+		
+		// Binary Types
+		"BINARY":    "BYTES",
+		"BLOB":      "BYTES",
+		
+		// JSON Types (JSON is an alias of JSONB)
+		"JSON":      "JSONB",
+		"JSONB":     "JSONB",
+		
+		// UUID
+		"UUID":      "UUID",
+	},
+	
+	"ClickHouse": {
+		// Primary Key Types (no sequences: Snowflake IDs are unique and roughly time-ordered)
+		"AUTO":      "UInt64 DEFAULT generateSnowflakeID()",
+		"BIGAUTO":   "UInt64 DEFAULT generateSnowflakeID()",
+		
+		// Numeric Types
+		"INT":       "Int32",
+		"BIGINT":    "Int64",
+		"SMALLINT":  "Int16",
+		"DECIMAL":   "Decimal(18, 2)",
+		"NUMERIC":   "Decimal(18, 2)",
+		"REAL":      "Float32",
+		"FLOAT":     "Float64",
+		
+		// String Types (String has no length; a size is dropped)
+		"STRING":    "String",
+		"TEXT":      "String",
+		"CHAR":      "String",
+		
+		// Boolean
+		"BOOLEAN":   "Bool",
+		"BOOL":      "Bool",
+		
+		// Date/Time Types (no time-of-day type)
+		"TIMESTAMP": "DateTime64(3)",
+		"DATETIME":  "DateTime64(3)",
+		"DATE":      "Date32",
+		"TIME":      "String",
+		
+		// Binary Types (String holds arbitrary bytes)
+		"BINARY":    "String",
+		"BLOB":      "String",
+		
+		// JSON Types (stored as text, read with JSONExtract*)
+		"JSON":      "String",
+		"JSONB":     "String",
+		
+		// UUID
+		"UUID":      "UUID",
+	},
+	
+	"BigQuery": {
+		// Primary Key Types (no sequences: keys are generated UUID strings)
+		"AUTO":      "STRING DEFAULT GENERATE_UUID()",
+		"BIGAUTO":   "STRING DEFAULT GENERATE_UUID()",
+		
+		// Numeric Types (one 64-bit integer type)
+		"INT":       "INT64",
+		"BIGINT":    "INT64",
+		"SMALLINT":  "INT64",
+		"DECIMAL":   "NUMERIC",
+		"NUMERIC":   "NUMERIC",
+		"REAL":      "FLOAT64",
+		"FLOAT":     "FLOAT64",
+		
+		// String Types
+		"STRING":    "STRING",
+		"TEXT":      "STRING",
+		"CHAR":      "STRING",
+		
+		// Boolean
+		"BOOLEAN":   "BOOL",
+		"BOOL":      "BOOL",
+		
+		// Date/Time Types (TIMESTAMP is a point in time, DATETIME a civil date and time)
+		"TIMESTAMP": "TIMESTAMP",
+		"DATETIME":  "DATETIME",
+		"DATE":      "DATE",
+		"TIME":      "TIME",
+		
+		// Binary Types
+		"BINARY":    "BYTES",
+		"BLOB":      "BYTES",
+		
+		// JSON Types
+		"JSON":      "JSON",
+		"JSONB":     "JSON",
+		
+		// UUID (GENERATE_UUID() returns a string)
+		"UUID":      "STRING",
+	},
+	
+	"Snowflake": {
+		// Primary Key Types (INT and BIGINT are both NUMBER(38,0))
+		"AUTO":      "INT AUTOINCREMENT",
+		"BIGAUTO":   "BIGINT AUTOINCREMENT",
+		
+		// Numeric Types
+		"INT":       "INT",
+		"BIGINT":    "BIGINT",
+		"SMALLINT":  "SMALLINT",
+		"DECIMAL":   "NUMBER",
+		"NUMERIC":   "NUMBER",
+		"REAL":      "FLOAT",
+		"FLOAT":     "FLOAT",
+		
+		// String Types
+		"STRING":    "VARCHAR",
+		"TEXT":      "VARCHAR",
+		"CHAR":      "CHAR",
+		
+		// Boolean
+		"BOOLEAN":   "BOOLEAN",
+		"BOOL":      "BOOLEAN",
+		
+		// Date/Time Types (no time zone, as in PostgreSQL)
+		"TIMESTAMP": "TIMESTAMP_NTZ",
+		"DATETIME":  "TIMESTAMP_NTZ",
+		"DATE":      "DATE",
+		"TIME":      "TIME",
+		
+		// Binary Types
+		"BINARY":    "BINARY",
+		"BLOB":      "BINARY",
+		
+		// JSON Types (semi-structured)
+		"JSON":      "VARIANT",
+		"JSONB":     "VARIANT",
+		
+		// UUID (UUID_STRING() returns a string)
+		"UUID":      "VARCHAR(36)",
+	},
+	
+	"Cassandra": {
+		// No AUTO / BIGAUTO: Cassandra has no sequences, rows are keyed by
+		// a uuid or a client-generated id
+		
+		// Numeric Types
+		"INT":       "int",
+		"BIGINT":    "bigint",
+		"SMALLINT":  "smallint",
+		"DECIMAL":   "decimal",
+		"NUMERIC":   "decimal",
+		"REAL":      "float",
+		"FLOAT":     "double",
+		
+		// String Types (no lengths)
+		"STRING":    "text",
+		"TEXT":      "text",
+		"CHAR":      "text",
+		
+		// Boolean
+		"BOOLEAN":   "boolean",
+		"BOOL":      "boolean",
+		
+		// Date/Time Types (timestamps are UTC milliseconds)
+		"TIMESTAMP": "timestamp",
+		"DATETIME":  "timestamp",
+		"DATE":      "date",
+		"TIME":      "time",
+		
+		// Binary Types
+		"BINARY":    "blob",
+		"BLOB":      "blob",
+		
+		// JSON Types (no JSON type: documents are stored as text)
+		"JSON":      "text",
+		"JSONB":     "text",
+		
+		// UUID
+		"UUID":      "uuid",
+	},
+	
 	"MongoDB": {
 		// MongoDB uses different type system
 		// These map to BSON types
@@ -170,6 +461,125 @@ var TypeMap = map[string]map[string]string{
 	},
 }
 
+// ArrayTypeMap - Storage for TYPE[] columns (e.g., tags:TEXT[])
+// "%s" is replaced with the mapped element type
+var ArrayTypeMap = map[string]string{
+	"PostgreSQL": "%s[]",  // Native arrays
+	"MySQL":      "JSON",  // No array type, store as JSON array
+	"SQLite":     "TEXT",  // JSON text
+	"Oracle":     "JSON",  // No array type, store as JSON array
+	"SQLServer":  "NVARCHAR(MAX)",  // JSON array text
+	"CockroachDB": "%s[]", // Native arrays
+	"ClickHouse": "Array(%s)",
+	"BigQuery":   "ARRAY<%s>",
+	"Snowflake":  "ARRAY",  // Untyped: elements are VARIANT values
+	"Cassandra":  "list<%s>",
+	"MongoDB":    "Array",
+}
+
+// JSONSchemaTypeMap - $jsonSchema bsonType aliases for MongoDB validators
+// Integers accept int and long (drivers pick by size), other numbers any BSON
+// number. AUTO columns are left out: MongoDB keys documents by _id.
+var JSONSchemaTypeMap = map[string][]string{
+	"INT":       {"int", "long"},
+	"BIGINT":    {"int", "long"},
+	"SMALLINT":  {"int", "long"},
+	"DECIMAL":   {"number"},
+	"NUMERIC":   {"number"},
+	"REAL":      {"number"},
+	"FLOAT":     {"number"},
+	"STRING":    {"string"},
+	"TEXT":      {"string"},
+	"CHAR":      {"string"},
+	"BOOLEAN":   {"bool"},
+	"BOOL":      {"bool"},
+	"TIMESTAMP": {"date"},
+	"DATETIME":  {"date"},
+	"DATE":      {"date"},
+	"TIME":      {"string"},
+	"BINARY":    {"binData"},
+	"BLOB":      {"binData"},
+	"JSON":      {"object", "array"},
+	"JSONB":     {"object", "array"},
+	"UUID":      {"binData", "string"},
+}
+
+// IsArrayType checks if a column type has the [] suffix
+func IsArrayType(columnType string) bool {
+	return strings.HasSuffix(columnType, "[]")
+}
+
+// ArrayElementType strips the [] suffix (TEXT[] -> TEXT)
+func ArrayElementType(columnType string) string {
+	return strings.TrimSuffix(columnType, "[]")
+}
+
+// CoreTypes - universal types every database with a type map must map
+// (translator.RegisterDialect checks a dialect's map with ValidateTypeMap)
+var CoreTypes = []string{
+	"AUTO", "BIGAUTO",
+	"INT", "BIGINT", "SMALLINT", "DECIMAL", "NUMERIC", "REAL", "FLOAT",
+	"STRING", "TEXT", "CHAR",
+	"BOOLEAN", "BOOL",
+	"TIMESTAMP", "DATETIME", "DATE", "TIME",
+	"BINARY", "BLOB",
+	"JSON", "JSONB",
+	"UUID",
+}
+
+// RegisterType maps a universal type to a native type of dbType, overriding
+// the default (RegisterType("PostgreSQL", "TEXT", "CITEXT")) or adding a
+// type (RegisterType("PostgreSQL", "MONEY", "NUMERIC(19,4)")). Like the
+// maps it changes, it is not safe for use concurrently with translation.
+func RegisterType(dbType, oqlType, nativeType string) error {
+	types, ok := TypeMap[dbType]
+	if !ok {
+		return fmt.Errorf("%s has no type map", dbType)
+	}
+	oqlType = strings.ToUpper(strings.TrimSpace(oqlType))
+	if oqlType == "" {
+		return fmt.Errorf("%s: type name is empty", dbType)
+	}
+	if strings.ContainsAny(oqlType, "()[]: ") {
+		return fmt.Errorf("%s: type name %q must be a bare name (no size, [] or constraints)", dbType, oqlType)
+	}
+	if strings.TrimSpace(nativeType) == "" {
+		return fmt.Errorf("%s: native type for %s is empty", dbType, oqlType)
+	}
+	types[oqlType] = strings.TrimSpace(nativeType)
+	return nil
+}
+
+// NativeType returns dbType's type for a universal type (STRING -> VARCHAR).
+// Builders pass unmapped types through as native ones (CREATE TYPE names,
+// ENUM(...)); NativeType is for callers that need a mapping to exist.
+func NativeType(dbType, oqlType string) (string, error) {
+	types, ok := TypeMap[dbType]
+	if !ok {
+		return "", fmt.Errorf("%s has no type map", dbType)
+	}
+	native, ok := types[strings.ToUpper(ArrayElementType(oqlType))]
+	if !ok {
+		return "", fmt.Errorf("type %s has no mapping in %s", strings.ToUpper(oqlType), dbType)
+	}
+	return native, nil
+}
+
+// ValidateTypeMap checks that types, the type map of dbType, maps every
+// core type to a non-empty native type
+func ValidateTypeMap(dbType string, types map[string]string) error {
+	var missing []string
+	for _, core := range CoreTypes {
+		if strings.TrimSpace(types[core]) == "" {
+			missing = append(missing, core)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s type map has no mapping for %s", dbType, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // TypeDefinition defines type documentation for each universal type
 type TypeDefinition struct {
 	UniversalType string // The universal type name (e.g., "AUTO", "STRING")
@@ -246,4 +656,13 @@ var TypeDocs = []TypeDefinition{
 		SQLite:        "TEXT",
 		MongoDB:       "Object",
 	},
+	{
+		UniversalType: "TEXT[]",
+		Description:   "Array of values (any element type + [])",
+		Example:       "tags: TEXT[]",
+		PostgreSQL:    "TEXT[]",
+		MySQL:         "JSON",
+		SQLite:        "TEXT",
+		MongoDB:       "Array",
+	},
 }
\ No newline at end of file
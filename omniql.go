@@ -3,10 +3,44 @@ package oql
 import (
 	"strings"
 
+	"github.com/omniql-engine/omniql/engine/lexer"
 	"github.com/omniql-engine/omniql/engine/models"
 	"github.com/omniql-engine/omniql/engine/parser"
+	"github.com/omniql-engine/omniql/engine/translator"
+	"github.com/omniql-engine/omniql/mapping"
 )
 
+// ParseError is returned (possibly wrapped) for any OmniQL syntax error.
+// It carries the byte offset, line, column and offending token.
+// Use errors.As or lexer.AsParseError to extract it.
+type ParseError = lexer.ParseError
+
+// SchemaProvider lets Parse reject unknown entities and columns before the
+// query reaches the database. See ParseWithSchema and Client.SetSchema.
+type SchemaProvider = parser.SchemaProvider
+
+// StaticSchema is a map-backed SchemaProvider: table name -> column names
+type StaticSchema = parser.StaticSchema
+
+// Dialect is a SQL backend shipped as a separate module: its name,
+// operation and type mappings, and a factory for the builder that writes
+// its statements. See RegisterDialect.
+type Dialect = translator.Dialect
+
+// DialectBuilder writes a registered dialect's native statement for a query
+type DialectBuilder = translator.Builder
+
+// ErrNotSupported is returned (possibly wrapped) for an operation, operator
+// or feature the database does not have, before anything is sent to it.
+// Use errors.As to extract it; see mapping.SupportsOperation and friends.
+type ErrNotSupported = mapping.ErrNotSupported
+
+// RegisterDialect makes dialect.Name() a database type that WrapSQL accepts
+// and Translate builds for. Call it from the dialect package's init function.
+func RegisterDialect(dialect Dialect) error {
+	return translator.RegisterDialect(dialect)
+}
+
 // Parse handles OmniQL queries with : prefix
 // Returns:
 //   - query: parsed AST (nil if not OmniQL)
@@ -23,4 +57,32 @@ func Parse(input string) (*models.Query, bool, error) {
 	}
 
 	return query, true, nil
-}
\ No newline at end of file
+}
+
+// ParseWithSchema is Parse with entity/column validation against schema
+// (e.g. "unknown column agee on users"). A nil schema behaves like Parse.
+func ParseWithSchema(input string, schema SchemaProvider) (*models.Query, bool, error) {
+	if !strings.HasPrefix(input, ":") {
+		return nil, false, nil // Not OmniQL, pass through as native
+	}
+
+	query, err := parser.ParseWithSchema(strings.TrimPrefix(input, ":"), schema)
+	if err != nil {
+		return nil, true, err // Is OmniQL, but failed to parse
+	}
+
+	return query, true, nil
+}
+
+// ErrNotRenderable is returned (wrapped) by Render for a query with no OQL syntax
+var ErrNotRenderable = parser.ErrNotRenderable
+
+// Render converts a query back into canonical OQL text with the : prefix,
+// which Parse reads back into the same query
+func Render(query *models.Query) (string, error) {
+	text, err := parser.Render(query)
+	if err != nil {
+		return "", err
+	}
+	return ":" + text, nil
+}
@@ -0,0 +1,37 @@
+package oql
+
+import (
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/translator"
+)
+
+// The page count counts the rows a query returns, not the rows it matches
+func TestPageCountQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`:GET User WHERE active = true PAGE 2 SIZE 10`,
+			`SELECT COUNT(*) FROM users WHERE active = $1`},
+		{`:GET role FROM User WHERE active = true DISTINCT PAGE 2 SIZE 10`,
+			`WITH page_rows AS (SELECT DISTINCT role FROM users WHERE active = $1) SELECT COUNT(*) AS total FROM page_rows`},
+		{`:COUNT * FROM User GROUP BY role ORDER BY role PAGE 1 SIZE 5`,
+			`WITH page_rows AS (SELECT COUNT(*), role FROM users GROUP BY role) SELECT COUNT(*) AS total FROM page_rows`},
+	}
+	for _, tt := range tests {
+		query, _, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		result, err := translator.Translate(pageCountQuery(query), "PostgreSQL", "")
+		if err != nil {
+			t.Errorf("%s: %v", tt.input, err)
+			continue
+		}
+		if got := result.GetRelational().Sql; got != tt.want {
+			t.Errorf("%s: count = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package oql
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/mapping"
+)
+
+// ============================================
+// TRANSACTION RETRIES (serialization failures)
+// ============================================
+
+// DefaultRetries is how many times a CockroachDB client runs a statement
+// again after a retryable error (see SetRetries)
+const DefaultRetries = 5
+
+// retryBackoff is the wait before the first retry; it doubles on each retry
+// up to maxRetryBackoff
+const (
+	retryBackoff    = 10 * time.Millisecond
+	maxRetryBackoff = time.Second
+)
+
+// isRetryable reports whether err aborted a transaction that can simply be
+// run again: SQLSTATE 40001 (serialization failure), which CockroachDB
+// returns as "restart transaction" when transactions contend. pgx, lib/pq
+// and most other drivers expose the code through a SQLState method.
+func isRetryable(err error) bool {
+	var coded interface{ SQLState() string }
+	if errors.As(err, &coded) {
+		return coded.SQLState() == "40001"
+	}
+	return strings.Contains(err.Error(), "restart transaction")
+}
+
+// withRetry runs fn, and again while it fails with a retryable error, up to
+// c.retries more times. A statement outside a transaction is its own
+// transaction, so running it again is safe. Between BEGIN and COMMIT or
+// ROLLBACK a 40001 has aborted the caller's whole transaction, and running
+// the statement again would only fail or run it outside that transaction, so
+// there, and for the transaction control statements themselves, it runs
+// once: only the caller can replay a whole transaction.
+func (c *Client) withRetry(query *models.Query, fn func() error) error {
+	err := fn()
+	if mapping.OperationGroups[query.Operation] == "TCL" {
+		c.trackTransaction(query.Operation, err)
+		return err
+	}
+	if c.inTransaction {
+		return err
+	}
+	backoff := retryBackoff
+	for attempt := 0; attempt < c.retries && err != nil && isRetryable(err); attempt++ {
+		select {
+		case <-c.ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+		err = fn()
+	}
+	return err
+}
+
+// trackTransaction records whether the client is inside an explicit
+// transaction after running a transaction control statement. A COMMIT or
+// ROLLBACK ends it even when it fails, since the database has then rolled
+// it back; ROLLBACK TO a savepoint keeps it open.
+func (c *Client) trackTransaction(operation string, err error) {
+	switch operation {
+	case "BEGIN", "START":
+		c.inTransaction = c.inTransaction || err == nil
+	case "COMMIT", "ROLLBACK":
+		c.inTransaction = false
+	}
+}
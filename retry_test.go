@@ -0,0 +1,46 @@
+package oql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/omniql-engine/omniql/engine/models"
+)
+
+// A serialization failure is retried on its own, but not between BEGIN and
+// COMMIT or ROLLBACK, where it has aborted the whole transaction
+func TestWithRetryInTransaction(t *testing.T) {
+	c := &Client{ctx: context.Background(), retries: 2}
+	restart := errors.New("restart transaction: TransactionRetryWithProtoRefreshError")
+	runs := func(operation string, err error) int {
+		n := 0
+		c.withRetry(&models.Query{Operation: operation}, func() error {
+			n++
+			return err
+		})
+		return n
+	}
+	steps := []struct {
+		operation string
+		err       error
+		want      int
+	}{
+		{"UPDATE", restart, 3},
+		{"BEGIN", nil, 1},
+		{"UPDATE", restart, 1},
+		{"ROLLBACK TO", nil, 1},
+		{"UPDATE", restart, 1},
+		{"COMMIT", restart, 1},
+		{"UPDATE", restart, 3},
+		{"START", nil, 1},
+		{"UPDATE", restart, 1},
+		{"ROLLBACK", nil, 1},
+		{"UPDATE", restart, 3},
+	}
+	for i, step := range steps {
+		if got := runs(step.operation, step.err); got != step.want {
+			t.Errorf("step %d: %s ran %d times, want %d", i, step.operation, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package oql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/engine/reverse"
+	"github.com/omniql-engine/omniql/engine/translator"
+)
+
+// ============================================
+// ROUND TRIP (OQL → native → OQL)
+// ============================================
+
+// RoundTripResult is an OQL query checked against its round trip: the native
+// query it translates to, and that query translated back
+type RoundTripResult struct {
+	Native   string
+	Original *models.Query
+	Reversed *models.Query
+	Diffs    []RoundTripDiff
+}
+
+// RoundTripDiff is a field that differs between the two queries, e.g.
+// Conditions[0].ValueExpr.Value, with each side's value as JSON
+type RoundTripDiff struct {
+	Path     string
+	Original string
+	Reversed string
+}
+
+// Equivalent reports whether the round trip gave back the same query
+func (r *RoundTripResult) Equivalent() bool {
+	return len(r.Diffs) == 0
+}
+
+// RoundTrip translates an OQL query to dbType, reverse translates the native
+// query, and reports where the two models.Query trees differ:
+//
+//	result, err := oql.RoundTrip(":GET User WHERE age > 21", "MongoDB", "tenant_1")
+//	for _, diff := range result.Diffs {
+//	    fmt.Printf("%s: %s != %s\n", diff.Path, diff.Original, diff.Reversed)
+//	}
+//
+// Differences no translation can keep are not reported: literals compare by
+// value whatever their type (NUMBER, STRING and BOOLEAN come back as
+// LITERAL), a SQL parameter ($1, ?) stands for any literal, GET's implicit
+// * column equals no columns, GET User WITH id equals GET id FROM User, an
+// UPSERT taking EXCLUDED.col for col equals the inferred update, fields
+// compare in name order, which a document does not keep, and on Redis, whose
+// HMSET always overwrites, an UPSERT on id that updates the inserted fields
+// equals CREATE.
+func RoundTrip(input, dbType, tenantID string) (*RoundTripResult, error) {
+	query, isOQL, err := Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+	if !isOQL {
+		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
+	}
+
+	translated, err := translator.Translate(query, dbType, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("translation error: %w", err)
+	}
+
+	var native string
+	switch {
+	case translated.GetRelational() != nil:
+		native = translated.GetRelational().Sql
+	case translated.GetDocument() != nil:
+		native = translated.GetDocument().Query
+	case translated.GetKeyValue() != nil:
+		native = translated.GetKeyValue().CommandString
+	}
+
+	reversed, err := reverse.ToQuery(native, dbType)
+	if err != nil {
+		return nil, fmt.Errorf("reverse translation error: %w", err)
+	}
+
+	result := &RoundTripResult{Native: native, Original: query, Reversed: reversed}
+	original, back := normalizeRoundTrip(query, dbType), normalizeRoundTrip(reversed, dbType)
+	result.Diffs = diffValues("", reflect.ValueOf(original), reflect.ValueOf(back), nil)
+	return result, nil
+}
+
+// normalizeRoundTrip returns a copy of q spelling one way what OQL spells
+// several ways: plain selected fields are columns, * is no columns, an
+// update to a column's own inserted value has no value, and fields are
+// sorted by name. On Redis an UPSERT on id that updates the inserted fields
+// is a CREATE. q is left untouched.
+func normalizeRoundTrip(q *models.Query, dbType string) *models.Query {
+	normalized := *q
+	if columns, ok := plainSelectColumns(q.SelectColumns); ok {
+		normalized.Columns, normalized.SelectColumns = columns, nil
+	}
+	if isStarColumns(reflect.ValueOf(normalized.Columns)) {
+		normalized.Columns = nil
+	}
+	normalized.Fields = sortedFields(q.Fields)
+	if q.BulkData != nil {
+		normalized.BulkData = make([][]models.Field, len(q.BulkData))
+		for i, row := range q.BulkData {
+			normalized.BulkData[i] = sortedFields(row)
+		}
+	}
+	if q.Upsert != nil {
+		upsert := *q.Upsert
+		upsert.UpdateFields = nil
+		for _, f := range q.Upsert.UpdateFields {
+			if f.NameExpr != nil && f.ValueExpr != nil && f.ValueExpr.Type == "FIELD" &&
+				strings.EqualFold(f.ValueExpr.Value, "EXCLUDED."+f.NameExpr.Value) {
+				f.ValueExpr = nil
+			}
+			upsert.UpdateFields = append(upsert.UpdateFields, f)
+		}
+		upsert.UpdateFields = sortedFields(upsert.UpdateFields)
+		normalized.Upsert = &upsert
+		if dbType == "Redis" && isInsertedUpdateOnID(normalized.Fields, &upsert) {
+			normalized.Operation, normalized.Upsert = "CREATE", nil
+		}
+	}
+	return &normalized
+}
+
+// isInsertedUpdateOnID reports whether upsert conflicts on id alone and
+// updates every other inserted field to its inserted value
+func isInsertedUpdateOnID(fields []models.Field, upsert *models.Upsert) bool {
+	if len(upsert.ConflictFields) != 1 || upsert.ConflictFields[0].Value != "id" || upsert.DoNothing {
+		return false
+	}
+	var names []string
+	for _, f := range fields {
+		if name := fieldName(f); name != "id" {
+			names = append(names, name)
+		}
+	}
+	if len(upsert.UpdateFields) != len(names) {
+		return false
+	}
+	for i, f := range upsert.UpdateFields {
+		if f.ValueExpr != nil || fieldName(f) != names[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// plainSelectColumns returns the selected columns as column names when all
+// of them are unaliased fields
+func plainSelectColumns(selected []models.SelectColumn) ([]*models.Expression, bool) {
+	if len(selected) == 0 {
+		return nil, false
+	}
+	columns := make([]*models.Expression, len(selected))
+	for i, col := range selected {
+		if col.Alias != "" || col.ExpressionObj == nil || col.ExpressionObj.Type != "FIELD" {
+			return nil, false
+		}
+		columns[i] = col.ExpressionObj
+	}
+	return columns, true
+}
+
+// sortedFields returns a copy of fields sorted by name
+func sortedFields(fields []models.Field) []models.Field {
+	if fields == nil {
+		return nil
+	}
+	sorted := append([]models.Field(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return fieldName(sorted[i]) < fieldName(sorted[j])
+	})
+	return sorted
+}
+
+func fieldName(f models.Field) string {
+	if f.NameExpr == nil {
+		return ""
+	}
+	return f.NameExpr.Value
+}
+
+// roundTripLiteralTypes are the expression types that compare by value alone
+var roundTripLiteralTypes = map[string]bool{
+	"LITERAL": true,
+	"NUMBER":  true,
+	"STRING":  true,
+	"BOOLEAN": true,
+}
+
+// roundTripParameter matches the placeholders SQL carries literals in
+var roundTripParameter = regexp.MustCompile(`^(\$\d+|\?)$`)
+
+var expressionType = reflect.TypeOf(models.Expression{})
+
+// diffValues walks two values of the same type and appends a diff for every
+// leaf that differs; a pointer, slice element or map entry missing on one
+// side is one diff holding the other side whole
+func diffValues(path string, a, b reflect.Value, diffs []RoundTripDiff) []RoundTripDiff {
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				diffs = append(diffs, newRoundTripDiff(path, a, b))
+			}
+			return diffs
+		}
+		return diffValues(path, a.Elem(), b.Elem(), diffs)
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() || skipRoundTripField(a, b, field.Name) {
+				continue
+			}
+			diffs = diffValues(joinPath(path, field.Name), a.Field(i), b.Field(i), diffs)
+		}
+		return diffs
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= a.Len() || i >= b.Len() {
+				diffs = append(diffs, newRoundTripDiff(elemPath, sliceIndex(a, i), sliceIndex(b, i)))
+				continue
+			}
+			diffs = diffValues(elemPath, a.Index(i), b.Index(i), diffs)
+		}
+		return diffs
+
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, key := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(key.Interface())] = key
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			elemPath := fmt.Sprintf("%s[%s]", path, name)
+			av, bv := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			if !av.IsValid() || !bv.IsValid() {
+				diffs = append(diffs, newRoundTripDiff(elemPath, av, bv))
+				continue
+			}
+			diffs = diffValues(elemPath, av, bv, diffs)
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		diffs = append(diffs, newRoundTripDiff(path, a, b))
+	}
+	return diffs
+}
+
+// skipRoundTripField reports whether a field's difference is one RoundTrip
+// ignores: the type of two literals, or a literal's value against a parameter
+func skipRoundTripField(a, b reflect.Value, name string) bool {
+	switch {
+	case a.Type() == expressionType && name == "Type":
+		return roundTripLiteralTypes[a.FieldByName(name).String()] && roundTripLiteralTypes[b.FieldByName(name).String()]
+	case a.Type() == expressionType && name == "Value":
+		return roundTripLiteralTypes[a.FieldByName("Type").String()] && roundTripParameter.MatchString(b.FieldByName(name).String())
+	}
+	return false
+}
+
+// isStarColumns reports whether columns are none or just *
+func isStarColumns(columns reflect.Value) bool {
+	if columns.Len() == 0 {
+		return true
+	}
+	if columns.Len() > 1 {
+		return false
+	}
+	column, ok := columns.Index(0).Interface().(*models.Expression)
+	return ok && column != nil && column.Type == "FIELD" && column.Value == "*"
+}
+
+func sliceIndex(v reflect.Value, i int) reflect.Value {
+	if i < v.Len() {
+		return v.Index(i)
+	}
+	return reflect.Value{}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func newRoundTripDiff(path string, a, b reflect.Value) RoundTripDiff {
+	return RoundTripDiff{Path: path, Original: describeValue(a), Reversed: describeValue(b)}
+}
+
+// describeValue renders one side of a diff as JSON; a missing value is "-"
+func describeValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "-"
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
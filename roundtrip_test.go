@@ -0,0 +1,70 @@
+package oql
+
+import "testing"
+
+// Simple queries come back equivalent from every reversible database
+func TestRoundTripEquivalent(t *testing.T) {
+	queries := map[string][]string{
+		"PostgreSQL": {
+			`:GET User WHERE age > 21 ORDER BY name LIMIT 5`,
+			`:GET User WITH id, name`,
+			`:GET id, name FROM User WHERE id = 1`,
+			`:CREATE User WITH name = "Alice", age = 30`,
+			`:UPDATE User SET name = "Bob" WHERE id = 1`,
+			`:UPSERT User WITH email = "a", name = "b" ON email`,
+			`:UPSERT Page WITH id = 1, hits = 1 ON id UPDATE SET hits = hits + 1`,
+		},
+		"MySQL": {
+			`:GET User WHERE age > 21 ORDER BY name LIMIT 5`,
+			`:GET User WITH id, name`,
+			`:CREATE User WITH name = "Alice", age = 30`,
+			`:UPDATE User SET name = "Bob" WHERE id = 1`,
+			`:UPSERT User WITH email = "a", name = "b" ON email`,
+			`:UPSERT Page WITH id = 1, hits = 1 ON id UPDATE SET hits = hits + 1`,
+		},
+		"SQLite": {
+			`:GET User WHERE age > 21 ORDER BY name LIMIT 5`,
+			`:GET User WITH id, name`,
+			`:CREATE User WITH name = "Alice", age = 30`,
+			`:UPDATE User SET name = "Bob" WHERE id = 1`,
+			`:UPSERT User WITH email = "a", name = "b" ON email`,
+		},
+		"MongoDB": {
+			`:GET User WHERE age > 21 ORDER BY name LIMIT 5`,
+			`:GET User WITH id, name`,
+			`:GET id, name FROM User WHERE id = 1`,
+			`:CREATE User WITH name = "Alice", age = 30`,
+			`:UPDATE User SET name = "Bob" WHERE id = 1`,
+			`:UPSERT User WITH email = "a", name = "b" ON email`,
+		},
+		"Redis": {
+			`:GET User WHERE id = 1`,
+			`:CREATE User WITH id = 1, name = "Alice"`,
+			`:UPDATE User SET name = "Bob" WHERE id = 1`,
+			`:UPSERT User WITH id = 1, name = "b" ON id`,
+		},
+	}
+	for dbType, inputs := range queries {
+		for _, input := range inputs {
+			result, err := RoundTrip(input, dbType, "tenant_1")
+			if err != nil {
+				t.Errorf("%s %s: %v", dbType, input, err)
+				continue
+			}
+			if !result.Equivalent() {
+				t.Errorf("%s %s: %s came back with differences %+v", dbType, input, result.Native, result.Diffs)
+			}
+		}
+	}
+}
+
+// A difference the native query really loses is still reported
+func TestRoundTripReportsLoss(t *testing.T) {
+	result, err := RoundTrip(`:GET User WHERE age > 21`, "Redis", "tenant_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Equivalent() {
+		t.Errorf("expected the Redis scan %s to lose the WHERE", result.Native)
+	}
+}
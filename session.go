@@ -0,0 +1,45 @@
+package oql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sfbuilders "github.com/omniql-engine/omniql/engine/builders/snowflake"
+)
+
+// ============================================
+// SESSION SETTINGS (Snowflake warehouse and parameters)
+// ============================================
+
+// sqlRunner runs a statement: the pool itself, or one connection from it
+type sqlRunner interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// session returns where to run the next statement and how to give it back.
+// A warehouse and session parameters belong to a connection, not the pool,
+// so with either set on a Snowflake client the statement takes a connection
+// and runs USE WAREHOUSE / ALTER SESSION SET on it first. Putting the
+// warehouse in the DSN instead saves that round trip.
+func (c *Client) session() (sqlRunner, func() error, error) {
+	if c.dbType != "Snowflake" || (c.warehouse == "" && len(c.sessionParams) == 0) {
+		return c.sqlDB, func() error { return nil }, nil
+	}
+	statements, err := sfbuilders.BuildSessionSQL(c.warehouse, c.sessionParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("session error: %w", err)
+	}
+	conn, err := c.sqlDB.Conn(c.ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("session error: %w", err)
+	}
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(c.ctx, statement); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("session error: %w", err)
+		}
+	}
+	return conn, conn.Close, nil
+}
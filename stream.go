@@ -0,0 +1,181 @@
+package oql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/omniql-engine/omniql/engine/models"
+	"github.com/omniql-engine/omniql/engine/translator"
+	"github.com/omniql-engine/omniql/mapping"
+)
+
+// ============================================
+// STREAMING (row-at-a-time results)
+// ============================================
+
+// RowStream reads the rows of a query one at a time, like database/sql's Rows:
+//
+//	stream, err := client.QueryStream(":GET Event WHERE kind = \"click\"")
+//	if err != nil { ... }
+//	defer stream.Close()
+//	for stream.Next() {
+//	    row := stream.Row()
+//	}
+//	err = stream.Err()
+type RowStream struct {
+	next  func() (map[string]any, error) // nil row: no more rows
+	close func() error
+	row   map[string]any
+	err   error
+	done  bool
+}
+
+// Next advances to the next row; it returns false after the last row or on an error
+func (s *RowStream) Next() bool {
+	if s.done {
+		return false
+	}
+	row, err := s.next()
+	if err != nil || row == nil {
+		s.err = err
+		s.Close()
+		return false
+	}
+	s.row = row
+	return true
+}
+
+// Row returns the current row
+func (s *RowStream) Row() map[string]any {
+	return s.row
+}
+
+// Err returns the error that ended the stream, if any
+func (s *RowStream) Err() error {
+	return s.err
+}
+
+// Close releases the stream; it is called by Next after the last row
+func (s *RowStream) Close() error {
+	if s.done {
+		return nil
+	}
+	s.done = true
+	s.row = nil
+	if s.close != nil {
+		return s.close()
+	}
+	return nil
+}
+
+// sliceStream streams rows already read in full
+func sliceStream(rows []map[string]any) *RowStream {
+	i := 0
+	return &RowStream{next: func() (map[string]any, error) {
+		if i >= len(rows) {
+			return nil, nil
+		}
+		i++
+		return rows[i-1], nil
+	}}
+}
+
+// QueryStream executes a query and returns its rows as a stream. SQL reads
+// the rows off the connection as Next asks for them, and a Redis GET over a
+// pattern walks the keys with SCAN, fetching each record only when it is
+// reached. Other queries run in full and stream the rows they returned.
+func (c *Client) QueryStream(input string) (*RowStream, error) {
+	query, isOQL, err := ParseWithSchema(input, c.schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if !isOQL {
+		return nil, fmt.Errorf("OmniQL syntax required: queries must start with ':'")
+	}
+
+	switch c.dbType {
+	case "PostgreSQL", "MySQL", "SQLite", "Oracle", "SQLServer", "CockroachDB", "ClickHouse", "BigQuery", "Snowflake", "Cassandra":
+		if query.Operation == "GET" {
+			return c.resultStream(c.sqlStream(query))
+		}
+	case "Redis":
+		result, err := translator.TranslateWithOptions(query, c.dbType, c.tenantID, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("translation error: %w", err)
+		}
+		kvQuery := result.GetKeyValue()
+		if strings.ToUpper(kvQuery.Command) == "HGETALL" && strings.Contains(kvQuery.Key, "*") && len(kvQuery.OrderBy) == 0 {
+			return c.resultStream(c.redisStream(kvQuery))
+		}
+	default:
+		if translator.IsDialect(c.dbType) && query.Operation == "GET" {
+			return c.resultStream(c.sqlStream(query))
+		}
+	}
+
+	rows, err := c.execute(query)
+	if err != nil {
+		return nil, err
+	}
+	return sliceStream(rows), nil
+}
+
+// resultStream renames the columns of each streamed row to OQL field names
+// (see resultFields)
+func (c *Client) resultStream(stream *RowStream, err error) (*RowStream, error) {
+	if err != nil || mapping.ColumnNaming[c.dbType] == mapping.AsWritten {
+		return stream, err
+	}
+	next := stream.next
+	stream.next = func() (map[string]any, error) {
+		row, err := next()
+		if row == nil {
+			return row, err
+		}
+		return c.resultFields([]map[string]any{row})[0], err
+	}
+	return stream, nil
+}
+
+// sqlStream runs a SELECT and scans each row when it is asked for
+func (c *Client) sqlStream(query *models.Query) (*RowStream, error) {
+	result, err := translator.TranslateWithOptions(query, c.dbType, c.tenantID, c.options)
+	if err != nil {
+		return nil, fmt.Errorf("translation error: %w", err)
+	}
+
+	runner, release, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+	var rows *sql.Rows
+	err = c.withRetry(query, func() error {
+		rows, err = runner.QueryContext(c.ctx, result.GetRelational().Sql)
+		return err
+	})
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		release()
+		return nil, err
+	}
+	return &RowStream{
+		next: func() (map[string]any, error) {
+			if !rows.Next() {
+				return nil, rows.Err()
+			}
+			return scanRow(rows, columns)
+		},
+		close: func() error {
+			err := rows.Close()
+			release()
+			return err
+		},
+	}, nil
+}
@@ -121,7 +121,7 @@ func (*UniversalQuery_KeyValue) isUniversalQuery_QueryType() {}
 
 type Expression struct {
 	state    protoimpl.MessageState `protogen:"open.v1"`
-	Type     string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // BINARY, FUNCTION, CASEWHEN, FIELD, LITERAL, WINDOW
+	Type     string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // BINARY, FUNCTION, CASEWHEN, FIELD, LITERAL, WINDOW, JSON_PATH
 	Position int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
 	// For leaf nodes (FIELD, LITERAL)
 	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
@@ -405,6 +405,7 @@ type QueryField struct {
 	ValueExpr     *Expression            `protobuf:"bytes,2,opt,name=value_expr,json=valueExpr,proto3" json:"value_expr,omitempty"` // Field value
 	Constraints   []string               `protobuf:"bytes,3,rep,name=constraints,proto3" json:"constraints,omitempty"`              // DDL: UNIQUE, NOT_NULL, PRIMARY_KEY
 	Position      int32                  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	GeneratedExpr *Expression            `protobuf:"bytes,5,opt,name=generated_expr,json=generatedExpr,proto3" json:"generated_expr,omitempty"` // DDL: GENERATED ALWAYS AS (expr) STORED
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -467,6 +468,13 @@ func (x *QueryField) GetPosition() int32 {
 	return 0
 }
 
+func (x *QueryField) GetGeneratedExpr() *Expression {
+	if x != nil {
+		return x.GeneratedExpr
+	}
+	return nil
+}
+
 type SelectColumn struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ExpressionObj *Expression            `protobuf:"bytes,1,opt,name=expression_obj,json=expressionObj,proto3" json:"expression_obj,omitempty"` // The expression
@@ -568,49 +576,93 @@ type RelationalQuery struct {
 	Sql              string                 `protobuf:"bytes,37,opt,name=sql,proto3" json:"sql,omitempty"`
 	AlterAction      string                 `protobuf:"bytes,38,opt,name=alter_action,json=alterAction,proto3" json:"alter_action,omitempty"` // ADD_COLUMN, DROP_COLUMN, RENAME_COLUMN
 	// PostgreSQL-specific DDL
-	SequenceName      string   `protobuf:"bytes,39,opt,name=sequence_name,json=sequenceName,proto3" json:"sequence_name,omitempty"`
-	SequenceStart     int64    `protobuf:"varint,40,opt,name=sequence_start,json=sequenceStart,proto3" json:"sequence_start,omitempty"`
-	SequenceIncrement int64    `protobuf:"varint,41,opt,name=sequence_increment,json=sequenceIncrement,proto3" json:"sequence_increment,omitempty"`
-	SequenceMin       int64    `protobuf:"varint,42,opt,name=sequence_min,json=sequenceMin,proto3" json:"sequence_min,omitempty"`
-	SequenceMax       int64    `protobuf:"varint,43,opt,name=sequence_max,json=sequenceMax,proto3" json:"sequence_max,omitempty"`
-	SequenceCache     int64    `protobuf:"varint,44,opt,name=sequence_cache,json=sequenceCache,proto3" json:"sequence_cache,omitempty"`
-	SequenceCycle     bool     `protobuf:"varint,45,opt,name=sequence_cycle,json=sequenceCycle,proto3" json:"sequence_cycle,omitempty"`
-	SequenceRestart   int64    `protobuf:"varint,46,opt,name=sequence_restart,json=sequenceRestart,proto3" json:"sequence_restart,omitempty"`
-	ExtensionName     string   `protobuf:"bytes,47,opt,name=extension_name,json=extensionName,proto3" json:"extension_name,omitempty"`
-	SchemaName        string   `protobuf:"bytes,48,opt,name=schema_name,json=schemaName,proto3" json:"schema_name,omitempty"`
-	SchemaOwner       string   `protobuf:"bytes,49,opt,name=schema_owner,json=schemaOwner,proto3" json:"schema_owner,omitempty"`
-	TypeName          string   `protobuf:"bytes,50,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
-	TypeKind          string   `protobuf:"bytes,51,opt,name=type_kind,json=typeKind,proto3" json:"type_kind,omitempty"`
-	EnumValues        []string `protobuf:"bytes,52,rep,name=enum_values,json=enumValues,proto3" json:"enum_values,omitempty"`
-	EnumValue         string   `protobuf:"bytes,53,opt,name=enum_value,json=enumValue,proto3" json:"enum_value,omitempty"`
-	NewEnumValue      string   `protobuf:"bytes,54,opt,name=new_enum_value,json=newEnumValue,proto3" json:"new_enum_value,omitempty"`
-	DomainName        string   `protobuf:"bytes,55,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
-	DomainType        string   `protobuf:"bytes,56,opt,name=domain_type,json=domainType,proto3" json:"domain_type,omitempty"`
-	DomainDefault     string   `protobuf:"bytes,57,opt,name=domain_default,json=domainDefault,proto3" json:"domain_default,omitempty"`
-	DomainConstraint  string   `protobuf:"bytes,58,opt,name=domain_constraint,json=domainConstraint,proto3" json:"domain_constraint,omitempty"`
-	FuncName          string   `protobuf:"bytes,59,opt,name=func_name,json=funcName,proto3" json:"func_name,omitempty"`
-	FuncBody          string   `protobuf:"bytes,60,opt,name=func_body,json=funcBody,proto3" json:"func_body,omitempty"`
-	FuncArgs          []string `protobuf:"bytes,61,rep,name=func_args,json=funcArgs,proto3" json:"func_args,omitempty"`
-	FuncReturns       string   `protobuf:"bytes,62,opt,name=func_returns,json=funcReturns,proto3" json:"func_returns,omitempty"`
-	FuncLanguage      string   `protobuf:"bytes,63,opt,name=func_language,json=funcLanguage,proto3" json:"func_language,omitempty"`
-	FuncOwner         string   `protobuf:"bytes,64,opt,name=func_owner,json=funcOwner,proto3" json:"func_owner,omitempty"`
-	TriggerName       string   `protobuf:"bytes,65,opt,name=trigger_name,json=triggerName,proto3" json:"trigger_name,omitempty"`
-	TriggerTiming     string   `protobuf:"bytes,66,opt,name=trigger_timing,json=triggerTiming,proto3" json:"trigger_timing,omitempty"`
-	TriggerEvents     string   `protobuf:"bytes,67,opt,name=trigger_events,json=triggerEvents,proto3" json:"trigger_events,omitempty"`
-	TriggerForEach    string   `protobuf:"bytes,68,opt,name=trigger_for_each,json=triggerForEach,proto3" json:"trigger_for_each,omitempty"`
-	PolicyName        string   `protobuf:"bytes,69,opt,name=policy_name,json=policyName,proto3" json:"policy_name,omitempty"`
-	PolicyFor         string   `protobuf:"bytes,70,opt,name=policy_for,json=policyFor,proto3" json:"policy_for,omitempty"`
-	PolicyTo          string   `protobuf:"bytes,71,opt,name=policy_to,json=policyTo,proto3" json:"policy_to,omitempty"`
-	PolicyUsing       string   `protobuf:"bytes,72,opt,name=policy_using,json=policyUsing,proto3" json:"policy_using,omitempty"`
-	PolicyCheck       string   `protobuf:"bytes,73,opt,name=policy_check,json=policyCheck,proto3" json:"policy_check,omitempty"`
-	RuleName          string   `protobuf:"bytes,74,opt,name=rule_name,json=ruleName,proto3" json:"rule_name,omitempty"`
-	RuleEvent         string   `protobuf:"bytes,75,opt,name=rule_event,json=ruleEvent,proto3" json:"rule_event,omitempty"`
-	RuleAction        string   `protobuf:"bytes,76,opt,name=rule_action,json=ruleAction,proto3" json:"rule_action,omitempty"`
-	CommentTarget     string   `protobuf:"bytes,77,opt,name=comment_target,json=commentTarget,proto3" json:"comment_target,omitempty"`
-	CommentText       string   `protobuf:"bytes,78,opt,name=comment_text,json=commentText,proto3" json:"comment_text,omitempty"`
-	Cascade           bool     `protobuf:"varint,79,opt,name=cascade,proto3" json:"cascade,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	SequenceName      string        `protobuf:"bytes,39,opt,name=sequence_name,json=sequenceName,proto3" json:"sequence_name,omitempty"`
+	SequenceStart     int64         `protobuf:"varint,40,opt,name=sequence_start,json=sequenceStart,proto3" json:"sequence_start,omitempty"`
+	SequenceIncrement int64         `protobuf:"varint,41,opt,name=sequence_increment,json=sequenceIncrement,proto3" json:"sequence_increment,omitempty"`
+	SequenceMin       int64         `protobuf:"varint,42,opt,name=sequence_min,json=sequenceMin,proto3" json:"sequence_min,omitempty"`
+	SequenceMax       int64         `protobuf:"varint,43,opt,name=sequence_max,json=sequenceMax,proto3" json:"sequence_max,omitempty"`
+	SequenceCache     int64         `protobuf:"varint,44,opt,name=sequence_cache,json=sequenceCache,proto3" json:"sequence_cache,omitempty"`
+	SequenceCycle     bool          `protobuf:"varint,45,opt,name=sequence_cycle,json=sequenceCycle,proto3" json:"sequence_cycle,omitempty"`
+	SequenceRestart   int64         `protobuf:"varint,46,opt,name=sequence_restart,json=sequenceRestart,proto3" json:"sequence_restart,omitempty"`
+	ExtensionName     string        `protobuf:"bytes,47,opt,name=extension_name,json=extensionName,proto3" json:"extension_name,omitempty"`
+	SchemaName        string        `protobuf:"bytes,48,opt,name=schema_name,json=schemaName,proto3" json:"schema_name,omitempty"`
+	SchemaOwner       string        `protobuf:"bytes,49,opt,name=schema_owner,json=schemaOwner,proto3" json:"schema_owner,omitempty"`
+	TypeName          string        `protobuf:"bytes,50,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
+	TypeKind          string        `protobuf:"bytes,51,opt,name=type_kind,json=typeKind,proto3" json:"type_kind,omitempty"`
+	EnumValues        []string      `protobuf:"bytes,52,rep,name=enum_values,json=enumValues,proto3" json:"enum_values,omitempty"`
+	EnumValue         string        `protobuf:"bytes,53,opt,name=enum_value,json=enumValue,proto3" json:"enum_value,omitempty"`
+	NewEnumValue      string        `protobuf:"bytes,54,opt,name=new_enum_value,json=newEnumValue,proto3" json:"new_enum_value,omitempty"`
+	DomainName        string        `protobuf:"bytes,55,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	DomainType        string        `protobuf:"bytes,56,opt,name=domain_type,json=domainType,proto3" json:"domain_type,omitempty"`
+	DomainDefault     string        `protobuf:"bytes,57,opt,name=domain_default,json=domainDefault,proto3" json:"domain_default,omitempty"`
+	DomainConstraint  string        `protobuf:"bytes,58,opt,name=domain_constraint,json=domainConstraint,proto3" json:"domain_constraint,omitempty"`
+	FuncName          string        `protobuf:"bytes,59,opt,name=func_name,json=funcName,proto3" json:"func_name,omitempty"`
+	FuncBody          string        `protobuf:"bytes,60,opt,name=func_body,json=funcBody,proto3" json:"func_body,omitempty"`
+	FuncArgs          []string      `protobuf:"bytes,61,rep,name=func_args,json=funcArgs,proto3" json:"func_args,omitempty"`
+	FuncReturns       string        `protobuf:"bytes,62,opt,name=func_returns,json=funcReturns,proto3" json:"func_returns,omitempty"`
+	FuncLanguage      string        `protobuf:"bytes,63,opt,name=func_language,json=funcLanguage,proto3" json:"func_language,omitempty"`
+	FuncOwner         string        `protobuf:"bytes,64,opt,name=func_owner,json=funcOwner,proto3" json:"func_owner,omitempty"`
+	TriggerName       string        `protobuf:"bytes,65,opt,name=trigger_name,json=triggerName,proto3" json:"trigger_name,omitempty"`
+	TriggerTiming     string        `protobuf:"bytes,66,opt,name=trigger_timing,json=triggerTiming,proto3" json:"trigger_timing,omitempty"`
+	TriggerEvents     string        `protobuf:"bytes,67,opt,name=trigger_events,json=triggerEvents,proto3" json:"trigger_events,omitempty"`
+	TriggerForEach    string        `protobuf:"bytes,68,opt,name=trigger_for_each,json=triggerForEach,proto3" json:"trigger_for_each,omitempty"`
+	PolicyName        string        `protobuf:"bytes,69,opt,name=policy_name,json=policyName,proto3" json:"policy_name,omitempty"`
+	PolicyFor         string        `protobuf:"bytes,70,opt,name=policy_for,json=policyFor,proto3" json:"policy_for,omitempty"`
+	PolicyTo          string        `protobuf:"bytes,71,opt,name=policy_to,json=policyTo,proto3" json:"policy_to,omitempty"`
+	PolicyUsing       string        `protobuf:"bytes,72,opt,name=policy_using,json=policyUsing,proto3" json:"policy_using,omitempty"`
+	PolicyCheck       string        `protobuf:"bytes,73,opt,name=policy_check,json=policyCheck,proto3" json:"policy_check,omitempty"`
+	RuleName          string        `protobuf:"bytes,74,opt,name=rule_name,json=ruleName,proto3" json:"rule_name,omitempty"`
+	RuleEvent         string        `protobuf:"bytes,75,opt,name=rule_event,json=ruleEvent,proto3" json:"rule_event,omitempty"`
+	RuleAction        string        `protobuf:"bytes,76,opt,name=rule_action,json=ruleAction,proto3" json:"rule_action,omitempty"`
+	CommentTarget     string        `protobuf:"bytes,77,opt,name=comment_target,json=commentTarget,proto3" json:"comment_target,omitempty"`
+	CommentText       string        `protobuf:"bytes,78,opt,name=comment_text,json=commentText,proto3" json:"comment_text,omitempty"`
+	Cascade           bool          `protobuf:"varint,79,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	Returning         []*Expression `protobuf:"bytes,80,rep,name=returning,proto3" json:"returning,omitempty"` // RETURNING columns (INSERT/UPDATE/DELETE) - 100% TrueAST
+	// PostgreSQL partitioning
+	PartitionStrategy  string        `protobuf:"bytes,81,opt,name=partition_strategy,json=partitionStrategy,proto3" json:"partition_strategy,omitempty"`     // RANGE, LIST, HASH (CREATE TABLE ... PARTITION BY); BigQuery: DAY, HOUR, MONTH, YEAR
+	PartitionKeys      []*Expression `protobuf:"bytes,82,rep,name=partition_keys,json=partitionKeys,proto3" json:"partition_keys,omitempty"`                 // Partition key columns - 100% TrueAST
+	PartitionName      string        `protobuf:"bytes,83,opt,name=partition_name,json=partitionName,proto3" json:"partition_name,omitempty"`                 // CREATE PARTITION name OF parent
+	PartitionFrom      []*Expression `protobuf:"bytes,84,rep,name=partition_from,json=partitionFrom,proto3" json:"partition_from,omitempty"`                 // FOR VALUES FROM (...)
+	PartitionTo        []*Expression `protobuf:"bytes,85,rep,name=partition_to,json=partitionTo,proto3" json:"partition_to,omitempty"`                       // FOR VALUES ... TO (...)
+	PartitionIn        []*Expression `protobuf:"bytes,86,rep,name=partition_in,json=partitionIn,proto3" json:"partition_in,omitempty"`                       // FOR VALUES IN (...)
+	PartitionModulus   int64         `protobuf:"varint,87,opt,name=partition_modulus,json=partitionModulus,proto3" json:"partition_modulus,omitempty"`       // FOR VALUES WITH (MODULUS m, ...)
+	PartitionRemainder int64         `protobuf:"varint,88,opt,name=partition_remainder,json=partitionRemainder,proto3" json:"partition_remainder,omitempty"` // FOR VALUES WITH (..., REMAINDER r)
+	PartitionDefault   bool          `protobuf:"varint,89,opt,name=partition_default,json=partitionDefault,proto3" json:"partition_default,omitempty"`       // DEFAULT partition
+	// DCL object kind
+	PermissionObject string `protobuf:"bytes,90,opt,name=permission_object,json=permissionObject,proto3" json:"permission_object,omitempty"` // DATABASE, SCHEMA, SEQUENCE, FUNCTION, ALL TABLES IN SCHEMA (empty = table)
+	// Role attributes (CREATE/ALTER ROLE, CREATE/ALTER USER)
+	RoleFlags       []string `protobuf:"bytes,91,rep,name=role_flags,json=roleFlags,proto3" json:"role_flags,omitempty"`                   // LOGIN, NOLOGIN, SUPERUSER, CREATEDB, ...
+	ValidUntil      string   `protobuf:"bytes,92,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`                // VALID UNTIL timestamp
+	ConnectionLimit string   `protobuf:"bytes,93,opt,name=connection_limit,json=connectionLimit,proto3" json:"connection_limit,omitempty"` // CONNECTION LIMIT n (empty = unset)
+	// LISTEN/UNLISTEN/NOTIFY
+	Channel string `protobuf:"bytes,94,opt,name=channel,proto3" json:"channel,omitempty"` // Channel name (* = all for UNLISTEN)
+	Payload string `protobuf:"bytes,95,opt,name=payload,proto3" json:"payload,omitempty"` // NOTIFY payload
+	// MySQL account host ('name'@'host')
+	UserHost string `protobuf:"bytes,96,opt,name=user_host,json=userHost,proto3" json:"user_host,omitempty"` // Host of user_name/permission_target (empty = builder default)
+	// Dialect table options (CREATE TABLE)
+	TableOptions map[string]string `protobuf:"bytes,97,rep,name=table_options,json=tableOptions,proto3" json:"table_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // MySQL: ENGINE, CHARSET, COLLATE, AUTO_INCREMENT; SQLite: STRICT, WITHOUT_ROWID; BigQuery: PARTITION_EXPIRATION_DAYS, REQUIRE_PARTITION_FILTER
+	// Row and table locking
+	Lock       string       `protobuf:"bytes,98,opt,name=lock,proto3" json:"lock,omitempty"`                                // SELECT ... FOR UPDATE | FOR SHARE
+	LockWait   string       `protobuf:"bytes,99,opt,name=lock_wait,json=lockWait,proto3" json:"lock_wait,omitempty"`        // NOWAIT, SKIP LOCKED
+	LockTables []*TableLock `protobuf:"bytes,100,rep,name=lock_tables,json=lockTables,proto3" json:"lock_tables,omitempty"` // LOCK TABLES (MySQL)
+	// Attached database file (SQLite ATTACH DATABASE 'file' AS database_name)
+	DatabaseFile string `protobuf:"bytes,101,opt,name=database_file,json=databaseFile,proto3" json:"database_file,omitempty"`
+	// Transaction locking mode (SQLite BEGIN DEFERRED | IMMEDIATE | EXCLUSIVE)
+	BeginMode string `protobuf:"bytes,102,opt,name=begin_mode,json=beginMode,proto3" json:"begin_mode,omitempty"`
+	// Historical read (CockroachDB AS OF SYSTEM TIME, Snowflake AT)
+	AsOf string `protobuf:"bytes,103,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"` // Interval ("-10s") or timestamp
+	// ClickHouse table modifiers (FROM table FINAL SAMPLE k)
+	Final  bool   `protobuf:"varint,104,opt,name=final,proto3" json:"final,omitempty"`  // Merge rows of the same key before reading
+	Sample string `protobuf:"bytes,105,opt,name=sample,proto3" json:"sample,omitempty"` // Fraction (0.1) or approximate row count (10000)
+	// Table clustering (BigQuery, Snowflake CLUSTER BY; Cassandra clustering columns)
+	ClusterKeys []*Expression `protobuf:"bytes,106,rep,name=cluster_keys,json=clusterKeys,proto3" json:"cluster_keys,omitempty"` // Clustering columns - 100% TrueAST
+	// Historical read just before a time or statement (Snowflake BEFORE)
+	Before string `protobuf:"bytes,107,opt,name=before,proto3" json:"before,omitempty"` // Interval, timestamp or query ID
+	// Row expiry and lightweight transactions (Cassandra INSERT ... IF NOT EXISTS USING TTL)
+	TtlMs         int64 `protobuf:"varint,108,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`                     // TTL in milliseconds (0 = none)
+	IfNotExists   bool  `protobuf:"varint,109,opt,name=if_not_exists,json=ifNotExists,proto3" json:"if_not_exists,omitempty"` // Insert only if no row has the key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RelationalQuery) Reset() {
@@ -1196,42 +1248,258 @@ func (x *RelationalQuery) GetCascade() bool {
 	return false
 }
 
+func (x *RelationalQuery) GetReturning() []*Expression {
+	if x != nil {
+		return x.Returning
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetPartitionStrategy() string {
+	if x != nil {
+		return x.PartitionStrategy
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetPartitionKeys() []*Expression {
+	if x != nil {
+		return x.PartitionKeys
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetPartitionName() string {
+	if x != nil {
+		return x.PartitionName
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetPartitionFrom() []*Expression {
+	if x != nil {
+		return x.PartitionFrom
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetPartitionTo() []*Expression {
+	if x != nil {
+		return x.PartitionTo
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetPartitionIn() []*Expression {
+	if x != nil {
+		return x.PartitionIn
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetPartitionModulus() int64 {
+	if x != nil {
+		return x.PartitionModulus
+	}
+	return 0
+}
+
+func (x *RelationalQuery) GetPartitionRemainder() int64 {
+	if x != nil {
+		return x.PartitionRemainder
+	}
+	return 0
+}
+
+func (x *RelationalQuery) GetPartitionDefault() bool {
+	if x != nil {
+		return x.PartitionDefault
+	}
+	return false
+}
+
+func (x *RelationalQuery) GetPermissionObject() string {
+	if x != nil {
+		return x.PermissionObject
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetRoleFlags() []string {
+	if x != nil {
+		return x.RoleFlags
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetValidUntil() string {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetConnectionLimit() string {
+	if x != nil {
+		return x.ConnectionLimit
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetUserHost() string {
+	if x != nil {
+		return x.UserHost
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetTableOptions() map[string]string {
+	if x != nil {
+		return x.TableOptions
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetLock() string {
+	if x != nil {
+		return x.Lock
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetLockWait() string {
+	if x != nil {
+		return x.LockWait
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetLockTables() []*TableLock {
+	if x != nil {
+		return x.LockTables
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetDatabaseFile() string {
+	if x != nil {
+		return x.DatabaseFile
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetBeginMode() string {
+	if x != nil {
+		return x.BeginMode
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *RelationalQuery) GetSample() string {
+	if x != nil {
+		return x.Sample
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetClusterKeys() []*Expression {
+	if x != nil {
+		return x.ClusterKeys
+	}
+	return nil
+}
+
+func (x *RelationalQuery) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *RelationalQuery) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *RelationalQuery) GetIfNotExists() bool {
+	if x != nil {
+		return x.IfNotExists
+	}
+	return false
+}
+
 type DocumentQuery struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Operation        string                 `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
-	Collection       string                 `protobuf:"bytes,2,opt,name=collection,proto3" json:"collection,omitempty"`
-	Conditions       []*QueryCondition      `protobuf:"bytes,3,rep,name=conditions,proto3" json:"conditions,omitempty"`
-	Fields           []*QueryField          `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`
-	Limit            int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
-	Skip             int32                  `protobuf:"varint,6,opt,name=skip,proto3" json:"skip,omitempty"`
-	Joins            []*JoinClause          `protobuf:"bytes,7,rep,name=joins,proto3" json:"joins,omitempty"`
-	Aggregate        *AggregateClause       `protobuf:"bytes,8,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
-	GroupBy          []*Expression          `protobuf:"bytes,9,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"` // 100% TrueAST
-	OrderBy          []*OrderByClause       `protobuf:"bytes,10,rep,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
-	SavepointName    string                 `protobuf:"bytes,11,opt,name=savepoint_name,json=savepointName,proto3" json:"savepoint_name,omitempty"`
-	Permissions      []string               `protobuf:"bytes,12,rep,name=permissions,proto3" json:"permissions,omitempty"`
-	PermissionTarget string                 `protobuf:"bytes,13,opt,name=permission_target,json=permissionTarget,proto3" json:"permission_target,omitempty"`
-	RoleName         string                 `protobuf:"bytes,14,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"`
-	WindowFunctions  []*WindowClause        `protobuf:"bytes,15,rep,name=window_functions,json=windowFunctions,proto3" json:"window_functions,omitempty"`
-	Pattern          string                 `protobuf:"bytes,16,opt,name=pattern,proto3" json:"pattern,omitempty"`
-	IsolationLevel   string                 `protobuf:"bytes,17,opt,name=isolation_level,json=isolationLevel,proto3" json:"isolation_level,omitempty"`
-	ReadOnly         bool                   `protobuf:"varint,18,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	Upsert           *UpsertClause          `protobuf:"bytes,19,opt,name=upsert,proto3" json:"upsert,omitempty"`
-	BulkData         []*BulkInsertRow       `protobuf:"bytes,20,rep,name=bulk_data,json=bulkData,proto3" json:"bulk_data,omitempty"`
-	ViewName         string                 `protobuf:"bytes,21,opt,name=view_name,json=viewName,proto3" json:"view_name,omitempty"`
-	ViewQuery        *DocumentQuery         `protobuf:"bytes,22,opt,name=view_query,json=viewQuery,proto3" json:"view_query,omitempty"` // 100% TrueAST
-	DatabaseName     string                 `protobuf:"bytes,23,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
-	UserName         string                 `protobuf:"bytes,24,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
-	Password         string                 `protobuf:"bytes,25,opt,name=password,proto3" json:"password,omitempty"`
-	UserRoles        []string               `protobuf:"bytes,26,rep,name=user_roles,json=userRoles,proto3" json:"user_roles,omitempty"`
-	NewName          string                 `protobuf:"bytes,27,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
-	SetOperation     *SetOperationClause    `protobuf:"bytes,28,opt,name=set_operation,json=setOperation,proto3" json:"set_operation,omitempty"`
-	Columns          []*Expression          `protobuf:"bytes,29,rep,name=columns,proto3" json:"columns,omitempty"` // 100% TrueAST
-	SelectColumns    []*SelectColumn        `protobuf:"bytes,30,rep,name=select_columns,json=selectColumns,proto3" json:"select_columns,omitempty"`
-	SessionId        string                 `protobuf:"bytes,31,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Having           []*QueryCondition      `protobuf:"bytes,32,rep,name=having,proto3" json:"having,omitempty"`
-	Distinct         bool                   `protobuf:"varint,33,opt,name=distinct,proto3" json:"distinct,omitempty"`
-	Query            string                 `protobuf:"bytes,34,opt,name=query,proto3" json:"query,omitempty"`
+	state            protoimpl.MessageState      `protogen:"open.v1"`
+	Operation        string                      `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Collection       string                      `protobuf:"bytes,2,opt,name=collection,proto3" json:"collection,omitempty"`
+	Conditions       []*QueryCondition           `protobuf:"bytes,3,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	Fields           []*QueryField               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`
+	Limit            int32                       `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Skip             int32                       `protobuf:"varint,6,opt,name=skip,proto3" json:"skip,omitempty"`
+	Joins            []*JoinClause               `protobuf:"bytes,7,rep,name=joins,proto3" json:"joins,omitempty"`
+	Aggregate        *AggregateClause            `protobuf:"bytes,8,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	GroupBy          []*Expression               `protobuf:"bytes,9,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"` // 100% TrueAST
+	OrderBy          []*OrderByClause            `protobuf:"bytes,10,rep,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	SavepointName    string                      `protobuf:"bytes,11,opt,name=savepoint_name,json=savepointName,proto3" json:"savepoint_name,omitempty"`
+	Permissions      []string                    `protobuf:"bytes,12,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	PermissionTarget string                      `protobuf:"bytes,13,opt,name=permission_target,json=permissionTarget,proto3" json:"permission_target,omitempty"`
+	RoleName         string                      `protobuf:"bytes,14,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"`
+	WindowFunctions  []*WindowClause             `protobuf:"bytes,15,rep,name=window_functions,json=windowFunctions,proto3" json:"window_functions,omitempty"`
+	Pattern          string                      `protobuf:"bytes,16,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	IsolationLevel   string                      `protobuf:"bytes,17,opt,name=isolation_level,json=isolationLevel,proto3" json:"isolation_level,omitempty"`
+	ReadOnly         bool                        `protobuf:"varint,18,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Upsert           *UpsertClause               `protobuf:"bytes,19,opt,name=upsert,proto3" json:"upsert,omitempty"`
+	BulkData         []*BulkInsertRow            `protobuf:"bytes,20,rep,name=bulk_data,json=bulkData,proto3" json:"bulk_data,omitempty"`
+	ViewName         string                      `protobuf:"bytes,21,opt,name=view_name,json=viewName,proto3" json:"view_name,omitempty"`
+	ViewQuery        *DocumentQuery              `protobuf:"bytes,22,opt,name=view_query,json=viewQuery,proto3" json:"view_query,omitempty"` // 100% TrueAST
+	DatabaseName     string                      `protobuf:"bytes,23,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	UserName         string                      `protobuf:"bytes,24,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Password         string                      `protobuf:"bytes,25,opt,name=password,proto3" json:"password,omitempty"`
+	UserRoles        []string                    `protobuf:"bytes,26,rep,name=user_roles,json=userRoles,proto3" json:"user_roles,omitempty"`
+	NewName          string                      `protobuf:"bytes,27,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	SetOperation     *DocumentSetOperationClause `protobuf:"bytes,28,opt,name=set_operation,json=setOperation,proto3" json:"set_operation,omitempty"`
+	Columns          []*Expression               `protobuf:"bytes,29,rep,name=columns,proto3" json:"columns,omitempty"` // 100% TrueAST
+	SelectColumns    []*SelectColumn             `protobuf:"bytes,30,rep,name=select_columns,json=selectColumns,proto3" json:"select_columns,omitempty"`
+	SessionId        string                      `protobuf:"bytes,31,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Having           []*QueryCondition           `protobuf:"bytes,32,rep,name=having,proto3" json:"having,omitempty"`
+	Distinct         bool                        `protobuf:"varint,33,opt,name=distinct,proto3" json:"distinct,omitempty"`
+	Query            string                      `protobuf:"bytes,34,opt,name=query,proto3" json:"query,omitempty"`
+	Facets           []*FacetClause              `protobuf:"bytes,35,rep,name=facets,proto3" json:"facets,omitempty"`                                 // FACET: extra aggregates in one $facet stage
+	GraphLookup      *GraphLookupClause          `protobuf:"bytes,36,opt,name=graph_lookup,json=graphLookup,proto3" json:"graph_lookup,omitempty"`    // Recursive CTE
+	ArrayFilters     []*QueryCondition           `protobuf:"bytes,37,rep,name=array_filters,json=arrayFilters,proto3" json:"array_filters,omitempty"` // UPDATE: conditions on $[name] array elements
+	Collation        *CollationClause            `protobuf:"bytes,38,opt,name=collation,proto3" json:"collation,omitempty"`                           // COLLATE locale [STRENGTH n]
+	Returning        []*Expression               `protobuf:"bytes,39,rep,name=returning,proto3" json:"returning,omitempty"`                           // RETURNING: UPDATE / DELETE run as findOneAndUpdate / findOneAndDelete
+	TtlMs            int64                       `protobuf:"varint,40,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`                     // TTL: the document's expiry date, removed by a TTL index
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -1455,7 +1723,7 @@ func (x *DocumentQuery) GetNewName() string {
 	return ""
 }
 
-func (x *DocumentQuery) GetSetOperation() *SetOperationClause {
+func (x *DocumentQuery) GetSetOperation() *DocumentSetOperationClause {
 	if x != nil {
 		return x.SetOperation
 	}
@@ -1504,6 +1772,48 @@ func (x *DocumentQuery) GetQuery() string {
 	return ""
 }
 
+func (x *DocumentQuery) GetFacets() []*FacetClause {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *DocumentQuery) GetGraphLookup() *GraphLookupClause {
+	if x != nil {
+		return x.GraphLookup
+	}
+	return nil
+}
+
+func (x *DocumentQuery) GetArrayFilters() []*QueryCondition {
+	if x != nil {
+		return x.ArrayFilters
+	}
+	return nil
+}
+
+func (x *DocumentQuery) GetCollation() *CollationClause {
+	if x != nil {
+		return x.Collation
+	}
+	return nil
+}
+
+func (x *DocumentQuery) GetReturning() []*Expression {
+	if x != nil {
+		return x.Returning
+	}
+	return nil
+}
+
+func (x *DocumentQuery) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
 type KeyValueQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
@@ -1518,6 +1828,8 @@ type KeyValueQuery struct {
 	Limit         int32             `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`                    // LIMIT clause
 	Offset        int32             `protobuf:"varint,10,opt,name=offset,proto3" json:"offset,omitempty"`                 // OFFSET clause
 	OrderBy       []*OrderByClause  `protobuf:"bytes,11,rep,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"` // ORDER BY clauses
+	TtlMs         int64             `protobuf:"varint,12,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`      // TTL: EXPIRE / PEXPIRE on the written key
+	Upsert        *UpsertClause     `protobuf:"bytes,13,opt,name=upsert,proto3" json:"upsert,omitempty"`                  // UPSERT: conflict fields and fields updated on conflict
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1629,6 +1941,20 @@ func (x *KeyValueQuery) GetOrderBy() []*OrderByClause {
 	return nil
 }
 
+func (x *KeyValueQuery) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *KeyValueQuery) GetUpsert() *UpsertClause {
+	if x != nil {
+		return x.Upsert
+	}
+	return nil
+}
+
 type KeyValuePair struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -1759,9 +2085,11 @@ func (x *JoinClause) GetPosition() int32 {
 
 type AggregateClause struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`                    // COUNT, SUM, AVG, MIN, MAX
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`                    // COUNT, SUM, AVG, MIN, MAX, STRING AGG
 	FieldExpr     *Expression            `protobuf:"bytes,2,opt,name=field_expr,json=fieldExpr,proto3" json:"field_expr,omitempty"` // 100% TrueAST
 	Position      int32                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	Separator     string                 `protobuf:"bytes,4,opt,name=separator,proto3" json:"separator,omitempty"`            // STRING AGG only
+	OrderBy       []*OrderByClause       `protobuf:"bytes,5,rep,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"` // STRING AGG only: order inside the aggregate
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1817,29 +2145,47 @@ func (x *AggregateClause) GetPosition() int32 {
 	return 0
 }
 
-type OrderByClause struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FieldExpr     *Expression            `protobuf:"bytes,1,opt,name=field_expr,json=fieldExpr,proto3" json:"field_expr,omitempty"` // 100% TrueAST
-	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`                  // ASC, DESC
-	Position      int32                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *AggregateClause) GetSeparator() string {
+	if x != nil {
+		return x.Separator
+	}
+	return ""
 }
 
-func (x *OrderByClause) Reset() {
-	*x = OrderByClause{}
+func (x *AggregateClause) GetOrderBy() []*OrderByClause {
+	if x != nil {
+		return x.OrderBy
+	}
+	return nil
+}
+
+type GraphLookupClause struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CteName          string                 `protobuf:"bytes,1,opt,name=cte_name,json=cteName,proto3" json:"cte_name,omitempty"`
+	From             string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`                            // Collection the recursive member reads
+	StartWith        string                 `protobuf:"bytes,3,opt,name=start_with,json=startWith,proto3" json:"start_with,omitempty"` // Anchor field the walk starts from
+	ConnectFromField string                 `protobuf:"bytes,4,opt,name=connect_from_field,json=connectFromField,proto3" json:"connect_from_field,omitempty"`
+	ConnectToField   string                 `protobuf:"bytes,5,opt,name=connect_to_field,json=connectToField,proto3" json:"connect_to_field,omitempty"`
+	MaxDepth         int32                  `protobuf:"varint,6,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`   // DEPTH n; 0 = unlimited
+	MainQuery        *DocumentQuery         `protobuf:"bytes,7,opt,name=main_query,json=mainQuery,proto3" json:"main_query,omitempty"` // Filter, sort and paging over the CTE rows
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GraphLookupClause) Reset() {
+	*x = GraphLookupClause{}
 	mi := &file_utilities_proto_events_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderByClause) String() string {
+func (x *GraphLookupClause) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderByClause) ProtoMessage() {}
+func (*GraphLookupClause) ProtoMessage() {}
 
-func (x *OrderByClause) ProtoReflect() protoreflect.Message {
+func (x *GraphLookupClause) ProtoReflect() protoreflect.Message {
 	mi := &file_utilities_proto_events_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1851,33 +2197,233 @@ func (x *OrderByClause) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderByClause.ProtoReflect.Descriptor instead.
-func (*OrderByClause) Descriptor() ([]byte, []int) {
+// Deprecated: Use GraphLookupClause.ProtoReflect.Descriptor instead.
+func (*GraphLookupClause) Descriptor() ([]byte, []int) {
 	return file_utilities_proto_events_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *OrderByClause) GetFieldExpr() *Expression {
+func (x *GraphLookupClause) GetCteName() string {
 	if x != nil {
-		return x.FieldExpr
+		return x.CteName
 	}
-	return nil
+	return ""
 }
 
-func (x *OrderByClause) GetDirection() string {
+func (x *GraphLookupClause) GetFrom() string {
 	if x != nil {
-		return x.Direction
+		return x.From
 	}
 	return ""
 }
 
-func (x *OrderByClause) GetPosition() int32 {
+func (x *GraphLookupClause) GetStartWith() string {
 	if x != nil {
-		return x.Position
+		return x.StartWith
+	}
+	return ""
+}
+
+func (x *GraphLookupClause) GetConnectFromField() string {
+	if x != nil {
+		return x.ConnectFromField
+	}
+	return ""
+}
+
+func (x *GraphLookupClause) GetConnectToField() string {
+	if x != nil {
+		return x.ConnectToField
+	}
+	return ""
+}
+
+func (x *GraphLookupClause) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
 	}
 	return 0
 }
 
-type WindowClause struct {
+func (x *GraphLookupClause) GetMainQuery() *DocumentQuery {
+	if x != nil {
+		return x.MainQuery
+	}
+	return nil
+}
+
+type CollationClause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locale        string                 `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
+	Strength      int32                  `protobuf:"varint,2,opt,name=strength,proto3" json:"strength,omitempty"` // 1-5; 0 = locale default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollationClause) Reset() {
+	*x = CollationClause{}
+	mi := &file_utilities_proto_events_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollationClause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollationClause) ProtoMessage() {}
+
+func (x *CollationClause) ProtoReflect() protoreflect.Message {
+	mi := &file_utilities_proto_events_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollationClause.ProtoReflect.Descriptor instead.
+func (*CollationClause) Descriptor() ([]byte, []int) {
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CollationClause) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *CollationClause) GetStrength() int32 {
+	if x != nil {
+		return x.Strength
+	}
+	return 0
+}
+
+type FacetClause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Aggregate     *AggregateClause       `protobuf:"bytes,2,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	GroupBy       []*Expression          `protobuf:"bytes,3,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"` // 100% TrueAST
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FacetClause) Reset() {
+	*x = FacetClause{}
+	mi := &file_utilities_proto_events_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FacetClause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FacetClause) ProtoMessage() {}
+
+func (x *FacetClause) ProtoReflect() protoreflect.Message {
+	mi := &file_utilities_proto_events_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FacetClause.ProtoReflect.Descriptor instead.
+func (*FacetClause) Descriptor() ([]byte, []int) {
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FacetClause) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FacetClause) GetAggregate() *AggregateClause {
+	if x != nil {
+		return x.Aggregate
+	}
+	return nil
+}
+
+func (x *FacetClause) GetGroupBy() []*Expression {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+type OrderByClause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FieldExpr     *Expression            `protobuf:"bytes,1,opt,name=field_expr,json=fieldExpr,proto3" json:"field_expr,omitempty"` // 100% TrueAST
+	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`                  // ASC, DESC
+	Position      int32                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderByClause) Reset() {
+	*x = OrderByClause{}
+	mi := &file_utilities_proto_events_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderByClause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderByClause) ProtoMessage() {}
+
+func (x *OrderByClause) ProtoReflect() protoreflect.Message {
+	mi := &file_utilities_proto_events_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderByClause.ProtoReflect.Descriptor instead.
+func (*OrderByClause) Descriptor() ([]byte, []int) {
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *OrderByClause) GetFieldExpr() *Expression {
+	if x != nil {
+		return x.FieldExpr
+	}
+	return nil
+}
+
+func (x *OrderByClause) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *OrderByClause) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type WindowClause struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`                    // ROW NUMBER, RANK, etc.
 	FieldExpr     *Expression            `protobuf:"bytes,2,opt,name=field_expr,json=fieldExpr,proto3" json:"field_expr,omitempty"` // 100% TrueAST
@@ -1887,13 +2433,17 @@ type WindowClause struct {
 	Offset        int32                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
 	Buckets       int32                  `protobuf:"varint,7,opt,name=buckets,proto3" json:"buckets,omitempty"`
 	Position      int32                  `protobuf:"varint,8,opt,name=position,proto3" json:"position,omitempty"`
+	DefaultValue  *Expression            `protobuf:"bytes,9,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"` // LAG/LEAD: value when no row is at the offset
+	FrameUnit     string                 `protobuf:"bytes,10,opt,name=frame_unit,json=frameUnit,proto3" json:"frame_unit,omitempty"`         // ROWS, RANGE (empty = database default frame)
+	FrameStart    string                 `protobuf:"bytes,11,opt,name=frame_start,json=frameStart,proto3" json:"frame_start,omitempty"`      // UNBOUNDED PRECEDING, n PRECEDING, CURRENT ROW, n FOLLOWING
+	FrameEnd      string                 `protobuf:"bytes,12,opt,name=frame_end,json=frameEnd,proto3" json:"frame_end,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WindowClause) Reset() {
 	*x = WindowClause{}
-	mi := &file_utilities_proto_events_proto_msgTypes[13]
+	mi := &file_utilities_proto_events_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1905,7 +2455,7 @@ func (x *WindowClause) String() string {
 func (*WindowClause) ProtoMessage() {}
 
 func (x *WindowClause) ProtoReflect() protoreflect.Message {
-	mi := &file_utilities_proto_events_proto_msgTypes[13]
+	mi := &file_utilities_proto_events_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1918,7 +2468,7 @@ func (x *WindowClause) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WindowClause.ProtoReflect.Descriptor instead.
 func (*WindowClause) Descriptor() ([]byte, []int) {
-	return file_utilities_proto_events_proto_rawDescGZIP(), []int{13}
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *WindowClause) GetFunction() string {
@@ -1977,19 +2527,48 @@ func (x *WindowClause) GetPosition() int32 {
 	return 0
 }
 
+func (x *WindowClause) GetDefaultValue() *Expression {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return nil
+}
+
+func (x *WindowClause) GetFrameUnit() string {
+	if x != nil {
+		return x.FrameUnit
+	}
+	return ""
+}
+
+func (x *WindowClause) GetFrameStart() string {
+	if x != nil {
+		return x.FrameStart
+	}
+	return ""
+}
+
+func (x *WindowClause) GetFrameEnd() string {
+	if x != nil {
+		return x.FrameEnd
+	}
+	return ""
+}
+
 type CTEClause struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	CteName        string                 `protobuf:"bytes,1,opt,name=cte_name,json=cteName,proto3" json:"cte_name,omitempty"`
 	CteQuery       *RelationalQuery       `protobuf:"bytes,2,opt,name=cte_query,json=cteQuery,proto3" json:"cte_query,omitempty"` // 100% TrueAST
 	Recursive      bool                   `protobuf:"varint,3,opt,name=recursive,proto3" json:"recursive,omitempty"`
 	AdditionalCtes []*CTEClause           `protobuf:"bytes,4,rep,name=additional_ctes,json=additionalCtes,proto3" json:"additional_ctes,omitempty"`
+	MainQuery      *RelationalQuery       `protobuf:"bytes,5,opt,name=main_query,json=mainQuery,proto3" json:"main_query,omitempty"` // Query that reads from the CTE (100% TrueAST)
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CTEClause) Reset() {
 	*x = CTEClause{}
-	mi := &file_utilities_proto_events_proto_msgTypes[14]
+	mi := &file_utilities_proto_events_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2001,7 +2580,7 @@ func (x *CTEClause) String() string {
 func (*CTEClause) ProtoMessage() {}
 
 func (x *CTEClause) ProtoReflect() protoreflect.Message {
-	mi := &file_utilities_proto_events_proto_msgTypes[14]
+	mi := &file_utilities_proto_events_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2014,7 +2593,7 @@ func (x *CTEClause) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CTEClause.ProtoReflect.Descriptor instead.
 func (*CTEClause) Descriptor() ([]byte, []int) {
-	return file_utilities_proto_events_proto_rawDescGZIP(), []int{14}
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *CTEClause) GetCteName() string {
@@ -2045,6 +2624,13 @@ func (x *CTEClause) GetAdditionalCtes() []*CTEClause {
 	return nil
 }
 
+func (x *CTEClause) GetMainQuery() *RelationalQuery {
+	if x != nil {
+		return x.MainQuery
+	}
+	return nil
+}
+
 type SubqueryClause struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SubqueryType  string                 `protobuf:"bytes,1,opt,name=subquery_type,json=subqueryType,proto3" json:"subquery_type,omitempty"` // IN, EXISTS, NOT_IN, NOT_EXISTS, SCALAR
@@ -2057,7 +2643,7 @@ type SubqueryClause struct {
 
 func (x *SubqueryClause) Reset() {
 	*x = SubqueryClause{}
-	mi := &file_utilities_proto_events_proto_msgTypes[15]
+	mi := &file_utilities_proto_events_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2069,7 +2655,7 @@ func (x *SubqueryClause) String() string {
 func (*SubqueryClause) ProtoMessage() {}
 
 func (x *SubqueryClause) ProtoReflect() protoreflect.Message {
-	mi := &file_utilities_proto_events_proto_msgTypes[15]
+	mi := &file_utilities_proto_events_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2082,7 +2668,7 @@ func (x *SubqueryClause) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubqueryClause.ProtoReflect.Descriptor instead.
 func (*SubqueryClause) Descriptor() ([]byte, []int) {
-	return file_utilities_proto_events_proto_rawDescGZIP(), []int{15}
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *SubqueryClause) GetSubqueryType() string {
@@ -2114,17 +2700,19 @@ func (x *SubqueryClause) GetAlias() string {
 }
 
 type UpsertClause struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ConflictFields []*Expression          `protobuf:"bytes,1,rep,name=conflict_fields,json=conflictFields,proto3" json:"conflict_fields,omitempty"` // 100% TrueAST
-	UpdateFields   []*QueryField          `protobuf:"bytes,2,rep,name=update_fields,json=updateFields,proto3" json:"update_fields,omitempty"`
-	ConflictAction string                 `protobuf:"bytes,3,opt,name=conflict_action,json=conflictAction,proto3" json:"conflict_action,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ConflictFields     []*Expression          `protobuf:"bytes,1,rep,name=conflict_fields,json=conflictFields,proto3" json:"conflict_fields,omitempty"` // 100% TrueAST
+	UpdateFields       []*QueryField          `protobuf:"bytes,2,rep,name=update_fields,json=updateFields,proto3" json:"update_fields,omitempty"`       // No value_expr = take the inserted value
+	ConflictAction     string                 `protobuf:"bytes,3,opt,name=conflict_action,json=conflictAction,proto3" json:"conflict_action,omitempty"`
+	ConflictConstraint string                 `protobuf:"bytes,4,opt,name=conflict_constraint,json=conflictConstraint,proto3" json:"conflict_constraint,omitempty"` // ON CONFLICT ON CONSTRAINT name
+	ConflictWhere      []*QueryCondition      `protobuf:"bytes,5,rep,name=conflict_where,json=conflictWhere,proto3" json:"conflict_where,omitempty"`                // Partial unique index predicate
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *UpsertClause) Reset() {
 	*x = UpsertClause{}
-	mi := &file_utilities_proto_events_proto_msgTypes[16]
+	mi := &file_utilities_proto_events_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2136,7 +2724,7 @@ func (x *UpsertClause) String() string {
 func (*UpsertClause) ProtoMessage() {}
 
 func (x *UpsertClause) ProtoReflect() protoreflect.Message {
-	mi := &file_utilities_proto_events_proto_msgTypes[16]
+	mi := &file_utilities_proto_events_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2149,7 +2737,7 @@ func (x *UpsertClause) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertClause.ProtoReflect.Descriptor instead.
 func (*UpsertClause) Descriptor() ([]byte, []int) {
-	return file_utilities_proto_events_proto_rawDescGZIP(), []int{16}
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *UpsertClause) GetConflictFields() []*Expression {
@@ -2173,6 +2761,20 @@ func (x *UpsertClause) GetConflictAction() string {
 	return ""
 }
 
+func (x *UpsertClause) GetConflictConstraint() string {
+	if x != nil {
+		return x.ConflictConstraint
+	}
+	return ""
+}
+
+func (x *UpsertClause) GetConflictWhere() []*QueryCondition {
+	if x != nil {
+		return x.ConflictWhere
+	}
+	return nil
+}
+
 type BulkInsertRow struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Fields        []*QueryField          `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
@@ -2182,7 +2784,7 @@ type BulkInsertRow struct {
 
 func (x *BulkInsertRow) Reset() {
 	*x = BulkInsertRow{}
-	mi := &file_utilities_proto_events_proto_msgTypes[17]
+	mi := &file_utilities_proto_events_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2194,7 +2796,7 @@ func (x *BulkInsertRow) String() string {
 func (*BulkInsertRow) ProtoMessage() {}
 
 func (x *BulkInsertRow) ProtoReflect() protoreflect.Message {
-	mi := &file_utilities_proto_events_proto_msgTypes[17]
+	mi := &file_utilities_proto_events_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2207,7 +2809,7 @@ func (x *BulkInsertRow) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BulkInsertRow.ProtoReflect.Descriptor instead.
 func (*BulkInsertRow) Descriptor() ([]byte, []int) {
-	return file_utilities_proto_events_proto_rawDescGZIP(), []int{17}
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *BulkInsertRow) GetFields() []*QueryField {
@@ -2217,6 +2819,58 @@ func (x *BulkInsertRow) GetFields() []*QueryField {
 	return nil
 }
 
+type TableLock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Table         string                 `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"` // READ, WRITE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TableLock) Reset() {
+	*x = TableLock{}
+	mi := &file_utilities_proto_events_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableLock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableLock) ProtoMessage() {}
+
+func (x *TableLock) ProtoReflect() protoreflect.Message {
+	mi := &file_utilities_proto_events_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableLock.ProtoReflect.Descriptor instead.
+func (*TableLock) Descriptor() ([]byte, []int) {
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TableLock) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *TableLock) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
 type SetOperationClause struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OperationType string                 `protobuf:"bytes,1,opt,name=operation_type,json=operationType,proto3" json:"operation_type,omitempty"` // UNION, UNION ALL, INTERSECT, EXCEPT
@@ -2228,7 +2882,7 @@ type SetOperationClause struct {
 
 func (x *SetOperationClause) Reset() {
 	*x = SetOperationClause{}
-	mi := &file_utilities_proto_events_proto_msgTypes[18]
+	mi := &file_utilities_proto_events_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2240,7 +2894,7 @@ func (x *SetOperationClause) String() string {
 func (*SetOperationClause) ProtoMessage() {}
 
 func (x *SetOperationClause) ProtoReflect() protoreflect.Message {
-	mi := &file_utilities_proto_events_proto_msgTypes[18]
+	mi := &file_utilities_proto_events_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2253,7 +2907,7 @@ func (x *SetOperationClause) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetOperationClause.ProtoReflect.Descriptor instead.
 func (*SetOperationClause) Descriptor() ([]byte, []int) {
-	return file_utilities_proto_events_proto_rawDescGZIP(), []int{18}
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *SetOperationClause) GetOperationType() string {
@@ -2277,6 +2931,66 @@ func (x *SetOperationClause) GetRightQuery() *RelationalQuery {
 	return nil
 }
 
+type DocumentSetOperationClause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationType string                 `protobuf:"bytes,1,opt,name=operation_type,json=operationType,proto3" json:"operation_type,omitempty"` // UNION, UNION ALL, INTERSECT, EXCEPT
+	LeftQuery     *DocumentQuery         `protobuf:"bytes,2,opt,name=left_query,json=leftQuery,proto3" json:"left_query,omitempty"`
+	RightQuery    *DocumentQuery         `protobuf:"bytes,3,opt,name=right_query,json=rightQuery,proto3" json:"right_query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentSetOperationClause) Reset() {
+	*x = DocumentSetOperationClause{}
+	mi := &file_utilities_proto_events_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentSetOperationClause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentSetOperationClause) ProtoMessage() {}
+
+func (x *DocumentSetOperationClause) ProtoReflect() protoreflect.Message {
+	mi := &file_utilities_proto_events_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentSetOperationClause.ProtoReflect.Descriptor instead.
+func (*DocumentSetOperationClause) Descriptor() ([]byte, []int) {
+	return file_utilities_proto_events_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DocumentSetOperationClause) GetOperationType() string {
+	if x != nil {
+		return x.OperationType
+	}
+	return ""
+}
+
+func (x *DocumentSetOperationClause) GetLeftQuery() *DocumentQuery {
+	if x != nil {
+		return x.LeftQuery
+	}
+	return nil
+}
+
+func (x *DocumentSetOperationClause) GetRightQuery() *DocumentQuery {
+	if x != nil {
+		return x.RightQuery
+	}
+	return nil
+}
+
 var File_utilities_proto_events_proto protoreflect.FileDescriptor
 
 const file_utilities_proto_events_proto_rawDesc = "" +
@@ -2319,18 +3033,19 @@ const file_utilities_proto_events_proto_rawDesc = "" +
 	"\rCaseCondition\x124\n" +
 	"\tcondition\x18\x01 \x01(\v2\x16.omniql.QueryConditionR\tcondition\x12/\n" +
 	"\tthen_expr\x18\x02 \x01(\v2\x12.omniql.ExpressionR\bthenExpr\x12\x1a\n" +
-	"\bposition\x18\x03 \x01(\x05R\bposition\"\xae\x01\n" +
+	"\bposition\x18\x03 \x01(\x05R\bposition\"\xe9\x01\n" +
 	"\n" +
 	"QueryField\x12/\n" +
 	"\tname_expr\x18\x01 \x01(\v2\x12.omniql.ExpressionR\bnameExpr\x121\n" +
 	"\n" +
 	"value_expr\x18\x02 \x01(\v2\x12.omniql.ExpressionR\tvalueExpr\x12 \n" +
 	"\vconstraints\x18\x03 \x03(\tR\vconstraints\x12\x1a\n" +
-	"\bposition\x18\x04 \x01(\x05R\bposition\"{\n" +
+	"\bposition\x18\x04 \x01(\x05R\bposition\x129\n" +
+	"\x0egenerated_expr\x18\x05 \x01(\v2\x12.omniql.ExpressionR\rgeneratedExpr\"{\n" +
 	"\fSelectColumn\x129\n" +
 	"\x0eexpression_obj\x18\x01 \x01(\v2\x12.omniql.ExpressionR\rexpressionObj\x12\x14\n" +
 	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x1a\n" +
-	"\bposition\x18\x03 \x01(\x05R\bposition\"\x80\x17\n" +
+	"\bposition\x18\x03 \x01(\x05R\bposition\"\xe7 \n" +
 	"\x0fRelationalQuery\x12\x1c\n" +
 	"\toperation\x18\x01 \x01(\tR\toperation\x12\x14\n" +
 	"\x05table\x18\x02 \x01(\tR\x05table\x126\n" +
@@ -2426,8 +3141,44 @@ const file_utilities_proto_events_proto_rawDesc = "" +
 	"ruleAction\x12%\n" +
 	"\x0ecomment_target\x18M \x01(\tR\rcommentTarget\x12!\n" +
 	"\fcomment_text\x18N \x01(\tR\vcommentText\x12\x18\n" +
-	"\acascade\x18O \x01(\bR\acascade\"\xcb\n" +
+	"\acascade\x18O \x01(\bR\acascade\x120\n" +
+	"\treturning\x18P \x03(\v2\x12.omniql.ExpressionR\treturning\x12-\n" +
+	"\x12partition_strategy\x18Q \x01(\tR\x11partitionStrategy\x129\n" +
+	"\x0epartition_keys\x18R \x03(\v2\x12.omniql.ExpressionR\rpartitionKeys\x12%\n" +
+	"\x0epartition_name\x18S \x01(\tR\rpartitionName\x129\n" +
+	"\x0epartition_from\x18T \x03(\v2\x12.omniql.ExpressionR\rpartitionFrom\x125\n" +
+	"\fpartition_to\x18U \x03(\v2\x12.omniql.ExpressionR\vpartitionTo\x125\n" +
+	"\fpartition_in\x18V \x03(\v2\x12.omniql.ExpressionR\vpartitionIn\x12+\n" +
+	"\x11partition_modulus\x18W \x01(\x03R\x10partitionModulus\x12/\n" +
+	"\x13partition_remainder\x18X \x01(\x03R\x12partitionRemainder\x12+\n" +
+	"\x11partition_default\x18Y \x01(\bR\x10partitionDefault\x12+\n" +
+	"\x11permission_object\x18Z \x01(\tR\x10permissionObject\x12\x1d\n" +
+	"\n" +
+	"role_flags\x18[ \x03(\tR\troleFlags\x12\x1f\n" +
+	"\vvalid_until\x18\\ \x01(\tR\n" +
+	"validUntil\x12)\n" +
+	"\x10connection_limit\x18] \x01(\tR\x0fconnectionLimit\x12\x18\n" +
+	"\achannel\x18^ \x01(\tR\achannel\x12\x18\n" +
+	"\apayload\x18_ \x01(\tR\apayload\x12\x1b\n" +
+	"\tuser_host\x18` \x01(\tR\buserHost\x12N\n" +
+	"\rtable_options\x18a \x03(\v2).omniql.RelationalQuery.TableOptionsEntryR\ftableOptions\x12\x12\n" +
+	"\x04lock\x18b \x01(\tR\x04lock\x12\x1b\n" +
+	"\tlock_wait\x18c \x01(\tR\blockWait\x122\n" +
+	"\vlock_tables\x18d \x03(\v2\x11.omniql.TableLockR\n" +
+	"lockTables\x12#\n" +
+	"\rdatabase_file\x18e \x01(\tR\fdatabaseFile\x12\x1d\n" +
 	"\n" +
+	"begin_mode\x18f \x01(\tR\tbeginMode\x12\x13\n" +
+	"\x05as_of\x18g \x01(\tR\x04asOf\x12\x14\n" +
+	"\x05final\x18h \x01(\bR\x05final\x12\x16\n" +
+	"\x06sample\x18i \x01(\tR\x06sample\x125\n" +
+	"\fcluster_keys\x18j \x03(\v2\x12.omniql.ExpressionR\vclusterKeys\x12\x16\n" +
+	"\x06before\x18k \x01(\tR\x06before\x12\x15\n" +
+	"\x06ttl_ms\x18l \x01(\x03R\x05ttlMs\x12\"\n" +
+	"\rif_not_exists\x18m \x01(\bR\vifNotExists\x1a?\n" +
+	"\x11TableOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfb\f\n" +
 	"\rDocumentQuery\x12\x1c\n" +
 	"\toperation\x18\x01 \x01(\tR\toperation\x12\x1e\n" +
 	"\n" +
@@ -2462,15 +3213,21 @@ const file_utilities_proto_events_proto_rawDesc = "" +
 	"\bpassword\x18\x19 \x01(\tR\bpassword\x12\x1d\n" +
 	"\n" +
 	"user_roles\x18\x1a \x03(\tR\tuserRoles\x12\x19\n" +
-	"\bnew_name\x18\x1b \x01(\tR\anewName\x12?\n" +
-	"\rset_operation\x18\x1c \x01(\v2\x1a.omniql.SetOperationClauseR\fsetOperation\x12,\n" +
+	"\bnew_name\x18\x1b \x01(\tR\anewName\x12G\n" +
+	"\rset_operation\x18\x1c \x01(\v2\".omniql.DocumentSetOperationClauseR\fsetOperation\x12,\n" +
 	"\acolumns\x18\x1d \x03(\v2\x12.omniql.ExpressionR\acolumns\x12;\n" +
 	"\x0eselect_columns\x18\x1e \x03(\v2\x14.omniql.SelectColumnR\rselectColumns\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x1f \x01(\tR\tsessionId\x12.\n" +
 	"\x06having\x18  \x03(\v2\x16.omniql.QueryConditionR\x06having\x12\x1a\n" +
 	"\bdistinct\x18! \x01(\bR\bdistinct\x12\x14\n" +
-	"\x05query\x18\" \x01(\tR\x05query\"\xfa\x02\n" +
+	"\x05query\x18\" \x01(\tR\x05query\x12+\n" +
+	"\x06facets\x18# \x03(\v2\x13.omniql.FacetClauseR\x06facets\x12<\n" +
+	"\fgraph_lookup\x18$ \x01(\v2\x19.omniql.GraphLookupClauseR\vgraphLookup\x12;\n" +
+	"\rarray_filters\x18% \x03(\v2\x16.omniql.QueryConditionR\farrayFilters\x125\n" +
+	"\tcollation\x18& \x01(\v2\x17.omniql.CollationClauseR\tcollation\x120\n" +
+	"\treturning\x18' \x03(\v2\x12.omniql.ExpressionR\treturning\x12\x15\n" +
+	"\x06ttl_ms\x18( \x01(\x03R\x05ttlMs\"\xbf\x03\n" +
 	"\rKeyValueQuery\x12\x18\n" +
 	"\acommand\x18\x01 \x01(\tR\acommand\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
@@ -2487,7 +3244,9 @@ const file_utilities_proto_events_proto_rawDesc = "" +
 	"\x05limit\x18\t \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\n" +
 	" \x01(\x05R\x06offset\x120\n" +
-	"\border_by\x18\v \x03(\v2\x15.omniql.OrderByClauseR\aorderBy\"6\n" +
+	"\border_by\x18\v \x03(\v2\x15.omniql.OrderByClauseR\aorderBy\x12\x15\n" +
+	"\x06ttl_ms\x18\f \x01(\x03R\x05ttlMs\x12,\n" +
+	"\x06upsert\x18\r \x01(\v2\x14.omniql.UpsertClauseR\x06upsert\"6\n" +
 	"\fKeyValuePair\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\"\xbf\x01\n" +
@@ -2498,17 +3257,36 @@ const file_utilities_proto_events_proto_rawDesc = "" +
 	"\tleft_expr\x18\x03 \x01(\v2\x12.omniql.ExpressionR\bleftExpr\x121\n" +
 	"\n" +
 	"right_expr\x18\x04 \x01(\v2\x12.omniql.ExpressionR\trightExpr\x12\x1a\n" +
-	"\bposition\x18\x05 \x01(\x05R\bposition\"|\n" +
+	"\bposition\x18\x05 \x01(\x05R\bposition\"\xcc\x01\n" +
 	"\x0fAggregateClause\x12\x1a\n" +
 	"\bfunction\x18\x01 \x01(\tR\bfunction\x121\n" +
 	"\n" +
 	"field_expr\x18\x02 \x01(\v2\x12.omniql.ExpressionR\tfieldExpr\x12\x1a\n" +
-	"\bposition\x18\x03 \x01(\x05R\bposition\"|\n" +
+	"\bposition\x18\x03 \x01(\x05R\bposition\x12\x1c\n" +
+	"\tseparator\x18\x04 \x01(\tR\tseparator\x120\n" +
+	"\border_by\x18\x05 \x03(\v2\x15.omniql.OrderByClauseR\aorderBy\"\x8c\x02\n" +
+	"\x11GraphLookupClause\x12\x19\n" +
+	"\bcte_name\x18\x01 \x01(\tR\acteName\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x1d\n" +
+	"\n" +
+	"start_with\x18\x03 \x01(\tR\tstartWith\x12,\n" +
+	"\x12connect_from_field\x18\x04 \x01(\tR\x10connectFromField\x12(\n" +
+	"\x10connect_to_field\x18\x05 \x01(\tR\x0econnectToField\x12\x1b\n" +
+	"\tmax_depth\x18\x06 \x01(\x05R\bmaxDepth\x124\n" +
+	"\n" +
+	"main_query\x18\a \x01(\v2\x15.omniql.DocumentQueryR\tmainQuery\"E\n" +
+	"\x0fCollationClause\x12\x16\n" +
+	"\x06locale\x18\x01 \x01(\tR\x06locale\x12\x1a\n" +
+	"\bstrength\x18\x02 \x01(\x05R\bstrength\"\x87\x01\n" +
+	"\vFacetClause\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x125\n" +
+	"\taggregate\x18\x02 \x01(\v2\x17.omniql.AggregateClauseR\taggregate\x12-\n" +
+	"\bgroup_by\x18\x03 \x03(\v2\x12.omniql.ExpressionR\agroupBy\"|\n" +
 	"\rOrderByClause\x121\n" +
 	"\n" +
 	"field_expr\x18\x01 \x01(\v2\x12.omniql.ExpressionR\tfieldExpr\x12\x1c\n" +
 	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x1a\n" +
-	"\bposition\x18\x03 \x01(\x05R\bposition\"\xaa\x02\n" +
+	"\bposition\x18\x03 \x01(\x05R\bposition\"\xc0\x03\n" +
 	"\fWindowClause\x12\x1a\n" +
 	"\bfunction\x18\x01 \x01(\tR\bfunction\x121\n" +
 	"\n" +
@@ -2518,29 +3296,49 @@ const file_utilities_proto_events_proto_rawDesc = "" +
 	"\border_by\x18\x05 \x03(\v2\x15.omniql.OrderByClauseR\aorderBy\x12\x16\n" +
 	"\x06offset\x18\x06 \x01(\x05R\x06offset\x12\x18\n" +
 	"\abuckets\x18\a \x01(\x05R\abuckets\x12\x1a\n" +
-	"\bposition\x18\b \x01(\x05R\bposition\"\xb6\x01\n" +
+	"\bposition\x18\b \x01(\x05R\bposition\x127\n" +
+	"\rdefault_value\x18\t \x01(\v2\x12.omniql.ExpressionR\fdefaultValue\x12\x1d\n" +
+	"\n" +
+	"frame_unit\x18\n" +
+	" \x01(\tR\tframeUnit\x12\x1f\n" +
+	"\vframe_start\x18\v \x01(\tR\n" +
+	"frameStart\x12\x1b\n" +
+	"\tframe_end\x18\f \x01(\tR\bframeEnd\"\xee\x01\n" +
 	"\tCTEClause\x12\x19\n" +
 	"\bcte_name\x18\x01 \x01(\tR\acteName\x124\n" +
 	"\tcte_query\x18\x02 \x01(\v2\x17.omniql.RelationalQueryR\bcteQuery\x12\x1c\n" +
 	"\trecursive\x18\x03 \x01(\bR\trecursive\x12:\n" +
-	"\x0fadditional_ctes\x18\x04 \x03(\v2\x11.omniql.CTEClauseR\x0eadditionalCtes\"\xb3\x01\n" +
+	"\x0fadditional_ctes\x18\x04 \x03(\v2\x11.omniql.CTEClauseR\x0eadditionalCtes\x126\n" +
+	"\n" +
+	"main_query\x18\x05 \x01(\v2\x17.omniql.RelationalQueryR\tmainQuery\"\xb3\x01\n" +
 	"\x0eSubqueryClause\x12#\n" +
 	"\rsubquery_type\x18\x01 \x01(\tR\fsubqueryType\x121\n" +
 	"\n" +
 	"field_expr\x18\x02 \x01(\v2\x12.omniql.ExpressionR\tfieldExpr\x123\n" +
 	"\bsubquery\x18\x03 \x01(\v2\x17.omniql.RelationalQueryR\bsubquery\x12\x14\n" +
-	"\x05alias\x18\x04 \x01(\tR\x05alias\"\xad\x01\n" +
+	"\x05alias\x18\x04 \x01(\tR\x05alias\"\x9d\x02\n" +
 	"\fUpsertClause\x12;\n" +
 	"\x0fconflict_fields\x18\x01 \x03(\v2\x12.omniql.ExpressionR\x0econflictFields\x127\n" +
 	"\rupdate_fields\x18\x02 \x03(\v2\x12.omniql.QueryFieldR\fupdateFields\x12'\n" +
-	"\x0fconflict_action\x18\x03 \x01(\tR\x0econflictAction\";\n" +
+	"\x0fconflict_action\x18\x03 \x01(\tR\x0econflictAction\x12/\n" +
+	"\x13conflict_constraint\x18\x04 \x01(\tR\x12conflictConstraint\x12=\n" +
+	"\x0econflict_where\x18\x05 \x03(\v2\x16.omniql.QueryConditionR\rconflictWhere\";\n" +
 	"\rBulkInsertRow\x12*\n" +
-	"\x06fields\x18\x01 \x03(\v2\x12.omniql.QueryFieldR\x06fields\"\xad\x01\n" +
+	"\x06fields\x18\x01 \x03(\v2\x12.omniql.QueryFieldR\x06fields\"5\n" +
+	"\tTableLock\x12\x14\n" +
+	"\x05table\x18\x01 \x01(\tR\x05table\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\"\xad\x01\n" +
 	"\x12SetOperationClause\x12%\n" +
 	"\x0eoperation_type\x18\x01 \x01(\tR\roperationType\x126\n" +
 	"\n" +
 	"left_query\x18\x02 \x01(\v2\x17.omniql.RelationalQueryR\tleftQuery\x128\n" +
 	"\vright_query\x18\x03 \x01(\v2\x17.omniql.RelationalQueryR\n" +
+	"rightQuery\"\xb1\x01\n" +
+	"\x1aDocumentSetOperationClause\x12%\n" +
+	"\x0eoperation_type\x18\x01 \x01(\tR\roperationType\x124\n" +
+	"\n" +
+	"left_query\x18\x02 \x01(\v2\x15.omniql.DocumentQueryR\tleftQuery\x126\n" +
+	"\vright_query\x18\x03 \x01(\v2\x15.omniql.DocumentQueryR\n" +
 	"rightQueryB1Z/github.com/omniql-engine/omniql/utilities/protob\x06proto3"
 
 var (
@@ -2555,27 +3353,33 @@ func file_utilities_proto_events_proto_rawDescGZIP() []byte {
 	return file_utilities_proto_events_proto_rawDescData
 }
 
-var file_utilities_proto_events_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_utilities_proto_events_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_utilities_proto_events_proto_goTypes = []any{
-	(*UniversalQuery)(nil),     // 0: omniql.UniversalQuery
-	(*Expression)(nil),         // 1: omniql.Expression
-	(*QueryCondition)(nil),     // 2: omniql.QueryCondition
-	(*CaseCondition)(nil),      // 3: omniql.CaseCondition
-	(*QueryField)(nil),         // 4: omniql.QueryField
-	(*SelectColumn)(nil),       // 5: omniql.SelectColumn
-	(*RelationalQuery)(nil),    // 6: omniql.RelationalQuery
-	(*DocumentQuery)(nil),      // 7: omniql.DocumentQuery
-	(*KeyValueQuery)(nil),      // 8: omniql.KeyValueQuery
-	(*KeyValuePair)(nil),       // 9: omniql.KeyValuePair
-	(*JoinClause)(nil),         // 10: omniql.JoinClause
-	(*AggregateClause)(nil),    // 11: omniql.AggregateClause
-	(*OrderByClause)(nil),      // 12: omniql.OrderByClause
-	(*WindowClause)(nil),       // 13: omniql.WindowClause
-	(*CTEClause)(nil),          // 14: omniql.CTEClause
-	(*SubqueryClause)(nil),     // 15: omniql.SubqueryClause
-	(*UpsertClause)(nil),       // 16: omniql.UpsertClause
-	(*BulkInsertRow)(nil),      // 17: omniql.BulkInsertRow
-	(*SetOperationClause)(nil), // 18: omniql.SetOperationClause
+	(*UniversalQuery)(nil),             // 0: omniql.UniversalQuery
+	(*Expression)(nil),                 // 1: omniql.Expression
+	(*QueryCondition)(nil),             // 2: omniql.QueryCondition
+	(*CaseCondition)(nil),              // 3: omniql.CaseCondition
+	(*QueryField)(nil),                 // 4: omniql.QueryField
+	(*SelectColumn)(nil),               // 5: omniql.SelectColumn
+	(*RelationalQuery)(nil),            // 6: omniql.RelationalQuery
+	(*DocumentQuery)(nil),              // 7: omniql.DocumentQuery
+	(*KeyValueQuery)(nil),              // 8: omniql.KeyValueQuery
+	(*KeyValuePair)(nil),               // 9: omniql.KeyValuePair
+	(*JoinClause)(nil),                 // 10: omniql.JoinClause
+	(*AggregateClause)(nil),            // 11: omniql.AggregateClause
+	(*GraphLookupClause)(nil),          // 12: omniql.GraphLookupClause
+	(*CollationClause)(nil),            // 13: omniql.CollationClause
+	(*FacetClause)(nil),                // 14: omniql.FacetClause
+	(*OrderByClause)(nil),              // 15: omniql.OrderByClause
+	(*WindowClause)(nil),               // 16: omniql.WindowClause
+	(*CTEClause)(nil),                  // 17: omniql.CTEClause
+	(*SubqueryClause)(nil),             // 18: omniql.SubqueryClause
+	(*UpsertClause)(nil),               // 19: omniql.UpsertClause
+	(*BulkInsertRow)(nil),              // 20: omniql.BulkInsertRow
+	(*TableLock)(nil),                  // 21: omniql.TableLock
+	(*SetOperationClause)(nil),         // 22: omniql.SetOperationClause
+	(*DocumentSetOperationClause)(nil), // 23: omniql.DocumentSetOperationClause
+	nil,                                // 24: omniql.RelationalQuery.TableOptionsEntry
 }
 var file_utilities_proto_events_proto_depIdxs = []int32{
 	6,  // 0: omniql.UniversalQuery.relational:type_name -> omniql.RelationalQuery
@@ -2595,61 +3399,85 @@ var file_utilities_proto_events_proto_depIdxs = []int32{
 	1,  // 14: omniql.CaseCondition.then_expr:type_name -> omniql.Expression
 	1,  // 15: omniql.QueryField.name_expr:type_name -> omniql.Expression
 	1,  // 16: omniql.QueryField.value_expr:type_name -> omniql.Expression
-	1,  // 17: omniql.SelectColumn.expression_obj:type_name -> omniql.Expression
-	2,  // 18: omniql.RelationalQuery.conditions:type_name -> omniql.QueryCondition
-	4,  // 19: omniql.RelationalQuery.fields:type_name -> omniql.QueryField
-	10, // 20: omniql.RelationalQuery.joins:type_name -> omniql.JoinClause
-	11, // 21: omniql.RelationalQuery.aggregate:type_name -> omniql.AggregateClause
-	1,  // 22: omniql.RelationalQuery.group_by:type_name -> omniql.Expression
-	2,  // 23: omniql.RelationalQuery.having:type_name -> omniql.QueryCondition
-	12, // 24: omniql.RelationalQuery.order_by:type_name -> omniql.OrderByClause
-	13, // 25: omniql.RelationalQuery.window_functions:type_name -> omniql.WindowClause
-	14, // 26: omniql.RelationalQuery.cte:type_name -> omniql.CTEClause
-	15, // 27: omniql.RelationalQuery.subquery:type_name -> omniql.SubqueryClause
-	16, // 28: omniql.RelationalQuery.upsert:type_name -> omniql.UpsertClause
-	17, // 29: omniql.RelationalQuery.bulk_data:type_name -> omniql.BulkInsertRow
-	6,  // 30: omniql.RelationalQuery.view_query:type_name -> omniql.RelationalQuery
-	18, // 31: omniql.RelationalQuery.set_operation:type_name -> omniql.SetOperationClause
-	1,  // 32: omniql.RelationalQuery.columns:type_name -> omniql.Expression
-	5,  // 33: omniql.RelationalQuery.select_columns:type_name -> omniql.SelectColumn
-	2,  // 34: omniql.DocumentQuery.conditions:type_name -> omniql.QueryCondition
-	4,  // 35: omniql.DocumentQuery.fields:type_name -> omniql.QueryField
-	10, // 36: omniql.DocumentQuery.joins:type_name -> omniql.JoinClause
-	11, // 37: omniql.DocumentQuery.aggregate:type_name -> omniql.AggregateClause
-	1,  // 38: omniql.DocumentQuery.group_by:type_name -> omniql.Expression
-	12, // 39: omniql.DocumentQuery.order_by:type_name -> omniql.OrderByClause
-	13, // 40: omniql.DocumentQuery.window_functions:type_name -> omniql.WindowClause
-	16, // 41: omniql.DocumentQuery.upsert:type_name -> omniql.UpsertClause
-	17, // 42: omniql.DocumentQuery.bulk_data:type_name -> omniql.BulkInsertRow
-	7,  // 43: omniql.DocumentQuery.view_query:type_name -> omniql.DocumentQuery
-	18, // 44: omniql.DocumentQuery.set_operation:type_name -> omniql.SetOperationClause
-	1,  // 45: omniql.DocumentQuery.columns:type_name -> omniql.Expression
-	5,  // 46: omniql.DocumentQuery.select_columns:type_name -> omniql.SelectColumn
-	2,  // 47: omniql.DocumentQuery.having:type_name -> omniql.QueryCondition
-	9,  // 48: omniql.KeyValueQuery.bulk_pairs:type_name -> omniql.KeyValuePair
-	2,  // 49: omniql.KeyValueQuery.conditions:type_name -> omniql.QueryCondition
-	12, // 50: omniql.KeyValueQuery.order_by:type_name -> omniql.OrderByClause
-	1,  // 51: omniql.JoinClause.left_expr:type_name -> omniql.Expression
-	1,  // 52: omniql.JoinClause.right_expr:type_name -> omniql.Expression
-	1,  // 53: omniql.AggregateClause.field_expr:type_name -> omniql.Expression
-	1,  // 54: omniql.OrderByClause.field_expr:type_name -> omniql.Expression
-	1,  // 55: omniql.WindowClause.field_expr:type_name -> omniql.Expression
-	1,  // 56: omniql.WindowClause.partition_by:type_name -> omniql.Expression
-	12, // 57: omniql.WindowClause.order_by:type_name -> omniql.OrderByClause
-	6,  // 58: omniql.CTEClause.cte_query:type_name -> omniql.RelationalQuery
-	14, // 59: omniql.CTEClause.additional_ctes:type_name -> omniql.CTEClause
-	1,  // 60: omniql.SubqueryClause.field_expr:type_name -> omniql.Expression
-	6,  // 61: omniql.SubqueryClause.subquery:type_name -> omniql.RelationalQuery
-	1,  // 62: omniql.UpsertClause.conflict_fields:type_name -> omniql.Expression
-	4,  // 63: omniql.UpsertClause.update_fields:type_name -> omniql.QueryField
-	4,  // 64: omniql.BulkInsertRow.fields:type_name -> omniql.QueryField
-	6,  // 65: omniql.SetOperationClause.left_query:type_name -> omniql.RelationalQuery
-	6,  // 66: omniql.SetOperationClause.right_query:type_name -> omniql.RelationalQuery
-	67, // [67:67] is the sub-list for method output_type
-	67, // [67:67] is the sub-list for method input_type
-	67, // [67:67] is the sub-list for extension type_name
-	67, // [67:67] is the sub-list for extension extendee
-	0,  // [0:67] is the sub-list for field type_name
+	1,  // 17: omniql.QueryField.generated_expr:type_name -> omniql.Expression
+	1,  // 18: omniql.SelectColumn.expression_obj:type_name -> omniql.Expression
+	2,  // 19: omniql.RelationalQuery.conditions:type_name -> omniql.QueryCondition
+	4,  // 20: omniql.RelationalQuery.fields:type_name -> omniql.QueryField
+	10, // 21: omniql.RelationalQuery.joins:type_name -> omniql.JoinClause
+	11, // 22: omniql.RelationalQuery.aggregate:type_name -> omniql.AggregateClause
+	1,  // 23: omniql.RelationalQuery.group_by:type_name -> omniql.Expression
+	2,  // 24: omniql.RelationalQuery.having:type_name -> omniql.QueryCondition
+	15, // 25: omniql.RelationalQuery.order_by:type_name -> omniql.OrderByClause
+	16, // 26: omniql.RelationalQuery.window_functions:type_name -> omniql.WindowClause
+	17, // 27: omniql.RelationalQuery.cte:type_name -> omniql.CTEClause
+	18, // 28: omniql.RelationalQuery.subquery:type_name -> omniql.SubqueryClause
+	19, // 29: omniql.RelationalQuery.upsert:type_name -> omniql.UpsertClause
+	20, // 30: omniql.RelationalQuery.bulk_data:type_name -> omniql.BulkInsertRow
+	6,  // 31: omniql.RelationalQuery.view_query:type_name -> omniql.RelationalQuery
+	22, // 32: omniql.RelationalQuery.set_operation:type_name -> omniql.SetOperationClause
+	1,  // 33: omniql.RelationalQuery.columns:type_name -> omniql.Expression
+	5,  // 34: omniql.RelationalQuery.select_columns:type_name -> omniql.SelectColumn
+	1,  // 35: omniql.RelationalQuery.returning:type_name -> omniql.Expression
+	1,  // 36: omniql.RelationalQuery.partition_keys:type_name -> omniql.Expression
+	1,  // 37: omniql.RelationalQuery.partition_from:type_name -> omniql.Expression
+	1,  // 38: omniql.RelationalQuery.partition_to:type_name -> omniql.Expression
+	1,  // 39: omniql.RelationalQuery.partition_in:type_name -> omniql.Expression
+	24, // 40: omniql.RelationalQuery.table_options:type_name -> omniql.RelationalQuery.TableOptionsEntry
+	21, // 41: omniql.RelationalQuery.lock_tables:type_name -> omniql.TableLock
+	1,  // 42: omniql.RelationalQuery.cluster_keys:type_name -> omniql.Expression
+	2,  // 43: omniql.DocumentQuery.conditions:type_name -> omniql.QueryCondition
+	4,  // 44: omniql.DocumentQuery.fields:type_name -> omniql.QueryField
+	10, // 45: omniql.DocumentQuery.joins:type_name -> omniql.JoinClause
+	11, // 46: omniql.DocumentQuery.aggregate:type_name -> omniql.AggregateClause
+	1,  // 47: omniql.DocumentQuery.group_by:type_name -> omniql.Expression
+	15, // 48: omniql.DocumentQuery.order_by:type_name -> omniql.OrderByClause
+	16, // 49: omniql.DocumentQuery.window_functions:type_name -> omniql.WindowClause
+	19, // 50: omniql.DocumentQuery.upsert:type_name -> omniql.UpsertClause
+	20, // 51: omniql.DocumentQuery.bulk_data:type_name -> omniql.BulkInsertRow
+	7,  // 52: omniql.DocumentQuery.view_query:type_name -> omniql.DocumentQuery
+	23, // 53: omniql.DocumentQuery.set_operation:type_name -> omniql.DocumentSetOperationClause
+	1,  // 54: omniql.DocumentQuery.columns:type_name -> omniql.Expression
+	5,  // 55: omniql.DocumentQuery.select_columns:type_name -> omniql.SelectColumn
+	2,  // 56: omniql.DocumentQuery.having:type_name -> omniql.QueryCondition
+	14, // 57: omniql.DocumentQuery.facets:type_name -> omniql.FacetClause
+	12, // 58: omniql.DocumentQuery.graph_lookup:type_name -> omniql.GraphLookupClause
+	2,  // 59: omniql.DocumentQuery.array_filters:type_name -> omniql.QueryCondition
+	13, // 60: omniql.DocumentQuery.collation:type_name -> omniql.CollationClause
+	1,  // 61: omniql.DocumentQuery.returning:type_name -> omniql.Expression
+	9,  // 62: omniql.KeyValueQuery.bulk_pairs:type_name -> omniql.KeyValuePair
+	2,  // 63: omniql.KeyValueQuery.conditions:type_name -> omniql.QueryCondition
+	15, // 64: omniql.KeyValueQuery.order_by:type_name -> omniql.OrderByClause
+	19, // 65: omniql.KeyValueQuery.upsert:type_name -> omniql.UpsertClause
+	1,  // 66: omniql.JoinClause.left_expr:type_name -> omniql.Expression
+	1,  // 67: omniql.JoinClause.right_expr:type_name -> omniql.Expression
+	1,  // 68: omniql.AggregateClause.field_expr:type_name -> omniql.Expression
+	15, // 69: omniql.AggregateClause.order_by:type_name -> omniql.OrderByClause
+	7,  // 70: omniql.GraphLookupClause.main_query:type_name -> omniql.DocumentQuery
+	11, // 71: omniql.FacetClause.aggregate:type_name -> omniql.AggregateClause
+	1,  // 72: omniql.FacetClause.group_by:type_name -> omniql.Expression
+	1,  // 73: omniql.OrderByClause.field_expr:type_name -> omniql.Expression
+	1,  // 74: omniql.WindowClause.field_expr:type_name -> omniql.Expression
+	1,  // 75: omniql.WindowClause.partition_by:type_name -> omniql.Expression
+	15, // 76: omniql.WindowClause.order_by:type_name -> omniql.OrderByClause
+	1,  // 77: omniql.WindowClause.default_value:type_name -> omniql.Expression
+	6,  // 78: omniql.CTEClause.cte_query:type_name -> omniql.RelationalQuery
+	17, // 79: omniql.CTEClause.additional_ctes:type_name -> omniql.CTEClause
+	6,  // 80: omniql.CTEClause.main_query:type_name -> omniql.RelationalQuery
+	1,  // 81: omniql.SubqueryClause.field_expr:type_name -> omniql.Expression
+	6,  // 82: omniql.SubqueryClause.subquery:type_name -> omniql.RelationalQuery
+	1,  // 83: omniql.UpsertClause.conflict_fields:type_name -> omniql.Expression
+	4,  // 84: omniql.UpsertClause.update_fields:type_name -> omniql.QueryField
+	2,  // 85: omniql.UpsertClause.conflict_where:type_name -> omniql.QueryCondition
+	4,  // 86: omniql.BulkInsertRow.fields:type_name -> omniql.QueryField
+	6,  // 87: omniql.SetOperationClause.left_query:type_name -> omniql.RelationalQuery
+	6,  // 88: omniql.SetOperationClause.right_query:type_name -> omniql.RelationalQuery
+	7,  // 89: omniql.DocumentSetOperationClause.left_query:type_name -> omniql.DocumentQuery
+	7,  // 90: omniql.DocumentSetOperationClause.right_query:type_name -> omniql.DocumentQuery
+	91, // [91:91] is the sub-list for method output_type
+	91, // [91:91] is the sub-list for method input_type
+	91, // [91:91] is the sub-list for extension type_name
+	91, // [91:91] is the sub-list for extension extendee
+	0,  // [0:91] is the sub-list for field type_name
 }
 
 func init() { file_utilities_proto_events_proto_init() }
@@ -2668,7 +3496,7 @@ func file_utilities_proto_events_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_utilities_proto_events_proto_rawDesc), len(file_utilities_proto_events_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   19,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   0,
 		},